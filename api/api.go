@@ -2,9 +2,17 @@ package api
 
 import (
 	"context"
+	"crypto/rand"
+	"fmt"
 	"io"
+	"math/big"
 	"mime"
+	"net"
 	gopath "path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/peer"
 )
 
 type key int
@@ -14,6 +22,8 @@ const (
 	tokenKey           key = 1
 	publicLinkKey      key = 2
 	publicLinkTokenKey key = 3
+	clientIPKey        key = 4
+	uploaderNameKey    key = 5
 )
 
 func ContextGetUser(ctx context.Context) (*User, bool) {
@@ -52,6 +62,93 @@ func ContextSetPublicLink(ctx context.Context, pl *PublicLink) context.Context {
 	return context.WithValue(ctx, publicLinkKey, pl)
 }
 
+// ContextGetClientIP returns the IP of the client that originated the request,
+// it is used to tell apart the visitors of public links.
+func ContextGetClientIP(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPKey).(string)
+	return ip, ok
+}
+
+func ContextSetClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+// ContextGetUploaderName returns the name given by the uploader to a drop only link,
+// it is used to put the uploads of each uploader in their own folder.
+func ContextGetUploaderName(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(uploaderNameKey).(string)
+	return name, ok
+}
+
+func ContextSetUploaderName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, uploaderNameKey, name)
+}
+
+// ParseTrustedProxies returns the networks of the trusted proxies, the IPs or CIDR
+// networks of the proxies whose client IPs are honoured, the single IPs are networks
+// of one address.
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := []*net.IPNet{}
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy: %s", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %s", proxy)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// IsTrustedProxy returns true if the IP is in the networks of the trusted proxies.
+func IsTrustedProxy(trustedProxies []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range trustedProxies {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPeerIP returns the IP of the peer of the gRPC call of the context, or an empty
+// string if it is not known.
+func GetPeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	ip, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return ip
+}
+
+// GetClientIP returns the client IP claimed by the peer of the gRPC call if it is
+// a trusted proxy, otherwise the IP of the peer, as any client can claim another IP.
+func GetClientIP(ctx context.Context, trustedProxies []*net.IPNet, claimed string) string {
+	ip := GetPeerIP(ctx)
+	if claimed != "" && IsTrustedProxy(trustedProxies, ip) {
+		return claimed
+	}
+	return ip
+}
+
 type MountOptions struct {
 	ReadOnly        bool `json:"read_only"`
 	SharingDisabled bool `json:"sharing_disabled"`
@@ -117,10 +214,14 @@ type Storage interface {
 	SetACL(ctx context.Context, path string, readOnly bool, recipient *ShareRecipient, shareList []*FolderShare) error
 	UnsetACL(ctx context.Context, path string, recipient *ShareRecipient, shareList []*FolderShare) error
 	UpdateACL(ctx context.Context, path string, readOnly bool, recipient *ShareRecipient, shareList []*FolderShare) error
+	ListACL(ctx context.Context, path string) ([]*ACLEntry, error)
 	GetQuota(ctx context.Context, path string) (int, int, error)
 }
 
 type PublicLinkOptions struct {
+	Token            string
+	Name             string
+	UpdateName       bool
 	Password         string
 	ReadOnly         bool
 	DropOnly         bool
@@ -129,6 +230,81 @@ type PublicLinkOptions struct {
 	UpdateReadOnly   bool
 	UpdateDropOnly   bool
 	UpdateExpiration bool
+
+	// MaxDownloads and MaxVisitors limit the usage of the link, zero means unlimited.
+	MaxDownloads       uint64
+	MaxVisitors        uint64
+	UpdateMaxDownloads bool
+	UpdateMaxVisitors  bool
+
+	// UploadPolicy restricts the uploads to drop only links, nil means unrestricted.
+	UploadPolicy       *UploadPolicy
+	UpdateUploadPolicy bool
+
+	// Labels tell apart the links of the same resource, like reviewers or submissions.
+	Labels       []string
+	UpdateLabels bool
+}
+
+const (
+	// MaxPublicLinkLabels is the number of labels a link can have.
+	MaxPublicLinkLabels = 10
+	// MaxPublicLinkLabelLength is the length of every label.
+	MaxPublicLinkLabelLength = 32
+)
+
+// CleanPublicLinkLabels returns the labels without surrounding spaces, empty labels
+// and duplicates, or a PublicLinkInvalidNameErrorCode error if there are too many,
+// they are too long or they contain commas, that separate them in the databases.
+func CleanPublicLinkLabels(labels []string) ([]string, error) {
+	cleaned := []string{}
+	seen := map[string]bool{}
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		if len(label) > MaxPublicLinkLabelLength || strings.Contains(label, ",") {
+			return nil, NewError(PublicLinkInvalidNameErrorCode).WithMessage(fmt.Sprintf("labels cannot be longer than %d characters or contain commas", MaxPublicLinkLabelLength))
+		}
+		seen[label] = true
+		cleaned = append(cleaned, label)
+	}
+	if len(cleaned) > MaxPublicLinkLabels {
+		return nil, NewError(PublicLinkInvalidNameErrorCode).WithMessage(fmt.Sprintf("links cannot have more than %d labels", MaxPublicLinkLabels))
+	}
+	return cleaned, nil
+}
+
+// IsInPath returns true if p is the same as root or lives under it.
+func IsInPath(p, root string) bool {
+	p, root = gopath.Clean(p), gopath.Clean(root)
+	return p == root || strings.HasPrefix(p, strings.TrimSuffix(root, "/")+"/")
+}
+
+// tokenLetters are the characters of the generated tokens, all of them safe in URLs.
+const tokenLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// GenToken returns a random token of the given length made of letters and digits.
+func GenToken(length int) (string, error) {
+	b := make([]byte, length)
+	max := big.NewInt(int64(len(tokenLetters)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = tokenLetters[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// PublicLinkUpload is a file uploaded to a drop only link, reported to the owner in the digests.
+type PublicLinkUpload struct {
+	Name     string
+	Uploader string
+	Size     uint64
+	Time     time.Time
 }
 
 type TagManager interface {
@@ -143,10 +319,65 @@ type PublicLinkManager interface {
 	InspectPublicLink(ctx context.Context, id string) (*PublicLink, error)
 	InspectPublicLinkByToken(ctx context.Context, token string) (*PublicLink, error)
 	ListPublicLinks(ctx context.Context, filterByPath string) ([]*PublicLink, error)
+	// ListPublicLinksInPath returns the links of the path and of all the resources under it.
+	ListPublicLinksInPath(ctx context.Context, path string) ([]*PublicLink, error)
+	// ListPublicLinksOfFileIDs returns the links of the resources with the given file ids.
+	ListPublicLinksOfFileIDs(ctx context.Context, fileIDs []string) ([]*PublicLink, error)
 	RevokePublicLink(ctx context.Context, token string) error
 
 	AuthenticatePublicLink(ctx context.Context, token, password string) (*PublicLink, error)
 	IsPublicLinkProtected(ctx context.Context, token string) (bool, error)
+
+	// RecordPublicLinkDownload counts a download done by the visitor, returning a
+	// PublicLinkLimitReachedErrorCode error if the link does not allow more downloads or visitors.
+	RecordPublicLinkDownload(ctx context.Context, id, visitor string) error
+	// RecordPublicLinkUpload counts an upload, adding size bytes to the uploaded bytes of the link.
+	// The bytes reserved with ReservePublicLinkUpload are already counted.
+	RecordPublicLinkUpload(ctx context.Context, id string, size uint64) error
+	// ReservePublicLinkUpload adds size bytes to the uploaded bytes of the link while they are uploaded,
+	// returning a PublicLinkUploadTooLargeErrorCode error if they exceed the maximum total size of the link.
+	ReservePublicLinkUpload(ctx context.Context, id string, size uint64) error
+	// ReleasePublicLinkUpload removes size bytes reserved for an upload that failed or turned out smaller.
+	ReleasePublicLinkUpload(ctx context.Context, id string, size uint64) error
+
+	// TransferPublicLink gives the link to newOwner, pointing it to newPath in the storage of newOwner.
+	// It does not require an user in the context and it is meant for administrative tasks.
+	TransferPublicLink(ctx context.Context, id, newOwner, newPath string) (*PublicLink, error)
+}
+
+// PublicLinkValidator checks that the link a token was forged for has not been revoked
+// or modified since, comparing the version embedded in the token with the current one.
+type PublicLinkValidator interface {
+	ValidatePublicLink(ctx context.Context, pl *PublicLink) error
+}
+
+// AuthAttempts are the consecutive failed authentication attempts
+// done with a key, like a public link token or a client IP.
+type AuthAttempts struct {
+	Failed int
+	Last   time.Time
+}
+
+// AuthAttemptStore keeps track of the failed authentication attempts so they can be throttled.
+// Keys not seen for a while are forgotten by the store.
+type AuthAttemptStore interface {
+	GetAuthAttempts(ctx context.Context, key string) (*AuthAttempts, error)
+	AddFailedAuthAttempt(ctx context.Context, key string) (*AuthAttempts, error)
+	ResetAuthAttempts(ctx context.Context, key string) error
+}
+
+// TokenRevocationStore keeps the ids of the revoked tokens, and when all the tokens
+// of a user were last revoked. The entries are forgotten after their expiration,
+// when the tokens they revoke have expired anyway.
+type TokenRevocationStore interface {
+	// RevokeToken returns false if the token was already revoked, in the same
+	// step it is revoked, so a token can be exchanged only once.
+	RevokeToken(ctx context.Context, id string, expiration time.Time) (bool, error)
+	IsTokenRevoked(ctx context.Context, id string) (bool, error)
+	// RevokeUserTokens revokes the tokens of the user issued until before.
+	RevokeUserTokens(ctx context.Context, accountID string, before, expiration time.Time) error
+	// GetUserTokensRevocation returns until when the tokens of the user are revoked, zero if never.
+	GetUserTokensRevocation(ctx context.Context, accountID string) (time.Time, error)
 }
 
 type ShareManager interface {
@@ -155,19 +386,58 @@ type ShareManager interface {
 	Unshare(ctx context.Context, shareID string) error
 	UpdateFolderShare(ctx context.Context, shareID string, updateReadOnly, readOnly bool) (*FolderShare, error)
 	ListFolderShares(ctx context.Context, filterByPath string) ([]*FolderShare, error)
+	// ListFolderSharesInPath returns the shares of the path and of all the folders under it.
+	ListFolderSharesInPath(ctx context.Context, path string) ([]*FolderShare, error)
+	// ListFolderSharesOfFileIDs returns the shares of the folders with the given file ids.
+	ListFolderSharesOfFileIDs(ctx context.Context, fileIDs []string) ([]*FolderShare, error)
 
 	ListReceivedShares(ctx context.Context) ([]*FolderShare, error)
 	GetReceivedFolderShare(ctx context.Context, shareID string) (*FolderShare, error)
 	UnmountReceivedShare(ctx context.Context, shareID string) error
+	// RenameReceivedShare changes the name under which the share is mounted for the user in the context,
+	// the owner and the other recipients keep seeing the previous name.
+	RenameReceivedShare(ctx context.Context, shareID, target string) (*FolderShare, error)
+
+	// ListAllFolderShares returns the folder shares of all the owners, it does not
+	// require an user in the context and it is meant for administrative tasks.
+	// The shares with remote recipients and guests have no acls and are not returned.
+	ListAllFolderShares(ctx context.Context) ([]*FolderShare, error)
+
+	// TransferFolderShare gives the share to newOwner, pointing it to newPath in the storage of newOwner.
+	// It does not require an user in the context and it is meant for administrative tasks.
+	TransferFolderShare(ctx context.Context, shareID, newOwner, newPath string) (*FolderShare, error)
 
 	/*
 		ListFolderRecipients(ctx context.Context, path string) ([]*ShareRecipient, error)
-		GetFolderSharesInPath(ctx context.Context, path string) ([]*FolderShare, error)
 
 		MountReceivedShare(ctx context.Context, shareID string) error
 	*/
 }
 
+// RemoteShareManager manages the shares that users of remote servers
+// have created for local users using the Open Cloud Mesh protocol.
+type RemoteShareManager interface {
+	// AddRemoteShare and RemoveRemoteShare are called on behalf of the remote server,
+	// they do not require an user in the context.
+	AddRemoteShare(ctx context.Context, share *RemoteShare) (*RemoteShare, error)
+	RemoveRemoteShare(ctx context.Context, remote, remoteID, token string) error
+
+	ListRemoteShares(ctx context.Context) ([]*RemoteShare, error)
+	GetRemoteShare(ctx context.Context, id string) (*RemoteShare, error)
+	AcceptRemoteShare(ctx context.Context, id string) error
+	RejectRemoteShare(ctx context.Context, id string) error
+}
+
+// OCMProviderClient talks to the Open Cloud Mesh endpoints of remote servers.
+type OCMProviderClient interface {
+	GetProvider(ctx context.Context, host string) (*OCMProvider, error)
+	SendShare(ctx context.Context, host string, share *OCMShare) error
+	SendNotification(ctx context.Context, host string, notification *OCMNotification) error
+	// VerifyShare returns an error unless the WebDAV endpoint discovered
+	// for the host grants access to a share with the token.
+	VerifyShare(ctx context.Context, host, token string) error
+}
+
 type Project struct {
 	Name         string
 	Path         string
@@ -177,24 +447,162 @@ type Project struct {
 	WritersGroup string
 }
 
+// ShareReconciler compares the shares known by the share manager with
+// the ACLs present on the storage.
+type ShareReconciler interface {
+	Reconcile(ctx context.Context, repair bool) ([]*ACLDrift, error)
+}
+
+// OwnershipTransferer hands over a folder or file of an user to another user,
+// together with its shares and public links.
+type OwnershipTransferer interface {
+	TransferOwnership(ctx context.Context, from, to, path string) ([]*TransferredItem, error)
+}
+
+// Notifier e-mails the recipients of new shares and links. The e-mails are queued,
+// so a nil error does not mean they have been delivered.
+type Notifier interface {
+	NotifyFolderShare(ctx context.Context, share *FolderShare, path string) error
+	NotifyPublicLink(ctx context.Context, link *PublicLink, path string, recipients []string) error
+	// NotifyGuestInvitation sends to a new guest the share and the token to activate its account.
+	NotifyGuestInvitation(ctx context.Context, share *FolderShare, path, activationToken string) error
+	// NotifyPublicLinkUpload adds the upload to the next digest sent to the owner of the link.
+	NotifyPublicLinkUpload(ctx context.Context, link *PublicLink, upload *PublicLinkUpload) error
+	GetNotificationPreferences(ctx context.Context) (*NotificationPreferences, error)
+	SetNotificationPreferences(ctx context.Context, prefs *NotificationPreferences) error
+}
+
 type ProjectManager interface {
 	GetAllProjects(ctx context.Context) ([]*Project, error)
 	GetProject(ctx context.Context, name string) (*Project, error)
 }
 type UserManager interface {
 	GetUserGroups(ctx context.Context, username string) ([]string, error)
+	// GetUserUnixGroups returns the unix groups of the user, kept apart from
+	// the other groups as a unix group and an e-group can have the same name.
+	GetUserUnixGroups(ctx context.Context, username string) ([]string, error)
 	IsInGroup(ctx context.Context, username, group string) (bool, error)
+	GetGroupMembers(ctx context.Context, group string) ([]string, error)
 }
+
+// GetRecipientGroups returns the groups and the unix groups whose shares are received
+// by the user, the ones from the user manager and, for the user of the context, the
+// groups it logged in with. Guests are not members of any group.
+func GetRecipientGroups(ctx context.Context, um UserManager, accountID string) ([]string, []string, error) {
+	u, ok := ContextGetUser(ctx)
+	if ok && u.AccountId == accountID && u.Guest {
+		return []string{}, []string{}, nil
+	}
+
+	groups, err := um.GetUserGroups(ctx, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ok && u.AccountId == accountID {
+		groups = append(groups, u.Groups...)
+	}
+
+	unixGroups, err := um.GetUserUnixGroups(ctx, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return uniqueGroups(groups), uniqueGroups(unixGroups), nil
+}
+
+func uniqueGroups(groups []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, g := range groups {
+		if g == "" || seen[g] {
+			continue
+		}
+		seen[g] = true
+		unique = append(unique, g)
+	}
+	return unique
+}
+
 type AuthManager interface {
 	Authenticate(ctx context.Context, clientID, clientPassword string) (*User, error)
 }
 
+// UserDirectory looks up the users and groups, to find the recipients
+// of the shares and show the names of the users.
+type UserDirectory interface {
+	// GetUser returns a UserNotFoundErrorCode error if the user does not exist.
+	GetUser(ctx context.Context, accountID string) (*DirectoryUser, error)
+	// GetUserAvatar returns the picture of the user and its mime type, or a
+	// UserNotFoundErrorCode error if the user has none.
+	GetUserAvatar(ctx context.Context, accountID string) ([]byte, string, error)
+	// Search returns the users and groups whose id, name or mail start with
+	// the prefix, the implementations may limit the number of entries.
+	Search(ctx context.Context, prefix string) ([]*DirectoryEntry, error)
+}
+
+// OIDCClientID is the client id used to authenticate with an OpenID Connect
+// access token, given as the client password.
+const OIDCClientID = "oidc-bearer"
+
+// GuestManager manages the accounts of the external users invited by e-mail.
+// A guest is identified by its e-mail address and cannot log in until it
+// chooses a password with the activation token sent in the invitation.
+type GuestManager interface {
+	// InviteGuest returns the activation token of a new or still pending guest,
+	// or an empty token if the guest is already active.
+	InviteGuest(ctx context.Context, email string) (string, error)
+	// ActivateGuest sets the password of the guest and returns its e-mail address,
+	// the token cannot be used again.
+	ActivateGuest(ctx context.Context, token, password string) (string, error)
+	AuthenticateGuest(ctx context.Context, email, password string) (*User, error)
+	// GetGuest returns the user of an active guest, as it logs in, or a
+	// UserNotFoundErrorCode error if there is none with the e-mail address.
+	GetGuest(ctx context.Context, email string) (*User, error)
+}
+
+// AppPasswordManager keeps the app passwords, the secondary passwords users create
+// for their clients and scripts, optionally restricted by a scope.
+type AppPasswordManager interface {
+	// CreateAppPassword returns the new app password of the user of the context
+	// and its password, that is not stored and cannot be retrieved again.
+	CreateAppPassword(ctx context.Context, label string, scope *TokenScope) (*AppPassword, string, error)
+	ListAppPasswords(ctx context.Context) ([]*AppPassword, error)
+	RevokeAppPassword(ctx context.Context, id string) error
+	// AuthenticateAppPassword returns the app password of the user matching the password.
+	AuthenticateAppPassword(ctx context.Context, accountID, password string) (*AppPassword, error)
+}
+
 type TokenManager interface {
 	ForgeUserToken(ctx context.Context, user *User) (string, error)
 	DismantleUserToken(ctx context.Context, token string) (*User, error)
 
 	ForgePublicLinkToken(ctx context.Context, pl *PublicLink) (string, error)
 	DismantlePublicLinkToken(ctx context.Context, token string) (*PublicLink, error)
+
+	// ForgeScopedToken returns a short lived token of the user restricted to the scope,
+	// that must restrict it to a path or a resource.
+	// The lifetime is in seconds, zero uses the default one.
+	ForgeScopedToken(ctx context.Context, user *User, scope *TokenScope, lifetime int) (string, error)
+
+	// ForgeImpersonationToken returns a short lived token of the user for the
+	// admin in its Impersonator.
+	ForgeImpersonationToken(ctx context.Context, user *User) (string, error)
+
+	// ForgeRefreshToken returns a long lived token that can only be exchanged for new user tokens.
+	ForgeRefreshToken(ctx context.Context, user *User) (string, error)
+	// RedeemRefreshToken returns the user of the refresh token and revokes it, returning
+	// a TokenInvalidErrorCode error if it is not valid or was already redeemed.
+	RedeemRefreshToken(ctx context.Context, token string) (*User, error)
+
+	// RevokeUserToken revokes a user or refresh token until it expires.
+	RevokeUserToken(ctx context.Context, token string) error
+	// RevokeUserTokens revokes all the user and refresh tokens forged for the user until now.
+	RevokeUserTokens(ctx context.Context, accountID string) error
+}
+
+// KeySetPublisher is implemented by the token managers signing with asymmetric keys,
+// to publish the public keys verifying their tokens as a JSON Web Key Set.
+type KeySetPublisher interface {
+	GetJWKS() ([]byte, error)
 }
 
 func GetStatus(err error) StatusCode {
@@ -228,6 +636,22 @@ func GetStatus(err error) StatusCode {
 		return StatusCode_PUBLIC_LINK_INVALID_DATE
 	case PublicLinkNotFoundErrorCode:
 		return StatusCode_PUBLIC_LINK_NOT_FOUND
+	case PermissionDeniedErrorCode:
+		return StatusCode_PERMISSION_DENIED
+	case RemoteShareNotFoundErrorCode:
+		return StatusCode_REMOTE_SHARE_NOT_FOUND
+	case NotificationInvalidRecipientErrorCode:
+		return StatusCode_NOTIFICATION_INVALID_RECIPIENT
+	case GuestInvalidTokenErrorCode:
+		return StatusCode_GUEST_INVALID_TOKEN
+	case PublicLinkUploadTooLargeErrorCode:
+		return StatusCode_PUBLIC_LINK_UPLOAD_TOO_LARGE
+	case PublicLinkUploadTypeNotAllowedErrorCode:
+		return StatusCode_PUBLIC_LINK_UPLOAD_TYPE_NOT_ALLOWED
+	case PublicLinkUploaderNameRequiredErrorCode:
+		return StatusCode_PUBLIC_LINK_UPLOADER_NAME_REQUIRED
+	case AppPasswordNotFoundErrorCode:
+		return StatusCode_APP_PASSWORD_NOT_FOUND
 	default:
 		return StatusCode_UNKNOWN
 	}
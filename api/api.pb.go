@@ -25,20 +25,34 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type StatusCode int32
 
 const (
-	StatusCode_OK                           StatusCode = 0
-	StatusCode_UNKNOWN                      StatusCode = 1
-	StatusCode_STORAGE_NOT_FOUND            StatusCode = 2
-	StatusCode_STORAGE_ALREADY_EXISTS       StatusCode = 3
-	StatusCode_STORAGE_PERMISSIONDENIED     StatusCode = 4
-	StatusCode_CONTEXT_USER_REQUIRED        StatusCode = 5
-	StatusCode_PATH_INVALID                 StatusCode = 6
-	StatusCode_PUBLIC_LINK_NOT_FOUND        StatusCode = 7
-	StatusCode_PUBLIC_LINK_INVALID_DATE     StatusCode = 8
-	StatusCode_PUBLIC_LINK_INVALID_PASSWORD StatusCode = 9
-	StatusCode_STORAGE_NOT_SUPPORTED        StatusCode = 10
-	StatusCode_USER_NOT_FOUND               StatusCode = 11
-	StatusCode_TOKEN_INVALID                StatusCode = 12
-	StatusCode_FOLDER_SHARE_NOT_FOUND       StatusCode = 13
+	StatusCode_OK                                  StatusCode = 0
+	StatusCode_UNKNOWN                             StatusCode = 1
+	StatusCode_STORAGE_NOT_FOUND                   StatusCode = 2
+	StatusCode_STORAGE_ALREADY_EXISTS              StatusCode = 3
+	StatusCode_STORAGE_PERMISSIONDENIED            StatusCode = 4
+	StatusCode_CONTEXT_USER_REQUIRED               StatusCode = 5
+	StatusCode_PATH_INVALID                        StatusCode = 6
+	StatusCode_PUBLIC_LINK_NOT_FOUND               StatusCode = 7
+	StatusCode_PUBLIC_LINK_INVALID_DATE            StatusCode = 8
+	StatusCode_PUBLIC_LINK_INVALID_PASSWORD        StatusCode = 9
+	StatusCode_STORAGE_NOT_SUPPORTED               StatusCode = 10
+	StatusCode_USER_NOT_FOUND                      StatusCode = 11
+	StatusCode_TOKEN_INVALID                       StatusCode = 12
+	StatusCode_FOLDER_SHARE_NOT_FOUND              StatusCode = 13
+	StatusCode_PERMISSION_DENIED                   StatusCode = 14
+	StatusCode_REMOTE_SHARE_NOT_FOUND              StatusCode = 15
+	StatusCode_PUBLIC_LINK_TOKEN_ALREADY_EXISTS    StatusCode = 16
+	StatusCode_PUBLIC_LINK_INVALID_TOKEN           StatusCode = 17
+	StatusCode_PUBLIC_LINK_LOCKED                  StatusCode = 18
+	StatusCode_PUBLIC_LINK_LIMIT_REACHED           StatusCode = 19
+	StatusCode_PUBLIC_LINK_INVALID_NAME            StatusCode = 20
+	StatusCode_FOLDER_SHARE_INVALID_TARGET         StatusCode = 21
+	StatusCode_NOTIFICATION_INVALID_RECIPIENT      StatusCode = 22
+	StatusCode_GUEST_INVALID_TOKEN                 StatusCode = 23
+	StatusCode_PUBLIC_LINK_UPLOAD_TOO_LARGE        StatusCode = 24
+	StatusCode_PUBLIC_LINK_UPLOAD_TYPE_NOT_ALLOWED StatusCode = 25
+	StatusCode_PUBLIC_LINK_UPLOADER_NAME_REQUIRED  StatusCode = 26
+	StatusCode_APP_PASSWORD_NOT_FOUND              StatusCode = 27
 )
 
 var StatusCode_name = map[int32]string{
@@ -56,23 +70,51 @@ var StatusCode_name = map[int32]string{
 	11: "USER_NOT_FOUND",
 	12: "TOKEN_INVALID",
 	13: "FOLDER_SHARE_NOT_FOUND",
+	14: "PERMISSION_DENIED",
+	15: "REMOTE_SHARE_NOT_FOUND",
+	16: "PUBLIC_LINK_TOKEN_ALREADY_EXISTS",
+	17: "PUBLIC_LINK_INVALID_TOKEN",
+	18: "PUBLIC_LINK_LOCKED",
+	19: "PUBLIC_LINK_LIMIT_REACHED",
+	20: "PUBLIC_LINK_INVALID_NAME",
+	21: "FOLDER_SHARE_INVALID_TARGET",
+	22: "NOTIFICATION_INVALID_RECIPIENT",
+	23: "GUEST_INVALID_TOKEN",
+	24: "PUBLIC_LINK_UPLOAD_TOO_LARGE",
+	25: "PUBLIC_LINK_UPLOAD_TYPE_NOT_ALLOWED",
+	26: "PUBLIC_LINK_UPLOADER_NAME_REQUIRED",
+	27: "APP_PASSWORD_NOT_FOUND",
 }
 
 var StatusCode_value = map[string]int32{
-	"OK":                           0,
-	"UNKNOWN":                      1,
-	"STORAGE_NOT_FOUND":            2,
-	"STORAGE_ALREADY_EXISTS":       3,
-	"STORAGE_PERMISSIONDENIED":     4,
-	"CONTEXT_USER_REQUIRED":        5,
-	"PATH_INVALID":                 6,
-	"PUBLIC_LINK_NOT_FOUND":        7,
-	"PUBLIC_LINK_INVALID_DATE":     8,
-	"PUBLIC_LINK_INVALID_PASSWORD": 9,
-	"STORAGE_NOT_SUPPORTED":        10,
-	"USER_NOT_FOUND":               11,
-	"TOKEN_INVALID":                12,
-	"FOLDER_SHARE_NOT_FOUND":       13,
+	"OK":                                  0,
+	"UNKNOWN":                             1,
+	"STORAGE_NOT_FOUND":                   2,
+	"STORAGE_ALREADY_EXISTS":              3,
+	"STORAGE_PERMISSIONDENIED":            4,
+	"CONTEXT_USER_REQUIRED":               5,
+	"PATH_INVALID":                        6,
+	"PUBLIC_LINK_NOT_FOUND":               7,
+	"PUBLIC_LINK_INVALID_DATE":            8,
+	"PUBLIC_LINK_INVALID_PASSWORD":        9,
+	"STORAGE_NOT_SUPPORTED":               10,
+	"USER_NOT_FOUND":                      11,
+	"TOKEN_INVALID":                       12,
+	"FOLDER_SHARE_NOT_FOUND":              13,
+	"PERMISSION_DENIED":                   14,
+	"REMOTE_SHARE_NOT_FOUND":              15,
+	"PUBLIC_LINK_TOKEN_ALREADY_EXISTS":    16,
+	"PUBLIC_LINK_INVALID_TOKEN":           17,
+	"PUBLIC_LINK_LOCKED":                  18,
+	"PUBLIC_LINK_LIMIT_REACHED":           19,
+	"PUBLIC_LINK_INVALID_NAME":            20,
+	"FOLDER_SHARE_INVALID_TARGET":         21,
+	"NOTIFICATION_INVALID_RECIPIENT":      22,
+	"GUEST_INVALID_TOKEN":                 23,
+	"PUBLIC_LINK_UPLOAD_TOO_LARGE":        24,
+	"PUBLIC_LINK_UPLOAD_TYPE_NOT_ALLOWED": 25,
+	"PUBLIC_LINK_UPLOADER_NAME_REQUIRED":  26,
+	"APP_PASSWORD_NOT_FOUND":              27,
 }
 
 func (x StatusCode) String() string {
@@ -114,18 +156,26 @@ const (
 	ShareRecipient_USER  ShareRecipient_RecipientType = 0
 	ShareRecipient_GROUP ShareRecipient_RecipientType = 1
 	ShareRecipient_UNIX  ShareRecipient_RecipientType = 2
+	// user@host of a remote server, see Open Cloud Mesh
+	ShareRecipient_REMOTE ShareRecipient_RecipientType = 3
+	// e-mail address of an external user, who is invited as a guest
+	ShareRecipient_GUEST ShareRecipient_RecipientType = 4
 )
 
 var ShareRecipient_RecipientType_name = map[int32]string{
 	0: "USER",
 	1: "GROUP",
 	2: "UNIX",
+	3: "REMOTE",
+	4: "GUEST",
 }
 
 var ShareRecipient_RecipientType_value = map[string]int32{
-	"USER":  0,
-	"GROUP": 1,
-	"UNIX":  2,
+	"USER":   0,
+	"GROUP":  1,
+	"UNIX":   2,
+	"REMOTE": 3,
+	"GUEST":  4,
 }
 
 func (x ShareRecipient_RecipientType) String() string {
@@ -133,7 +183,7 @@ func (x ShareRecipient_RecipientType) String() string {
 }
 
 func (ShareRecipient_RecipientType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{39, 0}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{48, 0}
 }
 
 type PublicLink_ItemType int32
@@ -158,7 +208,7 @@ func (x PublicLink_ItemType) String() string {
 }
 
 func (PublicLink_ItemType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{41, 0}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{51, 0}
 }
 
 type FolderShare_State int32
@@ -186,7 +236,119 @@ func (x FolderShare_State) String() string {
 }
 
 func (FolderShare_State) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{45, 0}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{55, 0}
+}
+
+type RemoteShare_State int32
+
+const (
+	RemoteShare_ACCEPTED RemoteShare_State = 0
+	RemoteShare_PENDING  RemoteShare_State = 1
+)
+
+var RemoteShare_State_name = map[int32]string{
+	0: "ACCEPTED",
+	1: "PENDING",
+}
+
+var RemoteShare_State_value = map[string]int32{
+	"ACCEPTED": 0,
+	"PENDING":  1,
+}
+
+func (x RemoteShare_State) String() string {
+	return proto.EnumName(RemoteShare_State_name, int32(x))
+}
+
+func (RemoteShare_State) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{63, 0}
+}
+
+type ACLDrift_Kind int32
+
+const (
+	// the share exists but the grant is not on the storage
+	ACLDrift_MISSING ACLDrift_Kind = 0
+	// the grant is on the storage but there is no share for it
+	ACLDrift_ORPHANED ACLDrift_Kind = 1
+	// the grant is on the storage with different permissions than the share
+	ACLDrift_MISMATCH ACLDrift_Kind = 2
+)
+
+var ACLDrift_Kind_name = map[int32]string{
+	0: "MISSING",
+	1: "ORPHANED",
+	2: "MISMATCH",
+}
+
+var ACLDrift_Kind_value = map[string]int32{
+	"MISSING":  0,
+	"ORPHANED": 1,
+	"MISMATCH": 2,
+}
+
+func (x ACLDrift_Kind) String() string {
+	return proto.EnumName(ACLDrift_Kind_name, int32(x))
+}
+
+func (ACLDrift_Kind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{69, 0}
+}
+
+type DirectoryEntry_EntryType int32
+
+const (
+	DirectoryEntry_USER       DirectoryEntry_EntryType = 0
+	DirectoryEntry_GROUP      DirectoryEntry_EntryType = 1
+	DirectoryEntry_UNIX_GROUP DirectoryEntry_EntryType = 2
+)
+
+var DirectoryEntry_EntryType_name = map[int32]string{
+	0: "USER",
+	1: "GROUP",
+	2: "UNIX_GROUP",
+}
+
+var DirectoryEntry_EntryType_value = map[string]int32{
+	"USER":       0,
+	"GROUP":      1,
+	"UNIX_GROUP": 2,
+}
+
+func (x DirectoryEntry_EntryType) String() string {
+	return proto.EnumName(DirectoryEntry_EntryType_name, int32(x))
+}
+
+func (DirectoryEntry_EntryType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{78, 0}
+}
+
+type TransferredItem_Kind int32
+
+const (
+	TransferredItem_DATA         TransferredItem_Kind = 0
+	TransferredItem_FOLDER_SHARE TransferredItem_Kind = 1
+	TransferredItem_PUBLIC_LINK  TransferredItem_Kind = 2
+)
+
+var TransferredItem_Kind_name = map[int32]string{
+	0: "DATA",
+	1: "FOLDER_SHARE",
+	2: "PUBLIC_LINK",
+}
+
+var TransferredItem_Kind_value = map[string]int32{
+	"DATA":         0,
+	"FOLDER_SHARE": 1,
+	"PUBLIC_LINK":  2,
+}
+
+func (x TransferredItem_Kind) String() string {
+	return proto.EnumName(TransferredItem_Kind_name, int32(x))
+}
+
+func (TransferredItem_Kind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{83, 0}
 }
 
 type TagReq struct {
@@ -428,6 +590,7 @@ func (m *IsPublicLinkProtectedResponse) GetProtected() bool {
 type ForgePublicLinkTokenReq struct {
 	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	ClientIp             string   `protobuf:"bytes,3,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -472,6 +635,13 @@ func (m *ForgePublicLinkTokenReq) GetPassword() string {
 	return ""
 }
 
+func (m *ForgePublicLinkTokenReq) GetClientIp() string {
+	if m != nil {
+		return m.ClientIp
+	}
+	return ""
+}
+
 type ForgePublicLinkTokenResponse struct {
 	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
 	Valid                bool       `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
@@ -817,9 +987,15 @@ func (m *UserResponse) GetUser() *User {
 }
 
 type User struct {
-	AccountId            string   `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	Groups               []string `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
-	DisplayName          string   `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	AccountId   string   `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Groups      []string `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
+	DisplayName string   `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// guests are external users invited by e-mail that only access what was shared with them
+	Guest bool `protobuf:"varint,4,opt,name=guest,proto3" json:"guest,omitempty"`
+	// set when the user logged in with a scoped credential, like an app password
+	Scope *TokenScope `protobuf:"bytes,5,opt,name=scope,proto3" json:"scope,omitempty"`
+	// set when an admin impersonates the user, the account id of the admin
+	Impersonator         string   `protobuf:"bytes,6,opt,name=impersonator,proto3" json:"impersonator,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -871,2877 +1047,6021 @@ func (m *User) GetDisplayName() string {
 	return ""
 }
 
-type TxInfoResponse struct {
-	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	TxInfo               *TxInfo    `protobuf:"bytes,2,opt,name=txInfo,proto3" json:"txInfo,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+func (m *User) GetGuest() bool {
+	if m != nil {
+		return m.Guest
+	}
+	return false
 }
 
-func (m *TxInfoResponse) Reset()         { *m = TxInfoResponse{} }
-func (m *TxInfoResponse) String() string { return proto.CompactTextString(m) }
-func (*TxInfoResponse) ProtoMessage()    {}
-func (*TxInfoResponse) Descriptor() ([]byte, []int) {
+func (m *User) GetScope() *TokenScope {
+	if m != nil {
+		return m.Scope
+	}
+	return nil
+}
+
+func (m *User) GetImpersonator() string {
+	if m != nil {
+		return m.Impersonator
+	}
+	return ""
+}
+
+// TokenScope restricts what can be done with the tokens of a user.
+type TokenScope struct {
+	ReadOnly bool `protobuf:"varint,1,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	// if set only this path and the paths under it can be accessed
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// if set the tokens do not outlive it, in unix seconds
+	Expiration uint64 `protobuf:"varint,3,opt,name=expiration,proto3" json:"expiration,omitempty"`
+	// if set only this id-based path, like home:123, and the paths under it can be accessed
+	ResourceId           string   `protobuf:"bytes,4,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TokenScope) Reset()         { *m = TokenScope{} }
+func (m *TokenScope) String() string { return proto.CompactTextString(m) }
+func (*TokenScope) ProtoMessage()    {}
+func (*TokenScope) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
 }
 
-func (m *TxInfoResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TxInfoResponse.Unmarshal(m, b)
+func (m *TokenScope) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenScope.Unmarshal(m, b)
 }
-func (m *TxInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TxInfoResponse.Marshal(b, m, deterministic)
+func (m *TokenScope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenScope.Marshal(b, m, deterministic)
 }
-func (m *TxInfoResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxInfoResponse.Merge(m, src)
+func (m *TokenScope) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenScope.Merge(m, src)
 }
-func (m *TxInfoResponse) XXX_Size() int {
-	return xxx_messageInfo_TxInfoResponse.Size(m)
+func (m *TokenScope) XXX_Size() int {
+	return xxx_messageInfo_TokenScope.Size(m)
 }
-func (m *TxInfoResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_TxInfoResponse.DiscardUnknown(m)
+func (m *TokenScope) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenScope.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TxInfoResponse proto.InternalMessageInfo
+var xxx_messageInfo_TokenScope proto.InternalMessageInfo
 
-func (m *TxInfoResponse) GetStatus() StatusCode {
+func (m *TokenScope) GetReadOnly() bool {
 	if m != nil {
-		return m.Status
+		return m.ReadOnly
 	}
-	return StatusCode_OK
+	return false
 }
 
-func (m *TxInfoResponse) GetTxInfo() *TxInfo {
+func (m *TokenScope) GetPath() string {
 	if m != nil {
-		return m.TxInfo
+		return m.Path
 	}
-	return nil
+	return ""
 }
 
-type TxInfo struct {
-	TxId                 string   `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+func (m *TokenScope) GetExpiration() uint64 {
+	if m != nil {
+		return m.Expiration
+	}
+	return 0
+}
+
+func (m *TokenScope) GetResourceId() string {
+	if m != nil {
+		return m.ResourceId
+	}
+	return ""
+}
+
+type ScopedTokenReq struct {
+	Scope *TokenScope `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+	// the lifetime of the token in seconds, like the length of an editing session,
+	// zero uses the default one and it is capped by the maximum one
+	Lifetime             uint64   `protobuf:"varint,2,opt,name=lifetime,proto3" json:"lifetime,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *TxInfo) Reset()         { *m = TxInfo{} }
-func (m *TxInfo) String() string { return proto.CompactTextString(m) }
-func (*TxInfo) ProtoMessage()    {}
-func (*TxInfo) Descriptor() ([]byte, []int) {
+func (m *ScopedTokenReq) Reset()         { *m = ScopedTokenReq{} }
+func (m *ScopedTokenReq) String() string { return proto.CompactTextString(m) }
+func (*ScopedTokenReq) ProtoMessage()    {}
+func (*ScopedTokenReq) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
 }
 
-func (m *TxInfo) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TxInfo.Unmarshal(m, b)
+func (m *ScopedTokenReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScopedTokenReq.Unmarshal(m, b)
 }
-func (m *TxInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TxInfo.Marshal(b, m, deterministic)
+func (m *ScopedTokenReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScopedTokenReq.Marshal(b, m, deterministic)
 }
-func (m *TxInfo) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxInfo.Merge(m, src)
+func (m *ScopedTokenReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScopedTokenReq.Merge(m, src)
 }
-func (m *TxInfo) XXX_Size() int {
-	return xxx_messageInfo_TxInfo.Size(m)
+func (m *ScopedTokenReq) XXX_Size() int {
+	return xxx_messageInfo_ScopedTokenReq.Size(m)
 }
-func (m *TxInfo) XXX_DiscardUnknown() {
-	xxx_messageInfo_TxInfo.DiscardUnknown(m)
+func (m *ScopedTokenReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScopedTokenReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TxInfo proto.InternalMessageInfo
+var xxx_messageInfo_ScopedTokenReq proto.InternalMessageInfo
 
-func (m *TxInfo) GetTxId() string {
+func (m *ScopedTokenReq) GetScope() *TokenScope {
 	if m != nil {
-		return m.TxId
+		return m.Scope
 	}
-	return ""
+	return nil
 }
 
-type ForgeUserTokenReq struct {
-	ClientId             string   `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
-	ClientSecret         string   `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *ScopedTokenReq) GetLifetime() uint64 {
+	if m != nil {
+		return m.Lifetime
+	}
+	return 0
 }
 
-func (m *ForgeUserTokenReq) Reset()         { *m = ForgeUserTokenReq{} }
-func (m *ForgeUserTokenReq) String() string { return proto.CompactTextString(m) }
-func (*ForgeUserTokenReq) ProtoMessage()    {}
-func (*ForgeUserTokenReq) Descriptor() ([]byte, []int) {
+type AppPassword struct {
+	Id                   string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label                string      `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Scope                *TokenScope `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`
+	Ctime                uint64      `protobuf:"varint,4,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	LastUsed             uint64      `protobuf:"varint,5,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *AppPassword) Reset()         { *m = AppPassword{} }
+func (m *AppPassword) String() string { return proto.CompactTextString(m) }
+func (*AppPassword) ProtoMessage()    {}
+func (*AppPassword) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{16}
 }
 
-func (m *ForgeUserTokenReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ForgeUserTokenReq.Unmarshal(m, b)
+func (m *AppPassword) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppPassword.Unmarshal(m, b)
 }
-func (m *ForgeUserTokenReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ForgeUserTokenReq.Marshal(b, m, deterministic)
+func (m *AppPassword) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppPassword.Marshal(b, m, deterministic)
 }
-func (m *ForgeUserTokenReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ForgeUserTokenReq.Merge(m, src)
+func (m *AppPassword) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppPassword.Merge(m, src)
 }
-func (m *ForgeUserTokenReq) XXX_Size() int {
-	return xxx_messageInfo_ForgeUserTokenReq.Size(m)
+func (m *AppPassword) XXX_Size() int {
+	return xxx_messageInfo_AppPassword.Size(m)
 }
-func (m *ForgeUserTokenReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_ForgeUserTokenReq.DiscardUnknown(m)
+func (m *AppPassword) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppPassword.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ForgeUserTokenReq proto.InternalMessageInfo
+var xxx_messageInfo_AppPassword proto.InternalMessageInfo
 
-func (m *ForgeUserTokenReq) GetClientId() string {
+func (m *AppPassword) GetId() string {
 	if m != nil {
-		return m.ClientId
+		return m.Id
 	}
 	return ""
 }
 
-func (m *ForgeUserTokenReq) GetClientSecret() string {
+func (m *AppPassword) GetLabel() string {
 	if m != nil {
-		return m.ClientSecret
+		return m.Label
 	}
 	return ""
 }
 
-type TokenResponse struct {
-	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	Token                string     `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+func (m *AppPassword) GetScope() *TokenScope {
+	if m != nil {
+		return m.Scope
+	}
+	return nil
 }
 
-func (m *TokenResponse) Reset()         { *m = TokenResponse{} }
-func (m *TokenResponse) String() string { return proto.CompactTextString(m) }
-func (*TokenResponse) ProtoMessage()    {}
-func (*TokenResponse) Descriptor() ([]byte, []int) {
+func (m *AppPassword) GetCtime() uint64 {
+	if m != nil {
+		return m.Ctime
+	}
+	return 0
+}
+
+func (m *AppPassword) GetLastUsed() uint64 {
+	if m != nil {
+		return m.LastUsed
+	}
+	return 0
+}
+
+type NewAppPasswordReq struct {
+	Label                string      `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Scope                *TokenScope `protobuf:"bytes,2,opt,name=scope,proto3" json:"scope,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *NewAppPasswordReq) Reset()         { *m = NewAppPasswordReq{} }
+func (m *NewAppPasswordReq) String() string { return proto.CompactTextString(m) }
+func (*NewAppPasswordReq) ProtoMessage()    {}
+func (*NewAppPasswordReq) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{17}
 }
 
-func (m *TokenResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TokenResponse.Unmarshal(m, b)
+func (m *NewAppPasswordReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewAppPasswordReq.Unmarshal(m, b)
 }
-func (m *TokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TokenResponse.Marshal(b, m, deterministic)
+func (m *NewAppPasswordReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewAppPasswordReq.Marshal(b, m, deterministic)
 }
-func (m *TokenResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TokenResponse.Merge(m, src)
+func (m *NewAppPasswordReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewAppPasswordReq.Merge(m, src)
 }
-func (m *TokenResponse) XXX_Size() int {
-	return xxx_messageInfo_TokenResponse.Size(m)
+func (m *NewAppPasswordReq) XXX_Size() int {
+	return xxx_messageInfo_NewAppPasswordReq.Size(m)
 }
-func (m *TokenResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_TokenResponse.DiscardUnknown(m)
+func (m *NewAppPasswordReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewAppPasswordReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TokenResponse proto.InternalMessageInfo
+var xxx_messageInfo_NewAppPasswordReq proto.InternalMessageInfo
 
-func (m *TokenResponse) GetStatus() StatusCode {
+func (m *NewAppPasswordReq) GetLabel() string {
 	if m != nil {
-		return m.Status
+		return m.Label
 	}
-	return StatusCode_OK
+	return ""
 }
 
-func (m *TokenResponse) GetToken() string {
+func (m *NewAppPasswordReq) GetScope() *TokenScope {
 	if m != nil {
-		return m.Token
+		return m.Scope
 	}
-	return ""
+	return nil
 }
 
-type TokenReq struct {
-	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+type AppPasswordReq struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *TokenReq) Reset()         { *m = TokenReq{} }
-func (m *TokenReq) String() string { return proto.CompactTextString(m) }
-func (*TokenReq) ProtoMessage()    {}
-func (*TokenReq) Descriptor() ([]byte, []int) {
+func (m *AppPasswordReq) Reset()         { *m = AppPasswordReq{} }
+func (m *AppPasswordReq) String() string { return proto.CompactTextString(m) }
+func (*AppPasswordReq) ProtoMessage()    {}
+func (*AppPasswordReq) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{18}
 }
 
-func (m *TokenReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TokenReq.Unmarshal(m, b)
+func (m *AppPasswordReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppPasswordReq.Unmarshal(m, b)
 }
-func (m *TokenReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TokenReq.Marshal(b, m, deterministic)
+func (m *AppPasswordReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppPasswordReq.Marshal(b, m, deterministic)
 }
-func (m *TokenReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TokenReq.Merge(m, src)
+func (m *AppPasswordReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppPasswordReq.Merge(m, src)
 }
-func (m *TokenReq) XXX_Size() int {
-	return xxx_messageInfo_TokenReq.Size(m)
+func (m *AppPasswordReq) XXX_Size() int {
+	return xxx_messageInfo_AppPasswordReq.Size(m)
 }
-func (m *TokenReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_TokenReq.DiscardUnknown(m)
+func (m *AppPasswordReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppPasswordReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TokenReq proto.InternalMessageInfo
+var xxx_messageInfo_AppPasswordReq proto.InternalMessageInfo
 
-func (m *TokenReq) GetToken() string {
+func (m *AppPasswordReq) GetId() string {
 	if m != nil {
-		return m.Token
+		return m.Id
 	}
 	return ""
 }
 
-type MetadataResponse struct {
-	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	Metadata             *Metadata  `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+type AppPasswordResponse struct {
+	Status      StatusCode   `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	AppPassword *AppPassword `protobuf:"bytes,2,opt,name=app_password,json=appPassword,proto3" json:"app_password,omitempty"`
+	// only set when the app password is created
+	Password             string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *MetadataResponse) Reset()         { *m = MetadataResponse{} }
-func (m *MetadataResponse) String() string { return proto.CompactTextString(m) }
-func (*MetadataResponse) ProtoMessage()    {}
-func (*MetadataResponse) Descriptor() ([]byte, []int) {
+func (m *AppPasswordResponse) Reset()         { *m = AppPasswordResponse{} }
+func (m *AppPasswordResponse) String() string { return proto.CompactTextString(m) }
+func (*AppPasswordResponse) ProtoMessage()    {}
+func (*AppPasswordResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{19}
 }
 
-func (m *MetadataResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MetadataResponse.Unmarshal(m, b)
+func (m *AppPasswordResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppPasswordResponse.Unmarshal(m, b)
 }
-func (m *MetadataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MetadataResponse.Marshal(b, m, deterministic)
+func (m *AppPasswordResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppPasswordResponse.Marshal(b, m, deterministic)
 }
-func (m *MetadataResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MetadataResponse.Merge(m, src)
+func (m *AppPasswordResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppPasswordResponse.Merge(m, src)
 }
-func (m *MetadataResponse) XXX_Size() int {
-	return xxx_messageInfo_MetadataResponse.Size(m)
+func (m *AppPasswordResponse) XXX_Size() int {
+	return xxx_messageInfo_AppPasswordResponse.Size(m)
 }
-func (m *MetadataResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_MetadataResponse.DiscardUnknown(m)
+func (m *AppPasswordResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppPasswordResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_MetadataResponse proto.InternalMessageInfo
+var xxx_messageInfo_AppPasswordResponse proto.InternalMessageInfo
 
-func (m *MetadataResponse) GetStatus() StatusCode {
+func (m *AppPasswordResponse) GetStatus() StatusCode {
 	if m != nil {
 		return m.Status
 	}
 	return StatusCode_OK
 }
 
-func (m *MetadataResponse) GetMetadata() *Metadata {
+func (m *AppPasswordResponse) GetAppPassword() *AppPassword {
 	if m != nil {
-		return m.Metadata
+		return m.AppPassword
 	}
 	return nil
 }
 
-type Metadata struct {
-	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Path        string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	Size        uint64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
-	Mtime       uint64 `protobuf:"varint,4,opt,name=mtime,proto3" json:"mtime,omitempty"`
-	IsDir       bool   `protobuf:"varint,5,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
-	Etag        string `protobuf:"bytes,6,opt,name=etag,proto3" json:"etag,omitempty"`
-	Checksum    string `protobuf:"bytes,7,opt,name=checksum,proto3" json:"checksum,omitempty"`
-	DerefPath   string `protobuf:"bytes,8,opt,name=deref_path,json=derefPath,proto3" json:"deref_path,omitempty"`
-	IsReadOnly  bool   `protobuf:"varint,9,opt,name=is_read_only,json=isReadOnly,proto3" json:"is_read_only,omitempty"`
-	IsShareable bool   `protobuf:"varint,10,opt,name=is_shareable,json=isShareable,proto3" json:"is_shareable,omitempty"`
-	Mime        string `protobuf:"bytes,11,opt,name=mime,proto3" json:"mime,omitempty"`
-	Sys         []byte `protobuf:"bytes,12,opt,name=sys,proto3" json:"sys,omitempty"`
-	TreeCount   uint64 `protobuf:"varint,13,opt,name=tree_count,json=treeCount,proto3" json:"tree_count,omitempty"`
-	// EOS filesytem extended metadata records
-	EosFile     string `protobuf:"bytes,14,opt,name=eos_file,json=eosFile,proto3" json:"eos_file,omitempty"`
-	EosInstance string `protobuf:"bytes,15,opt,name=eos_instance,json=eosInstance,proto3" json:"eos_instance,omitempty"`
-	// Share extended metadata records
-	ShareTarget string `protobuf:"bytes,16,opt,name=share_target,json=shareTarget,proto3" json:"share_target,omitempty"`
-	// Migration extended metadata records
-	MigId                string   `protobuf:"bytes,17,opt,name=mig_id,json=migId,proto3" json:"mig_id,omitempty"`
-	MigPath              string   `protobuf:"bytes,18,opt,name=mig_path,json=migPath,proto3" json:"mig_path,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *AppPasswordResponse) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
 }
 
-func (m *Metadata) Reset()         { *m = Metadata{} }
-func (m *Metadata) String() string { return proto.CompactTextString(m) }
-func (*Metadata) ProtoMessage()    {}
-func (*Metadata) Descriptor() ([]byte, []int) {
+type TxInfoResponse struct {
+	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	TxInfo               *TxInfo    `protobuf:"bytes,2,opt,name=txInfo,proto3" json:"txInfo,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *TxInfoResponse) Reset()         { *m = TxInfoResponse{} }
+func (m *TxInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*TxInfoResponse) ProtoMessage()    {}
+func (*TxInfoResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{20}
 }
 
-func (m *Metadata) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Metadata.Unmarshal(m, b)
+func (m *TxInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxInfoResponse.Unmarshal(m, b)
 }
-func (m *Metadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Metadata.Marshal(b, m, deterministic)
+func (m *TxInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxInfoResponse.Marshal(b, m, deterministic)
 }
-func (m *Metadata) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Metadata.Merge(m, src)
+func (m *TxInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxInfoResponse.Merge(m, src)
 }
-func (m *Metadata) XXX_Size() int {
-	return xxx_messageInfo_Metadata.Size(m)
+func (m *TxInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_TxInfoResponse.Size(m)
 }
-func (m *Metadata) XXX_DiscardUnknown() {
-	xxx_messageInfo_Metadata.DiscardUnknown(m)
+func (m *TxInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxInfoResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Metadata proto.InternalMessageInfo
+var xxx_messageInfo_TxInfoResponse proto.InternalMessageInfo
 
-func (m *Metadata) GetId() string {
+func (m *TxInfoResponse) GetStatus() StatusCode {
 	if m != nil {
-		return m.Id
+		return m.Status
 	}
-	return ""
+	return StatusCode_OK
 }
 
-func (m *Metadata) GetPath() string {
+func (m *TxInfoResponse) GetTxInfo() *TxInfo {
 	if m != nil {
-		return m.Path
+		return m.TxInfo
 	}
-	return ""
+	return nil
 }
 
-func (m *Metadata) GetSize() uint64 {
-	if m != nil {
-		return m.Size
-	}
-	return 0
+type TxInfo struct {
+	TxId                 string   `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Metadata) GetMtime() uint64 {
-	if m != nil {
-		return m.Mtime
-	}
-	return 0
+func (m *TxInfo) Reset()         { *m = TxInfo{} }
+func (m *TxInfo) String() string { return proto.CompactTextString(m) }
+func (*TxInfo) ProtoMessage()    {}
+func (*TxInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
 }
 
-func (m *Metadata) GetIsDir() bool {
-	if m != nil {
-		return m.IsDir
-	}
-	return false
+func (m *TxInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxInfo.Unmarshal(m, b)
 }
-
-func (m *Metadata) GetEtag() string {
-	if m != nil {
-		return m.Etag
-	}
-	return ""
+func (m *TxInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxInfo.Marshal(b, m, deterministic)
 }
-
-func (m *Metadata) GetChecksum() string {
-	if m != nil {
-		return m.Checksum
-	}
-	return ""
+func (m *TxInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxInfo.Merge(m, src)
 }
-
-func (m *Metadata) GetDerefPath() string {
-	if m != nil {
-		return m.DerefPath
-	}
-	return ""
+func (m *TxInfo) XXX_Size() int {
+	return xxx_messageInfo_TxInfo.Size(m)
 }
-
-func (m *Metadata) GetIsReadOnly() bool {
-	if m != nil {
-		return m.IsReadOnly
-	}
-	return false
+func (m *TxInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxInfo.DiscardUnknown(m)
 }
 
-func (m *Metadata) GetIsShareable() bool {
-	if m != nil {
-		return m.IsShareable
-	}
-	return false
-}
+var xxx_messageInfo_TxInfo proto.InternalMessageInfo
 
-func (m *Metadata) GetMime() string {
+func (m *TxInfo) GetTxId() string {
 	if m != nil {
-		return m.Mime
+		return m.TxId
 	}
 	return ""
 }
 
-func (m *Metadata) GetSys() []byte {
-	if m != nil {
-		return m.Sys
-	}
-	return nil
+type ForgeUserTokenReq struct {
+	ClientId     string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	// also forge a refresh token
+	Refresh              bool     `protobuf:"varint,3,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Metadata) GetTreeCount() uint64 {
-	if m != nil {
-		return m.TreeCount
-	}
-	return 0
+func (m *ForgeUserTokenReq) Reset()         { *m = ForgeUserTokenReq{} }
+func (m *ForgeUserTokenReq) String() string { return proto.CompactTextString(m) }
+func (*ForgeUserTokenReq) ProtoMessage()    {}
+func (*ForgeUserTokenReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
 }
 
-func (m *Metadata) GetEosFile() string {
-	if m != nil {
-		return m.EosFile
-	}
-	return ""
+func (m *ForgeUserTokenReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ForgeUserTokenReq.Unmarshal(m, b)
 }
-
-func (m *Metadata) GetEosInstance() string {
-	if m != nil {
-		return m.EosInstance
-	}
-	return ""
+func (m *ForgeUserTokenReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ForgeUserTokenReq.Marshal(b, m, deterministic)
+}
+func (m *ForgeUserTokenReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ForgeUserTokenReq.Merge(m, src)
+}
+func (m *ForgeUserTokenReq) XXX_Size() int {
+	return xxx_messageInfo_ForgeUserTokenReq.Size(m)
+}
+func (m *ForgeUserTokenReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ForgeUserTokenReq.DiscardUnknown(m)
 }
 
-func (m *Metadata) GetShareTarget() string {
+var xxx_messageInfo_ForgeUserTokenReq proto.InternalMessageInfo
+
+func (m *ForgeUserTokenReq) GetClientId() string {
 	if m != nil {
-		return m.ShareTarget
+		return m.ClientId
 	}
 	return ""
 }
 
-func (m *Metadata) GetMigId() string {
+func (m *ForgeUserTokenReq) GetClientSecret() string {
 	if m != nil {
-		return m.MigId
+		return m.ClientSecret
 	}
 	return ""
 }
 
-func (m *Metadata) GetMigPath() string {
+func (m *ForgeUserTokenReq) GetRefresh() bool {
 	if m != nil {
-		return m.MigPath
+		return m.Refresh
 	}
-	return ""
+	return false
 }
 
-type PathReq struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+type RefreshTokenReq struct {
+	RefreshToken         string   `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PathReq) Reset()         { *m = PathReq{} }
-func (m *PathReq) String() string { return proto.CompactTextString(m) }
-func (*PathReq) ProtoMessage()    {}
-func (*PathReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
+func (m *RefreshTokenReq) Reset()         { *m = RefreshTokenReq{} }
+func (m *RefreshTokenReq) String() string { return proto.CompactTextString(m) }
+func (*RefreshTokenReq) ProtoMessage()    {}
+func (*RefreshTokenReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
 }
 
-func (m *PathReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PathReq.Unmarshal(m, b)
+func (m *RefreshTokenReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefreshTokenReq.Unmarshal(m, b)
 }
-func (m *PathReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PathReq.Marshal(b, m, deterministic)
+func (m *RefreshTokenReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefreshTokenReq.Marshal(b, m, deterministic)
 }
-func (m *PathReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PathReq.Merge(m, src)
+func (m *RefreshTokenReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefreshTokenReq.Merge(m, src)
 }
-func (m *PathReq) XXX_Size() int {
-	return xxx_messageInfo_PathReq.Size(m)
+func (m *RefreshTokenReq) XXX_Size() int {
+	return xxx_messageInfo_RefreshTokenReq.Size(m)
 }
-func (m *PathReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_PathReq.DiscardUnknown(m)
+func (m *RefreshTokenReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefreshTokenReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PathReq proto.InternalMessageInfo
+var xxx_messageInfo_RefreshTokenReq proto.InternalMessageInfo
 
-func (m *PathReq) GetPath() string {
+func (m *RefreshTokenReq) GetRefreshToken() string {
 	if m != nil {
-		return m.Path
+		return m.RefreshToken
 	}
 	return ""
 }
 
-type MoveReq struct {
-	OldPath              string   `protobuf:"bytes,1,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`
-	NewPath              string   `protobuf:"bytes,2,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+type ActivateGuestReq struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *MoveReq) Reset()         { *m = MoveReq{} }
-func (m *MoveReq) String() string { return proto.CompactTextString(m) }
-func (*MoveReq) ProtoMessage()    {}
-func (*MoveReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
+func (m *ActivateGuestReq) Reset()         { *m = ActivateGuestReq{} }
+func (m *ActivateGuestReq) String() string { return proto.CompactTextString(m) }
+func (*ActivateGuestReq) ProtoMessage()    {}
+func (*ActivateGuestReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{24}
 }
 
-func (m *MoveReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MoveReq.Unmarshal(m, b)
+func (m *ActivateGuestReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActivateGuestReq.Unmarshal(m, b)
 }
-func (m *MoveReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MoveReq.Marshal(b, m, deterministic)
+func (m *ActivateGuestReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActivateGuestReq.Marshal(b, m, deterministic)
 }
-func (m *MoveReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MoveReq.Merge(m, src)
+func (m *ActivateGuestReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActivateGuestReq.Merge(m, src)
 }
-func (m *MoveReq) XXX_Size() int {
-	return xxx_messageInfo_MoveReq.Size(m)
+func (m *ActivateGuestReq) XXX_Size() int {
+	return xxx_messageInfo_ActivateGuestReq.Size(m)
 }
-func (m *MoveReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_MoveReq.DiscardUnknown(m)
+func (m *ActivateGuestReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActivateGuestReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_MoveReq proto.InternalMessageInfo
+var xxx_messageInfo_ActivateGuestReq proto.InternalMessageInfo
 
-func (m *MoveReq) GetOldPath() string {
+func (m *ActivateGuestReq) GetToken() string {
 	if m != nil {
-		return m.OldPath
+		return m.Token
 	}
 	return ""
 }
 
-func (m *MoveReq) GetNewPath() string {
+func (m *ActivateGuestReq) GetPassword() string {
 	if m != nil {
-		return m.NewPath
+		return m.Password
 	}
 	return ""
 }
 
-// maybe add checksum data ?
-type TxChunk struct {
-	TxId                 string   `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
-	Length               uint64   `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
-	Offset               uint64   `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
-	Data                 []byte   `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type TokenResponse struct {
+	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Token                string     `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken         string     `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
-func (m *TxChunk) Reset()         { *m = TxChunk{} }
-func (m *TxChunk) String() string { return proto.CompactTextString(m) }
-func (*TxChunk) ProtoMessage()    {}
-func (*TxChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
+func (m *TokenResponse) Reset()         { *m = TokenResponse{} }
+func (m *TokenResponse) String() string { return proto.CompactTextString(m) }
+func (*TokenResponse) ProtoMessage()    {}
+func (*TokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{25}
 }
 
-func (m *TxChunk) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TxChunk.Unmarshal(m, b)
+func (m *TokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenResponse.Unmarshal(m, b)
 }
-func (m *TxChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TxChunk.Marshal(b, m, deterministic)
+func (m *TokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenResponse.Marshal(b, m, deterministic)
 }
-func (m *TxChunk) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxChunk.Merge(m, src)
+func (m *TokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenResponse.Merge(m, src)
 }
-func (m *TxChunk) XXX_Size() int {
-	return xxx_messageInfo_TxChunk.Size(m)
+func (m *TokenResponse) XXX_Size() int {
+	return xxx_messageInfo_TokenResponse.Size(m)
 }
-func (m *TxChunk) XXX_DiscardUnknown() {
-	xxx_messageInfo_TxChunk.DiscardUnknown(m)
+func (m *TokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TxChunk proto.InternalMessageInfo
-
-func (m *TxChunk) GetTxId() string {
-	if m != nil {
-		return m.TxId
-	}
-	return ""
-}
+var xxx_messageInfo_TokenResponse proto.InternalMessageInfo
 
-func (m *TxChunk) GetLength() uint64 {
+func (m *TokenResponse) GetStatus() StatusCode {
 	if m != nil {
-		return m.Length
+		return m.Status
 	}
-	return 0
+	return StatusCode_OK
 }
 
-func (m *TxChunk) GetOffset() uint64 {
+func (m *TokenResponse) GetToken() string {
 	if m != nil {
-		return m.Offset
+		return m.Token
 	}
-	return 0
+	return ""
 }
 
-func (m *TxChunk) GetData() []byte {
+func (m *TokenResponse) GetRefreshToken() string {
 	if m != nil {
-		return m.Data
+		return m.RefreshToken
 	}
-	return nil
+	return ""
 }
 
-type WriteSummaryResponse struct {
-	Status               StatusCode    `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	WriteSummary         *WriteSummary `protobuf:"bytes,2,opt,name=writeSummary,proto3" json:"writeSummary,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+type TokenReq struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WriteSummaryResponse) Reset()         { *m = WriteSummaryResponse{} }
-func (m *WriteSummaryResponse) String() string { return proto.CompactTextString(m) }
-func (*WriteSummaryResponse) ProtoMessage()    {}
-func (*WriteSummaryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{24}
+func (m *TokenReq) Reset()         { *m = TokenReq{} }
+func (m *TokenReq) String() string { return proto.CompactTextString(m) }
+func (*TokenReq) ProtoMessage()    {}
+func (*TokenReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{26}
 }
 
-func (m *WriteSummaryResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WriteSummaryResponse.Unmarshal(m, b)
+func (m *TokenReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenReq.Unmarshal(m, b)
 }
-func (m *WriteSummaryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WriteSummaryResponse.Marshal(b, m, deterministic)
+func (m *TokenReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenReq.Marshal(b, m, deterministic)
 }
-func (m *WriteSummaryResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WriteSummaryResponse.Merge(m, src)
+func (m *TokenReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenReq.Merge(m, src)
 }
-func (m *WriteSummaryResponse) XXX_Size() int {
-	return xxx_messageInfo_WriteSummaryResponse.Size(m)
+func (m *TokenReq) XXX_Size() int {
+	return xxx_messageInfo_TokenReq.Size(m)
 }
-func (m *WriteSummaryResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_WriteSummaryResponse.DiscardUnknown(m)
+func (m *TokenReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WriteSummaryResponse proto.InternalMessageInfo
-
-func (m *WriteSummaryResponse) GetStatus() StatusCode {
-	if m != nil {
-		return m.Status
-	}
-	return StatusCode_OK
-}
+var xxx_messageInfo_TokenReq proto.InternalMessageInfo
 
-func (m *WriteSummaryResponse) GetWriteSummary() *WriteSummary {
+func (m *TokenReq) GetToken() string {
 	if m != nil {
-		return m.WriteSummary
+		return m.Token
 	}
-	return nil
+	return ""
 }
 
-type WriteSummary struct {
-	Nchunks              uint64   `protobuf:"varint,1,opt,name=nchunks,proto3" json:"nchunks,omitempty"`
-	TotalSize            uint64   `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type MetadataResponse struct {
+	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Metadata             *Metadata  `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
-func (m *WriteSummary) Reset()         { *m = WriteSummary{} }
-func (m *WriteSummary) String() string { return proto.CompactTextString(m) }
-func (*WriteSummary) ProtoMessage()    {}
-func (*WriteSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{25}
+func (m *MetadataResponse) Reset()         { *m = MetadataResponse{} }
+func (m *MetadataResponse) String() string { return proto.CompactTextString(m) }
+func (*MetadataResponse) ProtoMessage()    {}
+func (*MetadataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27}
 }
 
-func (m *WriteSummary) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WriteSummary.Unmarshal(m, b)
+func (m *MetadataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetadataResponse.Unmarshal(m, b)
 }
-func (m *WriteSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WriteSummary.Marshal(b, m, deterministic)
+func (m *MetadataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetadataResponse.Marshal(b, m, deterministic)
 }
-func (m *WriteSummary) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WriteSummary.Merge(m, src)
+func (m *MetadataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetadataResponse.Merge(m, src)
 }
-func (m *WriteSummary) XXX_Size() int {
-	return xxx_messageInfo_WriteSummary.Size(m)
+func (m *MetadataResponse) XXX_Size() int {
+	return xxx_messageInfo_MetadataResponse.Size(m)
 }
-func (m *WriteSummary) XXX_DiscardUnknown() {
-	xxx_messageInfo_WriteSummary.DiscardUnknown(m)
+func (m *MetadataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetadataResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WriteSummary proto.InternalMessageInfo
+var xxx_messageInfo_MetadataResponse proto.InternalMessageInfo
 
-func (m *WriteSummary) GetNchunks() uint64 {
+func (m *MetadataResponse) GetStatus() StatusCode {
 	if m != nil {
-		return m.Nchunks
+		return m.Status
 	}
-	return 0
+	return StatusCode_OK
 }
 
-func (m *WriteSummary) GetTotalSize() uint64 {
+func (m *MetadataResponse) GetMetadata() *Metadata {
 	if m != nil {
-		return m.TotalSize
+		return m.Metadata
 	}
-	return 0
+	return nil
 }
 
-type TxEnd struct {
-	TxId                 string   `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
-	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	Checksum             string   `protobuf:"bytes,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
+type Metadata struct {
+	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Path        string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Size        uint64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Mtime       uint64 `protobuf:"varint,4,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	IsDir       bool   `protobuf:"varint,5,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	Etag        string `protobuf:"bytes,6,opt,name=etag,proto3" json:"etag,omitempty"`
+	Checksum    string `protobuf:"bytes,7,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	DerefPath   string `protobuf:"bytes,8,opt,name=deref_path,json=derefPath,proto3" json:"deref_path,omitempty"`
+	IsReadOnly  bool   `protobuf:"varint,9,opt,name=is_read_only,json=isReadOnly,proto3" json:"is_read_only,omitempty"`
+	IsShareable bool   `protobuf:"varint,10,opt,name=is_shareable,json=isShareable,proto3" json:"is_shareable,omitempty"`
+	Mime        string `protobuf:"bytes,11,opt,name=mime,proto3" json:"mime,omitempty"`
+	Sys         []byte `protobuf:"bytes,12,opt,name=sys,proto3" json:"sys,omitempty"`
+	TreeCount   uint64 `protobuf:"varint,13,opt,name=tree_count,json=treeCount,proto3" json:"tree_count,omitempty"`
+	// EOS filesytem extended metadata records
+	EosFile     string `protobuf:"bytes,14,opt,name=eos_file,json=eosFile,proto3" json:"eos_file,omitempty"`
+	EosInstance string `protobuf:"bytes,15,opt,name=eos_instance,json=eosInstance,proto3" json:"eos_instance,omitempty"`
+	// Share extended metadata records
+	ShareTarget string `protobuf:"bytes,16,opt,name=share_target,json=shareTarget,proto3" json:"share_target,omitempty"`
+	// Migration extended metadata records
+	MigId   string `protobuf:"bytes,17,opt,name=mig_id,json=migId,proto3" json:"mig_id,omitempty"`
+	MigPath string `protobuf:"bytes,18,opt,name=mig_path,json=migPath,proto3" json:"mig_path,omitempty"`
+	// Share indicators, the kinds of shares on the resource: user, group, unix, remote or link
+	ShareTypes           []string `protobuf:"bytes,19,rep,name=share_types,json=shareTypes,proto3" json:"share_types,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *TxEnd) Reset()         { *m = TxEnd{} }
-func (m *TxEnd) String() string { return proto.CompactTextString(m) }
-func (*TxEnd) ProtoMessage()    {}
-func (*TxEnd) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{26}
+func (m *Metadata) Reset()         { *m = Metadata{} }
+func (m *Metadata) String() string { return proto.CompactTextString(m) }
+func (*Metadata) ProtoMessage()    {}
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{28}
 }
 
-func (m *TxEnd) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TxEnd.Unmarshal(m, b)
+func (m *Metadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Metadata.Unmarshal(m, b)
 }
-func (m *TxEnd) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TxEnd.Marshal(b, m, deterministic)
+func (m *Metadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Metadata.Marshal(b, m, deterministic)
 }
-func (m *TxEnd) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxEnd.Merge(m, src)
+func (m *Metadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Metadata.Merge(m, src)
 }
-func (m *TxEnd) XXX_Size() int {
-	return xxx_messageInfo_TxEnd.Size(m)
+func (m *Metadata) XXX_Size() int {
+	return xxx_messageInfo_Metadata.Size(m)
 }
-func (m *TxEnd) XXX_DiscardUnknown() {
-	xxx_messageInfo_TxEnd.DiscardUnknown(m)
+func (m *Metadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_Metadata.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TxEnd proto.InternalMessageInfo
+var xxx_messageInfo_Metadata proto.InternalMessageInfo
 
-func (m *TxEnd) GetTxId() string {
+func (m *Metadata) GetId() string {
 	if m != nil {
-		return m.TxId
+		return m.Id
 	}
 	return ""
 }
 
-func (m *TxEnd) GetPath() string {
+func (m *Metadata) GetPath() string {
 	if m != nil {
 		return m.Path
 	}
 	return ""
 }
 
-func (m *TxEnd) GetChecksum() string {
+func (m *Metadata) GetSize() uint64 {
 	if m != nil {
-		return m.Checksum
+		return m.Size
 	}
-	return ""
+	return 0
 }
 
-type DataChunkResponse struct {
-	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	DataChunk            *DataChunk `protobuf:"bytes,2,opt,name=dataChunk,proto3" json:"dataChunk,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+func (m *Metadata) GetMtime() uint64 {
+	if m != nil {
+		return m.Mtime
+	}
+	return 0
 }
 
-func (m *DataChunkResponse) Reset()         { *m = DataChunkResponse{} }
-func (m *DataChunkResponse) String() string { return proto.CompactTextString(m) }
-func (*DataChunkResponse) ProtoMessage()    {}
-func (*DataChunkResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{27}
+func (m *Metadata) GetIsDir() bool {
+	if m != nil {
+		return m.IsDir
+	}
+	return false
 }
 
-func (m *DataChunkResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DataChunkResponse.Unmarshal(m, b)
-}
-func (m *DataChunkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DataChunkResponse.Marshal(b, m, deterministic)
+func (m *Metadata) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
 }
-func (m *DataChunkResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DataChunkResponse.Merge(m, src)
+
+func (m *Metadata) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
 }
-func (m *DataChunkResponse) XXX_Size() int {
-	return xxx_messageInfo_DataChunkResponse.Size(m)
+
+func (m *Metadata) GetDerefPath() string {
+	if m != nil {
+		return m.DerefPath
+	}
+	return ""
 }
-func (m *DataChunkResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_DataChunkResponse.DiscardUnknown(m)
+
+func (m *Metadata) GetIsReadOnly() bool {
+	if m != nil {
+		return m.IsReadOnly
+	}
+	return false
 }
 
-var xxx_messageInfo_DataChunkResponse proto.InternalMessageInfo
+func (m *Metadata) GetIsShareable() bool {
+	if m != nil {
+		return m.IsShareable
+	}
+	return false
+}
 
-func (m *DataChunkResponse) GetStatus() StatusCode {
+func (m *Metadata) GetMime() string {
 	if m != nil {
-		return m.Status
+		return m.Mime
 	}
-	return StatusCode_OK
+	return ""
 }
 
-func (m *DataChunkResponse) GetDataChunk() *DataChunk {
+func (m *Metadata) GetSys() []byte {
 	if m != nil {
-		return m.DataChunk
+		return m.Sys
 	}
 	return nil
 }
 
-type DataChunk struct {
-	Length               uint64   `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
-	Offset               uint64   `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
-	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Metadata) GetTreeCount() uint64 {
+	if m != nil {
+		return m.TreeCount
+	}
+	return 0
 }
 
-func (m *DataChunk) Reset()         { *m = DataChunk{} }
-func (m *DataChunk) String() string { return proto.CompactTextString(m) }
-func (*DataChunk) ProtoMessage()    {}
-func (*DataChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{28}
+func (m *Metadata) GetEosFile() string {
+	if m != nil {
+		return m.EosFile
+	}
+	return ""
 }
 
-func (m *DataChunk) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DataChunk.Unmarshal(m, b)
-}
-func (m *DataChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DataChunk.Marshal(b, m, deterministic)
-}
-func (m *DataChunk) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DataChunk.Merge(m, src)
-}
-func (m *DataChunk) XXX_Size() int {
-	return xxx_messageInfo_DataChunk.Size(m)
-}
-func (m *DataChunk) XXX_DiscardUnknown() {
-	xxx_messageInfo_DataChunk.DiscardUnknown(m)
+func (m *Metadata) GetEosInstance() string {
+	if m != nil {
+		return m.EosInstance
+	}
+	return ""
 }
 
-var xxx_messageInfo_DataChunk proto.InternalMessageInfo
+func (m *Metadata) GetShareTarget() string {
+	if m != nil {
+		return m.ShareTarget
+	}
+	return ""
+}
 
-func (m *DataChunk) GetLength() uint64 {
+func (m *Metadata) GetMigId() string {
 	if m != nil {
-		return m.Length
+		return m.MigId
 	}
-	return 0
+	return ""
 }
 
-func (m *DataChunk) GetOffset() uint64 {
+func (m *Metadata) GetMigPath() string {
 	if m != nil {
-		return m.Offset
+		return m.MigPath
 	}
-	return 0
+	return ""
 }
 
-func (m *DataChunk) GetData() []byte {
+func (m *Metadata) GetShareTypes() []string {
 	if m != nil {
-		return m.Data
+		return m.ShareTypes
 	}
 	return nil
 }
 
-type RevisionResponse struct {
-	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	Revision             *Revision  `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+type PathReq struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RevisionResponse) Reset()         { *m = RevisionResponse{} }
-func (m *RevisionResponse) String() string { return proto.CompactTextString(m) }
-func (*RevisionResponse) ProtoMessage()    {}
-func (*RevisionResponse) Descriptor() ([]byte, []int) {
+func (m *PathReq) Reset()         { *m = PathReq{} }
+func (m *PathReq) String() string { return proto.CompactTextString(m) }
+func (*PathReq) ProtoMessage()    {}
+func (*PathReq) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{29}
 }
 
-func (m *RevisionResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RevisionResponse.Unmarshal(m, b)
+func (m *PathReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PathReq.Unmarshal(m, b)
 }
-func (m *RevisionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RevisionResponse.Marshal(b, m, deterministic)
+func (m *PathReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PathReq.Marshal(b, m, deterministic)
 }
-func (m *RevisionResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RevisionResponse.Merge(m, src)
+func (m *PathReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PathReq.Merge(m, src)
 }
-func (m *RevisionResponse) XXX_Size() int {
-	return xxx_messageInfo_RevisionResponse.Size(m)
+func (m *PathReq) XXX_Size() int {
+	return xxx_messageInfo_PathReq.Size(m)
 }
-func (m *RevisionResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_RevisionResponse.DiscardUnknown(m)
+func (m *PathReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_PathReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RevisionResponse proto.InternalMessageInfo
-
-func (m *RevisionResponse) GetStatus() StatusCode {
-	if m != nil {
-		return m.Status
-	}
-	return StatusCode_OK
-}
+var xxx_messageInfo_PathReq proto.InternalMessageInfo
 
-func (m *RevisionResponse) GetRevision() *Revision {
+func (m *PathReq) GetPath() string {
 	if m != nil {
-		return m.Revision
+		return m.Path
 	}
-	return nil
+	return ""
 }
 
-type Revision struct {
-	RevKey               string   `protobuf:"bytes,1,opt,name=rev_key,json=revKey,proto3" json:"rev_key,omitempty"`
-	Size                 uint64   `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
-	Mtime                uint64   `protobuf:"varint,3,opt,name=mtime,proto3" json:"mtime,omitempty"`
-	IsDir                bool     `protobuf:"varint,4,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+type MoveReq struct {
+	OldPath              string   `protobuf:"bytes,1,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`
+	NewPath              string   `protobuf:"bytes,2,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Revision) Reset()         { *m = Revision{} }
-func (m *Revision) String() string { return proto.CompactTextString(m) }
-func (*Revision) ProtoMessage()    {}
-func (*Revision) Descriptor() ([]byte, []int) {
+func (m *MoveReq) Reset()         { *m = MoveReq{} }
+func (m *MoveReq) String() string { return proto.CompactTextString(m) }
+func (*MoveReq) ProtoMessage()    {}
+func (*MoveReq) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{30}
 }
 
-func (m *Revision) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Revision.Unmarshal(m, b)
+func (m *MoveReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveReq.Unmarshal(m, b)
 }
-func (m *Revision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Revision.Marshal(b, m, deterministic)
+func (m *MoveReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveReq.Marshal(b, m, deterministic)
 }
-func (m *Revision) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Revision.Merge(m, src)
+func (m *MoveReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveReq.Merge(m, src)
 }
-func (m *Revision) XXX_Size() int {
-	return xxx_messageInfo_Revision.Size(m)
+func (m *MoveReq) XXX_Size() int {
+	return xxx_messageInfo_MoveReq.Size(m)
 }
-func (m *Revision) XXX_DiscardUnknown() {
-	xxx_messageInfo_Revision.DiscardUnknown(m)
+func (m *MoveReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Revision proto.InternalMessageInfo
+var xxx_messageInfo_MoveReq proto.InternalMessageInfo
 
-func (m *Revision) GetRevKey() string {
+func (m *MoveReq) GetOldPath() string {
 	if m != nil {
-		return m.RevKey
+		return m.OldPath
 	}
 	return ""
 }
 
-func (m *Revision) GetSize() uint64 {
+func (m *MoveReq) GetNewPath() string {
 	if m != nil {
-		return m.Size
+		return m.NewPath
 	}
-	return 0
+	return ""
 }
 
-func (m *Revision) GetMtime() uint64 {
-	if m != nil {
-		return m.Mtime
-	}
-	return 0
+// maybe add checksum data ?
+type TxChunk struct {
+	TxId                 string   `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Length               uint64   `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	Offset               uint64   `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data                 []byte   `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Revision) GetIsDir() bool {
-	if m != nil {
-		return m.IsDir
-	}
-	return false
-}
-
-type RevisionReq struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	RevKey               string   `protobuf:"bytes,2,opt,name=rev_key,json=revKey,proto3" json:"rev_key,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *RevisionReq) Reset()         { *m = RevisionReq{} }
-func (m *RevisionReq) String() string { return proto.CompactTextString(m) }
-func (*RevisionReq) ProtoMessage()    {}
-func (*RevisionReq) Descriptor() ([]byte, []int) {
+func (m *TxChunk) Reset()         { *m = TxChunk{} }
+func (m *TxChunk) String() string { return proto.CompactTextString(m) }
+func (*TxChunk) ProtoMessage()    {}
+func (*TxChunk) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{31}
 }
 
-func (m *RevisionReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RevisionReq.Unmarshal(m, b)
+func (m *TxChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxChunk.Unmarshal(m, b)
 }
-func (m *RevisionReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RevisionReq.Marshal(b, m, deterministic)
+func (m *TxChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxChunk.Marshal(b, m, deterministic)
 }
-func (m *RevisionReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RevisionReq.Merge(m, src)
+func (m *TxChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxChunk.Merge(m, src)
 }
-func (m *RevisionReq) XXX_Size() int {
-	return xxx_messageInfo_RevisionReq.Size(m)
+func (m *TxChunk) XXX_Size() int {
+	return xxx_messageInfo_TxChunk.Size(m)
 }
-func (m *RevisionReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_RevisionReq.DiscardUnknown(m)
+func (m *TxChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxChunk.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RevisionReq proto.InternalMessageInfo
+var xxx_messageInfo_TxChunk proto.InternalMessageInfo
 
-func (m *RevisionReq) GetPath() string {
+func (m *TxChunk) GetTxId() string {
 	if m != nil {
-		return m.Path
+		return m.TxId
 	}
 	return ""
 }
 
-func (m *RevisionReq) GetRevKey() string {
+func (m *TxChunk) GetLength() uint64 {
 	if m != nil {
-		return m.RevKey
+		return m.Length
 	}
-	return ""
+	return 0
 }
 
-type RecycleEntryResponse struct {
+func (m *TxChunk) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *TxChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type WriteSummaryResponse struct {
 	Status               StatusCode    `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	RecycleEntry         *RecycleEntry `protobuf:"bytes,2,opt,name=recycleEntry,proto3" json:"recycleEntry,omitempty"`
+	WriteSummary         *WriteSummary `protobuf:"bytes,2,opt,name=writeSummary,proto3" json:"writeSummary,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
 	XXX_unrecognized     []byte        `json:"-"`
 	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *RecycleEntryResponse) Reset()         { *m = RecycleEntryResponse{} }
-func (m *RecycleEntryResponse) String() string { return proto.CompactTextString(m) }
-func (*RecycleEntryResponse) ProtoMessage()    {}
-func (*RecycleEntryResponse) Descriptor() ([]byte, []int) {
+func (m *WriteSummaryResponse) Reset()         { *m = WriteSummaryResponse{} }
+func (m *WriteSummaryResponse) String() string { return proto.CompactTextString(m) }
+func (*WriteSummaryResponse) ProtoMessage()    {}
+func (*WriteSummaryResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{32}
 }
 
-func (m *RecycleEntryResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RecycleEntryResponse.Unmarshal(m, b)
+func (m *WriteSummaryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteSummaryResponse.Unmarshal(m, b)
 }
-func (m *RecycleEntryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RecycleEntryResponse.Marshal(b, m, deterministic)
+func (m *WriteSummaryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteSummaryResponse.Marshal(b, m, deterministic)
 }
-func (m *RecycleEntryResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RecycleEntryResponse.Merge(m, src)
+func (m *WriteSummaryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteSummaryResponse.Merge(m, src)
 }
-func (m *RecycleEntryResponse) XXX_Size() int {
-	return xxx_messageInfo_RecycleEntryResponse.Size(m)
+func (m *WriteSummaryResponse) XXX_Size() int {
+	return xxx_messageInfo_WriteSummaryResponse.Size(m)
 }
-func (m *RecycleEntryResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_RecycleEntryResponse.DiscardUnknown(m)
+func (m *WriteSummaryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteSummaryResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RecycleEntryResponse proto.InternalMessageInfo
+var xxx_messageInfo_WriteSummaryResponse proto.InternalMessageInfo
 
-func (m *RecycleEntryResponse) GetStatus() StatusCode {
+func (m *WriteSummaryResponse) GetStatus() StatusCode {
 	if m != nil {
 		return m.Status
 	}
 	return StatusCode_OK
 }
 
-func (m *RecycleEntryResponse) GetRecycleEntry() *RecycleEntry {
+func (m *WriteSummaryResponse) GetWriteSummary() *WriteSummary {
 	if m != nil {
-		return m.RecycleEntry
+		return m.WriteSummary
 	}
 	return nil
 }
 
-type RecycleEntry struct {
-	RestorePath          string   `protobuf:"bytes,1,opt,name=restore_path,json=restorePath,proto3" json:"restore_path,omitempty"`
-	RestoreKey           string   `protobuf:"bytes,2,opt,name=restore_key,json=restoreKey,proto3" json:"restore_key,omitempty"`
-	Size                 uint64   `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
-	DelMtime             uint64   `protobuf:"varint,4,opt,name=del_mtime,json=delMtime,proto3" json:"del_mtime,omitempty"`
-	IsDir                bool     `protobuf:"varint,5,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+type WriteSummary struct {
+	Nchunks              uint64   `protobuf:"varint,1,opt,name=nchunks,proto3" json:"nchunks,omitempty"`
+	TotalSize            uint64   `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RecycleEntry) Reset()         { *m = RecycleEntry{} }
-func (m *RecycleEntry) String() string { return proto.CompactTextString(m) }
-func (*RecycleEntry) ProtoMessage()    {}
-func (*RecycleEntry) Descriptor() ([]byte, []int) {
+func (m *WriteSummary) Reset()         { *m = WriteSummary{} }
+func (m *WriteSummary) String() string { return proto.CompactTextString(m) }
+func (*WriteSummary) ProtoMessage()    {}
+func (*WriteSummary) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{33}
 }
 
-func (m *RecycleEntry) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RecycleEntry.Unmarshal(m, b)
-}
-func (m *RecycleEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RecycleEntry.Marshal(b, m, deterministic)
+func (m *WriteSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteSummary.Unmarshal(m, b)
 }
-func (m *RecycleEntry) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RecycleEntry.Merge(m, src)
+func (m *WriteSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteSummary.Marshal(b, m, deterministic)
 }
-func (m *RecycleEntry) XXX_Size() int {
-	return xxx_messageInfo_RecycleEntry.Size(m)
+func (m *WriteSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteSummary.Merge(m, src)
 }
-func (m *RecycleEntry) XXX_DiscardUnknown() {
-	xxx_messageInfo_RecycleEntry.DiscardUnknown(m)
+func (m *WriteSummary) XXX_Size() int {
+	return xxx_messageInfo_WriteSummary.Size(m)
 }
-
-var xxx_messageInfo_RecycleEntry proto.InternalMessageInfo
-
-func (m *RecycleEntry) GetRestorePath() string {
-	if m != nil {
-		return m.RestorePath
-	}
-	return ""
+func (m *WriteSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteSummary.DiscardUnknown(m)
 }
 
-func (m *RecycleEntry) GetRestoreKey() string {
-	if m != nil {
-		return m.RestoreKey
-	}
-	return ""
-}
+var xxx_messageInfo_WriteSummary proto.InternalMessageInfo
 
-func (m *RecycleEntry) GetSize() uint64 {
+func (m *WriteSummary) GetNchunks() uint64 {
 	if m != nil {
-		return m.Size
+		return m.Nchunks
 	}
 	return 0
 }
 
-func (m *RecycleEntry) GetDelMtime() uint64 {
+func (m *WriteSummary) GetTotalSize() uint64 {
 	if m != nil {
-		return m.DelMtime
+		return m.TotalSize
 	}
 	return 0
 }
 
-func (m *RecycleEntry) GetIsDir() bool {
-	if m != nil {
-		return m.IsDir
-	}
-	return false
-}
-
-type RecycleEntryReq struct {
-	RestoreKey           string   `protobuf:"bytes,1,opt,name=restore_key,json=restoreKey,proto3" json:"restore_key,omitempty"`
+type TxEnd struct {
+	TxId                 string   `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Checksum             string   `protobuf:"bytes,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RecycleEntryReq) Reset()         { *m = RecycleEntryReq{} }
-func (m *RecycleEntryReq) String() string { return proto.CompactTextString(m) }
-func (*RecycleEntryReq) ProtoMessage()    {}
-func (*RecycleEntryReq) Descriptor() ([]byte, []int) {
+func (m *TxEnd) Reset()         { *m = TxEnd{} }
+func (m *TxEnd) String() string { return proto.CompactTextString(m) }
+func (*TxEnd) ProtoMessage()    {}
+func (*TxEnd) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{34}
 }
 
-func (m *RecycleEntryReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RecycleEntryReq.Unmarshal(m, b)
+func (m *TxEnd) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxEnd.Unmarshal(m, b)
 }
-func (m *RecycleEntryReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RecycleEntryReq.Marshal(b, m, deterministic)
+func (m *TxEnd) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxEnd.Marshal(b, m, deterministic)
 }
-func (m *RecycleEntryReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RecycleEntryReq.Merge(m, src)
+func (m *TxEnd) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxEnd.Merge(m, src)
 }
-func (m *RecycleEntryReq) XXX_Size() int {
-	return xxx_messageInfo_RecycleEntryReq.Size(m)
+func (m *TxEnd) XXX_Size() int {
+	return xxx_messageInfo_TxEnd.Size(m)
 }
-func (m *RecycleEntryReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_RecycleEntryReq.DiscardUnknown(m)
+func (m *TxEnd) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxEnd.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RecycleEntryReq proto.InternalMessageInfo
+var xxx_messageInfo_TxEnd proto.InternalMessageInfo
 
-func (m *RecycleEntryReq) GetRestoreKey() string {
+func (m *TxEnd) GetTxId() string {
 	if m != nil {
-		return m.RestoreKey
+		return m.TxId
 	}
 	return ""
 }
 
-type LinkPermissions struct {
-	Read                 bool     `protobuf:"varint,1,opt,name=read,proto3" json:"read,omitempty"`
-	Write                bool     `protobuf:"varint,2,opt,name=write,proto3" json:"write,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *TxEnd) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
 }
 
-func (m *LinkPermissions) Reset()         { *m = LinkPermissions{} }
-func (m *LinkPermissions) String() string { return proto.CompactTextString(m) }
-func (*LinkPermissions) ProtoMessage()    {}
-func (*LinkPermissions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{35}
+func (m *TxEnd) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
 }
 
-func (m *LinkPermissions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LinkPermissions.Unmarshal(m, b)
-}
-func (m *LinkPermissions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LinkPermissions.Marshal(b, m, deterministic)
+type DataChunkResponse struct {
+	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	DataChunk            *DataChunk `protobuf:"bytes,2,opt,name=dataChunk,proto3" json:"dataChunk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
-func (m *LinkPermissions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LinkPermissions.Merge(m, src)
+
+func (m *DataChunkResponse) Reset()         { *m = DataChunkResponse{} }
+func (m *DataChunkResponse) String() string { return proto.CompactTextString(m) }
+func (*DataChunkResponse) ProtoMessage()    {}
+func (*DataChunkResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{35}
 }
-func (m *LinkPermissions) XXX_Size() int {
-	return xxx_messageInfo_LinkPermissions.Size(m)
+
+func (m *DataChunkResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DataChunkResponse.Unmarshal(m, b)
 }
-func (m *LinkPermissions) XXX_DiscardUnknown() {
-	xxx_messageInfo_LinkPermissions.DiscardUnknown(m)
+func (m *DataChunkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DataChunkResponse.Marshal(b, m, deterministic)
+}
+func (m *DataChunkResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DataChunkResponse.Merge(m, src)
+}
+func (m *DataChunkResponse) XXX_Size() int {
+	return xxx_messageInfo_DataChunkResponse.Size(m)
+}
+func (m *DataChunkResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DataChunkResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LinkPermissions proto.InternalMessageInfo
+var xxx_messageInfo_DataChunkResponse proto.InternalMessageInfo
 
-func (m *LinkPermissions) GetRead() bool {
+func (m *DataChunkResponse) GetStatus() StatusCode {
 	if m != nil {
-		return m.Read
+		return m.Status
 	}
-	return false
+	return StatusCode_OK
 }
 
-func (m *LinkPermissions) GetWrite() bool {
+func (m *DataChunkResponse) GetDataChunk() *DataChunk {
 	if m != nil {
-		return m.Write
+		return m.DataChunk
 	}
-	return false
+	return nil
 }
 
-type NewLinkReq struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	ReadOnly             bool     `protobuf:"varint,2,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
-	Password             string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
-	Expires              uint64   `protobuf:"varint,4,opt,name=expires,proto3" json:"expires,omitempty"`
-	DropOnly             bool     `protobuf:"varint,5,opt,name=drop_only,json=dropOnly,proto3" json:"drop_only,omitempty"`
+type DataChunk struct {
+	Length               uint64   `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	Offset               uint64   `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *NewLinkReq) Reset()         { *m = NewLinkReq{} }
-func (m *NewLinkReq) String() string { return proto.CompactTextString(m) }
-func (*NewLinkReq) ProtoMessage()    {}
-func (*NewLinkReq) Descriptor() ([]byte, []int) {
+func (m *DataChunk) Reset()         { *m = DataChunk{} }
+func (m *DataChunk) String() string { return proto.CompactTextString(m) }
+func (*DataChunk) ProtoMessage()    {}
+func (*DataChunk) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{36}
 }
 
-func (m *NewLinkReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_NewLinkReq.Unmarshal(m, b)
+func (m *DataChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DataChunk.Unmarshal(m, b)
 }
-func (m *NewLinkReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_NewLinkReq.Marshal(b, m, deterministic)
+func (m *DataChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DataChunk.Marshal(b, m, deterministic)
 }
-func (m *NewLinkReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NewLinkReq.Merge(m, src)
+func (m *DataChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DataChunk.Merge(m, src)
 }
-func (m *NewLinkReq) XXX_Size() int {
-	return xxx_messageInfo_NewLinkReq.Size(m)
+func (m *DataChunk) XXX_Size() int {
+	return xxx_messageInfo_DataChunk.Size(m)
 }
-func (m *NewLinkReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_NewLinkReq.DiscardUnknown(m)
+func (m *DataChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_DataChunk.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_NewLinkReq proto.InternalMessageInfo
+var xxx_messageInfo_DataChunk proto.InternalMessageInfo
 
-func (m *NewLinkReq) GetPath() string {
+func (m *DataChunk) GetLength() uint64 {
 	if m != nil {
-		return m.Path
+		return m.Length
 	}
-	return ""
+	return 0
 }
 
-func (m *NewLinkReq) GetReadOnly() bool {
+func (m *DataChunk) GetOffset() uint64 {
 	if m != nil {
-		return m.ReadOnly
+		return m.Offset
 	}
-	return false
+	return 0
 }
 
-func (m *NewLinkReq) GetPassword() string {
+func (m *DataChunk) GetData() []byte {
 	if m != nil {
-		return m.Password
+		return m.Data
 	}
-	return ""
+	return nil
 }
 
-func (m *NewLinkReq) GetExpires() uint64 {
+type RevisionResponse struct {
+	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Revision             *Revision  `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *RevisionResponse) Reset()         { *m = RevisionResponse{} }
+func (m *RevisionResponse) String() string { return proto.CompactTextString(m) }
+func (*RevisionResponse) ProtoMessage()    {}
+func (*RevisionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{37}
+}
+
+func (m *RevisionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevisionResponse.Unmarshal(m, b)
+}
+func (m *RevisionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevisionResponse.Marshal(b, m, deterministic)
+}
+func (m *RevisionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevisionResponse.Merge(m, src)
+}
+func (m *RevisionResponse) XXX_Size() int {
+	return xxx_messageInfo_RevisionResponse.Size(m)
+}
+func (m *RevisionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevisionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevisionResponse proto.InternalMessageInfo
+
+func (m *RevisionResponse) GetStatus() StatusCode {
 	if m != nil {
-		return m.Expires
+		return m.Status
 	}
-	return 0
+	return StatusCode_OK
 }
 
-func (m *NewLinkReq) GetDropOnly() bool {
+func (m *RevisionResponse) GetRevision() *Revision {
 	if m != nil {
-		return m.DropOnly
+		return m.Revision
 	}
-	return false
+	return nil
 }
 
-type UpdateLinkReq struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UpdatePassword       bool     `protobuf:"varint,2,opt,name=update_password,json=updatePassword,proto3" json:"update_password,omitempty"`
-	Password             string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
-	UpdateExpiration     bool     `protobuf:"varint,4,opt,name=update_expiration,json=updateExpiration,proto3" json:"update_expiration,omitempty"`
-	Expiration           uint64   `protobuf:"varint,5,opt,name=expiration,proto3" json:"expiration,omitempty"`
-	ReadOnly             bool     `protobuf:"varint,6,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
-	UpdateReadOnly       bool     `protobuf:"varint,7,opt,name=update_read_only,json=updateReadOnly,proto3" json:"update_read_only,omitempty"`
-	DropOnly             bool     `protobuf:"varint,8,opt,name=drop_only,json=dropOnly,proto3" json:"drop_only,omitempty"`
-	UpdateDropOnly       bool     `protobuf:"varint,9,opt,name=update_drop_only,json=updateDropOnly,proto3" json:"update_drop_only,omitempty"`
+type Revision struct {
+	RevKey               string   `protobuf:"bytes,1,opt,name=rev_key,json=revKey,proto3" json:"rev_key,omitempty"`
+	Size                 uint64   `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Mtime                uint64   `protobuf:"varint,3,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	IsDir                bool     `protobuf:"varint,4,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateLinkReq) Reset()         { *m = UpdateLinkReq{} }
-func (m *UpdateLinkReq) String() string { return proto.CompactTextString(m) }
-func (*UpdateLinkReq) ProtoMessage()    {}
-func (*UpdateLinkReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{37}
+func (m *Revision) Reset()         { *m = Revision{} }
+func (m *Revision) String() string { return proto.CompactTextString(m) }
+func (*Revision) ProtoMessage()    {}
+func (*Revision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{38}
 }
 
-func (m *UpdateLinkReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateLinkReq.Unmarshal(m, b)
+func (m *Revision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Revision.Unmarshal(m, b)
 }
-func (m *UpdateLinkReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateLinkReq.Marshal(b, m, deterministic)
+func (m *Revision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Revision.Marshal(b, m, deterministic)
 }
-func (m *UpdateLinkReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateLinkReq.Merge(m, src)
+func (m *Revision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Revision.Merge(m, src)
 }
-func (m *UpdateLinkReq) XXX_Size() int {
-	return xxx_messageInfo_UpdateLinkReq.Size(m)
+func (m *Revision) XXX_Size() int {
+	return xxx_messageInfo_Revision.Size(m)
 }
-func (m *UpdateLinkReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateLinkReq.DiscardUnknown(m)
+func (m *Revision) XXX_DiscardUnknown() {
+	xxx_messageInfo_Revision.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UpdateLinkReq proto.InternalMessageInfo
+var xxx_messageInfo_Revision proto.InternalMessageInfo
 
-func (m *UpdateLinkReq) GetId() string {
+func (m *Revision) GetRevKey() string {
 	if m != nil {
-		return m.Id
+		return m.RevKey
 	}
 	return ""
 }
 
-func (m *UpdateLinkReq) GetUpdatePassword() bool {
+func (m *Revision) GetSize() uint64 {
 	if m != nil {
-		return m.UpdatePassword
+		return m.Size
 	}
-	return false
+	return 0
 }
 
-func (m *UpdateLinkReq) GetPassword() string {
+func (m *Revision) GetMtime() uint64 {
 	if m != nil {
-		return m.Password
+		return m.Mtime
 	}
-	return ""
+	return 0
 }
 
-func (m *UpdateLinkReq) GetUpdateExpiration() bool {
+func (m *Revision) GetIsDir() bool {
 	if m != nil {
-		return m.UpdateExpiration
+		return m.IsDir
 	}
 	return false
 }
 
-func (m *UpdateLinkReq) GetExpiration() uint64 {
-	if m != nil {
-		return m.Expiration
-	}
-	return 0
+type RevisionReq struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	RevKey               string   `protobuf:"bytes,2,opt,name=rev_key,json=revKey,proto3" json:"rev_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateLinkReq) GetReadOnly() bool {
-	if m != nil {
-		return m.ReadOnly
-	}
-	return false
+func (m *RevisionReq) Reset()         { *m = RevisionReq{} }
+func (m *RevisionReq) String() string { return proto.CompactTextString(m) }
+func (*RevisionReq) ProtoMessage()    {}
+func (*RevisionReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{39}
 }
 
-func (m *UpdateLinkReq) GetUpdateReadOnly() bool {
-	if m != nil {
-		return m.UpdateReadOnly
-	}
-	return false
+func (m *RevisionReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevisionReq.Unmarshal(m, b)
+}
+func (m *RevisionReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevisionReq.Marshal(b, m, deterministic)
+}
+func (m *RevisionReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevisionReq.Merge(m, src)
+}
+func (m *RevisionReq) XXX_Size() int {
+	return xxx_messageInfo_RevisionReq.Size(m)
+}
+func (m *RevisionReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevisionReq.DiscardUnknown(m)
 }
 
-func (m *UpdateLinkReq) GetDropOnly() bool {
+var xxx_messageInfo_RevisionReq proto.InternalMessageInfo
+
+func (m *RevisionReq) GetPath() string {
 	if m != nil {
-		return m.DropOnly
+		return m.Path
 	}
-	return false
+	return ""
 }
 
-func (m *UpdateLinkReq) GetUpdateDropOnly() bool {
+func (m *RevisionReq) GetRevKey() string {
 	if m != nil {
-		return m.UpdateDropOnly
+		return m.RevKey
 	}
-	return false
-}
-
-type PublicLinkResponse struct {
-	Status               StatusCode  `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	PublicLink           *PublicLink `protobuf:"bytes,2,opt,name=publicLink,proto3" json:"publicLink,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	return ""
 }
 
-func (m *PublicLinkResponse) Reset()         { *m = PublicLinkResponse{} }
-func (m *PublicLinkResponse) String() string { return proto.CompactTextString(m) }
-func (*PublicLinkResponse) ProtoMessage()    {}
-func (*PublicLinkResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{38}
+type RecycleEntryResponse struct {
+	Status               StatusCode    `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	RecycleEntry         *RecycleEntry `protobuf:"bytes,2,opt,name=recycleEntry,proto3" json:"recycleEntry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *PublicLinkResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PublicLinkResponse.Unmarshal(m, b)
+func (m *RecycleEntryResponse) Reset()         { *m = RecycleEntryResponse{} }
+func (m *RecycleEntryResponse) String() string { return proto.CompactTextString(m) }
+func (*RecycleEntryResponse) ProtoMessage()    {}
+func (*RecycleEntryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{40}
 }
-func (m *PublicLinkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PublicLinkResponse.Marshal(b, m, deterministic)
+
+func (m *RecycleEntryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecycleEntryResponse.Unmarshal(m, b)
 }
-func (m *PublicLinkResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PublicLinkResponse.Merge(m, src)
+func (m *RecycleEntryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecycleEntryResponse.Marshal(b, m, deterministic)
 }
-func (m *PublicLinkResponse) XXX_Size() int {
-	return xxx_messageInfo_PublicLinkResponse.Size(m)
+func (m *RecycleEntryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecycleEntryResponse.Merge(m, src)
 }
-func (m *PublicLinkResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_PublicLinkResponse.DiscardUnknown(m)
+func (m *RecycleEntryResponse) XXX_Size() int {
+	return xxx_messageInfo_RecycleEntryResponse.Size(m)
+}
+func (m *RecycleEntryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecycleEntryResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PublicLinkResponse proto.InternalMessageInfo
+var xxx_messageInfo_RecycleEntryResponse proto.InternalMessageInfo
 
-func (m *PublicLinkResponse) GetStatus() StatusCode {
+func (m *RecycleEntryResponse) GetStatus() StatusCode {
 	if m != nil {
 		return m.Status
 	}
 	return StatusCode_OK
 }
 
-func (m *PublicLinkResponse) GetPublicLink() *PublicLink {
+func (m *RecycleEntryResponse) GetRecycleEntry() *RecycleEntry {
 	if m != nil {
-		return m.PublicLink
+		return m.RecycleEntry
 	}
 	return nil
 }
 
-type ShareRecipient struct {
-	Identity             string                       `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
-	Type                 ShareRecipient_RecipientType `protobuf:"varint,2,opt,name=type,proto3,enum=api.ShareRecipient_RecipientType" json:"type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
-	XXX_unrecognized     []byte                       `json:"-"`
-	XXX_sizecache        int32                        `json:"-"`
+type RecycleEntry struct {
+	RestorePath          string   `protobuf:"bytes,1,opt,name=restore_path,json=restorePath,proto3" json:"restore_path,omitempty"`
+	RestoreKey           string   `protobuf:"bytes,2,opt,name=restore_key,json=restoreKey,proto3" json:"restore_key,omitempty"`
+	Size                 uint64   `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	DelMtime             uint64   `protobuf:"varint,4,opt,name=del_mtime,json=delMtime,proto3" json:"del_mtime,omitempty"`
+	IsDir                bool     `protobuf:"varint,5,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ShareRecipient) Reset()         { *m = ShareRecipient{} }
-func (m *ShareRecipient) String() string { return proto.CompactTextString(m) }
-func (*ShareRecipient) ProtoMessage()    {}
-func (*ShareRecipient) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{39}
+func (m *RecycleEntry) Reset()         { *m = RecycleEntry{} }
+func (m *RecycleEntry) String() string { return proto.CompactTextString(m) }
+func (*RecycleEntry) ProtoMessage()    {}
+func (*RecycleEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{41}
 }
 
-func (m *ShareRecipient) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ShareRecipient.Unmarshal(m, b)
+func (m *RecycleEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecycleEntry.Unmarshal(m, b)
 }
-func (m *ShareRecipient) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ShareRecipient.Marshal(b, m, deterministic)
+func (m *RecycleEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecycleEntry.Marshal(b, m, deterministic)
 }
-func (m *ShareRecipient) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShareRecipient.Merge(m, src)
+func (m *RecycleEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecycleEntry.Merge(m, src)
 }
-func (m *ShareRecipient) XXX_Size() int {
-	return xxx_messageInfo_ShareRecipient.Size(m)
+func (m *RecycleEntry) XXX_Size() int {
+	return xxx_messageInfo_RecycleEntry.Size(m)
 }
-func (m *ShareRecipient) XXX_DiscardUnknown() {
-	xxx_messageInfo_ShareRecipient.DiscardUnknown(m)
+func (m *RecycleEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecycleEntry.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ShareRecipient proto.InternalMessageInfo
+var xxx_messageInfo_RecycleEntry proto.InternalMessageInfo
 
-func (m *ShareRecipient) GetIdentity() string {
+func (m *RecycleEntry) GetRestorePath() string {
 	if m != nil {
-		return m.Identity
+		return m.RestorePath
 	}
 	return ""
 }
 
-func (m *ShareRecipient) GetType() ShareRecipient_RecipientType {
+func (m *RecycleEntry) GetRestoreKey() string {
 	if m != nil {
-		return m.Type
+		return m.RestoreKey
 	}
-	return ShareRecipient_USER
-}
-
-type ACLReq struct {
-	Path                 string          `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Recipient            *ShareRecipient `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
-	ReadOnly             bool            `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
-	Shares               []*FolderShare  `protobuf:"bytes,4,rep,name=shares,proto3" json:"shares,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
-}
-
-func (m *ACLReq) Reset()         { *m = ACLReq{} }
-func (m *ACLReq) String() string { return proto.CompactTextString(m) }
-func (*ACLReq) ProtoMessage()    {}
-func (*ACLReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{40}
-}
-
-func (m *ACLReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ACLReq.Unmarshal(m, b)
-}
-func (m *ACLReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ACLReq.Marshal(b, m, deterministic)
-}
-func (m *ACLReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ACLReq.Merge(m, src)
-}
-func (m *ACLReq) XXX_Size() int {
-	return xxx_messageInfo_ACLReq.Size(m)
-}
-func (m *ACLReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_ACLReq.DiscardUnknown(m)
+	return ""
 }
 
-var xxx_messageInfo_ACLReq proto.InternalMessageInfo
-
-func (m *ACLReq) GetPath() string {
+func (m *RecycleEntry) GetSize() uint64 {
 	if m != nil {
-		return m.Path
+		return m.Size
 	}
-	return ""
+	return 0
 }
 
-func (m *ACLReq) GetRecipient() *ShareRecipient {
+func (m *RecycleEntry) GetDelMtime() uint64 {
 	if m != nil {
-		return m.Recipient
+		return m.DelMtime
 	}
-	return nil
+	return 0
 }
 
-func (m *ACLReq) GetReadOnly() bool {
+func (m *RecycleEntry) GetIsDir() bool {
 	if m != nil {
-		return m.ReadOnly
+		return m.IsDir
 	}
 	return false
 }
 
-func (m *ACLReq) GetShares() []*FolderShare {
+type RecycleEntryReq struct {
+	RestoreKey           string   `protobuf:"bytes,1,opt,name=restore_key,json=restoreKey,proto3" json:"restore_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecycleEntryReq) Reset()         { *m = RecycleEntryReq{} }
+func (m *RecycleEntryReq) String() string { return proto.CompactTextString(m) }
+func (*RecycleEntryReq) ProtoMessage()    {}
+func (*RecycleEntryReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{42}
+}
+
+func (m *RecycleEntryReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecycleEntryReq.Unmarshal(m, b)
+}
+func (m *RecycleEntryReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecycleEntryReq.Marshal(b, m, deterministic)
+}
+func (m *RecycleEntryReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecycleEntryReq.Merge(m, src)
+}
+func (m *RecycleEntryReq) XXX_Size() int {
+	return xxx_messageInfo_RecycleEntryReq.Size(m)
+}
+func (m *RecycleEntryReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecycleEntryReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecycleEntryReq proto.InternalMessageInfo
+
+func (m *RecycleEntryReq) GetRestoreKey() string {
 	if m != nil {
-		return m.Shares
+		return m.RestoreKey
 	}
-	return nil
+	return ""
 }
 
-type PublicLink struct {
-	Id                   string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Token                string              `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
-	Path                 string              `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
-	Protected            bool                `protobuf:"varint,4,opt,name=protected,proto3" json:"protected,omitempty"`
-	Expires              uint64              `protobuf:"varint,5,opt,name=expires,proto3" json:"expires,omitempty"`
-	ReadOnly             bool                `protobuf:"varint,6,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
-	Mtime                uint64              `protobuf:"varint,7,opt,name=mtime,proto3" json:"mtime,omitempty"`
-	ItemType             PublicLink_ItemType `protobuf:"varint,8,opt,name=item_type,json=itemType,proto3,enum=api.PublicLink_ItemType" json:"item_type,omitempty"`
-	OwnerId              string              `protobuf:"bytes,9,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
-	Name                 string              `protobuf:"bytes,10,opt,name=name,proto3" json:"name,omitempty"`
-	DropOnly             bool                `protobuf:"varint,11,opt,name=drop_only,json=dropOnly,proto3" json:"drop_only,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+type LinkPermissions struct {
+	Read                 bool     `protobuf:"varint,1,opt,name=read,proto3" json:"read,omitempty"`
+	Write                bool     `protobuf:"varint,2,opt,name=write,proto3" json:"write,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PublicLink) Reset()         { *m = PublicLink{} }
-func (m *PublicLink) String() string { return proto.CompactTextString(m) }
-func (*PublicLink) ProtoMessage()    {}
-func (*PublicLink) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{41}
+func (m *LinkPermissions) Reset()         { *m = LinkPermissions{} }
+func (m *LinkPermissions) String() string { return proto.CompactTextString(m) }
+func (*LinkPermissions) ProtoMessage()    {}
+func (*LinkPermissions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{43}
 }
 
-func (m *PublicLink) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PublicLink.Unmarshal(m, b)
+func (m *LinkPermissions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LinkPermissions.Unmarshal(m, b)
 }
-func (m *PublicLink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PublicLink.Marshal(b, m, deterministic)
+func (m *LinkPermissions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LinkPermissions.Marshal(b, m, deterministic)
 }
-func (m *PublicLink) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PublicLink.Merge(m, src)
+func (m *LinkPermissions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinkPermissions.Merge(m, src)
 }
-func (m *PublicLink) XXX_Size() int {
-	return xxx_messageInfo_PublicLink.Size(m)
+func (m *LinkPermissions) XXX_Size() int {
+	return xxx_messageInfo_LinkPermissions.Size(m)
 }
-func (m *PublicLink) XXX_DiscardUnknown() {
-	xxx_messageInfo_PublicLink.DiscardUnknown(m)
+func (m *LinkPermissions) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinkPermissions.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PublicLink proto.InternalMessageInfo
+var xxx_messageInfo_LinkPermissions proto.InternalMessageInfo
 
-func (m *PublicLink) GetId() string {
+func (m *LinkPermissions) GetRead() bool {
 	if m != nil {
-		return m.Id
+		return m.Read
 	}
-	return ""
+	return false
 }
 
-func (m *PublicLink) GetToken() string {
+func (m *LinkPermissions) GetWrite() bool {
 	if m != nil {
-		return m.Token
+		return m.Write
 	}
-	return ""
+	return false
 }
 
-func (m *PublicLink) GetPath() string {
-	if m != nil {
-		return m.Path
-	}
-	return ""
+type NewLinkReq struct {
+	Path         string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ReadOnly     bool   `protobuf:"varint,2,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Password     string `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Expires      uint64 `protobuf:"varint,4,opt,name=expires,proto3" json:"expires,omitempty"`
+	DropOnly     bool   `protobuf:"varint,5,opt,name=drop_only,json=dropOnly,proto3" json:"drop_only,omitempty"`
+	Token        string `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`
+	MaxDownloads uint64 `protobuf:"varint,7,opt,name=max_downloads,json=maxDownloads,proto3" json:"max_downloads,omitempty"`
+	MaxVisitors  uint64 `protobuf:"varint,8,opt,name=max_visitors,json=maxVisitors,proto3" json:"max_visitors,omitempty"`
+	Name         string `protobuf:"bytes,9,opt,name=name,proto3" json:"name,omitempty"`
+	// e-mail addresses the link is sent to once created
+	Notify               []string      `protobuf:"bytes,10,rep,name=notify,proto3" json:"notify,omitempty"`
+	UploadPolicy         *UploadPolicy `protobuf:"bytes,11,opt,name=upload_policy,json=uploadPolicy,proto3" json:"upload_policy,omitempty"`
+	Labels               []string      `protobuf:"bytes,12,rep,name=labels,proto3" json:"labels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *PublicLink) GetProtected() bool {
-	if m != nil {
-		return m.Protected
-	}
-	return false
+func (m *NewLinkReq) Reset()         { *m = NewLinkReq{} }
+func (m *NewLinkReq) String() string { return proto.CompactTextString(m) }
+func (*NewLinkReq) ProtoMessage()    {}
+func (*NewLinkReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{44}
 }
 
-func (m *PublicLink) GetExpires() uint64 {
-	if m != nil {
-		return m.Expires
-	}
-	return 0
+func (m *NewLinkReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewLinkReq.Unmarshal(m, b)
 }
-
-func (m *PublicLink) GetReadOnly() bool {
-	if m != nil {
-		return m.ReadOnly
-	}
-	return false
+func (m *NewLinkReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewLinkReq.Marshal(b, m, deterministic)
+}
+func (m *NewLinkReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewLinkReq.Merge(m, src)
+}
+func (m *NewLinkReq) XXX_Size() int {
+	return xxx_messageInfo_NewLinkReq.Size(m)
+}
+func (m *NewLinkReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewLinkReq.DiscardUnknown(m)
 }
 
-func (m *PublicLink) GetMtime() uint64 {
+var xxx_messageInfo_NewLinkReq proto.InternalMessageInfo
+
+func (m *NewLinkReq) GetPath() string {
 	if m != nil {
-		return m.Mtime
+		return m.Path
 	}
-	return 0
+	return ""
 }
 
-func (m *PublicLink) GetItemType() PublicLink_ItemType {
+func (m *NewLinkReq) GetReadOnly() bool {
 	if m != nil {
-		return m.ItemType
+		return m.ReadOnly
 	}
-	return PublicLink_FILE
+	return false
 }
 
-func (m *PublicLink) GetOwnerId() string {
+func (m *NewLinkReq) GetPassword() string {
 	if m != nil {
-		return m.OwnerId
+		return m.Password
 	}
 	return ""
 }
 
-func (m *PublicLink) GetName() string {
+func (m *NewLinkReq) GetExpires() uint64 {
 	if m != nil {
-		return m.Name
+		return m.Expires
 	}
-	return ""
+	return 0
 }
 
-func (m *PublicLink) GetDropOnly() bool {
+func (m *NewLinkReq) GetDropOnly() bool {
 	if m != nil {
 		return m.DropOnly
 	}
 	return false
 }
 
-type PublicLinkTokenReq struct {
-	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *PublicLinkTokenReq) Reset()         { *m = PublicLinkTokenReq{} }
-func (m *PublicLinkTokenReq) String() string { return proto.CompactTextString(m) }
-func (*PublicLinkTokenReq) ProtoMessage()    {}
-func (*PublicLinkTokenReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{42}
-}
-
-func (m *PublicLinkTokenReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PublicLinkTokenReq.Unmarshal(m, b)
-}
-func (m *PublicLinkTokenReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PublicLinkTokenReq.Marshal(b, m, deterministic)
-}
-func (m *PublicLinkTokenReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PublicLinkTokenReq.Merge(m, src)
-}
-func (m *PublicLinkTokenReq) XXX_Size() int {
-	return xxx_messageInfo_PublicLinkTokenReq.Size(m)
-}
-func (m *PublicLinkTokenReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_PublicLinkTokenReq.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_PublicLinkTokenReq proto.InternalMessageInfo
-
-func (m *PublicLinkTokenReq) GetToken() string {
+func (m *NewLinkReq) GetToken() string {
 	if m != nil {
 		return m.Token
 	}
 	return ""
 }
 
-type ShareIDReq struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *ShareIDReq) Reset()         { *m = ShareIDReq{} }
-func (m *ShareIDReq) String() string { return proto.CompactTextString(m) }
-func (*ShareIDReq) ProtoMessage()    {}
-func (*ShareIDReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{43}
+func (m *NewLinkReq) GetMaxDownloads() uint64 {
+	if m != nil {
+		return m.MaxDownloads
+	}
+	return 0
 }
 
-func (m *ShareIDReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ShareIDReq.Unmarshal(m, b)
-}
-func (m *ShareIDReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ShareIDReq.Marshal(b, m, deterministic)
-}
-func (m *ShareIDReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShareIDReq.Merge(m, src)
-}
-func (m *ShareIDReq) XXX_Size() int {
-	return xxx_messageInfo_ShareIDReq.Size(m)
-}
-func (m *ShareIDReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_ShareIDReq.DiscardUnknown(m)
+func (m *NewLinkReq) GetMaxVisitors() uint64 {
+	if m != nil {
+		return m.MaxVisitors
+	}
+	return 0
 }
 
-var xxx_messageInfo_ShareIDReq proto.InternalMessageInfo
-
-func (m *ShareIDReq) GetId() string {
+func (m *NewLinkReq) GetName() string {
 	if m != nil {
-		return m.Id
+		return m.Name
 	}
 	return ""
 }
 
-type FolderShareResponse struct {
-	Status               StatusCode   `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	FolderShare          *FolderShare `protobuf:"bytes,2,opt,name=folderShare,proto3" json:"folderShare,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
-}
-
-func (m *FolderShareResponse) Reset()         { *m = FolderShareResponse{} }
-func (m *FolderShareResponse) String() string { return proto.CompactTextString(m) }
-func (*FolderShareResponse) ProtoMessage()    {}
-func (*FolderShareResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{44}
-}
-
-func (m *FolderShareResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_FolderShareResponse.Unmarshal(m, b)
-}
-func (m *FolderShareResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_FolderShareResponse.Marshal(b, m, deterministic)
-}
-func (m *FolderShareResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FolderShareResponse.Merge(m, src)
-}
-func (m *FolderShareResponse) XXX_Size() int {
-	return xxx_messageInfo_FolderShareResponse.Size(m)
-}
-func (m *FolderShareResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_FolderShareResponse.DiscardUnknown(m)
+func (m *NewLinkReq) GetNotify() []string {
+	if m != nil {
+		return m.Notify
+	}
+	return nil
 }
 
-var xxx_messageInfo_FolderShareResponse proto.InternalMessageInfo
-
-func (m *FolderShareResponse) GetStatus() StatusCode {
+func (m *NewLinkReq) GetUploadPolicy() *UploadPolicy {
 	if m != nil {
-		return m.Status
+		return m.UploadPolicy
 	}
-	return StatusCode_OK
+	return nil
 }
 
-func (m *FolderShareResponse) GetFolderShare() *FolderShare {
+func (m *NewLinkReq) GetLabels() []string {
 	if m != nil {
-		return m.FolderShare
+		return m.Labels
 	}
 	return nil
 }
 
-type FolderShare struct {
-	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Path                 string            `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	OwnerId              string            `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
-	Recipient            *ShareRecipient   `protobuf:"bytes,4,opt,name=recipient,proto3" json:"recipient,omitempty"`
-	ReadOnly             bool              `protobuf:"varint,5,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
-	Ctime                uint64            `protobuf:"varint,6,opt,name=ctime,proto3" json:"ctime,omitempty"`
-	Mtime                uint64            `protobuf:"varint,7,opt,name=mtime,proto3" json:"mtime,omitempty"`
-	Target               string            `protobuf:"bytes,8,opt,name=target,proto3" json:"target,omitempty"`
-	State                FolderShare_State `protobuf:"varint,9,opt,name=state,proto3,enum=api.FolderShare_State" json:"state,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+type UpdateLinkReq struct {
+	Id                   string        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UpdatePassword       bool          `protobuf:"varint,2,opt,name=update_password,json=updatePassword,proto3" json:"update_password,omitempty"`
+	Password             string        `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	UpdateExpiration     bool          `protobuf:"varint,4,opt,name=update_expiration,json=updateExpiration,proto3" json:"update_expiration,omitempty"`
+	Expiration           uint64        `protobuf:"varint,5,opt,name=expiration,proto3" json:"expiration,omitempty"`
+	ReadOnly             bool          `protobuf:"varint,6,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	UpdateReadOnly       bool          `protobuf:"varint,7,opt,name=update_read_only,json=updateReadOnly,proto3" json:"update_read_only,omitempty"`
+	DropOnly             bool          `protobuf:"varint,8,opt,name=drop_only,json=dropOnly,proto3" json:"drop_only,omitempty"`
+	UpdateDropOnly       bool          `protobuf:"varint,9,opt,name=update_drop_only,json=updateDropOnly,proto3" json:"update_drop_only,omitempty"`
+	UpdateMaxDownloads   bool          `protobuf:"varint,10,opt,name=update_max_downloads,json=updateMaxDownloads,proto3" json:"update_max_downloads,omitempty"`
+	MaxDownloads         uint64        `protobuf:"varint,11,opt,name=max_downloads,json=maxDownloads,proto3" json:"max_downloads,omitempty"`
+	UpdateMaxVisitors    bool          `protobuf:"varint,12,opt,name=update_max_visitors,json=updateMaxVisitors,proto3" json:"update_max_visitors,omitempty"`
+	MaxVisitors          uint64        `protobuf:"varint,13,opt,name=max_visitors,json=maxVisitors,proto3" json:"max_visitors,omitempty"`
+	UpdateName           bool          `protobuf:"varint,14,opt,name=update_name,json=updateName,proto3" json:"update_name,omitempty"`
+	Name                 string        `protobuf:"bytes,15,opt,name=name,proto3" json:"name,omitempty"`
+	UpdateUploadPolicy   bool          `protobuf:"varint,16,opt,name=update_upload_policy,json=updateUploadPolicy,proto3" json:"update_upload_policy,omitempty"`
+	UploadPolicy         *UploadPolicy `protobuf:"bytes,17,opt,name=upload_policy,json=uploadPolicy,proto3" json:"upload_policy,omitempty"`
+	UpdateLabels         bool          `protobuf:"varint,18,opt,name=update_labels,json=updateLabels,proto3" json:"update_labels,omitempty"`
+	Labels               []string      `protobuf:"bytes,19,rep,name=labels,proto3" json:"labels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *FolderShare) Reset()         { *m = FolderShare{} }
-func (m *FolderShare) String() string { return proto.CompactTextString(m) }
-func (*FolderShare) ProtoMessage()    {}
-func (*FolderShare) Descriptor() ([]byte, []int) {
+func (m *UpdateLinkReq) Reset()         { *m = UpdateLinkReq{} }
+func (m *UpdateLinkReq) String() string { return proto.CompactTextString(m) }
+func (*UpdateLinkReq) ProtoMessage()    {}
+func (*UpdateLinkReq) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{45}
 }
 
-func (m *FolderShare) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_FolderShare.Unmarshal(m, b)
+func (m *UpdateLinkReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateLinkReq.Unmarshal(m, b)
 }
-func (m *FolderShare) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_FolderShare.Marshal(b, m, deterministic)
+func (m *UpdateLinkReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateLinkReq.Marshal(b, m, deterministic)
 }
-func (m *FolderShare) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FolderShare.Merge(m, src)
+func (m *UpdateLinkReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateLinkReq.Merge(m, src)
 }
-func (m *FolderShare) XXX_Size() int {
-	return xxx_messageInfo_FolderShare.Size(m)
+func (m *UpdateLinkReq) XXX_Size() int {
+	return xxx_messageInfo_UpdateLinkReq.Size(m)
 }
-func (m *FolderShare) XXX_DiscardUnknown() {
-	xxx_messageInfo_FolderShare.DiscardUnknown(m)
+func (m *UpdateLinkReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateLinkReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_FolderShare proto.InternalMessageInfo
+var xxx_messageInfo_UpdateLinkReq proto.InternalMessageInfo
 
-func (m *FolderShare) GetId() string {
+func (m *UpdateLinkReq) GetId() string {
 	if m != nil {
 		return m.Id
 	}
 	return ""
 }
 
-func (m *FolderShare) GetPath() string {
+func (m *UpdateLinkReq) GetUpdatePassword() bool {
 	if m != nil {
-		return m.Path
+		return m.UpdatePassword
 	}
-	return ""
+	return false
 }
 
-func (m *FolderShare) GetOwnerId() string {
+func (m *UpdateLinkReq) GetPassword() string {
 	if m != nil {
-		return m.OwnerId
+		return m.Password
 	}
 	return ""
 }
 
-func (m *FolderShare) GetRecipient() *ShareRecipient {
-	if m != nil {
-		return m.Recipient
-	}
-	return nil
-}
-
-func (m *FolderShare) GetReadOnly() bool {
+func (m *UpdateLinkReq) GetUpdateExpiration() bool {
 	if m != nil {
-		return m.ReadOnly
+		return m.UpdateExpiration
 	}
 	return false
 }
 
-func (m *FolderShare) GetCtime() uint64 {
+func (m *UpdateLinkReq) GetExpiration() uint64 {
 	if m != nil {
-		return m.Ctime
+		return m.Expiration
 	}
 	return 0
 }
 
-func (m *FolderShare) GetMtime() uint64 {
+func (m *UpdateLinkReq) GetReadOnly() bool {
 	if m != nil {
-		return m.Mtime
+		return m.ReadOnly
 	}
-	return 0
+	return false
 }
 
-func (m *FolderShare) GetTarget() string {
+func (m *UpdateLinkReq) GetUpdateReadOnly() bool {
 	if m != nil {
-		return m.Target
+		return m.UpdateReadOnly
 	}
-	return ""
+	return false
 }
 
-func (m *FolderShare) GetState() FolderShare_State {
+func (m *UpdateLinkReq) GetDropOnly() bool {
 	if m != nil {
-		return m.State
+		return m.DropOnly
 	}
-	return FolderShare_ACCEPTED
-}
-
-type ReceivedShareResponse struct {
-	Status               StatusCode   `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
-	Share                *FolderShare `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	return false
 }
 
-func (m *ReceivedShareResponse) Reset()         { *m = ReceivedShareResponse{} }
-func (m *ReceivedShareResponse) String() string { return proto.CompactTextString(m) }
-func (*ReceivedShareResponse) ProtoMessage()    {}
-func (*ReceivedShareResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{46}
+func (m *UpdateLinkReq) GetUpdateDropOnly() bool {
+	if m != nil {
+		return m.UpdateDropOnly
+	}
+	return false
 }
 
-func (m *ReceivedShareResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ReceivedShareResponse.Unmarshal(m, b)
-}
-func (m *ReceivedShareResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ReceivedShareResponse.Marshal(b, m, deterministic)
-}
-func (m *ReceivedShareResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ReceivedShareResponse.Merge(m, src)
-}
-func (m *ReceivedShareResponse) XXX_Size() int {
-	return xxx_messageInfo_ReceivedShareResponse.Size(m)
-}
-func (m *ReceivedShareResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ReceivedShareResponse.DiscardUnknown(m)
+func (m *UpdateLinkReq) GetUpdateMaxDownloads() bool {
+	if m != nil {
+		return m.UpdateMaxDownloads
+	}
+	return false
 }
 
-var xxx_messageInfo_ReceivedShareResponse proto.InternalMessageInfo
-
-func (m *ReceivedShareResponse) GetStatus() StatusCode {
+func (m *UpdateLinkReq) GetMaxDownloads() uint64 {
 	if m != nil {
-		return m.Status
+		return m.MaxDownloads
 	}
-	return StatusCode_OK
+	return 0
 }
 
-func (m *ReceivedShareResponse) GetShare() *FolderShare {
+func (m *UpdateLinkReq) GetUpdateMaxVisitors() bool {
 	if m != nil {
-		return m.Share
+		return m.UpdateMaxVisitors
 	}
-	return nil
+	return false
 }
 
-type NewFolderShareReq struct {
-	Path                 string          `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Recipient            *ShareRecipient `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
-	ReadOnly             bool            `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+func (m *UpdateLinkReq) GetMaxVisitors() uint64 {
+	if m != nil {
+		return m.MaxVisitors
+	}
+	return 0
 }
 
-func (m *NewFolderShareReq) Reset()         { *m = NewFolderShareReq{} }
-func (m *NewFolderShareReq) String() string { return proto.CompactTextString(m) }
-func (*NewFolderShareReq) ProtoMessage()    {}
-func (*NewFolderShareReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{47}
+func (m *UpdateLinkReq) GetUpdateName() bool {
+	if m != nil {
+		return m.UpdateName
+	}
+	return false
 }
 
-func (m *NewFolderShareReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_NewFolderShareReq.Unmarshal(m, b)
-}
-func (m *NewFolderShareReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_NewFolderShareReq.Marshal(b, m, deterministic)
-}
-func (m *NewFolderShareReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NewFolderShareReq.Merge(m, src)
-}
-func (m *NewFolderShareReq) XXX_Size() int {
-	return xxx_messageInfo_NewFolderShareReq.Size(m)
-}
-func (m *NewFolderShareReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_NewFolderShareReq.DiscardUnknown(m)
+func (m *UpdateLinkReq) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
 }
 
-var xxx_messageInfo_NewFolderShareReq proto.InternalMessageInfo
-
-func (m *NewFolderShareReq) GetPath() string {
+func (m *UpdateLinkReq) GetUpdateUploadPolicy() bool {
 	if m != nil {
-		return m.Path
+		return m.UpdateUploadPolicy
 	}
-	return ""
+	return false
 }
 
-func (m *NewFolderShareReq) GetRecipient() *ShareRecipient {
+func (m *UpdateLinkReq) GetUploadPolicy() *UploadPolicy {
 	if m != nil {
-		return m.Recipient
+		return m.UploadPolicy
 	}
 	return nil
 }
 
-func (m *NewFolderShareReq) GetReadOnly() bool {
+func (m *UpdateLinkReq) GetUpdateLabels() bool {
 	if m != nil {
-		return m.ReadOnly
+		return m.UpdateLabels
 	}
 	return false
 }
 
-type UpdateFolderShareReq struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UpdateReadOnly       bool     `protobuf:"varint,2,opt,name=update_read_only,json=updateReadOnly,proto3" json:"update_read_only,omitempty"`
-	ReadOnly             bool     `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+func (m *UpdateLinkReq) GetLabels() []string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+// UploadPolicy restricts what can be uploaded to a drop only link, zero values mean unrestricted.
+type UploadPolicy struct {
+	MaxFileSize  uint64 `protobuf:"varint,1,opt,name=max_file_size,json=maxFileSize,proto3" json:"max_file_size,omitempty"`
+	MaxTotalSize uint64 `protobuf:"varint,2,opt,name=max_total_size,json=maxTotalSize,proto3" json:"max_total_size,omitempty"`
+	// extensions like .pdf and mime types like application/pdf or image/*
+	AllowedTypes []string `protobuf:"bytes,3,rep,name=allowed_types,json=allowedTypes,proto3" json:"allowed_types,omitempty"`
+	// puts the uploads in a folder named after the name the uploader enters
+	UploaderFolders bool `protobuf:"varint,4,opt,name=uploader_folders,json=uploaderFolders,proto3" json:"uploader_folders,omitempty"`
+	// e-mails the owner a periodic digest of the new uploads
+	Digest               bool     `protobuf:"varint,5,opt,name=digest,proto3" json:"digest,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateFolderShareReq) Reset()         { *m = UpdateFolderShareReq{} }
-func (m *UpdateFolderShareReq) String() string { return proto.CompactTextString(m) }
-func (*UpdateFolderShareReq) ProtoMessage()    {}
-func (*UpdateFolderShareReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{48}
+func (m *UploadPolicy) Reset()         { *m = UploadPolicy{} }
+func (m *UploadPolicy) String() string { return proto.CompactTextString(m) }
+func (*UploadPolicy) ProtoMessage()    {}
+func (*UploadPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{46}
 }
 
-func (m *UpdateFolderShareReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateFolderShareReq.Unmarshal(m, b)
+func (m *UploadPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UploadPolicy.Unmarshal(m, b)
 }
-func (m *UpdateFolderShareReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateFolderShareReq.Marshal(b, m, deterministic)
+func (m *UploadPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UploadPolicy.Marshal(b, m, deterministic)
 }
-func (m *UpdateFolderShareReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateFolderShareReq.Merge(m, src)
+func (m *UploadPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UploadPolicy.Merge(m, src)
 }
-func (m *UpdateFolderShareReq) XXX_Size() int {
-	return xxx_messageInfo_UpdateFolderShareReq.Size(m)
+func (m *UploadPolicy) XXX_Size() int {
+	return xxx_messageInfo_UploadPolicy.Size(m)
 }
-func (m *UpdateFolderShareReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateFolderShareReq.DiscardUnknown(m)
+func (m *UploadPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_UploadPolicy.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UpdateFolderShareReq proto.InternalMessageInfo
+var xxx_messageInfo_UploadPolicy proto.InternalMessageInfo
 
-func (m *UpdateFolderShareReq) GetId() string {
+func (m *UploadPolicy) GetMaxFileSize() uint64 {
 	if m != nil {
-		return m.Id
+		return m.MaxFileSize
 	}
-	return ""
+	return 0
 }
 
-func (m *UpdateFolderShareReq) GetUpdateReadOnly() bool {
+func (m *UploadPolicy) GetMaxTotalSize() uint64 {
 	if m != nil {
-		return m.UpdateReadOnly
+		return m.MaxTotalSize
+	}
+	return 0
+}
+
+func (m *UploadPolicy) GetAllowedTypes() []string {
+	if m != nil {
+		return m.AllowedTypes
+	}
+	return nil
+}
+
+func (m *UploadPolicy) GetUploaderFolders() bool {
+	if m != nil {
+		return m.UploaderFolders
 	}
 	return false
 }
 
-func (m *UpdateFolderShareReq) GetReadOnly() bool {
+func (m *UploadPolicy) GetDigest() bool {
 	if m != nil {
-		return m.ReadOnly
+		return m.Digest
 	}
 	return false
 }
 
-type UnshareFolderReq struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type PublicLinkResponse struct {
+	Status               StatusCode  `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	PublicLink           *PublicLink `protobuf:"bytes,2,opt,name=publicLink,proto3" json:"publicLink,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
-func (m *UnshareFolderReq) Reset()         { *m = UnshareFolderReq{} }
-func (m *UnshareFolderReq) String() string { return proto.CompactTextString(m) }
-func (*UnshareFolderReq) ProtoMessage()    {}
-func (*UnshareFolderReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{49}
+func (m *PublicLinkResponse) Reset()         { *m = PublicLinkResponse{} }
+func (m *PublicLinkResponse) String() string { return proto.CompactTextString(m) }
+func (*PublicLinkResponse) ProtoMessage()    {}
+func (*PublicLinkResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{47}
 }
 
-func (m *UnshareFolderReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UnshareFolderReq.Unmarshal(m, b)
+func (m *PublicLinkResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PublicLinkResponse.Unmarshal(m, b)
 }
-func (m *UnshareFolderReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UnshareFolderReq.Marshal(b, m, deterministic)
+func (m *PublicLinkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PublicLinkResponse.Marshal(b, m, deterministic)
 }
-func (m *UnshareFolderReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UnshareFolderReq.Merge(m, src)
+func (m *PublicLinkResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PublicLinkResponse.Merge(m, src)
 }
-func (m *UnshareFolderReq) XXX_Size() int {
-	return xxx_messageInfo_UnshareFolderReq.Size(m)
+func (m *PublicLinkResponse) XXX_Size() int {
+	return xxx_messageInfo_PublicLinkResponse.Size(m)
 }
-func (m *UnshareFolderReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_UnshareFolderReq.DiscardUnknown(m)
+func (m *PublicLinkResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PublicLinkResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UnshareFolderReq proto.InternalMessageInfo
+var xxx_messageInfo_PublicLinkResponse proto.InternalMessageInfo
 
-func (m *UnshareFolderReq) GetId() string {
+func (m *PublicLinkResponse) GetStatus() StatusCode {
 	if m != nil {
-		return m.Id
+		return m.Status
 	}
-	return ""
+	return StatusCode_OK
 }
 
-type ListPublicLinksReq struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *PublicLinkResponse) GetPublicLink() *PublicLink {
+	if m != nil {
+		return m.PublicLink
+	}
+	return nil
 }
 
-func (m *ListPublicLinksReq) Reset()         { *m = ListPublicLinksReq{} }
-func (m *ListPublicLinksReq) String() string { return proto.CompactTextString(m) }
-func (*ListPublicLinksReq) ProtoMessage()    {}
-func (*ListPublicLinksReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{50}
+type ShareRecipient struct {
+	Identity             string                       `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+	Type                 ShareRecipient_RecipientType `protobuf:"varint,2,opt,name=type,proto3,enum=api.ShareRecipient_RecipientType" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
 }
 
-func (m *ListPublicLinksReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListPublicLinksReq.Unmarshal(m, b)
+func (m *ShareRecipient) Reset()         { *m = ShareRecipient{} }
+func (m *ShareRecipient) String() string { return proto.CompactTextString(m) }
+func (*ShareRecipient) ProtoMessage()    {}
+func (*ShareRecipient) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{48}
 }
-func (m *ListPublicLinksReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListPublicLinksReq.Marshal(b, m, deterministic)
+
+func (m *ShareRecipient) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShareRecipient.Unmarshal(m, b)
 }
-func (m *ListPublicLinksReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListPublicLinksReq.Merge(m, src)
+func (m *ShareRecipient) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShareRecipient.Marshal(b, m, deterministic)
 }
-func (m *ListPublicLinksReq) XXX_Size() int {
-	return xxx_messageInfo_ListPublicLinksReq.Size(m)
+func (m *ShareRecipient) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShareRecipient.Merge(m, src)
 }
-func (m *ListPublicLinksReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListPublicLinksReq.DiscardUnknown(m)
+func (m *ShareRecipient) XXX_Size() int {
+	return xxx_messageInfo_ShareRecipient.Size(m)
+}
+func (m *ShareRecipient) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShareRecipient.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ListPublicLinksReq proto.InternalMessageInfo
+var xxx_messageInfo_ShareRecipient proto.InternalMessageInfo
 
-func (m *ListPublicLinksReq) GetPath() string {
+func (m *ShareRecipient) GetIdentity() string {
 	if m != nil {
-		return m.Path
+		return m.Identity
 	}
 	return ""
 }
 
-type ListFolderSharesReq struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *ShareRecipient) GetType() ShareRecipient_RecipientType {
+	if m != nil {
+		return m.Type
+	}
+	return ShareRecipient_USER
 }
 
-func (m *ListFolderSharesReq) Reset()         { *m = ListFolderSharesReq{} }
-func (m *ListFolderSharesReq) String() string { return proto.CompactTextString(m) }
-func (*ListFolderSharesReq) ProtoMessage()    {}
-func (*ListFolderSharesReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{51}
+type ACLReq struct {
+	Path                 string          `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Recipient            *ShareRecipient `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	ReadOnly             bool            `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Shares               []*FolderShare  `protobuf:"bytes,4,rep,name=shares,proto3" json:"shares,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func (m *ListFolderSharesReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListFolderSharesReq.Unmarshal(m, b)
+func (m *ACLReq) Reset()         { *m = ACLReq{} }
+func (m *ACLReq) String() string { return proto.CompactTextString(m) }
+func (*ACLReq) ProtoMessage()    {}
+func (*ACLReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{49}
 }
-func (m *ListFolderSharesReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListFolderSharesReq.Marshal(b, m, deterministic)
+
+func (m *ACLReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ACLReq.Unmarshal(m, b)
 }
-func (m *ListFolderSharesReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListFolderSharesReq.Merge(m, src)
+func (m *ACLReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ACLReq.Marshal(b, m, deterministic)
 }
-func (m *ListFolderSharesReq) XXX_Size() int {
-	return xxx_messageInfo_ListFolderSharesReq.Size(m)
+func (m *ACLReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ACLReq.Merge(m, src)
 }
-func (m *ListFolderSharesReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListFolderSharesReq.DiscardUnknown(m)
+func (m *ACLReq) XXX_Size() int {
+	return xxx_messageInfo_ACLReq.Size(m)
+}
+func (m *ACLReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ACLReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ListFolderSharesReq proto.InternalMessageInfo
+var xxx_messageInfo_ACLReq proto.InternalMessageInfo
 
-func (m *ListFolderSharesReq) GetPath() string {
+func (m *ACLReq) GetPath() string {
 	if m != nil {
 		return m.Path
 	}
 	return ""
 }
 
-type ReceivedShareReq struct {
-	ShareId              string   `protobuf:"bytes,1,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *ACLReq) GetRecipient() *ShareRecipient {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
 }
 
-func (m *ReceivedShareReq) Reset()         { *m = ReceivedShareReq{} }
-func (m *ReceivedShareReq) String() string { return proto.CompactTextString(m) }
-func (*ReceivedShareReq) ProtoMessage()    {}
-func (*ReceivedShareReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{52}
+func (m *ACLReq) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
 }
 
-func (m *ReceivedShareReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ReceivedShareReq.Unmarshal(m, b)
+func (m *ACLReq) GetShares() []*FolderShare {
+	if m != nil {
+		return m.Shares
+	}
+	return nil
 }
-func (m *ReceivedShareReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ReceivedShareReq.Marshal(b, m, deterministic)
+
+type ACLEntry struct {
+	Recipient            *ShareRecipient `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	ReadOnly             bool            `protobuf:"varint,2,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
-func (m *ReceivedShareReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ReceivedShareReq.Merge(m, src)
+
+func (m *ACLEntry) Reset()         { *m = ACLEntry{} }
+func (m *ACLEntry) String() string { return proto.CompactTextString(m) }
+func (*ACLEntry) ProtoMessage()    {}
+func (*ACLEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{50}
 }
-func (m *ReceivedShareReq) XXX_Size() int {
-	return xxx_messageInfo_ReceivedShareReq.Size(m)
+
+func (m *ACLEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ACLEntry.Unmarshal(m, b)
 }
-func (m *ReceivedShareReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_ReceivedShareReq.DiscardUnknown(m)
+func (m *ACLEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ACLEntry.Marshal(b, m, deterministic)
+}
+func (m *ACLEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ACLEntry.Merge(m, src)
+}
+func (m *ACLEntry) XXX_Size() int {
+	return xxx_messageInfo_ACLEntry.Size(m)
+}
+func (m *ACLEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_ACLEntry.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ReceivedShareReq proto.InternalMessageInfo
+var xxx_messageInfo_ACLEntry proto.InternalMessageInfo
 
-func (m *ReceivedShareReq) GetShareId() string {
+func (m *ACLEntry) GetRecipient() *ShareRecipient {
 	if m != nil {
-		return m.ShareId
+		return m.Recipient
 	}
-	return ""
+	return nil
 }
 
-func init() {
-	proto.RegisterEnum("api.StatusCode", StatusCode_name, StatusCode_value)
-	proto.RegisterEnum("api.Tag_ItemType", Tag_ItemType_name, Tag_ItemType_value)
-	proto.RegisterEnum("api.ShareRecipient_RecipientType", ShareRecipient_RecipientType_name, ShareRecipient_RecipientType_value)
-	proto.RegisterEnum("api.PublicLink_ItemType", PublicLink_ItemType_name, PublicLink_ItemType_value)
-	proto.RegisterEnum("api.FolderShare_State", FolderShare_State_name, FolderShare_State_value)
-	proto.RegisterType((*TagReq)(nil), "api.TagReq")
-	proto.RegisterType((*Tag)(nil), "api.Tag")
-	proto.RegisterType((*TagResponse)(nil), "api.TagResponse")
-	proto.RegisterType((*IsPublicLinkProtectedResponse)(nil), "api.IsPublicLinkProtectedResponse")
-	proto.RegisterType((*ForgePublicLinkTokenReq)(nil), "api.ForgePublicLinkTokenReq")
-	proto.RegisterType((*ForgePublicLinkTokenResponse)(nil), "api.ForgePublicLinkTokenResponse")
-	proto.RegisterType((*VerifyPublicLinkTokenReq)(nil), "api.VerifyPublicLinkTokenReq")
-	proto.RegisterType((*VerifyPublicLinkTokenResponse)(nil), "api.VerifyPublicLinkTokenResponse")
-	proto.RegisterType((*EmptyResponse)(nil), "api.EmptyResponse")
-	proto.RegisterType((*EmptyReq)(nil), "api.EmptyReq")
-	proto.RegisterType((*QuotaReq)(nil), "api.QuotaReq")
-	proto.RegisterType((*QuotaResponse)(nil), "api.QuotaResponse")
-	proto.RegisterType((*UserResponse)(nil), "api.UserResponse")
-	proto.RegisterType((*User)(nil), "api.User")
-	proto.RegisterType((*TxInfoResponse)(nil), "api.TxInfoResponse")
-	proto.RegisterType((*TxInfo)(nil), "api.TxInfo")
-	proto.RegisterType((*ForgeUserTokenReq)(nil), "api.ForgeUserTokenReq")
-	proto.RegisterType((*TokenResponse)(nil), "api.TokenResponse")
-	proto.RegisterType((*TokenReq)(nil), "api.TokenReq")
-	proto.RegisterType((*MetadataResponse)(nil), "api.MetadataResponse")
-	proto.RegisterType((*Metadata)(nil), "api.Metadata")
-	proto.RegisterType((*PathReq)(nil), "api.PathReq")
-	proto.RegisterType((*MoveReq)(nil), "api.MoveReq")
-	proto.RegisterType((*TxChunk)(nil), "api.TxChunk")
-	proto.RegisterType((*WriteSummaryResponse)(nil), "api.WriteSummaryResponse")
-	proto.RegisterType((*WriteSummary)(nil), "api.WriteSummary")
-	proto.RegisterType((*TxEnd)(nil), "api.TxEnd")
-	proto.RegisterType((*DataChunkResponse)(nil), "api.DataChunkResponse")
-	proto.RegisterType((*DataChunk)(nil), "api.DataChunk")
-	proto.RegisterType((*RevisionResponse)(nil), "api.RevisionResponse")
-	proto.RegisterType((*Revision)(nil), "api.Revision")
-	proto.RegisterType((*RevisionReq)(nil), "api.RevisionReq")
-	proto.RegisterType((*RecycleEntryResponse)(nil), "api.RecycleEntryResponse")
-	proto.RegisterType((*RecycleEntry)(nil), "api.RecycleEntry")
-	proto.RegisterType((*RecycleEntryReq)(nil), "api.RecycleEntryReq")
-	proto.RegisterType((*LinkPermissions)(nil), "api.LinkPermissions")
-	proto.RegisterType((*NewLinkReq)(nil), "api.NewLinkReq")
-	proto.RegisterType((*UpdateLinkReq)(nil), "api.UpdateLinkReq")
-	proto.RegisterType((*PublicLinkResponse)(nil), "api.PublicLinkResponse")
-	proto.RegisterType((*ShareRecipient)(nil), "api.ShareRecipient")
-	proto.RegisterType((*ACLReq)(nil), "api.ACLReq")
-	proto.RegisterType((*PublicLink)(nil), "api.PublicLink")
-	proto.RegisterType((*PublicLinkTokenReq)(nil), "api.PublicLinkTokenReq")
-	proto.RegisterType((*ShareIDReq)(nil), "api.ShareIDReq")
-	proto.RegisterType((*FolderShareResponse)(nil), "api.FolderShareResponse")
-	proto.RegisterType((*FolderShare)(nil), "api.FolderShare")
-	proto.RegisterType((*ReceivedShareResponse)(nil), "api.ReceivedShareResponse")
-	proto.RegisterType((*NewFolderShareReq)(nil), "api.NewFolderShareReq")
-	proto.RegisterType((*UpdateFolderShareReq)(nil), "api.UpdateFolderShareReq")
-	proto.RegisterType((*UnshareFolderReq)(nil), "api.UnshareFolderReq")
-	proto.RegisterType((*ListPublicLinksReq)(nil), "api.ListPublicLinksReq")
-	proto.RegisterType((*ListFolderSharesReq)(nil), "api.ListFolderSharesReq")
-	proto.RegisterType((*ReceivedShareReq)(nil), "api.ReceivedShareReq")
+func (m *ACLEntry) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
 }
 
-func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
+type PublicLink struct {
+	Id                   string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Token                string              `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	Path                 string              `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Protected            bool                `protobuf:"varint,4,opt,name=protected,proto3" json:"protected,omitempty"`
+	Expires              uint64              `protobuf:"varint,5,opt,name=expires,proto3" json:"expires,omitempty"`
+	ReadOnly             bool                `protobuf:"varint,6,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Mtime                uint64              `protobuf:"varint,7,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	ItemType             PublicLink_ItemType `protobuf:"varint,8,opt,name=item_type,json=itemType,proto3,enum=api.PublicLink_ItemType" json:"item_type,omitempty"`
+	OwnerId              string              `protobuf:"bytes,9,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Name                 string              `protobuf:"bytes,10,opt,name=name,proto3" json:"name,omitempty"`
+	DropOnly             bool                `protobuf:"varint,11,opt,name=drop_only,json=dropOnly,proto3" json:"drop_only,omitempty"`
+	MaxDownloads         uint64              `protobuf:"varint,12,opt,name=max_downloads,json=maxDownloads,proto3" json:"max_downloads,omitempty"`
+	MaxVisitors          uint64              `protobuf:"varint,13,opt,name=max_visitors,json=maxVisitors,proto3" json:"max_visitors,omitempty"`
+	Downloads            uint64              `protobuf:"varint,14,opt,name=downloads,proto3" json:"downloads,omitempty"`
+	Uploads              uint64              `protobuf:"varint,15,opt,name=uploads,proto3" json:"uploads,omitempty"`
+	Visitors             uint64              `protobuf:"varint,16,opt,name=visitors,proto3" json:"visitors,omitempty"`
+	LastAccess           uint64              `protobuf:"varint,17,opt,name=last_access,json=lastAccess,proto3" json:"last_access,omitempty"`
+	Version              string              `protobuf:"bytes,18,opt,name=version,proto3" json:"version,omitempty"`
+	UploadPolicy         *UploadPolicy       `protobuf:"bytes,19,opt,name=upload_policy,json=uploadPolicy,proto3" json:"upload_policy,omitempty"`
+	UploadedBytes        uint64              `protobuf:"varint,20,opt,name=uploaded_bytes,json=uploadedBytes,proto3" json:"uploaded_bytes,omitempty"`
+	Labels               []string            `protobuf:"bytes,21,rep,name=labels,proto3" json:"labels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
 
-var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 2744 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x3a, 0x5b, 0x73, 0xdb, 0xc6,
-	0xd5, 0xe6, 0x1d, 0x3c, 0xbc, 0x18, 0x5a, 0xcb, 0x0e, 0x25, 0xdb, 0x89, 0x83, 0x7c, 0xf3, 0xc5,
-	0x49, 0x1c, 0xc5, 0x61, 0xe3, 0x69, 0x92, 0xb6, 0xc9, 0x30, 0x24, 0xa5, 0x30, 0x96, 0x48, 0x06,
-	0x24, 0x9d, 0xf4, 0xa1, 0x45, 0x61, 0x62, 0x45, 0x6d, 0x45, 0x02, 0x34, 0xb0, 0xd4, 0x25, 0xbf,
-	0xa2, 0x93, 0xce, 0xf4, 0xa1, 0x7f, 0xa6, 0xfd, 0x15, 0x9d, 0xce, 0xf4, 0x3f, 0xf4, 0xb5, 0x33,
-	0x7d, 0xea, 0xec, 0x05, 0xc4, 0x82, 0xb7, 0x88, 0xee, 0x4c, 0x9f, 0x84, 0x3d, 0x7b, 0xf6, 0xdc,
-	0xf6, 0x5c, 0x97, 0x82, 0xbc, 0x3d, 0x25, 0x07, 0x53, 0xdf, 0xa3, 0x1e, 0x4a, 0xd9, 0x53, 0x62,
-	0xb4, 0x21, 0xdb, 0xb7, 0x47, 0x26, 0x7e, 0x85, 0xde, 0x80, 0x1c, 0xb5, 0x47, 0xd6, 0x39, 0xbe,
-	0xae, 0x24, 0x1e, 0x25, 0x1e, 0xe7, 0xcd, 0x2c, 0xb5, 0x47, 0xcf, 0xf1, 0x75, 0xb8, 0x71, 0x61,
-	0x8f, 0x2b, 0xc9, 0xf9, 0xc6, 0x0b, 0x7b, 0x8c, 0x10, 0xa4, 0xa7, 0x36, 0x3d, 0xab, 0xa4, 0x38,
-	0x94, 0x7f, 0x1b, 0xff, 0x4c, 0x40, 0xaa, 0x6f, 0x8f, 0x50, 0x19, 0x92, 0xc4, 0xe1, 0x84, 0x52,
-	0x66, 0x92, 0x38, 0xe8, 0x00, 0xf2, 0x84, 0xe2, 0x89, 0x45, 0xaf, 0xa7, 0x98, 0x93, 0x29, 0x57,
-	0x77, 0x0e, 0x98, 0x2c, 0x7d, 0x7b, 0x74, 0xd0, 0xa2, 0x78, 0xd2, 0xbf, 0x9e, 0x62, 0x53, 0x23,
-	0xf2, 0x0b, 0xe9, 0x90, 0x9a, 0x11, 0x47, 0x92, 0x66, 0x9f, 0xe8, 0xff, 0xa0, 0x7c, 0x4a, 0xc6,
-	0xd8, 0x22, 0x8e, 0x35, 0xf5, 0xf1, 0x29, 0xb9, 0xaa, 0xa4, 0xf9, 0x66, 0x91, 0x41, 0x5b, 0x4e,
-	0x97, 0xc3, 0x98, 0xb0, 0x12, 0xab, 0x92, 0x11, 0xc2, 0x8a, 0x6d, 0x55, 0xbd, 0x6c, 0x4c, 0xbd,
-	0xfb, 0x90, 0x97, 0xea, 0xcd, 0x70, 0x25, 0xc7, 0xb7, 0x34, 0xa1, 0xe0, 0x0c, 0x1b, 0x8f, 0x40,
-	0x0b, 0x85, 0x43, 0x00, 0xd9, 0xc3, 0xce, 0x71, 0xa3, 0x69, 0xea, 0xb7, 0x90, 0x06, 0xe9, 0xc3,
-	0xd6, 0x71, 0x53, 0x4f, 0x18, 0x26, 0x14, 0xb8, 0x01, 0x83, 0xa9, 0xe7, 0x06, 0x18, 0xbd, 0x0b,
-	0xd9, 0x80, 0xda, 0x74, 0x16, 0x70, 0xdd, 0xcb, 0xd5, 0xdb, 0x5c, 0xc9, 0x1e, 0x07, 0xd5, 0x3d,
-	0x07, 0x9b, 0x72, 0x1b, 0xed, 0x43, 0x8a, 0xda, 0x23, 0x6e, 0x8a, 0x42, 0x55, 0x0b, 0x4d, 0x61,
-	0x32, 0xa0, 0x71, 0x0a, 0x0f, 0x5b, 0x41, 0x77, 0xf6, 0x72, 0x4c, 0x86, 0xc7, 0xc4, 0x3d, 0xef,
-	0xfa, 0x1e, 0xc5, 0x43, 0x8a, 0x9d, 0xed, 0xb9, 0x3c, 0x80, 0xfc, 0x34, 0x3c, 0xcd, 0x79, 0x69,
-	0x66, 0x04, 0x30, 0x9e, 0xc3, 0x1b, 0x87, 0x9e, 0x3f, 0xc2, 0x11, 0xab, 0xbe, 0x77, 0x8e, 0x5d,
-	0xe6, 0x0d, 0xbb, 0x90, 0xa1, 0xec, 0x5b, 0xfa, 0x82, 0x58, 0xa0, 0x7d, 0xd0, 0xa6, 0x76, 0x10,
-	0x5c, 0x7a, 0xbe, 0x23, 0x7d, 0x61, 0xbe, 0x36, 0x7e, 0x03, 0x0f, 0x56, 0x13, 0xdb, 0x56, 0xe6,
-	0x5d, 0xc8, 0x5c, 0xd8, 0x63, 0x12, 0xca, 0x2b, 0x16, 0xc6, 0x53, 0xa8, 0xbc, 0xc0, 0x3e, 0x39,
-	0xbd, 0xbe, 0xa9, 0xb0, 0xc6, 0x0f, 0xf0, 0x70, 0xcd, 0x89, 0x6d, 0x25, 0x7a, 0x0a, 0x85, 0x29,
-	0xa7, 0x61, 0x8d, 0x89, 0x7b, 0x2e, 0xef, 0x4c, 0x60, 0x47, 0xb4, 0x4d, 0x98, 0xce, 0xbf, 0x8d,
-	0x4f, 0xa1, 0xd4, 0x9c, 0x4c, 0xe9, 0xf5, 0xd6, 0xbc, 0x0c, 0x00, 0x4d, 0x9e, 0x7c, 0x65, 0xbc,
-	0x09, 0xda, 0xb7, 0x33, 0x8f, 0xda, 0x4c, 0xc7, 0x30, 0xd8, 0x12, 0x4a, 0xb0, 0x5d, 0x41, 0x49,
-	0xee, 0x6f, 0xab, 0xd1, 0x5b, 0x50, 0xa0, 0x1e, 0xb5, 0xc7, 0xd6, 0xcb, 0x6b, 0x8a, 0x03, 0xae,
-	0x51, 0xca, 0x04, 0x0e, 0xfa, 0x8a, 0x41, 0xd0, 0x43, 0x80, 0x59, 0x80, 0x1d, 0xb9, 0x9f, 0xe2,
-	0xfb, 0x79, 0x06, 0xe1, 0xdb, 0xc6, 0x0b, 0x28, 0x0e, 0x02, 0xec, 0x6f, 0xcf, 0xf8, 0x21, 0xa4,
-	0x67, 0x01, 0xf6, 0xa5, 0x0d, 0xf3, 0x1c, 0x8d, 0x53, 0xe2, 0x60, 0xe3, 0x77, 0x90, 0x66, 0x2b,
-	0xc6, 0xde, 0x1e, 0x0e, 0xbd, 0x99, 0x4b, 0x2d, 0x99, 0x46, 0xf2, 0x66, 0x5e, 0x42, 0x5a, 0x0e,
-	0xba, 0x07, 0xd9, 0x91, 0xef, 0xcd, 0xa6, 0x4c, 0xf2, 0x14, 0x8b, 0x65, 0xb1, 0x42, 0x6f, 0x43,
-	0xd1, 0x21, 0xc1, 0x74, 0x6c, 0x5f, 0x5b, 0xae, 0x3d, 0xc1, 0x32, 0x7d, 0x14, 0x24, 0xac, 0x6d,
-	0x4f, 0xb0, 0xf1, 0x5b, 0x28, 0xf7, 0xaf, 0x5a, 0xee, 0xa9, 0xb7, 0xbd, 0xec, 0xef, 0x40, 0x96,
-	0xf2, 0xa3, 0x52, 0xfa, 0x82, 0x88, 0x5a, 0x41, 0x4d, 0x6e, 0x19, 0x0f, 0x21, 0x2b, 0x20, 0xe8,
-	0x0e, 0x64, 0xe8, 0x55, 0x24, 0x7e, 0x9a, 0x5e, 0xb5, 0x1c, 0x63, 0x00, 0x3b, 0x3c, 0x4a, 0x98,
-	0x96, 0x73, 0xff, 0xbd, 0x0f, 0xf9, 0xe1, 0x98, 0x60, 0x55, 0x59, 0x4d, 0x00, 0x5a, 0x0e, 0x7a,
-	0x07, 0x4a, 0x72, 0x33, 0xc0, 0x43, 0x1f, 0x53, 0x19, 0x78, 0x45, 0x01, 0xec, 0x71, 0x98, 0xd1,
-	0x86, 0xd2, 0xeb, 0x47, 0x9b, 0x88, 0x9d, 0xa4, 0x1a, 0x3b, 0x8f, 0x40, 0xfb, 0x89, 0xe8, 0x3a,
-	0x05, 0xfd, 0x04, 0x53, 0xdb, 0xb1, 0x5f, 0xc7, 0xfd, 0xde, 0x03, 0x6d, 0x22, 0x0f, 0x4b, 0x5b,
-	0x96, 0x38, 0xea, 0x9c, 0xe2, 0x7c, 0xdb, 0xf8, 0x47, 0x0a, 0xb4, 0x10, 0xac, 0x54, 0x95, 0x3c,
-	0xaf, 0x2a, 0x61, 0x50, 0x24, 0xa3, 0xa0, 0x60, 0xb0, 0x80, 0xfc, 0x20, 0xee, 0x3e, 0x6d, 0xf2,
-	0x6f, 0xa6, 0xc2, 0x84, 0x92, 0x09, 0xe6, 0x25, 0x23, 0x6d, 0x8a, 0x05, 0xba, 0x0b, 0x59, 0x12,
-	0x58, 0x0e, 0xf1, 0x79, 0xa9, 0xd0, 0xcc, 0x0c, 0x09, 0x1a, 0xc4, 0x67, 0x04, 0x30, 0x4b, 0xcd,
-	0xa2, 0x4c, 0xf0, 0x6f, 0x96, 0xf8, 0x86, 0x67, 0x78, 0x78, 0x1e, 0xcc, 0x26, 0x61, 0x8d, 0x08,
-	0xd7, 0xcc, 0x57, 0x1d, 0xec, 0xe3, 0x53, 0x8b, 0x8b, 0xa2, 0x09, 0x5f, 0xe5, 0x90, 0x2e, 0x93,
-	0xe7, 0x11, 0x14, 0x49, 0x60, 0xf9, 0xd8, 0x76, 0x2c, 0xcf, 0x1d, 0x5f, 0x57, 0xf2, 0x9c, 0x17,
-	0x90, 0xc0, 0xc4, 0xb6, 0xd3, 0x71, 0xc7, 0xd7, 0xcc, 0x6b, 0x49, 0x60, 0x05, 0x67, 0xb6, 0x8f,
-	0xed, 0x97, 0x63, 0x5c, 0x01, 0x8e, 0x51, 0x20, 0x41, 0x2f, 0x04, 0x31, 0x99, 0x26, 0x4c, 0xfe,
-	0x82, 0x90, 0x89, 0x7d, 0xb3, 0x12, 0x19, 0x5c, 0x07, 0x95, 0xe2, 0xa3, 0xc4, 0xe3, 0xa2, 0xc9,
-	0x3e, 0x99, 0x24, 0xd4, 0xc7, 0xd8, 0xe2, 0x61, 0x52, 0x29, 0x71, 0x5d, 0xf3, 0x0c, 0x52, 0x67,
-	0x00, 0xb4, 0x07, 0x1a, 0xf6, 0x02, 0x8b, 0x15, 0xc4, 0x4a, 0x99, 0x13, 0xca, 0x61, 0x2f, 0x38,
-	0x24, 0x63, 0xcc, 0x44, 0x60, 0x5b, 0xc4, 0x0d, 0xa8, 0xed, 0x0e, 0x71, 0xe5, 0xb6, 0x08, 0x1c,
-	0xec, 0x05, 0x2d, 0x09, 0x62, 0x28, 0x5c, 0x44, 0x8b, 0xda, 0xfe, 0x08, 0xd3, 0x8a, 0x2e, 0x50,
-	0x38, 0xac, 0xcf, 0x41, 0xcc, 0xa0, 0x13, 0x32, 0x62, 0x4e, 0xbc, 0x23, 0x5c, 0x65, 0x42, 0x46,
-	0x2d, 0x87, 0xf1, 0x65, 0x60, 0x6e, 0x1e, 0x24, 0xf8, 0x4e, 0xc8, 0x88, 0x19, 0xc7, 0x78, 0x08,
-	0x39, 0xf6, 0x77, 0x5d, 0x82, 0xfb, 0x12, 0x72, 0x27, 0xde, 0x05, 0x66, 0xdb, 0x7b, 0xa0, 0x79,
-	0x63, 0xc7, 0x52, 0x50, 0x72, 0xde, 0xd8, 0xe1, 0x16, 0xde, 0x03, 0xcd, 0xc5, 0x97, 0x96, 0xe2,
-	0x09, 0x39, 0x17, 0x5f, 0x72, 0xfa, 0x2f, 0x21, 0xd7, 0xbf, 0xaa, 0x9f, 0xcd, 0xdc, 0xf3, 0x95,
-	0xe1, 0xc8, 0x12, 0xc9, 0x18, 0xbb, 0x23, 0x79, 0x30, 0x6d, 0xca, 0x15, 0x83, 0x7b, 0xa7, 0xa7,
-	0x01, 0xa6, 0xd2, 0x8d, 0xe4, 0x8a, 0x09, 0xc9, 0x9d, 0x36, 0xcd, 0x8d, 0xce, 0xbf, 0x8d, 0x0b,
-	0xd8, 0xfd, 0xce, 0x27, 0x14, 0xf7, 0x66, 0x93, 0x89, 0xed, 0x6f, 0x9f, 0xf2, 0xd1, 0x33, 0x28,
-	0x5e, 0x2a, 0x04, 0x64, 0x44, 0x88, 0xf6, 0x28, 0x46, 0x39, 0x86, 0x66, 0x1c, 0x41, 0x51, 0xdd,
-	0x45, 0x15, 0xc8, 0xb9, 0x43, 0xa6, 0xaa, 0x60, 0x98, 0x36, 0xc3, 0x25, 0xf7, 0x0b, 0x9e, 0xed,
-	0x79, 0x60, 0x24, 0xa5, 0x5f, 0x30, 0x48, 0x8f, 0xfc, 0x80, 0x8d, 0x63, 0xc8, 0xf4, 0xaf, 0x9a,
-	0xae, 0xb3, 0xda, 0x44, 0xab, 0x62, 0x4c, 0x0d, 0x87, 0x54, 0x3c, 0x1c, 0x8c, 0xdf, 0xc3, 0x4e,
-	0xc3, 0xa6, 0x36, 0x37, 0xfa, 0xf6, 0xb6, 0x78, 0x02, 0x79, 0x27, 0x3c, 0x2d, 0x0d, 0x51, 0xe6,
-	0xb8, 0x11, 0xcd, 0x08, 0xc1, 0xe8, 0x40, 0x7e, 0x0e, 0x57, 0xee, 0x32, 0xb1, 0xe6, 0x2e, 0x93,
-	0x2b, 0xef, 0x32, 0xa5, 0xdc, 0xe5, 0x29, 0xe8, 0x26, 0xbe, 0x20, 0x01, 0xf1, 0xdc, 0xd7, 0xca,
-	0x6a, 0xbe, 0x3c, 0x1c, 0xcb, 0x6a, 0x73, 0x8a, 0xf3, 0x6d, 0xc3, 0x01, 0x2d, 0x84, 0xb2, 0xce,
-	0xd4, 0xc7, 0x17, 0x6a, 0xe3, 0xed, 0xe3, 0x0b, 0xd6, 0x99, 0x86, 0x99, 0x2c, 0xb9, 0x2a, 0x93,
-	0xa5, 0x56, 0x67, 0xb2, 0xb4, 0x92, 0xc9, 0x8c, 0xcf, 0xa1, 0x10, 0x69, 0xb3, 0x32, 0xc2, 0x54,
-	0xe6, 0x49, 0x95, 0x39, 0xf3, 0x6a, 0x13, 0x0f, 0xaf, 0x87, 0x63, 0xdc, 0x74, 0xe9, 0x6b, 0x7a,
-	0xb5, 0xaf, 0x10, 0x88, 0x79, 0x75, 0x8c, 0x72, 0x0c, 0xcd, 0xf8, 0x73, 0x02, 0x8a, 0xea, 0x36,
-	0xcb, 0x3b, 0x3e, 0x0e, 0xa8, 0xe7, 0x63, 0x35, 0xf8, 0x0b, 0x12, 0xc6, 0x13, 0xc0, 0x5b, 0x10,
-	0x2e, 0x15, 0x45, 0x40, 0x82, 0x54, 0x4b, 0xaa, 0x35, 0xe1, 0x3e, 0xe4, 0x1d, 0x3c, 0xb6, 0xd4,
-	0xba, 0xa0, 0x39, 0x78, 0x7c, 0xb2, 0xa1, 0x34, 0x18, 0x55, 0xb8, 0x1d, 0x37, 0xca, 0xab, 0x45,
-	0xde, 0x89, 0x45, 0xde, 0xc6, 0x2f, 0xe0, 0x36, 0x6f, 0xe2, 0xb1, 0x3f, 0x21, 0x01, 0xbb, 0x8a,
-	0x80, 0x89, 0xc3, 0xea, 0x01, 0x47, 0xd6, 0x4c, 0xfe, 0xcd, 0x2e, 0x96, 0x47, 0x77, 0xd8, 0xf5,
-	0xf2, 0x85, 0xf1, 0x87, 0x04, 0x40, 0x1b, 0x5f, 0xf2, 0xfe, 0x72, 0xcd, 0x0d, 0xde, 0x87, 0x7c,
-	0x54, 0x5c, 0xc4, 0x61, 0xcd, 0x0f, 0x4b, 0x8b, 0xda, 0xb0, 0xa7, 0xe2, 0x0d, 0x3b, 0xcb, 0x17,
-	0xf8, 0x6a, 0x4a, 0x7c, 0x1c, 0x48, 0xf5, 0xc3, 0x25, 0x37, 0x8d, 0xef, 0x4d, 0x05, 0x49, 0x61,
-	0x00, 0x8d, 0x01, 0x18, 0x49, 0xe3, 0x2f, 0x49, 0x28, 0x0d, 0xa6, 0x8e, 0x4d, 0x71, 0x28, 0xd5,
-	0x62, 0x55, 0x7e, 0x17, 0x6e, 0xcf, 0x38, 0x82, 0x15, 0x1b, 0x16, 0x34, 0xb3, 0x2c, 0xc0, 0xdd,
-	0x50, 0x82, 0x4d, 0xd2, 0x7d, 0x00, 0x3b, 0x92, 0x08, 0x97, 0xca, 0xa6, 0x2c, 0xaa, 0x84, 0x77,
-	0xeb, 0x62, 0xa3, 0x39, 0x87, 0xa3, 0x37, 0x01, 0x14, 0xac, 0x0c, 0xd7, 0x46, 0x81, 0xc4, 0x6d,
-	0x94, 0x5d, 0xb0, 0xd1, 0x63, 0x90, 0x04, 0x95, 0x22, 0x9d, 0x53, 0xe5, 0x9d, 0x17, 0xea, 0x98,
-	0x5d, 0xb4, 0xb8, 0x5d, 0x14, 0x32, 0x11, 0x4e, 0x5e, 0x25, 0xd3, 0x08, 0x2d, 0xe8, 0x02, 0x52,
-	0xc6, 0x86, 0xad, 0x03, 0xeb, 0x23, 0x50, 0x26, 0x8d, 0x9b, 0x0c, 0x23, 0x7f, 0x4c, 0x40, 0x99,
-	0xb7, 0x12, 0x26, 0x1e, 0x92, 0x29, 0xeb, 0x1a, 0x99, 0xe5, 0x89, 0x83, 0x5d, 0x4a, 0x68, 0xe8,
-	0xb2, 0xf3, 0x35, 0x7a, 0x06, 0x69, 0x65, 0x4a, 0x7f, 0x5b, 0x88, 0x11, 0x3b, 0x7e, 0x30, 0xff,
-	0xe2, 0x53, 0x3b, 0x47, 0x37, 0x0e, 0xa0, 0x14, 0x03, 0xb3, 0x19, 0x79, 0xd0, 0xe3, 0xd3, 0x72,
-	0x1e, 0x32, 0x47, 0x66, 0x67, 0xd0, 0xd5, 0x13, 0x1c, 0xd8, 0x6e, 0x7d, 0xaf, 0x27, 0x8d, 0x3f,
-	0x25, 0x20, 0x5b, 0xab, 0x1f, 0xaf, 0x73, 0xeb, 0x8f, 0xd9, 0x95, 0x49, 0x72, 0x52, 0xc9, 0x3b,
-	0x2b, 0x44, 0x31, 0x23, 0xac, 0xf8, 0x2d, 0xa7, 0x96, 0x6e, 0x39, 0xcb, 0x5b, 0x15, 0xe6, 0xec,
-	0xa9, 0xc7, 0x85, 0xaa, 0xce, 0x89, 0x1d, 0x7a, 0x63, 0x07, 0xfb, 0x82, 0xa4, 0xdc, 0x37, 0xfe,
-	0x96, 0x04, 0x88, 0x2c, 0xb9, 0xe4, 0xdd, 0x2b, 0x1b, 0xe6, 0x55, 0x6f, 0x21, 0xf1, 0xe1, 0x3b,
-	0xbd, 0x30, 0x7c, 0xab, 0xe1, 0x97, 0x59, 0x0a, 0xbf, 0xf5, 0xde, 0x3a, 0x2f, 0x00, 0x39, 0xb5,
-	0x00, 0x3c, 0x53, 0x9f, 0x57, 0x34, 0x7e, 0x71, 0x95, 0x05, 0x97, 0x58, 0xf5, 0xca, 0xc2, 0x9a,
-	0xaa, 0x4b, 0x17, 0xfb, 0xac, 0xe6, 0xe7, 0x65, 0x53, 0xc5, 0xd6, 0xa2, 0xec, 0xf3, 0x11, 0x0a,
-	0x84, 0x42, 0xec, 0x3b, 0xee, 0xff, 0x85, 0x85, 0xbc, 0xa0, 0x3e, 0x95, 0x84, 0xcf, 0x23, 0xb7,
-	0x94, 0x47, 0x93, 0x84, 0xf1, 0xbe, 0xea, 0xf7, 0x3f, 0x31, 0x5e, 0x3c, 0x00, 0xe0, 0xb7, 0xd2,
-	0x6a, 0xac, 0xc8, 0x30, 0x86, 0x0f, 0x77, 0xd4, 0x9b, 0xdb, 0x3a, 0x84, 0xaa, 0x50, 0x38, 0x8d,
-	0xce, 0x4b, 0xf7, 0x5a, 0xf6, 0x08, 0x15, 0xc9, 0xf8, 0x6b, 0x12, 0x0a, 0xca, 0xe6, 0x8d, 0x66,
-	0x11, 0xd5, 0xbe, 0xa9, 0xb8, 0x7d, 0x63, 0xfe, 0x9d, 0xde, 0xde, 0xbf, 0x33, 0xcb, 0x7e, 0x31,
-	0xe4, 0x7e, 0x91, 0x15, 0x7e, 0xc1, 0x17, 0x6b, 0xbc, 0xe5, 0x1e, 0x64, 0x65, 0x13, 0xaf, 0x85,
-	0x4f, 0x61, 0xbc, 0x7f, 0x7f, 0x02, 0x19, 0x66, 0x20, 0xcc, 0x7d, 0xa1, 0x5c, 0xbd, 0xb7, 0x68,
-	0x10, 0x6e, 0x4a, 0x6c, 0x0a, 0x24, 0xe3, 0x29, 0x64, 0xf8, 0x1a, 0x15, 0x41, 0xab, 0xd5, 0xeb,
-	0xcd, 0x6e, 0xbf, 0xd9, 0xd0, 0x6f, 0xa1, 0x02, 0xe4, 0xba, 0xcd, 0x76, 0xa3, 0xd5, 0x3e, 0xd2,
-	0x13, 0x6c, 0xcb, 0x6c, 0x7e, 0xd3, 0xac, 0xb3, 0xad, 0xa4, 0x71, 0x06, 0x77, 0x4d, 0x3c, 0xc4,
-	0xe4, 0x02, 0x3b, 0xaf, 0x79, 0x71, 0xff, 0x0f, 0x99, 0x60, 0xe3, 0x95, 0x89, 0x6d, 0xe3, 0x12,
-	0x76, 0xda, 0xf8, 0x32, 0xe6, 0x23, 0xff, 0x93, 0x34, 0x63, 0x4c, 0x60, 0x57, 0x14, 0xc7, 0x05,
-	0xde, 0x8b, 0xde, 0xb2, 0xaa, 0xe8, 0x24, 0xd7, 0x15, 0x9d, 0xf5, 0xec, 0x0c, 0xd0, 0x07, 0x2e,
-	0x57, 0x59, 0xf0, 0x5b, 0x15, 0x2c, 0x8f, 0x01, 0x1d, 0x93, 0x80, 0x46, 0xa1, 0x17, 0xac, 0x1b,
-	0xb7, 0xde, 0x83, 0x3b, 0x0c, 0x53, 0x11, 0x7d, 0x2d, 0xea, 0x87, 0xac, 0x51, 0x8e, 0x5d, 0x25,
-	0x1f, 0xd1, 0xc4, 0x84, 0x38, 0x67, 0x9f, 0xe3, 0xeb, 0x96, 0xf3, 0xfe, 0xdf, 0x93, 0x00, 0xd1,
-	0x75, 0xa2, 0x2c, 0x24, 0x3b, 0xcf, 0x85, 0xaf, 0x0c, 0xda, 0xcf, 0xdb, 0x9d, 0xef, 0xda, 0x7a,
-	0x02, 0xdd, 0x85, 0x9d, 0x5e, 0xbf, 0x63, 0xd6, 0x8e, 0x9a, 0x56, 0xbb, 0xd3, 0xb7, 0x0e, 0x3b,
-	0x83, 0x76, 0x43, 0x4f, 0xa2, 0x7d, 0xb8, 0x17, 0x82, 0x6b, 0xc7, 0x66, 0xb3, 0xd6, 0xf8, 0xb5,
-	0xd5, 0xfc, 0xbe, 0xd5, 0xeb, 0xf7, 0xf4, 0x14, 0x7a, 0x00, 0x95, 0x70, 0xaf, 0xdb, 0x34, 0x4f,
-	0x5a, 0xbd, 0x5e, 0xab, 0xd3, 0x6e, 0x34, 0xdb, 0xad, 0x66, 0x43, 0x4f, 0xa3, 0x3d, 0xb8, 0x5b,
-	0xef, 0xb4, 0xfb, 0xcd, 0xef, 0xfb, 0x16, 0x2b, 0x44, 0x96, 0xd9, 0xfc, 0x76, 0xd0, 0x32, 0x9b,
-	0x0d, 0x3d, 0x83, 0x74, 0x28, 0x76, 0x6b, 0xfd, 0xaf, 0xad, 0x56, 0xfb, 0x45, 0xed, 0xb8, 0xd5,
-	0xd0, 0xb3, 0x0c, 0xb9, 0x3b, 0xf8, 0xea, 0xb8, 0x55, 0xb7, 0x8e, 0x5b, 0xed, 0xe7, 0x8a, 0x04,
-	0x39, 0xc6, 0x45, 0xdd, 0x92, 0x67, 0xac, 0x46, 0xad, 0xdf, 0xd4, 0x35, 0xf4, 0x08, 0x1e, 0xac,
-	0xda, 0xed, 0xd6, 0x7a, 0xbd, 0xef, 0x3a, 0x66, 0x43, 0xcf, 0x33, 0xd2, 0xaa, 0x62, 0xbd, 0x41,
-	0xb7, 0xdb, 0x31, 0x59, 0x44, 0x00, 0x42, 0x50, 0xe6, 0xa2, 0x45, 0xec, 0x0a, 0x68, 0x07, 0x4a,
-	0xfd, 0xce, 0xf3, 0x66, 0x7b, 0x2e, 0x5c, 0x91, 0xd9, 0x40, 0x64, 0x51, 0xab, 0xf7, 0x75, 0xcd,
-	0x54, 0xed, 0x53, 0xaa, 0xfe, 0x98, 0x84, 0x74, 0x6d, 0x46, 0xcf, 0xd0, 0x17, 0x50, 0x8e, 0x3f,
-	0x2d, 0xa1, 0x30, 0x80, 0x17, 0xde, 0x9b, 0xf6, 0x91, 0x78, 0xb8, 0x52, 0x1f, 0x8c, 0x8c, 0x5b,
-	0xe8, 0x53, 0x40, 0x0d, 0x12, 0x4c, 0x6c, 0x97, 0x8e, 0x15, 0x1a, 0x25, 0x15, 0xf7, 0xd5, 0xfe,
-	0x4e, 0xf4, 0x62, 0x17, 0x9d, 0xfc, 0x06, 0x76, 0x57, 0x3d, 0xfd, 0xa2, 0x07, 0x11, 0xff, 0xe5,
-	0xc4, 0xbf, 0x46, 0x8a, 0x06, 0x54, 0xe6, 0x52, 0x2c, 0xd2, 0x5b, 0x90, 0xe5, 0x8d, 0xc5, 0xa6,
-	0x67, 0x4e, 0xa5, 0xfa, 0xef, 0x1c, 0xe4, 0x7a, 0xd4, 0xf3, 0xed, 0x11, 0x46, 0x1f, 0x41, 0xbe,
-	0xee, 0x63, 0xd6, 0x80, 0x11, 0x1f, 0x15, 0xc5, 0x19, 0xf1, 0xe6, 0x20, 0x45, 0x88, 0xbd, 0xd4,
-	0x1a, 0xb7, 0xd0, 0x13, 0xc8, 0x36, 0xf0, 0x18, 0xb3, 0xcc, 0x76, 0x03, 0xec, 0xf7, 0x21, 0x7d,
-	0xe2, 0x5d, 0x84, 0xb8, 0xf2, 0xb9, 0x62, 0x0d, 0xee, 0x53, 0xc8, 0xb5, 0xdc, 0x60, 0x8a, 0x87,
-	0x74, 0x81, 0xf4, 0xdd, 0xf8, 0xf3, 0x57, 0x74, 0xe2, 0x19, 0x40, 0x14, 0x92, 0x37, 0x3c, 0xf4,
-	0x34, 0x81, 0x3e, 0x81, 0x62, 0x8f, 0xda, 0x3e, 0xe5, 0x0f, 0x04, 0xfd, 0x2b, 0x69, 0xb9, 0xf0,
-	0x61, 0x79, 0xff, 0x8e, 0xfa, 0x72, 0x19, 0x31, 0xfb, 0x0c, 0x80, 0x1f, 0x10, 0xf3, 0x74, 0x51,
-	0x22, 0xf1, 0xd5, 0xfe, 0xde, 0xf2, 0x73, 0xc4, 0xfc, 0xe0, 0xe3, 0x04, 0xfa, 0x18, 0x4a, 0x87,
-	0xc4, 0x25, 0xc1, 0x59, 0xc8, 0x11, 0xe4, 0xe9, 0xa6, 0xeb, 0xac, 0x31, 0xc6, 0x27, 0x6c, 0x06,
-	0xb6, 0x1d, 0xfe, 0xfe, 0x14, 0x57, 0xec, 0xde, 0xc2, 0xc4, 0xaf, 0x6a, 0xf6, 0x29, 0x94, 0x98,
-	0x41, 0xc2, 0xb9, 0x36, 0x58, 0x69, 0x93, 0xc5, 0x19, 0x9e, 0x9f, 0xfc, 0x25, 0x1b, 0x2c, 0x6d,
-	0x67, 0x3e, 0x77, 0xeb, 0x0b, 0xa8, 0x9b, 0xf9, 0x7e, 0xc6, 0x46, 0x3f, 0x3e, 0xd4, 0x6d, 0x20,
-	0xb0, 0x5a, 0xd1, 0xcf, 0xa1, 0x20, 0x44, 0xe6, 0x93, 0xe3, 0x82, 0xc0, 0x7b, 0xcb, 0x03, 0xb1,
-	0xca, 0xb6, 0x06, 0x77, 0xe6, 0x6c, 0x95, 0xa1, 0x78, 0x77, 0xc5, 0xa9, 0x75, 0xec, 0xab, 0x50,
-	0x94, 0xa0, 0x55, 0xfc, 0x57, 0x9f, 0xf9, 0x00, 0xb2, 0x3d, 0x4c, 0x6b, 0xf5, 0x63, 0x24, 0x1e,
-	0xb9, 0x45, 0xa3, 0xbe, 0x06, 0xf9, 0x00, 0xf2, 0xa2, 0xe6, 0xdd, 0x10, 0xff, 0x43, 0xd0, 0x06,
-	0x6e, 0x70, 0x63, 0xf2, 0x1f, 0x81, 0x76, 0x84, 0x29, 0xff, 0xa1, 0x43, 0xfa, 0x71, 0xf8, 0xa3,
-	0x88, 0x3c, 0x10, 0xfb, 0x0d, 0xc4, 0xb8, 0x55, 0xfd, 0x31, 0xc1, 0x7f, 0xd4, 0x1c, 0x61, 0x1f,
-	0x3d, 0x81, 0xdc, 0x11, 0xa6, 0x7d, 0x7b, 0x14, 0x48, 0x4e, 0xe2, 0xc7, 0xce, 0x7d, 0x3d, 0x5a,
-	0x28, 0xc6, 0x16, 0x5a, 0xf7, 0xed, 0x51, 0x1c, 0x79, 0x83, 0x16, 0x37, 0x46, 0xaf, 0xfe, 0x2b,
-	0x0b, 0x19, 0xd1, 0x38, 0x7e, 0x01, 0xba, 0xc8, 0x47, 0xca, 0x90, 0x21, 0x1a, 0x9e, 0x68, 0xd2,
-	0xdf, 0x90, 0xdb, 0x50, 0x0d, 0x74, 0x61, 0x6e, 0xe5, 0xbc, 0xe0, 0x19, 0x1b, 0xcb, 0x37, 0x91,
-	0xf8, 0x12, 0x76, 0x64, 0x1e, 0x5a, 0x92, 0x21, 0xea, 0xba, 0x37, 0x11, 0xf8, 0x8c, 0xbf, 0x93,
-	0x79, 0xe7, 0x78, 0xd3, 0xf9, 0xd5, 0x76, 0x3b, 0x82, 0xdb, 0x0b, 0xed, 0x08, 0x12, 0x8c, 0x96,
-	0x9b, 0x94, 0x0d, 0x12, 0x3c, 0x4d, 0xa0, 0x06, 0x94, 0x6b, 0x8e, 0xa3, 0xb6, 0xe4, 0xf7, 0x42,
-	0x2b, 0xc6, 0x9b, 0xaf, 0xfd, 0xca, 0x52, 0x9b, 0xa8, 0xd6, 0xae, 0x9d, 0xa5, 0x86, 0x0d, 0xed,
-	0x29, 0xe6, 0xdc, 0x8a, 0x96, 0xbe, 0xd8, 0x3f, 0xa1, 0xca, 0x5c, 0xb7, 0x85, 0xb6, 0x6a, 0x13,
-	0x25, 0x9e, 0xad, 0x4a, 0xb1, 0xce, 0x0e, 0x89, 0xcc, 0xb6, 0xd8, 0xed, 0xad, 0x31, 0xf2, 0xaf,
-	0xa0, 0x7c, 0x84, 0x55, 0x8e, 0xcb, 0xb7, 0xb3, 0x49, 0x91, 0xba, 0x68, 0x19, 0x63, 0x1d, 0x5e,
-	0xb0, 0x58, 0x44, 0xf6, 0xc3, 0x1c, 0xb4, 0xdc, 0xd0, 0xcb, 0xd4, 0x85, 0x4e, 0xbc, 0x99, 0x1b,
-	0xa7, 0x82, 0xee, 0xae, 0x3a, 0xb5, 0x4e, 0x8d, 0x3a, 0xec, 0x0e, 0xdc, 0xc9, 0x7f, 0x47, 0xa4,
-	0xfa, 0x15, 0xe4, 0xba, 0x3e, 0xbe, 0x20, 0xf8, 0x12, 0xfd, 0x1c, 0x0a, 0xac, 0x04, 0x84, 0xcb,
-	0x1b, 0x57, 0x9d, 0x97, 0x59, 0xfe, 0x2f, 0x13, 0x3f, 0xfb, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0xb7, 0x4c, 0x6b, 0xd8, 0x3f, 0x21, 0x00, 0x00,
+func (m *PublicLink) Reset()         { *m = PublicLink{} }
+func (m *PublicLink) String() string { return proto.CompactTextString(m) }
+func (*PublicLink) ProtoMessage()    {}
+func (*PublicLink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{51}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *PublicLink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PublicLink.Unmarshal(m, b)
+}
+func (m *PublicLink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PublicLink.Marshal(b, m, deterministic)
+}
+func (m *PublicLink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PublicLink.Merge(m, src)
+}
+func (m *PublicLink) XXX_Size() int {
+	return xxx_messageInfo_PublicLink.Size(m)
+}
+func (m *PublicLink) XXX_DiscardUnknown() {
+	xxx_messageInfo_PublicLink.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_PublicLink proto.InternalMessageInfo
 
-// AuthClient is the client API for Auth service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AuthClient interface {
-	ForgeUserToken(ctx context.Context, in *ForgeUserTokenReq, opts ...grpc.CallOption) (*TokenResponse, error)
-	DismantleUserToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*UserResponse, error)
-	ForgePublicLinkToken(ctx context.Context, in *ForgePublicLinkTokenReq, opts ...grpc.CallOption) (*TokenResponse, error)
-	DismantlePublicLinkToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*PublicLinkResponse, error)
+func (m *PublicLink) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
 }
 
-type authClient struct {
-	cc *grpc.ClientConn
+func (m *PublicLink) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
 }
 
-func NewAuthClient(cc *grpc.ClientConn) AuthClient {
-	return &authClient{cc}
+func (m *PublicLink) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
 }
 
-func (c *authClient) ForgeUserToken(ctx context.Context, in *ForgeUserTokenReq, opts ...grpc.CallOption) (*TokenResponse, error) {
-	out := new(TokenResponse)
-	err := c.cc.Invoke(ctx, "/api.Auth/ForgeUserToken", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PublicLink) GetProtected() bool {
+	if m != nil {
+		return m.Protected
 	}
-	return out, nil
+	return false
 }
 
-func (c *authClient) DismantleUserToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*UserResponse, error) {
-	out := new(UserResponse)
-	err := c.cc.Invoke(ctx, "/api.Auth/DismantleUserToken", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PublicLink) GetExpires() uint64 {
+	if m != nil {
+		return m.Expires
 	}
-	return out, nil
+	return 0
 }
 
-func (c *authClient) ForgePublicLinkToken(ctx context.Context, in *ForgePublicLinkTokenReq, opts ...grpc.CallOption) (*TokenResponse, error) {
-	out := new(TokenResponse)
-	err := c.cc.Invoke(ctx, "/api.Auth/ForgePublicLinkToken", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PublicLink) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
 	}
-	return out, nil
+	return false
 }
 
-func (c *authClient) DismantlePublicLinkToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*PublicLinkResponse, error) {
-	out := new(PublicLinkResponse)
-	err := c.cc.Invoke(ctx, "/api.Auth/DismantlePublicLinkToken", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PublicLink) GetMtime() uint64 {
+	if m != nil {
+		return m.Mtime
 	}
-	return out, nil
-}
-
-// AuthServer is the server API for Auth service.
-type AuthServer interface {
-	ForgeUserToken(context.Context, *ForgeUserTokenReq) (*TokenResponse, error)
-	DismantleUserToken(context.Context, *TokenReq) (*UserResponse, error)
-	ForgePublicLinkToken(context.Context, *ForgePublicLinkTokenReq) (*TokenResponse, error)
-	DismantlePublicLinkToken(context.Context, *TokenReq) (*PublicLinkResponse, error)
+	return 0
 }
 
-func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
-	s.RegisterService(&_Auth_serviceDesc, srv)
+func (m *PublicLink) GetItemType() PublicLink_ItemType {
+	if m != nil {
+		return m.ItemType
+	}
+	return PublicLink_FILE
 }
 
-func _Auth_ForgeUserToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ForgeUserTokenReq)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).ForgeUserToken(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Auth/ForgeUserToken",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).ForgeUserToken(ctx, req.(*ForgeUserTokenReq))
+func (m *PublicLink) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Auth_DismantleUserToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TokenReq)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).DismantleUserToken(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Auth/DismantleUserToken",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).DismantleUserToken(ctx, req.(*TokenReq))
+func (m *PublicLink) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Auth_ForgePublicLinkToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ForgePublicLinkTokenReq)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).ForgePublicLinkToken(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Auth/ForgePublicLinkToken",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).ForgePublicLinkToken(ctx, req.(*ForgePublicLinkTokenReq))
+func (m *PublicLink) GetDropOnly() bool {
+	if m != nil {
+		return m.DropOnly
 	}
-	return interceptor(ctx, in, info, handler)
+	return false
 }
 
-func _Auth_DismantlePublicLinkToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TokenReq)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).DismantlePublicLinkToken(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Auth/DismantlePublicLinkToken",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).DismantlePublicLinkToken(ctx, req.(*TokenReq))
+func (m *PublicLink) GetMaxDownloads() uint64 {
+	if m != nil {
+		return m.MaxDownloads
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-var _Auth_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "api.Auth",
-	HandlerType: (*AuthServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "ForgeUserToken",
-			Handler:    _Auth_ForgeUserToken_Handler,
-		},
-		{
-			MethodName: "DismantleUserToken",
-			Handler:    _Auth_DismantleUserToken_Handler,
-		},
-		{
-			MethodName: "ForgePublicLinkToken",
-			Handler:    _Auth_ForgePublicLinkToken_Handler,
-		},
-		{
-			MethodName: "DismantlePublicLinkToken",
-			Handler:    _Auth_DismantlePublicLinkToken_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "api.proto",
+func (m *PublicLink) GetMaxVisitors() uint64 {
+	if m != nil {
+		return m.MaxVisitors
+	}
+	return 0
 }
 
-// StorageClient is the client API for Storage service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type StorageClient interface {
-	CreateDir(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	Delete(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	Move(ctx context.Context, in *MoveReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	Inspect(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*MetadataResponse, error)
-	ListFolder(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListFolderClient, error)
-	StartWriteTx(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (*TxInfoResponse, error)
-	WriteChunk(ctx context.Context, opts ...grpc.CallOption) (Storage_WriteChunkClient, error)
-	FinishWriteTx(ctx context.Context, in *TxEnd, opts ...grpc.CallOption) (*EmptyResponse, error)
-	ReadFile(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ReadFileClient, error)
-	ListRevisions(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListRevisionsClient, error)
-	ReadRevision(ctx context.Context, in *RevisionReq, opts ...grpc.CallOption) (Storage_ReadRevisionClient, error)
-	RestoreRevision(ctx context.Context, in *RevisionReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	ListRecycle(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListRecycleClient, error)
-	RestoreRecycleEntry(ctx context.Context, in *RecycleEntryReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	EmptyRecycle(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	SetACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	UpdateACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	UnsetACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	GetQuota(ctx context.Context, in *QuotaReq, opts ...grpc.CallOption) (*QuotaResponse, error)
+func (m *PublicLink) GetDownloads() uint64 {
+	if m != nil {
+		return m.Downloads
+	}
+	return 0
 }
 
-type storageClient struct {
-	cc *grpc.ClientConn
+func (m *PublicLink) GetUploads() uint64 {
+	if m != nil {
+		return m.Uploads
+	}
+	return 0
 }
 
-func NewStorageClient(cc *grpc.ClientConn) StorageClient {
-	return &storageClient{cc}
+func (m *PublicLink) GetVisitors() uint64 {
+	if m != nil {
+		return m.Visitors
+	}
+	return 0
 }
 
-func (c *storageClient) CreateDir(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/CreateDir", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PublicLink) GetLastAccess() uint64 {
+	if m != nil {
+		return m.LastAccess
 	}
-	return out, nil
+	return 0
 }
 
-func (c *storageClient) Delete(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/Delete", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PublicLink) GetVersion() string {
+	if m != nil {
+		return m.Version
 	}
-	return out, nil
+	return ""
 }
 
-func (c *storageClient) Move(ctx context.Context, in *MoveReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/Move", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PublicLink) GetUploadPolicy() *UploadPolicy {
+	if m != nil {
+		return m.UploadPolicy
 	}
-	return out, nil
+	return nil
 }
 
-func (c *storageClient) Inspect(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*MetadataResponse, error) {
-	out := new(MetadataResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/Inspect", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PublicLink) GetUploadedBytes() uint64 {
+	if m != nil {
+		return m.UploadedBytes
 	}
-	return out, nil
+	return 0
 }
 
-func (c *storageClient) ListFolder(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListFolderClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[0], "/api.Storage/ListFolder", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &storageListFolderClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *PublicLink) GetLabels() []string {
+	if m != nil {
+		return m.Labels
 	}
-	return x, nil
+	return nil
 }
 
-type Storage_ListFolderClient interface {
-	Recv() (*MetadataResponse, error)
-	grpc.ClientStream
+type PublicLinkTokenReq struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type storageListFolderClient struct {
-	grpc.ClientStream
+func (m *PublicLinkTokenReq) Reset()         { *m = PublicLinkTokenReq{} }
+func (m *PublicLinkTokenReq) String() string { return proto.CompactTextString(m) }
+func (*PublicLinkTokenReq) ProtoMessage()    {}
+func (*PublicLinkTokenReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{52}
 }
 
-func (x *storageListFolderClient) Recv() (*MetadataResponse, error) {
-	m := new(MetadataResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (m *PublicLinkTokenReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PublicLinkTokenReq.Unmarshal(m, b)
 }
-
-func (c *storageClient) StartWriteTx(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (*TxInfoResponse, error) {
-	out := new(TxInfoResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/StartWriteTx", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *PublicLinkTokenReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PublicLinkTokenReq.Marshal(b, m, deterministic)
 }
-
-func (c *storageClient) WriteChunk(ctx context.Context, opts ...grpc.CallOption) (Storage_WriteChunkClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[1], "/api.Storage/WriteChunk", opts...)
-	if err != nil {
-		return nil, err
-	}
+func (m *PublicLinkTokenReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PublicLinkTokenReq.Merge(m, src)
+}
+func (m *PublicLinkTokenReq) XXX_Size() int {
+	return xxx_messageInfo_PublicLinkTokenReq.Size(m)
+}
+func (m *PublicLinkTokenReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_PublicLinkTokenReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PublicLinkTokenReq proto.InternalMessageInfo
+
+func (m *PublicLinkTokenReq) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ShareIDReq struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShareIDReq) Reset()         { *m = ShareIDReq{} }
+func (m *ShareIDReq) String() string { return proto.CompactTextString(m) }
+func (*ShareIDReq) ProtoMessage()    {}
+func (*ShareIDReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{53}
+}
+
+func (m *ShareIDReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShareIDReq.Unmarshal(m, b)
+}
+func (m *ShareIDReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShareIDReq.Marshal(b, m, deterministic)
+}
+func (m *ShareIDReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShareIDReq.Merge(m, src)
+}
+func (m *ShareIDReq) XXX_Size() int {
+	return xxx_messageInfo_ShareIDReq.Size(m)
+}
+func (m *ShareIDReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShareIDReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShareIDReq proto.InternalMessageInfo
+
+func (m *ShareIDReq) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type FolderShareResponse struct {
+	Status               StatusCode   `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	FolderShare          *FolderShare `protobuf:"bytes,2,opt,name=folderShare,proto3" json:"folderShare,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *FolderShareResponse) Reset()         { *m = FolderShareResponse{} }
+func (m *FolderShareResponse) String() string { return proto.CompactTextString(m) }
+func (*FolderShareResponse) ProtoMessage()    {}
+func (*FolderShareResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{54}
+}
+
+func (m *FolderShareResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FolderShareResponse.Unmarshal(m, b)
+}
+func (m *FolderShareResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FolderShareResponse.Marshal(b, m, deterministic)
+}
+func (m *FolderShareResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FolderShareResponse.Merge(m, src)
+}
+func (m *FolderShareResponse) XXX_Size() int {
+	return xxx_messageInfo_FolderShareResponse.Size(m)
+}
+func (m *FolderShareResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FolderShareResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FolderShareResponse proto.InternalMessageInfo
+
+func (m *FolderShareResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *FolderShareResponse) GetFolderShare() *FolderShare {
+	if m != nil {
+		return m.FolderShare
+	}
+	return nil
+}
+
+type FolderShare struct {
+	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Path                 string            `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	OwnerId              string            `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Recipient            *ShareRecipient   `protobuf:"bytes,4,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	ReadOnly             bool              `protobuf:"varint,5,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Ctime                uint64            `protobuf:"varint,6,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	Mtime                uint64            `protobuf:"varint,7,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	Target               string            `protobuf:"bytes,8,opt,name=target,proto3" json:"target,omitempty"`
+	State                FolderShare_State `protobuf:"varint,9,opt,name=state,proto3,enum=api.FolderShare_State" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *FolderShare) Reset()         { *m = FolderShare{} }
+func (m *FolderShare) String() string { return proto.CompactTextString(m) }
+func (*FolderShare) ProtoMessage()    {}
+func (*FolderShare) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{55}
+}
+
+func (m *FolderShare) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FolderShare.Unmarshal(m, b)
+}
+func (m *FolderShare) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FolderShare.Marshal(b, m, deterministic)
+}
+func (m *FolderShare) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FolderShare.Merge(m, src)
+}
+func (m *FolderShare) XXX_Size() int {
+	return xxx_messageInfo_FolderShare.Size(m)
+}
+func (m *FolderShare) XXX_DiscardUnknown() {
+	xxx_messageInfo_FolderShare.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FolderShare proto.InternalMessageInfo
+
+func (m *FolderShare) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *FolderShare) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FolderShare) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *FolderShare) GetRecipient() *ShareRecipient {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+func (m *FolderShare) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
+}
+
+func (m *FolderShare) GetCtime() uint64 {
+	if m != nil {
+		return m.Ctime
+	}
+	return 0
+}
+
+func (m *FolderShare) GetMtime() uint64 {
+	if m != nil {
+		return m.Mtime
+	}
+	return 0
+}
+
+func (m *FolderShare) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *FolderShare) GetState() FolderShare_State {
+	if m != nil {
+		return m.State
+	}
+	return FolderShare_ACCEPTED
+}
+
+type ReceivedShareResponse struct {
+	Status               StatusCode   `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Share                *FolderShare `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ReceivedShareResponse) Reset()         { *m = ReceivedShareResponse{} }
+func (m *ReceivedShareResponse) String() string { return proto.CompactTextString(m) }
+func (*ReceivedShareResponse) ProtoMessage()    {}
+func (*ReceivedShareResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{56}
+}
+
+func (m *ReceivedShareResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReceivedShareResponse.Unmarshal(m, b)
+}
+func (m *ReceivedShareResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReceivedShareResponse.Marshal(b, m, deterministic)
+}
+func (m *ReceivedShareResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReceivedShareResponse.Merge(m, src)
+}
+func (m *ReceivedShareResponse) XXX_Size() int {
+	return xxx_messageInfo_ReceivedShareResponse.Size(m)
+}
+func (m *ReceivedShareResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReceivedShareResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReceivedShareResponse proto.InternalMessageInfo
+
+func (m *ReceivedShareResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *ReceivedShareResponse) GetShare() *FolderShare {
+	if m != nil {
+		return m.Share
+	}
+	return nil
+}
+
+type NewFolderShareReq struct {
+	Path                 string          `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Recipient            *ShareRecipient `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	ReadOnly             bool            `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *NewFolderShareReq) Reset()         { *m = NewFolderShareReq{} }
+func (m *NewFolderShareReq) String() string { return proto.CompactTextString(m) }
+func (*NewFolderShareReq) ProtoMessage()    {}
+func (*NewFolderShareReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{57}
+}
+
+func (m *NewFolderShareReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewFolderShareReq.Unmarshal(m, b)
+}
+func (m *NewFolderShareReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewFolderShareReq.Marshal(b, m, deterministic)
+}
+func (m *NewFolderShareReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewFolderShareReq.Merge(m, src)
+}
+func (m *NewFolderShareReq) XXX_Size() int {
+	return xxx_messageInfo_NewFolderShareReq.Size(m)
+}
+func (m *NewFolderShareReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewFolderShareReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewFolderShareReq proto.InternalMessageInfo
+
+func (m *NewFolderShareReq) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *NewFolderShareReq) GetRecipient() *ShareRecipient {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+func (m *NewFolderShareReq) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
+}
+
+type UpdateFolderShareReq struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UpdateReadOnly       bool     `protobuf:"varint,2,opt,name=update_read_only,json=updateReadOnly,proto3" json:"update_read_only,omitempty"`
+	ReadOnly             bool     `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateFolderShareReq) Reset()         { *m = UpdateFolderShareReq{} }
+func (m *UpdateFolderShareReq) String() string { return proto.CompactTextString(m) }
+func (*UpdateFolderShareReq) ProtoMessage()    {}
+func (*UpdateFolderShareReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{58}
+}
+
+func (m *UpdateFolderShareReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateFolderShareReq.Unmarshal(m, b)
+}
+func (m *UpdateFolderShareReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateFolderShareReq.Marshal(b, m, deterministic)
+}
+func (m *UpdateFolderShareReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateFolderShareReq.Merge(m, src)
+}
+func (m *UpdateFolderShareReq) XXX_Size() int {
+	return xxx_messageInfo_UpdateFolderShareReq.Size(m)
+}
+func (m *UpdateFolderShareReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateFolderShareReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateFolderShareReq proto.InternalMessageInfo
+
+func (m *UpdateFolderShareReq) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *UpdateFolderShareReq) GetUpdateReadOnly() bool {
+	if m != nil {
+		return m.UpdateReadOnly
+	}
+	return false
+}
+
+func (m *UpdateFolderShareReq) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
+}
+
+type UnshareFolderReq struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnshareFolderReq) Reset()         { *m = UnshareFolderReq{} }
+func (m *UnshareFolderReq) String() string { return proto.CompactTextString(m) }
+func (*UnshareFolderReq) ProtoMessage()    {}
+func (*UnshareFolderReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{59}
+}
+
+func (m *UnshareFolderReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnshareFolderReq.Unmarshal(m, b)
+}
+func (m *UnshareFolderReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnshareFolderReq.Marshal(b, m, deterministic)
+}
+func (m *UnshareFolderReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnshareFolderReq.Merge(m, src)
+}
+func (m *UnshareFolderReq) XXX_Size() int {
+	return xxx_messageInfo_UnshareFolderReq.Size(m)
+}
+func (m *UnshareFolderReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnshareFolderReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnshareFolderReq proto.InternalMessageInfo
+
+func (m *UnshareFolderReq) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ListPublicLinksReq struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// recursive lists also the links of the resources under the path
+	Recursive bool `protobuf:"varint,2,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	// file_ids lists only the links of these resources, in place of path
+	FileIds              []string `protobuf:"bytes,3,rep,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListPublicLinksReq) Reset()         { *m = ListPublicLinksReq{} }
+func (m *ListPublicLinksReq) String() string { return proto.CompactTextString(m) }
+func (*ListPublicLinksReq) ProtoMessage()    {}
+func (*ListPublicLinksReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{60}
+}
+
+func (m *ListPublicLinksReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPublicLinksReq.Unmarshal(m, b)
+}
+func (m *ListPublicLinksReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPublicLinksReq.Marshal(b, m, deterministic)
+}
+func (m *ListPublicLinksReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPublicLinksReq.Merge(m, src)
+}
+func (m *ListPublicLinksReq) XXX_Size() int {
+	return xxx_messageInfo_ListPublicLinksReq.Size(m)
+}
+func (m *ListPublicLinksReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPublicLinksReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPublicLinksReq proto.InternalMessageInfo
+
+func (m *ListPublicLinksReq) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ListPublicLinksReq) GetRecursive() bool {
+	if m != nil {
+		return m.Recursive
+	}
+	return false
+}
+
+func (m *ListPublicLinksReq) GetFileIds() []string {
+	if m != nil {
+		return m.FileIds
+	}
+	return nil
+}
+
+type ListFolderSharesReq struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// recursive lists also the shares of the resources under the path
+	Recursive bool `protobuf:"varint,2,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	// file_ids lists only the shares of these resources, in place of path
+	FileIds              []string `protobuf:"bytes,3,rep,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListFolderSharesReq) Reset()         { *m = ListFolderSharesReq{} }
+func (m *ListFolderSharesReq) String() string { return proto.CompactTextString(m) }
+func (*ListFolderSharesReq) ProtoMessage()    {}
+func (*ListFolderSharesReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{61}
+}
+
+func (m *ListFolderSharesReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListFolderSharesReq.Unmarshal(m, b)
+}
+func (m *ListFolderSharesReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListFolderSharesReq.Marshal(b, m, deterministic)
+}
+func (m *ListFolderSharesReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListFolderSharesReq.Merge(m, src)
+}
+func (m *ListFolderSharesReq) XXX_Size() int {
+	return xxx_messageInfo_ListFolderSharesReq.Size(m)
+}
+func (m *ListFolderSharesReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListFolderSharesReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListFolderSharesReq proto.InternalMessageInfo
+
+func (m *ListFolderSharesReq) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ListFolderSharesReq) GetRecursive() bool {
+	if m != nil {
+		return m.Recursive
+	}
+	return false
+}
+
+func (m *ListFolderSharesReq) GetFileIds() []string {
+	if m != nil {
+		return m.FileIds
+	}
+	return nil
+}
+
+type ReceivedShareReq struct {
+	ShareId              string   `protobuf:"bytes,1,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReceivedShareReq) Reset()         { *m = ReceivedShareReq{} }
+func (m *ReceivedShareReq) String() string { return proto.CompactTextString(m) }
+func (*ReceivedShareReq) ProtoMessage()    {}
+func (*ReceivedShareReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{62}
+}
+
+func (m *ReceivedShareReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReceivedShareReq.Unmarshal(m, b)
+}
+func (m *ReceivedShareReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReceivedShareReq.Marshal(b, m, deterministic)
+}
+func (m *ReceivedShareReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReceivedShareReq.Merge(m, src)
+}
+func (m *ReceivedShareReq) XXX_Size() int {
+	return xxx_messageInfo_ReceivedShareReq.Size(m)
+}
+func (m *ReceivedShareReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReceivedShareReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReceivedShareReq proto.InternalMessageInfo
+
+func (m *ReceivedShareReq) GetShareId() string {
+	if m != nil {
+		return m.ShareId
+	}
+	return ""
+}
+
+type RemoteShare struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// host of the remote server
+	Remote string `protobuf:"bytes,2,opt,name=remote,proto3" json:"remote,omitempty"`
+	// id of the share on the remote server
+	RemoteId string `protobuf:"bytes,3,opt,name=remote_id,json=remoteId,proto3" json:"remote_id,omitempty"`
+	// shared secret to access the share on the remote server
+	Token string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	Name  string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	// user@host of the owner on the remote server
+	Owner                string            `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	Recipient            string            `protobuf:"bytes,7,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	State                RemoteShare_State `protobuf:"varint,8,opt,name=state,proto3,enum=api.RemoteShare_State" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RemoteShare) Reset()         { *m = RemoteShare{} }
+func (m *RemoteShare) String() string { return proto.CompactTextString(m) }
+func (*RemoteShare) ProtoMessage()    {}
+func (*RemoteShare) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{63}
+}
+
+func (m *RemoteShare) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoteShare.Unmarshal(m, b)
+}
+func (m *RemoteShare) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoteShare.Marshal(b, m, deterministic)
+}
+func (m *RemoteShare) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoteShare.Merge(m, src)
+}
+func (m *RemoteShare) XXX_Size() int {
+	return xxx_messageInfo_RemoteShare.Size(m)
+}
+func (m *RemoteShare) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoteShare.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoteShare proto.InternalMessageInfo
+
+func (m *RemoteShare) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RemoteShare) GetRemote() string {
+	if m != nil {
+		return m.Remote
+	}
+	return ""
+}
+
+func (m *RemoteShare) GetRemoteId() string {
+	if m != nil {
+		return m.RemoteId
+	}
+	return ""
+}
+
+func (m *RemoteShare) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *RemoteShare) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RemoteShare) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *RemoteShare) GetRecipient() string {
+	if m != nil {
+		return m.Recipient
+	}
+	return ""
+}
+
+func (m *RemoteShare) GetState() RemoteShare_State {
+	if m != nil {
+		return m.State
+	}
+	return RemoteShare_ACCEPTED
+}
+
+type RemoteShareResponse struct {
+	Status               StatusCode   `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Share                *RemoteShare `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *RemoteShareResponse) Reset()         { *m = RemoteShareResponse{} }
+func (m *RemoteShareResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoteShareResponse) ProtoMessage()    {}
+func (*RemoteShareResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{64}
+}
+
+func (m *RemoteShareResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoteShareResponse.Unmarshal(m, b)
+}
+func (m *RemoteShareResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoteShareResponse.Marshal(b, m, deterministic)
+}
+func (m *RemoteShareResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoteShareResponse.Merge(m, src)
+}
+func (m *RemoteShareResponse) XXX_Size() int {
+	return xxx_messageInfo_RemoteShareResponse.Size(m)
+}
+func (m *RemoteShareResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoteShareResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoteShareResponse proto.InternalMessageInfo
+
+func (m *RemoteShareResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *RemoteShareResponse) GetShare() *RemoteShare {
+	if m != nil {
+		return m.Share
+	}
+	return nil
+}
+
+type NewRemoteShareReq struct {
+	Share                *RemoteShare `protobuf:"bytes,1,opt,name=share,proto3" json:"share,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *NewRemoteShareReq) Reset()         { *m = NewRemoteShareReq{} }
+func (m *NewRemoteShareReq) String() string { return proto.CompactTextString(m) }
+func (*NewRemoteShareReq) ProtoMessage()    {}
+func (*NewRemoteShareReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{65}
+}
+
+func (m *NewRemoteShareReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewRemoteShareReq.Unmarshal(m, b)
+}
+func (m *NewRemoteShareReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewRemoteShareReq.Marshal(b, m, deterministic)
+}
+func (m *NewRemoteShareReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewRemoteShareReq.Merge(m, src)
+}
+func (m *NewRemoteShareReq) XXX_Size() int {
+	return xxx_messageInfo_NewRemoteShareReq.Size(m)
+}
+func (m *NewRemoteShareReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewRemoteShareReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewRemoteShareReq proto.InternalMessageInfo
+
+func (m *NewRemoteShareReq) GetShare() *RemoteShare {
+	if m != nil {
+		return m.Share
+	}
+	return nil
+}
+
+type RemoveRemoteShareReq struct {
+	Remote               string   `protobuf:"bytes,1,opt,name=remote,proto3" json:"remote,omitempty"`
+	RemoteId             string   `protobuf:"bytes,2,opt,name=remote_id,json=remoteId,proto3" json:"remote_id,omitempty"`
+	Token                string   `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveRemoteShareReq) Reset()         { *m = RemoveRemoteShareReq{} }
+func (m *RemoveRemoteShareReq) String() string { return proto.CompactTextString(m) }
+func (*RemoveRemoteShareReq) ProtoMessage()    {}
+func (*RemoveRemoteShareReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{66}
+}
+
+func (m *RemoveRemoteShareReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveRemoteShareReq.Unmarshal(m, b)
+}
+func (m *RemoveRemoteShareReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveRemoteShareReq.Marshal(b, m, deterministic)
+}
+func (m *RemoveRemoteShareReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveRemoteShareReq.Merge(m, src)
+}
+func (m *RemoveRemoteShareReq) XXX_Size() int {
+	return xxx_messageInfo_RemoveRemoteShareReq.Size(m)
+}
+func (m *RemoveRemoteShareReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveRemoteShareReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveRemoteShareReq proto.InternalMessageInfo
+
+func (m *RemoveRemoteShareReq) GetRemote() string {
+	if m != nil {
+		return m.Remote
+	}
+	return ""
+}
+
+func (m *RemoveRemoteShareReq) GetRemoteId() string {
+	if m != nil {
+		return m.RemoteId
+	}
+	return ""
+}
+
+func (m *RemoveRemoteShareReq) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ReconcileSharesReq struct {
+	DryRun               bool     `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReconcileSharesReq) Reset()         { *m = ReconcileSharesReq{} }
+func (m *ReconcileSharesReq) String() string { return proto.CompactTextString(m) }
+func (*ReconcileSharesReq) ProtoMessage()    {}
+func (*ReconcileSharesReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{67}
+}
+
+func (m *ReconcileSharesReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReconcileSharesReq.Unmarshal(m, b)
+}
+func (m *ReconcileSharesReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReconcileSharesReq.Marshal(b, m, deterministic)
+}
+func (m *ReconcileSharesReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReconcileSharesReq.Merge(m, src)
+}
+func (m *ReconcileSharesReq) XXX_Size() int {
+	return xxx_messageInfo_ReconcileSharesReq.Size(m)
+}
+func (m *ReconcileSharesReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReconcileSharesReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReconcileSharesReq proto.InternalMessageInfo
+
+func (m *ReconcileSharesReq) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type ACLDriftResponse struct {
+	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Drift                *ACLDrift  `protobuf:"bytes,2,opt,name=drift,proto3" json:"drift,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ACLDriftResponse) Reset()         { *m = ACLDriftResponse{} }
+func (m *ACLDriftResponse) String() string { return proto.CompactTextString(m) }
+func (*ACLDriftResponse) ProtoMessage()    {}
+func (*ACLDriftResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{68}
+}
+
+func (m *ACLDriftResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ACLDriftResponse.Unmarshal(m, b)
+}
+func (m *ACLDriftResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ACLDriftResponse.Marshal(b, m, deterministic)
+}
+func (m *ACLDriftResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ACLDriftResponse.Merge(m, src)
+}
+func (m *ACLDriftResponse) XXX_Size() int {
+	return xxx_messageInfo_ACLDriftResponse.Size(m)
+}
+func (m *ACLDriftResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ACLDriftResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ACLDriftResponse proto.InternalMessageInfo
+
+func (m *ACLDriftResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *ACLDriftResponse) GetDrift() *ACLDrift {
+	if m != nil {
+		return m.Drift
+	}
+	return nil
+}
+
+type ACLDrift struct {
+	Kind                 ACLDrift_Kind   `protobuf:"varint,1,opt,name=kind,proto3,enum=api.ACLDrift_Kind" json:"kind,omitempty"`
+	Path                 string          `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	OwnerId              string          `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	ShareId              string          `protobuf:"bytes,4,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
+	Recipient            *ShareRecipient `protobuf:"bytes,5,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	ReadOnly             bool            `protobuf:"varint,6,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Repaired             bool            `protobuf:"varint,7,opt,name=repaired,proto3" json:"repaired,omitempty"`
+	Error                string          `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ACLDrift) Reset()         { *m = ACLDrift{} }
+func (m *ACLDrift) String() string { return proto.CompactTextString(m) }
+func (*ACLDrift) ProtoMessage()    {}
+func (*ACLDrift) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{69}
+}
+
+func (m *ACLDrift) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ACLDrift.Unmarshal(m, b)
+}
+func (m *ACLDrift) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ACLDrift.Marshal(b, m, deterministic)
+}
+func (m *ACLDrift) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ACLDrift.Merge(m, src)
+}
+func (m *ACLDrift) XXX_Size() int {
+	return xxx_messageInfo_ACLDrift.Size(m)
+}
+func (m *ACLDrift) XXX_DiscardUnknown() {
+	xxx_messageInfo_ACLDrift.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ACLDrift proto.InternalMessageInfo
+
+func (m *ACLDrift) GetKind() ACLDrift_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return ACLDrift_MISSING
+}
+
+func (m *ACLDrift) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ACLDrift) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *ACLDrift) GetShareId() string {
+	if m != nil {
+		return m.ShareId
+	}
+	return ""
+}
+
+func (m *ACLDrift) GetRecipient() *ShareRecipient {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+func (m *ACLDrift) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
+}
+
+func (m *ACLDrift) GetRepaired() bool {
+	if m != nil {
+		return m.Repaired
+	}
+	return false
+}
+
+func (m *ACLDrift) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type RevokeUserTokensReq struct {
+	AccountId            string   `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeUserTokensReq) Reset()         { *m = RevokeUserTokensReq{} }
+func (m *RevokeUserTokensReq) String() string { return proto.CompactTextString(m) }
+func (*RevokeUserTokensReq) ProtoMessage()    {}
+func (*RevokeUserTokensReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{70}
+}
+
+func (m *RevokeUserTokensReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeUserTokensReq.Unmarshal(m, b)
+}
+func (m *RevokeUserTokensReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeUserTokensReq.Marshal(b, m, deterministic)
+}
+func (m *RevokeUserTokensReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeUserTokensReq.Merge(m, src)
+}
+func (m *RevokeUserTokensReq) XXX_Size() int {
+	return xxx_messageInfo_RevokeUserTokensReq.Size(m)
+}
+func (m *RevokeUserTokensReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeUserTokensReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeUserTokensReq proto.InternalMessageInfo
+
+func (m *RevokeUserTokensReq) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+type ImpersonateUserReq struct {
+	AccountId            string   `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImpersonateUserReq) Reset()         { *m = ImpersonateUserReq{} }
+func (m *ImpersonateUserReq) String() string { return proto.CompactTextString(m) }
+func (*ImpersonateUserReq) ProtoMessage()    {}
+func (*ImpersonateUserReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{71}
+}
+
+func (m *ImpersonateUserReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImpersonateUserReq.Unmarshal(m, b)
+}
+func (m *ImpersonateUserReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImpersonateUserReq.Marshal(b, m, deterministic)
+}
+func (m *ImpersonateUserReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImpersonateUserReq.Merge(m, src)
+}
+func (m *ImpersonateUserReq) XXX_Size() int {
+	return xxx_messageInfo_ImpersonateUserReq.Size(m)
+}
+func (m *ImpersonateUserReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImpersonateUserReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImpersonateUserReq proto.InternalMessageInfo
+
+func (m *ImpersonateUserReq) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+type UserReq struct {
+	AccountId            string   `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserReq) Reset()         { *m = UserReq{} }
+func (m *UserReq) String() string { return proto.CompactTextString(m) }
+func (*UserReq) ProtoMessage()    {}
+func (*UserReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{72}
+}
+
+func (m *UserReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserReq.Unmarshal(m, b)
+}
+func (m *UserReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserReq.Marshal(b, m, deterministic)
+}
+func (m *UserReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserReq.Merge(m, src)
+}
+func (m *UserReq) XXX_Size() int {
+	return xxx_messageInfo_UserReq.Size(m)
+}
+func (m *UserReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserReq proto.InternalMessageInfo
+
+func (m *UserReq) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+type GroupReq struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GroupReq) Reset()         { *m = GroupReq{} }
+func (m *GroupReq) String() string { return proto.CompactTextString(m) }
+func (*GroupReq) ProtoMessage()    {}
+func (*GroupReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{73}
+}
+
+func (m *GroupReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupReq.Unmarshal(m, b)
+}
+func (m *GroupReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupReq.Marshal(b, m, deterministic)
+}
+func (m *GroupReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupReq.Merge(m, src)
+}
+func (m *GroupReq) XXX_Size() int {
+	return xxx_messageInfo_GroupReq.Size(m)
+}
+func (m *GroupReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupReq proto.InternalMessageInfo
+
+func (m *GroupReq) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+type SearchReq struct {
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// the entries are sorted by id, offset skips the first ones and limit 0 means no limit
+	Offset               uint64   `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit                uint64   `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchReq) Reset()         { *m = SearchReq{} }
+func (m *SearchReq) String() string { return proto.CompactTextString(m) }
+func (*SearchReq) ProtoMessage()    {}
+func (*SearchReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{74}
+}
+
+func (m *SearchReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchReq.Unmarshal(m, b)
+}
+func (m *SearchReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchReq.Marshal(b, m, deterministic)
+}
+func (m *SearchReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchReq.Merge(m, src)
+}
+func (m *SearchReq) XXX_Size() int {
+	return xxx_messageInfo_SearchReq.Size(m)
+}
+func (m *SearchReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchReq proto.InternalMessageInfo
+
+func (m *SearchReq) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *SearchReq) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *SearchReq) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type DirectoryUser struct {
+	AccountId            string   `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	DisplayName          string   `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Mail                 string   `protobuf:"bytes,3,opt,name=mail,proto3" json:"mail,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DirectoryUser) Reset()         { *m = DirectoryUser{} }
+func (m *DirectoryUser) String() string { return proto.CompactTextString(m) }
+func (*DirectoryUser) ProtoMessage()    {}
+func (*DirectoryUser) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{75}
+}
+
+func (m *DirectoryUser) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DirectoryUser.Unmarshal(m, b)
+}
+func (m *DirectoryUser) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DirectoryUser.Marshal(b, m, deterministic)
+}
+func (m *DirectoryUser) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DirectoryUser.Merge(m, src)
+}
+func (m *DirectoryUser) XXX_Size() int {
+	return xxx_messageInfo_DirectoryUser.Size(m)
+}
+func (m *DirectoryUser) XXX_DiscardUnknown() {
+	xxx_messageInfo_DirectoryUser.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DirectoryUser proto.InternalMessageInfo
+
+func (m *DirectoryUser) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *DirectoryUser) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *DirectoryUser) GetMail() string {
+	if m != nil {
+		return m.Mail
+	}
+	return ""
+}
+
+type DirectoryUserResponse struct {
+	Status               StatusCode     `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	User                 *DirectoryUser `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *DirectoryUserResponse) Reset()         { *m = DirectoryUserResponse{} }
+func (m *DirectoryUserResponse) String() string { return proto.CompactTextString(m) }
+func (*DirectoryUserResponse) ProtoMessage()    {}
+func (*DirectoryUserResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{76}
+}
+
+func (m *DirectoryUserResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DirectoryUserResponse.Unmarshal(m, b)
+}
+func (m *DirectoryUserResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DirectoryUserResponse.Marshal(b, m, deterministic)
+}
+func (m *DirectoryUserResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DirectoryUserResponse.Merge(m, src)
+}
+func (m *DirectoryUserResponse) XXX_Size() int {
+	return xxx_messageInfo_DirectoryUserResponse.Size(m)
+}
+func (m *DirectoryUserResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DirectoryUserResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DirectoryUserResponse proto.InternalMessageInfo
+
+func (m *DirectoryUserResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *DirectoryUserResponse) GetUser() *DirectoryUser {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+type AvatarResponse struct {
+	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Avatar               []byte     `protobuf:"bytes,2,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	MimeType             string     `protobuf:"bytes,3,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *AvatarResponse) Reset()         { *m = AvatarResponse{} }
+func (m *AvatarResponse) String() string { return proto.CompactTextString(m) }
+func (*AvatarResponse) ProtoMessage()    {}
+func (*AvatarResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{77}
+}
+
+func (m *AvatarResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AvatarResponse.Unmarshal(m, b)
+}
+func (m *AvatarResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AvatarResponse.Marshal(b, m, deterministic)
+}
+func (m *AvatarResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AvatarResponse.Merge(m, src)
+}
+func (m *AvatarResponse) XXX_Size() int {
+	return xxx_messageInfo_AvatarResponse.Size(m)
+}
+func (m *AvatarResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AvatarResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AvatarResponse proto.InternalMessageInfo
+
+func (m *AvatarResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *AvatarResponse) GetAvatar() []byte {
+	if m != nil {
+		return m.Avatar
+	}
+	return nil
+}
+
+func (m *AvatarResponse) GetMimeType() string {
+	if m != nil {
+		return m.MimeType
+	}
+	return ""
+}
+
+type DirectoryEntry struct {
+	Type                 DirectoryEntry_EntryType `protobuf:"varint,1,opt,name=type,proto3,enum=api.DirectoryEntry_EntryType" json:"type,omitempty"`
+	Id                   string                   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	DisplayName          string                   `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Mail                 string                   `protobuf:"bytes,4,opt,name=mail,proto3" json:"mail,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *DirectoryEntry) Reset()         { *m = DirectoryEntry{} }
+func (m *DirectoryEntry) String() string { return proto.CompactTextString(m) }
+func (*DirectoryEntry) ProtoMessage()    {}
+func (*DirectoryEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{78}
+}
+
+func (m *DirectoryEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DirectoryEntry.Unmarshal(m, b)
+}
+func (m *DirectoryEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DirectoryEntry.Marshal(b, m, deterministic)
+}
+func (m *DirectoryEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DirectoryEntry.Merge(m, src)
+}
+func (m *DirectoryEntry) XXX_Size() int {
+	return xxx_messageInfo_DirectoryEntry.Size(m)
+}
+func (m *DirectoryEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_DirectoryEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DirectoryEntry proto.InternalMessageInfo
+
+func (m *DirectoryEntry) GetType() DirectoryEntry_EntryType {
+	if m != nil {
+		return m.Type
+	}
+	return DirectoryEntry_USER
+}
+
+func (m *DirectoryEntry) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DirectoryEntry) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *DirectoryEntry) GetMail() string {
+	if m != nil {
+		return m.Mail
+	}
+	return ""
+}
+
+type DirectoryEntryResponse struct {
+	Status               StatusCode      `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Entry                *DirectoryEntry `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *DirectoryEntryResponse) Reset()         { *m = DirectoryEntryResponse{} }
+func (m *DirectoryEntryResponse) String() string { return proto.CompactTextString(m) }
+func (*DirectoryEntryResponse) ProtoMessage()    {}
+func (*DirectoryEntryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{79}
+}
+
+func (m *DirectoryEntryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DirectoryEntryResponse.Unmarshal(m, b)
+}
+func (m *DirectoryEntryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DirectoryEntryResponse.Marshal(b, m, deterministic)
+}
+func (m *DirectoryEntryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DirectoryEntryResponse.Merge(m, src)
+}
+func (m *DirectoryEntryResponse) XXX_Size() int {
+	return xxx_messageInfo_DirectoryEntryResponse.Size(m)
+}
+func (m *DirectoryEntryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DirectoryEntryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DirectoryEntryResponse proto.InternalMessageInfo
+
+func (m *DirectoryEntryResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *DirectoryEntryResponse) GetEntry() *DirectoryEntry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+type GroupMembersResponse struct {
+	Status               StatusCode `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Members              []string   `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GroupMembersResponse) Reset()         { *m = GroupMembersResponse{} }
+func (m *GroupMembersResponse) String() string { return proto.CompactTextString(m) }
+func (*GroupMembersResponse) ProtoMessage()    {}
+func (*GroupMembersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{80}
+}
+
+func (m *GroupMembersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupMembersResponse.Unmarshal(m, b)
+}
+func (m *GroupMembersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupMembersResponse.Marshal(b, m, deterministic)
+}
+func (m *GroupMembersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupMembersResponse.Merge(m, src)
+}
+func (m *GroupMembersResponse) XXX_Size() int {
+	return xxx_messageInfo_GroupMembersResponse.Size(m)
+}
+func (m *GroupMembersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupMembersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupMembersResponse proto.InternalMessageInfo
+
+func (m *GroupMembersResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *GroupMembersResponse) GetMembers() []string {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+type TransferOwnershipReq struct {
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// path in the storage of the from user
+	Path                 string   `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferOwnershipReq) Reset()         { *m = TransferOwnershipReq{} }
+func (m *TransferOwnershipReq) String() string { return proto.CompactTextString(m) }
+func (*TransferOwnershipReq) ProtoMessage()    {}
+func (*TransferOwnershipReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{81}
+}
+
+func (m *TransferOwnershipReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferOwnershipReq.Unmarshal(m, b)
+}
+func (m *TransferOwnershipReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferOwnershipReq.Marshal(b, m, deterministic)
+}
+func (m *TransferOwnershipReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferOwnershipReq.Merge(m, src)
+}
+func (m *TransferOwnershipReq) XXX_Size() int {
+	return xxx_messageInfo_TransferOwnershipReq.Size(m)
+}
+func (m *TransferOwnershipReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferOwnershipReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferOwnershipReq proto.InternalMessageInfo
+
+func (m *TransferOwnershipReq) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *TransferOwnershipReq) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *TransferOwnershipReq) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type TransferredItemResponse struct {
+	Status               StatusCode       `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Item                 *TransferredItem `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *TransferredItemResponse) Reset()         { *m = TransferredItemResponse{} }
+func (m *TransferredItemResponse) String() string { return proto.CompactTextString(m) }
+func (*TransferredItemResponse) ProtoMessage()    {}
+func (*TransferredItemResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{82}
+}
+
+func (m *TransferredItemResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferredItemResponse.Unmarshal(m, b)
+}
+func (m *TransferredItemResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferredItemResponse.Marshal(b, m, deterministic)
+}
+func (m *TransferredItemResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferredItemResponse.Merge(m, src)
+}
+func (m *TransferredItemResponse) XXX_Size() int {
+	return xxx_messageInfo_TransferredItemResponse.Size(m)
+}
+func (m *TransferredItemResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferredItemResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferredItemResponse proto.InternalMessageInfo
+
+func (m *TransferredItemResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *TransferredItemResponse) GetItem() *TransferredItem {
+	if m != nil {
+		return m.Item
+	}
+	return nil
+}
+
+type TransferredItem struct {
+	Kind TransferredItem_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=api.TransferredItem_Kind" json:"kind,omitempty"`
+	// id of the share or link, empty for the data
+	Id      string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	OldPath string `protobuf:"bytes,3,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`
+	// new_path is empty for the shares with the new owner, that are removed
+	NewPath     string          `protobuf:"bytes,4,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	Recipient   *ShareRecipient `protobuf:"bytes,5,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Transferred bool            `protobuf:"varint,6,opt,name=transferred,proto3" json:"transferred,omitempty"`
+	Error       string          `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	// number of files and bytes copied, only for the data
+	Files                uint64   `protobuf:"varint,8,opt,name=files,proto3" json:"files,omitempty"`
+	Size                 uint64   `protobuf:"varint,9,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferredItem) Reset()         { *m = TransferredItem{} }
+func (m *TransferredItem) String() string { return proto.CompactTextString(m) }
+func (*TransferredItem) ProtoMessage()    {}
+func (*TransferredItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{83}
+}
+
+func (m *TransferredItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferredItem.Unmarshal(m, b)
+}
+func (m *TransferredItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferredItem.Marshal(b, m, deterministic)
+}
+func (m *TransferredItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferredItem.Merge(m, src)
+}
+func (m *TransferredItem) XXX_Size() int {
+	return xxx_messageInfo_TransferredItem.Size(m)
+}
+func (m *TransferredItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferredItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferredItem proto.InternalMessageInfo
+
+func (m *TransferredItem) GetKind() TransferredItem_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return TransferredItem_DATA
+}
+
+func (m *TransferredItem) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TransferredItem) GetOldPath() string {
+	if m != nil {
+		return m.OldPath
+	}
+	return ""
+}
+
+func (m *TransferredItem) GetNewPath() string {
+	if m != nil {
+		return m.NewPath
+	}
+	return ""
+}
+
+func (m *TransferredItem) GetRecipient() *ShareRecipient {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+func (m *TransferredItem) GetTransferred() bool {
+	if m != nil {
+		return m.Transferred
+	}
+	return false
+}
+
+func (m *TransferredItem) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *TransferredItem) GetFiles() uint64 {
+	if m != nil {
+		return m.Files
+	}
+	return 0
+}
+
+func (m *TransferredItem) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type NotificationPreferences struct {
+	// opt_out disables the e-mails about shares received by the user
+	OptOut bool `protobuf:"varint,1,opt,name=opt_out,json=optOut,proto3" json:"opt_out,omitempty"`
+	// language of the e-mails, like en or fr, empty means the default one
+	Language             string   `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NotificationPreferences) Reset()         { *m = NotificationPreferences{} }
+func (m *NotificationPreferences) String() string { return proto.CompactTextString(m) }
+func (*NotificationPreferences) ProtoMessage()    {}
+func (*NotificationPreferences) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{84}
+}
+
+func (m *NotificationPreferences) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NotificationPreferences.Unmarshal(m, b)
+}
+func (m *NotificationPreferences) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NotificationPreferences.Marshal(b, m, deterministic)
+}
+func (m *NotificationPreferences) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NotificationPreferences.Merge(m, src)
+}
+func (m *NotificationPreferences) XXX_Size() int {
+	return xxx_messageInfo_NotificationPreferences.Size(m)
+}
+func (m *NotificationPreferences) XXX_DiscardUnknown() {
+	xxx_messageInfo_NotificationPreferences.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NotificationPreferences proto.InternalMessageInfo
+
+func (m *NotificationPreferences) GetOptOut() bool {
+	if m != nil {
+		return m.OptOut
+	}
+	return false
+}
+
+func (m *NotificationPreferences) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+type NotificationPreferencesReq struct {
+	Preferences          *NotificationPreferences `protobuf:"bytes,1,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *NotificationPreferencesReq) Reset()         { *m = NotificationPreferencesReq{} }
+func (m *NotificationPreferencesReq) String() string { return proto.CompactTextString(m) }
+func (*NotificationPreferencesReq) ProtoMessage()    {}
+func (*NotificationPreferencesReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{85}
+}
+
+func (m *NotificationPreferencesReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NotificationPreferencesReq.Unmarshal(m, b)
+}
+func (m *NotificationPreferencesReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NotificationPreferencesReq.Marshal(b, m, deterministic)
+}
+func (m *NotificationPreferencesReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NotificationPreferencesReq.Merge(m, src)
+}
+func (m *NotificationPreferencesReq) XXX_Size() int {
+	return xxx_messageInfo_NotificationPreferencesReq.Size(m)
+}
+func (m *NotificationPreferencesReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_NotificationPreferencesReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NotificationPreferencesReq proto.InternalMessageInfo
+
+func (m *NotificationPreferencesReq) GetPreferences() *NotificationPreferences {
+	if m != nil {
+		return m.Preferences
+	}
+	return nil
+}
+
+type NotificationPreferencesResponse struct {
+	Status               StatusCode               `protobuf:"varint,1,opt,name=status,proto3,enum=api.StatusCode" json:"status,omitempty"`
+	Preferences          *NotificationPreferences `protobuf:"bytes,2,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *NotificationPreferencesResponse) Reset()         { *m = NotificationPreferencesResponse{} }
+func (m *NotificationPreferencesResponse) String() string { return proto.CompactTextString(m) }
+func (*NotificationPreferencesResponse) ProtoMessage()    {}
+func (*NotificationPreferencesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{86}
+}
+
+func (m *NotificationPreferencesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NotificationPreferencesResponse.Unmarshal(m, b)
+}
+func (m *NotificationPreferencesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NotificationPreferencesResponse.Marshal(b, m, deterministic)
+}
+func (m *NotificationPreferencesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NotificationPreferencesResponse.Merge(m, src)
+}
+func (m *NotificationPreferencesResponse) XXX_Size() int {
+	return xxx_messageInfo_NotificationPreferencesResponse.Size(m)
+}
+func (m *NotificationPreferencesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NotificationPreferencesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NotificationPreferencesResponse proto.InternalMessageInfo
+
+func (m *NotificationPreferencesResponse) GetStatus() StatusCode {
+	if m != nil {
+		return m.Status
+	}
+	return StatusCode_OK
+}
+
+func (m *NotificationPreferencesResponse) GetPreferences() *NotificationPreferences {
+	if m != nil {
+		return m.Preferences
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("api.StatusCode", StatusCode_name, StatusCode_value)
+	proto.RegisterEnum("api.Tag_ItemType", Tag_ItemType_name, Tag_ItemType_value)
+	proto.RegisterEnum("api.ShareRecipient_RecipientType", ShareRecipient_RecipientType_name, ShareRecipient_RecipientType_value)
+	proto.RegisterEnum("api.PublicLink_ItemType", PublicLink_ItemType_name, PublicLink_ItemType_value)
+	proto.RegisterEnum("api.FolderShare_State", FolderShare_State_name, FolderShare_State_value)
+	proto.RegisterEnum("api.RemoteShare_State", RemoteShare_State_name, RemoteShare_State_value)
+	proto.RegisterEnum("api.ACLDrift_Kind", ACLDrift_Kind_name, ACLDrift_Kind_value)
+	proto.RegisterEnum("api.DirectoryEntry_EntryType", DirectoryEntry_EntryType_name, DirectoryEntry_EntryType_value)
+	proto.RegisterEnum("api.TransferredItem_Kind", TransferredItem_Kind_name, TransferredItem_Kind_value)
+	proto.RegisterType((*TagReq)(nil), "api.TagReq")
+	proto.RegisterType((*Tag)(nil), "api.Tag")
+	proto.RegisterType((*TagResponse)(nil), "api.TagResponse")
+	proto.RegisterType((*IsPublicLinkProtectedResponse)(nil), "api.IsPublicLinkProtectedResponse")
+	proto.RegisterType((*ForgePublicLinkTokenReq)(nil), "api.ForgePublicLinkTokenReq")
+	proto.RegisterType((*ForgePublicLinkTokenResponse)(nil), "api.ForgePublicLinkTokenResponse")
+	proto.RegisterType((*VerifyPublicLinkTokenReq)(nil), "api.VerifyPublicLinkTokenReq")
+	proto.RegisterType((*VerifyPublicLinkTokenResponse)(nil), "api.VerifyPublicLinkTokenResponse")
+	proto.RegisterType((*EmptyResponse)(nil), "api.EmptyResponse")
+	proto.RegisterType((*EmptyReq)(nil), "api.EmptyReq")
+	proto.RegisterType((*QuotaReq)(nil), "api.QuotaReq")
+	proto.RegisterType((*QuotaResponse)(nil), "api.QuotaResponse")
+	proto.RegisterType((*UserResponse)(nil), "api.UserResponse")
+	proto.RegisterType((*User)(nil), "api.User")
+	proto.RegisterType((*TokenScope)(nil), "api.TokenScope")
+	proto.RegisterType((*ScopedTokenReq)(nil), "api.ScopedTokenReq")
+	proto.RegisterType((*AppPassword)(nil), "api.AppPassword")
+	proto.RegisterType((*NewAppPasswordReq)(nil), "api.NewAppPasswordReq")
+	proto.RegisterType((*AppPasswordReq)(nil), "api.AppPasswordReq")
+	proto.RegisterType((*AppPasswordResponse)(nil), "api.AppPasswordResponse")
+	proto.RegisterType((*TxInfoResponse)(nil), "api.TxInfoResponse")
+	proto.RegisterType((*TxInfo)(nil), "api.TxInfo")
+	proto.RegisterType((*ForgeUserTokenReq)(nil), "api.ForgeUserTokenReq")
+	proto.RegisterType((*RefreshTokenReq)(nil), "api.RefreshTokenReq")
+	proto.RegisterType((*ActivateGuestReq)(nil), "api.ActivateGuestReq")
+	proto.RegisterType((*TokenResponse)(nil), "api.TokenResponse")
+	proto.RegisterType((*TokenReq)(nil), "api.TokenReq")
+	proto.RegisterType((*MetadataResponse)(nil), "api.MetadataResponse")
+	proto.RegisterType((*Metadata)(nil), "api.Metadata")
+	proto.RegisterType((*PathReq)(nil), "api.PathReq")
+	proto.RegisterType((*MoveReq)(nil), "api.MoveReq")
+	proto.RegisterType((*TxChunk)(nil), "api.TxChunk")
+	proto.RegisterType((*WriteSummaryResponse)(nil), "api.WriteSummaryResponse")
+	proto.RegisterType((*WriteSummary)(nil), "api.WriteSummary")
+	proto.RegisterType((*TxEnd)(nil), "api.TxEnd")
+	proto.RegisterType((*DataChunkResponse)(nil), "api.DataChunkResponse")
+	proto.RegisterType((*DataChunk)(nil), "api.DataChunk")
+	proto.RegisterType((*RevisionResponse)(nil), "api.RevisionResponse")
+	proto.RegisterType((*Revision)(nil), "api.Revision")
+	proto.RegisterType((*RevisionReq)(nil), "api.RevisionReq")
+	proto.RegisterType((*RecycleEntryResponse)(nil), "api.RecycleEntryResponse")
+	proto.RegisterType((*RecycleEntry)(nil), "api.RecycleEntry")
+	proto.RegisterType((*RecycleEntryReq)(nil), "api.RecycleEntryReq")
+	proto.RegisterType((*LinkPermissions)(nil), "api.LinkPermissions")
+	proto.RegisterType((*NewLinkReq)(nil), "api.NewLinkReq")
+	proto.RegisterType((*UpdateLinkReq)(nil), "api.UpdateLinkReq")
+	proto.RegisterType((*UploadPolicy)(nil), "api.UploadPolicy")
+	proto.RegisterType((*PublicLinkResponse)(nil), "api.PublicLinkResponse")
+	proto.RegisterType((*ShareRecipient)(nil), "api.ShareRecipient")
+	proto.RegisterType((*ACLReq)(nil), "api.ACLReq")
+	proto.RegisterType((*ACLEntry)(nil), "api.ACLEntry")
+	proto.RegisterType((*PublicLink)(nil), "api.PublicLink")
+	proto.RegisterType((*PublicLinkTokenReq)(nil), "api.PublicLinkTokenReq")
+	proto.RegisterType((*ShareIDReq)(nil), "api.ShareIDReq")
+	proto.RegisterType((*FolderShareResponse)(nil), "api.FolderShareResponse")
+	proto.RegisterType((*FolderShare)(nil), "api.FolderShare")
+	proto.RegisterType((*ReceivedShareResponse)(nil), "api.ReceivedShareResponse")
+	proto.RegisterType((*NewFolderShareReq)(nil), "api.NewFolderShareReq")
+	proto.RegisterType((*UpdateFolderShareReq)(nil), "api.UpdateFolderShareReq")
+	proto.RegisterType((*UnshareFolderReq)(nil), "api.UnshareFolderReq")
+	proto.RegisterType((*ListPublicLinksReq)(nil), "api.ListPublicLinksReq")
+	proto.RegisterType((*ListFolderSharesReq)(nil), "api.ListFolderSharesReq")
+	proto.RegisterType((*ReceivedShareReq)(nil), "api.ReceivedShareReq")
+	proto.RegisterType((*RemoteShare)(nil), "api.RemoteShare")
+	proto.RegisterType((*RemoteShareResponse)(nil), "api.RemoteShareResponse")
+	proto.RegisterType((*NewRemoteShareReq)(nil), "api.NewRemoteShareReq")
+	proto.RegisterType((*RemoveRemoteShareReq)(nil), "api.RemoveRemoteShareReq")
+	proto.RegisterType((*ReconcileSharesReq)(nil), "api.ReconcileSharesReq")
+	proto.RegisterType((*ACLDriftResponse)(nil), "api.ACLDriftResponse")
+	proto.RegisterType((*ACLDrift)(nil), "api.ACLDrift")
+	proto.RegisterType((*RevokeUserTokensReq)(nil), "api.RevokeUserTokensReq")
+	proto.RegisterType((*ImpersonateUserReq)(nil), "api.ImpersonateUserReq")
+	proto.RegisterType((*UserReq)(nil), "api.UserReq")
+	proto.RegisterType((*GroupReq)(nil), "api.GroupReq")
+	proto.RegisterType((*SearchReq)(nil), "api.SearchReq")
+	proto.RegisterType((*DirectoryUser)(nil), "api.DirectoryUser")
+	proto.RegisterType((*DirectoryUserResponse)(nil), "api.DirectoryUserResponse")
+	proto.RegisterType((*AvatarResponse)(nil), "api.AvatarResponse")
+	proto.RegisterType((*DirectoryEntry)(nil), "api.DirectoryEntry")
+	proto.RegisterType((*DirectoryEntryResponse)(nil), "api.DirectoryEntryResponse")
+	proto.RegisterType((*GroupMembersResponse)(nil), "api.GroupMembersResponse")
+	proto.RegisterType((*TransferOwnershipReq)(nil), "api.TransferOwnershipReq")
+	proto.RegisterType((*TransferredItemResponse)(nil), "api.TransferredItemResponse")
+	proto.RegisterType((*TransferredItem)(nil), "api.TransferredItem")
+	proto.RegisterType((*NotificationPreferences)(nil), "api.NotificationPreferences")
+	proto.RegisterType((*NotificationPreferencesReq)(nil), "api.NotificationPreferencesReq")
+	proto.RegisterType((*NotificationPreferencesResponse)(nil), "api.NotificationPreferencesResponse")
+}
+
+func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
+
+var fileDescriptor_00212fb1f9d3bf1c = []byte{
+	// 4701 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x3b, 0x4d, 0x73, 0xe3, 0xc6,
+	0x72, 0xcb, 0x6f, 0xb0, 0xf9, 0x21, 0x68, 0xa4, 0xdd, 0xa5, 0xe4, 0x5d, 0xef, 0x1a, 0xf6, 0xb3,
+	0xf7, 0xf9, 0xd9, 0xeb, 0x7d, 0xb2, 0xfd, 0x9e, 0x3f, 0x12, 0x3b, 0xb4, 0xc8, 0x95, 0xf9, 0x56,
+	0x22, 0x69, 0x90, 0xf2, 0x47, 0x2a, 0x09, 0x83, 0x25, 0x46, 0x5c, 0xbc, 0x25, 0x01, 0x1a, 0x00,
+	0xf5, 0xe1, 0x43, 0x0e, 0x39, 0x25, 0xa9, 0xa4, 0x52, 0xf5, 0x0e, 0x39, 0xe4, 0x07, 0xe4, 0x90,
+	0x4b, 0xae, 0x39, 0xe4, 0xf0, 0x72, 0xcf, 0x21, 0x95, 0x1f, 0x92, 0x4b, 0x0e, 0xa9, 0x4a, 0xe5,
+	0x90, 0xea, 0x99, 0x01, 0x30, 0x00, 0x41, 0x5a, 0x54, 0x52, 0xb9, 0x48, 0x98, 0x9e, 0x9e, 0x9e,
+	0xee, 0x9e, 0xee, 0x9e, 0x9e, 0x9e, 0x21, 0x94, 0x8d, 0xb9, 0xf5, 0x78, 0xee, 0x3a, 0xbe, 0x43,
+	0x72, 0xc6, 0xdc, 0xd2, 0xba, 0x50, 0x1c, 0x1a, 0x13, 0x9d, 0x7e, 0x4f, 0xee, 0x42, 0xc9, 0x37,
+	0x26, 0xa3, 0x97, 0xf4, 0xaa, 0x91, 0x79, 0x98, 0x79, 0x54, 0xd6, 0x8b, 0xbe, 0x31, 0x79, 0x46,
+	0xaf, 0x82, 0x8e, 0x73, 0x63, 0xda, 0xc8, 0x86, 0x1d, 0x5f, 0x1b, 0x53, 0x42, 0x20, 0x3f, 0x37,
+	0xfc, 0x17, 0x8d, 0x1c, 0x83, 0xb2, 0x6f, 0xed, 0xdf, 0x33, 0x90, 0x1b, 0x1a, 0x13, 0x52, 0x87,
+	0xac, 0x65, 0x32, 0x42, 0x39, 0x3d, 0x6b, 0x99, 0xe4, 0x31, 0x94, 0x2d, 0x9f, 0xce, 0x46, 0xfe,
+	0xd5, 0x9c, 0x32, 0x32, 0xf5, 0x83, 0xed, 0xc7, 0xc8, 0xcb, 0xd0, 0x98, 0x3c, 0xee, 0xf8, 0x74,
+	0x36, 0xbc, 0x9a, 0x53, 0x5d, 0xb1, 0xc4, 0x17, 0x51, 0x21, 0xb7, 0xb0, 0x4c, 0x41, 0x1a, 0x3f,
+	0xc9, 0x1b, 0x50, 0x3f, 0xb3, 0xa6, 0x74, 0x64, 0x99, 0xa3, 0xb9, 0x4b, 0xcf, 0xac, 0xcb, 0x46,
+	0x9e, 0x75, 0x56, 0x11, 0xda, 0x31, 0xfb, 0x0c, 0x86, 0xcc, 0x0a, 0xac, 0x46, 0x81, 0x33, 0xcb,
+	0xbb, 0x65, 0xf1, 0x8a, 0x31, 0xf1, 0x5e, 0x81, 0xb2, 0x10, 0x6f, 0x41, 0x1b, 0x25, 0xd6, 0xa5,
+	0x70, 0x01, 0x17, 0x54, 0x7b, 0x08, 0x4a, 0xc0, 0x1c, 0x01, 0x28, 0x3e, 0xed, 0x1d, 0xb7, 0xda,
+	0xba, 0x7a, 0x8b, 0x28, 0x90, 0x7f, 0xda, 0x39, 0x6e, 0xab, 0x19, 0x4d, 0x87, 0x0a, 0x53, 0xa0,
+	0x37, 0x77, 0x6c, 0x8f, 0x92, 0xb7, 0xa0, 0xe8, 0xf9, 0x86, 0xbf, 0xf0, 0x98, 0xec, 0xf5, 0x83,
+	0x2d, 0x26, 0xe4, 0x80, 0x81, 0x0e, 0x1d, 0x93, 0xea, 0xa2, 0x9b, 0xec, 0x43, 0xce, 0x37, 0x26,
+	0x4c, 0x15, 0x95, 0x03, 0x25, 0x50, 0x85, 0x8e, 0x40, 0xed, 0x0c, 0xee, 0x77, 0xbc, 0xfe, 0xe2,
+	0xf9, 0xd4, 0x1a, 0x1f, 0x5b, 0xf6, 0xcb, 0xbe, 0xeb, 0xf8, 0x74, 0xec, 0x53, 0x73, 0xf3, 0x59,
+	0xee, 0x41, 0x79, 0x1e, 0x8c, 0x66, 0x73, 0x29, 0x7a, 0x04, 0xd0, 0x5e, 0xc0, 0xdd, 0xa7, 0x8e,
+	0x3b, 0xa1, 0xd1, 0x54, 0x43, 0xe7, 0x25, 0xb5, 0xd1, 0x1a, 0x76, 0xa1, 0xe0, 0xe3, 0xb7, 0xb0,
+	0x05, 0xde, 0x20, 0xfb, 0xa0, 0xcc, 0x0d, 0xcf, 0xbb, 0x70, 0x5c, 0x53, 0xd8, 0x42, 0xd8, 0x46,
+	0x3d, 0x8e, 0xa7, 0x16, 0xb5, 0xfd, 0x91, 0x35, 0x17, 0xeb, 0xa6, 0x70, 0x40, 0x67, 0xae, 0xfd,
+	0x21, 0xdc, 0x4b, 0x9f, 0x69, 0x53, 0x81, 0x76, 0xa1, 0x70, 0x6e, 0x4c, 0xad, 0x40, 0x18, 0xde,
+	0xd0, 0x9e, 0x40, 0xe3, 0x6b, 0xea, 0x5a, 0x67, 0x57, 0xd7, 0x95, 0x44, 0xfb, 0x01, 0xee, 0xaf,
+	0x18, 0xb1, 0x29, 0x47, 0x4f, 0xa0, 0x32, 0x67, 0x34, 0x46, 0x53, 0xcb, 0x7e, 0x29, 0x16, 0x94,
+	0x63, 0x47, 0xb4, 0x75, 0x98, 0x87, 0xdf, 0xda, 0x47, 0x50, 0x6b, 0xcf, 0xe6, 0xfe, 0xd5, 0xc6,
+	0x73, 0x69, 0x00, 0x8a, 0x18, 0xf9, 0xbd, 0xf6, 0x2a, 0x28, 0x5f, 0x2d, 0x1c, 0xdf, 0x40, 0x19,
+	0x03, 0x4f, 0xcc, 0x48, 0x9e, 0x78, 0x09, 0x35, 0xd1, 0xbf, 0xa9, 0x44, 0x0f, 0xa0, 0xe2, 0x3b,
+	0xbe, 0x31, 0x1d, 0x3d, 0xbf, 0xf2, 0xa9, 0xc7, 0x24, 0xca, 0xe9, 0xc0, 0x40, 0x5f, 0x20, 0x84,
+	0xdc, 0x07, 0x58, 0x78, 0xd4, 0x14, 0xfd, 0x39, 0xd6, 0x5f, 0x46, 0x08, 0xeb, 0xd6, 0xbe, 0x86,
+	0xea, 0xa9, 0x47, 0xdd, 0xcd, 0x27, 0xbe, 0x0f, 0xf9, 0x85, 0x47, 0x5d, 0xa1, 0xc3, 0x32, 0x43,
+	0x63, 0x94, 0x18, 0x58, 0xfb, 0xe7, 0x0c, 0xe4, 0xb1, 0x89, 0xf3, 0x1b, 0xe3, 0xb1, 0xb3, 0x40,
+	0x5b, 0x33, 0x85, 0xd0, 0x65, 0x01, 0xe9, 0x98, 0xe4, 0x0e, 0x14, 0x27, 0xae, 0xb3, 0x98, 0x23,
+	0xeb, 0x39, 0xf4, 0x74, 0xde, 0x22, 0xaf, 0x41, 0xd5, 0xb4, 0xbc, 0xf9, 0xd4, 0xb8, 0x1a, 0xd9,
+	0xc6, 0x8c, 0x0a, 0x23, 0xad, 0x08, 0x58, 0xd7, 0x98, 0x51, 0x34, 0x96, 0xc9, 0x82, 0x7a, 0x3e,
+	0x8b, 0x2d, 0x8a, 0xce, 0x1b, 0xe4, 0x27, 0x50, 0xf0, 0xc6, 0xce, 0x9c, 0x36, 0x0a, 0xd2, 0xe2,
+	0x32, 0x73, 0x19, 0x20, 0x58, 0xe7, 0xbd, 0x44, 0x83, 0xaa, 0x35, 0x9b, 0x53, 0xd7, 0x73, 0x6c,
+	0xc3, 0x77, 0x5c, 0x11, 0x67, 0x62, 0x30, 0xed, 0x4f, 0x00, 0xa2, 0x81, 0xe8, 0x33, 0x2e, 0x35,
+	0xcc, 0x91, 0x63, 0x4f, 0x79, 0xd4, 0x55, 0x74, 0x05, 0x01, 0x3d, 0x7b, 0x7a, 0x15, 0x2e, 0x6a,
+	0x36, 0x5a, 0x54, 0xf2, 0x2a, 0x00, 0xbd, 0x9c, 0x5b, 0xae, 0xe1, 0x5b, 0x8e, 0xcd, 0x04, 0xc8,
+	0xeb, 0x12, 0x04, 0x97, 0xce, 0xa5, 0x9e, 0xb3, 0x70, 0xc7, 0x2c, 0x04, 0xf2, 0x08, 0x09, 0x01,
+	0xa8, 0x63, 0x6a, 0xbf, 0x84, 0x3a, 0x9b, 0xda, 0x0c, 0xfd, 0x23, 0x14, 0x2e, 0xb3, 0x4e, 0x38,
+	0xed, 0xaf, 0x32, 0x50, 0x69, 0xce, 0xe7, 0xfd, 0xc0, 0xdd, 0xa3, 0x00, 0x5f, 0x66, 0x01, 0x7e,
+	0x17, 0x0a, 0x53, 0xe3, 0x39, 0x0d, 0xf6, 0x08, 0xde, 0x88, 0x88, 0xe7, 0xd6, 0x6a, 0x6e, 0x17,
+	0x0a, 0x63, 0xdf, 0x9a, 0x51, 0xc6, 0x70, 0x5e, 0xe7, 0x0d, 0xd4, 0xce, 0xd4, 0xf0, 0xfc, 0x11,
+	0x5a, 0x16, 0x53, 0x7d, 0x5e, 0x57, 0x10, 0x70, 0xea, 0x51, 0x53, 0xeb, 0xc3, 0x76, 0x97, 0x5e,
+	0x48, 0x1c, 0x09, 0x5f, 0xe7, 0x4c, 0x64, 0x52, 0x99, 0xc8, 0xae, 0x95, 0xf0, 0x21, 0xd4, 0x13,
+	0xe4, 0x12, 0x32, 0x6a, 0x7f, 0x9d, 0x81, 0x9d, 0x18, 0xca, 0xa6, 0x06, 0xfe, 0x3e, 0x54, 0x8d,
+	0xf9, 0x7c, 0x14, 0x8b, 0xa1, 0x95, 0x03, 0x95, 0xa1, 0xcb, 0x84, 0x2b, 0x46, 0xd4, 0x88, 0x05,
+	0xdd, 0x5c, 0x3c, 0xe8, 0x6a, 0x7f, 0x04, 0xf5, 0xe1, 0x65, 0xc7, 0x3e, 0x73, 0x36, 0xe7, 0xe5,
+	0x75, 0x28, 0xfa, 0x6c, 0xa8, 0xe0, 0xa2, 0xc2, 0xd5, 0xc2, 0xa9, 0x89, 0x2e, 0xed, 0x3e, 0x14,
+	0x39, 0x84, 0xec, 0x40, 0xc1, 0xbf, 0x8c, 0xdc, 0x2d, 0xef, 0x5f, 0x76, 0x4c, 0xcd, 0x81, 0x6d,
+	0x16, 0xd6, 0xd1, 0x2b, 0x43, 0x83, 0x92, 0x36, 0x82, 0x00, 0x3b, 0xd8, 0x08, 0x4c, 0xf2, 0x3a,
+	0xd4, 0x44, 0xa7, 0x47, 0xc7, 0x2e, 0xf5, 0x85, 0xb9, 0x54, 0x39, 0x70, 0xc0, 0x60, 0xa4, 0x01,
+	0x25, 0x97, 0x9e, 0xb9, 0xd4, 0xe3, 0xb9, 0x85, 0xa2, 0x07, 0x4d, 0xed, 0x17, 0xb0, 0xa5, 0xf3,
+	0xcf, 0x70, 0xba, 0xd7, 0xa1, 0x26, 0x7a, 0x47, 0x72, 0x9c, 0xaf, 0xba, 0x12, 0x9e, 0xd6, 0x02,
+	0xb5, 0x39, 0xf6, 0xad, 0x73, 0xc3, 0xa7, 0x47, 0xe8, 0xd2, 0x37, 0xda, 0xe2, 0x34, 0x0f, 0x6a,
+	0x37, 0xdf, 0xb6, 0xf8, 0x5c, 0x59, 0x79, 0xae, 0x25, 0xd6, 0x73, 0x29, 0xac, 0x3f, 0x04, 0xe5,
+	0x47, 0xf6, 0xb2, 0x33, 0x50, 0x4f, 0xa8, 0x6f, 0x98, 0xc6, 0x4d, 0x82, 0xfd, 0x4f, 0x41, 0x99,
+	0x89, 0xc1, 0xc2, 0x10, 0x6a, 0x0c, 0x35, 0xa4, 0x18, 0x76, 0x6b, 0xff, 0x9d, 0x03, 0x25, 0x00,
+	0x2f, 0xf9, 0x7f, 0x5a, 0xb4, 0x22, 0x90, 0xf7, 0xac, 0x1f, 0xa8, 0x88, 0x53, 0xec, 0x1b, 0x45,
+	0x98, 0xc9, 0xae, 0xce, 0x1a, 0xe4, 0x36, 0x14, 0x2d, 0x6f, 0x64, 0x5a, 0x2e, 0xf3, 0x73, 0x45,
+	0x2f, 0x58, 0x5e, 0xcb, 0x72, 0x91, 0x00, 0xc5, 0x2c, 0x89, 0x47, 0x52, 0xf6, 0x8d, 0x0b, 0x34,
+	0x7e, 0x41, 0xc7, 0x2f, 0xbd, 0xc5, 0x2c, 0x48, 0xd7, 0x82, 0x36, 0x6e, 0x0c, 0x26, 0x75, 0xe9,
+	0xd9, 0x88, 0xb1, 0xa2, 0xb0, 0xde, 0x32, 0x83, 0xf4, 0x91, 0x9f, 0x87, 0x50, 0xb5, 0xbc, 0x51,
+	0x14, 0x71, 0xcb, 0x6c, 0x2e, 0xb0, 0x3c, 0x3d, 0x88, 0xb9, 0xaf, 0x31, 0x0c, 0xef, 0x85, 0xe1,
+	0x52, 0xe3, 0xf9, 0x94, 0x36, 0x80, 0x61, 0x54, 0x2c, 0x6f, 0x10, 0x80, 0x90, 0xa7, 0x19, 0xf2,
+	0x5f, 0xe1, 0x3c, 0xe1, 0x37, 0x66, 0xab, 0xde, 0x95, 0xd7, 0xa8, 0x3e, 0xcc, 0x3c, 0xaa, 0xea,
+	0xf8, 0x89, 0x9c, 0xf8, 0x2e, 0xa5, 0x23, 0xb6, 0x27, 0x35, 0x6a, 0x4c, 0xd6, 0x32, 0x42, 0x0e,
+	0x11, 0x40, 0xf6, 0x40, 0xa1, 0x8e, 0x37, 0xc2, 0xdc, 0xb4, 0x51, 0x67, 0x84, 0x4a, 0xd4, 0xf1,
+	0x9e, 0x5a, 0x53, 0x8a, 0x2c, 0x60, 0x97, 0x65, 0x7b, 0xbe, 0x61, 0x8f, 0x69, 0x63, 0x8b, 0xef,
+	0x52, 0xd4, 0xf1, 0x3a, 0x02, 0x84, 0x28, 0x8c, 0xc5, 0x91, 0x6f, 0xb8, 0x13, 0xea, 0x37, 0x54,
+	0x8e, 0xc2, 0x60, 0x43, 0x06, 0x42, 0x85, 0xce, 0xac, 0x09, 0x7a, 0xe0, 0x36, 0x37, 0x95, 0x99,
+	0x35, 0xe9, 0x98, 0x38, 0x2f, 0x82, 0x99, 0x7a, 0x08, 0x9f, 0x77, 0x66, 0x4d, 0x98, 0x72, 0x1e,
+	0x40, 0x45, 0x10, 0xbd, 0x9a, 0x53, 0xaf, 0xb1, 0xc3, 0xb6, 0x4e, 0xe0, 0x34, 0x11, 0xa2, 0xdd,
+	0x87, 0x12, 0x22, 0xae, 0xca, 0x37, 0x3e, 0x87, 0xd2, 0x89, 0x73, 0x4e, 0xb1, 0x7b, 0x0f, 0x14,
+	0x67, 0x6a, 0x8e, 0x24, 0x94, 0x92, 0x33, 0x35, 0xd9, 0x2c, 0x7b, 0xa0, 0xd8, 0xf4, 0x62, 0x24,
+	0x99, 0x4a, 0xc9, 0xa6, 0x17, 0xd8, 0xa5, 0x3d, 0x87, 0xd2, 0xf0, 0xf2, 0xf0, 0xc5, 0xc2, 0x7e,
+	0x99, 0x1a, 0x6c, 0x70, 0x5b, 0x9f, 0x52, 0x7b, 0x22, 0x06, 0xe6, 0x75, 0xd1, 0x42, 0xb8, 0x73,
+	0x76, 0xe6, 0x51, 0x5f, 0xd8, 0x99, 0x68, 0x21, 0x93, 0xcc, 0xaa, 0xf3, 0x6c, 0x55, 0xd8, 0xb7,
+	0x76, 0x0e, 0xbb, 0xdf, 0xb8, 0x96, 0x4f, 0x07, 0x8b, 0xd9, 0xcc, 0x70, 0x37, 0xcf, 0xc0, 0xc8,
+	0x87, 0x50, 0xbd, 0x90, 0x08, 0x08, 0x97, 0xe1, 0x47, 0x99, 0x18, 0xe5, 0x18, 0x9a, 0x76, 0x04,
+	0x55, 0xb9, 0x17, 0x23, 0x9c, 0x3d, 0x46, 0x51, 0xf9, 0x84, 0x79, 0x3d, 0x68, 0x32, 0xc3, 0x61,
+	0xc9, 0x17, 0xf3, 0x9c, 0xac, 0x30, 0x1c, 0x84, 0x0c, 0xac, 0x1f, 0xa8, 0x76, 0x0c, 0x85, 0xe1,
+	0x65, 0xdb, 0x36, 0xd3, 0x55, 0x94, 0xe6, 0x84, 0xb2, 0xbf, 0xe4, 0xe2, 0xfe, 0xa2, 0xfd, 0x1a,
+	0xb6, 0x5b, 0x86, 0x6f, 0x30, 0xa5, 0x6f, 0xae, 0x8b, 0x77, 0xa0, 0x6c, 0x06, 0xa3, 0x85, 0x22,
+	0xea, 0x0c, 0x37, 0xa2, 0x19, 0x21, 0x68, 0x3d, 0x28, 0x87, 0x70, 0x69, 0x2d, 0x33, 0x2b, 0xd6,
+	0x32, 0x9b, 0xba, 0x96, 0x39, 0x69, 0x2d, 0xcf, 0x40, 0xd5, 0xe9, 0xb9, 0xe5, 0x59, 0x8e, 0x7d,
+	0xa3, 0xb0, 0xe7, 0x8a, 0xc1, 0xb1, 0xb0, 0x17, 0x52, 0x0c, 0xbb, 0x35, 0x13, 0x94, 0x00, 0x8a,
+	0xa7, 0x48, 0x97, 0x9e, 0xcb, 0x87, 0x64, 0x97, 0x9e, 0xe3, 0x29, 0x32, 0x08, 0x75, 0xd9, 0xb4,
+	0x50, 0x97, 0x4b, 0x0f, 0x75, 0x79, 0x29, 0xd4, 0x69, 0x9f, 0x40, 0x25, 0x92, 0x26, 0xd5, 0xc3,
+	0xe4, 0xc9, 0xb3, 0xf2, 0xe4, 0x68, 0xd5, 0x3a, 0x1d, 0x5f, 0x8d, 0xa7, 0xb4, 0x6d, 0xfb, 0x37,
+	0xb4, 0x6a, 0x57, 0x22, 0x10, 0xb3, 0xea, 0x18, 0xe5, 0x18, 0x9a, 0xf6, 0xb7, 0x19, 0xa8, 0xca,
+	0xdd, 0x18, 0x98, 0x5c, 0xea, 0xf9, 0x8e, 0x4b, 0x65, 0xe7, 0xaf, 0x08, 0x58, 0x10, 0x66, 0x02,
+	0x94, 0x48, 0x10, 0x10, 0x20, 0x59, 0x93, 0xf2, 0xa6, 0xf1, 0x0a, 0x94, 0x4d, 0x3a, 0x1d, 0xc9,
+	0x1b, 0x87, 0x62, 0xd2, 0xe9, 0xc9, 0x9a, 0xbd, 0x43, 0x3b, 0x80, 0x2d, 0x99, 0x37, 0x54, 0x6a,
+	0x62, 0xee, 0x4c, 0x72, 0x6e, 0xed, 0x53, 0xd8, 0x62, 0x07, 0x6e, 0xea, 0xce, 0x2c, 0x0f, 0x97,
+	0xc2, 0x43, 0x76, 0x70, 0xc3, 0x10, 0xd9, 0x39, 0xfb, 0xc6, 0x85, 0x65, 0xde, 0x1d, 0x1c, 0x42,
+	0x59, 0x43, 0xfb, 0xb7, 0x2c, 0x40, 0x97, 0x5e, 0x20, 0x81, 0x55, 0x2b, 0x18, 0xcb, 0xf7, 0xb3,
+	0x89, 0x7c, 0x7f, 0x4d, 0x9e, 0x87, 0xf1, 0x82, 0x65, 0xf9, 0xd4, 0x13, 0xe2, 0x07, 0x4d, 0xa6,
+	0x1a, 0xd7, 0x99, 0x73, 0x92, 0x5c, 0x01, 0x0a, 0x02, 0x18, 0xc9, 0x30, 0x5f, 0x28, 0x26, 0xd2,
+	0x8e, 0x99, 0x71, 0x39, 0x32, 0x9d, 0x0b, 0x7b, 0xea, 0x18, 0xa6, 0xc7, 0xb6, 0xd1, 0xbc, 0x5e,
+	0x9d, 0x19, 0x97, 0xad, 0x00, 0x86, 0x4b, 0x89, 0x48, 0x68, 0x91, 0xbe, 0xe3, 0x7a, 0x6c, 0x33,
+	0xcd, 0xeb, 0x95, 0x99, 0x71, 0xf9, 0xb5, 0x00, 0xa1, 0x84, 0xec, 0x1c, 0x55, 0xe6, 0x12, 0xe2,
+	0x37, 0x3a, 0xb0, 0xed, 0xf8, 0xd6, 0xd9, 0x55, 0x03, 0xf8, 0xd9, 0x8b, 0xb7, 0xc8, 0x2f, 0xa0,
+	0xb6, 0x98, 0x23, 0xe5, 0xd1, 0xdc, 0x99, 0x5a, 0xe3, 0xab, 0x46, 0x45, 0x32, 0xb1, 0x53, 0xd6,
+	0xd3, 0x67, 0x1d, 0x7a, 0x75, 0x21, 0xb5, 0xb4, 0x3f, 0x2b, 0x40, 0xed, 0x74, 0x6e, 0x1a, 0x3e,
+	0x0d, 0xf4, 0x9a, 0x4c, 0x3c, 0xde, 0x82, 0xad, 0x05, 0x43, 0x88, 0xa7, 0xd5, 0x8a, 0x5e, 0xe7,
+	0xe0, 0xeb, 0xe4, 0xd1, 0xe4, 0x67, 0xb0, 0x2d, 0x88, 0x48, 0xc7, 0x2b, 0xee, 0x9f, 0x2a, 0xef,
+	0x68, 0x87, 0xf0, 0xc4, 0x21, 0xac, 0xb0, 0x74, 0x08, 0x8b, 0xad, 0x72, 0x31, 0xb1, 0xca, 0x8f,
+	0x40, 0x10, 0x94, 0xf2, 0x90, 0x92, 0xcc, 0x6f, 0x98, 0x8b, 0xc4, 0x56, 0x56, 0x49, 0xac, 0x6c,
+	0x44, 0x26, 0xc2, 0x29, 0xcb, 0x64, 0x5a, 0x01, 0xe6, 0x13, 0xd8, 0x15, 0x98, 0xf1, 0x45, 0xe7,
+	0xa9, 0x0d, 0xe1, 0x7d, 0x27, 0xf2, 0xd2, 0x2f, 0xd9, 0x47, 0x25, 0xc5, 0x3e, 0x1e, 0xc3, 0x8e,
+	0x44, 0x36, 0x34, 0x93, 0x2a, 0xa3, 0xba, 0x1d, 0x52, 0x0d, 0x8d, 0x25, 0x69, 0x4f, 0xb5, 0x65,
+	0x7b, 0x7a, 0x00, 0x15, 0x41, 0x92, 0x99, 0x55, 0x9d, 0x91, 0x02, 0x0e, 0x62, 0xa7, 0xf3, 0xc0,
+	0xe0, 0xb6, 0x24, 0x83, 0x8b, 0xc4, 0x8b, 0xdb, 0x97, 0x2a, 0x8b, 0x27, 0x1b, 0xd8, 0xb2, 0x29,
+	0x6e, 0x5f, 0xcf, 0x14, 0x7f, 0x9b, 0x81, 0x6a, 0x8c, 0x90, 0xc6, 0xf5, 0xc4, 0xea, 0x8d, 0x2c,
+	0x64, 0x65, 0x42, 0x99, 0x30, 0x93, 0xc3, 0xfd, 0x1a, 0xab, 0x96, 0x88, 0xb3, 0xb4, 0xa5, 0xa3,
+	0x32, 0x86, 0xc1, 0xae, 0x8e, 0x1a, 0x37, 0xa6, 0x53, 0xe7, 0x82, 0x9a, 0x22, 0xfb, 0xca, 0x31,
+	0xe7, 0xa9, 0x0a, 0x20, 0xcb, 0xbf, 0xc8, 0x4f, 0x71, 0xc9, 0x71, 0x7a, 0xea, 0x8e, 0xce, 0x9c,
+	0xa9, 0x49, 0x5d, 0x4f, 0x98, 0xe8, 0x56, 0x00, 0x7f, 0xca, 0xc1, 0xe8, 0x85, 0xa6, 0x35, 0xc1,
+	0x3a, 0x06, 0x8f, 0x08, 0xa2, 0xa5, 0xd9, 0x40, 0xa4, 0x9a, 0xd4, 0xc6, 0xdb, 0xc4, 0x7b, 0x20,
+	0x95, 0xb1, 0xae, 0x53, 0xe9, 0xfa, 0xfb, 0x0c, 0xd4, 0x59, 0xe6, 0xac, 0xd3, 0xb1, 0x35, 0xc7,
+	0x13, 0x1e, 0x7a, 0xa1, 0x65, 0x52, 0xdb, 0xb7, 0xfc, 0x20, 0x00, 0x87, 0x6d, 0xf2, 0x21, 0xe4,
+	0xa5, 0xfa, 0xf0, 0x6b, 0x9c, 0x8d, 0xd8, 0xf0, 0xc7, 0xe1, 0x17, 0xab, 0x17, 0x33, 0x74, 0xad,
+	0x0d, 0xb5, 0x18, 0x18, 0xab, 0xb3, 0xa7, 0x03, 0x56, 0xa7, 0x2d, 0x43, 0xe1, 0x48, 0xef, 0x9d,
+	0xf6, 0xd5, 0x0c, 0x03, 0x76, 0x3b, 0xdf, 0xaa, 0x59, 0x2c, 0xe4, 0xea, 0xed, 0x93, 0xde, 0xb0,
+	0xad, 0xe6, 0x18, 0xc2, 0x69, 0x7b, 0x30, 0x54, 0xf3, 0xda, 0xdf, 0x64, 0xa0, 0xd8, 0x3c, 0x3c,
+	0x5e, 0x15, 0xbb, 0x7f, 0x8e, 0x5e, 0x2d, 0x66, 0x11, 0xb2, 0xef, 0xa4, 0x70, 0xa8, 0x47, 0x58,
+	0xf1, 0x40, 0x90, 0x5b, 0x0a, 0x04, 0x45, 0x96, 0x5c, 0xe3, 0x22, 0xe6, 0xc2, 0x2a, 0x00, 0x5f,
+	0x41, 0x4e, 0x52, 0xf4, 0x6b, 0xbf, 0x0f, 0x4a, 0xf3, 0xf0, 0x98, 0xef, 0xb0, 0x31, 0x2e, 0x32,
+	0x9b, 0x73, 0x91, 0xd8, 0x74, 0xb4, 0xbf, 0x2c, 0x00, 0x44, 0x8b, 0x97, 0x56, 0xd5, 0x49, 0x39,
+	0xb7, 0xa6, 0x14, 0xfe, 0xe3, 0x95, 0xe6, 0x7c, 0xa2, 0xd2, 0x2c, 0xef, 0x5f, 0x85, 0xa5, 0xfd,
+	0x6b, 0x75, 0xb0, 0x0c, 0x33, 0xa8, 0x92, 0x9c, 0x41, 0x7d, 0x28, 0xdf, 0x25, 0x28, 0xcc, 0x56,
+	0x1a, 0x09, 0x2b, 0x4c, 0xbb, 0x52, 0xc0, 0x53, 0xc9, 0x85, 0x4d, 0x5d, 0x4c, 0x9a, 0xcb, 0xe2,
+	0x54, 0x82, 0x6d, 0x9e, 0x37, 0xb3, 0xc0, 0x02, 0x52, 0x60, 0x89, 0x85, 0xdf, 0x4a, 0x22, 0xfc,
+	0x2e, 0x85, 0xc8, 0xea, 0x35, 0xb6, 0xd0, 0x94, 0x90, 0x77, 0x0f, 0xca, 0x11, 0x8d, 0x3a, 0xeb,
+	0x8f, 0x00, 0xa8, 0x35, 0xee, 0xd9, 0x1e, 0x0b, 0x79, 0x79, 0x3d, 0x68, 0xa2, 0x17, 0x85, 0x64,
+	0x55, 0xd6, 0xa5, 0x9c, 0x4b, 0x61, 0x94, 0x95, 0xcd, 0x8c, 0xf1, 0x98, 0x7a, 0x1e, 0x8b, 0x6e,
+	0x79, 0x1d, 0x10, 0xd4, 0x64, 0x10, 0x24, 0x7b, 0x4e, 0x5d, 0x96, 0xfa, 0x8a, 0x33, 0xa0, 0x68,
+	0x2e, 0x87, 0xc6, 0x9d, 0x6b, 0x85, 0x46, 0xf2, 0x13, 0xa8, 0xf3, 0x76, 0x58, 0x14, 0xde, 0x65,
+	0xb3, 0xd6, 0x02, 0x28, 0x2f, 0x0c, 0xcb, 0xb7, 0x29, 0xc1, 0x0d, 0xca, 0x2d, 0xe9, 0x5e, 0x25,
+	0xa3, 0xbd, 0x2d, 0x07, 0xa8, 0x1f, 0x29, 0x7b, 0xdc, 0x03, 0x60, 0x46, 0xdf, 0x69, 0xa5, 0xd5,
+	0xea, 0x5c, 0xd8, 0x91, 0x7d, 0x69, 0xe3, 0x58, 0x77, 0x00, 0x95, 0xb3, 0x68, 0x7c, 0xac, 0x52,
+	0x27, 0xd3, 0x95, 0x91, 0xb4, 0xdf, 0x66, 0xa1, 0x22, 0x75, 0x5e, 0xab, 0x46, 0x22, 0x5b, 0x65,
+	0x2e, 0x6e, 0x95, 0x31, 0x5f, 0xcf, 0x6f, 0xee, 0xeb, 0x85, 0x65, 0x6f, 0xe2, 0x55, 0xd6, 0xa2,
+	0x5c, 0x65, 0x4d, 0xf7, 0xb1, 0x3b, 0x50, 0x14, 0xc5, 0x05, 0x25, 0xb8, 0x2d, 0xc3, 0x16, 0x79,
+	0x07, 0x0a, 0xa8, 0x20, 0x9e, 0xf4, 0xd5, 0x0f, 0xee, 0x24, 0x15, 0xc2, 0x54, 0x89, 0x25, 0x55,
+	0xfc, 0xa7, 0x3d, 0x81, 0x02, 0x6b, 0x93, 0x2a, 0x86, 0xb0, 0xc3, 0x76, 0x7f, 0xd8, 0x6e, 0xa9,
+	0xb7, 0x48, 0x05, 0x4a, 0xfd, 0x76, 0xb7, 0xd5, 0xe9, 0x1e, 0xa9, 0x19, 0xec, 0xd2, 0xdb, 0xbf,
+	0x6a, 0x1f, 0x62, 0x57, 0x56, 0x7b, 0x01, 0xb7, 0x75, 0x3a, 0xa6, 0xd6, 0x39, 0x35, 0x6f, 0xb8,
+	0x70, 0x6f, 0x42, 0xc1, 0x5b, 0xbb, 0x64, 0xbc, 0x5b, 0xbb, 0x60, 0x05, 0x64, 0xb9, 0xe3, 0xff,
+	0x27, 0xf0, 0x6b, 0x33, 0xd8, 0xe5, 0x19, 0x6d, 0x62, 0xee, 0xa4, 0xb5, 0xa4, 0x65, 0x8a, 0xd9,
+	0x55, 0x99, 0xe2, 0xea, 0xe9, 0x34, 0x50, 0x4f, 0x6d, 0x26, 0x32, 0x9f, 0x2f, 0xcd, 0x59, 0x9e,
+	0x02, 0x39, 0xb6, 0x3c, 0x3f, 0x72, 0x3d, 0x6f, 0x95, 0x32, 0xee, 0x31, 0x65, 0x2c, 0x5c, 0xcf,
+	0x3a, 0x0f, 0x8e, 0x3f, 0x11, 0x40, 0x3b, 0x82, 0x1d, 0xa4, 0x23, 0x09, 0x76, 0x43, 0x42, 0xef,
+	0x82, 0x9a, 0x30, 0x03, 0x56, 0x55, 0xe2, 0x05, 0xaa, 0x90, 0xf5, 0x12, 0x6b, 0x77, 0x4c, 0xed,
+	0x4f, 0xb3, 0x78, 0x7a, 0x9e, 0x39, 0x3e, 0x4d, 0x77, 0xbc, 0x3b, 0x50, 0x74, 0x59, 0x77, 0x74,
+	0x70, 0xc6, 0x16, 0x57, 0x1c, 0x7e, 0x45, 0xde, 0xa7, 0x70, 0x40, 0x47, 0xda, 0xfb, 0xf2, 0x89,
+	0xbd, 0x8f, 0x6d, 0x15, 0x05, 0x69, 0xab, 0xd8, 0x85, 0x02, 0xf3, 0xd9, 0xe0, 0x98, 0xc5, 0x1a,
+	0x42, 0x42, 0x61, 0x37, 0xbc, 0x52, 0x19, 0x01, 0x22, 0x47, 0x52, 0x24, 0x47, 0x92, 0x64, 0x88,
+	0x3b, 0x92, 0xf6, 0xe3, 0x8e, 0xa4, 0x9d, 0xc1, 0x8e, 0x34, 0xfe, 0xff, 0xc8, 0x71, 0x64, 0x8a,
+	0xc2, 0x71, 0x3e, 0x65, 0x8e, 0x13, 0x9b, 0xea, 0xfb, 0x68, 0x70, 0x66, 0xfd, 0x60, 0x03, 0x4b,
+	0x15, 0x33, 0x56, 0x27, 0x8c, 0x8d, 0x8f, 0x56, 0x28, 0xb3, 0x7a, 0x85, 0xb2, 0xab, 0x56, 0x28,
+	0x27, 0xef, 0x0b, 0xef, 0x02, 0xd1, 0xe9, 0xd8, 0xb1, 0xc7, 0xd6, 0x94, 0xd3, 0xf7, 0xc4, 0xf3,
+	0x06, 0xd3, 0xbd, 0x1a, 0xb9, 0x0b, 0x5b, 0x1c, 0xe5, 0x8b, 0xa6, 0x7b, 0xa5, 0x2f, 0x6c, 0xed,
+	0x8f, 0x41, 0x6d, 0x1e, 0x1e, 0xb7, 0x5c, 0xeb, 0xcc, 0xbf, 0xc9, 0x25, 0x4a, 0xc1, 0xc4, 0x91,
+	0xb1, 0x1a, 0x52, 0x48, 0x8e, 0xf7, 0x69, 0xff, 0x90, 0x05, 0x25, 0x80, 0x91, 0x37, 0x21, 0xff,
+	0xd2, 0xb2, 0x4d, 0x41, 0x98, 0xc4, 0x06, 0x3c, 0x7e, 0x66, 0xd9, 0xa6, 0xce, 0xfa, 0x37, 0xdd,
+	0x2b, 0x64, 0xe7, 0xc8, 0xc7, 0x9c, 0x23, 0x1e, 0xbf, 0x0a, 0x9b, 0xc7, 0xaf, 0xe2, 0x72, 0x9d,
+	0xc2, 0xa5, 0x73, 0xc3, 0x72, 0xa9, 0x29, 0x4e, 0xae, 0x61, 0x1b, 0x57, 0x84, 0xba, 0xae, 0xe3,
+	0x8a, 0x5d, 0x83, 0x37, 0xb4, 0xf7, 0x20, 0x8f, 0x92, 0xa1, 0xb9, 0x9e, 0x74, 0x06, 0x03, 0x34,
+	0xd7, 0x5b, 0x68, 0xc9, 0x3d, 0xbd, 0xff, 0x65, 0xb3, 0xdb, 0x6e, 0xf1, 0x5d, 0xe0, 0xa4, 0x33,
+	0x38, 0x69, 0x0e, 0x0f, 0xbf, 0x54, 0xb3, 0xda, 0x07, 0x68, 0xca, 0xe7, 0xce, 0xcb, 0xe8, 0x62,
+	0x89, 0xad, 0xe1, 0xfa, 0x7b, 0x5f, 0xed, 0x7d, 0x20, 0x9d, 0xf0, 0xae, 0x95, 0xf2, 0x2b, 0xe8,
+	0x1f, 0x1d, 0xf4, 0x08, 0x4a, 0xd7, 0xc4, 0x7c, 0x08, 0xca, 0x11, 0x5e, 0x24, 0x8b, 0x8c, 0x84,
+	0x5d, 0x2a, 0x07, 0x19, 0x09, 0x6b, 0x68, 0x5f, 0x41, 0x79, 0x40, 0x0d, 0x77, 0xfc, 0x42, 0x58,
+	0xb4, 0x78, 0xa7, 0x22, 0x2c, 0x9a, 0xb7, 0x56, 0x96, 0x38, 0xf1, 0xee, 0xd2, 0x9a, 0x59, 0x41,
+	0x15, 0x9b, 0x37, 0x34, 0x0a, 0xb5, 0x96, 0xe5, 0xd2, 0xb1, 0xef, 0xb8, 0x57, 0xd7, 0xb9, 0xfb,
+	0x4e, 0xde, 0x71, 0x67, 0x97, 0xef, 0xb8, 0xf1, 0x02, 0xc3, 0xb0, 0xa6, 0x41, 0xf6, 0x8e, 0xdf,
+	0xb8, 0xed, 0xc6, 0xa6, 0xb9, 0x49, 0xf4, 0x90, 0xef, 0xee, 0xb9, 0x5d, 0xc7, 0x49, 0xb2, 0x7e,
+	0xcd, 0x86, 0x7a, 0xf3, 0xdc, 0xf0, 0x8d, 0x1b, 0x4c, 0x71, 0x07, 0x8a, 0x06, 0x1b, 0xca, 0x26,
+	0xa9, 0xea, 0xa2, 0x85, 0xd6, 0x8a, 0xb7, 0x30, 0xfc, 0x3c, 0x20, 0xa2, 0x38, 0x02, 0x30, 0xcf,
+	0xd4, 0xfe, 0x29, 0x03, 0xf5, 0x90, 0x8f, 0xe0, 0x0c, 0xc5, 0x8f, 0x99, 0x7c, 0xba, 0xfb, 0x71,
+	0x56, 0x19, 0xca, 0x63, 0xf6, 0x37, 0x3a, 0x62, 0x8a, 0x0d, 0x25, 0x1b, 0x6e, 0x28, 0xd7, 0x78,
+	0x4a, 0x10, 0xa8, 0x39, 0x2f, 0xa9, 0xf9, 0x09, 0x94, 0x43, 0xca, 0xe9, 0xa7, 0xd4, 0x3a, 0x00,
+	0x9e, 0x52, 0x47, 0xbc, 0x9d, 0xd5, 0xa6, 0x70, 0x27, 0xce, 0xda, 0x4d, 0x4a, 0xdd, 0x05, 0x2a,
+	0x55, 0x75, 0x77, 0x52, 0xe4, 0xd5, 0x39, 0x86, 0xf6, 0x1d, 0xec, 0x32, 0x13, 0x3f, 0xa1, 0xb3,
+	0xe7, 0xd4, 0xf5, 0x36, 0x9f, 0xab, 0x01, 0xa5, 0x19, 0x1f, 0x2b, 0xde, 0x5e, 0x04, 0x4d, 0xad,
+	0x0b, 0xbb, 0x43, 0xd7, 0xb0, 0xbd, 0x33, 0xea, 0xf6, 0x30, 0x66, 0x79, 0x2f, 0xac, 0xb9, 0xc8,
+	0x0d, 0xce, 0x5c, 0x67, 0x16, 0xe4, 0x06, 0xf8, 0x8d, 0xda, 0xf6, 0x9d, 0x40, 0xdb, 0xbe, 0x93,
+	0xfa, 0xd0, 0x6c, 0x0a, 0x77, 0x03, 0x7a, 0x2e, 0x35, 0xf1, 0x58, 0xb1, 0x39, 0xb7, 0x8f, 0x20,
+	0x8f, 0xa7, 0x43, 0xa1, 0x98, 0x5d, 0x86, 0x96, 0x24, 0xca, 0x30, 0xb4, 0x7f, 0xcd, 0xc2, 0x56,
+	0xa2, 0x87, 0xbc, 0x1b, 0x8b, 0xe4, 0x7b, 0x69, 0xa3, 0xe5, 0x80, 0x9e, 0x34, 0x21, 0xf9, 0x92,
+	0x2c, 0xb7, 0xfa, 0x92, 0x2c, 0x1f, 0xbb, 0x24, 0xbb, 0x49, 0x30, 0x7f, 0x08, 0x15, 0x3f, 0x62,
+	0x4b, 0x84, 0x73, 0x19, 0x14, 0x45, 0xed, 0x92, 0x14, 0xb5, 0x11, 0x8a, 0xa5, 0xad, 0xa0, 0xf4,
+	0xcb, 0x1b, 0x61, 0x79, 0xbe, 0x1c, 0x95, 0xe7, 0xb5, 0xf7, 0x45, 0x7c, 0x57, 0x20, 0xdf, 0x6a,
+	0x0e, 0x9b, 0xea, 0x2d, 0xa2, 0x42, 0x95, 0x9f, 0xe9, 0x46, 0x83, 0x2f, 0x9b, 0x7a, 0x5b, 0xcd,
+	0x90, 0x2d, 0xa8, 0xf4, 0x4f, 0xbf, 0x38, 0xee, 0x1c, 0x8e, 0x8e, 0x3b, 0xdd, 0x67, 0x6a, 0x56,
+	0xeb, 0xc2, 0xdd, 0x2e, 0xd6, 0x86, 0xad, 0x31, 0xab, 0x9a, 0xe2, 0xfb, 0x3d, 0xea, 0x52, 0x7b,
+	0x4c, 0x3d, 0xdc, 0xab, 0x9d, 0xb9, 0x3f, 0x72, 0x16, 0x7e, 0xb0, 0x57, 0x3b, 0x73, 0xbf, 0xb7,
+	0x60, 0xc5, 0xa3, 0xa9, 0x61, 0x4f, 0x16, 0xc6, 0x24, 0x88, 0x6c, 0x61, 0x5b, 0xfb, 0x03, 0xd8,
+	0x5f, 0x41, 0x0f, 0xcd, 0xec, 0x33, 0xa8, 0xcc, 0x23, 0x88, 0xc8, 0x52, 0xee, 0x31, 0xcd, 0xad,
+	0x1a, 0x25, 0x0f, 0xd0, 0xfe, 0x22, 0x03, 0x0f, 0x56, 0x92, 0xdf, 0xd4, 0xee, 0x12, 0xcc, 0x64,
+	0x37, 0x64, 0xe6, 0xed, 0xdf, 0x14, 0x01, 0x22, 0xb2, 0xa4, 0x08, 0xd9, 0xde, 0x33, 0x9e, 0x0d,
+	0x9e, 0x76, 0x9f, 0x75, 0x7b, 0xdf, 0x74, 0xd5, 0x0c, 0xb9, 0x0d, 0xdb, 0x83, 0x61, 0x4f, 0x6f,
+	0x1e, 0xb5, 0x47, 0xdd, 0xde, 0x70, 0xf4, 0xb4, 0x77, 0xda, 0x6d, 0xa9, 0x59, 0xb2, 0x0f, 0x77,
+	0x02, 0x70, 0xf3, 0x58, 0x6f, 0x37, 0x5b, 0xdf, 0x8d, 0xda, 0xdf, 0x76, 0x06, 0xc3, 0x81, 0x9a,
+	0x23, 0xf7, 0xa0, 0x11, 0xf4, 0xf5, 0xdb, 0x3a, 0xdb, 0xa9, 0x7b, 0xdd, 0x56, 0xbb, 0xdb, 0x69,
+	0xb7, 0xd4, 0x3c, 0xd9, 0x83, 0xdb, 0x87, 0xbd, 0xee, 0xb0, 0xfd, 0xed, 0x70, 0x84, 0x61, 0x6b,
+	0xa4, 0xb7, 0xbf, 0x3a, 0xed, 0xe8, 0xed, 0x96, 0x5a, 0xc0, 0xd5, 0xee, 0x37, 0x87, 0x5f, 0x8e,
+	0x3a, 0xdd, 0xaf, 0x9b, 0xc7, 0x9d, 0x96, 0x5a, 0x44, 0x64, 0x69, 0xb5, 0x25, 0x0e, 0x4a, 0x38,
+	0x8b, 0xdc, 0x25, 0xc6, 0x8c, 0x5a, 0xcd, 0x61, 0x5b, 0x55, 0xc8, 0x43, 0xb8, 0x97, 0xd6, 0xdb,
+	0x6f, 0x0e, 0x06, 0xdf, 0xf4, 0xf4, 0x96, 0x5a, 0x46, 0xd2, 0xb2, 0x60, 0x83, 0xd3, 0x7e, 0xbf,
+	0xa7, 0x63, 0x3a, 0x0c, 0x84, 0x40, 0x9d, 0xb1, 0x16, 0x4d, 0x57, 0x21, 0xdb, 0x50, 0x1b, 0xf6,
+	0x9e, 0xb5, 0xbb, 0x21, 0x73, 0x55, 0xd4, 0x81, 0x6c, 0x9c, 0x12, 0x7a, 0x0d, 0xd5, 0x16, 0xc9,
+	0x3e, 0x12, 0xc2, 0xd7, 0x71, 0x08, 0x2f, 0x19, 0x2e, 0x0d, 0xd9, 0x22, 0x6f, 0xc0, 0x43, 0x99,
+	0x65, 0x3e, 0x5b, 0x42, 0xb9, 0x2a, 0xb9, 0x0f, 0x7b, 0x69, 0x82, 0x31, 0x6c, 0x75, 0x9b, 0xdc,
+	0x01, 0x22, 0x77, 0x1f, 0xf7, 0x0e, 0x9f, 0xb5, 0x5b, 0x2a, 0x49, 0x0e, 0x3b, 0xee, 0x9c, 0x74,
+	0x86, 0x23, 0xbd, 0xdd, 0x3c, 0xfc, 0xb2, 0xdd, 0x52, 0x77, 0x56, 0x29, 0xb3, 0xdb, 0x3c, 0x69,
+	0xab, 0xbb, 0xe4, 0x01, 0xbc, 0x12, 0x13, 0x34, 0x9c, 0xb4, 0xa9, 0x1f, 0xb5, 0x87, 0xea, 0x6d,
+	0xa2, 0xc1, 0xab, 0xdd, 0xde, 0xb0, 0xf3, 0xb4, 0x73, 0xd8, 0x1c, 0xa2, 0xbc, 0x01, 0x82, 0xde,
+	0x3e, 0xec, 0xf4, 0x3b, 0xed, 0xee, 0x50, 0xbd, 0x43, 0xee, 0xc2, 0x0e, 0xab, 0x90, 0x26, 0x58,
+	0xbe, 0x9b, 0x5c, 0xaa, 0xd3, 0xfe, 0x71, 0xaf, 0x89, 0xbd, 0xbd, 0xd1, 0x31, 0xd2, 0x57, 0x1b,
+	0xe4, 0x2d, 0x78, 0x3d, 0x0d, 0xe3, 0xbb, 0x3e, 0x57, 0x60, 0xf3, 0xf8, 0xb8, 0xf7, 0x4d, 0xbb,
+	0xa5, 0xee, 0x91, 0x37, 0x41, 0x5b, 0x46, 0xc4, 0x85, 0x6c, 0x9e, 0xb4, 0x23, 0x43, 0xdb, 0xc7,
+	0x65, 0x68, 0xf6, 0xfb, 0xa1, 0x35, 0x48, 0xcb, 0xf0, 0xca, 0xc1, 0xbf, 0x14, 0x20, 0xdf, 0x5c,
+	0xf8, 0x2f, 0xc8, 0x67, 0x50, 0x8f, 0x3f, 0x4a, 0x22, 0x41, 0x95, 0x22, 0xf1, 0x52, 0x69, 0x9f,
+	0x44, 0x2f, 0xc1, 0x02, 0x27, 0xd6, 0x6e, 0x91, 0x8f, 0x80, 0xb4, 0x2c, 0x6f, 0x66, 0xd8, 0xfe,
+	0x54, 0xa2, 0x51, 0x93, 0x71, 0xbf, 0xdf, 0xdf, 0x8e, 0x1e, 0x27, 0x46, 0x23, 0x7f, 0x05, 0xbb,
+	0x69, 0xaf, 0x5c, 0xc9, 0xbd, 0x68, 0xfe, 0xe5, 0xea, 0xd6, 0x0a, 0x2e, 0x5a, 0xd0, 0x08, 0xb9,
+	0x48, 0xd2, 0x4b, 0xf0, 0x72, 0x37, 0x59, 0x82, 0x8f, 0xa8, 0x7c, 0x0a, 0xb5, 0xd8, 0xbb, 0x27,
+	0x72, 0x9b, 0x1f, 0x38, 0x12, 0x6f, 0xa1, 0xd2, 0xc5, 0xf9, 0x0c, 0x0f, 0xe1, 0xec, 0x25, 0x52,
+	0xa4, 0x86, 0x5d, 0x71, 0xb0, 0x8b, 0xbd, 0xc1, 0x5a, 0x21, 0xc2, 0x01, 0xbb, 0xd2, 0x76, 0x5e,
+	0xd2, 0x54, 0xae, 0xf9, 0x98, 0xd8, 0x43, 0x58, 0xed, 0x16, 0x39, 0x82, 0xed, 0x43, 0x97, 0x1a,
+	0x3e, 0x95, 0xdf, 0x1a, 0xf2, 0xf5, 0x5b, 0x7a, 0xee, 0xb7, 0xdf, 0x58, 0x7a, 0x38, 0x17, 0x11,
+	0xfa, 0x1c, 0x54, 0x2c, 0x45, 0x48, 0x9d, 0x1e, 0xa9, 0xc9, 0x53, 0xae, 0x1d, 0xfe, 0x24, 0x43,
+	0x3e, 0x83, 0x6d, 0xce, 0xbd, 0xcc, 0xc9, 0xce, 0xf2, 0x90, 0x55, 0x92, 0xfc, 0x2e, 0xa8, 0x6c,
+	0xc5, 0xa5, 0xe7, 0x96, 0x62, 0x78, 0xfc, 0x01, 0x66, 0xba, 0xf2, 0x0e, 0xfe, 0xab, 0x04, 0xa5,
+	0x81, 0xef, 0xb8, 0xc6, 0x84, 0x92, 0xf7, 0xa0, 0xcc, 0x95, 0x82, 0x6f, 0xa2, 0xaa, 0x7c, 0xb5,
+	0xf9, 0x4b, 0x9c, 0x15, 0x73, 0xbf, 0x03, 0xc5, 0x16, 0x9d, 0x52, 0xac, 0x17, 0x5c, 0x03, 0xfb,
+	0x6d, 0xc8, 0xe3, 0xcb, 0x1d, 0x81, 0x2b, 0x1e, 0xf1, 0xac, 0xc0, 0x7d, 0x02, 0xa5, 0x8e, 0xed,
+	0xcd, 0xe9, 0xd8, 0x4f, 0x90, 0xbe, 0x1d, 0x7f, 0x35, 0x16, 0x8d, 0xf8, 0x10, 0x20, 0xaa, 0x09,
+	0x5d, 0x73, 0xd0, 0x93, 0x0c, 0xf9, 0x00, 0xaa, 0x03, 0xdf, 0x70, 0x7d, 0xf6, 0x6c, 0x66, 0x78,
+	0x99, 0x5c, 0xbb, 0x1d, 0xf9, 0xb5, 0x62, 0x34, 0xd9, 0xc7, 0x00, 0x6c, 0x00, 0x7f, 0x65, 0x52,
+	0x15, 0x48, 0xac, 0xb5, 0xbf, 0xb7, 0xfc, 0x48, 0x27, 0x1c, 0xf8, 0x28, 0x43, 0x7e, 0x0e, 0xb5,
+	0xa7, 0x96, 0x6d, 0x79, 0x2f, 0x82, 0x19, 0x41, 0x8c, 0x6e, 0xdb, 0xe6, 0x0a, 0x65, 0x7c, 0x80,
+	0x2f, 0x43, 0x0c, 0x93, 0x3d, 0xdb, 0x8a, 0x0b, 0x76, 0x27, 0xf1, 0x0e, 0x46, 0x96, 0xec, 0x23,
+	0xa8, 0xa1, 0x42, 0x82, 0xd7, 0x1e, 0x5e, 0xaa, 0x4e, 0x92, 0x2f, 0x5b, 0xd8, 0xc8, 0xdf, 0xc1,
+	0xe7, 0x16, 0x86, 0x19, 0xf4, 0x11, 0x35, 0x81, 0xba, 0x7e, 0xde, 0x8f, 0xf1, 0x41, 0x04, 0x7b,
+	0xea, 0xb0, 0x86, 0x40, 0xba, 0xa0, 0x9f, 0x40, 0x85, 0xb3, 0xcc, 0xde, 0x53, 0x24, 0x18, 0xde,
+	0x5b, 0x7e, 0x26, 0x22, 0x4f, 0xdb, 0x84, 0x9d, 0x70, 0xda, 0x08, 0x25, 0x0c, 0x24, 0xf2, 0xa8,
+	0x55, 0xd3, 0x1f, 0x40, 0x55, 0x80, 0xd2, 0xe6, 0x4f, 0x1f, 0xf3, 0x33, 0x28, 0x0e, 0xa8, 0xdf,
+	0x3c, 0x3c, 0x26, 0x95, 0xa0, 0xc6, 0xb2, 0x1a, 0xf9, 0x31, 0x94, 0x79, 0x49, 0xf6, 0x9a, 0xf8,
+	0xef, 0x82, 0x72, 0x6a, 0x7b, 0xd7, 0x26, 0xff, 0x1e, 0x28, 0x47, 0xd4, 0x67, 0xaf, 0xf1, 0x85,
+	0x1d, 0x07, 0x2f, 0xf7, 0xf7, 0x89, 0xdc, 0x0c, 0x9d, 0xff, 0x37, 0x19, 0xf6, 0xb3, 0x9c, 0x09,
+	0x75, 0xc9, 0x3b, 0x50, 0x3a, 0xa2, 0xfe, 0xd0, 0x98, 0x78, 0xa4, 0x12, 0xfe, 0x4a, 0x84, 0x7e,
+	0xbf, 0xaf, 0x46, 0x0d, 0x49, 0xd9, 0x5c, 0x6a, 0xfc, 0x01, 0x4e, 0x0c, 0x79, 0x8d, 0x14, 0xd7,
+	0x46, 0x3f, 0xf8, 0xcf, 0x22, 0x14, 0x78, 0x79, 0xf5, 0x33, 0x50, 0x79, 0x3c, 0x92, 0x6e, 0x0e,
+	0xb7, 0x82, 0x18, 0x2d, 0xde, 0x69, 0xac, 0xdb, 0x95, 0x9a, 0xa0, 0x72, 0x75, 0x4b, 0xe3, 0x89,
+	0xb8, 0x63, 0x92, 0x9e, 0x7a, 0xac, 0x23, 0xf1, 0x39, 0x6c, 0x8b, 0x38, 0xb4, 0xc4, 0x43, 0x74,
+	0x29, 0xb4, 0x8e, 0xc0, 0xc7, 0xec, 0xf5, 0x98, 0xf3, 0x92, 0xae, 0x1b, 0xbf, 0x6a, 0x8f, 0xda,
+	0x4a, 0x54, 0xcb, 0x09, 0x9f, 0x68, 0xb9, 0x86, 0xbe, 0x86, 0x83, 0x27, 0x19, 0xd2, 0x82, 0x7a,
+	0xd3, 0x34, 0xe5, 0x1b, 0xa3, 0x70, 0xa7, 0x8b, 0xdf, 0x0d, 0x88, 0xad, 0x2a, 0xe5, 0x42, 0x8b,
+	0x65, 0x1d, 0xdb, 0x4b, 0xf7, 0x09, 0x64, 0x4f, 0x52, 0xe7, 0x46, 0xb4, 0xd4, 0x64, 0x01, 0x9f,
+	0x34, 0x42, 0xd9, 0x12, 0x75, 0xfd, 0x75, 0x94, 0x58, 0xb4, 0xaa, 0xc5, 0x2e, 0x1e, 0x44, 0xee,
+	0x91, 0xbc, 0x8c, 0x58, 0xb9, 0x7d, 0xd6, 0x8f, 0xa8, 0x3c, 0xe3, 0xf2, 0xea, 0xac, 0x13, 0xe4,
+	0x90, 0xdf, 0x68, 0xc4, 0x2e, 0x11, 0x96, 0x12, 0x80, 0xfd, 0x20, 0x06, 0x2d, 0xdf, 0x37, 0x89,
+	0xd0, 0x45, 0x4e, 0xb0, 0xae, 0x16, 0xc3, 0x20, 0xb7, 0xd3, 0x46, 0xad, 0x12, 0xe3, 0x10, 0x76,
+	0x4f, 0xed, 0xd9, 0xff, 0x8e, 0xc8, 0xc1, 0x17, 0x50, 0xea, 0xe3, 0x73, 0x44, 0x7a, 0x41, 0x7e,
+	0x89, 0x39, 0x95, 0x61, 0x06, 0xcd, 0x6b, 0xef, 0x3a, 0x07, 0x7f, 0x9e, 0x83, 0x5c, 0xef, 0xf0,
+	0x44, 0xd8, 0x9c, 0x7c, 0x59, 0x12, 0xda, 0x5c, 0xbc, 0x24, 0x2f, 0xd4, 0x9b, 0x72, 0xa5, 0xc0,
+	0xb2, 0xd3, 0xed, 0xa5, 0x32, 0x3e, 0xd9, 0x0b, 0x07, 0x24, 0xcb, 0xfb, 0x2b, 0x94, 0x23, 0x72,
+	0x34, 0x09, 0x77, 0x45, 0x8e, 0x96, 0xca, 0xc4, 0x93, 0x8c, 0x30, 0x12, 0x99, 0x87, 0x15, 0x46,
+	0x92, 0x2e, 0xc5, 0x27, 0xb0, 0x8d, 0x57, 0xe2, 0xf3, 0xf5, 0x14, 0x56, 0x6d, 0x89, 0xdb, 0x3a,
+	0xfd, 0x35, 0x1d, 0xdf, 0x60, 0xec, 0xc1, 0xdf, 0x65, 0xa1, 0xd0, 0x34, 0x67, 0x96, 0x4d, 0xda,
+	0xb0, 0x95, 0xb8, 0xab, 0x10, 0xa1, 0x64, 0xf9, 0x06, 0x63, 0xff, 0x76, 0xec, 0xae, 0x20, 0xa6,
+	0x87, 0x3e, 0x6c, 0x2f, 0x15, 0xd7, 0x48, 0xbc, 0x22, 0x25, 0x17, 0xdd, 0xf6, 0xef, 0xa5, 0x96,
+	0xba, 0x64, 0x8a, 0x5f, 0x04, 0xe1, 0x31, 0xaa, 0xc0, 0x93, 0x40, 0x95, 0x4b, 0x85, 0xf9, 0x15,
+	0x2a, 0xfa, 0x3d, 0xd8, 0x4a, 0xd4, 0xe3, 0x85, 0x70, 0xcb, 0x55, 0xfa, 0x15, 0x49, 0xf0, 0x3f,
+	0x66, 0xa0, 0x2a, 0x57, 0x44, 0xc8, 0x00, 0xf6, 0x8f, 0xa8, 0xbf, 0xaa, 0x6e, 0x94, 0xb0, 0x9d,
+	0x37, 0xd6, 0x56, 0x54, 0x22, 0x3e, 0x07, 0xb0, 0x3f, 0x58, 0x4d, 0xf4, 0xc1, 0x7a, 0x2a, 0xab,
+	0xd6, 0xf8, 0x3f, 0x32, 0x50, 0x0e, 0x8b, 0xac, 0xe4, 0x43, 0xb6, 0x8b, 0x33, 0x15, 0x54, 0xa5,
+	0xa3, 0x56, 0x10, 0x82, 0x52, 0x6b, 0xef, 0x2c, 0xc1, 0xac, 0x89, 0x61, 0xbc, 0x66, 0x9e, 0x18,
+	0x2c, 0x4e, 0x23, 0xb1, 0x72, 0x3a, 0xdb, 0xda, 0x8a, 0xfc, 0x1a, 0x82, 0xf0, 0xe7, 0xd8, 0xe1,
+	0x9d, 0xc4, 0xfe, 0x2b, 0x69, 0xb5, 0xdf, 0xf8, 0xa1, 0x87, 0x79, 0xa4, 0x5c, 0x04, 0x16, 0x5a,
+	0x0d, 0xae, 0x3e, 0xf6, 0xf7, 0xa2, 0x66, 0xa2, 0x4c, 0xac, 0xdd, 0x7a, 0x5e, 0x64, 0x3f, 0x2d,
+	0x7e, 0xff, 0x7f, 0x06, 0x00, 0x9c, 0xad, 0xa5, 0x06, 0x67, 0x3c, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AuthClient is the client API for Auth service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuthClient interface {
+	ForgeUserToken(ctx context.Context, in *ForgeUserTokenReq, opts ...grpc.CallOption) (*TokenResponse, error)
+	DismantleUserToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*UserResponse, error)
+	ForgePublicLinkToken(ctx context.Context, in *ForgePublicLinkTokenReq, opts ...grpc.CallOption) (*TokenResponse, error)
+	DismantlePublicLinkToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*PublicLinkResponse, error)
+	ActivateGuest(ctx context.Context, in *ActivateGuestReq, opts ...grpc.CallOption) (*UserResponse, error)
+	// exchanges a refresh token, which is revoked, for a new user token and refresh token
+	RefreshUserToken(ctx context.Context, in *RefreshTokenReq, opts ...grpc.CallOption) (*TokenResponse, error)
+	// revokes a user token or a refresh token until it expires
+	RevokeToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	// app passwords let clients and scripts log in without the password of the user,
+	// the password of a new one is only returned when it is created
+	CreateAppPassword(ctx context.Context, in *NewAppPasswordReq, opts ...grpc.CallOption) (*AppPasswordResponse, error)
+	ListAppPasswords(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (Auth_ListAppPasswordsClient, error)
+	RevokeAppPassword(ctx context.Context, in *AppPasswordReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	// forges a short lived token of the user restricted to the scope, to hand to apps
+	ForgeScopedToken(ctx context.Context, in *ScopedTokenReq, opts ...grpc.CallOption) (*TokenResponse, error)
+}
+
+type authClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuthClient(cc *grpc.ClientConn) AuthClient {
+	return &authClient{cc}
+}
+
+func (c *authClient) ForgeUserToken(ctx context.Context, in *ForgeUserTokenReq, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/ForgeUserToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) DismantleUserToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*UserResponse, error) {
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/DismantleUserToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ForgePublicLinkToken(ctx context.Context, in *ForgePublicLinkTokenReq, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/ForgePublicLinkToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) DismantlePublicLinkToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*PublicLinkResponse, error) {
+	out := new(PublicLinkResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/DismantlePublicLinkToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ActivateGuest(ctx context.Context, in *ActivateGuestReq, opts ...grpc.CallOption) (*UserResponse, error) {
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/ActivateGuest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RefreshUserToken(ctx context.Context, in *RefreshTokenReq, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/RefreshUserToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeToken(ctx context.Context, in *TokenReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/RevokeToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) CreateAppPassword(ctx context.Context, in *NewAppPasswordReq, opts ...grpc.CallOption) (*AppPasswordResponse, error) {
+	out := new(AppPasswordResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/CreateAppPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListAppPasswords(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (Auth_ListAppPasswordsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[0], "/api.Auth/ListAppPasswords", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authListAppPasswordsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_ListAppPasswordsClient interface {
+	Recv() (*AppPasswordResponse, error)
+	grpc.ClientStream
+}
+
+type authListAppPasswordsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authListAppPasswordsClient) Recv() (*AppPasswordResponse, error) {
+	m := new(AppPasswordResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) RevokeAppPassword(ctx context.Context, in *AppPasswordReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/RevokeAppPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ForgeScopedToken(ctx context.Context, in *ScopedTokenReq, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/api.Auth/ForgeScopedToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthServer is the server API for Auth service.
+type AuthServer interface {
+	ForgeUserToken(context.Context, *ForgeUserTokenReq) (*TokenResponse, error)
+	DismantleUserToken(context.Context, *TokenReq) (*UserResponse, error)
+	ForgePublicLinkToken(context.Context, *ForgePublicLinkTokenReq) (*TokenResponse, error)
+	DismantlePublicLinkToken(context.Context, *TokenReq) (*PublicLinkResponse, error)
+	ActivateGuest(context.Context, *ActivateGuestReq) (*UserResponse, error)
+	// exchanges a refresh token, which is revoked, for a new user token and refresh token
+	RefreshUserToken(context.Context, *RefreshTokenReq) (*TokenResponse, error)
+	// revokes a user token or a refresh token until it expires
+	RevokeToken(context.Context, *TokenReq) (*EmptyResponse, error)
+	// app passwords let clients and scripts log in without the password of the user,
+	// the password of a new one is only returned when it is created
+	CreateAppPassword(context.Context, *NewAppPasswordReq) (*AppPasswordResponse, error)
+	ListAppPasswords(*EmptyReq, Auth_ListAppPasswordsServer) error
+	RevokeAppPassword(context.Context, *AppPasswordReq) (*EmptyResponse, error)
+	// forges a short lived token of the user restricted to the scope, to hand to apps
+	ForgeScopedToken(context.Context, *ScopedTokenReq) (*TokenResponse, error)
+}
+
+func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
+	s.RegisterService(&_Auth_serviceDesc, srv)
+}
+
+func _Auth_ForgeUserToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForgeUserTokenReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ForgeUserToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/ForgeUserToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ForgeUserToken(ctx, req.(*ForgeUserTokenReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_DismantleUserToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).DismantleUserToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/DismantleUserToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DismantleUserToken(ctx, req.(*TokenReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ForgePublicLinkToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForgePublicLinkTokenReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ForgePublicLinkToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/ForgePublicLinkToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ForgePublicLinkToken(ctx, req.(*ForgePublicLinkTokenReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_DismantlePublicLinkToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).DismantlePublicLinkToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/DismantlePublicLinkToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DismantlePublicLinkToken(ctx, req.(*TokenReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ActivateGuest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActivateGuestReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ActivateGuest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/ActivateGuest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ActivateGuest(ctx, req.(*ActivateGuestReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RefreshUserToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RefreshUserToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/RefreshUserToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RefreshUserToken(ctx, req.(*RefreshTokenReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RevokeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/RevokeToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeToken(ctx, req.(*TokenReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_CreateAppPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewAppPasswordReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).CreateAppPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/CreateAppPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).CreateAppPassword(ctx, req.(*NewAppPasswordReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListAppPasswords_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EmptyReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServer).ListAppPasswords(m, &authListAppPasswordsServer{stream})
+}
+
+type Auth_ListAppPasswordsServer interface {
+	Send(*AppPasswordResponse) error
+	grpc.ServerStream
+}
+
+type authListAppPasswordsServer struct {
+	grpc.ServerStream
+}
+
+func (x *authListAppPasswordsServer) Send(m *AppPasswordResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Auth_RevokeAppPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppPasswordReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeAppPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/RevokeAppPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeAppPassword(ctx, req.(*AppPasswordReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ForgeScopedToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScopedTokenReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ForgeScopedToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Auth/ForgeScopedToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ForgeScopedToken(ctx, req.(*ScopedTokenReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Auth_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.Auth",
+	HandlerType: (*AuthServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ForgeUserToken",
+			Handler:    _Auth_ForgeUserToken_Handler,
+		},
+		{
+			MethodName: "DismantleUserToken",
+			Handler:    _Auth_DismantleUserToken_Handler,
+		},
+		{
+			MethodName: "ForgePublicLinkToken",
+			Handler:    _Auth_ForgePublicLinkToken_Handler,
+		},
+		{
+			MethodName: "DismantlePublicLinkToken",
+			Handler:    _Auth_DismantlePublicLinkToken_Handler,
+		},
+		{
+			MethodName: "ActivateGuest",
+			Handler:    _Auth_ActivateGuest_Handler,
+		},
+		{
+			MethodName: "RefreshUserToken",
+			Handler:    _Auth_RefreshUserToken_Handler,
+		},
+		{
+			MethodName: "RevokeToken",
+			Handler:    _Auth_RevokeToken_Handler,
+		},
+		{
+			MethodName: "CreateAppPassword",
+			Handler:    _Auth_CreateAppPassword_Handler,
+		},
+		{
+			MethodName: "RevokeAppPassword",
+			Handler:    _Auth_RevokeAppPassword_Handler,
+		},
+		{
+			MethodName: "ForgeScopedToken",
+			Handler:    _Auth_ForgeScopedToken_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListAppPasswords",
+			Handler:       _Auth_ListAppPasswords_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
+}
+
+// StorageClient is the client API for Storage service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StorageClient interface {
+	CreateDir(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	Delete(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	Move(ctx context.Context, in *MoveReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	Inspect(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*MetadataResponse, error)
+	ListFolder(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListFolderClient, error)
+	StartWriteTx(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (*TxInfoResponse, error)
+	WriteChunk(ctx context.Context, opts ...grpc.CallOption) (Storage_WriteChunkClient, error)
+	FinishWriteTx(ctx context.Context, in *TxEnd, opts ...grpc.CallOption) (*EmptyResponse, error)
+	ReadFile(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ReadFileClient, error)
+	ListRevisions(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListRevisionsClient, error)
+	ReadRevision(ctx context.Context, in *RevisionReq, opts ...grpc.CallOption) (Storage_ReadRevisionClient, error)
+	RestoreRevision(ctx context.Context, in *RevisionReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	ListRecycle(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListRecycleClient, error)
+	RestoreRecycleEntry(ctx context.Context, in *RecycleEntryReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	EmptyRecycle(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	SetACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	UpdateACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	UnsetACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	GetQuota(ctx context.Context, in *QuotaReq, opts ...grpc.CallOption) (*QuotaResponse, error)
+}
+
+type storageClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStorageClient(cc *grpc.ClientConn) StorageClient {
+	return &storageClient{cc}
+}
+
+func (c *storageClient) CreateDir(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/CreateDir", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Delete(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Move(ctx context.Context, in *MoveReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/Move", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Inspect(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*MetadataResponse, error) {
+	out := new(MetadataResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/Inspect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ListFolder(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListFolderClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[0], "/api.Storage/ListFolder", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageListFolderClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ListFolderClient interface {
+	Recv() (*MetadataResponse, error)
+	grpc.ClientStream
+}
+
+type storageListFolderClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageListFolderClient) Recv() (*MetadataResponse, error) {
+	m := new(MetadataResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) StartWriteTx(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (*TxInfoResponse, error) {
+	out := new(TxInfoResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/StartWriteTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) WriteChunk(ctx context.Context, opts ...grpc.CallOption) (Storage_WriteChunkClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[1], "/api.Storage/WriteChunk", opts...)
+	if err != nil {
+		return nil, err
+	}
 	x := &storageWriteChunkClient{stream}
 	return x, nil
 }
 
-type Storage_WriteChunkClient interface {
-	Send(*TxChunk) error
-	CloseAndRecv() (*WriteSummaryResponse, error)
-	grpc.ClientStream
+type Storage_WriteChunkClient interface {
+	Send(*TxChunk) error
+	CloseAndRecv() (*WriteSummaryResponse, error)
+	grpc.ClientStream
+}
+
+type storageWriteChunkClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageWriteChunkClient) Send(m *TxChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *storageWriteChunkClient) CloseAndRecv() (*WriteSummaryResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(WriteSummaryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) FinishWriteTx(ctx context.Context, in *TxEnd, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/FinishWriteTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ReadFile(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ReadFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[2], "/api.Storage/ReadFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageReadFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ReadFileClient interface {
+	Recv() (*DataChunkResponse, error)
+	grpc.ClientStream
+}
+
+type storageReadFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageReadFileClient) Recv() (*DataChunkResponse, error) {
+	m := new(DataChunkResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) ListRevisions(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListRevisionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[3], "/api.Storage/ListRevisions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageListRevisionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ListRevisionsClient interface {
+	Recv() (*RevisionResponse, error)
+	grpc.ClientStream
+}
+
+type storageListRevisionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageListRevisionsClient) Recv() (*RevisionResponse, error) {
+	m := new(RevisionResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) ReadRevision(ctx context.Context, in *RevisionReq, opts ...grpc.CallOption) (Storage_ReadRevisionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[4], "/api.Storage/ReadRevision", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageReadRevisionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ReadRevisionClient interface {
+	Recv() (*DataChunkResponse, error)
+	grpc.ClientStream
+}
+
+type storageReadRevisionClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageReadRevisionClient) Recv() (*DataChunkResponse, error) {
+	m := new(DataChunkResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) RestoreRevision(ctx context.Context, in *RevisionReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/RestoreRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ListRecycle(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListRecycleClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[5], "/api.Storage/ListRecycle", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageListRecycleClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ListRecycleClient interface {
+	Recv() (*RecycleEntryResponse, error)
+	grpc.ClientStream
+}
+
+type storageListRecycleClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageListRecycleClient) Recv() (*RecycleEntryResponse, error) {
+	m := new(RecycleEntryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) RestoreRecycleEntry(ctx context.Context, in *RecycleEntryReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/RestoreRecycleEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) EmptyRecycle(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/EmptyRecycle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/SetACL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) UpdateACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/UpdateACL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) UnsetACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/UnsetACL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetQuota(ctx context.Context, in *QuotaReq, opts ...grpc.CallOption) (*QuotaResponse, error) {
+	out := new(QuotaResponse)
+	err := c.cc.Invoke(ctx, "/api.Storage/GetQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StorageServer is the server API for Storage service.
+type StorageServer interface {
+	CreateDir(context.Context, *PathReq) (*EmptyResponse, error)
+	Delete(context.Context, *PathReq) (*EmptyResponse, error)
+	Move(context.Context, *MoveReq) (*EmptyResponse, error)
+	Inspect(context.Context, *PathReq) (*MetadataResponse, error)
+	ListFolder(*PathReq, Storage_ListFolderServer) error
+	StartWriteTx(context.Context, *EmptyReq) (*TxInfoResponse, error)
+	WriteChunk(Storage_WriteChunkServer) error
+	FinishWriteTx(context.Context, *TxEnd) (*EmptyResponse, error)
+	ReadFile(*PathReq, Storage_ReadFileServer) error
+	ListRevisions(*PathReq, Storage_ListRevisionsServer) error
+	ReadRevision(*RevisionReq, Storage_ReadRevisionServer) error
+	RestoreRevision(context.Context, *RevisionReq) (*EmptyResponse, error)
+	ListRecycle(*PathReq, Storage_ListRecycleServer) error
+	RestoreRecycleEntry(context.Context, *RecycleEntryReq) (*EmptyResponse, error)
+	EmptyRecycle(context.Context, *PathReq) (*EmptyResponse, error)
+	SetACL(context.Context, *ACLReq) (*EmptyResponse, error)
+	UpdateACL(context.Context, *ACLReq) (*EmptyResponse, error)
+	UnsetACL(context.Context, *ACLReq) (*EmptyResponse, error)
+	GetQuota(context.Context, *QuotaReq) (*QuotaResponse, error)
+}
+
+func RegisterStorageServer(s *grpc.Server, srv StorageServer) {
+	s.RegisterService(&_Storage_serviceDesc, srv)
+}
+
+func _Storage_CreateDir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).CreateDir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/CreateDir",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).CreateDir(ctx, req.(*PathReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Delete(ctx, req.(*PathReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Move(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/Move",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Move(ctx, req.(*MoveReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Inspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/Inspect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Inspect(ctx, req.(*PathReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ListFolder_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PathReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ListFolder(m, &storageListFolderServer{stream})
+}
+
+type Storage_ListFolderServer interface {
+	Send(*MetadataResponse) error
+	grpc.ServerStream
+}
+
+type storageListFolderServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageListFolderServer) Send(m *MetadataResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Storage_StartWriteTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).StartWriteTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/StartWriteTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).StartWriteTx(ctx, req.(*EmptyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_WriteChunk_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StorageServer).WriteChunk(&storageWriteChunkServer{stream})
+}
+
+type Storage_WriteChunkServer interface {
+	SendAndClose(*WriteSummaryResponse) error
+	Recv() (*TxChunk, error)
+	grpc.ServerStream
+}
+
+type storageWriteChunkServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageWriteChunkServer) SendAndClose(m *WriteSummaryResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *storageWriteChunkServer) Recv() (*TxChunk, error) {
+	m := new(TxChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Storage_FinishWriteTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxEnd)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).FinishWriteTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/FinishWriteTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).FinishWriteTx(ctx, req.(*TxEnd))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ReadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PathReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ReadFile(m, &storageReadFileServer{stream})
+}
+
+type Storage_ReadFileServer interface {
+	Send(*DataChunkResponse) error
+	grpc.ServerStream
+}
+
+type storageReadFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageReadFileServer) Send(m *DataChunkResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Storage_ListRevisions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PathReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ListRevisions(m, &storageListRevisionsServer{stream})
+}
+
+type Storage_ListRevisionsServer interface {
+	Send(*RevisionResponse) error
+	grpc.ServerStream
+}
+
+type storageListRevisionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageListRevisionsServer) Send(m *RevisionResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Storage_ReadRevision_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RevisionReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ReadRevision(m, &storageReadRevisionServer{stream})
 }
 
-type storageWriteChunkClient struct {
-	grpc.ClientStream
+type Storage_ReadRevisionServer interface {
+	Send(*DataChunkResponse) error
+	grpc.ServerStream
 }
 
-func (x *storageWriteChunkClient) Send(m *TxChunk) error {
-	return x.ClientStream.SendMsg(m)
+type storageReadRevisionServer struct {
+	grpc.ServerStream
 }
 
-func (x *storageWriteChunkClient) CloseAndRecv() (*WriteSummaryResponse, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
+func (x *storageReadRevisionServer) Send(m *DataChunkResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Storage_RestoreRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevisionReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	m := new(WriteSummaryResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(StorageServer).RestoreRevision(ctx, in)
 	}
-	return m, nil
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/RestoreRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).RestoreRevision(ctx, req.(*RevisionReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *storageClient) FinishWriteTx(ctx context.Context, in *TxEnd, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/FinishWriteTx", in, out, opts...)
-	if err != nil {
-		return nil, err
+func _Storage_ListRecycle_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PathReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return out, nil
+	return srv.(StorageServer).ListRecycle(m, &storageListRecycleServer{stream})
 }
 
-func (c *storageClient) ReadFile(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ReadFileClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[2], "/api.Storage/ReadFile", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &storageReadFileClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
+type Storage_ListRecycleServer interface {
+	Send(*RecycleEntryResponse) error
+	grpc.ServerStream
 }
 
-type Storage_ReadFileClient interface {
-	Recv() (*DataChunkResponse, error)
-	grpc.ClientStream
+type storageListRecycleServer struct {
+	grpc.ServerStream
 }
 
-type storageReadFileClient struct {
-	grpc.ClientStream
+func (x *storageListRecycleServer) Send(m *RecycleEntryResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (x *storageReadFileClient) Recv() (*DataChunkResponse, error) {
-	m := new(DataChunkResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _Storage_RestoreRecycleEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecycleEntryReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(StorageServer).RestoreRecycleEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/RestoreRecycleEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).RestoreRecycleEntry(ctx, req.(*RecycleEntryReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *storageClient) ListRevisions(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListRevisionsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[3], "/api.Storage/ListRevisions", opts...)
-	if err != nil {
+func _Storage_EmptyRecycle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &storageListRevisionsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(StorageServer).EmptyRecycle(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/EmptyRecycle",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).EmptyRecycle(ctx, req.(*PathReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Storage_ListRevisionsClient interface {
-	Recv() (*RevisionResponse, error)
-	grpc.ClientStream
+func _Storage_SetACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ACLReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/SetACL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetACL(ctx, req.(*ACLReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type storageListRevisionsClient struct {
-	grpc.ClientStream
+func _Storage_UpdateACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ACLReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).UpdateACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/UpdateACL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).UpdateACL(ctx, req.(*ACLReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *storageListRevisionsClient) Recv() (*RevisionResponse, error) {
-	m := new(RevisionResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _Storage_UnsetACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ACLReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(StorageServer).UnsetACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/UnsetACL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).UnsetACL(ctx, req.(*ACLReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *storageClient) ReadRevision(ctx context.Context, in *RevisionReq, opts ...grpc.CallOption) (Storage_ReadRevisionClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[4], "/api.Storage/ReadRevision", opts...)
-	if err != nil {
+func _Storage_GetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuotaReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &storageReadRevisionClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(StorageServer).GetQuota(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Storage/GetQuota",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetQuota(ctx, req.(*QuotaReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Storage_ReadRevisionClient interface {
-	Recv() (*DataChunkResponse, error)
-	grpc.ClientStream
+var _Storage_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.Storage",
+	HandlerType: (*StorageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateDir",
+			Handler:    _Storage_CreateDir_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Storage_Delete_Handler,
+		},
+		{
+			MethodName: "Move",
+			Handler:    _Storage_Move_Handler,
+		},
+		{
+			MethodName: "Inspect",
+			Handler:    _Storage_Inspect_Handler,
+		},
+		{
+			MethodName: "StartWriteTx",
+			Handler:    _Storage_StartWriteTx_Handler,
+		},
+		{
+			MethodName: "FinishWriteTx",
+			Handler:    _Storage_FinishWriteTx_Handler,
+		},
+		{
+			MethodName: "RestoreRevision",
+			Handler:    _Storage_RestoreRevision_Handler,
+		},
+		{
+			MethodName: "RestoreRecycleEntry",
+			Handler:    _Storage_RestoreRecycleEntry_Handler,
+		},
+		{
+			MethodName: "EmptyRecycle",
+			Handler:    _Storage_EmptyRecycle_Handler,
+		},
+		{
+			MethodName: "SetACL",
+			Handler:    _Storage_SetACL_Handler,
+		},
+		{
+			MethodName: "UpdateACL",
+			Handler:    _Storage_UpdateACL_Handler,
+		},
+		{
+			MethodName: "UnsetACL",
+			Handler:    _Storage_UnsetACL_Handler,
+		},
+		{
+			MethodName: "GetQuota",
+			Handler:    _Storage_GetQuota_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListFolder",
+			Handler:       _Storage_ListFolder_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WriteChunk",
+			Handler:       _Storage_WriteChunk_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ReadFile",
+			Handler:       _Storage_ReadFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListRevisions",
+			Handler:       _Storage_ListRevisions_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadRevision",
+			Handler:       _Storage_ReadRevision_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListRecycle",
+			Handler:       _Storage_ListRecycle_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
 }
 
-type storageReadRevisionClient struct {
-	grpc.ClientStream
+// TaggerClient is the client API for Tagger service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TaggerClient interface {
+	GetTags(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (Tagger_GetTagsClient, error)
+	SetTag(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	UnSetTag(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (*EmptyResponse, error)
 }
 
-func (x *storageReadRevisionClient) Recv() (*DataChunkResponse, error) {
-	m := new(DataChunkResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+type taggerClient struct {
+	cc *grpc.ClientConn
 }
 
-func (c *storageClient) RestoreRevision(ctx context.Context, in *RevisionReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/RestoreRevision", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func NewTaggerClient(cc *grpc.ClientConn) TaggerClient {
+	return &taggerClient{cc}
 }
 
-func (c *storageClient) ListRecycle(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Storage_ListRecycleClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[5], "/api.Storage/ListRecycle", opts...)
+func (c *taggerClient) GetTags(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (Tagger_GetTagsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Tagger_serviceDesc.Streams[0], "/api.Tagger/GetTags", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &storageListRecycleClient{stream}
+	x := &taggerGetTagsClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -3751,841 +7071,736 @@ func (c *storageClient) ListRecycle(ctx context.Context, in *PathReq, opts ...gr
 	return x, nil
 }
 
-type Storage_ListRecycleClient interface {
-	Recv() (*RecycleEntryResponse, error)
+type Tagger_GetTagsClient interface {
+	Recv() (*TagResponse, error)
 	grpc.ClientStream
 }
 
-type storageListRecycleClient struct {
+type taggerGetTagsClient struct {
 	grpc.ClientStream
 }
 
-func (x *storageListRecycleClient) Recv() (*RecycleEntryResponse, error) {
-	m := new(RecycleEntryResponse)
+func (x *taggerGetTagsClient) Recv() (*TagResponse, error) {
+	m := new(TagResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *storageClient) RestoreRecycleEntry(ctx context.Context, in *RecycleEntryReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/RestoreRecycleEntry", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *storageClient) EmptyRecycle(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+func (c *taggerClient) SetTag(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
 	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/EmptyRecycle", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/api.Tagger/SetTag", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *storageClient) SetACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+func (c *taggerClient) UnSetTag(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
 	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/SetACL", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/api.Tagger/UnSetTag", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *storageClient) UpdateACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/UpdateACL", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+// TaggerServer is the server API for Tagger service.
+type TaggerServer interface {
+	GetTags(*TagReq, Tagger_GetTagsServer) error
+	SetTag(context.Context, *TagReq) (*EmptyResponse, error)
+	UnSetTag(context.Context, *TagReq) (*EmptyResponse, error)
 }
 
-func (c *storageClient) UnsetACL(ctx context.Context, in *ACLReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/UnsetACL", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func RegisterTaggerServer(s *grpc.Server, srv TaggerServer) {
+	s.RegisterService(&_Tagger_serviceDesc, srv)
 }
 
-func (c *storageClient) GetQuota(ctx context.Context, in *QuotaReq, opts ...grpc.CallOption) (*QuotaResponse, error) {
-	out := new(QuotaResponse)
-	err := c.cc.Invoke(ctx, "/api.Storage/GetQuota", in, out, opts...)
-	if err != nil {
-		return nil, err
+func _Tagger_GetTags_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TagReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return out, nil
+	return srv.(TaggerServer).GetTags(m, &taggerGetTagsServer{stream})
 }
 
-// StorageServer is the server API for Storage service.
-type StorageServer interface {
-	CreateDir(context.Context, *PathReq) (*EmptyResponse, error)
-	Delete(context.Context, *PathReq) (*EmptyResponse, error)
-	Move(context.Context, *MoveReq) (*EmptyResponse, error)
-	Inspect(context.Context, *PathReq) (*MetadataResponse, error)
-	ListFolder(*PathReq, Storage_ListFolderServer) error
-	StartWriteTx(context.Context, *EmptyReq) (*TxInfoResponse, error)
-	WriteChunk(Storage_WriteChunkServer) error
-	FinishWriteTx(context.Context, *TxEnd) (*EmptyResponse, error)
-	ReadFile(*PathReq, Storage_ReadFileServer) error
-	ListRevisions(*PathReq, Storage_ListRevisionsServer) error
-	ReadRevision(*RevisionReq, Storage_ReadRevisionServer) error
-	RestoreRevision(context.Context, *RevisionReq) (*EmptyResponse, error)
-	ListRecycle(*PathReq, Storage_ListRecycleServer) error
-	RestoreRecycleEntry(context.Context, *RecycleEntryReq) (*EmptyResponse, error)
-	EmptyRecycle(context.Context, *PathReq) (*EmptyResponse, error)
-	SetACL(context.Context, *ACLReq) (*EmptyResponse, error)
-	UpdateACL(context.Context, *ACLReq) (*EmptyResponse, error)
-	UnsetACL(context.Context, *ACLReq) (*EmptyResponse, error)
-	GetQuota(context.Context, *QuotaReq) (*QuotaResponse, error)
+type Tagger_GetTagsServer interface {
+	Send(*TagResponse) error
+	grpc.ServerStream
 }
 
-func RegisterStorageServer(s *grpc.Server, srv StorageServer) {
-	s.RegisterService(&_Storage_serviceDesc, srv)
+type taggerGetTagsServer struct {
+	grpc.ServerStream
 }
 
-func _Storage_CreateDir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PathReq)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(StorageServer).CreateDir(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Storage/CreateDir",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).CreateDir(ctx, req.(*PathReq))
-	}
-	return interceptor(ctx, in, info, handler)
+func (x *taggerGetTagsServer) Send(m *TagResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func _Storage_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PathReq)
+func _Tagger_SetTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).Delete(ctx, in)
+		return srv.(TaggerServer).SetTag(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Storage/Delete",
+		FullMethod: "/api.Tagger/SetTag",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Delete(ctx, req.(*PathReq))
+		return srv.(TaggerServer).SetTag(ctx, req.(*TagReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MoveReq)
+func _Tagger_UnSetTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).Move(ctx, in)
+		return srv.(TaggerServer).UnSetTag(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Storage/Move",
+		FullMethod: "/api.Tagger/UnSetTag",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Move(ctx, req.(*MoveReq))
+		return srv.(TaggerServer).UnSetTag(ctx, req.(*TagReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PathReq)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(StorageServer).Inspect(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Storage/Inspect",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Inspect(ctx, req.(*PathReq))
-	}
-	return interceptor(ctx, in, info, handler)
+var _Tagger_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.Tagger",
+	HandlerType: (*TaggerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetTag",
+			Handler:    _Tagger_SetTag_Handler,
+		},
+		{
+			MethodName: "UnSetTag",
+			Handler:    _Tagger_UnSetTag_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetTags",
+			Handler:       _Tagger_GetTags_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
 }
 
-func _Storage_ListFolder_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PathReq)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(StorageServer).ListFolder(m, &storageListFolderServer{stream})
+// ShareClient is the client API for Share service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ShareClient interface {
+	// with user context, relative to the user logged in
+	CreatePublicLink(ctx context.Context, in *NewLinkReq, opts ...grpc.CallOption) (*PublicLinkResponse, error)
+	UpdatePublicLink(ctx context.Context, in *UpdateLinkReq, opts ...grpc.CallOption) (*PublicLinkResponse, error)
+	InspectPublicLink(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*PublicLinkResponse, error)
+	RevokePublicLink(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	ListPublicLinks(ctx context.Context, in *ListPublicLinksReq, opts ...grpc.CallOption) (Share_ListPublicLinksClient, error)
+	// with user context, relative to user logged in
+	AddFolderShare(ctx context.Context, in *NewFolderShareReq, opts ...grpc.CallOption) (*FolderShareResponse, error)
+	UpdateFolderShare(ctx context.Context, in *UpdateFolderShareReq, opts ...grpc.CallOption) (*FolderShareResponse, error)
+	ListFolderShares(ctx context.Context, in *ListFolderSharesReq, opts ...grpc.CallOption) (Share_ListFolderSharesClient, error)
+	UnshareFolder(ctx context.Context, in *UnshareFolderReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	GetFolderShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*FolderShareResponse, error)
+	// with user context, relative to the user logged in, in this case, the receiver
+	ListReceivedShares(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (Share_ListReceivedSharesClient, error)
+	MountReceivedShare(ctx context.Context, in *ReceivedShareReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	UnmountReceivedShare(ctx context.Context, in *ReceivedShareReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	// with public link context, counts a download done by the visitor of the link
+	RecordPublicLinkDownload(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error)
 }
 
-type Storage_ListFolderServer interface {
-	Send(*MetadataResponse) error
-	grpc.ServerStream
+type shareClient struct {
+	cc *grpc.ClientConn
 }
 
-type storageListFolderServer struct {
-	grpc.ServerStream
+func NewShareClient(cc *grpc.ClientConn) ShareClient {
+	return &shareClient{cc}
 }
 
-func (x *storageListFolderServer) Send(m *MetadataResponse) error {
-	return x.ServerStream.SendMsg(m)
+func (c *shareClient) CreatePublicLink(ctx context.Context, in *NewLinkReq, opts ...grpc.CallOption) (*PublicLinkResponse, error) {
+	out := new(PublicLinkResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/CreatePublicLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func _Storage_StartWriteTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmptyReq)
-	if err := dec(in); err != nil {
+func (c *shareClient) UpdatePublicLink(ctx context.Context, in *UpdateLinkReq, opts ...grpc.CallOption) (*PublicLinkResponse, error) {
+	out := new(PublicLinkResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/UpdatePublicLink", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	if interceptor == nil {
-		return srv.(StorageServer).StartWriteTx(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Storage/StartWriteTx",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).StartWriteTx(ctx, req.(*EmptyReq))
+	return out, nil
+}
+
+func (c *shareClient) InspectPublicLink(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*PublicLinkResponse, error) {
+	out := new(PublicLinkResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/InspectPublicLink", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return interceptor(ctx, in, info, handler)
+	return out, nil
 }
 
-func _Storage_WriteChunk_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(StorageServer).WriteChunk(&storageWriteChunkServer{stream})
+func (c *shareClient) RevokePublicLink(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/RevokePublicLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type Storage_WriteChunkServer interface {
-	SendAndClose(*WriteSummaryResponse) error
-	Recv() (*TxChunk, error)
-	grpc.ServerStream
+func (c *shareClient) ListPublicLinks(ctx context.Context, in *ListPublicLinksReq, opts ...grpc.CallOption) (Share_ListPublicLinksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Share_serviceDesc.Streams[0], "/api.Share/ListPublicLinks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shareListPublicLinksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-type storageWriteChunkServer struct {
-	grpc.ServerStream
+type Share_ListPublicLinksClient interface {
+	Recv() (*PublicLinkResponse, error)
+	grpc.ClientStream
 }
 
-func (x *storageWriteChunkServer) SendAndClose(m *WriteSummaryResponse) error {
-	return x.ServerStream.SendMsg(m)
+type shareListPublicLinksClient struct {
+	grpc.ClientStream
 }
 
-func (x *storageWriteChunkServer) Recv() (*TxChunk, error) {
-	m := new(TxChunk)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func (x *shareListPublicLinksClient) Recv() (*PublicLinkResponse, error) {
+	m := new(PublicLinkResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func _Storage_FinishWriteTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TxEnd)
-	if err := dec(in); err != nil {
+func (c *shareClient) AddFolderShare(ctx context.Context, in *NewFolderShareReq, opts ...grpc.CallOption) (*FolderShareResponse, error) {
+	out := new(FolderShareResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/AddFolderShare", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	if interceptor == nil {
-		return srv.(StorageServer).FinishWriteTx(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Storage/FinishWriteTx",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).FinishWriteTx(ctx, req.(*TxEnd))
-	}
-	return interceptor(ctx, in, info, handler)
+	return out, nil
 }
 
-func _Storage_ReadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PathReq)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func (c *shareClient) UpdateFolderShare(ctx context.Context, in *UpdateFolderShareReq, opts ...grpc.CallOption) (*FolderShareResponse, error) {
+	out := new(FolderShareResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/UpdateFolderShare", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return srv.(StorageServer).ReadFile(m, &storageReadFileServer{stream})
+	return out, nil
 }
 
-type Storage_ReadFileServer interface {
-	Send(*DataChunkResponse) error
-	grpc.ServerStream
+func (c *shareClient) ListFolderShares(ctx context.Context, in *ListFolderSharesReq, opts ...grpc.CallOption) (Share_ListFolderSharesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Share_serviceDesc.Streams[1], "/api.Share/ListFolderShares", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shareListFolderSharesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-type storageReadFileServer struct {
-	grpc.ServerStream
+type Share_ListFolderSharesClient interface {
+	Recv() (*FolderShareResponse, error)
+	grpc.ClientStream
 }
 
-func (x *storageReadFileServer) Send(m *DataChunkResponse) error {
-	return x.ServerStream.SendMsg(m)
+type shareListFolderSharesClient struct {
+	grpc.ClientStream
 }
 
-func _Storage_ListRevisions_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PathReq)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func (x *shareListFolderSharesClient) Recv() (*FolderShareResponse, error) {
+	m := new(FolderShareResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
 	}
-	return srv.(StorageServer).ListRevisions(m, &storageListRevisionsServer{stream})
-}
-
-type Storage_ListRevisionsServer interface {
-	Send(*RevisionResponse) error
-	grpc.ServerStream
+	return m, nil
 }
 
-type storageListRevisionsServer struct {
-	grpc.ServerStream
+func (c *shareClient) UnshareFolder(ctx context.Context, in *UnshareFolderReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/UnshareFolder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (x *storageListRevisionsServer) Send(m *RevisionResponse) error {
-	return x.ServerStream.SendMsg(m)
+func (c *shareClient) GetFolderShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*FolderShareResponse, error) {
+	out := new(FolderShareResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/GetFolderShare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func _Storage_ReadRevision_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(RevisionReq)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func (c *shareClient) ListReceivedShares(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (Share_ListReceivedSharesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Share_serviceDesc.Streams[2], "/api.Share/ListReceivedShares", opts...)
+	if err != nil {
+		return nil, err
 	}
-	return srv.(StorageServer).ReadRevision(m, &storageReadRevisionServer{stream})
+	x := &shareListReceivedSharesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-type Storage_ReadRevisionServer interface {
-	Send(*DataChunkResponse) error
-	grpc.ServerStream
+type Share_ListReceivedSharesClient interface {
+	Recv() (*ReceivedShareResponse, error)
+	grpc.ClientStream
 }
 
-type storageReadRevisionServer struct {
-	grpc.ServerStream
+type shareListReceivedSharesClient struct {
+	grpc.ClientStream
 }
 
-func (x *storageReadRevisionServer) Send(m *DataChunkResponse) error {
-	return x.ServerStream.SendMsg(m)
+func (x *shareListReceivedSharesClient) Recv() (*ReceivedShareResponse, error) {
+	m := new(ReceivedShareResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-func _Storage_RestoreRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RevisionReq)
-	if err := dec(in); err != nil {
+func (c *shareClient) MountReceivedShare(ctx context.Context, in *ReceivedShareReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/MountReceivedShare", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	if interceptor == nil {
-		return srv.(StorageServer).RestoreRevision(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Storage/RestoreRevision",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).RestoreRevision(ctx, req.(*RevisionReq))
-	}
-	return interceptor(ctx, in, info, handler)
+	return out, nil
 }
 
-func _Storage_ListRecycle_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PathReq)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func (c *shareClient) UnmountReceivedShare(ctx context.Context, in *ReceivedShareReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/UnmountReceivedShare", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return srv.(StorageServer).ListRecycle(m, &storageListRecycleServer{stream})
+	return out, nil
 }
 
-type Storage_ListRecycleServer interface {
-	Send(*RecycleEntryResponse) error
-	grpc.ServerStream
+func (c *shareClient) RecordPublicLinkDownload(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Share/RecordPublicLinkDownload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type storageListRecycleServer struct {
-	grpc.ServerStream
+// ShareServer is the server API for Share service.
+type ShareServer interface {
+	// with user context, relative to the user logged in
+	CreatePublicLink(context.Context, *NewLinkReq) (*PublicLinkResponse, error)
+	UpdatePublicLink(context.Context, *UpdateLinkReq) (*PublicLinkResponse, error)
+	InspectPublicLink(context.Context, *ShareIDReq) (*PublicLinkResponse, error)
+	RevokePublicLink(context.Context, *ShareIDReq) (*EmptyResponse, error)
+	ListPublicLinks(*ListPublicLinksReq, Share_ListPublicLinksServer) error
+	// with user context, relative to user logged in
+	AddFolderShare(context.Context, *NewFolderShareReq) (*FolderShareResponse, error)
+	UpdateFolderShare(context.Context, *UpdateFolderShareReq) (*FolderShareResponse, error)
+	ListFolderShares(*ListFolderSharesReq, Share_ListFolderSharesServer) error
+	UnshareFolder(context.Context, *UnshareFolderReq) (*EmptyResponse, error)
+	GetFolderShare(context.Context, *ShareIDReq) (*FolderShareResponse, error)
+	// with user context, relative to the user logged in, in this case, the receiver
+	ListReceivedShares(*EmptyReq, Share_ListReceivedSharesServer) error
+	MountReceivedShare(context.Context, *ReceivedShareReq) (*EmptyResponse, error)
+	UnmountReceivedShare(context.Context, *ReceivedShareReq) (*EmptyResponse, error)
+	// with public link context, counts a download done by the visitor of the link
+	RecordPublicLinkDownload(context.Context, *ShareIDReq) (*EmptyResponse, error)
 }
 
-func (x *storageListRecycleServer) Send(m *RecycleEntryResponse) error {
-	return x.ServerStream.SendMsg(m)
+func RegisterShareServer(s *grpc.Server, srv ShareServer) {
+	s.RegisterService(&_Share_serviceDesc, srv)
 }
 
-func _Storage_RestoreRecycleEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RecycleEntryReq)
+func _Share_CreatePublicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewLinkReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).RestoreRecycleEntry(ctx, in)
+		return srv.(ShareServer).CreatePublicLink(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Storage/RestoreRecycleEntry",
+		FullMethod: "/api.Share/CreatePublicLink",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).RestoreRecycleEntry(ctx, req.(*RecycleEntryReq))
+		return srv.(ShareServer).CreatePublicLink(ctx, req.(*NewLinkReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_EmptyRecycle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PathReq)
+func _Share_UpdatePublicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLinkReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).EmptyRecycle(ctx, in)
+		return srv.(ShareServer).UpdatePublicLink(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Storage/EmptyRecycle",
+		FullMethod: "/api.Share/UpdatePublicLink",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).EmptyRecycle(ctx, req.(*PathReq))
+		return srv.(ShareServer).UpdatePublicLink(ctx, req.(*UpdateLinkReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_SetACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ACLReq)
+func _Share_InspectPublicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareIDReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).SetACL(ctx, in)
+		return srv.(ShareServer).InspectPublicLink(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Storage/SetACL",
+		FullMethod: "/api.Share/InspectPublicLink",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).SetACL(ctx, req.(*ACLReq))
+		return srv.(ShareServer).InspectPublicLink(ctx, req.(*ShareIDReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_UpdateACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ACLReq)
+func _Share_RevokePublicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareIDReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).UpdateACL(ctx, in)
+		return srv.(ShareServer).RevokePublicLink(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Storage/UpdateACL",
+		FullMethod: "/api.Share/RevokePublicLink",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).UpdateACL(ctx, req.(*ACLReq))
+		return srv.(ShareServer).RevokePublicLink(ctx, req.(*ShareIDReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_UnsetACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ACLReq)
+func _Share_ListPublicLinks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPublicLinksReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShareServer).ListPublicLinks(m, &shareListPublicLinksServer{stream})
+}
+
+type Share_ListPublicLinksServer interface {
+	Send(*PublicLinkResponse) error
+	grpc.ServerStream
+}
+
+type shareListPublicLinksServer struct {
+	grpc.ServerStream
+}
+
+func (x *shareListPublicLinksServer) Send(m *PublicLinkResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Share_AddFolderShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewFolderShareReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).UnsetACL(ctx, in)
+		return srv.(ShareServer).AddFolderShare(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Storage/UnsetACL",
+		FullMethod: "/api.Share/AddFolderShare",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).UnsetACL(ctx, req.(*ACLReq))
+		return srv.(ShareServer).AddFolderShare(ctx, req.(*NewFolderShareReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_GetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QuotaReq)
+func _Share_UpdateFolderShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFolderShareReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).GetQuota(ctx, in)
+		return srv.(ShareServer).UpdateFolderShare(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Storage/GetQuota",
+		FullMethod: "/api.Share/UpdateFolderShare",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).GetQuota(ctx, req.(*QuotaReq))
+		return srv.(ShareServer).UpdateFolderShare(ctx, req.(*UpdateFolderShareReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Storage_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "api.Storage",
-	HandlerType: (*StorageServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateDir",
-			Handler:    _Storage_CreateDir_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _Storage_Delete_Handler,
-		},
-		{
-			MethodName: "Move",
-			Handler:    _Storage_Move_Handler,
-		},
-		{
-			MethodName: "Inspect",
-			Handler:    _Storage_Inspect_Handler,
-		},
-		{
-			MethodName: "StartWriteTx",
-			Handler:    _Storage_StartWriteTx_Handler,
-		},
-		{
-			MethodName: "FinishWriteTx",
-			Handler:    _Storage_FinishWriteTx_Handler,
-		},
-		{
-			MethodName: "RestoreRevision",
-			Handler:    _Storage_RestoreRevision_Handler,
-		},
-		{
-			MethodName: "RestoreRecycleEntry",
-			Handler:    _Storage_RestoreRecycleEntry_Handler,
-		},
-		{
-			MethodName: "EmptyRecycle",
-			Handler:    _Storage_EmptyRecycle_Handler,
-		},
-		{
-			MethodName: "SetACL",
-			Handler:    _Storage_SetACL_Handler,
-		},
-		{
-			MethodName: "UpdateACL",
-			Handler:    _Storage_UpdateACL_Handler,
-		},
-		{
-			MethodName: "UnsetACL",
-			Handler:    _Storage_UnsetACL_Handler,
-		},
-		{
-			MethodName: "GetQuota",
-			Handler:    _Storage_GetQuota_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "ListFolder",
-			Handler:       _Storage_ListFolder_Handler,
-			ServerStreams: true,
-		},
-		{
-			StreamName:    "WriteChunk",
-			Handler:       _Storage_WriteChunk_Handler,
-			ClientStreams: true,
-		},
-		{
-			StreamName:    "ReadFile",
-			Handler:       _Storage_ReadFile_Handler,
-			ServerStreams: true,
-		},
-		{
-			StreamName:    "ListRevisions",
-			Handler:       _Storage_ListRevisions_Handler,
-			ServerStreams: true,
-		},
-		{
-			StreamName:    "ReadRevision",
-			Handler:       _Storage_ReadRevision_Handler,
-			ServerStreams: true,
-		},
-		{
-			StreamName:    "ListRecycle",
-			Handler:       _Storage_ListRecycle_Handler,
-			ServerStreams: true,
-		},
-	},
-	Metadata: "api.proto",
+func _Share_ListFolderShares_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListFolderSharesReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShareServer).ListFolderShares(m, &shareListFolderSharesServer{stream})
 }
 
-// TaggerClient is the client API for Tagger service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type TaggerClient interface {
-	GetTags(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (Tagger_GetTagsClient, error)
-	SetTag(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	UnSetTag(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+type Share_ListFolderSharesServer interface {
+	Send(*FolderShareResponse) error
+	grpc.ServerStream
 }
 
-type taggerClient struct {
-	cc *grpc.ClientConn
+type shareListFolderSharesServer struct {
+	grpc.ServerStream
 }
 
-func NewTaggerClient(cc *grpc.ClientConn) TaggerClient {
-	return &taggerClient{cc}
+func (x *shareListFolderSharesServer) Send(m *FolderShareResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *taggerClient) GetTags(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (Tagger_GetTagsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Tagger_serviceDesc.Streams[0], "/api.Tagger/GetTags", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &taggerGetTagsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+func _Share_UnshareFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnshareFolderReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return x, nil
-}
-
-type Tagger_GetTagsClient interface {
-	Recv() (*TagResponse, error)
-	grpc.ClientStream
-}
-
-type taggerGetTagsClient struct {
-	grpc.ClientStream
-}
-
-func (x *taggerGetTagsClient) Recv() (*TagResponse, error) {
-	m := new(TagResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ShareServer).UnshareFolder(ctx, in)
 	}
-	return m, nil
-}
-
-func (c *taggerClient) SetTag(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Tagger/SetTag", in, out, opts...)
-	if err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Share/UnshareFolder",
 	}
-	return out, nil
-}
-
-func (c *taggerClient) UnSetTag(ctx context.Context, in *TagReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Tagger/UnSetTag", in, out, opts...)
-	if err != nil {
-		return nil, err
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareServer).UnshareFolder(ctx, req.(*UnshareFolderReq))
 	}
-	return out, nil
-}
-
-// TaggerServer is the server API for Tagger service.
-type TaggerServer interface {
-	GetTags(*TagReq, Tagger_GetTagsServer) error
-	SetTag(context.Context, *TagReq) (*EmptyResponse, error)
-	UnSetTag(context.Context, *TagReq) (*EmptyResponse, error)
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterTaggerServer(s *grpc.Server, srv TaggerServer) {
-	s.RegisterService(&_Tagger_serviceDesc, srv)
+func _Share_GetFolderShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareServer).GetFolderShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Share/GetFolderShare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareServer).GetFolderShare(ctx, req.(*ShareIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Tagger_GetTags_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(TagReq)
+func _Share_ListReceivedShares_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EmptyReq)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(TaggerServer).GetTags(m, &taggerGetTagsServer{stream})
+	return srv.(ShareServer).ListReceivedShares(m, &shareListReceivedSharesServer{stream})
 }
 
-type Tagger_GetTagsServer interface {
-	Send(*TagResponse) error
+type Share_ListReceivedSharesServer interface {
+	Send(*ReceivedShareResponse) error
 	grpc.ServerStream
 }
 
-type taggerGetTagsServer struct {
+type shareListReceivedSharesServer struct {
 	grpc.ServerStream
 }
 
-func (x *taggerGetTagsServer) Send(m *TagResponse) error {
+func (x *shareListReceivedSharesServer) Send(m *ReceivedShareResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Tagger_SetTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TagReq)
+func _Share_MountReceivedShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceivedShareReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaggerServer).SetTag(ctx, in)
+		return srv.(ShareServer).MountReceivedShare(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Tagger/SetTag",
+		FullMethod: "/api.Share/MountReceivedShare",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaggerServer).SetTag(ctx, req.(*TagReq))
+		return srv.(ShareServer).MountReceivedShare(ctx, req.(*ReceivedShareReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Tagger_UnSetTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TagReq)
+func _Share_UnmountReceivedShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceivedShareReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaggerServer).UnSetTag(ctx, in)
+		return srv.(ShareServer).UnmountReceivedShare(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Tagger/UnSetTag",
+		FullMethod: "/api.Share/UnmountReceivedShare",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaggerServer).UnSetTag(ctx, req.(*TagReq))
+		return srv.(ShareServer).UnmountReceivedShare(ctx, req.(*ReceivedShareReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Tagger_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "api.Tagger",
-	HandlerType: (*TaggerServer)(nil),
+func _Share_RecordPublicLinkDownload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareServer).RecordPublicLinkDownload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Share/RecordPublicLinkDownload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareServer).RecordPublicLinkDownload(ctx, req.(*ShareIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Share_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.Share",
+	HandlerType: (*ShareServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "SetTag",
-			Handler:    _Tagger_SetTag_Handler,
+			MethodName: "CreatePublicLink",
+			Handler:    _Share_CreatePublicLink_Handler,
 		},
 		{
-			MethodName: "UnSetTag",
-			Handler:    _Tagger_UnSetTag_Handler,
+			MethodName: "UpdatePublicLink",
+			Handler:    _Share_UpdatePublicLink_Handler,
+		},
+		{
+			MethodName: "InspectPublicLink",
+			Handler:    _Share_InspectPublicLink_Handler,
+		},
+		{
+			MethodName: "RevokePublicLink",
+			Handler:    _Share_RevokePublicLink_Handler,
+		},
+		{
+			MethodName: "AddFolderShare",
+			Handler:    _Share_AddFolderShare_Handler,
+		},
+		{
+			MethodName: "UpdateFolderShare",
+			Handler:    _Share_UpdateFolderShare_Handler,
+		},
+		{
+			MethodName: "UnshareFolder",
+			Handler:    _Share_UnshareFolder_Handler,
+		},
+		{
+			MethodName: "GetFolderShare",
+			Handler:    _Share_GetFolderShare_Handler,
+		},
+		{
+			MethodName: "MountReceivedShare",
+			Handler:    _Share_MountReceivedShare_Handler,
+		},
+		{
+			MethodName: "UnmountReceivedShare",
+			Handler:    _Share_UnmountReceivedShare_Handler,
+		},
+		{
+			MethodName: "RecordPublicLinkDownload",
+			Handler:    _Share_RecordPublicLinkDownload_Handler,
 		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "GetTags",
-			Handler:       _Tagger_GetTags_Handler,
+			StreamName:    "ListPublicLinks",
+			Handler:       _Share_ListPublicLinks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListFolderShares",
+			Handler:       _Share_ListFolderShares_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListReceivedShares",
+			Handler:       _Share_ListReceivedShares_Handler,
 			ServerStreams: true,
 		},
 	},
 	Metadata: "api.proto",
 }
 
-// ShareClient is the client API for Share service.
+// PreviewClient is the client API for Preview service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ShareClient interface {
-	// with user context, relative to the user logged in
-	CreatePublicLink(ctx context.Context, in *NewLinkReq, opts ...grpc.CallOption) (*PublicLinkResponse, error)
-	UpdatePublicLink(ctx context.Context, in *UpdateLinkReq, opts ...grpc.CallOption) (*PublicLinkResponse, error)
-	InspectPublicLink(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*PublicLinkResponse, error)
-	RevokePublicLink(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	ListPublicLinks(ctx context.Context, in *ListPublicLinksReq, opts ...grpc.CallOption) (Share_ListPublicLinksClient, error)
-	// with user context, relative to user logged in
-	AddFolderShare(ctx context.Context, in *NewFolderShareReq, opts ...grpc.CallOption) (*FolderShareResponse, error)
-	UpdateFolderShare(ctx context.Context, in *UpdateFolderShareReq, opts ...grpc.CallOption) (*FolderShareResponse, error)
-	ListFolderShares(ctx context.Context, in *ListFolderSharesReq, opts ...grpc.CallOption) (Share_ListFolderSharesClient, error)
-	UnshareFolder(ctx context.Context, in *UnshareFolderReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	GetFolderShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*FolderShareResponse, error)
-	// with user context, relative to the user logged in, in this case, the receiver
-	ListReceivedShares(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (Share_ListReceivedSharesClient, error)
-	MountReceivedShare(ctx context.Context, in *ReceivedShareReq, opts ...grpc.CallOption) (*EmptyResponse, error)
-	UnmountReceivedShare(ctx context.Context, in *ReceivedShareReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+type PreviewClient interface {
+	ReadPreview(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Preview_ReadPreviewClient, error)
 }
 
-type shareClient struct {
+type previewClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewShareClient(cc *grpc.ClientConn) ShareClient {
-	return &shareClient{cc}
-}
-
-func (c *shareClient) CreatePublicLink(ctx context.Context, in *NewLinkReq, opts ...grpc.CallOption) (*PublicLinkResponse, error) {
-	out := new(PublicLinkResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/CreatePublicLink", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *shareClient) UpdatePublicLink(ctx context.Context, in *UpdateLinkReq, opts ...grpc.CallOption) (*PublicLinkResponse, error) {
-	out := new(PublicLinkResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/UpdatePublicLink", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *shareClient) InspectPublicLink(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*PublicLinkResponse, error) {
-	out := new(PublicLinkResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/InspectPublicLink", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *shareClient) RevokePublicLink(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/RevokePublicLink", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *shareClient) ListPublicLinks(ctx context.Context, in *ListPublicLinksReq, opts ...grpc.CallOption) (Share_ListPublicLinksClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Share_serviceDesc.Streams[0], "/api.Share/ListPublicLinks", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &shareListPublicLinksClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type Share_ListPublicLinksClient interface {
-	Recv() (*PublicLinkResponse, error)
-	grpc.ClientStream
-}
-
-type shareListPublicLinksClient struct {
-	grpc.ClientStream
-}
-
-func (x *shareListPublicLinksClient) Recv() (*PublicLinkResponse, error) {
-	m := new(PublicLinkResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func (c *shareClient) AddFolderShare(ctx context.Context, in *NewFolderShareReq, opts ...grpc.CallOption) (*FolderShareResponse, error) {
-	out := new(FolderShareResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/AddFolderShare", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *shareClient) UpdateFolderShare(ctx context.Context, in *UpdateFolderShareReq, opts ...grpc.CallOption) (*FolderShareResponse, error) {
-	out := new(FolderShareResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/UpdateFolderShare", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func NewPreviewClient(cc *grpc.ClientConn) PreviewClient {
+	return &previewClient{cc}
 }
 
-func (c *shareClient) ListFolderShares(ctx context.Context, in *ListFolderSharesReq, opts ...grpc.CallOption) (Share_ListFolderSharesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Share_serviceDesc.Streams[1], "/api.Share/ListFolderShares", opts...)
+func (c *previewClient) ReadPreview(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Preview_ReadPreviewClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Preview_serviceDesc.Streams[0], "/api.Preview/ReadPreview", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &shareListFolderSharesClient{stream}
+	x := &previewReadPreviewClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -4595,47 +7810,113 @@ func (c *shareClient) ListFolderShares(ctx context.Context, in *ListFolderShares
 	return x, nil
 }
 
-type Share_ListFolderSharesClient interface {
-	Recv() (*FolderShareResponse, error)
+type Preview_ReadPreviewClient interface {
+	Recv() (*DataChunkResponse, error)
 	grpc.ClientStream
 }
 
-type shareListFolderSharesClient struct {
+type previewReadPreviewClient struct {
 	grpc.ClientStream
 }
 
-func (x *shareListFolderSharesClient) Recv() (*FolderShareResponse, error) {
-	m := new(FolderShareResponse)
+func (x *previewReadPreviewClient) Recv() (*DataChunkResponse, error) {
+	m := new(DataChunkResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *shareClient) UnshareFolder(ctx context.Context, in *UnshareFolderReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
-	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/UnshareFolder", in, out, opts...)
+// PreviewServer is the server API for Preview service.
+type PreviewServer interface {
+	ReadPreview(*PathReq, Preview_ReadPreviewServer) error
+}
+
+func RegisterPreviewServer(s *grpc.Server, srv PreviewServer) {
+	s.RegisterService(&_Preview_serviceDesc, srv)
+}
+
+func _Preview_ReadPreview_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PathReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PreviewServer).ReadPreview(m, &previewReadPreviewServer{stream})
+}
+
+type Preview_ReadPreviewServer interface {
+	Send(*DataChunkResponse) error
+	grpc.ServerStream
+}
+
+type previewReadPreviewServer struct {
+	grpc.ServerStream
+}
+
+func (x *previewReadPreviewServer) Send(m *DataChunkResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Preview_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.Preview",
+	HandlerType: (*PreviewServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadPreview",
+			Handler:       _Preview_ReadPreview_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
+}
+
+// OCMClient is the client API for OCM service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type OCMClient interface {
+	// without user context, called on behalf of remote servers
+	AddRemoteShare(ctx context.Context, in *NewRemoteShareReq, opts ...grpc.CallOption) (*RemoteShareResponse, error)
+	RemoveRemoteShare(ctx context.Context, in *RemoveRemoteShareReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	// with user context, relative to the user logged in, in this case, the receiver
+	ListRemoteShares(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (OCM_ListRemoteSharesClient, error)
+	GetRemoteShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*RemoteShareResponse, error)
+	AcceptRemoteShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	RejectRemoteShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+}
+
+type oCMClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewOCMClient(cc *grpc.ClientConn) OCMClient {
+	return &oCMClient{cc}
+}
+
+func (c *oCMClient) AddRemoteShare(ctx context.Context, in *NewRemoteShareReq, opts ...grpc.CallOption) (*RemoteShareResponse, error) {
+	out := new(RemoteShareResponse)
+	err := c.cc.Invoke(ctx, "/api.OCM/AddRemoteShare", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *shareClient) GetFolderShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*FolderShareResponse, error) {
-	out := new(FolderShareResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/GetFolderShare", in, out, opts...)
+func (c *oCMClient) RemoveRemoteShare(ctx context.Context, in *RemoveRemoteShareReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.OCM/RemoveRemoteShare", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *shareClient) ListReceivedShares(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (Share_ListReceivedSharesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Share_serviceDesc.Streams[2], "/api.Share/ListReceivedShares", opts...)
+func (c *oCMClient) ListRemoteShares(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (OCM_ListRemoteSharesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_OCM_serviceDesc.Streams[0], "/api.OCM/ListRemoteShares", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &shareListReceivedSharesClient{stream}
+	x := &oCMListRemoteSharesClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -4645,394 +7926,581 @@ func (c *shareClient) ListReceivedShares(ctx context.Context, in *EmptyReq, opts
 	return x, nil
 }
 
-type Share_ListReceivedSharesClient interface {
-	Recv() (*ReceivedShareResponse, error)
+type OCM_ListRemoteSharesClient interface {
+	Recv() (*RemoteShareResponse, error)
 	grpc.ClientStream
 }
 
-type shareListReceivedSharesClient struct {
+type oCMListRemoteSharesClient struct {
 	grpc.ClientStream
 }
 
-func (x *shareListReceivedSharesClient) Recv() (*ReceivedShareResponse, error) {
-	m := new(ReceivedShareResponse)
+func (x *oCMListRemoteSharesClient) Recv() (*RemoteShareResponse, error) {
+	m := new(RemoteShareResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *shareClient) MountReceivedShare(ctx context.Context, in *ReceivedShareReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+func (c *oCMClient) GetRemoteShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*RemoteShareResponse, error) {
+	out := new(RemoteShareResponse)
+	err := c.cc.Invoke(ctx, "/api.OCM/GetRemoteShare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oCMClient) AcceptRemoteShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
 	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/MountReceivedShare", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/api.OCM/AcceptRemoteShare", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *shareClient) UnmountReceivedShare(ctx context.Context, in *ReceivedShareReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+func (c *oCMClient) RejectRemoteShare(ctx context.Context, in *ShareIDReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
 	out := new(EmptyResponse)
-	err := c.cc.Invoke(ctx, "/api.Share/UnmountReceivedShare", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/api.OCM/RejectRemoteShare", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// ShareServer is the server API for Share service.
-type ShareServer interface {
-	// with user context, relative to the user logged in
-	CreatePublicLink(context.Context, *NewLinkReq) (*PublicLinkResponse, error)
-	UpdatePublicLink(context.Context, *UpdateLinkReq) (*PublicLinkResponse, error)
-	InspectPublicLink(context.Context, *ShareIDReq) (*PublicLinkResponse, error)
-	RevokePublicLink(context.Context, *ShareIDReq) (*EmptyResponse, error)
-	ListPublicLinks(*ListPublicLinksReq, Share_ListPublicLinksServer) error
-	// with user context, relative to user logged in
-	AddFolderShare(context.Context, *NewFolderShareReq) (*FolderShareResponse, error)
-	UpdateFolderShare(context.Context, *UpdateFolderShareReq) (*FolderShareResponse, error)
-	ListFolderShares(*ListFolderSharesReq, Share_ListFolderSharesServer) error
-	UnshareFolder(context.Context, *UnshareFolderReq) (*EmptyResponse, error)
-	GetFolderShare(context.Context, *ShareIDReq) (*FolderShareResponse, error)
+// OCMServer is the server API for OCM service.
+type OCMServer interface {
+	// without user context, called on behalf of remote servers
+	AddRemoteShare(context.Context, *NewRemoteShareReq) (*RemoteShareResponse, error)
+	RemoveRemoteShare(context.Context, *RemoveRemoteShareReq) (*EmptyResponse, error)
 	// with user context, relative to the user logged in, in this case, the receiver
-	ListReceivedShares(*EmptyReq, Share_ListReceivedSharesServer) error
-	MountReceivedShare(context.Context, *ReceivedShareReq) (*EmptyResponse, error)
-	UnmountReceivedShare(context.Context, *ReceivedShareReq) (*EmptyResponse, error)
+	ListRemoteShares(*EmptyReq, OCM_ListRemoteSharesServer) error
+	GetRemoteShare(context.Context, *ShareIDReq) (*RemoteShareResponse, error)
+	AcceptRemoteShare(context.Context, *ShareIDReq) (*EmptyResponse, error)
+	RejectRemoteShare(context.Context, *ShareIDReq) (*EmptyResponse, error)
 }
 
-func RegisterShareServer(s *grpc.Server, srv ShareServer) {
-	s.RegisterService(&_Share_serviceDesc, srv)
+func RegisterOCMServer(s *grpc.Server, srv OCMServer) {
+	s.RegisterService(&_OCM_serviceDesc, srv)
 }
 
-func _Share_CreatePublicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewLinkReq)
+func _OCM_AddRemoteShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewRemoteShareReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ShareServer).CreatePublicLink(ctx, in)
+		return srv.(OCMServer).AddRemoteShare(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Share/CreatePublicLink",
+		FullMethod: "/api.OCM/AddRemoteShare",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).CreatePublicLink(ctx, req.(*NewLinkReq))
+		return srv.(OCMServer).AddRemoteShare(ctx, req.(*NewRemoteShareReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Share_UpdatePublicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateLinkReq)
+func _OCM_RemoveRemoteShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRemoteShareReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ShareServer).UpdatePublicLink(ctx, in)
+		return srv.(OCMServer).RemoveRemoteShare(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Share/UpdatePublicLink",
+		FullMethod: "/api.OCM/RemoveRemoteShare",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).UpdatePublicLink(ctx, req.(*UpdateLinkReq))
+		return srv.(OCMServer).RemoveRemoteShare(ctx, req.(*RemoveRemoteShareReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Share_InspectPublicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _OCM_ListRemoteShares_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EmptyReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OCMServer).ListRemoteShares(m, &oCMListRemoteSharesServer{stream})
+}
+
+type OCM_ListRemoteSharesServer interface {
+	Send(*RemoteShareResponse) error
+	grpc.ServerStream
+}
+
+type oCMListRemoteSharesServer struct {
+	grpc.ServerStream
+}
+
+func (x *oCMListRemoteSharesServer) Send(m *RemoteShareResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OCM_GetRemoteShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ShareIDReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ShareServer).InspectPublicLink(ctx, in)
+		return srv.(OCMServer).GetRemoteShare(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Share/InspectPublicLink",
+		FullMethod: "/api.OCM/GetRemoteShare",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).InspectPublicLink(ctx, req.(*ShareIDReq))
+		return srv.(OCMServer).GetRemoteShare(ctx, req.(*ShareIDReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Share_RevokePublicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _OCM_AcceptRemoteShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ShareIDReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ShareServer).RevokePublicLink(ctx, in)
+		return srv.(OCMServer).AcceptRemoteShare(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Share/RevokePublicLink",
+		FullMethod: "/api.OCM/AcceptRemoteShare",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).RevokePublicLink(ctx, req.(*ShareIDReq))
+		return srv.(OCMServer).AcceptRemoteShare(ctx, req.(*ShareIDReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Share_ListPublicLinks_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ListPublicLinksReq)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _OCM_RejectRemoteShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(ShareServer).ListPublicLinks(m, &shareListPublicLinksServer{stream})
+	if interceptor == nil {
+		return srv.(OCMServer).RejectRemoteShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.OCM/RejectRemoteShare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OCMServer).RejectRemoteShare(ctx, req.(*ShareIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Share_ListPublicLinksServer interface {
-	Send(*PublicLinkResponse) error
-	grpc.ServerStream
+var _OCM_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.OCM",
+	HandlerType: (*OCMServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddRemoteShare",
+			Handler:    _OCM_AddRemoteShare_Handler,
+		},
+		{
+			MethodName: "RemoveRemoteShare",
+			Handler:    _OCM_RemoveRemoteShare_Handler,
+		},
+		{
+			MethodName: "GetRemoteShare",
+			Handler:    _OCM_GetRemoteShare_Handler,
+		},
+		{
+			MethodName: "AcceptRemoteShare",
+			Handler:    _OCM_AcceptRemoteShare_Handler,
+		},
+		{
+			MethodName: "RejectRemoteShare",
+			Handler:    _OCM_RejectRemoteShare_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListRemoteShares",
+			Handler:       _OCM_ListRemoteShares_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
 }
 
-type shareListPublicLinksServer struct {
-	grpc.ServerStream
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AdminClient interface {
+	// with user context, the user must be member of the admin group
+	ReconcileShares(ctx context.Context, in *ReconcileSharesReq, opts ...grpc.CallOption) (Admin_ReconcileSharesClient, error)
+	TransferOwnership(ctx context.Context, in *TransferOwnershipReq, opts ...grpc.CallOption) (Admin_TransferOwnershipClient, error)
+	// revokes all the tokens forged for the user until now
+	RevokeUserTokens(ctx context.Context, in *RevokeUserTokensReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+	// forges a short lived token of the user for a member of the impersonation group,
+	// it cannot be used to change shares
+	ImpersonateUser(ctx context.Context, in *ImpersonateUserReq, opts ...grpc.CallOption) (*TokenResponse, error)
+}
+
+type adminClient struct {
+	cc *grpc.ClientConn
 }
 
-func (x *shareListPublicLinksServer) Send(m *PublicLinkResponse) error {
-	return x.ServerStream.SendMsg(m)
+func NewAdminClient(cc *grpc.ClientConn) AdminClient {
+	return &adminClient{cc}
 }
 
-func _Share_AddFolderShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewFolderShareReq)
-	if err := dec(in); err != nil {
+func (c *adminClient) ReconcileShares(ctx context.Context, in *ReconcileSharesReq, opts ...grpc.CallOption) (Admin_ReconcileSharesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Admin_serviceDesc.Streams[0], "/api.Admin/ReconcileShares", opts...)
+	if err != nil {
 		return nil, err
 	}
-	if interceptor == nil {
-		return srv.(ShareServer).AddFolderShare(ctx, in)
+	x := &adminReconcileSharesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Share/AddFolderShare",
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).AddFolderShare(ctx, req.(*NewFolderShareReq))
+	return x, nil
+}
+
+type Admin_ReconcileSharesClient interface {
+	Recv() (*ACLDriftResponse, error)
+	grpc.ClientStream
+}
+
+type adminReconcileSharesClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminReconcileSharesClient) Recv() (*ACLDriftResponse, error) {
+	m := new(ACLDriftResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
 	}
-	return interceptor(ctx, in, info, handler)
+	return m, nil
+}
+
+func (c *adminClient) TransferOwnership(ctx context.Context, in *TransferOwnershipReq, opts ...grpc.CallOption) (Admin_TransferOwnershipClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Admin_serviceDesc.Streams[1], "/api.Admin/TransferOwnership", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminTransferOwnershipClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Admin_TransferOwnershipClient interface {
+	Recv() (*TransferredItemResponse, error)
+	grpc.ClientStream
+}
+
+type adminTransferOwnershipClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminTransferOwnershipClient) Recv() (*TransferredItemResponse, error) {
+	m := new(TransferredItemResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminClient) RevokeUserTokens(ctx context.Context, in *RevokeUserTokensReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Admin/RevokeUserTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ImpersonateUser(ctx context.Context, in *ImpersonateUserReq, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/api.Admin/ImpersonateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+type AdminServer interface {
+	// with user context, the user must be member of the admin group
+	ReconcileShares(*ReconcileSharesReq, Admin_ReconcileSharesServer) error
+	TransferOwnership(*TransferOwnershipReq, Admin_TransferOwnershipServer) error
+	// revokes all the tokens forged for the user until now
+	RevokeUserTokens(context.Context, *RevokeUserTokensReq) (*EmptyResponse, error)
+	// forges a short lived token of the user for a member of the impersonation group,
+	// it cannot be used to change shares
+	ImpersonateUser(context.Context, *ImpersonateUserReq) (*TokenResponse, error)
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
 }
 
-func _Share_UpdateFolderShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateFolderShareReq)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ShareServer).UpdateFolderShare(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/api.Share/UpdateFolderShare",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).UpdateFolderShare(ctx, req.(*UpdateFolderShareReq))
+func _Admin_ReconcileShares_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReconcileSharesReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return interceptor(ctx, in, info, handler)
+	return srv.(AdminServer).ReconcileShares(m, &adminReconcileSharesServer{stream})
 }
 
-func _Share_ListFolderShares_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ListFolderSharesReq)
+type Admin_ReconcileSharesServer interface {
+	Send(*ACLDriftResponse) error
+	grpc.ServerStream
+}
+
+type adminReconcileSharesServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminReconcileSharesServer) Send(m *ACLDriftResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Admin_TransferOwnership_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TransferOwnershipReq)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ShareServer).ListFolderShares(m, &shareListFolderSharesServer{stream})
+	return srv.(AdminServer).TransferOwnership(m, &adminTransferOwnershipServer{stream})
 }
 
-type Share_ListFolderSharesServer interface {
-	Send(*FolderShareResponse) error
+type Admin_TransferOwnershipServer interface {
+	Send(*TransferredItemResponse) error
 	grpc.ServerStream
 }
 
-type shareListFolderSharesServer struct {
+type adminTransferOwnershipServer struct {
 	grpc.ServerStream
 }
 
-func (x *shareListFolderSharesServer) Send(m *FolderShareResponse) error {
+func (x *adminTransferOwnershipServer) Send(m *TransferredItemResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Share_UnshareFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UnshareFolderReq)
+func _Admin_RevokeUserTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeUserTokensReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ShareServer).UnshareFolder(ctx, in)
+		return srv.(AdminServer).RevokeUserTokens(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Share/UnshareFolder",
+		FullMethod: "/api.Admin/RevokeUserTokens",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).UnshareFolder(ctx, req.(*UnshareFolderReq))
+		return srv.(AdminServer).RevokeUserTokens(ctx, req.(*RevokeUserTokensReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Share_GetFolderShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ShareIDReq)
+func _Admin_ImpersonateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImpersonateUserReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ShareServer).GetFolderShare(ctx, in)
+		return srv.(AdminServer).ImpersonateUser(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Share/GetFolderShare",
+		FullMethod: "/api.Admin/ImpersonateUser",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).GetFolderShare(ctx, req.(*ShareIDReq))
+		return srv.(AdminServer).ImpersonateUser(ctx, req.(*ImpersonateUserReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Share_ListReceivedShares_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(EmptyReq)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RevokeUserTokens",
+			Handler:    _Admin_RevokeUserTokens_Handler,
+		},
+		{
+			MethodName: "ImpersonateUser",
+			Handler:    _Admin_ImpersonateUser_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReconcileShares",
+			Handler:       _Admin_ReconcileShares_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TransferOwnership",
+			Handler:       _Admin_TransferOwnership_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
+}
+
+// NotificationClient is the client API for Notification service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NotificationClient interface {
+	// with user context, relative to the user logged in
+	GetNotificationPreferences(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (*NotificationPreferencesResponse, error)
+	SetNotificationPreferences(ctx context.Context, in *NotificationPreferencesReq, opts ...grpc.CallOption) (*EmptyResponse, error)
+}
+
+type notificationClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNotificationClient(cc *grpc.ClientConn) NotificationClient {
+	return &notificationClient{cc}
+}
+
+func (c *notificationClient) GetNotificationPreferences(ctx context.Context, in *EmptyReq, opts ...grpc.CallOption) (*NotificationPreferencesResponse, error) {
+	out := new(NotificationPreferencesResponse)
+	err := c.cc.Invoke(ctx, "/api.Notification/GetNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return srv.(ShareServer).ListReceivedShares(m, &shareListReceivedSharesServer{stream})
+	return out, nil
 }
 
-type Share_ListReceivedSharesServer interface {
-	Send(*ReceivedShareResponse) error
-	grpc.ServerStream
+func (c *notificationClient) SetNotificationPreferences(ctx context.Context, in *NotificationPreferencesReq, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, "/api.Notification/SetNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type shareListReceivedSharesServer struct {
-	grpc.ServerStream
+// NotificationServer is the server API for Notification service.
+type NotificationServer interface {
+	// with user context, relative to the user logged in
+	GetNotificationPreferences(context.Context, *EmptyReq) (*NotificationPreferencesResponse, error)
+	SetNotificationPreferences(context.Context, *NotificationPreferencesReq) (*EmptyResponse, error)
 }
 
-func (x *shareListReceivedSharesServer) Send(m *ReceivedShareResponse) error {
-	return x.ServerStream.SendMsg(m)
+func RegisterNotificationServer(s *grpc.Server, srv NotificationServer) {
+	s.RegisterService(&_Notification_serviceDesc, srv)
 }
 
-func _Share_MountReceivedShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReceivedShareReq)
+func _Notification_GetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ShareServer).MountReceivedShare(ctx, in)
+		return srv.(NotificationServer).GetNotificationPreferences(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Share/MountReceivedShare",
+		FullMethod: "/api.Notification/GetNotificationPreferences",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).MountReceivedShare(ctx, req.(*ReceivedShareReq))
+		return srv.(NotificationServer).GetNotificationPreferences(ctx, req.(*EmptyReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Share_UnmountReceivedShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReceivedShareReq)
+func _Notification_SetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationPreferencesReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ShareServer).UnmountReceivedShare(ctx, in)
+		return srv.(NotificationServer).SetNotificationPreferences(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.Share/UnmountReceivedShare",
+		FullMethod: "/api.Notification/SetNotificationPreferences",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShareServer).UnmountReceivedShare(ctx, req.(*ReceivedShareReq))
+		return srv.(NotificationServer).SetNotificationPreferences(ctx, req.(*NotificationPreferencesReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Share_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "api.Share",
-	HandlerType: (*ShareServer)(nil),
+var _Notification_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.Notification",
+	HandlerType: (*NotificationServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "CreatePublicLink",
-			Handler:    _Share_CreatePublicLink_Handler,
-		},
-		{
-			MethodName: "UpdatePublicLink",
-			Handler:    _Share_UpdatePublicLink_Handler,
-		},
-		{
-			MethodName: "InspectPublicLink",
-			Handler:    _Share_InspectPublicLink_Handler,
-		},
-		{
-			MethodName: "RevokePublicLink",
-			Handler:    _Share_RevokePublicLink_Handler,
-		},
-		{
-			MethodName: "AddFolderShare",
-			Handler:    _Share_AddFolderShare_Handler,
-		},
-		{
-			MethodName: "UpdateFolderShare",
-			Handler:    _Share_UpdateFolderShare_Handler,
-		},
-		{
-			MethodName: "UnshareFolder",
-			Handler:    _Share_UnshareFolder_Handler,
-		},
-		{
-			MethodName: "GetFolderShare",
-			Handler:    _Share_GetFolderShare_Handler,
-		},
-		{
-			MethodName: "MountReceivedShare",
-			Handler:    _Share_MountReceivedShare_Handler,
-		},
-		{
-			MethodName: "UnmountReceivedShare",
-			Handler:    _Share_UnmountReceivedShare_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "ListPublicLinks",
-			Handler:       _Share_ListPublicLinks_Handler,
-			ServerStreams: true,
-		},
-		{
-			StreamName:    "ListFolderShares",
-			Handler:       _Share_ListFolderShares_Handler,
-			ServerStreams: true,
+			MethodName: "GetNotificationPreferences",
+			Handler:    _Notification_GetNotificationPreferences_Handler,
 		},
 		{
-			StreamName:    "ListReceivedShares",
-			Handler:       _Share_ListReceivedShares_Handler,
-			ServerStreams: true,
+			MethodName: "SetNotificationPreferences",
+			Handler:    _Notification_SetNotificationPreferences_Handler,
 		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "api.proto",
 }
 
-// PreviewClient is the client API for Preview service.
+// DirectoryClient is the client API for Directory service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type PreviewClient interface {
-	ReadPreview(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Preview_ReadPreviewClient, error)
+type DirectoryClient interface {
+	// with user context, the guests cannot look up the directory
+	GetUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*DirectoryUserResponse, error)
+	GetUserAvatar(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*AvatarResponse, error)
+	// searches the users and groups whose id, name or mail start with the prefix
+	Search(ctx context.Context, in *SearchReq, opts ...grpc.CallOption) (Directory_SearchClient, error)
+	ListGroupMembers(ctx context.Context, in *GroupReq, opts ...grpc.CallOption) (*GroupMembersResponse, error)
 }
 
-type previewClient struct {
+type directoryClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewPreviewClient(cc *grpc.ClientConn) PreviewClient {
-	return &previewClient{cc}
+func NewDirectoryClient(cc *grpc.ClientConn) DirectoryClient {
+	return &directoryClient{cc}
 }
 
-func (c *previewClient) ReadPreview(ctx context.Context, in *PathReq, opts ...grpc.CallOption) (Preview_ReadPreviewClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Preview_serviceDesc.Streams[0], "/api.Preview/ReadPreview", opts...)
+func (c *directoryClient) GetUser(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*DirectoryUserResponse, error) {
+	out := new(DirectoryUserResponse)
+	err := c.cc.Invoke(ctx, "/api.Directory/GetUser", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &previewReadPreviewClient{stream}
+	return out, nil
+}
+
+func (c *directoryClient) GetUserAvatar(ctx context.Context, in *UserReq, opts ...grpc.CallOption) (*AvatarResponse, error) {
+	out := new(AvatarResponse)
+	err := c.cc.Invoke(ctx, "/api.Directory/GetUserAvatar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *directoryClient) Search(ctx context.Context, in *SearchReq, opts ...grpc.CallOption) (Directory_SearchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Directory_serviceDesc.Streams[0], "/api.Directory/Search", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &directorySearchClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -5042,61 +8510,142 @@ func (c *previewClient) ReadPreview(ctx context.Context, in *PathReq, opts ...gr
 	return x, nil
 }
 
-type Preview_ReadPreviewClient interface {
-	Recv() (*DataChunkResponse, error)
+type Directory_SearchClient interface {
+	Recv() (*DirectoryEntryResponse, error)
 	grpc.ClientStream
 }
 
-type previewReadPreviewClient struct {
+type directorySearchClient struct {
 	grpc.ClientStream
 }
 
-func (x *previewReadPreviewClient) Recv() (*DataChunkResponse, error) {
-	m := new(DataChunkResponse)
+func (x *directorySearchClient) Recv() (*DirectoryEntryResponse, error) {
+	m := new(DirectoryEntryResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-// PreviewServer is the server API for Preview service.
-type PreviewServer interface {
-	ReadPreview(*PathReq, Preview_ReadPreviewServer) error
+func (c *directoryClient) ListGroupMembers(ctx context.Context, in *GroupReq, opts ...grpc.CallOption) (*GroupMembersResponse, error) {
+	out := new(GroupMembersResponse)
+	err := c.cc.Invoke(ctx, "/api.Directory/ListGroupMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func RegisterPreviewServer(s *grpc.Server, srv PreviewServer) {
-	s.RegisterService(&_Preview_serviceDesc, srv)
+// DirectoryServer is the server API for Directory service.
+type DirectoryServer interface {
+	// with user context, the guests cannot look up the directory
+	GetUser(context.Context, *UserReq) (*DirectoryUserResponse, error)
+	GetUserAvatar(context.Context, *UserReq) (*AvatarResponse, error)
+	// searches the users and groups whose id, name or mail start with the prefix
+	Search(*SearchReq, Directory_SearchServer) error
+	ListGroupMembers(context.Context, *GroupReq) (*GroupMembersResponse, error)
 }
 
-func _Preview_ReadPreview_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PathReq)
+func RegisterDirectoryServer(s *grpc.Server, srv DirectoryServer) {
+	s.RegisterService(&_Directory_serviceDesc, srv)
+}
+
+func _Directory_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DirectoryServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Directory/GetUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DirectoryServer).GetUser(ctx, req.(*UserReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Directory_GetUserAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DirectoryServer).GetUserAvatar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Directory/GetUserAvatar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DirectoryServer).GetUserAvatar(ctx, req.(*UserReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Directory_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchReq)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(PreviewServer).ReadPreview(m, &previewReadPreviewServer{stream})
+	return srv.(DirectoryServer).Search(m, &directorySearchServer{stream})
 }
 
-type Preview_ReadPreviewServer interface {
-	Send(*DataChunkResponse) error
+type Directory_SearchServer interface {
+	Send(*DirectoryEntryResponse) error
 	grpc.ServerStream
 }
 
-type previewReadPreviewServer struct {
+type directorySearchServer struct {
 	grpc.ServerStream
 }
 
-func (x *previewReadPreviewServer) Send(m *DataChunkResponse) error {
+func (x *directorySearchServer) Send(m *DirectoryEntryResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-var _Preview_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "api.Preview",
-	HandlerType: (*PreviewServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+func _Directory_ListGroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DirectoryServer).ListGroupMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Directory/ListGroupMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DirectoryServer).ListGroupMembers(ctx, req.(*GroupReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Directory_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api.Directory",
+	HandlerType: (*DirectoryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUser",
+			Handler:    _Directory_GetUser_Handler,
+		},
+		{
+			MethodName: "GetUserAvatar",
+			Handler:    _Directory_GetUserAvatar_Handler,
+		},
+		{
+			MethodName: "ListGroupMembers",
+			Handler:    _Directory_ListGroupMembers_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "ReadPreview",
-			Handler:       _Preview_ReadPreview_Handler,
+			StreamName:    "Search",
+			Handler:       _Directory_Search_Handler,
 			ServerStreams: true,
 		},
 	},
@@ -0,0 +1,205 @@
+package app_password_manager_db
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+
+	"database/sql"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const (
+	// the passwords are 4 groups of 5 characters, like abcde-fghij-kmnpq-rstuv,
+	// with 100 random bits they cannot be guessed so they are hashed without salt,
+	// which allows to find them by their hash.
+	passwordAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+	passwordGroups   = 4
+	passwordGroupLen = 5
+
+	idLength = 8
+
+	// lastUsedPrecision avoids an update of last_used on every login.
+	lastUsedPrecision = 60
+)
+
+// New returns an app password manager that keeps the app passwords in a MySQL table,
+// only the hashes of the passwords are stored.
+//
+// The table is created by the migration migrations/mysql/0007_cbox_app_passwords.up.sql.
+func New(dbUsername, dbPassword, dbHost string, dbPort int, dbName string) (api.AppPasswordManager, error) {
+	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", dbUsername, dbPassword, dbHost, dbPort, dbName))
+	if err != nil {
+		return nil, err
+	}
+	return &appPasswordManager{db: db}, nil
+}
+
+type appPasswordManager struct {
+	db *sql.DB
+}
+
+func (m *appPasswordManager) CreateAppPassword(ctx context.Context, label string, scope *api.TokenScope) (*api.AppPassword, string, error) {
+	l := ctx_zap.Extract(ctx)
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return nil, "", api.NewError(api.ContextUserRequiredError)
+	}
+
+	if scope == nil {
+		scope = &api.TokenScope{}
+	}
+	if scope.Path != "" {
+		if !strings.HasPrefix(scope.Path, "/") {
+			return nil, "", api.NewError(api.PathInvalidError).WithMessage("the path of the scope must be absolute")
+		}
+		scope = &api.TokenScope{ReadOnly: scope.ReadOnly, Path: path.Clean(scope.Path), Expiration: scope.Expiration}
+	}
+
+	id, err := newID()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, "", err
+	}
+	password, err := newPassword()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, "", err
+	}
+
+	now := time.Now().Unix()
+	stmtString := "insert into cbox_app_passwords (id, account_id, label, password, read_only, path, expiration, ctime) values (?, ?, ?, ?, ?, ?, ?, ?)"
+	if _, err := m.db.Exec(stmtString, id, u.AccountId, label, hashPassword(password), scope.ReadOnly, scope.Path, scope.Expiration, now); err != nil {
+		l.Error("", zap.Error(err))
+		return nil, "", err
+	}
+
+	l.Info("audit: app password created", zap.String("account_id", u.AccountId), zap.String("id", id), zap.String("label", label), zap.Bool("read_only", scope.ReadOnly), zap.String("path", scope.Path), zap.Uint64("expiration", scope.Expiration))
+	ap := &api.AppPassword{Id: id, Label: label, Scope: scope, Ctime: uint64(now)}
+	return ap, password, nil
+}
+
+func (m *appPasswordManager) ListAppPasswords(ctx context.Context) ([]*api.AppPassword, error) {
+	l := ctx_zap.Extract(ctx)
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return nil, api.NewError(api.ContextUserRequiredError)
+	}
+
+	query := "select id, label, read_only, path, expiration, ctime, last_used from cbox_app_passwords where account_id=? order by ctime"
+	rows, err := m.db.Query(query, u.AccountId)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	aps := []*api.AppPassword{}
+	for rows.Next() {
+		ap := &api.AppPassword{Scope: &api.TokenScope{}}
+		if err := rows.Scan(&ap.Id, &ap.Label, &ap.Scope.ReadOnly, &ap.Scope.Path, &ap.Scope.Expiration, &ap.Ctime, &ap.LastUsed); err != nil {
+			l.Error("", zap.Error(err))
+			return nil, err
+		}
+		aps = append(aps, ap)
+	}
+	if err := rows.Err(); err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	return aps, nil
+}
+
+func (m *appPasswordManager) RevokeAppPassword(ctx context.Context, id string) error {
+	l := ctx_zap.Extract(ctx)
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return api.NewError(api.ContextUserRequiredError)
+	}
+
+	res, err := m.db.Exec("delete from cbox_app_passwords where id=? and account_id=?", id, u.AccountId)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+	rowCnt, err := res.RowsAffected()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+	if rowCnt == 0 {
+		return api.NewError(api.AppPasswordNotFoundErrorCode)
+	}
+
+	l.Info("audit: app password revoked", zap.String("account_id", u.AccountId), zap.String("id", id))
+	return nil
+}
+
+func (m *appPasswordManager) AuthenticateAppPassword(ctx context.Context, accountID, password string) (*api.AppPassword, error) {
+	l := ctx_zap.Extract(ctx)
+
+	ap := &api.AppPassword{Scope: &api.TokenScope{}}
+	query := "select id, label, read_only, path, expiration, ctime, last_used from cbox_app_passwords where account_id=? and password=?"
+	err := m.db.QueryRow(query, accountID, hashPassword(password)).Scan(&ap.Id, &ap.Label, &ap.Scope.ReadOnly, &ap.Scope.Path, &ap.Scope.Expiration, &ap.Ctime, &ap.LastUsed)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NewError(api.UserNotFoundErrorCode)
+		}
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	now := uint64(time.Now().Unix())
+	if ap.Scope.Expiration != 0 && ap.Scope.Expiration < now {
+		return nil, api.NewError(api.UserNotFoundErrorCode).WithMessage("app password expired")
+	}
+
+	if now-ap.LastUsed > lastUsedPrecision {
+		// not updating it does not prevent the login
+		if _, err := m.db.Exec("update cbox_app_passwords set last_used=? where id=?", now, ap.Id); err != nil {
+			l.Error("error updating last use of app password", zap.Error(err))
+		}
+		ap.LastUsed = now
+	}
+	return ap, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, idLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func newPassword() (string, error) {
+	groups := make([]string, passwordGroups)
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	for i := range groups {
+		group := make([]byte, passwordGroupLen)
+		for j := range group {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			group[j] = passwordAlphabet[n.Int64()]
+		}
+		groups[i] = string(group)
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+func hashPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,61 @@
+package auth_attempt_store_db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+
+	"database/sql"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// New returns a store that keeps the failed authentication attempts in a MySQL table,
+// so they are shared by all the instances of a deployment.
+// The attempts of a key are forgotten after expiration seconds without new failures.
+// The table is created by the migration migrations/mysql/0002_cbox_auth_attempts.up.sql.
+func New(dbUsername, dbPassword, dbHost string, dbPort int, dbName string, expiration int) (api.AuthAttemptStore, error) {
+	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", dbUsername, dbPassword, dbHost, dbPort, dbName))
+	if err != nil {
+		return nil, err
+	}
+	return &store{db: db, expiration: int64(expiration)}, nil
+}
+
+type store struct {
+	db         *sql.DB
+	expiration int64
+}
+
+func (s *store) GetAuthAttempts(ctx context.Context, key string) (*api.AuthAttempts, error) {
+	var (
+		failed      int
+		lastAttempt int64
+	)
+
+	query := "select failed, last_attempt from cbox_auth_attempts where attempt_key=? and last_attempt>?"
+	if err := s.db.QueryRow(query, key, time.Now().Unix()-s.expiration).Scan(&failed, &lastAttempt); err != nil {
+		if err == sql.ErrNoRows {
+			return &api.AuthAttempts{}, nil
+		}
+		return nil, err
+	}
+	return &api.AuthAttempts{Failed: failed, Last: time.Unix(lastAttempt, 0)}, nil
+}
+
+func (s *store) AddFailedAuthAttempt(ctx context.Context, key string) (*api.AuthAttempts, error) {
+	now := time.Now().Unix()
+
+	// the counter starts again if the previous failure is older than the expiration.
+	stmtString := "insert into cbox_auth_attempts (attempt_key, failed, last_attempt) values (?, 1, ?) on duplicate key update failed=if(last_attempt>?, failed+1, 1), last_attempt=?"
+	if _, err := s.db.Exec(stmtString, key, now, now-s.expiration, now); err != nil {
+		return nil, err
+	}
+	return s.GetAuthAttempts(ctx, key)
+}
+
+func (s *store) ResetAuthAttempts(ctx context.Context, key string) error {
+	_, err := s.db.Exec("delete from cbox_auth_attempts where attempt_key=?", key)
+	return err
+}
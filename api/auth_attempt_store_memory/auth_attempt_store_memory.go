@@ -0,0 +1,60 @@
+package auth_attempt_store_memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/cernbox/revaold/api"
+)
+
+// New returns a store that keeps the failed authentication attempts in memory.
+// The attempts are not shared with other instances, so every instance
+// throttles on its own. The attempts of a key are forgotten after
+// expiration seconds without new failures, and at most size keys are kept.
+func New(size, expiration int) api.AuthAttemptStore {
+	cache := gcache.New(size).LRU().Build()
+	return &store{cache: cache, expiration: time.Second * time.Duration(expiration)}
+}
+
+type store struct {
+	sync.Mutex
+	cache      gcache.Cache
+	expiration time.Duration
+}
+
+func (s *store) GetAuthAttempts(ctx context.Context, key string) (*api.AuthAttempts, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.get(key), nil
+}
+
+func (s *store) AddFailedAuthAttempt(ctx context.Context, key string) (*api.AuthAttempts, error) {
+	s.Lock()
+	defer s.Unlock()
+	attempts := s.get(key)
+	attempts.Failed++
+	attempts.Last = time.Now()
+	if err := s.cache.SetWithExpire(key, *attempts, s.expiration); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (s *store) ResetAuthAttempts(ctx context.Context, key string) error {
+	s.Lock()
+	defer s.Unlock()
+	s.cache.Remove(key)
+	return nil
+}
+
+// get returns a copy of the attempts stored for the key, the caller must hold the lock.
+func (s *store) get(key string) *api.AuthAttempts {
+	v, err := s.cache.Get(key)
+	if err != nil {
+		return &api.AuthAttempts{}
+	}
+	attempts := v.(api.AuthAttempts)
+	return &attempts
+}
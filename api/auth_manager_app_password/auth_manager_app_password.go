@@ -0,0 +1,42 @@
+package auth_manager_app_password
+
+import (
+	"context"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// New returns an auth manager that authenticates the users logging in with one
+// of their app passwords, restricted to the scope of the app password, and
+// delegates the rest of the logins to am. The groups of the users are taken from um.
+func New(am api.AuthManager, apm api.AppPasswordManager, um api.UserManager) api.AuthManager {
+	return &authManager{am: am, apm: apm, um: um}
+}
+
+type authManager struct {
+	am  api.AuthManager
+	apm api.AppPasswordManager
+	um  api.UserManager
+}
+
+func (am *authManager) Authenticate(ctx context.Context, clientID, clientSecret string) (*api.User, error) {
+	l := ctx_zap.Extract(ctx)
+	ap, err := am.apm.AuthenticateAppPassword(ctx, clientID, clientSecret)
+	if err != nil {
+		if api.IsErrorCode(err, api.UserNotFoundErrorCode) {
+			return am.am.Authenticate(ctx, clientID, clientSecret)
+		}
+		return nil, err
+	}
+
+	groups, err := am.um.GetUserGroups(ctx, clientID)
+	if err != nil {
+		l.Error("error getting groups of user", zap.Error(err), zap.String("account_id", clientID))
+		return nil, err
+	}
+
+	l.Info("user authenticated with app password", zap.String("account_id", clientID), zap.String("app_password", ap.Id))
+	return &api.User{AccountId: clientID, DisplayName: clientID, Groups: groups, Scope: ap.Scope}, nil
+}
@@ -0,0 +1,63 @@
+package auth_manager_app_password
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+)
+
+type fakeAppPasswordManager struct {
+	api.AppPasswordManager
+}
+
+func (apm *fakeAppPasswordManager) AuthenticateAppPassword(ctx context.Context, accountID, password string) (*api.AppPassword, error) {
+	if accountID != "alice" || password != "app-password" {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+	return &api.AppPassword{Id: "1", Label: "ci", Scope: &api.TokenScope{ReadOnly: true, Path: "/home/alice/ci"}}, nil
+}
+
+type fakeAuthManager struct {
+	api.AuthManager
+}
+
+func (am *fakeAuthManager) Authenticate(ctx context.Context, clientID, clientSecret string) (*api.User, error) {
+	if clientSecret != "password" {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+	return &api.User{AccountId: clientID}, nil
+}
+
+type fakeUserManager struct {
+	api.UserManager
+}
+
+func (um *fakeUserManager) GetUserGroups(ctx context.Context, accountID string) ([]string, error) {
+	return []string{"physics"}, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	am := New(&fakeAuthManager{}, &fakeAppPasswordManager{}, &fakeUserManager{})
+	ctx := context.Background()
+
+	u, err := am.Authenticate(ctx, "alice", "app-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scope == nil || !u.Scope.ReadOnly || u.Scope.Path != "/home/alice/ci" || len(u.Groups) != 1 {
+		t.Errorf("expected the user restricted to the scope of the app password, got %+v", u)
+	}
+
+	// the other logins go to the next auth manager without scope
+	u, err = am.Authenticate(ctx, "alice", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scope != nil {
+		t.Errorf("expected the user without scope, got %+v", u.Scope)
+	}
+	if _, err := am.Authenticate(ctx, "alice", "guess"); !api.IsErrorCode(err, api.UserNotFoundErrorCode) {
+		t.Errorf("expected a wrong password to be rejected, got %v", err)
+	}
+}
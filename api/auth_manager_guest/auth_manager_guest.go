@@ -0,0 +1,26 @@
+package auth_manager_guest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cernbox/revaold/api"
+)
+
+// New returns an auth manager that authenticates the guests, who log in with
+// their e-mail address, and delegates the rest of the users to am.
+func New(am api.AuthManager, gm api.GuestManager) api.AuthManager {
+	return &authManager{am: am, gm: gm}
+}
+
+type authManager struct {
+	am api.AuthManager
+	gm api.GuestManager
+}
+
+func (am *authManager) Authenticate(ctx context.Context, clientID, clientSecret string) (*api.User, error) {
+	if strings.Contains(clientID, "@") {
+		return am.gm.AuthenticateGuest(ctx, clientID, clientSecret)
+	}
+	return am.am.Authenticate(ctx, clientID, clientSecret)
+}
@@ -2,76 +2,218 @@ package auth_manager_ldap
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 
 	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/api/ldapclient"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
 	"gopkg.in/ldap.v2"
 )
 
-type authManager struct {
-	hostname     string
-	port         int
-	baseDN       string
-	filter       string
-	bindUsername string
-	bindPassword string
+type Options struct {
+	Logger *zap.Logger
+
+	Hostname string
+	Port     int
+
+	// BaseDN and Filter locate the users, the filter gets the escaped account id.
+	BaseDN       string
+	Filter       string
+	BindUsername string
+	BindPassword string
+
+	// CACertFile is a PEM file with the certificates of the authorities trusted to
+	// sign the certificate of the server, if empty the ones of the system are used.
+	// Insecure disables the verification of the certificate, only for testing.
+	CACertFile string
+	ServerName string
+	Insecure   bool
+
+	// DisplayNameAttributes are tried in order to get the display name of the users.
+	DisplayNameAttributes []string
+
+	// The groups are the values of GroupsAttribute, like memberOf, reduced to the
+	// value of their first RDN. If GroupFilter is set, the groups are searched instead in
+	// GroupBaseDN, with the escaped DN of the user replacing %s in the filter, and
+	// named after GroupNameAttribute.
+	GroupsAttribute    string
+	GroupBaseDN        string
+	GroupFilter        string
+	GroupNameAttribute string
+
+	// PoolSize is the maximum number of open connections, they are reused while
+	// they were used less than IdleTimeout seconds ago.
+	PoolSize    int
+	IdleTimeout int
+
+	// ConnectTimeout and RequestTimeout are in seconds.
+	ConnectTimeout int
+	RequestTimeout int
 }
 
-func New(hostname string, port int, basedn, filter, bindclientID, bindpassword string) api.AuthManager {
-	return &authManager{
-		hostname:     hostname,
-		port:         port,
-		baseDN:       basedn,
-		filter:       filter,
-		bindUsername: bindclientID,
-		bindPassword: bindpassword,
+func (opt *Options) init() {
+	if opt.Logger == nil {
+		opt.Logger, _ = zap.NewProduction()
+	}
+	if opt.Port == 0 {
+		opt.Port = 636
+	}
+	if opt.Filter == "" {
+		opt.Filter = "(samaccountname=%s)"
+	}
+	if len(opt.DisplayNameAttributes) == 0 {
+		opt.DisplayNameAttributes = []string{"displayName", "cn"}
+	}
+	if opt.GroupsAttribute == "" {
+		opt.GroupsAttribute = "memberOf"
+	}
+	if opt.GroupBaseDN == "" {
+		opt.GroupBaseDN = opt.BaseDN
+	}
+	if opt.GroupNameAttribute == "" {
+		opt.GroupNameAttribute = "cn"
+	}
+	if opt.PoolSize <= 0 {
+		opt.PoolSize = 10
+	}
+	if opt.IdleTimeout <= 0 {
+		opt.IdleTimeout = 300
+	}
+	if opt.ConnectTimeout <= 0 {
+		opt.ConnectTimeout = 5
+	}
+	if opt.RequestTimeout <= 0 {
+		opt.RequestTimeout = 10
 	}
 }
 
-func (am *authManager) Authenticate(ctx context.Context, clientID, clientSecret string) (*api.User, error) {
-	l, err := ldap.DialTLS("tcp", fmt.Sprintf("%s:%d", am.hostname, am.port), &tls.Config{InsecureSkipVerify: true})
+// New returns an auth manager that checks the passwords binding as the users
+// to the LDAP server, over connections taken from a bounded pool.
+func New(opt *Options) (api.AuthManager, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	client, err := ldapclient.New(&ldapclient.Options{
+		Logger:         opt.Logger,
+		Hostname:       opt.Hostname,
+		Port:           opt.Port,
+		BindUsername:   opt.BindUsername,
+		BindPassword:   opt.BindPassword,
+		CACertFile:     opt.CACertFile,
+		ServerName:     opt.ServerName,
+		Insecure:       opt.Insecure,
+		PoolSize:       opt.PoolSize,
+		IdleTimeout:    opt.IdleTimeout,
+		ConnectTimeout: opt.ConnectTimeout,
+		RequestTimeout: opt.RequestTimeout,
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer l.Close()
+	return &authManager{opt: opt, client: client}, nil
+}
+
+type authManager struct {
+	opt    *Options
+	client *ldapclient.Client
+}
+
+func (am *authManager) Authenticate(ctx context.Context, clientID, clientSecret string) (*api.User, error) {
+	l := ctx_zap.Extract(ctx)
 
-	// First bind with a read only user
-	err = l.Bind(am.bindUsername, am.bindPassword)
+	// an empty password is an unauthenticated bind, which always succeeds
+	if clientID == "" || clientSecret == "" {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+
+	c, err := am.client.GetConn(ctx)
 	if err != nil {
+		l.Error("error getting ldap connection", zap.Error(err))
 		return nil, err
 	}
+	user, err := am.authenticate(c, clientID, clientSecret)
+	am.client.PutConn(c, err)
+	if err != nil {
+		if api.IsErrorCode(err, api.UserNotFoundErrorCode) {
+			return nil, err
+		}
+		l.Error("error authenticating with ldap", zap.Error(err), zap.String("account_id", clientID))
+		return nil, err
+	}
+	return user, nil
+}
 
-	// Search for the given clientID
+func (am *authManager) authenticate(c *ldapclient.Conn, clientID, clientSecret string) (*api.User, error) {
+	attributes := append([]string{"dn", am.opt.GroupsAttribute}, am.opt.DisplayNameAttributes...)
 	searchRequest := ldap.NewSearchRequest(
-		am.baseDN,
-		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
-		fmt.Sprintf(am.filter, clientID),
-		[]string{"dn"},
+		am.opt.BaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, am.opt.RequestTimeout, false,
+		fmt.Sprintf(am.opt.Filter, ldap.EscapeFilter(clientID)),
+		attributes,
 		nil,
 	)
 
-	sr, err := l.Search(searchRequest)
+	sr, err := c.Search(searchRequest)
 	if err != nil {
 		return nil, err
 	}
-
 	if len(sr.Entries) != 1 {
 		return nil, api.NewError(api.UserNotFoundErrorCode)
 	}
+	entry := sr.Entries[0]
 
-	for _, e := range sr.Entries {
-		e.Print()
+	// the groups are resolved before binding as the user, who may not be allowed to search
+	groups, err := am.getGroups(c, entry)
+	if err != nil {
+		return nil, err
 	}
 
-	userdn := sr.Entries[0].DN
-
 	// Bind as the user to verify their password
-	err = l.Bind(userdn, clientSecret)
-	if err != nil {
+	if err := c.Bind(entry.DN, clientSecret); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, api.NewError(api.UserNotFoundErrorCode)
+		}
 		return nil, err
 	}
-	//TODO(labkode): add groups support
 
-	return &api.User{AccountId: clientID, Groups: []string{}}, nil
+	var displayName string
+	for _, attr := range am.opt.DisplayNameAttributes {
+		if displayName = entry.GetAttributeValue(attr); displayName != "" {
+			break
+		}
+	}
+	return &api.User{AccountId: clientID, DisplayName: displayName, Groups: groups}, nil
+}
+
+func (am *authManager) getGroups(c *ldapclient.Conn, entry *ldap.Entry) ([]string, error) {
+	groups := []string{}
+	if am.opt.GroupFilter == "" {
+		for _, dn := range entry.GetAttributeValues(am.opt.GroupsAttribute) {
+			if name := ldapclient.GetFirstRDNValue(dn); name != "" {
+				groups = append(groups, name)
+			}
+		}
+		return groups, nil
+	}
+
+	searchRequest := ldap.NewSearchRequest(
+		am.opt.GroupBaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, am.opt.RequestTimeout, false,
+		fmt.Sprintf(am.opt.GroupFilter, ldap.EscapeFilter(entry.DN)),
+		[]string{am.opt.GroupNameAttribute},
+		nil,
+	)
+	sr, err := c.Search(searchRequest)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range sr.Entries {
+		if name := e.GetAttributeValue(am.opt.GroupNameAttribute); name != "" {
+			groups = append(groups, name)
+		}
+	}
+	return groups, nil
 }
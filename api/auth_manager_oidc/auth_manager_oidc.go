@@ -0,0 +1,404 @@
+package auth_manager_oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// minRefreshInterval limits how often the keys are fetched again when a token
+// is signed with an unknown key, so random tokens cannot flood the issuer.
+const minRefreshInterval = 60 * time.Second
+
+type Options struct {
+	Logger *zap.Logger
+
+	// Issuer is the URL of the OpenID Connect provider, it must match the iss claim
+	// of the tokens. A local mock issuer like http://localhost:8080 can be used for testing.
+	Issuer string
+
+	// JWKSURL is where the signing keys of the issuer are published, if empty
+	// it is discovered from <issuer>/.well-known/openid-configuration.
+	JWKSURL string
+
+	// Audience must be in the aud claim of the tokens, usually the client id
+	// registered in the issuer, so the tokens issued to other clients are rejected.
+	Audience string
+
+	// The claims mapped to the account id, the display name and the groups of the users.
+	AccountIDClaim   string
+	DisplayNameClaim string
+	GroupsClaim      string
+
+	// RefreshInterval is the number of seconds the keys are cached before fetching them again.
+	RefreshInterval int
+
+	// Timeout is the number of seconds to wait for the issuer.
+	Timeout int
+}
+
+func (opt *Options) init() {
+	if opt.Logger == nil {
+		opt.Logger, _ = zap.NewProduction()
+	}
+	opt.Issuer = strings.TrimSuffix(opt.Issuer, "/")
+	if opt.AccountIDClaim == "" {
+		opt.AccountIDClaim = "preferred_username"
+	}
+	if opt.DisplayNameClaim == "" {
+		opt.DisplayNameClaim = "name"
+	}
+	if opt.GroupsClaim == "" {
+		opt.GroupsClaim = "groups"
+	}
+	if opt.RefreshInterval <= 0 {
+		opt.RefreshInterval = 3600
+	}
+	if opt.Timeout <= 0 {
+		opt.Timeout = 10
+	}
+}
+
+// New returns an auth manager that authenticates the users presenting an access
+// token of the OpenID Connect issuer, with api.OIDCClientID as client id and the token
+// as password, and delegates the rest of the users to am.
+// The signing keys of the issuer are fetched when first needed and cached, and
+// fetched again when they expire or a token is signed with an unknown key.
+func New(am api.AuthManager, opt *Options) (api.AuthManager, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+	if opt.Issuer == "" {
+		return nil, errors.New("oidc: issuer is required")
+	}
+	if opt.Audience == "" {
+		return nil, errors.New("oidc: audience is required")
+	}
+
+	return &authManager{
+		am:     am,
+		opt:    opt,
+		logger: opt.Logger,
+		client: &http.Client{Timeout: time.Duration(opt.Timeout) * time.Second},
+		keys:   map[string]interface{}{},
+	}, nil
+}
+
+type authManager struct {
+	am     api.AuthManager
+	opt    *Options
+	logger *zap.Logger
+	client *http.Client
+
+	mu         sync.Mutex
+	jwksURL    string
+	keys       map[string]interface{}
+	fetched    time.Time
+	attempted  time.Time
+	refreshing chan struct{}
+}
+
+func (am *authManager) Authenticate(ctx context.Context, clientID, clientSecret string) (*api.User, error) {
+	if clientID != api.OIDCClientID {
+		return am.am.Authenticate(ctx, clientID, clientSecret)
+	}
+
+	l := ctx_zap.Extract(ctx)
+	claims, err := am.validate(ctx, clientSecret)
+	if err != nil {
+		l.Warn("invalid oidc token", zap.Error(err))
+		return nil, api.NewError(api.TokenInvalidErrorCode).WithMessage(err.Error())
+	}
+
+	user, err := am.getUser(claims)
+	if err != nil {
+		l.Warn("invalid oidc claims", zap.Error(err))
+		return nil, api.NewError(api.TokenInvalidErrorCode).WithMessage(err.Error())
+	}
+	l.Info("user authenticated with oidc token", zap.String("account_id", user.AccountId))
+	return user, nil
+}
+
+// validate checks the signature, the expiration, the issuer and the audience of the token.
+func (am *authManager) validate(ctx context.Context, token string) (jwt.MapClaims, error) {
+	rawToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing method %s", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return am.getKey(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !rawToken.Valid {
+		return nil, errors.New("token is not valid")
+	}
+
+	claims := rawToken.Claims.(jwt.MapClaims)
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("token has no expiration")
+	}
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != am.opt.Issuer {
+		return nil, fmt.Errorf("token issued by %q", iss)
+	}
+	if !hasAudience(claims, am.opt.Audience) {
+		return nil, fmt.Errorf("token not issued for %q", am.opt.Audience)
+	}
+	return claims, nil
+}
+
+func (am *authManager) getUser(claims jwt.MapClaims) (*api.User, error) {
+	accountID, _ := claims[am.opt.AccountIDClaim].(string)
+	if accountID == "" {
+		return nil, fmt.Errorf("claim %s is missing", am.opt.AccountIDClaim)
+	}
+	displayName, _ := claims[am.opt.DisplayNameClaim].(string) // no displayname is not an error
+
+	groups := []string{}
+	switch rawGroups := claims[am.opt.GroupsClaim].(type) {
+	case []interface{}:
+		for _, g := range rawGroups {
+			group, ok := g.(string)
+			if !ok {
+				return nil, fmt.Errorf("group %+v can not be casted to string", g)
+			}
+			groups = append(groups, group)
+		}
+	case string:
+		groups = append(groups, rawGroups)
+	}
+
+	return &api.User{AccountId: accountID, DisplayName: displayName, Groups: groups}, nil
+}
+
+// getKey returns the key with the given id, fetching the keys again if they
+// expired or the key is not known, as the issuer may have rotated them.
+// The keys are fetched without holding the lock, the tokens signed with
+// known keys are validated meanwhile and the rest wait for the new keys.
+func (am *authManager) getKey(ctx context.Context, kid string) (interface{}, error) {
+	am.mu.Lock()
+	expired := time.Since(am.fetched) > time.Duration(am.opt.RefreshInterval)*time.Second
+	key, ok := am.keys[kid]
+	if ok && !expired {
+		am.mu.Unlock()
+		return key, nil
+	}
+
+	refreshing := am.refreshing
+	if refreshing == nil && time.Since(am.attempted) > minRefreshInterval {
+		am.attempted = time.Now()
+		refreshing = make(chan struct{})
+		am.refreshing = refreshing
+		jwksURL := am.jwksURL
+		am.mu.Unlock()
+		am.refreshKeys(ctx, jwksURL)
+	} else {
+		am.mu.Unlock()
+	}
+
+	if refreshing != nil {
+		select {
+		case <-refreshing:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if key, ok := am.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// refreshKeys fetches the keys and replaces the cached ones, waking up
+// the requests waiting for them. It must be called without the lock held.
+func (am *authManager) refreshKeys(ctx context.Context, jwksURL string) {
+	keys, jwksURL, err := am.fetchKeys(ctx, jwksURL)
+
+	am.mu.Lock()
+	if err != nil {
+		// the cached keys are still used if the issuer is not reachable
+		ctx_zap.Extract(ctx).Error("error fetching oidc keys", zap.Error(err))
+	} else {
+		am.keys = keys
+		am.jwksURL = jwksURL
+		am.fetched = time.Now()
+	}
+	close(am.refreshing)
+	am.refreshing = nil
+	am.mu.Unlock()
+}
+
+// fetchKeys returns the keys of the issuer, discovering first where they
+// are published if jwksURL is empty, and the url they were fetched from.
+func (am *authManager) fetchKeys(ctx context.Context, jwksURL string) (map[string]interface{}, string, error) {
+	if jwksURL == "" {
+		var err error
+		jwksURL, err = am.discover(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	var jwks struct {
+		Keys []*jwk `json:"keys"`
+	}
+	if err := am.get(ctx, jwksURL, &jwks); err != nil {
+		return nil, "", err
+	}
+
+	keys := map[string]interface{}{}
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			am.logger.Warn("ignoring oidc key", zap.Error(err), zap.String("kid", k.Kid))
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, "", errors.New("no usable keys published by the issuer")
+	}
+
+	am.logger.Info("fetched oidc keys", zap.String("url", jwksURL), zap.Int("keys", len(keys)))
+	return keys, jwksURL, nil
+}
+
+// discover returns the url of the keys published in the discovery document of the issuer.
+func (am *authManager) discover(ctx context.Context) (string, error) {
+	if am.opt.JWKSURL != "" {
+		return am.opt.JWKSURL, nil
+	}
+
+	var config struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := am.get(ctx, am.opt.Issuer+"/.well-known/openid-configuration", &config); err != nil {
+		return "", err
+	}
+	// the document must be the one of the issuer, as required by the
+	// discovery spec, so its keys are not trusted for another issuer
+	if strings.TrimSuffix(config.Issuer, "/") != am.opt.Issuer {
+		return "", fmt.Errorf("the discovery document is of issuer %q", config.Issuer)
+	}
+	if config.JWKSURI == "" {
+		return "", errors.New("the issuer does not publish jwks_uri")
+	}
+	return config.JWKSURI, nil
+}
+
+func (am *authManager) get(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := am.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", url, res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func hasAudience(claims jwt.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jwk is a public key of a JSON Web Key Set, only RSA and EC keys are supported.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
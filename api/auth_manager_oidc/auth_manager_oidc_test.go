@@ -0,0 +1,137 @@
+package auth_manager_oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// mockIssuer publishes the discovery document and the public key of key,
+// claiming to be issuer, or itself if empty.
+type mockIssuer struct {
+	*httptest.Server
+	key     *rsa.PrivateKey
+	issuer  string
+	fetches int
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := m.issuer
+		if issuer == "" {
+			issuer = m.URL
+		}
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer, "jwks_uri": m.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		m.fetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []*jwk{{
+			Kid: "1",
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	m.Server = httptest.NewServer(mux)
+	return m
+}
+
+func (m *mockIssuer) token(t *testing.T, audience string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                m.URL,
+		"aud":                audience,
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "alice",
+		"groups":             []string{"physics"},
+	})
+	token.Header["kid"] = "1"
+	signed, err := token.SignedString(m.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func newContext() context.Context {
+	return ctx_zap.ToContext(context.Background(), zap.NewNop())
+}
+
+func newAuthManager(t *testing.T, issuer string) api.AuthManager {
+	am, err := New(nil, &Options{Logger: zap.NewNop(), Issuer: issuer, Audience: "cernbox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return am
+}
+
+func TestAuthenticate(t *testing.T) {
+	m := newMockIssuer(t)
+	defer m.Close()
+	am := newAuthManager(t, m.URL)
+
+	u, err := am.Authenticate(newContext(), api.OIDCClientID, m.token(t, "cernbox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.AccountId != "alice" || len(u.Groups) != 1 || u.Groups[0] != "physics" {
+		t.Errorf("unexpected user %+v", u)
+	}
+
+	if _, err := am.Authenticate(newContext(), api.OIDCClientID, m.token(t, "cernbox")); err != nil {
+		t.Fatal(err)
+	}
+	if m.fetches != 1 {
+		t.Errorf("expected the keys to be cached, fetched %d times", m.fetches)
+	}
+}
+
+func TestTokenOfOtherAudienceIsRejected(t *testing.T) {
+	m := newMockIssuer(t)
+	defer m.Close()
+	am := newAuthManager(t, m.URL)
+
+	_, err := am.Authenticate(newContext(), api.OIDCClientID, m.token(t, "other-client"))
+	if !api.IsErrorCode(err, api.TokenInvalidErrorCode) {
+		t.Fatalf("expected an invalid token, got %v", err)
+	}
+}
+
+func TestAudienceIsRequired(t *testing.T) {
+	if _, err := New(nil, &Options{Logger: zap.NewNop(), Issuer: "https://issuer.example.org"}); err == nil {
+		t.Fatal("expected an error without audience")
+	}
+}
+
+func TestDiscoveryOfOtherIssuerIsRejected(t *testing.T) {
+	m := newMockIssuer(t)
+	defer m.Close()
+	m.issuer = "https://evil.example.org"
+	am := newAuthManager(t, m.URL)
+
+	_, err := am.Authenticate(newContext(), api.OIDCClientID, m.token(t, "cernbox"))
+	if !api.IsErrorCode(err, api.TokenInvalidErrorCode) {
+		t.Fatalf("expected an invalid token, got %v", err)
+	}
+	if m.fetches != 0 {
+		t.Error("keys fetched from the discovery document of another issuer")
+	}
+}
@@ -29,15 +29,64 @@ const (
 
 	PublicLinkInvalidPasswordErrorCode ErrorCode = "PUBLIC_LINK_INVALID_PASSWORD"
 
+	// PublicLinkTokenAlreadyExistsErrorCode is used when the token chosen for a link is already taken.
+	PublicLinkTokenAlreadyExistsErrorCode ErrorCode = "PUBLIC_LINK_TOKEN_ALREADY_EXISTS"
+
+	// PublicLinkInvalidTokenErrorCode is used when the token chosen for a link has an invalid length or characters.
+	PublicLinkInvalidTokenErrorCode ErrorCode = "PUBLIC_LINK_INVALID_TOKEN"
+
+	// PublicLinkLockedErrorCode is used when a link cannot be accessed temporarily
+	// because of too many failed authentication attempts.
+	PublicLinkLockedErrorCode ErrorCode = "PUBLIC_LINK_LOCKED"
+
+	// PublicLinkLimitReachedErrorCode is used when a link reached its maximum number of downloads or visitors.
+	PublicLinkLimitReachedErrorCode ErrorCode = "PUBLIC_LINK_LIMIT_REACHED"
+
+	// PublicLinkInvalidNameErrorCode is used when the name or the labels chosen for a link are invalid.
+	PublicLinkInvalidNameErrorCode ErrorCode = "PUBLIC_LINK_INVALID_NAME"
+
+	// PublicLinkUploadTooLargeErrorCode is used when an upload to a link exceeds the maximum size of the files
+	// or the maximum total size of its upload policy.
+	PublicLinkUploadTooLargeErrorCode ErrorCode = "PUBLIC_LINK_UPLOAD_TOO_LARGE"
+
+	// PublicLinkUploadTypeNotAllowedErrorCode is used when the type of an upload to a link is not allowed by its upload policy.
+	PublicLinkUploadTypeNotAllowedErrorCode ErrorCode = "PUBLIC_LINK_UPLOAD_TYPE_NOT_ALLOWED"
+
+	// PublicLinkUploaderNameRequiredErrorCode is used when a link puts the uploads in a folder per uploader
+	// and the uploader did not give its name.
+	PublicLinkUploaderNameRequiredErrorCode ErrorCode = "PUBLIC_LINK_UPLOADER_NAME_REQUIRED"
+
 	// FolderShareNotFoundErrorCode is used when a resource is not found.
 	FolderShareNotFoundErrorCode ErrorCode = "FOLDER_SHARE_NOT_FOUND"
 
+	// FolderShareInvalidTargetErrorCode is used when the name chosen by a recipient for a received share is not valid.
+	FolderShareInvalidTargetErrorCode ErrorCode = "FOLDER_SHARE_INVALID_TARGET"
+
+	// RemoteShareNotFoundErrorCode is used when a share received from a remote server is not found.
+	RemoteShareNotFoundErrorCode ErrorCode = "REMOTE_SHARE_NOT_FOUND"
+
+	// NotificationInvalidRecipientErrorCode is used when a notification is requested for an invalid e-mail address.
+	NotificationInvalidRecipientErrorCode ErrorCode = "NOTIFICATION_INVALID_RECIPIENT"
+
+	// NotificationLimitExceededErrorCode is used when a link is sent to more recipients than allowed.
+	NotificationLimitExceededErrorCode ErrorCode = "NOTIFICATION_LIMIT_EXCEEDED"
+
+	// GuestInvalidTokenErrorCode is used when the token to activate a guest account is unknown, used or expired.
+	GuestInvalidTokenErrorCode ErrorCode = "GUEST_INVALID_TOKEN"
+
+	// AppPasswordNotFoundErrorCode is used when an app password does not exist or belongs to another user.
+	AppPasswordNotFoundErrorCode ErrorCode = "APP_PASSWORD_NOT_FOUND"
+
 	// StorageOperationNotSupported is used when some operation is not available on
 	// the storage, like emptying the recycle bin
 	StorageNotSupportedErrorCode ErrorCode = "STORAGE_NOT_SUPPORTED"
 
 	UserNotFoundErrorCode ErrorCode = "USER_NOT_FOUND"
 
+	// PermissionDeniedErrorCode is used when the user is not allowed to perform the operation,
+	// like running administrative tasks without being member of the admin group.
+	PermissionDeniedErrorCode ErrorCode = "PERMISSION_DENIED"
+
 	TokenInvalidErrorCode ErrorCode = "TOKEN_INVALID"
 
 	// ProjectNotFoundErrorCode is used when a resource is not found.
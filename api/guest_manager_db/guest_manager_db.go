@@ -0,0 +1,175 @@
+package guest_manager_db
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"database/sql"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const tokenLength = 32
+
+// New returns a guest manager that keeps the guest accounts in a MySQL table.
+// The activation tokens are valid during expiration seconds after the invitation
+// and only their hashes are stored.
+//
+// The table is created by the migration migrations/mysql/0005_cbox_guests.up.sql.
+func New(dbUsername, dbPassword, dbHost string, dbPort int, dbName string, expiration int) (api.GuestManager, error) {
+	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", dbUsername, dbPassword, dbHost, dbPort, dbName))
+	if err != nil {
+		return nil, err
+	}
+	return &guestManager{db: db, expiration: int64(expiration)}, nil
+}
+
+type guestManager struct {
+	db         *sql.DB
+	expiration int64
+}
+
+func (gm *guestManager) InviteGuest(ctx context.Context, email string) (string, error) {
+	l := ctx_zap.Extract(ctx)
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return "", api.NewError(api.ContextUserRequiredError)
+	}
+	email = strings.ToLower(email)
+
+	var active bool
+	err := gm.db.QueryRow("select active from cbox_guests where email=?", email).Scan(&active)
+	if err != nil && err != sql.ErrNoRows {
+		l.Error("", zap.Error(err))
+		return "", err
+	}
+	if active {
+		return "", nil
+	}
+
+	// a pending guest invited again gets a new token, the previous one is no longer valid
+	token, err := newToken()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return "", err
+	}
+	now := time.Now().Unix()
+	stmtString := "insert into cbox_guests (email, activation_token, token_expiration, invited_by, ctime) values (?, ?, ?, ?, ?) on duplicate key update activation_token=?, token_expiration=?"
+	if _, err := gm.db.Exec(stmtString, email, hashToken(token), now+gm.expiration, u.AccountId, now, hashToken(token), now+gm.expiration); err != nil {
+		l.Error("", zap.Error(err))
+		return "", err
+	}
+
+	l.Info("guest invited", zap.String("email", email), zap.String("invited_by", u.AccountId))
+	return token, nil
+}
+
+func (gm *guestManager) ActivateGuest(ctx context.Context, token, password string) (string, error) {
+	l := ctx_zap.Extract(ctx)
+	var (
+		email      string
+		expiration int64
+	)
+
+	hash := hashToken(token)
+	query := "select email, token_expiration from cbox_guests where activation_token=? and active=0"
+	if err := gm.db.QueryRow(query, hash).Scan(&email, &expiration); err != nil {
+		if err == sql.ErrNoRows {
+			return "", api.NewError(api.GuestInvalidTokenErrorCode)
+		}
+		l.Error("", zap.Error(err))
+		return "", err
+	}
+	if expiration < time.Now().Unix() {
+		return "", api.NewError(api.GuestInvalidTokenErrorCode).WithMessage("activation token expired")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return "", err
+	}
+
+	// the token is matched again so two concurrent activations cannot both succeed
+	stmtString := "update cbox_guests set password=?, active=1, activation_token=null where email=? and activation_token=?"
+	res, err := gm.db.Exec(stmtString, string(hashedPassword), email, hash)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return "", err
+	}
+	rowCnt, err := res.RowsAffected()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return "", err
+	}
+	if rowCnt == 0 {
+		return "", api.NewError(api.GuestInvalidTokenErrorCode)
+	}
+
+	l.Info("guest activated", zap.String("email", email))
+	return email, nil
+}
+
+func (gm *guestManager) AuthenticateGuest(ctx context.Context, email, password string) (*api.User, error) {
+	l := ctx_zap.Extract(ctx)
+	email = strings.ToLower(email)
+
+	var hashedPassword string
+	query := "select password from cbox_guests where email=? and active=1"
+	if err := gm.db.QueryRow(query, email).Scan(&hashedPassword); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NewError(api.UserNotFoundErrorCode)
+		}
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)); err != nil {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+	return newGuestUser(email), nil
+}
+
+func (gm *guestManager) GetGuest(ctx context.Context, email string) (*api.User, error) {
+	l := ctx_zap.Extract(ctx)
+	email = strings.ToLower(email)
+
+	var active bool
+	if err := gm.db.QueryRow("select active from cbox_guests where email=?", email).Scan(&active); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NewError(api.UserNotFoundErrorCode)
+		}
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	if !active {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+	return newGuestUser(email), nil
+}
+
+func newGuestUser(email string) *api.User {
+	return &api.User{AccountId: email, DisplayName: email, Groups: []string{}, Guest: true}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,42 @@
+// Package jsonfile keeps the state of the file backends, like the JSON share and
+// public link managers, in JSON files.
+package jsonfile
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+)
+
+// Load decodes the file into v. A missing or empty file leaves v untouched.
+func Load(file string, v interface{}) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Save encodes v to a temporary file that replaces the previous one,
+// so a crash never leaves a truncated file.
+func Save(file string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
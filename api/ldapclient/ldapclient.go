@@ -0,0 +1,207 @@
+package ldapclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/ldap.v2"
+)
+
+// Options configure the connections to the LDAP server.
+type Options struct {
+	Logger *zap.Logger
+
+	Hostname string
+	Port     int
+
+	// BindUsername and BindPassword are the credentials of the read only user
+	// the connections are bound with.
+	BindUsername string
+	BindPassword string
+
+	// CACertFile is a PEM file with the certificates of the authorities trusted to
+	// sign the certificate of the server, if empty the ones of the system are used.
+	// Insecure disables the verification of the certificate, only for testing.
+	CACertFile string
+	ServerName string
+	Insecure   bool
+
+	// PoolSize is the maximum number of open connections, they are reused while
+	// they were used less than IdleTimeout seconds ago.
+	PoolSize    int
+	IdleTimeout int
+
+	// ConnectTimeout and RequestTimeout are in seconds.
+	ConnectTimeout int
+	RequestTimeout int
+}
+
+func (opt *Options) init() {
+	if opt.Logger == nil {
+		opt.Logger, _ = zap.NewProduction()
+	}
+	if opt.Port == 0 {
+		opt.Port = 636
+	}
+	if opt.PoolSize <= 0 {
+		opt.PoolSize = 10
+	}
+	if opt.IdleTimeout <= 0 {
+		opt.IdleTimeout = 300
+	}
+	if opt.ConnectTimeout <= 0 {
+		opt.ConnectTimeout = 5
+	}
+	if opt.RequestTimeout <= 0 {
+		opt.RequestTimeout = 10
+	}
+}
+
+// Client hands out TLS connections to the LDAP server from a bounded pool.
+type Client struct {
+	opt       *Options
+	tlsConfig *tls.Config
+
+	// slots bounds the open connections and idle holds the ones not in use.
+	slots chan struct{}
+	idle  chan *Conn
+}
+
+// Conn is a connection of the pool, bound with the read only user.
+type Conn struct {
+	*ldap.Conn
+	lastUsed time.Time
+}
+
+// New returns a client for the LDAP server, the defaults are set in opt.
+func New(opt *Options) (*Client, error) {
+	opt.init()
+
+	tlsConfig := &tls.Config{ServerName: opt.ServerName, InsecureSkipVerify: opt.Insecure}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = opt.Hostname
+	}
+	if opt.CACertFile != "" {
+		pem, err := ioutil.ReadFile(opt.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ldap: no certificates found in %s", opt.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if opt.Insecure {
+		opt.Logger.Warn("ldap: the certificate of the server is not verified")
+	}
+
+	return &Client{
+		opt:       opt,
+		tlsConfig: tlsConfig,
+		slots:     make(chan struct{}, opt.PoolSize),
+		idle:      make(chan *Conn, opt.PoolSize),
+	}, nil
+}
+
+// GetConn returns an idle connection or opens a new one, waiting for one to be
+// released if the pool is full. The connection is bound with the read only user,
+// which also checks that the reused ones are still alive.
+func (cl *Client) GetConn(ctx context.Context) (*Conn, error) {
+	timeout := time.NewTimer(time.Duration(cl.opt.ConnectTimeout) * time.Second)
+	defer timeout.Stop()
+
+	for {
+		// the idle connections are preferred to opening new ones
+		var c *Conn
+		select {
+		case c = <-cl.idle:
+		default:
+			select {
+			case c = <-cl.idle:
+			case cl.slots <- struct{}{}:
+				return cl.open()
+			case <-timeout.C:
+				return nil, errors.New("ldap: timeout waiting for a connection")
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if time.Since(c.lastUsed) > time.Duration(cl.opt.IdleTimeout)*time.Second {
+			cl.closeConn(c)
+			continue
+		}
+		if err := c.Bind(cl.opt.BindUsername, cl.opt.BindPassword); err != nil {
+			cl.opt.Logger.Warn("discarding broken ldap connection", zap.Error(err))
+			cl.closeConn(c)
+			continue
+		}
+		return c, nil
+	}
+}
+
+// open opens and binds a new connection in the slot taken by the caller,
+// that is released if it fails.
+func (cl *Client) open() (*Conn, error) {
+	c, err := cl.dial()
+	if err != nil {
+		<-cl.slots
+		return nil, err
+	}
+	if err := c.Bind(cl.opt.BindUsername, cl.opt.BindPassword); err != nil {
+		cl.closeConn(c)
+		return nil, err
+	}
+	return c, nil
+}
+
+// PutConn returns the connection to the pool, unless it failed with a network error.
+func (cl *Client) PutConn(c *Conn, err error) {
+	if err != nil && ldap.IsErrorWithCode(err, ldap.ErrorNetwork) {
+		cl.closeConn(c)
+		return
+	}
+	c.lastUsed = time.Now()
+	select {
+	case cl.idle <- c:
+	default:
+		cl.closeConn(c)
+	}
+}
+
+func (cl *Client) closeConn(c *Conn) {
+	c.Close()
+	<-cl.slots
+}
+
+func (cl *Client) dial() (*Conn, error) {
+	addr := net.JoinHostPort(cl.opt.Hostname, fmt.Sprintf("%d", cl.opt.Port))
+	dialer := &net.Dialer{Timeout: time.Duration(cl.opt.ConnectTimeout) * time.Second}
+	tlsConn, err := tls.DialWithDialer(dialer, "tcp", addr, cl.tlsConfig)
+	if err != nil {
+		return nil, ldap.NewError(ldap.ErrorNetwork, err)
+	}
+
+	l := ldap.NewConn(tlsConn, true)
+	l.Start()
+	l.SetTimeout(time.Duration(cl.opt.RequestTimeout) * time.Second)
+	return &Conn{Conn: l, lastUsed: time.Now()}, nil
+}
+
+// GetFirstRDNValue returns the value of the first RDN of the DN, like the cn of a group.
+func GetFirstRDNValue(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+		return strings.TrimSpace(dn)
+	}
+	return parsed.RDNs[0].Attributes[0].Value
+}
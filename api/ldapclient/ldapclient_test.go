@@ -0,0 +1,255 @@
+package ldapclient
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/ldap.v2"
+)
+
+// fakeServer is an LDAP server that accepts every bind.
+type fakeServer struct {
+	ln    net.Listener
+	ca    string
+	mu    sync.Mutex
+	conns []net.Conn
+	binds int
+}
+
+// newFakeServer listens with the certificate of the httptest package, valid for
+// 127.0.0.1, and writes it to a file to be used as CA.
+func newFakeServer(t *testing.T) *fakeServer {
+	ts := httptest.NewUnstartedServer(nil)
+	ts.StartTLS()
+	cert, ca := ts.TLS.Certificates[0], ts.Certificate()
+	ts.Close()
+
+	dir, err := ioutil.TempDir("", "ldapclient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{ca: filepath.Join(dir, "ca.pem")}
+	if err := ioutil.WriteFile(s.ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s.ln, err = tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.accept()
+	return s
+}
+
+func (s *fakeServer) accept() {
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+		go s.serve(c)
+	}
+}
+
+// serve answers the bind requests with a success, the bind response only
+// differs in the message id of the request.
+func (s *fakeServer) serve(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	for {
+		id, op, err := readMessage(r)
+		if err != nil || op != 0x60 {
+			return
+		}
+		s.mu.Lock()
+		s.binds++
+		s.mu.Unlock()
+
+		res := append([]byte{0x02, byte(len(id))}, id...)
+		res = append(res, 0x61, 0x07, 0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00)
+		if _, err := c.Write(append([]byte{0x30, byte(len(res))}, res...)); err != nil {
+			return
+		}
+	}
+}
+
+// readMessage returns the message id and the protocol operation tag of an LDAP message.
+func readMessage(r *bufio.Reader) ([]byte, byte, error) {
+	if _, err := r.ReadByte(); err != nil {
+		return nil, 0, err
+	}
+	length, err := r.ReadByte()
+	if err != nil {
+		return nil, 0, err
+	}
+	size := int(length)
+	if length&0x80 != 0 {
+		size = 0
+		for i := 0; i < int(length&0x7f); i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, 0, err
+			}
+			size = size<<8 | int(b)
+		}
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, 0, err
+	}
+	if len(body) < 3 || body[0] != 0x02 || len(body) < 3+int(body[1]) {
+		return nil, 0, errors.New("invalid message")
+	}
+	return body[2 : 2+body[1]], body[2+body[1]], nil
+}
+
+func (s *fakeServer) stats() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns), s.binds
+}
+
+// closeConns closes the connections on the server side, like an LDAP server restart.
+func (s *fakeServer) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+}
+
+func (s *fakeServer) close() {
+	s.ln.Close()
+	s.closeConns()
+	os.RemoveAll(filepath.Dir(s.ca))
+}
+
+func (s *fakeServer) newClient(t *testing.T, poolSize int) *Client {
+	_, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	cl, err := New(&Options{Logger: zap.NewNop(), Hostname: "127.0.0.1", Port: p, BindUsername: "cn=reader", BindPassword: "secret", CACertFile: s.ca, PoolSize: poolSize, ConnectTimeout: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cl
+}
+
+func TestConnectionsAreReused(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.close()
+	cl := s.newClient(t, 2)
+
+	c, err := cl.GetConn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl.PutConn(c, nil)
+	reused, err := cl.GetConn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.PutConn(reused, nil)
+
+	// the reused connection is bound again to check it is alive
+	if conns, binds := s.stats(); reused != c || conns != 1 || binds != 2 {
+		t.Errorf("expected the connection to be reused, got %d connections and %d binds", conns, binds)
+	}
+}
+
+func TestPoolIsBounded(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.close()
+	cl := s.newClient(t, 1)
+
+	c, err := cl.GetConn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := cl.GetConn(ctx); err == nil {
+		t.Fatal("expected to wait for the connection in use")
+	}
+
+	cl.PutConn(c, nil)
+	c, err = cl.GetConn(context.Background())
+	if err != nil {
+		t.Fatalf("expected the released connection, got %v", err)
+	}
+	cl.PutConn(c, nil)
+	if conns, _ := s.stats(); conns != 1 {
+		t.Errorf("expected one connection, got %d", conns)
+	}
+}
+
+func TestBrokenConnectionsAreReplaced(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.close()
+	cl := s.newClient(t, 1)
+
+	c, err := cl.GetConn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl.PutConn(c, nil)
+	s.closeConns()
+
+	c, err = cl.GetConn(context.Background())
+	if err != nil {
+		t.Fatalf("expected a new connection, got %v", err)
+	}
+	// the connection that failed with a network error is not reused
+	cl.PutConn(c, ldap.NewError(ldap.ErrorNetwork, errors.New("connection reset")))
+	c, err = cl.GetConn(context.Background())
+	if err != nil {
+		t.Fatalf("expected a new connection, got %v", err)
+	}
+	cl.PutConn(c, nil)
+
+	if conns, _ := s.stats(); conns != 3 {
+		t.Errorf("expected three connections, got %d", conns)
+	}
+}
+
+func TestServerCertificateIsVerified(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.close()
+	cl := s.newClient(t, 1)
+	cl.tlsConfig.RootCAs = nil
+
+	if _, err := cl.GetConn(context.Background()); err == nil {
+		t.Error("expected the certificate signed by an unknown authority to be rejected")
+	}
+	if _, err := New(&Options{Logger: zap.NewNop(), CACertFile: filepath.Join(filepath.Dir(s.ca), "missing.pem")}); err == nil {
+		t.Error("expected a missing CA file to be an error")
+	}
+}
+
+func TestGetFirstRDNValue(t *testing.T) {
+	tests := map[string]string{
+		"CN=cernbox-admins,OU=e-groups,OU=Workgroups,DC=cern,DC=ch": "cernbox-admins",
+		"cn=physicists,dc=example,dc=org":                           "physicists",
+		"not a dn":                                                  "not a dn",
+	}
+	for dn, expected := range tests {
+		if got := GetFirstRDNValue(dn); got != expected {
+			t.Errorf("GetFirstRDNValue(%q) = %q, expected %q", dn, got, expected)
+		}
+	}
+}
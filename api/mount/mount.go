@@ -108,6 +108,14 @@ func (m *mount) UnsetACL(ctx context.Context, path string, recipient *api.ShareR
 	return m.storage.UnsetACL(ctx, p, recipient, shareList)
 }
 
+func (m *mount) ListACL(ctx context.Context, path string) ([]*api.ACLEntry, error) {
+	p, _, err := m.getInternalPath(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return m.storage.ListACL(ctx, p)
+}
+
 func (m *mount) CreateDir(ctx context.Context, path string) error {
 	if m.isReadOnly() {
 		return api.NewError(api.StoragePermissionDeniedErrorCode).WithMessage("read-only mount")
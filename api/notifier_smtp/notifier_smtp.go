@@ -0,0 +1,753 @@
+package notifier_smtp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/api/jsonfile"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// The templates used for each kind of notification. The first line of a rendered
+// template is the subject of the e-mail and the rest is the body.
+const (
+	folderShareUserTemplate   = "folder_share_user"
+	folderShareGroupTemplate  = "folder_share_group"
+	publicLinkTemplate        = "public_link"
+	guestInvitationTemplate   = "guest_invitation"
+	publicLinkUploadsTemplate = "public_link_uploads"
+)
+
+// builtinLanguage is the language of the templates compiled in the binary.
+const builtinLanguage = "en"
+
+var builtinTemplates = map[string]string{
+	folderShareUserTemplate: `{{.Owner}} shared folder '{{.Name}}' with you
+
+{{.Owner}} shared the folder '{{.Name}}' with you ({{.Recipient}}).
+
+You can go to the tab 'Shared with you' to find the shared folder called '{{.Name}}',
+or access the share directly with this link: {{.URL}}
+
+Best regards,
+CERNBox Team
+`,
+	folderShareGroupTemplate: `{{.Owner}} shared folder '{{.Name}}' with you
+
+{{.Owner}} shared the folder '{{.Name}}' with the group '{{.Group}}' that you are member of.
+
+You can go to the tab 'Shared with you' to find the shared folder called '{{.Name}}',
+or access the share directly with this link: {{.URL}}
+
+Best regards,
+CERNBox Team
+`,
+	publicLinkTemplate: `{{.Owner}} shared '{{.Name}}' with you
+
+{{.Owner}} shared '{{.Name}}' with you.
+
+You can access it with this link: {{.URL}}
+{{- if .Protected}}
+The link is protected by a password, ask {{.Owner}} for it.
+{{- end}}
+{{- if .Expires}}
+The link expires on {{.Expires}}.
+{{- end}}
+
+Best regards,
+CERNBox Team
+`,
+	guestInvitationTemplate: `{{.Owner}} invited you to access folder '{{.Name}}'
+
+{{.Owner}} shared the folder '{{.Name}}' with you ({{.Recipient}}).
+
+To access it, activate your guest account choosing a password with this link: {{.ActivationURL}}
+The link can only be used once and expires in a few days.
+
+Afterwards you can log in with your e-mail address and access the share with this link: {{.URL}}
+
+Best regards,
+CERNBox Team
+`,
+	publicLinkUploadsTemplate: `New files uploaded to your link '{{.Name}}'
+
+The following files were uploaded to your link '{{.Name}}' ({{.URL}}):
+{{range .Uploads}}
+- {{.Name}} ({{.Size}}){{if .Uploader}} by {{.Uploader}}{{end}} on {{.Time}}
+{{- end}}
+
+Best regards,
+CERNBox Team
+`,
+}
+
+type Options struct {
+	Logger *zap.Logger
+
+	// Server is the host:port of the SMTP server. A local sink like
+	// localhost:1025 can be used for testing.
+	Server string
+	From   string
+
+	// MailDomain is appended to the account ids to get the addresses of the users.
+	MailDomain string
+
+	// ShareURL and PublicLinkURL are the prefixes of the links sent in the e-mails,
+	// followed by the name of the share and the token of the link.
+	ShareURL      string
+	PublicLinkURL string
+
+	// GuestActivationURL is the prefix of the links sent to the guests
+	// to activate their accounts, followed by the activation token.
+	GuestActivationURL string
+
+	// TemplatesFolder contains a folder per language, like fr, with the templates
+	// named <kind>.tmpl. The english templates are built in and used as fallback.
+	TemplatesFolder string
+	DefaultLanguage string
+
+	// PreferencesFile is where the preferences of the users are stored,
+	// if empty they are only kept in memory.
+	PreferencesFile string
+
+	QueueSize int
+	Workers   int
+
+	// MaxRetries is the number of times a failed e-mail is sent again, waiting
+	// RetryInterval seconds the first time and doubling it on each retry.
+	// A negative value disables the retries.
+	MaxRetries    int
+	RetryInterval int
+
+	// DigestInterval is the number of seconds the uploads to drop only links
+	// are collected before sending their digest to the owner of the link.
+	DigestInterval int
+
+	// MaxLinkRecipients is the number of addresses a public link can be sent to
+	// at once, and MaxLinkRecipientsPerHour the number a user can send links to
+	// in an hour, so the notifications cannot be used to send spam.
+	MaxLinkRecipients        int
+	MaxLinkRecipientsPerHour int
+}
+
+func (opt *Options) init() {
+	if opt.Logger == nil {
+		opt.Logger, _ = zap.NewProduction()
+	}
+	if opt.Server == "" {
+		opt.Server = "localhost:25"
+	}
+	if opt.From == "" {
+		opt.From = "cernbox-noreply@cern.ch"
+	}
+	if opt.MailDomain == "" {
+		opt.MailDomain = "cern.ch"
+	}
+	if opt.ShareURL == "" {
+		opt.ShareURL = "https://cernbox.cern.ch/index.php/apps/files/?dir=/__myshares/"
+	}
+	if opt.PublicLinkURL == "" {
+		opt.PublicLinkURL = "https://cernbox.cern.ch/index.php/s/"
+	}
+	if opt.GuestActivationURL == "" {
+		opt.GuestActivationURL = "https://cernbox.cern.ch/index.php/apps/guests/activate?token="
+	}
+	if opt.DefaultLanguage == "" {
+		opt.DefaultLanguage = builtinLanguage
+	}
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = 1000
+	}
+	if opt.Workers <= 0 {
+		opt.Workers = 2
+	}
+	if opt.MaxRetries == 0 {
+		opt.MaxRetries = 5
+	}
+	if opt.RetryInterval <= 0 {
+		opt.RetryInterval = 30
+	}
+	if opt.DigestInterval <= 0 {
+		opt.DigestInterval = 3600
+	}
+	if opt.MaxLinkRecipients <= 0 {
+		opt.MaxLinkRecipients = 20
+	}
+	if opt.MaxLinkRecipientsPerHour <= 0 {
+		opt.MaxLinkRecipientsPerHour = 100
+	}
+}
+
+// New returns a notifier that sends the e-mails with SMTP from a pool of workers.
+// The e-mails that cannot be sent are retried, and dropped once the retries are exhausted.
+func New(opt *Options, um api.UserManager) (api.Notifier, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	templates, err := loadTemplates(opt.TemplatesFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &notifier{
+		opt:         opt,
+		um:          um,
+		logger:      opt.Logger,
+		templates:   templates,
+		preferences: map[string]*api.NotificationPreferences{},
+		queue:       make(chan *message, opt.QueueSize),
+		digests:     map[string]*digest{},
+		linkQuotas:  map[string]*linkQuota{},
+	}
+	if err := n.loadPreferences(); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < opt.Workers; i++ {
+		go n.work()
+	}
+	go n.sendDigests()
+	return n, nil
+}
+
+type notifier struct {
+	opt       *Options
+	um        api.UserManager
+	logger    *zap.Logger
+	templates map[string]map[string]*template.Template
+	queue     chan *message
+
+	mu          sync.Mutex
+	preferences map[string]*api.NotificationPreferences
+
+	digestsMu sync.Mutex
+	digests   map[string]*digest
+
+	linkQuotasMu sync.Mutex
+	linkQuotas   map[string]*linkQuota
+}
+
+// linkQuota counts the recipients a user sent public links to since start.
+type linkQuota struct {
+	start      time.Time
+	recipients int
+}
+
+// digest holds the uploads to a link not notified yet. They are only kept
+// in memory, so the pending ones are lost on restart.
+type digest struct {
+	link    *api.PublicLink
+	uploads []*api.PublicLinkUpload
+}
+
+// message is an e-mail to send, or a share with a group to expand into
+// an e-mail for each member when group is set.
+type message struct {
+	to       string
+	subject  string
+	body     string
+	group    *templateData
+	attempts int
+}
+
+// templateData is the data available to the templates.
+type templateData struct {
+	Owner     string
+	OwnerID   string
+	Recipient string
+	Group     string
+	Name      string
+	URL       string
+	ReadOnly  bool
+	Protected bool
+	Expires   string
+
+	ActivationURL string
+	Uploads       []*uploadData
+}
+
+type uploadData struct {
+	Name     string
+	Uploader string
+	Size     string
+	Time     string
+}
+
+func (n *notifier) NotifyFolderShare(ctx context.Context, share *api.FolderShare, p string) error {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := path.Base(p)
+	target := fmt.Sprintf("%s (id:%s)", name, share.Id)
+	data := &templateData{
+		Owner:    getDisplayName(u),
+		OwnerID:  u.AccountId,
+		Name:     name,
+		URL:      n.opt.ShareURL + url.QueryEscape(target),
+		ReadOnly: share.ReadOnly,
+	}
+
+	switch share.Recipient.Type {
+	case api.ShareRecipient_USER, api.ShareRecipient_GUEST:
+		if err := n.notifyUser(share.Recipient.Identity, folderShareUserTemplate, *data); err != nil {
+			l.Error("error queueing notification", zap.Error(err), zap.String("recipient", share.Recipient.Identity))
+			return err
+		}
+	case api.ShareRecipient_GROUP, api.ShareRecipient_UNIX:
+		// the members are looked up by the workers, as big groups
+		// would otherwise delay the creation of the share
+		data.Group = share.Recipient.Identity
+		if err := n.enqueue(&message{to: "members of " + data.Group, group: data}); err != nil {
+			l.Error("error queueing notification", zap.Error(err), zap.String("group", data.Group))
+			return err
+		}
+	default:
+		// remote recipients are notified by their own server
+		return nil
+	}
+
+	l.Info("queued folder share notifications", zap.String("share_id", share.Id))
+	return nil
+}
+
+// notifyUser queues the e-mail to a user, unless it is the owner of
+// the share or opted out of the notifications.
+func (n *notifier) notifyUser(recipient, templateName string, data templateData) error {
+	if recipient == data.OwnerID {
+		return nil
+	}
+
+	prefs := n.getPreferences(recipient)
+	if prefs.OptOut {
+		n.logger.Debug("recipient opted out of notifications", zap.String("recipient", recipient))
+		return nil
+	}
+
+	data.Recipient = recipient
+	msg, err := n.render(prefs.Language, templateName, &data)
+	if err != nil {
+		return err
+	}
+	msg.to = getAddress(recipient, n.opt.MailDomain)
+	return n.enqueue(msg)
+}
+
+// expandGroup queues the e-mails to the members of the group of a share.
+// Only the lookup of the members is retried, as retrying after some e-mails
+// are queued would send them twice.
+func (n *notifier) expandGroup(data *templateData) error {
+	members, err := n.um.GetGroupMembers(context.Background(), data.Group)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if err := n.notifyUser(member, folderShareGroupTemplate, *data); err != nil {
+			n.logger.Error("error queueing notification", zap.Error(err), zap.String("recipient", member), zap.String("group", data.Group))
+		}
+	}
+	n.logger.Info("queued group share notifications", zap.String("group", data.Group), zap.Int("members", len(members)))
+	return nil
+}
+
+func (n *notifier) NotifyPublicLink(ctx context.Context, link *api.PublicLink, p string, recipients []string) error {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(recipients) > n.opt.MaxLinkRecipients {
+		return api.NewError(api.NotificationLimitExceededErrorCode).WithMessage(fmt.Sprintf("a link can be sent to at most %d recipients", n.opt.MaxLinkRecipients))
+	}
+	addresses := []string{}
+	for _, recipient := range recipients {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return api.NewError(api.NotificationInvalidRecipientErrorCode).WithMessage(recipient + " is not a valid e-mail address")
+		}
+		addresses = append(addresses, address.Address)
+	}
+	if !n.takeLinkQuota(u.AccountId, len(addresses)) {
+		l.Warn("user exceeded the public link notifications", zap.String("user", u.AccountId), zap.Int("recipients", len(addresses)))
+		return api.NewError(api.NotificationLimitExceededErrorCode).WithMessage("too many public links sent, try again later")
+	}
+
+	data := &templateData{
+		Owner:     getDisplayName(u),
+		OwnerID:   u.AccountId,
+		Name:      path.Base(p),
+		URL:       n.opt.PublicLinkURL + link.Token,
+		ReadOnly:  link.ReadOnly,
+		Protected: link.Protected,
+	}
+	if link.Expires != 0 {
+		data.Expires = time.Unix(int64(link.Expires), 0).Format("2006-01-02")
+	}
+
+	// the recipients may not be users, so the e-mail is in the language of the creator
+	language := n.getPreferences(u.AccountId).Language
+	for _, address := range addresses {
+		data.Recipient = address
+		msg, err := n.render(language, publicLinkTemplate, data)
+		if err != nil {
+			l.Error("error rendering notification", zap.Error(err), zap.String("template", publicLinkTemplate))
+			return err
+		}
+		msg.to = address
+		if err := n.enqueue(msg); err != nil {
+			l.Error("error queueing notification", zap.Error(err), zap.String("to", msg.to))
+			return err
+		}
+	}
+
+	l.Info("queued public link notifications", zap.String("id", link.Id), zap.Int("recipients", len(addresses)))
+	return nil
+}
+
+// takeLinkQuota counts the recipients against the hourly quota of the user,
+// returning false without counting them when they exceed it.
+func (n *notifier) takeLinkQuota(accountID string, recipients int) bool {
+	n.linkQuotasMu.Lock()
+	defer n.linkQuotasMu.Unlock()
+
+	now := time.Now()
+	q, ok := n.linkQuotas[accountID]
+	if !ok || now.Sub(q.start) >= time.Hour {
+		q = &linkQuota{start: now}
+		n.linkQuotas[accountID] = q
+	}
+	if q.recipients+recipients > n.opt.MaxLinkRecipientsPerHour {
+		return false
+	}
+	q.recipients += recipients
+
+	// the quotas of the previous hours are dropped so the map does not grow
+	for id, other := range n.linkQuotas {
+		if now.Sub(other.start) >= time.Hour {
+			delete(n.linkQuotas, id)
+		}
+	}
+	return true
+}
+
+func (n *notifier) NotifyGuestInvitation(ctx context.Context, share *api.FolderShare, p, activationToken string) error {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := path.Base(p)
+	target := fmt.Sprintf("%s (id:%s)", name, share.Id)
+	data := &templateData{
+		Owner:         getDisplayName(u),
+		OwnerID:       u.AccountId,
+		Recipient:     share.Recipient.Identity,
+		Name:          name,
+		URL:           n.opt.ShareURL + url.QueryEscape(target),
+		ReadOnly:      share.ReadOnly,
+		ActivationURL: n.opt.GuestActivationURL + url.QueryEscape(activationToken),
+	}
+
+	// the invitation is not optional, without it the guest cannot access the share,
+	// and it is in the language of the owner as the guest has no preferences yet
+	language := n.getPreferences(u.AccountId).Language
+	msg, err := n.render(language, guestInvitationTemplate, data)
+	if err != nil {
+		l.Error("error rendering notification", zap.Error(err), zap.String("template", guestInvitationTemplate))
+		return err
+	}
+	msg.to = share.Recipient.Identity
+	if err := n.enqueue(msg); err != nil {
+		l.Error("error queueing notification", zap.Error(err), zap.String("to", msg.to))
+		return err
+	}
+
+	l.Info("queued guest invitation", zap.String("share_id", share.Id), zap.String("to", msg.to))
+	return nil
+}
+
+func (n *notifier) NotifyPublicLinkUpload(ctx context.Context, link *api.PublicLink, upload *api.PublicLinkUpload) error {
+	n.digestsMu.Lock()
+	defer n.digestsMu.Unlock()
+
+	d, ok := n.digests[link.Id]
+	if !ok {
+		d = &digest{}
+		n.digests[link.Id] = d
+	}
+	// the latest link is kept as its name may have changed since the first upload
+	d.link = link
+	d.uploads = append(d.uploads, upload)
+	return nil
+}
+
+// sendDigests queues periodically the digests of the uploads collected since the last time.
+func (n *notifier) sendDigests() {
+	ticker := time.NewTicker(time.Duration(n.opt.DigestInterval) * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		n.digestsMu.Lock()
+		digests := n.digests
+		n.digests = map[string]*digest{}
+		n.digestsMu.Unlock()
+
+		for _, d := range digests {
+			if err := n.sendDigest(d); err != nil {
+				n.logger.Error("error queueing upload digest", zap.Error(err), zap.String("id", d.link.Id))
+			}
+		}
+	}
+}
+
+func (n *notifier) sendDigest(d *digest) error {
+	name := d.link.Name
+	if name == "" {
+		name = d.link.Token
+	}
+	data := &templateData{
+		OwnerID: d.link.OwnerId,
+		Name:    name,
+		URL:     n.opt.PublicLinkURL + d.link.Token,
+	}
+	for _, upload := range d.uploads {
+		data.Uploads = append(data.Uploads, &uploadData{
+			Name:     upload.Name,
+			Uploader: upload.Uploader,
+			Size:     formatSize(upload.Size),
+			Time:     upload.Time.Format("2006-01-02 15:04"),
+		})
+	}
+
+	msg, err := n.render(n.getPreferences(d.link.OwnerId).Language, publicLinkUploadsTemplate, data)
+	if err != nil {
+		return err
+	}
+	msg.to = getAddress(d.link.OwnerId, n.opt.MailDomain)
+	if err := n.enqueue(msg); err != nil {
+		return err
+	}
+
+	n.logger.Info("queued upload digest", zap.String("id", d.link.Id), zap.String("to", msg.to), zap.Int("uploads", len(d.uploads)))
+	return nil
+}
+
+func (n *notifier) GetNotificationPreferences(ctx context.Context) (*api.NotificationPreferences, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.getPreferences(u.AccountId), nil
+}
+
+func (n *notifier) SetNotificationPreferences(ctx context.Context, prefs *api.NotificationPreferences) error {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.preferences[u.AccountId] = &api.NotificationPreferences{OptOut: prefs.OptOut, Language: strings.TrimSpace(prefs.Language)}
+	if err := n.savePreferences(); err != nil {
+		l.Error("error saving notification preferences", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (n *notifier) getPreferences(accountID string) *api.NotificationPreferences {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	prefs, ok := n.preferences[accountID]
+	if !ok {
+		return &api.NotificationPreferences{}
+	}
+	return &api.NotificationPreferences{OptOut: prefs.OptOut, Language: prefs.Language}
+}
+
+func (n *notifier) loadPreferences() error {
+	if n.opt.PreferencesFile == "" {
+		return nil
+	}
+	return jsonfile.Load(n.opt.PreferencesFile, &n.preferences)
+}
+
+// savePreferences must be called with the lock held.
+func (n *notifier) savePreferences() error {
+	if n.opt.PreferencesFile == "" {
+		return nil
+	}
+	return jsonfile.Save(n.opt.PreferencesFile, n.preferences)
+}
+
+// render executes the template in the given language, falling back to the
+// default language and then to the built in templates.
+func (n *notifier) render(language, name string, data *templateData) (*message, error) {
+	var tpl *template.Template
+	for _, lang := range []string{language, n.opt.DefaultLanguage, builtinLanguage} {
+		if t, ok := n.templates[lang][name]; ok {
+			tpl = t
+			break
+		}
+	}
+	if tpl == nil {
+		return nil, fmt.Errorf("notification template %s not found", name)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, data); err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(buf.String(), "\n", 2)
+	msg := &message{subject: strings.TrimSpace(parts[0])}
+	if len(parts) == 2 {
+		msg.body = strings.TrimLeft(parts[1], "\n")
+	}
+	return msg, nil
+}
+
+func (n *notifier) enqueue(msg *message) error {
+	select {
+	case n.queue <- msg:
+		return nil
+	default:
+		return fmt.Errorf("notification queue is full")
+	}
+}
+
+func (n *notifier) work() {
+	for msg := range n.queue {
+		msg := msg
+		var err error
+		if msg.group != nil {
+			err = n.expandGroup(msg.group)
+		} else {
+			err = n.send(msg)
+		}
+		if err == nil {
+			if msg.group == nil {
+				n.logger.Info("notification sent", zap.String("to", msg.to), zap.String("subject", msg.subject))
+			}
+			continue
+		}
+
+		if msg.attempts >= n.opt.MaxRetries {
+			n.logger.Error("error sending notification, giving up", zap.Error(err), zap.String("to", msg.to), zap.Int("attempts", msg.attempts+1))
+			continue
+		}
+
+		delay := time.Duration(n.opt.RetryInterval) * time.Second << uint(msg.attempts)
+		msg.attempts++
+		n.logger.Warn("error sending notification, retrying", zap.Error(err), zap.String("to", msg.to), zap.Int("attempt", msg.attempts), zap.Duration("delay", delay))
+		time.AfterFunc(delay, func() {
+			if err := n.enqueue(msg); err != nil {
+				n.logger.Error("error queueing notification retry", zap.Error(err), zap.String("to", msg.to))
+			}
+		})
+	}
+}
+
+func (n *notifier) send(msg *message) error {
+	headers := []string{
+		"From: " + n.opt.From,
+		"To: " + msg.to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+	}
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + strings.Replace(msg.body, "\n", "\r\n", -1)
+	return smtp.SendMail(n.opt.Server, nil, n.opt.From, []string{msg.to}, []byte(body))
+}
+
+// loadTemplates parses the built in templates and the ones in the folder, if given.
+func loadTemplates(folder string) (map[string]map[string]*template.Template, error) {
+	templates := map[string]map[string]*template.Template{builtinLanguage: {}}
+	for name, text := range builtinTemplates {
+		tpl, err := template.New(name).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		templates[builtinLanguage][name] = tpl
+	}
+
+	if folder == "" {
+		return templates, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(folder, "*", "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		lang := filepath.Base(filepath.Dir(file))
+		name := strings.TrimSuffix(filepath.Base(file), ".tmpl")
+		tpl, err := template.ParseFiles(file)
+		if err != nil {
+			return nil, err
+		}
+		if templates[lang] == nil {
+			templates[lang] = map[string]*template.Template{}
+		}
+		templates[lang][name] = tpl
+	}
+	return templates, nil
+}
+
+// getAddress returns the e-mail address of the recipient, the account ids
+// of the guests are already their addresses.
+func getAddress(accountID, mailDomain string) string {
+	if strings.Contains(accountID, "@") {
+		return accountID
+	}
+	return accountID + "@" + mailDomain
+}
+
+func formatSize(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func getDisplayName(u *api.User) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.AccountId
+}
+
+func getUserFromContext(ctx context.Context) (*api.User, error) {
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return nil, api.NewError(api.ContextUserRequiredError)
+	}
+	return u, nil
+}
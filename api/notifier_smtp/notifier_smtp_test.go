@@ -0,0 +1,169 @@
+package notifier_smtp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+type fakeUserManager struct {
+	api.UserManager
+	members map[string][]string
+	lookups int
+}
+
+func (um *fakeUserManager) GetGroupMembers(ctx context.Context, group string) ([]string, error) {
+	um.lookups++
+	return um.members[group], nil
+}
+
+// newNotifier returns a notifier without workers, so the tests
+// can inspect the queue.
+func newNotifier(t *testing.T, opt *Options, um api.UserManager) *notifier {
+	opt.Logger = zap.NewNop()
+	opt.init()
+	templates, err := loadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &notifier{
+		opt:         opt,
+		um:          um,
+		logger:      opt.Logger,
+		templates:   templates,
+		preferences: map[string]*api.NotificationPreferences{},
+		queue:       make(chan *message, opt.QueueSize),
+		digests:     map[string]*digest{},
+		linkQuotas:  map[string]*linkQuota{},
+	}
+}
+
+func newContext(accountID string) context.Context {
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	return api.ContextSetUser(ctx, &api.User{AccountId: accountID})
+}
+
+func TestGroupIsExpandedByTheWorkers(t *testing.T) {
+	um := &fakeUserManager{members: map[string][]string{"physics": {"alice", "bob", "carol"}}}
+	n := newNotifier(t, &Options{}, um)
+	n.preferences["bob"] = &api.NotificationPreferences{OptOut: true}
+
+	share := &api.FolderShare{Id: "1", Recipient: &api.ShareRecipient{Type: api.ShareRecipient_GROUP, Identity: "physics"}}
+	if err := n.NotifyFolderShare(newContext("alice"), share, "/home/project"); err != nil {
+		t.Fatal(err)
+	}
+	if um.lookups != 0 {
+		t.Fatal("group expanded while sharing")
+	}
+	if len(n.queue) != 1 {
+		t.Fatalf("expected the group to be queued, got %d messages", len(n.queue))
+	}
+
+	msg := <-n.queue
+	if err := n.expandGroup(msg.group); err != nil {
+		t.Fatal(err)
+	}
+	if len(n.queue) != 1 {
+		t.Fatalf("expected only carol to be notified, got %d messages", len(n.queue))
+	}
+	if msg := <-n.queue; msg.to != "carol@cern.ch" {
+		t.Errorf("expected an e-mail to carol, got %s", msg.to)
+	}
+}
+
+func TestPublicLinkRecipientsAreLimited(t *testing.T) {
+	n := newNotifier(t, &Options{MaxLinkRecipients: 2, MaxLinkRecipientsPerHour: 3}, &fakeUserManager{})
+	ctx := newContext("alice")
+	link := &api.PublicLink{Id: "1", Token: "abc"}
+
+	err := n.NotifyPublicLink(ctx, link, "/home/project", []string{"a@example.org", "b@example.org", "c@example.org"})
+	if !api.IsErrorCode(err, api.NotificationLimitExceededErrorCode) {
+		t.Fatalf("expected too many recipients, got %v", err)
+	}
+	if err := n.NotifyPublicLink(ctx, link, "/home/project", []string{"a@example.org", "b@example.org"}); err != nil {
+		t.Fatal(err)
+	}
+	err = n.NotifyPublicLink(ctx, link, "/home/project", []string{"c@example.org", "d@example.org"})
+	if !api.IsErrorCode(err, api.NotificationLimitExceededErrorCode) {
+		t.Fatalf("expected the hourly quota to be exceeded, got %v", err)
+	}
+	if err := n.NotifyPublicLink(newContext("bob"), link, "/home/project", []string{"c@example.org", "d@example.org"}); err != nil {
+		t.Fatalf("the quota of other users should not be used: %v", err)
+	}
+	if len(n.queue) != 4 {
+		t.Errorf("expected 4 e-mails queued, got %d", len(n.queue))
+	}
+}
+
+// smtpSink accepts the e-mails sent to it, without authentication nor TLS,
+// and returns the data of each one in the channel.
+func smtpSink(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan string, 1)
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		fmt.Fprint(conn, "220 sink\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "DATA"):
+				fmt.Fprint(conn, "354 go ahead\r\n")
+				data := ""
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data += line
+				}
+				received <- data
+				fmt.Fprint(conn, "250 ok\r\n")
+			case strings.HasPrefix(cmd, "QUIT"):
+				fmt.Fprint(conn, "221 bye\r\n")
+				return
+			default:
+				fmt.Fprint(conn, "250 ok\r\n")
+			}
+		}
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestSend(t *testing.T) {
+	server, received := smtpSink(t)
+	n := newNotifier(t, &Options{Server: server}, &fakeUserManager{})
+
+	if err := n.send(&message{to: "bob@cern.ch", subject: "Alice shared folder 'project' with you", body: "hello\n"}); err != nil {
+		t.Fatal(err)
+	}
+	data := <-received
+	for _, expected := range []string{"To: bob@cern.ch\r\n", "Subject: Alice shared folder 'project' with you\r\n", "\r\n\r\nhello\r\n"} {
+		if !strings.Contains(data, expected) {
+			t.Errorf("expected %q in the e-mail, got %q", expected, data)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"strings"
+)
+
+// Notification types defined by the Open Cloud Mesh protocol.
+const (
+	OCMNotificationShareAccepted = "SHARE_ACCEPTED"
+	OCMNotificationShareDeclined = "SHARE_DECLINED"
+	OCMNotificationShareUnshared = "SHARE_UNSHARED"
+)
+
+// OCMProvider is the discovery document published by Open Cloud Mesh servers
+// under /ocm-provider/.
+type OCMProvider struct {
+	Enabled       bool               `json:"enabled"`
+	APIVersion    string             `json:"apiVersion"`
+	EndPoint      string             `json:"endPoint"`
+	Provider      string             `json:"provider"`
+	ResourceTypes []*OCMResourceType `json:"resourceTypes"`
+}
+
+type OCMResourceType struct {
+	Name       string            `json:"name"`
+	ShareTypes []string          `json:"shareTypes"`
+	Protocols  map[string]string `json:"protocols"`
+}
+
+// GetWebDAVPath returns the path where the remote server exposes the
+// shared files through WebDAV, like /public.php/webdav/.
+func (p *OCMProvider) GetWebDAVPath() string {
+	for _, rt := range p.ResourceTypes {
+		if rt.Name == "file" && rt.Protocols["webdav"] != "" {
+			return rt.Protocols["webdav"]
+		}
+	}
+	return "/public.php/webdav/"
+}
+
+// OCMShare is sent to the remote server to notify that a share has been
+// created for one of its users.
+type OCMShare struct {
+	ShareWith         string       `json:"shareWith"`
+	Name              string       `json:"name"`
+	Description       string       `json:"description"`
+	ProviderID        string       `json:"providerId"`
+	Owner             string       `json:"owner"`
+	Sender            string       `json:"sender"`
+	OwnerDisplayName  string       `json:"ownerDisplayName"`
+	SenderDisplayName string       `json:"senderDisplayName"`
+	ShareType         string       `json:"shareType"`
+	ResourceType      string       `json:"resourceType"`
+	Protocol          *OCMProtocol `json:"protocol"`
+}
+
+type OCMProtocol struct {
+	Name    string              `json:"name"`
+	Options *OCMProtocolOptions `json:"options"`
+}
+
+type OCMProtocolOptions struct {
+	SharedSecret string `json:"sharedSecret"`
+}
+
+// OCMNotification is sent to the remote server to notify changes on an
+// existing share, like the share being accepted by the recipient or removed by the owner.
+type OCMNotification struct {
+	NotificationType string               `json:"notificationType"`
+	ResourceType     string               `json:"resourceType"`
+	ProviderID       string               `json:"providerId"`
+	Notification     *OCMNotificationData `json:"notification"`
+}
+
+type OCMNotificationData struct {
+	SharedSecret string `json:"sharedSecret"`
+	Message      string `json:"message"`
+}
+
+// SplitFederatedID returns the user and the host of a federated
+// cloud id like gonzalhu@cernbox.cern.ch.
+// An empty host is returned if the id does not contain a host.
+func SplitFederatedID(id string) (string, string) {
+	i := strings.LastIndex(id, "@")
+	if i == -1 {
+		return id, ""
+	}
+	return id[:i], id[i+1:]
+}
+
+// GetRemoteURL returns the url of a remote server, that may
+// be given as a plain host name like cernbox.cern.ch.
+func GetRemoteURL(host string) string {
+	if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+		host = "https://" + host
+	}
+	return strings.TrimSuffix(host, "/")
+}
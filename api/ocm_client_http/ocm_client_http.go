@@ -0,0 +1,208 @@
+package ocm_client_http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/cernbox/revaold/api"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type Options struct {
+	Logger *zap.Logger
+
+	// Timeout in seconds for the requests to remote servers.
+	Timeout int
+
+	// ProviderCacheEviction is the time in seconds the discovery
+	// documents of the remote servers are kept in memory.
+	ProviderCacheEviction int
+
+	InsecureSkipVerify bool
+}
+
+func (opt *Options) init() {
+	if opt.Logger == nil {
+		opt.Logger, _ = zap.NewProduction()
+	}
+	if opt.Timeout == 0 {
+		opt.Timeout = 10
+	}
+	if opt.ProviderCacheEviction == 0 {
+		opt.ProviderCacheEviction = 3600
+	}
+}
+
+// New returns an OCMClient that discovers the Open Cloud Mesh endpoints of remote servers
+// and sends them the shares and notifications as JSON over HTTP.
+func New(opt *Options) api.OCMProviderClient {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	tr := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		TLSClientConfig:     &tls.Config{InsecureSkipVerify: opt.InsecureSkipVerify},
+	}
+	client := &http.Client{Transport: tr, Timeout: time.Duration(opt.Timeout) * time.Second}
+
+	return &ocmClient{
+		logger:        opt.Logger,
+		client:        client,
+		cache:         gcache.New(1000).LRU().Build(),
+		cacheEviction: time.Duration(opt.ProviderCacheEviction) * time.Second,
+	}
+}
+
+type ocmClient struct {
+	logger        *zap.Logger
+	client        *http.Client
+	cache         gcache.Cache
+	cacheEviction time.Duration
+}
+
+// discoveryPaths are tried in order to find the discovery document of a remote server.
+var discoveryPaths = []string{"/ocm-provider/", "/.well-known/ocm"}
+
+func (c *ocmClient) GetProvider(ctx context.Context, host string) (*api.OCMProvider, error) {
+	baseURL := api.GetRemoteURL(host)
+	if v, err := c.cache.Get(baseURL); err == nil {
+		if provider, ok := v.(*api.OCMProvider); ok {
+			return provider, nil
+		}
+	}
+
+	var lastErr error
+	for _, p := range discoveryPaths {
+		provider, err := c.discover(ctx, baseURL+p)
+		if err != nil {
+			c.logger.Debug("ocm discovery failed", zap.String("url", baseURL+p), zap.Error(err))
+			lastErr = err
+			continue
+		}
+
+		if !provider.Enabled {
+			return nil, fmt.Errorf("ocm: remote server %s has ocm disabled", host)
+		}
+		c.cache.SetWithExpire(baseURL, provider, c.cacheEviction)
+		return provider, nil
+	}
+
+	return nil, errors.Wrapf(lastErr, "ocm: cannot discover remote server %s", host)
+}
+
+func (c *ocmClient) discover(ctx context.Context, url string) (*api.OCMProvider, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected http status %d", res.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &api.OCMProvider{}
+	if err := json.Unmarshal(body, provider); err != nil {
+		return nil, err
+	}
+	if provider.EndPoint == "" {
+		return nil, errors.New("discovery document does not contain the endpoint")
+	}
+	return provider, nil
+}
+
+func (c *ocmClient) SendShare(ctx context.Context, host string, share *api.OCMShare) error {
+	return c.post(ctx, host, "/shares", share)
+}
+
+func (c *ocmClient) SendNotification(ctx context.Context, host string, notification *api.OCMNotification) error {
+	return c.post(ctx, host, "/notifications", notification)
+}
+
+func (c *ocmClient) VerifyShare(ctx context.Context, host, token string) error {
+	provider, err := c.GetProvider(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	url := api.GetRemoteURL(host) + path.Join("/", provider.GetWebDAVPath()) + "/"
+	req, err := http.NewRequest("PROPFIND", url, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Depth", "0")
+	// the token is sent as the user name, like ownCloud does for federated shares
+	req.SetBasicAuth(token, "")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "ocm: error verifying share with %s", url)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusMultiStatus {
+		return fmt.Errorf("ocm: remote server %s answered the share verification with http status %d", host, res.StatusCode)
+	}
+	return nil
+}
+
+func (c *ocmClient) post(ctx context.Context, host, endpoint string, payload interface{}) error {
+	provider, err := c.GetProvider(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(provider.EndPoint, "/") + endpoint
+	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "ocm: error sending request to %s", url)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(res.Body)
+		c.logger.Error("ocm request rejected by remote server", zap.String("url", url), zap.Int("http_code", res.StatusCode), zap.String("body", string(body)))
+		return fmt.Errorf("ocm: remote server %s answered with http status %d", host, res.StatusCode)
+	}
+
+	c.logger.Info("ocm request sent to remote server", zap.String("url", url), zap.Int("http_code", res.StatusCode))
+	return nil
+}
@@ -0,0 +1,277 @@
+package ownership_transferer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/cernbox/revaold/api"
+	"go.uber.org/zap"
+)
+
+type Options struct {
+	Logger *zap.Logger
+
+	// HomePrefix is the mount point of the homes of the users,
+	// the data is transferred under <HomePrefix>/transferred-from-<from>.
+	HomePrefix string
+}
+
+func (opt *Options) init() {
+	if opt.Logger == nil {
+		l, _ := zap.NewProduction()
+		opt.Logger = l
+	}
+	if opt.HomePrefix == "" {
+		opt.HomePrefix = "/home"
+	}
+}
+
+// New returns a transferer that copies the data to the home of the new owner and
+// then moves the shares and links of the data to it.
+// The homes of two users are different storages for the virtual storage, so the data
+// is always copied and the original is deleted only when everything was transferred.
+func New(opt *Options, vs api.VirtualStorage, sm api.ShareManager, lm api.PublicLinkManager) api.OwnershipTransferer {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+	return &transferer{vs: vs, sm: sm, lm: lm, logger: opt.Logger, homePrefix: opt.HomePrefix}
+}
+
+type transferer struct {
+	// mu serializes transfers, so two of them cannot copy to the same target
+	mu         sync.Mutex
+	vs         api.VirtualStorage
+	sm         api.ShareManager
+	lm         api.PublicLinkManager
+	logger     *zap.Logger
+	homePrefix string
+}
+
+// sharedItem is a share or link found under the transferred path,
+// with its path relative to it.
+type sharedItem struct {
+	share        *api.FolderShare
+	link         *api.PublicLink
+	relativePath string
+}
+
+func (t *transferer) TransferOwnership(ctx context.Context, from, to, p string) ([]*api.TransferredItem, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if from == "" || to == "" || from == to {
+		return nil, api.NewError(api.PathInvalidError).WithMessage("the users must be different and not empty")
+	}
+
+	fromCtx := t.getUserContext(ctx, from)
+	toCtx := t.getUserContext(ctx, to)
+
+	md, err := t.vs.GetMetadata(fromCtx, p)
+	if err != nil {
+		t.logger.Error("error getting metadata of path to transfer", zap.Error(err), zap.String("from", from), zap.String("path", p))
+		return nil, err
+	}
+
+	transferFolder := path.Join(t.homePrefix, "transferred-from-"+from)
+	targetPath := path.Join(transferFolder, path.Base(md.Path))
+	if _, err := t.vs.GetMetadata(toCtx, targetPath); err == nil {
+		return nil, api.NewError(api.StorageAlreadyExistsErrorCode).WithMessage(targetPath + " already exists in the storage of " + to)
+	}
+
+	// the shares and links are collected before copying,
+	// as they cannot be found anymore once the original is deleted.
+	items, err := t.getSharedItems(fromCtx, md)
+	if err != nil {
+		t.logger.Error("error listing shares to transfer", zap.Error(err), zap.String("from", from), zap.String("path", md.Path))
+		return nil, err
+	}
+
+	data := &api.TransferredItem{Kind: api.TransferredItem_DATA, OldPath: md.Path, NewPath: targetPath}
+	report := []*api.TransferredItem{data}
+
+	if _, err := t.vs.GetMetadata(toCtx, transferFolder); err != nil {
+		if err := t.vs.CreateDir(toCtx, transferFolder); err != nil {
+			t.setResult(data, err)
+			return report, nil
+		}
+	}
+
+	if err := t.copy(fromCtx, md, toCtx, targetPath, data); err != nil {
+		// the original and its shares are left untouched, only the partial copy needs to be cleaned
+		t.setResult(data, err)
+		if err := t.vs.Delete(toCtx, targetPath); err != nil {
+			t.logger.Error("error deleting partial copy, fix manually", zap.Error(err), zap.String("to", to), zap.String("path", targetPath))
+		}
+		return report, nil
+	}
+
+	failed := false
+	for _, item := range items {
+		oldPath := path.Join(md.Path, item.relativePath)
+		newPath := path.Join(targetPath, item.relativePath)
+		var transferred *api.TransferredItem
+		if item.share != nil && isSharedWith(item.share, to) {
+			// the new owner does not need a share with itself
+			transferred = t.dropShare(fromCtx, item.share, oldPath)
+		} else if item.share != nil {
+			transferred = t.transferShare(ctx, toCtx, to, item.share, oldPath, newPath)
+		} else {
+			transferred = t.transferLink(ctx, to, item.link, oldPath, newPath)
+		}
+		if !transferred.Transferred {
+			failed = true
+		}
+		report = append(report, transferred)
+	}
+
+	// the original is kept if something could not be transferred, so it can be fixed by hand
+	if failed {
+		t.setResult(data, fmt.Errorf("some shares or links could not be transferred, %s has not been deleted", md.Path))
+		return report, nil
+	}
+	t.setResult(data, t.vs.Delete(fromCtx, md.Path))
+
+	t.logger.Info("ownership transferred", zap.String("from", from), zap.String("to", to), zap.String("path", md.Path), zap.String("new_path", targetPath), zap.Uint64("files", data.Files), zap.Uint64("size", data.Size), zap.Int("shares", len(items)))
+	return report, nil
+}
+
+func (t *transferer) getSharedItems(ctx context.Context, md *api.Metadata) ([]*sharedItem, error) {
+	shares, err := t.sm.ListFolderSharesInPath(ctx, md.Path)
+	if err != nil {
+		return nil, err
+	}
+	links, err := t.lm.ListPublicLinksInPath(ctx, md.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*sharedItem{}
+	for _, share := range shares {
+		rel, err := t.getRelativePath(ctx, share.Path, md.Path)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &sharedItem{share: share, relativePath: rel})
+	}
+	for _, link := range links {
+		rel, err := t.getRelativePath(ctx, link.Path, md.Path)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &sharedItem{link: link, relativePath: rel})
+	}
+	return items, nil
+}
+
+func (t *transferer) getRelativePath(ctx context.Context, fileID, root string) (string, error) {
+	md, err := t.vs.GetMetadata(ctx, fileID)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(md.Path, root), nil
+}
+
+// copy copies recursively the resource described by md to target.
+func (t *transferer) copy(fromCtx context.Context, md *api.Metadata, toCtx context.Context, target string, data *api.TransferredItem) error {
+	if !md.IsDir {
+		r, err := t.vs.Download(fromCtx, md.Path)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		if err := t.vs.Upload(toCtx, target, r); err != nil {
+			return err
+		}
+		data.Files++
+		data.Size += md.Size
+		return nil
+	}
+
+	if err := t.vs.CreateDir(toCtx, target); err != nil {
+		return err
+	}
+
+	children, err := t.vs.ListFolder(fromCtx, md.Path)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := t.copy(fromCtx, child, toCtx, path.Join(target, path.Base(child.Path)), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *transferer) transferShare(ctx, toCtx context.Context, to string, share *api.FolderShare, oldPath, newPath string) *api.TransferredItem {
+	item := &api.TransferredItem{
+		Kind:      api.TransferredItem_FOLDER_SHARE,
+		Id:        share.Id,
+		OldPath:   oldPath,
+		NewPath:   newPath,
+		Recipient: share.Recipient,
+	}
+
+	newShare, err := t.sm.TransferFolderShare(ctx, share.Id, to, newPath)
+	if err != nil {
+		t.setResult(item, err)
+		return item
+	}
+
+	// remote recipients access the share with a token and guests with the
+	// identity of the owner, there is no acl to set
+	if newShare.Recipient.Type == api.ShareRecipient_REMOTE || newShare.Recipient.Type == api.ShareRecipient_GUEST {
+		t.setResult(item, nil)
+		return item
+	}
+
+	t.setResult(item, t.vs.SetACL(toCtx, newPath, newShare.ReadOnly, newShare.Recipient, []*api.FolderShare{}))
+	return item
+}
+
+// dropShare removes a share whose recipient becomes the owner of the data.
+func (t *transferer) dropShare(fromCtx context.Context, share *api.FolderShare, oldPath string) *api.TransferredItem {
+	item := &api.TransferredItem{
+		Kind:      api.TransferredItem_FOLDER_SHARE,
+		Id:        share.Id,
+		OldPath:   oldPath,
+		Recipient: share.Recipient,
+	}
+	t.setResult(item, t.sm.Unshare(fromCtx, share.Id))
+	return item
+}
+
+// isSharedWith returns true if the share is with the user directly.
+func isSharedWith(share *api.FolderShare, accountID string) bool {
+	return share.Recipient.Type == api.ShareRecipient_USER && share.Recipient.Identity == accountID
+}
+
+func (t *transferer) transferLink(ctx context.Context, to string, link *api.PublicLink, oldPath, newPath string) *api.TransferredItem {
+	item := &api.TransferredItem{
+		Kind:    api.TransferredItem_PUBLIC_LINK,
+		Id:      link.Id,
+		OldPath: oldPath,
+		NewPath: newPath,
+	}
+	_, err := t.lm.TransferPublicLink(ctx, link.Id, to, newPath)
+	t.setResult(item, err)
+	return item
+}
+
+func (t *transferer) setResult(item *api.TransferredItem, err error) {
+	if err != nil {
+		t.logger.Error("error transferring ownership", zap.Error(err), zap.String("kind", item.Kind.String()), zap.String("id", item.Id), zap.String("path", item.OldPath), zap.String("new_path", item.NewPath))
+		item.Error = err.Error()
+		item.Transferred = false
+		return
+	}
+	item.Transferred = true
+}
+
+func (t *transferer) getUserContext(ctx context.Context, accountID string) context.Context {
+	return api.ContextSetUser(ctx, &api.User{AccountId: accountID, Groups: []string{}})
+}
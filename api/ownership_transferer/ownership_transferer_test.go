@@ -0,0 +1,206 @@
+package ownership_transferer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"go.uber.org/zap"
+)
+
+// fakeStorage keeps the files of all the users in a map indexed by path,
+// the paths are the same for all the users and the file ids are the paths.
+type fakeStorage struct {
+	api.VirtualStorage
+	dirs      map[string]bool
+	files     map[string]string
+	failWrite string
+	open      int
+}
+
+func (fs *fakeStorage) GetMetadata(ctx context.Context, p string) (*api.Metadata, error) {
+	if fs.dirs[p] {
+		return &api.Metadata{Id: p, Path: p, IsDir: true}, nil
+	}
+	if data, ok := fs.files[p]; ok {
+		return &api.Metadata{Id: p, Path: p, Size: uint64(len(data))}, nil
+	}
+	return nil, api.NewError(api.StorageNotFoundErrorCode)
+}
+
+func (fs *fakeStorage) ListFolder(ctx context.Context, p string) ([]*api.Metadata, error) {
+	mds := []*api.Metadata{}
+	for d := range fs.dirs {
+		if path.Dir(d) == p && d != p {
+			mds = append(mds, &api.Metadata{Id: d, Path: d, IsDir: true})
+		}
+	}
+	for f := range fs.files {
+		if path.Dir(f) == p {
+			mds = append(mds, &api.Metadata{Id: f, Path: f})
+		}
+	}
+	return mds, nil
+}
+
+func (fs *fakeStorage) CreateDir(ctx context.Context, p string) error {
+	fs.dirs[p] = true
+	return nil
+}
+
+func (fs *fakeStorage) Delete(ctx context.Context, p string) error {
+	for d := range fs.dirs {
+		if d == p || isUnder(d, p) {
+			delete(fs.dirs, d)
+		}
+	}
+	for f := range fs.files {
+		if f == p || isUnder(f, p) {
+			delete(fs.files, f)
+		}
+	}
+	return nil
+}
+
+func (fs *fakeStorage) Download(ctx context.Context, p string) (io.ReadCloser, error) {
+	fs.open++
+	return &trackedReader{Reader: bytes.NewBufferString(fs.files[p]), fs: fs}, nil
+}
+
+func (fs *fakeStorage) Upload(ctx context.Context, p string, r io.ReadCloser) error {
+	if p == fs.failWrite {
+		return errors.New("disk full")
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	fs.files[p] = string(data)
+	return nil
+}
+
+func (fs *fakeStorage) SetACL(ctx context.Context, p string, readOnly bool, recipient *api.ShareRecipient, shareList []*api.FolderShare) error {
+	return nil
+}
+
+type trackedReader struct {
+	io.Reader
+	fs *fakeStorage
+}
+
+func (r *trackedReader) Close() error {
+	r.fs.open--
+	return nil
+}
+
+func isUnder(p, root string) bool {
+	return len(p) > len(root) && p[:len(root)+1] == root+"/"
+}
+
+type fakeShareManager struct {
+	api.ShareManager
+	shares      []*api.FolderShare
+	unshared    []string
+	transferred []string
+}
+
+func (sm *fakeShareManager) ListFolderSharesInPath(ctx context.Context, p string) ([]*api.FolderShare, error) {
+	return sm.shares, nil
+}
+
+func (sm *fakeShareManager) Unshare(ctx context.Context, id string) error {
+	sm.unshared = append(sm.unshared, id)
+	return nil
+}
+
+func (sm *fakeShareManager) TransferFolderShare(ctx context.Context, id, newOwner, newPath string) (*api.FolderShare, error) {
+	sm.transferred = append(sm.transferred, id)
+	for _, s := range sm.shares {
+		if s.Id == id {
+			return &api.FolderShare{Id: id, OwnerId: newOwner, Path: newPath, Recipient: s.Recipient}, nil
+		}
+	}
+	return nil, api.NewError(api.FolderShareNotFoundErrorCode)
+}
+
+type fakeLinkManager struct {
+	api.PublicLinkManager
+}
+
+func (lm *fakeLinkManager) ListPublicLinksInPath(ctx context.Context, p string) ([]*api.PublicLink, error) {
+	return []*api.PublicLink{}, nil
+}
+
+func newStorage() *fakeStorage {
+	return &fakeStorage{
+		dirs: map[string]bool{"/home": true, "/home/project": true, "/home/project/data": true},
+		files: map[string]string{
+			"/home/project/a.txt":      "a",
+			"/home/project/data/b.txt": "b",
+		},
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	fs := newStorage()
+	sm := &fakeShareManager{shares: []*api.FolderShare{
+		{Id: "1", Path: "/home/project/data", Recipient: &api.ShareRecipient{Type: api.ShareRecipient_USER, Identity: "carol"}},
+		{Id: "2", Path: "/home/project", Recipient: &api.ShareRecipient{Type: api.ShareRecipient_USER, Identity: "bob"}},
+	}}
+	tr := New(&Options{Logger: zap.NewNop()}, fs, sm, &fakeLinkManager{})
+
+	report, err := tr.TransferOwnership(context.Background(), "alice", "bob", "/home/project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range report {
+		if !item.Transferred {
+			t.Errorf("%s %s not transferred: %s", item.Kind, item.OldPath, item.Error)
+		}
+	}
+
+	if fs.files["/home/transferred-from-alice/project/data/b.txt"] != "b" {
+		t.Error("data not copied")
+	}
+	if _, ok := fs.files["/home/project/a.txt"]; ok {
+		t.Error("original not deleted")
+	}
+	if fs.open != 0 {
+		t.Errorf("%d downloads not closed", fs.open)
+	}
+	if len(sm.transferred) != 1 || sm.transferred[0] != "1" {
+		t.Errorf("expected only the share with carol to be transferred, got %v", sm.transferred)
+	}
+	if len(sm.unshared) != 1 || sm.unshared[0] != "2" {
+		t.Errorf("expected the share with the new owner to be removed, got %v", sm.unshared)
+	}
+}
+
+func TestTransferOwnershipDeletesPartialCopy(t *testing.T) {
+	fs := newStorage()
+	fs.failWrite = "/home/transferred-from-alice/project/data/b.txt"
+	sm := &fakeShareManager{shares: []*api.FolderShare{}}
+	tr := New(&Options{Logger: zap.NewNop()}, fs, sm, &fakeLinkManager{})
+
+	report, err := tr.TransferOwnership(context.Background(), "alice", "bob", "/home/project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report[0].Transferred {
+		t.Fatal("expected the copy to fail")
+	}
+	if _, err := fs.GetMetadata(context.Background(), "/home/transferred-from-alice/project"); err == nil {
+		t.Error("partial copy not deleted")
+	}
+	if fs.files["/home/project/a.txt"] != "a" {
+		t.Error("original modified")
+	}
+	if fs.open != 0 {
+		t.Errorf("%d downloads not closed", fs.open)
+	}
+}
@@ -0,0 +1,864 @@
+package public_link_manager_json
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	gopath "path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/api/jsonfile"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const defaultTokenLength = 15
+const versionPrefix = ".sys.v#."
+
+// maxNameLength is the same as in the owncloud link manager,
+// so links can be moved between both backends.
+const maxNameLength = 64
+
+// maxTokenAttempts is the number of tokens generated before giving up
+// when the generated ones collide with existing links.
+const maxTokenAttempts = 5
+
+// vanityTokenRegexp defines the tokens that owners can choose for their links.
+// Tokens are part of the link URL, so only URL safe characters are allowed.
+// Short tokens could be guessed, so they need at least 10 characters.
+var vanityTokenRegexp = regexp.MustCompile("^[a-zA-Z0-9_-]{10,64}$")
+
+// The permissions use the owncloud values.
+const (
+	permissionsReadOnly  = 1
+	permissionsDropOnly  = 4
+	permissionsReadWrite = 15
+)
+
+// New returns a public link manager that keeps the links in a JSON file, meant for
+// development and tests where there is no ownCloud database.
+// The file is read once and rewritten after every change, so it must not be shared
+// between several revad instances. The tokenLength is the length of the generated
+// tokens, if it is not positive the default length is used.
+func New(file string, tokenLength int, vfs api.VirtualStorage) (api.PublicLinkManager, error) {
+	if tokenLength <= 0 {
+		tokenLength = defaultTokenLength
+	}
+
+	lm := &linkManager{file: file, vfs: vfs, tokenLength: tokenLength, db: &linkDB{NextID: 1, Links: map[string]*link{}}}
+	if err := lm.load(); err != nil {
+		return nil, err
+	}
+	return lm, nil
+}
+
+type linkManager struct {
+	mu          sync.Mutex
+	file        string
+	vfs         api.VirtualStorage
+	tokenLength int
+	db          *linkDB
+}
+
+type linkDB struct {
+	NextID int64            `json:"next_id"`
+	Links  map[string]*link `json:"links"`
+}
+
+type link struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	// FileID is the id of the folder or, for files, the id of
+	// their versions folder, which does not change between versions.
+	FileID      string `json:"file_id"`
+	IsDir       bool   `json:"is_dir"`
+	Token       string `json:"token"`
+	Password    string `json:"password,omitempty"`
+	Expiration  uint64 `json:"expiration,omitempty"`
+	Permissions int    `json:"permissions"`
+	STime       uint64 `json:"stime"`
+	Name        string `json:"name"`
+
+	MaxDownloads uint64          `json:"max_downloads,omitempty"`
+	MaxVisitors  uint64          `json:"max_visitors,omitempty"`
+	Downloads    uint64          `json:"downloads,omitempty"`
+	Uploads      uint64          `json:"uploads,omitempty"`
+	Visitors     map[string]bool `json:"visitors,omitempty"`
+	LastAccess   uint64          `json:"last_access,omitempty"`
+
+	UploadPolicy  *api.UploadPolicy `json:"upload_policy,omitempty"`
+	UploadedBytes uint64            `json:"uploaded_bytes,omitempty"`
+
+	Labels []string `json:"labels,omitempty"`
+}
+
+func (lm *linkManager) load() error {
+	db := &linkDB{NextID: 1}
+	if err := jsonfile.Load(lm.file, db); err != nil {
+		return err
+	}
+	if db.Links == nil {
+		db.Links = map[string]*link{}
+	}
+	lm.db = db
+	return nil
+}
+
+// update applies change to a copy of the links and saves it, the links in memory
+// are only replaced once the copy is saved. It must be called with the lock held.
+func (lm *linkManager) update(change func(db *linkDB) error) error {
+	db := &linkDB{NextID: lm.db.NextID, Links: make(map[string]*link, len(lm.db.Links))}
+	for k, ln := range lm.db.Links {
+		db.Links[k] = copyLink(ln)
+	}
+	if err := change(db); err != nil {
+		return err
+	}
+	if err := jsonfile.Save(lm.file, db); err != nil {
+		return err
+	}
+	lm.db = db
+	return nil
+}
+
+func (lm *linkManager) AuthenticatePublicLink(ctx context.Context, token, password string) (*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	ln, err := lm.getLinkByToken(token)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	pb, err := lm.convertToPublicLink(ctx, ln)
+	if err != nil {
+		l.Error("error converting link to public link", zap.Error(err))
+		return nil, err
+	}
+
+	// check expiration time
+	if pb.Expires != 0 && uint64(time.Now().Unix()) > pb.Expires {
+		l.Warn("public link has expired", zap.String("id", pb.Id))
+		return nil, api.NewError(api.PublicLinkInvalidExpireDateErrorCode)
+	}
+
+	if pb.Protected && !checkPasswordHash(password, ln.Password) {
+		return nil, api.NewError(api.PublicLinkInvalidPasswordErrorCode)
+	}
+
+	return pb, nil
+}
+
+func (lm *linkManager) IsPublicLinkProtected(ctx context.Context, token string) (bool, error) {
+	l := ctx_zap.Extract(ctx)
+	ln, err := lm.getLinkByToken(token)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return false, err
+	}
+	return ln.Password != "", nil
+}
+
+func (lm *linkManager) InspectPublicLinkByToken(ctx context.Context, token string) (*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	ln, err := lm.getLinkByToken(token)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	pb, err := lm.convertToPublicLink(ctx, ln)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	return pb, nil
+}
+
+func (lm *linkManager) CreatePublicLink(ctx context.Context, path string, opt *api.PublicLinkOptions) (*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	md, err := lm.vfs.GetMetadata(ctx, path)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	fileID, err := lm.getLinkFileID(ctx, md)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	if len(opt.Name) > maxNameLength {
+		err := api.NewError(api.PublicLinkInvalidNameErrorCode).WithMessage(fmt.Sprintf("names cannot be longer than %d characters", maxNameLength))
+		l.Warn("", zap.Error(err))
+		return nil, err
+	}
+
+	labels, err := api.CleanPublicLinkLabels(opt.Labels)
+	if err != nil {
+		l.Warn("", zap.Error(err))
+		return nil, err
+	}
+
+	if opt.Token != "" && !vanityTokenRegexp.MatchString(opt.Token) {
+		err := api.NewError(api.PublicLinkInvalidTokenErrorCode).WithMessage("tokens must have between 10 and 64 letters, digits, dashes or underscores")
+		l.Warn("", zap.Error(err), zap.String("token", opt.Token))
+		return nil, err
+	}
+
+	// links without name are named after the resource, like owncloud does.
+	name := opt.Name
+	if name == "" {
+		name = gopath.Base(path)
+	}
+
+	ln := &link{
+		Owner:        u.AccountId,
+		FileID:       fileID,
+		IsDir:        md.IsDir,
+		Expiration:   opt.Expiration,
+		Permissions:  getPermissions(opt.ReadOnly, opt.DropOnly),
+		STime:        uint64(time.Now().Unix()),
+		Name:         name,
+		MaxDownloads: opt.MaxDownloads,
+		MaxVisitors:  opt.MaxVisitors,
+		UploadPolicy: copyUploadPolicy(opt.UploadPolicy),
+		Labels:       labels,
+	}
+
+	if opt.Password != "" {
+		hashedPassword, err := hashPassword(opt.Password)
+		if err != nil {
+			return nil, err
+		}
+		ln.Password = hashedPassword
+	}
+
+	for attempt := 1; ; attempt++ {
+		ln.Token = opt.Token
+		if ln.Token == "" {
+			ln.Token, err = api.GenToken(lm.tokenLength)
+			if err != nil {
+				l.Error("error generating token", zap.Error(err))
+				return nil, err
+			}
+		}
+
+		err = lm.insertLink(ln)
+		if err == nil {
+			break
+		}
+
+		if !api.IsErrorCode(err, api.PublicLinkTokenAlreadyExistsErrorCode) {
+			l.Error("", zap.Error(err))
+			return nil, err
+		}
+
+		// a token chosen by the owner is not replaced by a generated one.
+		if opt.Token != "" {
+			l.Warn("token already in use", zap.String("token", ln.Token))
+			return nil, err
+		}
+
+		if attempt == maxTokenAttempts {
+			l.Error("cannot generate a unique token", zap.Int("attempts", attempt))
+			return nil, err
+		}
+		l.Warn("generated token already in use, generating a new one", zap.Int("attempt", attempt))
+	}
+	l.Info("created public link", zap.Int64("id", ln.ID))
+
+	pb, err := lm.InspectPublicLink(ctx, getKey(ln.ID))
+	if err != nil {
+		l.Error("error inspecting public link", zap.Error(err))
+		return nil, err
+	}
+	return pb, nil
+}
+
+// insertLink assigns an id to the link and stores it, only if its token is not used yet.
+func (lm *linkManager) insertLink(ln *link) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.update(func(db *linkDB) error {
+		for _, other := range db.Links {
+			if other.Token == ln.Token {
+				return api.NewError(api.PublicLinkTokenAlreadyExistsErrorCode)
+			}
+		}
+
+		ln.ID = db.NextID
+		db.NextID++
+		db.Links[getKey(ln.ID)] = copyLink(ln)
+		return nil
+	})
+}
+
+func (lm *linkManager) UpdatePublicLink(ctx context.Context, id string, opt *api.PublicLinkOptions) (*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	pb, err := lm.InspectPublicLink(ctx, id)
+	if err != nil {
+		l.Error("error getting link before update", zap.Error(err))
+		return nil, err
+	}
+
+	var hashedPassword string
+	if opt.UpdatePassword && opt.Password != "" {
+		hashedPassword, err = hashPassword(opt.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var name string
+	if opt.UpdateName {
+		if len(opt.Name) > maxNameLength {
+			err := api.NewError(api.PublicLinkInvalidNameErrorCode).WithMessage(fmt.Sprintf("names cannot be longer than %d characters", maxNameLength))
+			l.Warn("", zap.Error(err))
+			return nil, err
+		}
+
+		name = opt.Name
+		if name == "" {
+			// go back to the default name
+			md, err := lm.vfs.GetMetadata(ctx, pb.Path)
+			if err != nil {
+				l.Error("error getting metadata for default link name", zap.Error(err))
+				return nil, err
+			}
+			name = gopath.Base(md.Path)
+		}
+	}
+
+	var labels []string
+	if opt.UpdateLabels {
+		labels, err = api.CleanPublicLinkLabels(opt.Labels)
+		if err != nil {
+			l.Warn("", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	err = lm.updateLink(u.AccountId, id, func(ln *link) {
+		if opt.UpdatePassword {
+			ln.Password = hashedPassword
+		}
+		if opt.UpdateExpiration {
+			ln.Expiration = opt.Expiration
+		}
+		if opt.UpdateName {
+			ln.Name = name
+		}
+		if opt.UpdateReadOnly || opt.UpdateDropOnly {
+			ln.Permissions = getPermissions(opt.ReadOnly, opt.DropOnly)
+		}
+		if opt.UpdateMaxDownloads {
+			ln.MaxDownloads = opt.MaxDownloads
+		}
+		if opt.UpdateMaxVisitors {
+			ln.MaxVisitors = opt.MaxVisitors
+		}
+		if opt.UpdateUploadPolicy {
+			ln.UploadPolicy = copyUploadPolicy(opt.UploadPolicy)
+		}
+		if opt.UpdateLabels {
+			ln.Labels = labels
+		}
+	})
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	l.Info("updated public link", zap.String("id", id))
+
+	pb, err = lm.InspectPublicLink(ctx, id)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	return pb, nil
+}
+
+// updateLink applies update to the link of the owner and stores the result.
+func (lm *linkManager) updateLink(owner, id string, update func(*link)) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.update(func(db *linkDB) error {
+		ln, ok := db.Links[id]
+		if !ok || ln.Owner != owner {
+			return api.NewError(api.PublicLinkNotFoundErrorCode)
+		}
+		update(ln)
+		return nil
+	})
+}
+
+func (lm *linkManager) InspectPublicLink(ctx context.Context, id string) (*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	lm.mu.Lock()
+	ln, ok := lm.db.Links[id]
+	if ok {
+		ln = copyLink(ln)
+	}
+	lm.mu.Unlock()
+
+	if !ok || ln.Owner != u.AccountId {
+		err := api.NewError(api.PublicLinkNotFoundErrorCode)
+		l.Error("cannot get link", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+
+	pb, err := lm.convertToPublicLink(ctx, ln)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	return pb, nil
+}
+
+func (lm *linkManager) ListPublicLinks(ctx context.Context, filterByPath string) ([]*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileID string
+	if filterByPath != "" {
+		md, err := lm.vfs.GetMetadata(ctx, filterByPath)
+		if err != nil {
+			return nil, err
+		}
+
+		fileID = getFileID(md)
+		if !md.IsDir {
+			// if the version folder does not exist the file
+			// is not shared by link and the file id matches nothing.
+			if mdVersion, err := lm.vfs.GetMetadata(ctx, getVersionFolder(md.Path)); err == nil {
+				fileID = getFileID(mdVersion)
+			}
+		}
+	}
+
+	links := lm.filter(func(ln *link) bool {
+		return ln.Owner == u.AccountId && (fileID == "" || ln.FileID == fileID)
+	})
+
+	publicLinks := []*api.PublicLink{}
+	for _, ln := range links {
+		pb, err := lm.convertToPublicLink(ctx, ln)
+		if err != nil {
+			l.Error("", zap.Error(err))
+			continue
+		}
+		publicLinks = append(publicLinks, pb)
+	}
+	return publicLinks, nil
+}
+
+// ListPublicLinksInPath returns the links of the user on the path and on any resource under it.
+func (lm *linkManager) ListPublicLinksInPath(ctx context.Context, p string) ([]*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	md, err := lm.vfs.GetMetadata(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := lm.ListPublicLinks(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	publicLinks := []*api.PublicLink{}
+	for _, link := range links {
+		linkMd, err := lm.vfs.GetMetadata(ctx, link.Path)
+		if err != nil {
+			l.Warn("error resolving path of public link", zap.String("id", link.Id), zap.String("fileid", link.Path), zap.Error(err))
+			continue
+		}
+		if api.IsInPath(linkMd.Path, md.Path) {
+			publicLinks = append(publicLinks, link)
+		}
+	}
+	return publicLinks, nil
+}
+
+// ListPublicLinksOfFileIDs returns the links of the user on the resources with the given file ids.
+func (lm *linkManager) ListPublicLinksOfFileIDs(ctx context.Context, fileIDs []string) ([]*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, fileID := range fileIDs {
+		md, err := lm.vfs.GetMetadata(ctx, fileID)
+		if err != nil {
+			l.Warn("error resolving resource of public links", zap.String("fileid", fileID), zap.Error(err))
+			continue
+		}
+		if !md.IsDir {
+			// without version folder the file is not shared by link
+			if md, err = lm.vfs.GetMetadata(ctx, getVersionFolder(md.Path)); err != nil {
+				continue
+			}
+		}
+		ids[getFileID(md)] = true
+	}
+
+	links := lm.filter(func(ln *link) bool {
+		return ln.Owner == u.AccountId && ids[ln.FileID]
+	})
+
+	publicLinks := []*api.PublicLink{}
+	for _, ln := range links {
+		pb, err := lm.convertToPublicLink(ctx, ln)
+		if err != nil {
+			l.Error("", zap.Error(err))
+			continue
+		}
+		publicLinks = append(publicLinks, pb)
+	}
+	return publicLinks, nil
+}
+
+func (lm *linkManager) TransferPublicLink(ctx context.Context, id, newOwner, newPath string) (*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	ownerCtx := api.ContextSetUser(ctx, &api.User{AccountId: newOwner, Groups: []string{}})
+	md, err := lm.vfs.GetMetadata(ownerCtx, newPath)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	fileID, err := lm.getLinkFileID(ownerCtx, md)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	lm.mu.Lock()
+	err = lm.update(func(db *linkDB) error {
+		ln, ok := db.Links[id]
+		if !ok {
+			return api.NewError(api.PublicLinkNotFoundErrorCode)
+		}
+		ln.Owner = newOwner
+		ln.FileID = fileID
+		ln.IsDir = md.IsDir
+		return nil
+	})
+	lm.mu.Unlock()
+	if err != nil {
+		l.Error("error saving links", zap.Error(err))
+		return nil, err
+	}
+
+	l.Info("transferred public link", zap.String("id", id), zap.String("new_owner", newOwner), zap.String("new_path", newPath))
+	return lm.InspectPublicLink(ownerCtx, id)
+}
+
+func (lm *linkManager) RevokePublicLink(ctx context.Context, id string) error {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	return lm.update(func(db *linkDB) error {
+		ln, ok := db.Links[id]
+		if !ok || ln.Owner != u.AccountId {
+			err := api.NewError(api.PublicLinkNotFoundErrorCode)
+			l.Error("", zap.Error(err), zap.String("id", id))
+			return err
+		}
+		delete(db.Links, id)
+		return nil
+	})
+}
+
+func (lm *linkManager) RecordPublicLinkDownload(ctx context.Context, id, visitor string) error {
+	l := ctx_zap.Extract(ctx)
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.update(func(db *linkDB) error {
+		ln, ok := db.Links[id]
+		if !ok {
+			return api.NewError(api.PublicLinkNotFoundErrorCode)
+		}
+
+		// visitors already seen are always allowed.
+		if visitor != "" && ln.MaxVisitors > 0 && uint64(len(ln.Visitors)) >= ln.MaxVisitors && !ln.Visitors[visitor] {
+			l.Warn("public link reached maximum number of visitors", zap.String("id", id), zap.Uint64("max_visitors", ln.MaxVisitors))
+			return api.NewError(api.PublicLinkLimitReachedErrorCode).WithMessage("maximum number of visitors reached")
+		}
+
+		if ln.MaxDownloads > 0 && ln.Downloads >= ln.MaxDownloads {
+			l.Warn("public link reached maximum number of downloads", zap.String("id", id), zap.Uint64("max_downloads", ln.MaxDownloads))
+			return api.NewError(api.PublicLinkLimitReachedErrorCode).WithMessage("maximum number of downloads reached")
+		}
+
+		ln.Downloads++
+		ln.LastAccess = uint64(time.Now().Unix())
+		if visitor != "" {
+			ln.Visitors[visitor] = true
+		}
+		return nil
+	})
+}
+
+func (lm *linkManager) RecordPublicLinkUpload(ctx context.Context, id string, size uint64) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.update(func(db *linkDB) error {
+		ln, ok := db.Links[id]
+		if !ok {
+			return api.NewError(api.PublicLinkNotFoundErrorCode)
+		}
+		ln.Uploads++
+		ln.UploadedBytes += size
+		ln.LastAccess = uint64(time.Now().Unix())
+		return nil
+	})
+}
+
+func (lm *linkManager) ReservePublicLinkUpload(ctx context.Context, id string, size uint64) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.update(func(db *linkDB) error {
+		ln, ok := db.Links[id]
+		if !ok {
+			return api.NewError(api.PublicLinkNotFoundErrorCode)
+		}
+		if ln.UploadPolicy != nil && ln.UploadPolicy.MaxTotalSize > 0 && ln.UploadedBytes+size > ln.UploadPolicy.MaxTotalSize {
+			return api.NewError(api.PublicLinkUploadTooLargeErrorCode).WithMessage("the upload exceeds the maximum total size of the link")
+		}
+		ln.UploadedBytes += size
+		return nil
+	})
+}
+
+func (lm *linkManager) ReleasePublicLinkUpload(ctx context.Context, id string, size uint64) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.update(func(db *linkDB) error {
+		ln, ok := db.Links[id]
+		if !ok {
+			return api.NewError(api.PublicLinkNotFoundErrorCode)
+		}
+		if ln.UploadedBytes > size {
+			ln.UploadedBytes -= size
+		} else {
+			ln.UploadedBytes = 0
+		}
+		return nil
+	})
+}
+
+func (lm *linkManager) getLinkByToken(token string) (*link, error) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	for _, ln := range lm.db.Links {
+		if ln.Token == token {
+			return copyLink(ln), nil
+		}
+	}
+	return nil, api.NewError(api.PublicLinkNotFoundErrorCode)
+}
+
+// filter returns copies of the links accepted by match, sorted by id.
+func (lm *linkManager) filter(match func(*link) bool) []*link {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	links := []*link{}
+	for _, ln := range lm.db.Links {
+		if match(ln) {
+			links = append(links, copyLink(ln))
+		}
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].ID < links[j].ID })
+	return links
+}
+
+// getLinkFileID returns the id stored for a link to the resource. Links to files
+// point to the versions folder, so they survive the upload of new versions.
+func (lm *linkManager) getLinkFileID(ctx context.Context, md *api.Metadata) (string, error) {
+	if md.IsDir {
+		return getFileID(md), nil
+	}
+
+	versionFolder := getVersionFolder(md.Path)
+	mdVersion, err := lm.vfs.GetMetadata(ctx, versionFolder)
+	if err != nil {
+		if err := lm.vfs.CreateDir(ctx, versionFolder); err != nil {
+			return "", err
+		}
+		mdVersion, err = lm.vfs.GetMetadata(ctx, versionFolder)
+		if err != nil {
+			return "", err
+		}
+	}
+	return getFileID(mdVersion), nil
+}
+
+// convertToPublicLink converts a stored link to a public link. Links to files point to the
+// version folder, which is converted back to the current version of the file, so in the UI
+// the link appears on the latest version.
+func (lm *linkManager) convertToPublicLink(ctx context.Context, ln *link) (*api.PublicLink, error) {
+	fileID := ln.FileID
+	itemType := api.PublicLink_FOLDER
+	if !ln.IsDir {
+		itemType = api.PublicLink_FILE
+		newCtx := api.ContextSetUser(ctx, &api.User{AccountId: ln.Owner})
+		md, err := lm.vfs.GetMetadata(newCtx, ln.FileID)
+		if err != nil {
+			l := ctx_zap.Extract(ctx)
+			l.Error("error getting metadata for public link", zap.Error(err))
+			return nil, err
+		}
+
+		md, err = lm.vfs.GetMetadata(newCtx, getFileIDFromVersionFolder(md.Path))
+		if err != nil {
+			return nil, err
+		}
+		fileID = md.Id
+	}
+
+	publicLink := &api.PublicLink{
+		Id:           getKey(ln.ID),
+		Token:        ln.Token,
+		Mtime:        ln.STime,
+		Protected:    ln.Password != "",
+		Path:         fileID,
+		Expires:      ln.Expiration,
+		ReadOnly:     ln.Permissions == permissionsReadOnly,
+		DropOnly:     ln.Permissions == permissionsDropOnly,
+		ItemType:     itemType,
+		OwnerId:      ln.Owner,
+		Name:         ln.Name,
+		MaxDownloads: ln.MaxDownloads,
+		MaxVisitors:  ln.MaxVisitors,
+		Downloads:    ln.Downloads,
+		Uploads:      ln.Uploads,
+		Visitors:     uint64(len(ln.Visitors)),
+		LastAccess:   ln.LastAccess,
+		Version:      getLinkVersion(ln),
+
+		UploadPolicy:  copyUploadPolicy(ln.UploadPolicy),
+		UploadedBytes: ln.UploadedBytes,
+		Labels:        append([]string{}, ln.Labels...),
+	}
+	return publicLink, nil
+}
+
+// copyUploadPolicy returns a copy of the policy so the stored one is never shared.
+func copyUploadPolicy(policy *api.UploadPolicy) *api.UploadPolicy {
+	if policy == nil {
+		return nil
+	}
+	c := *policy
+	c.AllowedTypes = append([]string{}, policy.AllowedTypes...)
+	return &c
+}
+
+// copyLink returns a copy of the link that can be used without holding the lock.
+func copyLink(ln *link) *link {
+	c := *ln
+	c.Visitors = make(map[string]bool, len(ln.Visitors))
+	for k, v := range ln.Visitors {
+		c.Visitors[k] = v
+	}
+	return &c
+}
+
+// getLinkVersion returns a fingerprint of the fields that grant access to the link,
+// so any update of the password, permissions or expiration changes the version.
+func getLinkVersion(ln *link) string {
+	data := fmt.Sprintf("%d|%s|%s|%d|%d", ln.ID, ln.Token, ln.Password, ln.Permissions, ln.Expiration)
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:8])
+}
+
+func getPermissions(readOnly, dropOnly bool) int {
+	if readOnly {
+		return permissionsReadOnly
+	} else if dropOnly {
+		return permissionsDropOnly
+	}
+	return permissionsReadWrite
+}
+
+// getFileID returns the id used to reference the resource, the migration
+// id has precedence over the storage one like in the owncloud link manager.
+func getFileID(md *api.Metadata) string {
+	if md.MigId != "" {
+		return md.MigId
+	}
+	return md.Id
+}
+
+func getKey(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func getUserFromContext(ctx context.Context) (*api.User, error) {
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return nil, api.NewError(api.ContextUserRequiredError)
+	}
+	return u, nil
+}
+
+func getFileIDFromVersionFolder(p string) string {
+	basename := strings.TrimPrefix(gopath.Base(p), versionPrefix)
+	return gopath.Join(gopath.Dir(p), basename)
+}
+
+func getVersionFolder(p string) string {
+	return gopath.Join(gopath.Dir(p), versionPrefix+gopath.Base(p))
+}
+
+func hashPassword(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	return string(bytes), err
+}
+
+func checkPasswordHash(password, hash string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return err == nil
+}
@@ -0,0 +1,226 @@
+package public_link_manager_json
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+type fakeStorage struct {
+	api.VirtualStorage
+}
+
+func (fs *fakeStorage) GetMetadata(ctx context.Context, p string) (*api.Metadata, error) {
+	return &api.Metadata{Id: "home:1", Path: p, IsDir: true}, nil
+}
+
+func newTestManager(t *testing.T) (*linkManager, context.Context, string) {
+	dir, err := ioutil.TempDir("", "links")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "links.json")
+	lm, err := New(file, 0, &fakeStorage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	ctx = api.ContextSetUser(ctx, &api.User{AccountId: "alice"})
+	return lm.(*linkManager), ctx, dir
+}
+
+func TestLinksArePersisted(t *testing.T) {
+	lm, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	pl, err := lm.CreatePublicLink(ctx, "/home/project", &api.PublicLinkOptions{Labels: []string{"review"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := New(lm.file, 0, &fakeStorage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reloaded.InspectPublicLink(ctx, pl.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Token != pl.Token || len(got.Labels) != 1 || got.Labels[0] != "review" {
+		t.Errorf("expected %v, got %v", pl, got)
+	}
+}
+
+func TestFailedSaveKeepsLinks(t *testing.T) {
+	lm, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	pl, err := lm.CreatePublicLink(ctx, "/home/project", &api.PublicLinkOptions{MaxDownloads: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lm.file = filepath.Join(dir, "missing", "links.json")
+	if err := lm.RecordPublicLinkDownload(ctx, pl.Id, "192.0.2.1"); err == nil {
+		t.Fatal("expected the save to fail")
+	}
+	if _, err := lm.CreatePublicLink(ctx, "/home/project", &api.PublicLinkOptions{}); err == nil {
+		t.Fatal("expected the save to fail")
+	}
+	if err := lm.RevokePublicLink(ctx, pl.Id); err == nil {
+		t.Fatal("expected the save to fail")
+	}
+
+	got, err := lm.InspectPublicLink(ctx, pl.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Downloads != 0 || got.Visitors != 0 {
+		t.Errorf("download counted without being saved: %v", got)
+	}
+	if links, _ := lm.ListPublicLinks(ctx, ""); len(links) != 1 {
+		t.Errorf("expected only the saved link, got %v", links)
+	}
+}
+
+func TestRecordPublicLinkDownload(t *testing.T) {
+	lm, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	pl, err := lm.CreatePublicLink(ctx, "/home/project", &api.PublicLinkOptions{MaxVisitors: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lm.RecordPublicLinkDownload(ctx, pl.Id, "192.0.2.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := lm.RecordPublicLinkDownload(ctx, pl.Id, "192.0.2.1"); err != nil {
+		t.Errorf("expected a known visitor to be allowed, got %v", err)
+	}
+	if err := lm.RecordPublicLinkDownload(ctx, pl.Id, "192.0.2.2"); !api.IsErrorCode(err, api.PublicLinkLimitReachedErrorCode) {
+		t.Errorf("expected a new visitor to be rejected, got %v", err)
+	}
+}
+
+func TestLinkVersionChangesOnAccessUpdates(t *testing.T) {
+	lm, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	pl, err := lm.CreatePublicLink(ctx, "/home/project", &api.PublicLinkOptions{Password: "secret", ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		opt     *api.PublicLinkOptions
+		changed bool
+	}{
+		{"name", &api.PublicLinkOptions{UpdateName: true, Name: "renamed"}, false},
+		{"password", &api.PublicLinkOptions{UpdatePassword: true, Password: "other"}, true},
+		{"permissions", &api.PublicLinkOptions{UpdateReadOnly: true, ReadOnly: false}, true},
+		{"expiration", &api.PublicLinkOptions{UpdateExpiration: true, Expiration: 4102444800}, true},
+	}
+	for _, test := range tests {
+		updated, err := lm.UpdatePublicLink(ctx, pl.Id, test.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed := updated.Version != pl.Version; changed != test.changed {
+			t.Errorf("%s: expected the version changed %t", test.name, test.changed)
+		}
+		pl = updated
+	}
+}
+
+func TestCustomTokens(t *testing.T) {
+	lm, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		token string
+		code  api.ErrorCode
+	}{
+		{"project-2018", ""},
+		{"project_2019", ""},
+		{"project-2018", api.PublicLinkTokenAlreadyExistsErrorCode},
+		{"short-tok", api.PublicLinkInvalidTokenErrorCode},
+		{"with/slash-token", api.PublicLinkInvalidTokenErrorCode},
+		{strings.Repeat("a", 65), api.PublicLinkInvalidTokenErrorCode},
+	}
+	for _, test := range tests {
+		pl, err := lm.CreatePublicLink(ctx, "/home/project", &api.PublicLinkOptions{Token: test.token})
+		if test.code == "" {
+			if err != nil || pl.Token != test.token {
+				t.Errorf("%s: expected the custom token, got %v %v", test.token, pl, err)
+			}
+			continue
+		}
+		if !api.IsErrorCode(err, test.code) {
+			t.Errorf("%s: expected %s, got %v", test.token, test.code, err)
+		}
+	}
+
+	pl, err := lm.CreatePublicLink(ctx, "/home/project", &api.PublicLinkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pl.Token) != defaultTokenLength {
+		t.Errorf("expected a generated token of %d characters, got %q", defaultTokenLength, pl.Token)
+	}
+}
+
+func TestNamedLinksWithLabels(t *testing.T) {
+	lm, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	reviewers, err := lm.CreatePublicLink(ctx, "/home/project", &api.PublicLinkOptions{Name: "reviewers", ReadOnly: true, Labels: []string{" review ", "review", ""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	submissions, err := lm.CreatePublicLink(ctx, "/home/project", &api.PublicLinkOptions{DropOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reviewers.Name != "reviewers" || len(reviewers.Labels) != 1 || reviewers.Labels[0] != "review" {
+		t.Errorf("expected the name and the cleaned labels, got %v", reviewers)
+	}
+	if submissions.Name != "project" {
+		t.Errorf("expected the link named after the resource, got %q", submissions.Name)
+	}
+
+	submissions, err = lm.UpdatePublicLink(ctx, submissions.Id, &api.PublicLinkOptions{UpdateName: true, Name: "submissions", UpdateLabels: true, Labels: []string{"drop", "2018"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submissions.Name != "submissions" || len(submissions.Labels) != 2 {
+		t.Errorf("expected the updated name and labels, got %v", submissions)
+	}
+
+	links, err := lm.ListPublicLinks(ctx, "/home/project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 2 {
+		t.Errorf("expected both links of the resource, got %v", links)
+	}
+
+	invalid := []*api.PublicLinkOptions{
+		{Name: strings.Repeat("a", maxNameLength+1)},
+		{Labels: []string{"a,b"}},
+		{Labels: []string{strings.Repeat("a", api.MaxPublicLinkLabelLength+1)}},
+		{Labels: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}},
+	}
+	for _, opt := range invalid {
+		if _, err := lm.CreatePublicLink(ctx, "/home/project", opt); !api.IsErrorCode(err, api.PublicLinkInvalidNameErrorCode) {
+			t.Errorf("expected %+v to be rejected, got %v", opt, err)
+		}
+	}
+}
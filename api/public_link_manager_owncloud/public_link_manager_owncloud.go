@@ -2,8 +2,11 @@ package public_link_manager_owncloud
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	gopath "path"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -12,31 +15,51 @@ import (
 
 	"database/sql"
 	"github.com/bluele/gcache"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
-	"math/rand"
 )
 
-func init() {
-	// Seed the random source with unix nano time
-	rand.Seed(time.Now().UTC().UnixNano())
-}
-
 //TODO(labkode): add owner_id to other public link queries when consulting db
-const tokenLength = 15
-const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+const defaultTokenLength = 15
 const versionPrefix = ".sys.v#."
 
-func New(dbUsername, dbPassword, dbHost string, dbPort int, dbName string, cacheSize, cacheEviction int, vfs api.VirtualStorage) (api.PublicLinkManager, error) {
+// maxNameLength is the size of the share_name column in the owncloud schema.
+const maxNameLength = 64
+
+// maxTokenAttempts is the number of tokens generated before giving up
+// when the generated ones collide with existing links.
+const maxTokenAttempts = 5
+
+// mysqlDuplicateEntry is the error number returned by MySQL when an insert
+// violates a unique index, like the one on the token column.
+const mysqlDuplicateEntry = 1062
+
+// vanityTokenRegexp defines the tokens that owners can choose for their links.
+// Tokens are part of the link URL, so only URL safe characters are allowed.
+// Short tokens could be guessed, so they need at least 10 characters.
+var vanityTokenRegexp = regexp.MustCompile("^[a-zA-Z0-9_-]{10,64}$")
+
+// remoteShareType is used by the shares to users of remote servers. The remote servers
+// access them using the token, like a public link without password.
+const remoteShareType = 6
+
+// New returns a public link manager backed by the owncloud database.
+// The tokenLength is the length of the generated tokens, if it is not positive
+// the default length is used.
+func New(dbUsername, dbPassword, dbHost string, dbPort int, dbName string, cacheSize, cacheEviction, tokenLength int, vfs api.VirtualStorage) (api.PublicLinkManager, error) {
 	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", dbUsername, dbPassword, dbHost, dbPort, dbName))
 	if err != nil {
 		return nil, err
 	}
 
+	if tokenLength <= 0 {
+		tokenLength = defaultTokenLength
+	}
+
 	cache := gcache.New(cacheSize).LFU().Build()
-	return &linkManager{db: db, vfs: vfs, cache: cache, cacheEviction: time.Second * time.Duration(cacheEviction)}, nil
+	return &linkManager{db: db, vfs: vfs, cache: cache, cacheEviction: time.Second * time.Duration(cacheEviction), tokenLength: tokenLength}, nil
 }
 
 type linkManager struct {
@@ -45,6 +68,7 @@ type linkManager struct {
 	cache         gcache.Cache
 	cacheSize     int
 	cacheEviction time.Duration
+	tokenLength   int
 }
 
 // getFileIDParts returns the two parts of a fileID.
@@ -54,6 +78,25 @@ func splitFileID(fileID string) (string, string) {
 	return tokens[0], tokens[1]
 }
 
+// getFileIDsQuery returns the condition to find the rows of any of the file ids,
+// the malformed ids match nothing.
+func getFileIDsQuery(fileIDs []string) (string, []interface{}) {
+	conds := []string{}
+	params := []interface{}{}
+	for _, fileID := range fileIDs {
+		if !strings.Contains(fileID, ":") {
+			continue
+		}
+		prefix, itemSource := splitFileID(fileID)
+		conds = append(conds, "(fileid_prefix=? and item_source=?)")
+		params = append(params, prefix, itemSource)
+	}
+	if len(conds) == 0 {
+		return "1=0 ", params
+	}
+	return "(" + strings.Join(conds, " or ") + ") ", params
+}
+
 // joinFileID concatenates the prefix and the inode to form a valid fileID.
 func joinFileID(prefix, inode string) string {
 	return strings.Join([]string{prefix, inode}, ":")
@@ -182,17 +225,22 @@ func (lm *linkManager) CreatePublicLink(ctx context.Context, path string, opt *a
 		permissions = 4
 	}
 
-	token := genToken()
-	_, err = lm.getDBShareByToken(ctx, token)
-	if err == nil { // token already exists, abort
-		panic("the generated token already exists in the database. token: " + token)
+	if len(opt.Name) > maxNameLength {
+		err := api.NewError(api.PublicLinkInvalidNameErrorCode).WithMessage(fmt.Sprintf("names cannot be longer than %d characters", maxNameLength))
+		l.Warn("", zap.Error(err))
+		return nil, err
 	}
 
+	labels, err := api.CleanPublicLinkLabels(opt.Labels)
 	if err != nil {
-		if !api.IsErrorCode(err, api.PublicLinkNotFoundErrorCode) {
-			l.Error("error checking the uniqueness of the generated token", zap.Error(err))
-			return nil, err
-		}
+		l.Warn("", zap.Error(err))
+		return nil, err
+	}
+
+	if opt.Token != "" && !vanityTokenRegexp.MatchString(opt.Token) {
+		err := api.NewError(api.PublicLinkInvalidTokenErrorCode).WithMessage("tokens must have between 10 and 64 letters, digits, dashes or underscores")
+		l.Warn("", zap.Error(err), zap.String("token", opt.Token))
+		return nil, err
 	}
 
 	fileSource, err := strconv.ParseUint(itemSource, 10, 64)
@@ -201,10 +249,14 @@ func (lm *linkManager) CreatePublicLink(ctx context.Context, path string, opt *a
 		return nil, err
 	}
 
-	shareName := gopath.Base(path)
+	// links without name are named after the resource, like owncloud does.
+	shareName := opt.Name
+	if shareName == "" {
+		shareName = gopath.Base(path)
+	}
 
-	stmtString := "insert into oc_share set share_type=?,uid_owner=?,uid_initiator=?,item_type=?,fileid_prefix=?,item_source=?,file_source=?,permissions=?,stime=?,token=?,share_name=?"
-	stmtValues := []interface{}{3, u.AccountId, u.AccountId, itemType, prefix, itemSource, fileSource, permissions, time.Now().Unix(), token, shareName}
+	columns := []string{"share_type", "uid_owner", "uid_initiator", "item_type", "fileid_prefix", "item_source", "file_source", "permissions", "stime", "share_name"}
+	values := []interface{}{3, u.AccountId, u.AccountId, itemType, prefix, itemSource, fileSource, permissions, time.Now().Unix(), shareName}
 
 	if opt.Password != "" {
 		hashedPassword, err := hashPassword(opt.Password)
@@ -212,40 +264,102 @@ func (lm *linkManager) CreatePublicLink(ctx context.Context, path string, opt *a
 			return nil, err
 		}
 		hashedPassword = "1|" + hashedPassword
-		stmtString += ",share_with=?"
-		stmtValues = append(stmtValues, hashedPassword)
+		columns = append(columns, "share_with")
+		values = append(values, hashedPassword)
 	}
 
 	if opt.Expiration != 0 {
 		t := time.Unix(int64(opt.Expiration), 0)
-		stmtString += ",expiration=?"
-		stmtValues = append(stmtValues, t)
+		columns = append(columns, "expiration")
+		values = append(values, t)
 	}
 
-	stmt, err := lm.db.Prepare(stmtString)
+	var lastId int64
+	for attempt := 1; ; attempt++ {
+		token := opt.Token
+		if token == "" {
+			token, err = api.GenToken(lm.tokenLength)
+			if err != nil {
+				l.Error("error generating token", zap.Error(err))
+				return nil, err
+			}
+		}
+
+		lastId, err = lm.insertLink(columns, values, token)
+		if err == nil {
+			break
+		}
+
+		if !api.IsErrorCode(err, api.PublicLinkTokenAlreadyExistsErrorCode) {
+			l.Error("", zap.Error(err))
+			return nil, err
+		}
+
+		// a token chosen by the owner is not replaced by a generated one.
+		if opt.Token != "" {
+			l.Warn("token already in use", zap.String("token", token))
+			return nil, err
+		}
+
+		if attempt == maxTokenAttempts {
+			l.Error("cannot generate a unique token", zap.Int("attempts", attempt))
+			return nil, err
+		}
+		l.Warn("generated token already in use, generating a new one", zap.Int("attempt", attempt))
+	}
+	l.Info("created oc share", zap.Int64("share_id", lastId))
+
+	if opt.MaxDownloads > 0 || opt.MaxVisitors > 0 || opt.UploadPolicy != nil || len(labels) > 0 {
+		limits := map[string]interface{}{"max_downloads": opt.MaxDownloads, "max_visitors": opt.MaxVisitors, "labels": strings.Join(labels, ",")}
+		for k, v := range getUploadPolicyColumns(opt.UploadPolicy) {
+			limits[k] = v
+		}
+		if err := lm.setLinkLimits(lastId, limits); err != nil {
+			l.Error("error setting link limits", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	pb, err := lm.InspectPublicLink(ctx, fmt.Sprintf("%d", lastId))
 	if err != nil {
-		l.Error("", zap.Error(err))
+		l.Error("error inspecting public link", zap.Error(err))
 		return nil, err
 	}
+	return pb, nil
+}
 
-	result, err := stmt.Exec(stmtValues...)
+// insertLink inserts the link with the given token only if the token is not used yet
+// by other link or remote share, doing the check and the insert in the same statement.
+// The unique index on the token column, created by the migration
+// migrations/mysql/0001_oc_share_token_index.up.sql, reports the concurrent
+// inserts as collisions too.
+func (lm *linkManager) insertLink(columns []string, values []interface{}, token string) (int64, error) {
+	placeholders := strings.Repeat("?,", len(columns)) + "?"
+	stmtString := fmt.Sprintf("insert into oc_share (%s,token) select %s from dual where not exists (select 1 from oc_share where token=?)", strings.Join(columns, ","), placeholders)
+	stmtValues := append(append([]interface{}{}, values...), token, token)
+
+	stmt, err := lm.db.Prepare(stmtString)
 	if err != nil {
-		l.Error("", zap.Error(err))
-		return nil, err
+		return 0, err
 	}
-	lastId, err := result.LastInsertId()
+	defer stmt.Close()
+
+	result, err := stmt.Exec(stmtValues...)
 	if err != nil {
-		l.Error("", zap.Error(err))
-		return nil, err
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == mysqlDuplicateEntry {
+			return 0, api.NewError(api.PublicLinkTokenAlreadyExistsErrorCode)
+		}
+		return 0, err
 	}
-	l.Info("created oc share", zap.Int64("share_id", lastId))
 
-	pb, err := lm.InspectPublicLink(ctx, fmt.Sprintf("%d", lastId))
+	rowCnt, err := result.RowsAffected()
 	if err != nil {
-		l.Error("error inspecting public link", zap.Error(err))
-		return nil, err
+		return 0, err
 	}
-	return pb, nil
+	if rowCnt == 0 {
+		return 0, api.NewError(api.PublicLinkTokenAlreadyExistsErrorCode)
+	}
+	return result.LastInsertId()
 }
 
 // TODO(labkode): handle nil opt
@@ -285,6 +399,26 @@ func (lm *linkManager) UpdatePublicLink(ctx context.Context, id string, opt *api
 		stmtPairs["expiration"] = t
 	}
 
+	if opt.UpdateName {
+		if len(opt.Name) > maxNameLength {
+			err := api.NewError(api.PublicLinkInvalidNameErrorCode).WithMessage(fmt.Sprintf("names cannot be longer than %d characters", maxNameLength))
+			l.Warn("", zap.Error(err))
+			return nil, err
+		}
+
+		name := opt.Name
+		if name == "" {
+			// go back to the default name
+			md, err := lm.vfs.GetMetadata(ctx, pb.Path)
+			if err != nil {
+				l.Error("error getting metadata for default link name", zap.Error(err))
+				return nil, err
+			}
+			name = gopath.Base(md.Path)
+		}
+		stmtPairs["share_name"] = name
+	}
+
 	if opt.UpdateReadOnly || opt.UpdateDropOnly {
 		if opt.ReadOnly {
 			stmtPairs["permissions"] = 1
@@ -295,8 +429,44 @@ func (lm *linkManager) UpdatePublicLink(ctx context.Context, id string, opt *api
 		}
 	}
 
-	if len(stmtPairs) == 0 { // nothing to update
-		return pb, nil
+	limits := map[string]interface{}{}
+	if opt.UpdateMaxDownloads {
+		limits["max_downloads"] = opt.MaxDownloads
+	}
+	if opt.UpdateMaxVisitors {
+		limits["max_visitors"] = opt.MaxVisitors
+	}
+	if opt.UpdateUploadPolicy {
+		for k, v := range getUploadPolicyColumns(opt.UploadPolicy) {
+			limits[k] = v
+		}
+	}
+	if opt.UpdateLabels {
+		labels, err := api.CleanPublicLinkLabels(opt.Labels)
+		if err != nil {
+			l.Warn("", zap.Error(err))
+			return nil, err
+		}
+		limits["labels"] = strings.Join(labels, ",")
+	}
+
+	if len(limits) > 0 {
+		intID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			l.Error("cannot parse id to int64", zap.Error(err))
+			return nil, err
+		}
+		if err := lm.setLinkLimits(intID, limits); err != nil {
+			l.Error("error updating link limits", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if len(stmtPairs) == 0 { // nothing else to update
+		if len(limits) == 0 {
+			return pb, nil
+		}
+		return lm.InspectPublicLink(ctx, id)
 	}
 
 	stmtTail := []string{}
@@ -353,14 +523,14 @@ func (lm *linkManager) InspectPublicLink(ctx context.Context, id string) (*api.P
 }
 
 func (lm *linkManager) ListPublicLinks(ctx context.Context, filterByPath string) ([]*api.PublicLink, error) {
-	l := ctx_zap.Extract(ctx)
 	u, err := getUserFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var fileID string
+	var fileIDs []string
 	if filterByPath != "" {
+		var fileID string
 		md, err := lm.vfs.GetMetadata(ctx, filterByPath)
 		if err != nil {
 			return nil, err
@@ -389,16 +559,32 @@ func (lm *linkManager) ListPublicLinks(ctx context.Context, filterByPath string)
 				fileID = md.Id
 			}
 		}
+		fileIDs = []string{fileID}
 	}
 
-	dbShares, err := lm.getDBShares(ctx, u.AccountId, fileID)
+	dbShares, err := lm.getDBShares(ctx, u.AccountId, fileIDs)
 	if err != nil {
 		return nil, err
 	}
+	return lm.convertToPublicLinks(ctx, dbShares)
+}
+
+// convertToPublicLinks converts the links, getting their counters at once.
+func (lm *linkManager) convertToPublicLinks(ctx context.Context, dbShares []*dbShare) ([]*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	ids := []int64{}
+	for _, dbShare := range dbShares {
+		ids = append(ids, int64(dbShare.ID))
+	}
+	stats, err := lm.getLinksStats(ids)
+	if err != nil {
+		l.Error("error getting link counters", zap.Error(err))
+		return nil, err
+	}
 
 	publicLinks := []*api.PublicLink{}
 	for _, dbShare := range dbShares {
-		pb, err := lm.convertToPublicLink(ctx, dbShare)
+		pb, err := lm.convertToPublicLinkWithStats(ctx, dbShare, stats[int64(dbShare.ID)])
 		if err != nil {
 			l.Error("", zap.Error(err))
 			//TODO(labkode): log error and continue
@@ -410,6 +596,131 @@ func (lm *linkManager) ListPublicLinks(ctx context.Context, filterByPath string)
 	return publicLinks, nil
 }
 
+// ListPublicLinksInPath returns the links of the user on the path and on any resource under it.
+// The db only stores the inodes of the shared resources, so every link of the user is resolved
+// to its path and compared with the given one.
+func (lm *linkManager) ListPublicLinksInPath(ctx context.Context, p string) ([]*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	md, err := lm.vfs.GetMetadata(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := lm.ListPublicLinks(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	publicLinks := []*api.PublicLink{}
+	for _, link := range links {
+		linkMd, err := lm.vfs.GetMetadata(ctx, link.Path)
+		if err != nil {
+			l.Warn("error resolving path of public link", zap.String("id", link.Id), zap.String("fileid", link.Path), zap.Error(err))
+			continue
+		}
+		if api.IsInPath(linkMd.Path, md.Path) {
+			publicLinks = append(publicLinks, link)
+		}
+	}
+	return publicLinks, nil
+}
+
+// ListPublicLinksOfFileIDs returns the links of the user on the resources with the given file ids.
+// The links to files point to their version folder, so only the resources are resolved, not every link of the user.
+func (lm *linkManager) ListPublicLinksOfFileIDs(ctx context.Context, fileIDs []string) ([]*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	linkFileIDs := []string{}
+	for _, fileID := range fileIDs {
+		md, err := lm.vfs.GetMetadata(ctx, fileID)
+		if err != nil {
+			l.Warn("error resolving resource of public links", zap.String("fileid", fileID), zap.Error(err))
+			continue
+		}
+		if !md.IsDir {
+			// without version folder the file is not shared by link
+			if md, err = lm.vfs.GetMetadata(ctx, getVersionFolder(md.Path)); err != nil {
+				continue
+			}
+		}
+		if md.MigId != "" {
+			linkFileIDs = append(linkFileIDs, md.MigId)
+		} else {
+			linkFileIDs = append(linkFileIDs, md.Id)
+		}
+	}
+	if len(linkFileIDs) == 0 {
+		return []*api.PublicLink{}, nil
+	}
+
+	dbShares, err := lm.getDBShares(ctx, u.AccountId, linkFileIDs)
+	if err != nil {
+		return nil, err
+	}
+	return lm.convertToPublicLinks(ctx, dbShares)
+}
+
+func (lm *linkManager) TransferPublicLink(ctx context.Context, id, newOwner, newPath string) (*api.PublicLink, error) {
+	l := ctx_zap.Extract(ctx)
+	ownerCtx := api.ContextSetUser(ctx, &api.User{AccountId: newOwner, Groups: []string{}})
+	md, err := lm.vfs.GetMetadata(ownerCtx, newPath)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	var prefix, itemSource string
+	if md.MigId != "" {
+		prefix, itemSource = splitFileID(md.MigId)
+	} else {
+		prefix, itemSource = splitFileID(md.Id)
+	}
+
+	// links to files point to the versions folder, like in CreatePublicLink
+	if !md.IsDir {
+		versionFolderID, err := lm.getVersionFolderID(ownerCtx, md.Path)
+		if err != nil {
+			l.Error("", zap.Error(err))
+			return nil, err
+		}
+		_, itemSource = splitFileID(versionFolderID)
+	}
+
+	fileSource, err := strconv.ParseUint(itemSource, 10, 64)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	stmt, err := lm.db.Prepare("update oc_share set uid_owner=?, uid_initiator=?, fileid_prefix=?, item_source=?, file_source=? where id=? and share_type=3")
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	res, err := stmt.Exec(newOwner, newOwner, prefix, itemSource, fileSource, id)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	rowCnt, err := res.RowsAffected()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	if rowCnt == 0 {
+		return nil, api.NewError(api.PublicLinkNotFoundErrorCode)
+	}
+
+	l.Info("transferred public link", zap.String("id", id), zap.String("new_owner", newOwner), zap.String("new_path", newPath))
+	return lm.InspectPublicLink(ownerCtx, id)
+}
+
 func (lm *linkManager) RevokePublicLink(ctx context.Context, id string) error {
 	l := ctx_zap.Extract(ctx)
 	u, err := getUserFromContext(ctx)
@@ -441,9 +752,258 @@ func (lm *linkManager) RevokePublicLink(ctx context.Context, id string) error {
 		l.Error("", zap.Error(err), zap.String("id", id))
 		return err
 	}
+
+	// the link is already gone, leftover counters are harmless.
+	if err := lm.deleteLinkStats(id); err != nil {
+		l.Warn("error deleting link counters", zap.Error(err), zap.String("id", id))
+	}
+	return nil
+}
+
+// The usage limits and counters of the links are kept in tables owned by CERNBox,
+// as oc_share belongs to the owncloud schema. They are created by the migration
+// migrations/mysql/0003_cbox_public_link_stats.up.sql, the labels of the links
+// are kept with them by migrations/mysql/0004_cbox_public_link_labels.up.sql.
+type linkStats struct {
+	MaxDownloads uint64
+	MaxVisitors  uint64
+	Downloads    uint64
+	Uploads      uint64
+	Visitors     uint64
+	LastAccess   uint64
+
+	MaxFileSize     uint64
+	MaxTotalSize    uint64
+	AllowedTypes    string
+	UploaderFolders bool
+	UploadDigest    bool
+	UploadedBytes   uint64
+
+	Labels string
+}
+
+// labels returns the labels of the link, stored separated by commas.
+func (s *linkStats) labels() []string {
+	if s.Labels == "" {
+		return nil
+	}
+	return strings.Split(s.Labels, ",")
+}
+
+// uploadPolicy returns the upload policy of the link, or nil if it has none.
+func (s *linkStats) uploadPolicy() *api.UploadPolicy {
+	if s.MaxFileSize == 0 && s.MaxTotalSize == 0 && s.AllowedTypes == "" && !s.UploaderFolders && !s.UploadDigest {
+		return nil
+	}
+	policy := &api.UploadPolicy{
+		MaxFileSize:     s.MaxFileSize,
+		MaxTotalSize:    s.MaxTotalSize,
+		UploaderFolders: s.UploaderFolders,
+		Digest:          s.UploadDigest,
+	}
+	if s.AllowedTypes != "" {
+		policy.AllowedTypes = strings.Split(s.AllowedTypes, ",")
+	}
+	return policy
+}
+
+// getUploadPolicyColumns returns the columns storing the policy, a nil policy clears them.
+func getUploadPolicyColumns(policy *api.UploadPolicy) map[string]interface{} {
+	if policy == nil {
+		policy = &api.UploadPolicy{}
+	}
+	types := []string{}
+	for _, t := range policy.AllowedTypes {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return map[string]interface{}{
+		"max_file_size":    policy.MaxFileSize,
+		"max_total_size":   policy.MaxTotalSize,
+		"allowed_types":    strings.Join(types, ","),
+		"uploader_folders": policy.UploaderFolders,
+		"upload_digest":    policy.Digest,
+	}
+}
+
+func (lm *linkManager) getLinkStats(id int64) (*linkStats, error) {
+	stats, err := lm.getLinksStats([]int64{id})
+	if err != nil {
+		return nil, err
+	}
+	return stats[id], nil
+}
+
+// getLinksStats returns the counters and limits of the links with two queries, whatever
+// the number of links. The links without counters, like the ones created before the
+// counters existed, get zero counters and no limits.
+func (lm *linkManager) getLinksStats(ids []int64) (map[int64]*linkStats, error) {
+	stats := map[int64]*linkStats{}
+	if len(ids) == 0 {
+		return stats, nil
+	}
+	args := []interface{}{}
+	for _, id := range ids {
+		stats[id] = &linkStats{}
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	query := fmt.Sprintf("select share_id, max_downloads, max_visitors, downloads, uploads, last_access, max_file_size, max_total_size, allowed_types, uploader_folders, upload_digest, uploaded_bytes, labels from cbox_public_link_stats where share_id in (%s)", placeholders)
+	rows, err := lm.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		s := &linkStats{}
+		if err := rows.Scan(&id, &s.MaxDownloads, &s.MaxVisitors, &s.Downloads, &s.Uploads, &s.LastAccess, &s.MaxFileSize, &s.MaxTotalSize, &s.AllowedTypes, &s.UploaderFolders, &s.UploadDigest, &s.UploadedBytes, &s.Labels); err != nil {
+			return nil, err
+		}
+		stats[id] = s
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf("select share_id, count(*) from cbox_public_link_visitors where share_id in (%s) group by share_id", placeholders)
+	visitorRows, err := lm.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer visitorRows.Close()
+	for visitorRows.Next() {
+		var (
+			id       int64
+			visitors uint64
+		)
+		if err := visitorRows.Scan(&id, &visitors); err != nil {
+			return nil, err
+		}
+		if s, ok := stats[id]; ok {
+			s.Visitors = visitors
+		}
+	}
+	return stats, visitorRows.Err()
+}
+
+// setLinkLimits sets the given limit columns, creating the counters of the link if needed.
+func (lm *linkManager) setLinkLimits(id int64, limits map[string]interface{}) error {
+	columns := []string{"share_id"}
+	values := []interface{}{id}
+	updates := []string{}
+	for k, v := range limits {
+		columns = append(columns, k)
+		values = append(values, v)
+		updates = append(updates, fmt.Sprintf("%s=values(%s)", k, k))
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",")
+	stmtString := fmt.Sprintf("insert into cbox_public_link_stats (%s) values (%s) on duplicate key update %s", strings.Join(columns, ","), placeholders, strings.Join(updates, ","))
+	_, err := lm.db.Exec(stmtString, values...)
+	return err
+}
+
+func (lm *linkManager) deleteLinkStats(id string) error {
+	if _, err := lm.db.Exec("delete from cbox_public_link_stats where share_id=?", id); err != nil {
+		return err
+	}
+	_, err := lm.db.Exec("delete from cbox_public_link_visitors where share_id=?", id)
+	return err
+}
+
+func (lm *linkManager) RecordPublicLinkDownload(ctx context.Context, id, visitor string) error {
+	l := ctx_zap.Extract(ctx)
+	intID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		l.Error("cannot parse id to int64", zap.Error(err))
+		return err
+	}
+
+	if _, err := lm.db.Exec("insert ignore into cbox_public_link_stats (share_id) values (?)", intID); err != nil {
+		l.Error("error creating link counters", zap.Error(err))
+		return err
+	}
+
+	stats, err := lm.getLinkStats(intID)
+	if err != nil {
+		l.Error("error getting link counters", zap.Error(err))
+		return err
+	}
+
+	// visitors already seen are always allowed, concurrent first visits can
+	// exceed the maximum by a few, which is acceptable.
+	if visitor != "" && stats.MaxVisitors > 0 && stats.Visitors >= stats.MaxVisitors {
+		var known int
+		query := "select count(*) from cbox_public_link_visitors where share_id=? and visitor=?"
+		if err := lm.db.QueryRow(query, intID, visitor).Scan(&known); err != nil {
+			l.Error("error getting link visitor", zap.Error(err))
+			return err
+		}
+		if known == 0 {
+			l.Warn("public link reached maximum number of visitors", zap.String("id", id), zap.Uint64("max_visitors", stats.MaxVisitors))
+			return api.NewError(api.PublicLinkLimitReachedErrorCode).WithMessage("maximum number of visitors reached")
+		}
+	}
+
+	// the limit is checked in the update so concurrent downloads cannot exceed it.
+	stmtString := "update cbox_public_link_stats set downloads=downloads+1, last_access=? where share_id=? and (max_downloads=0 or downloads<max_downloads)"
+	res, err := lm.db.Exec(stmtString, time.Now().Unix(), intID)
+	if err != nil {
+		l.Error("error updating link downloads", zap.Error(err))
+		return err
+	}
+	rowCnt, err := res.RowsAffected()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+	if rowCnt == 0 {
+		l.Warn("public link reached maximum number of downloads", zap.String("id", id), zap.Uint64("max_downloads", stats.MaxDownloads))
+		return api.NewError(api.PublicLinkLimitReachedErrorCode).WithMessage("maximum number of downloads reached")
+	}
+
+	if visitor != "" {
+		if _, err := lm.db.Exec("insert ignore into cbox_public_link_visitors (share_id, visitor) values (?, ?)", intID, visitor); err != nil {
+			l.Error("error storing link visitor", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+func (lm *linkManager) RecordPublicLinkUpload(ctx context.Context, id string, size uint64) error {
+	stmtString := "insert into cbox_public_link_stats (share_id, uploads, uploaded_bytes, last_access) values (?, 1, ?, ?) on duplicate key update uploads=uploads+1, uploaded_bytes=uploaded_bytes+values(uploaded_bytes), last_access=values(last_access)"
+	_, err := lm.db.Exec(stmtString, id, size, time.Now().Unix())
+	return err
+}
+
+// ReservePublicLinkUpload checks and adds the bytes in the same statement, so concurrent
+// uploads cannot exceed the maximum together. The links without a row have no maximum.
+func (lm *linkManager) ReservePublicLinkUpload(ctx context.Context, id string, size uint64) error {
+	stmtString := "update cbox_public_link_stats set uploaded_bytes=uploaded_bytes+? where share_id=? and (max_total_size=0 or uploaded_bytes+?<=max_total_size)"
+	res, err := lm.db.Exec(stmtString, size, id, size)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return api.NewError(api.PublicLinkUploadTooLargeErrorCode).WithMessage("the upload exceeds the maximum total size of the link")
+	}
 	return nil
 }
 
+func (lm *linkManager) ReleasePublicLinkUpload(ctx context.Context, id string, size uint64) error {
+	stmtString := "update cbox_public_link_stats set uploaded_bytes=if(uploaded_bytes>?, uploaded_bytes-?, 0) where share_id=?"
+	_, err := lm.db.Exec(stmtString, size, size, id)
+	return err
+}
+
 /*
 type ocShare struct {
 	ID          int64          `db:"id"`
@@ -477,6 +1037,7 @@ type dbShare struct {
 	Permissions int
 	Owner       string
 	ShareName   string
+	ShareType   int
 }
 
 func (lm *linkManager) getDBShareByToken(ctx context.Context, token string) (*dbShare, error) {
@@ -491,16 +1052,17 @@ func (lm *linkManager) getDBShareByToken(ctx context.Context, token string) (*db
 		itemType    string
 		uidOwner    string
 		shareName   string
+		shareType   int
 	)
 
-	query := "select id, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, coalesce(token,'') as token, coalesce(expiration, '') as expiration, stime, permissions, item_type, uid_owner, coalesce(share_name, '') as share_name from oc_share where share_type=? and token=?"
-	if err := lm.db.QueryRow(query, 3, token).Scan(&id, &shareWith, &prefix, &itemSource, &token, &expiration, &stime, &permissions, &itemType, &uidOwner, &shareName); err != nil {
+	query := "select id, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, coalesce(token,'') as token, coalesce(expiration, '') as expiration, stime, permissions, item_type, uid_owner, coalesce(share_name, '') as share_name, share_type from oc_share where (share_type=? or share_type=?) and token=?"
+	if err := lm.db.QueryRow(query, 3, remoteShareType, token).Scan(&id, &shareWith, &prefix, &itemSource, &token, &expiration, &stime, &permissions, &itemType, &uidOwner, &shareName, &shareType); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, api.NewError(api.PublicLinkNotFoundErrorCode)
 		}
 		return nil, err
 	}
-	dbShare := &dbShare{ID: id, Prefix: prefix, ItemSource: itemSource, ShareWith: shareWith, Token: token, Expiration: expiration, STime: stime, Permissions: permissions, ItemType: itemType, Owner: uidOwner, ShareName: shareName}
+	dbShare := &dbShare{ID: id, Prefix: prefix, ItemSource: itemSource, ShareWith: shareWith, Token: token, Expiration: expiration, STime: stime, Permissions: permissions, ItemType: itemType, Owner: uidOwner, ShareName: shareName, ShareType: shareType}
 	return dbShare, nil
 
 }
@@ -537,14 +1099,16 @@ func (lm *linkManager) getDBShare(ctx context.Context, accountID, id string) (*d
 	return dbShare, nil
 
 }
-func (lm *linkManager) getDBShares(ctx context.Context, accountID, fileID string) ([]*dbShare, error) {
+
+// getDBShares returns the links of the user, only the ones on filterByFileIDs if not empty.
+func (lm *linkManager) getDBShares(ctx context.Context, accountID string, filterByFileIDs []string) ([]*dbShare, error) {
 	query := "select id, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, coalesce(token,'') as token, coalesce(expiration, '') as expiration, stime, permissions, item_type, coalesce(share_name, '') as share_name from oc_share where share_type=? and uid_owner=? "
 	params := []interface{}{3, accountID}
 
-	if fileID != "" {
-		prefix, itemSource := splitFileID(fileID)
-		query += "and fileid_prefix=? and item_source=?"
-		params = append(params, prefix, itemSource)
+	if len(filterByFileIDs) > 0 {
+		fileIDQuery, fileIDParams := getFileIDsQuery(filterByFileIDs)
+		query += "and " + fileIDQuery
+		params = append(params, fileIDParams...)
 	}
 
 	rows, err := lm.db.Query(query, params...)
@@ -589,6 +1153,16 @@ func (lm *linkManager) getDBShares(ctx context.Context, accountID, fileID string
 // to convert the version folder back to a file id, hence performing a md operation. This operation is expensive
 // but we can perform aggresive caching as it a file exists the version folder will exist and viceversa.
 func (lm *linkManager) convertToPublicLink(ctx context.Context, dbShare *dbShare) (*api.PublicLink, error) {
+	stats, err := lm.getLinkStats(int64(dbShare.ID))
+	if err != nil {
+		return nil, err
+	}
+	return lm.convertToPublicLinkWithStats(ctx, dbShare, stats)
+}
+
+// convertToPublicLinkWithStats is convertToPublicLink with the counters of the link
+// already fetched, so the links of a listing are converted without a query each.
+func (lm *linkManager) convertToPublicLinkWithStats(ctx context.Context, dbShare *dbShare, stats *linkStats) (*api.PublicLink, error) {
 	var expires uint64
 	if dbShare.Expiration != "" {
 		t, err := time.Parse("2006-01-02 03:04:05", dbShare.Expiration)
@@ -634,17 +1208,28 @@ func (lm *linkManager) convertToPublicLink(ctx context.Context, dbShare *dbShare
 	}
 
 	publicLink := &api.PublicLink{
-		Id:        fmt.Sprintf("%d", dbShare.ID),
-		Token:     dbShare.Token,
-		Mtime:     uint64(dbShare.STime),
-		Protected: dbShare.ShareWith != "",
-		Path:      fileID,
-		Expires:   expires,
-		ReadOnly:  dbShare.Permissions == 1,
-		DropOnly:  dbShare.Permissions == 4,
-		ItemType:  itemType,
-		OwnerId:   dbShare.Owner,
-		Name:      dbShare.ShareName,
+		Id:           fmt.Sprintf("%d", dbShare.ID),
+		Token:        dbShare.Token,
+		Mtime:        uint64(dbShare.STime),
+		Protected:    dbShare.ShareWith != "" && dbShare.ShareType != remoteShareType, // share_with of remote shares is the recipient
+		Path:         fileID,
+		Expires:      expires,
+		ReadOnly:     dbShare.Permissions == 1,
+		DropOnly:     dbShare.Permissions == 4,
+		ItemType:     itemType,
+		OwnerId:      dbShare.Owner,
+		Name:         dbShare.ShareName,
+		MaxDownloads: stats.MaxDownloads,
+		MaxVisitors:  stats.MaxVisitors,
+		Downloads:    stats.Downloads,
+		Uploads:      stats.Uploads,
+		Visitors:     stats.Visitors,
+		LastAccess:   stats.LastAccess,
+		Version:      getLinkVersion(dbShare),
+
+		UploadPolicy:  stats.uploadPolicy(),
+		UploadedBytes: stats.UploadedBytes,
+		Labels:        stats.labels(),
 	}
 
 	return publicLink, nil
@@ -671,6 +1256,15 @@ func (lm *linkManager) getCachedMetadata(ctx context.Context, key string) (*api.
 	return md, nil
 }
 
+// getLinkVersion returns a fingerprint of the fields that grant access to the link,
+// so any update of the password, permissions or expiration changes the version.
+// The password is stored salted, so setting the same password again also changes it.
+func getLinkVersion(dbShare *dbShare) string {
+	data := fmt.Sprintf("%d|%s|%s|%d|%s", dbShare.ID, dbShare.Token, dbShare.ShareWith, dbShare.Permissions, dbShare.Expiration)
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:8])
+}
+
 func getUserFromContext(ctx context.Context) (*api.User, error) {
 	u, ok := api.ContextGetUser(ctx)
 	if !ok {
@@ -693,14 +1287,6 @@ func getVersionFolder(p string) string {
 	return versionFolder
 }
 
-func genToken() string {
-	b := make([]byte, tokenLength)
-	for i := range b {
-		b[i] = letterBytes[rand.Intn(len(letterBytes))]
-	}
-	return string(b)
-}
-
 func hashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
 	return string(bytes), err
@@ -0,0 +1,46 @@
+package public_link_manager_owncloud
+
+import "testing"
+
+func TestGetLinkVersion(t *testing.T) {
+	share := dbShare{ID: 1, Token: "abcdefghij", ShareWith: "1|hash", Permissions: 1, Expiration: "", ShareName: "project"}
+	version := getLinkVersion(&share)
+
+	tests := []struct {
+		name    string
+		update  func(s *dbShare)
+		changed bool
+	}{
+		{"password", func(s *dbShare) { s.ShareWith = "1|other" }, true},
+		{"permissions", func(s *dbShare) { s.Permissions = 15 }, true},
+		{"expiration", func(s *dbShare) { s.Expiration = "2030-01-01 00:00:00" }, true},
+		{"name", func(s *dbShare) { s.ShareName = "renamed" }, false},
+	}
+	for _, test := range tests {
+		updated := share
+		test.update(&updated)
+		if changed := getLinkVersion(&updated) != version; changed != test.changed {
+			t.Errorf("%s: expected the version changed %t", test.name, test.changed)
+		}
+	}
+}
+
+func TestVanityTokens(t *testing.T) {
+	tests := []struct {
+		token string
+		valid bool
+	}{
+		{"project-2018", true},
+		{"Project_2018", true},
+		{"abcdefghij", true},
+		{"abcdefghi", false},
+		{"project 2018", false},
+		{"project/2018", false},
+		{"../project-2018", false},
+	}
+	for _, test := range tests {
+		if valid := vanityTokenRegexp.MatchString(test.token); valid != test.valid {
+			t.Errorf("%q: expected valid %t", test.token, test.valid)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package public_link_validator
+
+import (
+	"context"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// New returns a validator that compares the version of the links in the tokens with
+// the version of the links in the link manager. The current links are cached during
+// cacheEviction seconds, so a revocation or an update takes at most that time to be enforced.
+func New(lm api.PublicLinkManager, cacheSize, cacheEviction int) api.PublicLinkValidator {
+	cache := gcache.New(cacheSize).LRU().Build()
+	return &validator{lm: lm, cache: cache, cacheEviction: time.Second * time.Duration(cacheEviction)}
+}
+
+type validator struct {
+	lm            api.PublicLinkManager
+	cache         gcache.Cache
+	cacheEviction time.Duration
+}
+
+func (v *validator) ValidatePublicLink(ctx context.Context, pl *api.PublicLink) error {
+	l := ctx_zap.Extract(ctx)
+	link, err := v.getLink(ctx, pl.Token)
+	if err != nil {
+		if api.IsErrorCode(err, api.PublicLinkNotFoundErrorCode) {
+			l.Warn("token forged for a link that does not exist anymore", zap.String("token", pl.Token))
+			return api.NewError(api.TokenInvalidErrorCode).WithMessage("link has been revoked")
+		}
+		l.Error("error getting link to validate token", zap.Error(err))
+		return err
+	}
+
+	if link.Version != pl.Version {
+		l.Warn("token forged for an old version of the link", zap.String("token", pl.Token), zap.String("version", pl.Version), zap.String("current_version", link.Version))
+		return api.NewError(api.TokenInvalidErrorCode).WithMessage("link has been modified")
+	}
+
+	if link.Expires != 0 && uint64(time.Now().Unix()) > link.Expires {
+		l.Warn("token forged for an expired link", zap.String("token", pl.Token))
+		return api.NewError(api.TokenInvalidErrorCode).WithMessage("link has expired")
+	}
+	return nil
+}
+
+func (v *validator) getLink(ctx context.Context, token string) (*api.PublicLink, error) {
+	if val, err := v.cache.Get(token); err == nil {
+		if link, ok := val.(*api.PublicLink); ok {
+			return link, nil
+		}
+	}
+
+	link, err := v.lm.InspectPublicLinkByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	v.cache.SetWithExpire(token, link, v.cacheEviction)
+	return link, nil
+}
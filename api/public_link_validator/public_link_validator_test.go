@@ -0,0 +1,78 @@
+package public_link_validator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// fakeLinkManager returns the links by token and counts the lookups.
+type fakeLinkManager struct {
+	api.PublicLinkManager
+	links   map[string]*api.PublicLink
+	lookups int
+}
+
+func (lm *fakeLinkManager) InspectPublicLinkByToken(ctx context.Context, token string) (*api.PublicLink, error) {
+	lm.lookups++
+	pl, ok := lm.links[token]
+	if !ok {
+		return nil, api.NewError(api.PublicLinkNotFoundErrorCode)
+	}
+	return pl, nil
+}
+
+func TestValidatePublicLink(t *testing.T) {
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	past := uint64(time.Now().Add(-time.Hour).Unix())
+	future := uint64(time.Now().Add(time.Hour).Unix())
+	tests := []struct {
+		name  string
+		link  *api.PublicLink
+		token *api.PublicLink
+		valid bool
+	}{
+		{"valid", &api.PublicLink{Token: "abc", Version: "1"}, &api.PublicLink{Token: "abc", Version: "1"}, true},
+		{"not expired", &api.PublicLink{Token: "abc", Version: "1", Expires: future}, &api.PublicLink{Token: "abc", Version: "1"}, true},
+		{"revoked", nil, &api.PublicLink{Token: "abc", Version: "1"}, false},
+		{"updated", &api.PublicLink{Token: "abc", Version: "2"}, &api.PublicLink{Token: "abc", Version: "1"}, false},
+		{"expired", &api.PublicLink{Token: "abc", Version: "1", Expires: past}, &api.PublicLink{Token: "abc", Version: "1"}, false},
+	}
+	for _, test := range tests {
+		lm := &fakeLinkManager{links: map[string]*api.PublicLink{}}
+		if test.link != nil {
+			lm.links[test.link.Token] = test.link
+		}
+		v := New(lm, 10, 60)
+		err := v.ValidatePublicLink(ctx, test.token)
+		if test.valid && err != nil {
+			t.Errorf("%s: expected the token to be valid, got %v", test.name, err)
+		}
+		if !test.valid && !api.IsErrorCode(err, api.TokenInvalidErrorCode) {
+			t.Errorf("%s: expected the token to be invalid, got %v", test.name, err)
+		}
+	}
+}
+
+func TestLinksAreCached(t *testing.T) {
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	lm := &fakeLinkManager{links: map[string]*api.PublicLink{"abc": {Token: "abc", Version: "1"}}}
+	v := New(lm, 10, 60)
+	token := &api.PublicLink{Token: "abc", Version: "1"}
+
+	if err := v.ValidatePublicLink(ctx, token); err != nil {
+		t.Fatal(err)
+	}
+	// the revocation is only seen after the cache eviction
+	delete(lm.links, "abc")
+	if err := v.ValidatePublicLink(ctx, token); err != nil {
+		t.Errorf("expected the cached link to be used, got %v", err)
+	}
+	if lm.lookups != 1 {
+		t.Errorf("expected one lookup in the link manager, got %d", lm.lookups)
+	}
+}
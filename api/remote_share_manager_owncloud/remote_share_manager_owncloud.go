@@ -0,0 +1,312 @@
+package remote_share_manager_owncloud
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"path"
+
+	"github.com/cernbox/revaold/api"
+
+	"database/sql"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// New returns a RemoteShareManager that stores the shares received from
+// remote servers in the oc_share_external table of ownCloud.
+func New(dbUsername, dbPassword, dbHost string, dbPort int, dbName string) (api.RemoteShareManager, error) {
+	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", dbUsername, dbPassword, dbHost, dbPort, dbName))
+	if err != nil {
+		return nil, err
+	}
+
+	return &remoteShareManager{db: db}, nil
+}
+
+type remoteShareManager struct {
+	db *sql.DB
+}
+
+/*
+type ocShareExternal struct {
+	ID             int64  `db:"id"`
+	Remote         string `db:"remote"`
+	RemoteID       string `db:"remote_id"`
+	ShareToken     string `db:"share_token"`
+	Password       string `db:"password"`
+	Name           string `db:"name"`
+	Owner          string `db:"owner"`
+	User           string `db:"user"`
+	Mountpoint     string `db:"mountpoint"`
+	MountpointHash string `db:"mountpoint_hash"`
+	Accepted       int    `db:"accepted"`
+}
+*/
+
+type dbRemoteShare struct {
+	ID         int
+	Remote     string
+	RemoteID   string
+	ShareToken string
+	Name       string
+	Owner      string
+	User       string
+	Accepted   int
+}
+
+func (rm *remoteShareManager) AddRemoteShare(ctx context.Context, share *api.RemoteShare) (*api.RemoteShare, error) {
+	l := ctx_zap.Extract(ctx)
+	if share.Recipient == "" || share.Remote == "" || share.Token == "" {
+		return nil, errors.New("remote share is missing the recipient, the remote or the token")
+	}
+
+	mountpoint := path.Join("/", path.Base(path.Join("/", share.Name)))
+	hash := md5.Sum([]byte(mountpoint))
+
+	stmtString := "insert into oc_share_external set remote=?,remote_id=?,share_token=?,password=?,name=?,owner=?,user=?,mountpoint=?,mountpoint_hash=?,accepted=?"
+	stmtValues := []interface{}{share.Remote, share.RemoteId, share.Token, "", share.Name, share.Owner, share.Recipient, mountpoint, hex.EncodeToString(hash[:]), 0}
+
+	stmt, err := rm.db.Prepare(stmtString)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	result, err := stmt.Exec(stmtValues...)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	l.Info("created remote share", zap.Int64("id", lastID), zap.String("remote", share.Remote), zap.String("user", share.Recipient))
+
+	dbShare, err := rm.getDBRemoteShare(ctx, share.Recipient, fmt.Sprintf("%d", lastID))
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	return convertToRemoteShare(dbShare), nil
+}
+
+func (rm *remoteShareManager) RemoveRemoteShare(ctx context.Context, remote, remoteID, token string) error {
+	l := ctx_zap.Extract(ctx)
+	// the notifications of the OCM protocol do not carry the remote server,
+	// in that case the share is identified by its remote id and its secret token.
+	stmtString := "delete from oc_share_external where remote_id=? and share_token=?"
+	stmtValues := []interface{}{remoteID, token}
+	if remote != "" {
+		stmtString += " and remote=?"
+		stmtValues = append(stmtValues, remote)
+	}
+
+	stmt, err := rm.db.Prepare(stmtString)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	res, err := stmt.Exec(stmtValues...)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	rowCnt, err := res.RowsAffected()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	if rowCnt == 0 {
+		err := api.NewError(api.RemoteShareNotFoundErrorCode)
+		l.Error("", zap.Error(err), zap.String("remote", remote), zap.String("remote_id", remoteID))
+		return err
+	}
+	l.Info("removed remote share", zap.String("remote", remote), zap.String("remote_id", remoteID))
+	return nil
+}
+
+func (rm *remoteShareManager) ListRemoteShares(ctx context.Context) ([]*api.RemoteShare, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dbShares, err := rm.getDBRemoteShares(ctx, u.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := []*api.RemoteShare{}
+	for _, dbShare := range dbShares {
+		shares = append(shares, convertToRemoteShare(dbShare))
+	}
+	return shares, nil
+}
+
+func (rm *remoteShareManager) GetRemoteShare(ctx context.Context, id string) (*api.RemoteShare, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	dbShare, err := rm.getDBRemoteShare(ctx, u.AccountId, id)
+	if err != nil {
+		l.Error("cannot get db remote share", zap.Error(err), zap.String("id", id), zap.String("user", u.AccountId))
+		return nil, err
+	}
+	return convertToRemoteShare(dbShare), nil
+}
+
+func (rm *remoteShareManager) AcceptRemoteShare(ctx context.Context, id string) error {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	if _, err := rm.getDBRemoteShare(ctx, u.AccountId, id); err != nil {
+		return err
+	}
+
+	stmt, err := rm.db.Prepare("update oc_share_external set accepted=1 where user=? and id=?")
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	if _, err := stmt.Exec(u.AccountId, id); err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+	l.Info("accepted remote share", zap.String("id", id))
+	return nil
+}
+
+func (rm *remoteShareManager) RejectRemoteShare(ctx context.Context, id string) error {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	stmt, err := rm.db.Prepare("delete from oc_share_external where user=? and id=?")
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	res, err := stmt.Exec(u.AccountId, id)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	rowCnt, err := res.RowsAffected()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
+	if rowCnt == 0 {
+		return api.NewError(api.RemoteShareNotFoundErrorCode)
+	}
+	l.Info("rejected remote share", zap.String("id", id))
+	return nil
+}
+
+func (rm *remoteShareManager) getDBRemoteShare(ctx context.Context, user, id string) (*dbRemoteShare, error) {
+	var (
+		intID      int
+		remote     string
+		remoteID   string
+		shareToken string
+		name       string
+		owner      string
+		accepted   int
+	)
+
+	query := "select id, remote, remote_id, share_token, name, owner, accepted from oc_share_external where user=? and id=?"
+	if err := rm.db.QueryRow(query, user, id).Scan(&intID, &remote, &remoteID, &shareToken, &name, &owner, &accepted); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.NewError(api.RemoteShareNotFoundErrorCode)
+		}
+		return nil, err
+	}
+
+	dbShare := &dbRemoteShare{ID: intID, Remote: remote, RemoteID: remoteID, ShareToken: shareToken, Name: name, Owner: owner, User: user, Accepted: accepted}
+	return dbShare, nil
+}
+
+func (rm *remoteShareManager) getDBRemoteShares(ctx context.Context, user string) ([]*dbRemoteShare, error) {
+	query := "select id, remote, remote_id, share_token, name, owner, accepted from oc_share_external where user=?"
+	rows, err := rm.db.Query(query, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		id         int
+		remote     string
+		remoteID   string
+		shareToken string
+		name       string
+		owner      string
+		accepted   int
+	)
+
+	dbShares := []*dbRemoteShare{}
+	for rows.Next() {
+		err := rows.Scan(&id, &remote, &remoteID, &shareToken, &name, &owner, &accepted)
+		if err != nil {
+			return nil, err
+		}
+		dbShare := &dbRemoteShare{ID: id, Remote: remote, RemoteID: remoteID, ShareToken: shareToken, Name: name, Owner: owner, User: user, Accepted: accepted}
+		dbShares = append(dbShares, dbShare)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return dbShares, nil
+}
+
+func convertToRemoteShare(dbShare *dbRemoteShare) *api.RemoteShare {
+	state := api.RemoteShare_PENDING
+	if dbShare.Accepted == 1 {
+		state = api.RemoteShare_ACCEPTED
+	}
+
+	return &api.RemoteShare{
+		Id:        fmt.Sprintf("%d", dbShare.ID),
+		Remote:    dbShare.Remote,
+		RemoteId:  dbShare.RemoteID,
+		Token:     dbShare.ShareToken,
+		Name:      dbShare.Name,
+		Owner:     dbShare.Owner,
+		Recipient: dbShare.User,
+		State:     state,
+	}
+}
+
+func getUserFromContext(ctx context.Context) (*api.User, error) {
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return nil, api.NewError(api.ContextUserRequiredError)
+	}
+	return u, nil
+}
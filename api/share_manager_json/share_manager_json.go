@@ -0,0 +1,577 @@
+package share_manager_json
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/api/jsonfile"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// maxTargetLength is the maximum length of the name of a received share.
+const maxTargetLength = 255
+
+// New returns a share manager that keeps the shares in a JSON file, meant for
+// development and tests where there is no ownCloud database.
+// The file is read once and rewritten after every change, so it must not be shared
+// between several revad instances. Shares to remote recipients are not supported.
+func New(file string, vfs api.VirtualStorage, um api.UserManager) (api.ShareManager, error) {
+	sm := &shareManager{file: file, vfs: vfs, um: um, db: &shareDB{NextID: 1, Shares: map[string]*share{}}}
+	if err := sm.load(); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+type shareManager struct {
+	mu   sync.Mutex
+	file string
+	vfs  api.VirtualStorage
+	um   api.UserManager
+	db   *shareDB
+}
+
+type shareDB struct {
+	NextID int64             `json:"next_id"`
+	Shares map[string]*share `json:"shares"`
+}
+
+type share struct {
+	ID            int64                            `json:"id"`
+	Owner         string                           `json:"owner"`
+	FileID        string                           `json:"file_id"`
+	ReadOnly      bool                             `json:"read_only"`
+	STime         uint64                           `json:"stime"`
+	RecipientType api.ShareRecipient_RecipientType `json:"recipient_type"`
+	Recipient     string                           `json:"recipient"`
+	// Target is the name of the mount point for the recipients, Targets
+	// are the names chosen by some of them.
+	Target     string            `json:"target"`
+	Targets    map[string]string `json:"targets,omitempty"`
+	RejectedBy map[string]bool   `json:"rejected_by,omitempty"`
+}
+
+func (sm *shareManager) load() error {
+	db := &shareDB{NextID: 1}
+	if err := jsonfile.Load(sm.file, db); err != nil {
+		return err
+	}
+	if db.Shares == nil {
+		db.Shares = map[string]*share{}
+	}
+	sm.db = db
+	return nil
+}
+
+// update applies change to a copy of the shares and saves it, the shares in memory
+// are only replaced once the copy is saved. It must be called with the lock held.
+// The stored shares are never modified, so they can be read after releasing the lock.
+func (sm *shareManager) update(change func(db *shareDB) error) error {
+	db := &shareDB{NextID: sm.db.NextID, Shares: make(map[string]*share, len(sm.db.Shares))}
+	for k, s := range sm.db.Shares {
+		db.Shares[k] = copyShare(s)
+	}
+	if err := change(db); err != nil {
+		return err
+	}
+	if err := jsonfile.Save(sm.file, db); err != nil {
+		return err
+	}
+	sm.db = db
+	return nil
+}
+
+func (sm *shareManager) AddFolderShare(ctx context.Context, p string, recipient *api.ShareRecipient, readOnly bool) (*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := sm.vfs.GetMetadata(ctx, p)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	if !md.IsDir {
+		return nil, api.NewError(api.StorageNotSupportedErrorCode)
+	}
+
+	if recipient.Type == api.ShareRecipient_REMOTE {
+		return nil, api.NewError(api.StorageNotSupportedErrorCode).WithMessage("federated sharing is not supported by the json share manager")
+	}
+
+	var s *share
+	sm.mu.Lock()
+	err = sm.update(func(db *shareDB) error {
+		s = &share{
+			ID:            db.NextID,
+			Owner:         u.AccountId,
+			FileID:        getFileID(md),
+			ReadOnly:      readOnly,
+			STime:         uint64(time.Now().Unix()),
+			RecipientType: recipient.Type,
+			Recipient:     recipient.Identity,
+			Target:        path.Join("/", path.Base(p)),
+		}
+		db.NextID++
+		db.Shares[getKey(s.ID)] = s
+		return nil
+	})
+	sm.mu.Unlock()
+	if err != nil {
+		l.Error("error saving shares", zap.Error(err))
+		return nil, err
+	}
+	l.Info("created share", zap.Int64("share_id", s.ID))
+
+	folderShare := convertToFolderShare(s)
+
+	// guests access the files through the share storage with the identity of the owner
+	if recipient.Type == api.ShareRecipient_GUEST {
+		return folderShare, nil
+	}
+
+	// set acl on the storage
+	if err := sm.vfs.SetACL(ctx, p, readOnly, recipient, []*api.FolderShare{}); err != nil {
+		l.Error("error setting acl on storage, rollbacking operation", zap.Error(err))
+		if err2 := sm.deleteShare(u.AccountId, folderShare.Id); err2 != nil {
+			l.Error("cannot remove non commited share, fix manually", zap.Error(err2), zap.String("share_id", folderShare.Id))
+			return nil, err2
+		}
+		return nil, err
+	}
+
+	l.Info("share commited on storage acl", zap.String("share_id", folderShare.Id))
+	return folderShare, nil
+}
+
+func (sm *shareManager) GetFolderShare(ctx context.Context, id string) (*api.FolderShare, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	s, ok := sm.db.Shares[id]
+	if !ok || s.Owner != u.AccountId {
+		return nil, api.NewError(api.FolderShareNotFoundErrorCode)
+	}
+	return convertToFolderShare(s), nil
+}
+
+func (sm *shareManager) Unshare(ctx context.Context, id string) error {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	share, err := sm.GetFolderShare(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// guests access the files with the identity of the owner, the rest have a grant
+	// that is removed before the share, so a failure keeps the share to retry
+	// instead of leaving a grant that no share refers to.
+	if share.Recipient.Type != api.ShareRecipient_GUEST {
+		if err := sm.vfs.UnsetACL(ctx, share.Path, share.Recipient, []*api.FolderShare{}); err != nil {
+			l.Error("error removing acl on storage", zap.Error(err))
+			return err
+		}
+		l.Info("share removed from storage acl", zap.String("share_id", share.Id))
+	}
+
+	if err := sm.deleteShare(u.AccountId, id); err != nil {
+		l.Error("error deleting share", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (sm *shareManager) deleteShare(owner, id string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.update(func(db *shareDB) error {
+		s, ok := db.Shares[id]
+		if !ok || s.Owner != owner {
+			return api.NewError(api.FolderShareNotFoundErrorCode)
+		}
+		delete(db.Shares, id)
+		return nil
+	})
+}
+
+func (sm *shareManager) UpdateFolderShare(ctx context.Context, id string, updateReadOnly, readOnly bool) (*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := sm.GetFolderShare(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !updateReadOnly { // nothing to update
+		return share, nil
+	}
+
+	md, err := sm.vfs.GetMetadata(ctx, share.Path)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	sm.mu.Lock()
+	err = sm.update(func(db *shareDB) error {
+		s, ok := db.Shares[id]
+		if !ok || s.Owner != u.AccountId {
+			return api.NewError(api.FolderShareNotFoundErrorCode)
+		}
+		s.ReadOnly = readOnly
+		share = convertToFolderShare(s)
+		return nil
+	})
+	sm.mu.Unlock()
+	if err != nil {
+		l.Error("error saving shares", zap.Error(err))
+		return nil, err
+	}
+
+	if share.Recipient.Type == api.ShareRecipient_GUEST {
+		return share, nil
+	}
+
+	//  update acl on the storage
+	if err := sm.vfs.SetACL(ctx, md.Path, share.ReadOnly, share.Recipient, []*api.FolderShare{}); err != nil {
+		l.Error("error setting acl on storage, rollbacking operation", zap.Error(err))
+		if err2 := sm.Unshare(ctx, share.Id); err2 != nil {
+			l.Error("cannot remove non commited share, fix manually", zap.Error(err2), zap.String("share_id", share.Id))
+			return nil, err2
+		}
+		return nil, err
+	}
+
+	l.Info("share commited on storage acl", zap.String("share_id", share.Id))
+	return share, nil
+}
+
+func (sm *shareManager) ListFolderShares(ctx context.Context, filterByPath string) ([]*api.FolderShare, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileID string
+	if filterByPath != "" {
+		md, err := sm.vfs.GetMetadata(ctx, filterByPath)
+		if err != nil {
+			return nil, err
+		}
+		fileID = getFileID(md)
+	}
+
+	return sm.filter(func(s *share) bool {
+		return s.Owner == u.AccountId && (fileID == "" || s.FileID == fileID)
+	}, convertToFolderShare), nil
+}
+
+// ListFolderSharesInPath returns the shares of the user on the path and on any folder under it.
+func (sm *shareManager) ListFolderSharesInPath(ctx context.Context, p string) ([]*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	md, err := sm.vfs.GetMetadata(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := sm.ListFolderShares(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	sharesInPath := []*api.FolderShare{}
+	for _, share := range shares {
+		shareMd, err := sm.vfs.GetMetadata(ctx, share.Path)
+		if err != nil {
+			l.Warn("error resolving path of folder share", zap.String("id", share.Id), zap.String("fileid", share.Path), zap.Error(err))
+			continue
+		}
+		if api.IsInPath(shareMd.Path, md.Path) {
+			sharesInPath = append(sharesInPath, share)
+		}
+	}
+	return sharesInPath, nil
+}
+
+// ListFolderSharesOfFileIDs returns the shares of the user on the folders with the given file ids.
+func (sm *shareManager) ListFolderSharesOfFileIDs(ctx context.Context, fileIDs []string) ([]*api.FolderShare, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, fileID := range fileIDs {
+		ids[fileID] = true
+	}
+	return sm.filter(func(s *share) bool {
+		return s.Owner == u.AccountId && ids[s.FileID]
+	}, convertToFolderShare), nil
+}
+
+func (sm *shareManager) ListReceivedShares(ctx context.Context) ([]*api.FolderShare, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, unixGroups, err := api.GetRecipientGroups(ctx, sm.um, u.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return sm.filter(func(s *share) bool {
+		return isReceivedBy(s, u.AccountId, groups, unixGroups)
+	}, func(s *share) *api.FolderShare {
+		return convertToReceivedFolderShare(s, u.AccountId)
+	}), nil
+}
+
+func (sm *shareManager) GetReceivedFolderShare(ctx context.Context, id string) (*api.FolderShare, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, unixGroups, err := api.GetRecipientGroups(ctx, sm.um, u.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	s, ok := sm.db.Shares[id]
+	if !ok || !isReceivedBy(s, u.AccountId, groups, unixGroups) {
+		return nil, api.NewError(api.FolderShareNotFoundErrorCode)
+	}
+	return convertToReceivedFolderShare(s, u.AccountId), nil
+}
+
+func (sm *shareManager) UnmountReceivedShare(ctx context.Context, id string) error {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := sm.GetReceivedFolderShare(ctx, id); err != nil {
+		return err
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.update(func(db *shareDB) error {
+		s, ok := db.Shares[id]
+		if !ok {
+			return api.NewError(api.FolderShareNotFoundErrorCode)
+		}
+		if s.RejectedBy == nil {
+			s.RejectedBy = map[string]bool{}
+		}
+		s.RejectedBy[u.AccountId] = true
+		return nil
+	})
+}
+
+func (sm *shareManager) RenameReceivedShare(ctx context.Context, id, target string) (*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target = strings.TrimSpace(target)
+	if target == "" || target == "." || target == ".." || strings.Contains(target, "/") || len(target) > maxTargetLength {
+		return nil, api.NewError(api.FolderShareInvalidTargetErrorCode).WithMessage("target must be a file name without slashes")
+	}
+
+	if _, err := sm.GetReceivedFolderShare(ctx, id); err != nil {
+		return nil, err
+	}
+
+	sm.mu.Lock()
+	err = sm.update(func(db *shareDB) error {
+		s, ok := db.Shares[id]
+		if !ok {
+			return api.NewError(api.FolderShareNotFoundErrorCode)
+		}
+		if s.Targets == nil {
+			s.Targets = map[string]string{}
+		}
+		s.Targets[u.AccountId] = path.Join("/", target)
+		return nil
+	})
+	sm.mu.Unlock()
+	if err != nil {
+		l.Error("error saving shares", zap.Error(err))
+		return nil, err
+	}
+
+	l.Info("renamed received share", zap.String("id", id), zap.String("user", u.AccountId), zap.String("target", target))
+	return sm.GetReceivedFolderShare(ctx, id)
+}
+
+func (sm *shareManager) ListAllFolderShares(ctx context.Context) ([]*api.FolderShare, error) {
+	// like the owncloud manager, only the shares backed by storage acls are returned
+	return sm.filter(func(s *share) bool { return s.RecipientType != api.ShareRecipient_GUEST }, convertToFolderShare), nil
+}
+
+func (sm *shareManager) TransferFolderShare(ctx context.Context, id, newOwner, newPath string) (*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	ownerCtx := api.ContextSetUser(ctx, &api.User{AccountId: newOwner, Groups: []string{}})
+	md, err := sm.vfs.GetMetadata(ownerCtx, newPath)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	var share *api.FolderShare
+	sm.mu.Lock()
+	err = sm.update(func(db *shareDB) error {
+		s, ok := db.Shares[id]
+		if !ok {
+			return api.NewError(api.FolderShareNotFoundErrorCode)
+		}
+		s.Owner = newOwner
+		s.FileID = getFileID(md)
+		share = convertToFolderShare(s)
+		return nil
+	})
+	sm.mu.Unlock()
+	if err != nil {
+		l.Error("error saving shares", zap.Error(err))
+		return nil, err
+	}
+
+	l.Info("transferred folder share", zap.String("id", id), zap.String("new_owner", newOwner), zap.String("new_path", newPath))
+	return share, nil
+}
+
+// filter returns the shares accepted by match converted with convert, sorted by id.
+func (sm *shareManager) filter(match func(*share) bool, convert func(*share) *api.FolderShare) []*api.FolderShare {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	matched := []*share{}
+	for _, s := range sm.db.Shares {
+		if match(s) {
+			matched = append(matched, s)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+
+	shares := []*api.FolderShare{}
+	for _, s := range matched {
+		shares = append(shares, convert(s))
+	}
+	return shares
+}
+
+// isReceivedBy returns true if the share is for the user, either directly, as a guest,
+// or through one of its groups or unix groups, and the user has not rejected it.
+func isReceivedBy(s *share, accountID string, groups, unixGroups []string) bool {
+	if s.Owner == accountID || s.RejectedBy[accountID] {
+		return false
+	}
+
+	switch s.RecipientType {
+	case api.ShareRecipient_USER, api.ShareRecipient_GUEST:
+		return s.Recipient == accountID
+	case api.ShareRecipient_GROUP:
+		return isInGroups(s.Recipient, groups)
+	case api.ShareRecipient_UNIX:
+		return isInGroups(s.Recipient, unixGroups)
+	}
+	return false
+}
+
+func isInGroups(group string, groups []string) bool {
+	for _, g := range groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+func copyShare(s *share) *share {
+	c := *s
+	if s.Targets != nil {
+		c.Targets = make(map[string]string, len(s.Targets))
+		for k, v := range s.Targets {
+			c.Targets[k] = v
+		}
+	}
+	if s.RejectedBy != nil {
+		c.RejectedBy = make(map[string]bool, len(s.RejectedBy))
+		for k, v := range s.RejectedBy {
+			c.RejectedBy[k] = v
+		}
+	}
+	return &c
+}
+
+func convertToFolderShare(s *share) *api.FolderShare {
+	return &api.FolderShare{
+		OwnerId:  s.Owner,
+		Id:       getKey(s.ID),
+		Mtime:    s.STime,
+		Path:     s.FileID,
+		ReadOnly: s.ReadOnly,
+		Recipient: &api.ShareRecipient{
+			Identity: s.Recipient,
+			Type:     s.RecipientType,
+		},
+	}
+}
+
+func convertToReceivedFolderShare(s *share, accountID string) *api.FolderShare {
+	share := convertToFolderShare(s)
+	share.Target = s.Target
+	if target, ok := s.Targets[accountID]; ok {
+		share.Target = target
+	}
+	return share
+}
+
+// getFileID returns the id used to reference the resource, the migration
+// id has precedence over the storage one like in the owncloud share manager.
+func getFileID(md *api.Metadata) string {
+	if md.MigId != "" {
+		return md.MigId
+	}
+	return md.Id
+}
+
+func getKey(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func getUserFromContext(ctx context.Context) (*api.User, error) {
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return nil, api.NewError(api.ContextUserRequiredError)
+	}
+	return u, nil
+}
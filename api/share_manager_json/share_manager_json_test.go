@@ -0,0 +1,222 @@
+package share_manager_json
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// fakeStorage records the acls of the recipients, failUnset makes UnsetACL fail.
+type fakeStorage struct {
+	api.VirtualStorage
+	acls      map[string]bool
+	failUnset bool
+}
+
+func (fs *fakeStorage) GetMetadata(ctx context.Context, p string) (*api.Metadata, error) {
+	return &api.Metadata{Id: "home:1", Path: "/home/project", IsDir: true}, nil
+}
+
+func (fs *fakeStorage) SetACL(ctx context.Context, p string, readOnly bool, recipient *api.ShareRecipient, shareList []*api.FolderShare) error {
+	fs.acls[recipient.Identity] = true
+	return nil
+}
+
+func (fs *fakeStorage) UnsetACL(ctx context.Context, p string, recipient *api.ShareRecipient, shareList []*api.FolderShare) error {
+	if fs.failUnset {
+		return errors.New("storage unavailable")
+	}
+	delete(fs.acls, recipient.Identity)
+	return nil
+}
+
+func newTestManager(t *testing.T) (*shareManager, *fakeStorage, context.Context, string) {
+	dir, err := ioutil.TempDir("", "shares")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := &fakeStorage{acls: map[string]bool{}}
+	sm, err := New(filepath.Join(dir, "shares.json"), fs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	ctx = api.ContextSetUser(ctx, &api.User{AccountId: "alice"})
+	return sm.(*shareManager), fs, ctx, dir
+}
+
+var bob = &api.ShareRecipient{Type: api.ShareRecipient_USER, Identity: "bob"}
+
+func TestSharesArePersisted(t *testing.T) {
+	sm, fs, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	share, err := sm.AddFolderShare(ctx, "/home/project", bob, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fs.acls["bob"] {
+		t.Error("acl not set")
+	}
+
+	reloaded, err := New(sm.file, fs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reloaded.GetFolderShare(ctx, share.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Path != "home:1" || !got.ReadOnly || got.Recipient.Identity != "bob" {
+		t.Errorf("expected %v, got %v", share, got)
+	}
+}
+
+func TestFailedSaveKeepsShares(t *testing.T) {
+	sm, fs, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	share, err := sm.AddFolderShare(ctx, "/home/project", bob, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm.file = filepath.Join(dir, "missing", "shares.json")
+	if _, err := sm.UpdateFolderShare(ctx, share.Id, true, false); err == nil {
+		t.Fatal("expected the save to fail")
+	}
+	if _, err := sm.AddFolderShare(ctx, "/home/project", &api.ShareRecipient{Type: api.ShareRecipient_USER, Identity: "carol"}, true); err == nil {
+		t.Fatal("expected the save to fail")
+	}
+
+	got, err := sm.GetFolderShare(ctx, share.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.ReadOnly {
+		t.Error("share updated without being saved")
+	}
+	if shares, _ := sm.ListFolderShares(ctx, ""); len(shares) != 1 {
+		t.Errorf("expected only the saved share, got %v", shares)
+	}
+	if !fs.acls["bob"] || fs.acls["carol"] {
+		t.Errorf("expected only the acl of the saved share, got %v", fs.acls)
+	}
+}
+
+func TestUnshareRemovesTheACLFirst(t *testing.T) {
+	sm, fs, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	share, err := sm.AddFolderShare(ctx, "/home/project", bob, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs.failUnset = true
+	if err := sm.Unshare(ctx, share.Id); err == nil {
+		t.Fatal("expected the unshare to fail")
+	}
+	if _, err := sm.GetFolderShare(ctx, share.Id); err != nil {
+		t.Errorf("expected the share to be kept to retry, got %v", err)
+	}
+
+	fs.failUnset = false
+	if err := sm.Unshare(ctx, share.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sm.GetFolderShare(ctx, share.Id); !api.IsErrorCode(err, api.FolderShareNotFoundErrorCode) {
+		t.Errorf("expected the share to be removed, got %v", err)
+	}
+	if fs.acls["bob"] {
+		t.Error("acl not removed")
+	}
+}
+
+func TestIsReceivedBy(t *testing.T) {
+	groups := []string{"physicists"}
+	unixGroups := []string{"cern"}
+
+	tests := []struct {
+		name     string
+		share    *share
+		received bool
+	}{
+		{"user", &share{Owner: "alice", RecipientType: api.ShareRecipient_USER, Recipient: "bob"}, true},
+		{"other user", &share{Owner: "alice", RecipientType: api.ShareRecipient_USER, Recipient: "carol"}, false},
+		{"own share", &share{Owner: "bob", RecipientType: api.ShareRecipient_GROUP, Recipient: "physicists"}, false},
+		{"group", &share{Owner: "alice", RecipientType: api.ShareRecipient_GROUP, Recipient: "physicists"}, true},
+		{"unix group", &share{Owner: "alice", RecipientType: api.ShareRecipient_UNIX, Recipient: "cern"}, true},
+		// a unix group and an e-group with the same name are different groups
+		{"e-group as unix group", &share{Owner: "alice", RecipientType: api.ShareRecipient_UNIX, Recipient: "physicists"}, false},
+		{"unix group as e-group", &share{Owner: "alice", RecipientType: api.ShareRecipient_GROUP, Recipient: "cern"}, false},
+		{"rejected", &share{Owner: "alice", RecipientType: api.ShareRecipient_GROUP, Recipient: "physicists", RejectedBy: map[string]bool{"bob": true}}, false},
+	}
+	for _, tt := range tests {
+		if got := isReceivedBy(tt.share, "bob", groups, unixGroups); got != tt.received {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.received, got)
+		}
+	}
+}
+
+func TestListFolderSharesOfFileIDs(t *testing.T) {
+	sm := &shareManager{db: &shareDB{Shares: map[string]*share{
+		"1": {ID: 1, Owner: "alice", FileID: "home:1", RecipientType: api.ShareRecipient_USER, Recipient: "bob"},
+		"2": {ID: 2, Owner: "alice", FileID: "home:2", RecipientType: api.ShareRecipient_GROUP, Recipient: "physicists"},
+		"3": {ID: 3, Owner: "alice", FileID: "home:3", RecipientType: api.ShareRecipient_USER, Recipient: "bob"},
+		"4": {ID: 4, Owner: "carol", FileID: "home:1", RecipientType: api.ShareRecipient_USER, Recipient: "bob"},
+	}}}
+	ctx := api.ContextSetUser(context.Background(), &api.User{AccountId: "alice"})
+
+	shares, err := sm.ListFolderSharesOfFileIDs(ctx, []string{"home:1", "home:2", "home:9"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 2 || shares[0].Id != "1" || shares[1].Id != "2" {
+		t.Errorf("expected the shares 1 and 2 of alice, got %v", shares)
+	}
+}
+
+func TestGuestSharesHaveNoACL(t *testing.T) {
+	sm, fs, ctx, dir := newTestManager(t)
+	defer os.RemoveAll(dir)
+
+	guest := &api.ShareRecipient{Type: api.ShareRecipient_GUEST, Identity: "marie@example.org"}
+	share, err := sm.AddFolderShare(ctx, "/home/project", guest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs.acls) != 0 {
+		t.Errorf("expected no acl for the guest, got %v", fs.acls)
+	}
+
+	// the guest is not in the directory, the user manager is not asked for its groups
+	guestCtx := api.ContextSetUser(ctx, &api.User{AccountId: "marie@example.org", Guest: true})
+	received, err := sm.ListReceivedShares(guestCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0].Id != share.Id {
+		t.Errorf("expected the share received by the guest, got %v", received)
+	}
+
+	all, err := sm.ListAllFolderShares(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 0 {
+		t.Errorf("expected the guest share not to be reconciled, got %v", all)
+	}
+
+	fs.failUnset = true
+	if err := sm.Unshare(ctx, share.Id); err != nil {
+		t.Errorf("expected the guest share to be removed without acl, got %v", err)
+	}
+}
@@ -17,19 +17,45 @@ import (
 	"go.uber.org/zap"
 )
 
-func New(dbUsername, dbPassword, dbHost string, dbPort int, dbName string, vfs api.VirtualStorage, um api.UserManager) (api.ShareManager, error) {
+const (
+	shareTypeUser   = 0
+	shareTypeGroup  = 1
+	shareTypeRemote = 6
+	// shareTypeGuest is the type ownCloud uses for shares by e-mail,
+	// the recipient is the address of a guest.
+	shareTypeGuest = 4
+	// shareTypeUserGroup marks the child rows that ownCloud creates for a member of a group
+	// when it changes its view of a group share, like the name of the mount point.
+	shareTypeUserGroup = 2
+	// shareTypeUnix is not used by ownCloud, it marks shares
+	// with unix groups, that are different from e-groups on the storage acls.
+	shareTypeUnix = 10
+)
+
+const tokenLength = 32
+
+// maxTargetLength is the maximum length of the name of a received share.
+const maxTargetLength = 255
+
+// New returns a share manager backed by the oc_share table of ownCloud.
+// Shares to remote recipients are notified to the remote server with the ocm client
+// and the owners are identified as <username>@<ocmDomain>. If ocm is nil, shares to
+// remote recipients are not allowed.
+func New(dbUsername, dbPassword, dbHost string, dbPort int, dbName string, vfs api.VirtualStorage, um api.UserManager, ocm api.OCMProviderClient, ocmDomain string) (api.ShareManager, error) {
 	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", dbUsername, dbPassword, dbHost, dbPort, dbName))
 	if err != nil {
 		return nil, err
 	}
 
-	return &shareManager{db: db, vfs: vfs, um: um}, nil
+	return &shareManager{db: db, vfs: vfs, um: um, ocm: ocm, ocmDomain: ocmDomain}, nil
 }
 
 type shareManager struct {
-	db  *sql.DB
-	vfs api.VirtualStorage
-	um  api.UserManager
+	db        *sql.DB
+	vfs       api.VirtualStorage
+	um        api.UserManager
+	ocm       api.OCMProviderClient
+	ocmDomain string
 }
 
 func (sm *shareManager) UnmountReceivedShare(ctx context.Context, id string) error {
@@ -48,6 +74,79 @@ func (sm *shareManager) UnmountReceivedShare(ctx context.Context, id string) err
 	return nil
 }
 
+func (sm *shareManager) RenameReceivedShare(ctx context.Context, id, target string) (*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	target = strings.TrimSpace(target)
+	if target == "" || target == "." || target == ".." || strings.Contains(target, "/") || len(target) > maxTargetLength {
+		return nil, api.NewError(api.FolderShareInvalidTargetErrorCode).WithMessage("target must be a file name without slashes")
+	}
+	fileTarget := path.Join("/", target)
+
+	dbShare, err := sm.getDBShareWithMe(ctx, u.AccountId, id)
+	if err != nil {
+		l.Error("cannot get db share", zap.Error(err), zap.String("id", id), zap.String("user", u.AccountId))
+		return nil, err
+	}
+
+	// user and guest shares have a row per recipient, so the target can be changed in place,
+	// group shares need a child row for the recipient, like ownCloud does.
+	if dbShare.ShareType == shareTypeUser || dbShare.ShareType == shareTypeGuest {
+		err = sm.updateDBShareTarget(ctx, u.AccountId, id, dbShare.ShareType, fileTarget)
+	} else {
+		err = sm.setDBShareChildTarget(ctx, u.AccountId, id, fileTarget)
+	}
+	if err != nil {
+		l.Error("error renaming received share", zap.Error(err), zap.String("id", id), zap.String("target", fileTarget))
+		return nil, err
+	}
+	l.Info("renamed received share", zap.String("id", id), zap.String("user", u.AccountId), zap.String("target", fileTarget))
+
+	return sm.GetReceivedFolderShare(ctx, id)
+}
+
+func (sm *shareManager) updateDBShareTarget(ctx context.Context, receiver, id string, shareType int, fileTarget string) error {
+	stmt, err := sm.db.Prepare("update oc_share set file_target=? where id=? and share_type=? and share_with=?")
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(fileTarget, id, shareType, receiver)
+	return err
+}
+
+func (sm *shareManager) setDBShareChildTarget(ctx context.Context, receiver, id, fileTarget string) error {
+	var childID int64
+	query := "select id from oc_share where parent=? and share_type=? and share_with=?"
+	err := sm.db.QueryRow(query, id, shareTypeUserGroup, receiver).Scan(&childID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if err == nil {
+		stmt, err := sm.db.Prepare("update oc_share set file_target=? where id=?")
+		if err != nil {
+			return err
+		}
+		_, err = stmt.Exec(fileTarget, childID)
+		return err
+	}
+
+	// the child row is a copy of the group share that only differs in the recipient and the target
+	stmtString := "insert into oc_share (share_type, uid_owner, uid_initiator, item_type, fileid_prefix, item_source, file_source, permissions, stime, share_with, file_target, parent) " +
+		"select ?, uid_owner, uid_initiator, item_type, fileid_prefix, item_source, file_source, permissions, stime, ?, ?, id from oc_share where id=?"
+	stmt, err := sm.db.Prepare(stmtString)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(shareTypeUserGroup, receiver, fileTarget, id)
+	return err
+}
+
 func (sm *shareManager) rejectShare(ctx context.Context, receiver, id string) error {
 	intID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
@@ -130,7 +229,7 @@ func (sm *shareManager) ListFolderShares(ctx context.Context, filterByPath strin
 		return nil, err
 	}
 
-	var shareID string
+	var fileIDs []string
 	if filterByPath != "" {
 		md, err := sm.vfs.GetMetadata(ctx, filterByPath)
 		if err != nil {
@@ -138,13 +237,13 @@ func (sm *shareManager) ListFolderShares(ctx context.Context, filterByPath strin
 		}
 
 		if md.MigId != "" {
-			shareID = md.MigId
+			fileIDs = []string{md.MigId}
 		} else {
-			shareID = md.Id
+			fileIDs = []string{md.Id}
 		}
 	}
 
-	dbShares, err := sm.getDBShares(ctx, u.AccountId, shareID)
+	dbShares, err := sm.getDBShares(ctx, u.AccountId, fileIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +261,129 @@ func (sm *shareManager) ListFolderShares(ctx context.Context, filterByPath strin
 	return shares, nil
 }
 
+// ListFolderSharesInPath returns the shares of the user on the path and on any folder under it.
+// The db only stores the inodes of the shared folders, so every share of the user is resolved
+// to its path and compared with the given one.
+func (sm *shareManager) ListFolderSharesInPath(ctx context.Context, p string) ([]*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	md, err := sm.vfs.GetMetadata(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := sm.ListFolderShares(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	sharesInPath := []*api.FolderShare{}
+	for _, share := range shares {
+		shareMd, err := sm.vfs.GetMetadata(ctx, share.Path)
+		if err != nil {
+			l.Warn("error resolving path of folder share", zap.String("id", share.Id), zap.String("fileid", share.Path), zap.Error(err))
+			continue
+		}
+		if api.IsInPath(shareMd.Path, md.Path) {
+			sharesInPath = append(sharesInPath, share)
+		}
+	}
+	return sharesInPath, nil
+}
+
+// ListFolderSharesOfFileIDs returns the shares of the user on the folders with the given file ids,
+// in a single query and without resolving the paths of the shares.
+func (sm *shareManager) ListFolderSharesOfFileIDs(ctx context.Context, fileIDs []string) ([]*api.FolderShare, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := []*api.FolderShare{}
+	if len(fileIDs) == 0 {
+		return shares, nil
+	}
+
+	dbShares, err := sm.getDBShares(ctx, u.AccountId, fileIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, dbShare := range dbShares {
+		share, err := sm.convertToFolderShare(ctx, dbShare)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+func (sm *shareManager) TransferFolderShare(ctx context.Context, id, newOwner, newPath string) (*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	ownerCtx := api.ContextSetUser(ctx, &api.User{AccountId: newOwner, Groups: []string{}})
+	md, err := sm.vfs.GetMetadata(ownerCtx, newPath)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	var prefix, itemSource string
+	if md.MigId != "" {
+		prefix, itemSource = splitFileID(md.MigId)
+	} else {
+		prefix, itemSource = splitFileID(md.Id)
+	}
+
+	fileSource, err := strconv.ParseUint(itemSource, 10, 64)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	// the child rows of the members of a group follow the share
+	stmtString := "update oc_share set uid_owner=?, uid_initiator=?, fileid_prefix=?, item_source=?, file_source=? where (id=? and share_type in (?,?,?,?,?)) or (parent=? and share_type=?)"
+	stmt, err := sm.db.Prepare(stmtString)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	res, err := stmt.Exec(newOwner, newOwner, prefix, itemSource, fileSource, id, shareTypeUser, shareTypeGroup, shareTypeUnix, shareTypeRemote, shareTypeGuest, id, shareTypeUserGroup)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	rowCnt, err := res.RowsAffected()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	if rowCnt == 0 {
+		return nil, api.NewError(api.FolderShareNotFoundErrorCode)
+	}
+
+	l.Info("transferred folder share", zap.String("id", id), zap.String("new_owner", newOwner), zap.String("new_path", newPath))
+	return sm.GetFolderShare(ownerCtx, id)
+}
+
+func (sm *shareManager) ListAllFolderShares(ctx context.Context) ([]*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	dbShares, err := sm.getAllDBShares(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shares := []*api.FolderShare{}
+	for _, dbShare := range dbShares {
+		share, err := sm.convertToFolderShare(ctx, dbShare)
+		if err != nil {
+			l.Error("", zap.Error(err))
+			continue
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
 func (sm *shareManager) UpdateFolderShare(ctx context.Context, id string, updateReadOnly, readOnly bool) (*api.FolderShare, error) {
 	l := ctx_zap.Extract(ctx)
 	u, err := getUserFromContext(ctx)
@@ -227,6 +449,12 @@ func (sm *shareManager) UpdateFolderShare(ctx context.Context, id string, update
 		return nil, err
 	}
 
+	// remote recipients access the share with the token and guests with the
+	// identity of the owner, there is no acl to update
+	if share.Recipient.Type == api.ShareRecipient_REMOTE || share.Recipient.Type == api.ShareRecipient_GUEST {
+		return share, nil
+	}
+
 	//  update acl on the storage
 	err = sm.vfs.SetACL(ctx, md.Path, share.ReadOnly, share.Recipient, []*api.FolderShare{})
 	if err != nil {
@@ -256,13 +484,65 @@ func (sm *shareManager) Unshare(ctx context.Context, id string) error {
 		return err
 	}
 
+	// the token is needed to authenticate the unshare notification to the remote server
+	var token string
+	if share.Recipient.Type == api.ShareRecipient_REMOTE {
+		dbShare, err := sm.getDBShare(ctx, u.AccountId, id)
+		if err != nil {
+			l.Error("", zap.Error(err))
+			return err
+		}
+		token = dbShare.Token
+	}
+
+	// remote recipients access the share with the token and guests with the identity
+	// of the owner, the rest have a grant that is removed before the share, so a failure
+	// keeps the share to retry instead of leaving a grant that no share refers to.
+	if share.Recipient.Type != api.ShareRecipient_REMOTE && share.Recipient.Type != api.ShareRecipient_GUEST {
+		err = sm.vfs.UnsetACL(ctx, share.Path, share.Recipient, []*api.FolderShare{})
+		if err != nil {
+			l.Error("error removing acl on storage, share kept", zap.Error(err), zap.String("share_id", share.Id))
+			return err
+		}
+		l.Info("share removed from storage acl", zap.String("share_id", share.Id))
+	}
+
+	if err := sm.deleteDBShare(ctx, u.AccountId, id); err != nil {
+		// the reconciler reports the share as missing its grant
+		l.Error("error deleting share without acl, fix manually", zap.Error(err), zap.String("share_id", share.Id))
+		return err
+	}
+
+	if share.Recipient.Type == api.ShareRecipient_REMOTE {
+		// the share is already gone, a failure to notify the remote server only
+		// leaves a broken mount on its side.
+		if err := sm.notifyRemoteUnshare(ctx, share, token); err != nil {
+			l.Error("error notifying unshare to remote server", zap.Error(err), zap.String("share_id", share.Id))
+		}
+	}
+	return nil
+}
+
+func (sm *shareManager) deleteDBShare(ctx context.Context, owner, id string) error {
+	l := ctx_zap.Extract(ctx)
+	// the targets chosen by the members of a group go away with the share
+	childStmt, err := sm.db.Prepare("delete from oc_share where uid_owner=? and parent=? and share_type=?")
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+	if _, err := childStmt.Exec(owner, id, shareTypeUserGroup); err != nil {
+		l.Error("", zap.Error(err))
+		return err
+	}
+
 	stmt, err := sm.db.Prepare("delete from oc_share where uid_owner=? and id=?")
 	if err != nil {
 		l.Error("", zap.Error(err))
 		return err
 	}
 
-	res, err := stmt.Exec(u.AccountId, id)
+	res, err := stmt.Exec(owner, id)
 	if err != nil {
 		l.Error("", zap.Error(err))
 		return err
@@ -279,16 +559,6 @@ func (sm *shareManager) Unshare(ctx context.Context, id string) error {
 		l.Error("", zap.Error(err), zap.String("id", id))
 		return err
 	}
-
-	// re-set acl on the storage
-	err = sm.vfs.UnsetACL(ctx, share.Path, share.Recipient, []*api.FolderShare{})
-	if err != nil {
-		l.Error("error removing acl on storage, fix manually", zap.Error(err))
-		return err
-	}
-
-	l.Info("share removed from storage acl", zap.String("share_id", share.Id))
-
 	return nil
 }
 
@@ -332,6 +602,10 @@ func (sm *shareManager) AddFolderShare(ctx context.Context, p string, recipient
 		return nil, api.NewError(api.StorageNotSupportedErrorCode)
 	}
 
+	if recipient.Type == api.ShareRecipient_REMOTE {
+		return sm.addRemoteFolderShare(ctx, u, md, recipient, readOnly)
+	}
+
 	itemType := "folder"
 
 	permissions := 15
@@ -353,10 +627,7 @@ func (sm *shareManager) AddFolderShare(ctx context.Context, p string, recipient
 		return nil, err
 	}
 
-	shareType := 0 // user
-	if recipient.Type == api.ShareRecipient_GROUP {
-		shareType = 1
-	}
+	shareType := getShareType(recipient.Type)
 
 	targetPath := path.Join("/", path.Base(p))
 
@@ -387,11 +658,17 @@ func (sm *shareManager) AddFolderShare(ctx context.Context, p string, recipient
 		return nil, err
 	}
 
+	// guests access the files through the share storage with the identity of the owner
+	if recipient.Type == api.ShareRecipient_GUEST {
+		return share, nil
+	}
+
 	// set acl on the storage
 	err = sm.vfs.SetACL(ctx, p, readOnly, recipient, []*api.FolderShare{})
 	if err != nil {
 		l.Error("error setting acl on storage, rollbacking operation", zap.Error(err))
-		err2 := sm.Unshare(ctx, share.Id)
+		// the acl was not set, only the row has to go
+		err2 := sm.deleteDBShare(ctx, u.AccountId, share.Id)
 		if err2 != nil {
 			l.Error("cannot remove non commited share, fix manually", zap.Error(err2), zap.String("share_id", share.Id))
 			return nil, err2
@@ -404,6 +681,132 @@ func (sm *shareManager) AddFolderShare(ctx context.Context, p string, recipient
 	return share, nil
 }
 
+// addRemoteFolderShare stores a share for an user of a remote server and sends it to the remote server.
+// The share has a token that the remote server uses to access the files like a public link,
+// so no acl is set on the storage.
+func (sm *shareManager) addRemoteFolderShare(ctx context.Context, u *api.User, md *api.Metadata, recipient *api.ShareRecipient, readOnly bool) (*api.FolderShare, error) {
+	l := ctx_zap.Extract(ctx)
+	if sm.ocm == nil {
+		return nil, api.NewError(api.StorageNotSupportedErrorCode).WithMessage("federated sharing is not enabled")
+	}
+
+	if _, host := api.SplitFederatedID(recipient.Identity); host == "" {
+		return nil, api.NewError(api.UserNotFoundErrorCode).WithMessage("remote recipient must be user@host: " + recipient.Identity)
+	}
+
+	permissions := 15
+	if readOnly {
+		permissions = 1
+	}
+
+	var prefix string
+	var itemSource string
+	if md.MigId != "" {
+		prefix, itemSource = splitFileID(md.MigId)
+	} else {
+		prefix, itemSource = splitFileID(md.Id)
+	}
+
+	fileSource, err := strconv.ParseUint(itemSource, 10, 64)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	token, err := api.GenToken(tokenLength)
+	if err != nil {
+		l.Error("error generating token for remote share", zap.Error(err))
+		return nil, err
+	}
+
+	targetPath := path.Join("/", path.Base(md.Path))
+
+	stmtString := "insert into oc_share set share_type=?,uid_owner=?,uid_initiator=?,item_type=?,fileid_prefix=?,item_source=?,file_source=?,permissions=?,stime=?,share_with=?,file_target=?,token=?"
+	stmtValues := []interface{}{shareTypeRemote, u.AccountId, u.AccountId, "folder", prefix, itemSource, fileSource, permissions, time.Now().Unix(), recipient.Identity, targetPath, token}
+
+	stmt, err := sm.db.Prepare(stmtString)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	result, err := stmt.Exec(stmtValues...)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	l.Info("created oc remote share", zap.Int64("share_id", lastId))
+
+	share, err := sm.GetFolderShare(ctx, fmt.Sprintf("%d", lastId))
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	err = sm.notifyRemoteShare(ctx, u, share, path.Base(md.Path), token)
+	if err != nil {
+		l.Error("error notifying share to remote server, rollbacking operation", zap.Error(err))
+		err2 := sm.deleteDBShare(ctx, u.AccountId, share.Id)
+		if err2 != nil {
+			l.Error("cannot remove non notified share, fix manually", zap.Error(err2), zap.String("share_id", share.Id))
+			return nil, err2
+		}
+		return nil, err
+	}
+
+	l.Info("share notified to remote server", zap.String("share_id", share.Id), zap.String("recipient", recipient.Identity))
+	return share, nil
+}
+
+func (sm *shareManager) notifyRemoteShare(ctx context.Context, u *api.User, share *api.FolderShare, name, token string) error {
+	_, host := api.SplitFederatedID(share.Recipient.Identity)
+	owner := fmt.Sprintf("%s@%s", u.AccountId, sm.ocmDomain)
+	displayName := u.DisplayName
+	if displayName == "" {
+		displayName = u.AccountId
+	}
+
+	ocmShare := &api.OCMShare{
+		ShareWith:         share.Recipient.Identity,
+		Name:              name,
+		ProviderID:        share.Id,
+		Owner:             owner,
+		Sender:            owner,
+		OwnerDisplayName:  displayName,
+		SenderDisplayName: displayName,
+		ShareType:         "user",
+		ResourceType:      "file",
+		Protocol: &api.OCMProtocol{
+			Name:    "webdav",
+			Options: &api.OCMProtocolOptions{SharedSecret: token},
+		},
+	}
+	return sm.ocm.SendShare(ctx, host, ocmShare)
+}
+
+func (sm *shareManager) notifyRemoteUnshare(ctx context.Context, share *api.FolderShare, token string) error {
+	if sm.ocm == nil {
+		return nil
+	}
+
+	_, host := api.SplitFederatedID(share.Recipient.Identity)
+	notification := &api.OCMNotification{
+		NotificationType: api.OCMNotificationShareUnshared,
+		ResourceType:     "file",
+		ProviderID:       share.Id,
+		Notification: &api.OCMNotificationData{
+			SharedSecret: token,
+			Message:      "the share has been removed by the owner",
+		},
+	}
+	return sm.ocm.SendNotification(ctx, host, notification)
+}
+
 /*
 type ocShare struct {
 	ID          int64          `db:"id"`
@@ -436,6 +839,7 @@ type dbShare struct {
 	STime       int
 	FileTarget  string
 	State       int
+	Token       string
 }
 
 func (sm *shareManager) getDBShareWithMe(ctx context.Context, accountID, id string) (*dbShare, error) {
@@ -458,28 +862,19 @@ func (sm *shareManager) getDBShareWithMe(ctx context.Context, accountID, id stri
 		state       int
 	)
 
-	groups, err := sm.um.GetUserGroups(ctx, accountID)
+	groups, unixGroups, err := api.GetRecipientGroups(ctx, sm.um, accountID)
 	if err != nil {
 		l.Error("", zap.Error(err))
 		return nil, err
 	}
 
-	queryArgs := []interface{}{id, accountID}
-	groupArgs := []interface{}{}
-	for _, v := range groups {
-		groupArgs = append(groupArgs, v)
-	}
-
-	var query string
+	query := "select coalesce(uid_owner, '') as uid_owner, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type, " + fileTargetQuery + ", accepted from oc_share where id=? and (accepted=0 or accepted=1) and "
+	queryArgs := []interface{}{shareTypeUserGroup, accountID, id}
 
-	if len(groups) > 1 {
-		query = "select coalesce(uid_owner, '') as uid_owner, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type, file_target, accepted from oc_share where id=? and (accepted=0 or accepted=1) and (share_with=? or share_with in (?" + strings.Repeat(",?", len(groups)-1) + ")) and id not in (select distinct(id) from oc_share_acl where rejected_by=?)"
-		queryArgs = append(queryArgs, groupArgs...)
-		queryArgs = append(queryArgs, accountID)
-	} else {
-		query = "select coalesce(uid_owner, '') as uid_owner, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type, file_target, accepted from oc_share where id=? and (accepted=0 or accepted=1) and (share_with=?) and id not in (select distinct(id) from oc_share_acl where rejected_by=?)"
-		queryArgs = append(queryArgs, accountID)
-	}
+	recipientQuery, recipientArgs := getRecipientQuery(accountID, groups, unixGroups)
+	query += recipientQuery + " and id not in (select distinct(id) from oc_share_acl where rejected_by=?)"
+	queryArgs = append(queryArgs, recipientArgs...)
+	queryArgs = append(queryArgs, accountID)
 
 	if err := sm.db.QueryRow(query, queryArgs...).Scan(&uidOwner, &shareWith, &prefix, &itemSource, &stime, &permissions, &shareType, &fileTarget, &state); err != nil {
 		if err == sql.ErrNoRows {
@@ -492,29 +887,25 @@ func (sm *shareManager) getDBShareWithMe(ctx context.Context, accountID, id stri
 
 }
 
+// fileTargetQuery selects the target of a received share, preferring the one chosen
+// by the recipient in a child row. It takes the child share type and the recipient as arguments.
+const fileTargetQuery = "coalesce((select c.file_target from oc_share c where c.parent=oc_share.id and c.share_type=? and c.share_with=? limit 1), file_target, '') as file_target"
+
 func (sm *shareManager) getDBSharesWithMe(ctx context.Context, accountID string) ([]*dbShare, error) {
 	l := ctx_zap.Extract(ctx)
-	groups, err := sm.um.GetUserGroups(ctx, accountID)
+	groups, unixGroups, err := api.GetRecipientGroups(ctx, sm.um, accountID)
 	if err != nil {
 		l.Error("", zap.Error(err))
 		return nil, err
 	}
-	queryArgs := []interface{}{0, 1, accountID, accountID}
-	groupArgs := []interface{}{}
-	for _, v := range groups {
-		groupArgs = append(groupArgs, v)
-	}
 
-	var query string
+	query := "select id, coalesce(uid_owner, '') as uid_owner, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type, " + fileTargetQuery + " from oc_share where (accepted=0 or accepted=1) and uid_owner!=? and "
+	queryArgs := []interface{}{shareTypeUserGroup, accountID, accountID}
 
-	if len(groups) > 1 {
-		query = "select id, coalesce(uid_owner, '') as uid_owner, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type, file_target from oc_share where (accepted=0 or accepted=1) and (share_type=? or share_type=?) and uid_owner!=? and (share_with=? or share_with in (?" + strings.Repeat(",?", len(groups)-1) + ")) and id not in (select distinct(id) from oc_share_acl where rejected_by=?)"
-		queryArgs = append(queryArgs, groupArgs...)
-		queryArgs = append(queryArgs, accountID)
-	} else {
-		query = "select id, coalesce(uid_owner, '') as uid_owner, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type, file_target from oc_share where (accepted=0 or accepted=1) and (share_type=? or share_type=?) and uid_owner!=? and (share_with=?) and id not in (select distinct(id) from oc_share_acl where rejected_by=?)"
-		queryArgs = append(queryArgs, accountID)
-	}
+	recipientQuery, recipientArgs := getRecipientQuery(accountID, groups, unixGroups)
+	query += recipientQuery + " and id not in (select distinct(id) from oc_share_acl where rejected_by=?)"
+	queryArgs = append(queryArgs, recipientArgs...)
+	queryArgs = append(queryArgs, accountID)
 	rows, err := sm.db.Query(query, queryArgs...)
 	if err != nil {
 		return nil, err
@@ -567,27 +958,29 @@ func (sm *shareManager) getDBShare(ctx context.Context, accountID, id string) (*
 		shareType   int
 		stime       int
 		permissions int
+		token       string
 	)
 
-	query := "select coalesce(uid_owner, '') as uid_owner, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type from oc_share where uid_owner=? and id=?"
-	if err := sm.db.QueryRow(query, accountID, id).Scan(&uidOwner, &shareWith, &prefix, &itemSource, &stime, &permissions, &shareType); err != nil {
+	query := "select coalesce(uid_owner, '') as uid_owner, coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type, coalesce(token, '') as token from oc_share where uid_owner=? and id=?"
+	if err := sm.db.QueryRow(query, accountID, id).Scan(&uidOwner, &shareWith, &prefix, &itemSource, &stime, &permissions, &shareType, &token); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, api.NewError(api.FolderShareNotFoundErrorCode)
 		}
 		return nil, err
 	}
-	dbShare := &dbShare{ID: int(intID), UIDOwner: uidOwner, Prefix: prefix, ItemSource: itemSource, ShareWith: shareWith, STime: stime, Permissions: permissions, ShareType: shareType}
+	dbShare := &dbShare{ID: int(intID), UIDOwner: uidOwner, Prefix: prefix, ItemSource: itemSource, ShareWith: shareWith, STime: stime, Permissions: permissions, ShareType: shareType, Token: token}
 	return dbShare, nil
 
 }
 
-func (sm *shareManager) getDBShares(ctx context.Context, accountID, filterByFileID string) ([]*dbShare, error) {
-	query := "select id, coalesce(uid_owner, '') as uid_owner,  coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type from oc_share where uid_owner=? and (share_type=? or share_type=?) "
-	params := []interface{}{accountID, 0, 1}
-	if filterByFileID != "" {
-		prefix, itemSource := splitFileID(filterByFileID)
-		query += "and fileid_prefix=? and item_source=?"
-		params = append(params, prefix, itemSource)
+// getDBShares returns the shares of the user, only the ones on filterByFileIDs if not empty.
+func (sm *shareManager) getDBShares(ctx context.Context, accountID string, filterByFileIDs []string) ([]*dbShare, error) {
+	query := "select id, coalesce(uid_owner, '') as uid_owner,  coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type from oc_share where uid_owner=? and (share_type=? or share_type=? or share_type=? or share_type=? or share_type=?) "
+	params := []interface{}{accountID, shareTypeUser, shareTypeGroup, shareTypeUnix, shareTypeRemote, shareTypeGuest}
+	if len(filterByFileIDs) > 0 {
+		fileIDQuery, fileIDParams := getFileIDsQuery(filterByFileIDs)
+		query += "and " + fileIDQuery
+		params = append(params, fileIDParams...)
 	}
 
 	rows, err := sm.db.Query(query, params...)
@@ -625,13 +1018,44 @@ func (sm *shareManager) getDBShares(ctx context.Context, accountID, filterByFile
 	return dbShares, nil
 }
 
-func (sm *shareManager) convertToReceivedFolderShare(ctx context.Context, dbShare *dbShare) (*api.FolderShare, error) {
-	var recipientType api.ShareRecipient_RecipientType
-	if dbShare.ShareType == 0 {
-		recipientType = api.ShareRecipient_USER
-	} else {
-		recipientType = api.ShareRecipient_GROUP
+func (sm *shareManager) getAllDBShares(ctx context.Context) ([]*dbShare, error) {
+	query := "select id, coalesce(uid_owner, '') as uid_owner,  coalesce(share_with, '') as share_with, coalesce(fileid_prefix, '') as fileid_prefix, coalesce(item_source, '') as item_source, stime, permissions, share_type from oc_share where (share_type=? or share_type=? or share_type=?)"
+	rows, err := sm.db.Query(query, shareTypeUser, shareTypeGroup, shareTypeUnix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		id          int
+		uidOwner    string
+		shareWith   string
+		prefix      string
+		itemSource  string
+		shareType   int
+		stime       int
+		permissions int
+	)
+
+	dbShares := []*dbShare{}
+	for rows.Next() {
+		err := rows.Scan(&id, &uidOwner, &shareWith, &prefix, &itemSource, &stime, &permissions, &shareType)
+		if err != nil {
+			return nil, err
+		}
+		dbShare := &dbShare{ID: id, UIDOwner: uidOwner, Prefix: prefix, ItemSource: itemSource, ShareWith: shareWith, STime: stime, Permissions: permissions, ShareType: shareType}
+		dbShares = append(dbShares, dbShare)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
 	}
+
+	return dbShares, nil
+}
+
+func (sm *shareManager) convertToReceivedFolderShare(ctx context.Context, dbShare *dbShare) (*api.FolderShare, error) {
+	recipientType := getRecipientType(dbShare.ShareType)
 	path := joinFileID(dbShare.Prefix, dbShare.ItemSource)
 	share := &api.FolderShare{
 		OwnerId:  dbShare.UIDOwner,
@@ -650,13 +1074,7 @@ func (sm *shareManager) convertToReceivedFolderShare(ctx context.Context, dbShar
 }
 
 func (sm *shareManager) convertToFolderShare(ctx context.Context, dbShare *dbShare) (*api.FolderShare, error) {
-	var recipientType api.ShareRecipient_RecipientType
-	if dbShare.ShareType == 0 {
-		recipientType = api.ShareRecipient_USER
-	} else {
-		recipientType = api.ShareRecipient_GROUP
-	}
-
+	recipientType := getRecipientType(dbShare.ShareType)
 	path := joinFileID(dbShare.Prefix, dbShare.ItemSource)
 	share := &api.FolderShare{
 		OwnerId:  dbShare.UIDOwner,
@@ -673,6 +1091,59 @@ func (sm *shareManager) convertToFolderShare(ctx context.Context, dbShare *dbSha
 
 }
 
+// getRecipientQuery returns the condition to find the shares received by the user,
+// either directly, as a guest, or through one of its groups or unix groups.
+func getRecipientQuery(accountID string, groups, unixGroups []string) (string, []interface{}) {
+	query := "((share_type in (?,?) and share_with=?)"
+	args := []interface{}{shareTypeUser, shareTypeGuest, accountID}
+	if len(groups) > 0 {
+		query += " or (share_type=? and share_with in (?" + strings.Repeat(",?", len(groups)-1) + "))"
+		args = append(args, shareTypeGroup)
+		for _, g := range groups {
+			args = append(args, g)
+		}
+	}
+	if len(unixGroups) > 0 {
+		query += " or (share_type=? and share_with in (?" + strings.Repeat(",?", len(unixGroups)-1) + "))"
+		args = append(args, shareTypeUnix)
+		for _, g := range unixGroups {
+			args = append(args, g)
+		}
+	}
+	query += ")"
+	return query, args
+}
+
+func getShareType(recipientType api.ShareRecipient_RecipientType) int {
+	switch recipientType {
+	case api.ShareRecipient_GROUP:
+		return shareTypeGroup
+	case api.ShareRecipient_UNIX:
+		return shareTypeUnix
+	case api.ShareRecipient_REMOTE:
+		return shareTypeRemote
+	case api.ShareRecipient_GUEST:
+		return shareTypeGuest
+	default:
+		return shareTypeUser
+	}
+}
+
+func getRecipientType(shareType int) api.ShareRecipient_RecipientType {
+	switch shareType {
+	case shareTypeGroup:
+		return api.ShareRecipient_GROUP
+	case shareTypeUnix:
+		return api.ShareRecipient_UNIX
+	case shareTypeRemote:
+		return api.ShareRecipient_REMOTE
+	case shareTypeGuest:
+		return api.ShareRecipient_GUEST
+	default:
+		return api.ShareRecipient_USER
+	}
+}
+
 func getUserFromContext(ctx context.Context) (*api.User, error) {
 	u, ok := api.ContextGetUser(ctx)
 	if !ok {
@@ -688,6 +1159,25 @@ func splitFileID(fileID string) (string, string) {
 	return tokens[0], tokens[1]
 }
 
+// getFileIDsQuery returns the condition to find the rows of any of the file ids,
+// the malformed ids match nothing.
+func getFileIDsQuery(fileIDs []string) (string, []interface{}) {
+	conds := []string{}
+	params := []interface{}{}
+	for _, fileID := range fileIDs {
+		if !strings.Contains(fileID, ":") {
+			continue
+		}
+		prefix, itemSource := splitFileID(fileID)
+		conds = append(conds, "(fileid_prefix=? and item_source=?)")
+		params = append(params, prefix, itemSource)
+	}
+	if len(conds) == 0 {
+		return "1=0 ", params
+	}
+	return "(" + strings.Join(conds, " or ") + ") ", params
+}
+
 // joinFileID concatenates the prefix and the inode to form a valid fileID.
 func joinFileID(prefix, inode string) string {
 	return strings.Join([]string{prefix, inode}, ":")
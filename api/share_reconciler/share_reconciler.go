@@ -0,0 +1,263 @@
+package share_reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cernbox/revaold/api"
+	"go.uber.org/zap"
+)
+
+type Options struct {
+	Logger *zap.Logger
+
+	// IgnoredRecipients are grants that are managed outside
+	// of the share manager, like admin e-groups, and are never reported as orphaned.
+	// The format is <type>:<identity>, like group:cernbox-admins.
+	IgnoredRecipients []string
+}
+
+func (opt *Options) init() {
+	if opt.Logger == nil {
+		l, _ := zap.NewProduction()
+		opt.Logger = l
+	}
+}
+
+// New returns a reconciler that compares the folder shares stored in the share manager
+// with the ACLs found on the storage.
+// Only the paths that are referenced by at least one share are inspected, so grants
+// on folders that lost all their shares cannot be detected. The share managers remove
+// the grant before the share, so those are only left by changes made outside of them.
+func New(opt *Options, sm api.ShareManager, vs api.VirtualStorage) api.ShareReconciler {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	ignored := map[string]bool{}
+	for _, v := range opt.IgnoredRecipients {
+		ignored[v] = true
+	}
+	return &reconciler{sm: sm, vs: vs, logger: opt.Logger, ignored: ignored}
+}
+
+type reconciler struct {
+	// mu serializes runs from the background job and the admin service
+	mu      sync.Mutex
+	sm      api.ShareManager
+	vs      api.VirtualStorage
+	logger  *zap.Logger
+	ignored map[string]bool
+}
+
+// sharedFile contains all the shares pointing to the same file.
+type sharedFile struct {
+	owner  string
+	id     string
+	path   string
+	shares []*api.FolderShare
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, repair bool) ([]*api.ACLDrift, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shares, err := r.sm.ListAllFolderShares(ctx)
+	if err != nil {
+		r.logger.Error("error listing all folder shares", zap.Error(err))
+		return nil, err
+	}
+
+	files := []*sharedFile{}
+	filesByKey := map[string]*sharedFile{}
+	for _, share := range shares {
+		key := share.OwnerId + "/" + share.Path
+		sf, ok := filesByKey[key]
+		if !ok {
+			sf = &sharedFile{owner: share.OwnerId, id: share.Path}
+			filesByKey[key] = sf
+			files = append(files, sf)
+		}
+		sf.shares = append(sf.shares, share)
+	}
+
+	// resolve the ids to paths, as ACLs applied on a folder are inherited
+	// by the shared folders below it.
+	resolved := []*sharedFile{}
+	for _, sf := range files {
+		md, err := r.vs.GetMetadata(r.getOwnerContext(ctx, sf.owner), sf.id)
+		if err != nil {
+			r.logger.Warn("cannot resolve shared file, skipping", zap.Error(err), zap.String("owner", sf.owner), zap.String("id", sf.id))
+			continue
+		}
+		sf.path = md.Path
+		resolved = append(resolved, sf)
+	}
+
+	drifts := []*api.ACLDrift{}
+	for _, sf := range resolved {
+		fileDrifts, err := r.reconcileFile(ctx, sf, r.getAncestors(sf, resolved), r.getDescendants(sf, resolved), repair)
+		if err != nil {
+			r.logger.Warn("cannot reconcile shared file, skipping", zap.Error(err), zap.String("owner", sf.owner), zap.String("path", sf.path))
+			continue
+		}
+		drifts = append(drifts, fileDrifts...)
+	}
+
+	r.logger.Info("share reconciliation finished", zap.Int("shares", len(shares)), zap.Int("files", len(resolved)), zap.Int("drifts", len(drifts)), zap.Bool("repair", repair))
+	return drifts, nil
+}
+
+func (r *reconciler) reconcileFile(ctx context.Context, sf *sharedFile, ancestors, descendants []*sharedFile, repair bool) ([]*api.ACLDrift, error) {
+	ctx = r.getOwnerContext(ctx, sf.owner)
+	entries, err := r.vs.ListACL(ctx, sf.path)
+	if err != nil {
+		return nil, err
+	}
+
+	present := map[string]*api.ACLEntry{}
+	for _, e := range entries {
+		present[getRecipientKey(e.Recipient)] = e
+	}
+
+	inherited := map[string]bool{}
+	for _, a := range ancestors {
+		for _, share := range a.shares {
+			inherited[getRecipientKey(share.Recipient)] = true
+		}
+	}
+
+	drifts := []*api.ACLDrift{}
+	expected := map[string]bool{}
+	for _, share := range sf.shares {
+		key := getRecipientKey(share.Recipient)
+		expected[key] = true
+
+		e, ok := present[key]
+		if !ok {
+			drift := r.newDrift(api.ACLDrift_MISSING, sf, share.Id, share.Recipient, share.ReadOnly)
+			if repair {
+				r.setRepairResult(drift, r.vs.SetACL(ctx, sf.path, share.ReadOnly, share.Recipient, []*api.FolderShare{}))
+			}
+			drifts = append(drifts, drift)
+			continue
+		}
+
+		// the permissions of inherited grants depend on the order the shares were created.
+		if e.ReadOnly != share.ReadOnly && !inherited[key] {
+			drift := r.newDrift(api.ACLDrift_MISMATCH, sf, share.Id, share.Recipient, share.ReadOnly)
+			if repair {
+				r.setRepairResult(drift, r.vs.UpdateACL(ctx, sf.path, share.ReadOnly, share.Recipient, []*api.FolderShare{}))
+			}
+			drifts = append(drifts, drift)
+		}
+	}
+
+	for _, e := range entries {
+		key := getRecipientKey(e.Recipient)
+		if expected[key] || inherited[key] || r.ignored[key] {
+			continue
+		}
+		if e.Recipient.Type == api.ShareRecipient_USER && e.Recipient.Identity == sf.owner {
+			continue
+		}
+
+		drift := r.newDrift(api.ACLDrift_ORPHANED, sf, "", e.Recipient, e.ReadOnly)
+		if repair {
+			r.setRepairResult(drift, r.unsetOrphanedACL(ctx, sf, e.Recipient, descendants))
+		}
+		drifts = append(drifts, drift)
+	}
+
+	return drifts, nil
+}
+
+func (r *reconciler) newDrift(kind api.ACLDrift_Kind, sf *sharedFile, shareID string, recipient *api.ShareRecipient, readOnly bool) *api.ACLDrift {
+	drift := &api.ACLDrift{
+		Kind:      kind,
+		Path:      sf.path,
+		OwnerId:   sf.owner,
+		ShareId:   shareID,
+		Recipient: recipient,
+		ReadOnly:  readOnly,
+	}
+	r.logger.Warn("share acl drift detected", zap.String("kind", kind.String()), zap.String("owner", sf.owner), zap.String("path", sf.path), zap.String("share_id", shareID), zap.String("recipient", getRecipientKey(recipient)))
+	return drift
+}
+
+func (r *reconciler) setRepairResult(drift *api.ACLDrift, err error) {
+	if err != nil {
+		r.logger.Error("cannot repair share acl drift", zap.Error(err), zap.String("path", drift.Path), zap.String("recipient", getRecipientKey(drift.Recipient)))
+		drift.Error = err.Error()
+		return
+	}
+	drift.Repaired = true
+}
+
+// unsetOrphanedACL removes the grant of the recipient from sf. The grants are removed
+// recursively by the storage, so they are set again on the shared files below sf
+// that are shared with the same recipient.
+func (r *reconciler) unsetOrphanedACL(ctx context.Context, sf *sharedFile, recipient *api.ShareRecipient, descendants []*sharedFile) error {
+	if err := r.vs.UnsetACL(ctx, sf.path, recipient, []*api.FolderShare{}); err != nil {
+		return err
+	}
+	key := getRecipientKey(recipient)
+	for _, d := range descendants {
+		for _, share := range d.shares {
+			if getRecipientKey(share.Recipient) != key {
+				continue
+			}
+			if err := r.vs.SetACL(ctx, d.path, share.ReadOnly, share.Recipient, []*api.FolderShare{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getAncestors returns the shared files of the same owner that contain sf.
+func (r *reconciler) getAncestors(sf *sharedFile, files []*sharedFile) []*sharedFile {
+	ancestors := []*sharedFile{}
+	for _, f := range files {
+		if f.owner == sf.owner && f.path != sf.path && strings.HasPrefix(sf.path, strings.TrimSuffix(f.path, "/")+"/") {
+			ancestors = append(ancestors, f)
+		}
+	}
+	return ancestors
+}
+
+// getDescendants returns the shared files of the same owner contained in sf, the
+// shallowest first as the grants set on a folder are inherited by the ones below it.
+func (r *reconciler) getDescendants(sf *sharedFile, files []*sharedFile) []*sharedFile {
+	descendants := []*sharedFile{}
+	for _, f := range files {
+		if f.owner == sf.owner && f.path != sf.path && strings.HasPrefix(f.path, strings.TrimSuffix(sf.path, "/")+"/") {
+			descendants = append(descendants, f)
+		}
+	}
+	sort.SliceStable(descendants, func(i, j int) bool {
+		return strings.Count(descendants[i].path, "/") < strings.Count(descendants[j].path, "/")
+	})
+	return descendants
+}
+
+func (r *reconciler) getOwnerContext(ctx context.Context, owner string) context.Context {
+	return api.ContextSetUser(ctx, &api.User{AccountId: owner, Groups: []string{}})
+}
+
+func getRecipientKey(recipient *api.ShareRecipient) string {
+	var t string
+	switch recipient.Type {
+	case api.ShareRecipient_USER:
+		t = "user"
+	case api.ShareRecipient_GROUP:
+		t = "group"
+	case api.ShareRecipient_UNIX:
+		t = "unix"
+	}
+	return fmt.Sprintf("%s:%s", t, recipient.Identity)
+}
@@ -0,0 +1,210 @@
+package share_reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+)
+
+type fakeShareManager struct {
+	api.ShareManager
+	shares []*api.FolderShare
+}
+
+func (sm *fakeShareManager) ListAllFolderShares(ctx context.Context) ([]*api.FolderShare, error) {
+	return sm.shares, nil
+}
+
+// fakeStorage keeps the acls by path, the ids of the shares are the paths.
+type fakeStorage struct {
+	api.VirtualStorage
+	acls map[string][]*api.ACLEntry
+}
+
+func (vs *fakeStorage) GetMetadata(ctx context.Context, p string) (*api.Metadata, error) {
+	return &api.Metadata{Path: p, IsDir: true}, nil
+}
+
+func (vs *fakeStorage) ListACL(ctx context.Context, p string) ([]*api.ACLEntry, error) {
+	return vs.acls[p], nil
+}
+
+func (vs *fakeStorage) SetACL(ctx context.Context, p string, readOnly bool, recipient *api.ShareRecipient, shares []*api.FolderShare) error {
+	vs.acls[p] = append(vs.acls[p], &api.ACLEntry{Recipient: recipient, ReadOnly: readOnly})
+	return nil
+}
+
+func (vs *fakeStorage) UpdateACL(ctx context.Context, p string, readOnly bool, recipient *api.ShareRecipient, shares []*api.FolderShare) error {
+	for _, e := range vs.acls[p] {
+		if getRecipientKey(e.Recipient) == getRecipientKey(recipient) {
+			e.ReadOnly = readOnly
+		}
+	}
+	return nil
+}
+
+// UnsetACL removes the grant recursively, like eos.
+func (vs *fakeStorage) UnsetACL(ctx context.Context, p string, recipient *api.ShareRecipient, shares []*api.FolderShare) error {
+	for path, acls := range vs.acls {
+		if path != p && !strings.HasPrefix(path, p+"/") {
+			continue
+		}
+		entries := []*api.ACLEntry{}
+		for _, e := range acls {
+			if getRecipientKey(e.Recipient) != getRecipientKey(recipient) {
+				entries = append(entries, e)
+			}
+		}
+		vs.acls[path] = entries
+	}
+	return nil
+}
+
+func user(id string) *api.ShareRecipient {
+	return &api.ShareRecipient{Type: api.ShareRecipient_USER, Identity: id}
+}
+
+func group(id string) *api.ShareRecipient {
+	return &api.ShareRecipient{Type: api.ShareRecipient_GROUP, Identity: id}
+}
+
+func newFixture() (*fakeShareManager, *fakeStorage) {
+	sm := &fakeShareManager{shares: []*api.FolderShare{
+		{Id: "1", OwnerId: "alice", Path: "/alice/a", Recipient: user("bob"), ReadOnly: true},
+		{Id: "2", OwnerId: "alice", Path: "/alice/a", Recipient: user("carol"), ReadOnly: false},
+		{Id: "3", OwnerId: "alice", Path: "/alice/a/b", Recipient: group("physicists"), ReadOnly: true},
+		{Id: "4", OwnerId: "alice", Path: "/alice/a", Recipient: user("dave"), ReadOnly: true},
+	}}
+	vs := &fakeStorage{acls: map[string][]*api.ACLEntry{
+		"/alice/a": {
+			{Recipient: user("alice"), ReadOnly: false},
+			{Recipient: user("bob"), ReadOnly: true},
+			{Recipient: user("carol"), ReadOnly: true},
+			{Recipient: user("eve"), ReadOnly: true},
+			{Recipient: group("cernbox-admins"), ReadOnly: false},
+		},
+		"/alice/a/b": {
+			{Recipient: user("alice"), ReadOnly: false},
+			{Recipient: user("bob"), ReadOnly: false},
+			{Recipient: user("carol"), ReadOnly: true},
+			{Recipient: user("eve"), ReadOnly: true},
+			{Recipient: group("physicists"), ReadOnly: true},
+		},
+	}}
+	return sm, vs
+}
+
+func TestReconcileDetectsDrifts(t *testing.T) {
+	sm, vs := newFixture()
+	r := New(&Options{IgnoredRecipients: []string{"group:cernbox-admins"}}, sm, vs)
+
+	drifts, err := r.Reconcile(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]api.ACLDrift_Kind{}
+	for _, d := range drifts {
+		if d.Repaired {
+			t.Errorf("drift repaired in a dry run: %+v", d)
+		}
+		got[d.Path+" "+getRecipientKey(d.Recipient)] = d.Kind
+	}
+	// the grants of the recipients of /alice/a are inherited by /alice/a/b and not
+	// reported there, the owner and the ignored recipients are not reported either
+	expected := map[string]api.ACLDrift_Kind{
+		"/alice/a user:carol": api.ACLDrift_MISMATCH,
+		"/alice/a user:dave":  api.ACLDrift_MISSING,
+		"/alice/a user:eve":   api.ACLDrift_ORPHANED,
+		"/alice/a/b user:eve": api.ACLDrift_ORPHANED,
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected drifts %v, got %v", expected, got)
+	}
+	for k, kind := range expected {
+		if got[k] != kind {
+			t.Errorf("expected %s for %s, got %v", kind, k, got)
+		}
+	}
+}
+
+func TestReconcileRepairsDrifts(t *testing.T) {
+	sm, vs := newFixture()
+	r := New(&Options{IgnoredRecipients: []string{"group:cernbox-admins"}}, sm, vs)
+
+	drifts, err := r.Reconcile(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range drifts {
+		if !d.Repaired || d.Error != "" {
+			t.Errorf("drift not repaired: %+v", d)
+		}
+	}
+
+	drifts, err = r.Reconcile(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("expected no drifts after the repair, got %v", drifts)
+	}
+}
+
+func TestRepairKeepsTheGrantsOfNestedShares(t *testing.T) {
+	sm := &fakeShareManager{shares: []*api.FolderShare{
+		{Id: "1", OwnerId: "alice", Path: "/alice/a", Recipient: user("bob"), ReadOnly: true},
+		{Id: "2", OwnerId: "alice", Path: "/alice/a/b", Recipient: user("eve"), ReadOnly: false},
+		{Id: "3", OwnerId: "alice", Path: "/alice/a/b/c", Recipient: user("eve"), ReadOnly: true},
+	}}
+	vs := &fakeStorage{acls: map[string][]*api.ACLEntry{
+		"/alice/a": {
+			{Recipient: user("bob"), ReadOnly: true},
+			{Recipient: user("eve"), ReadOnly: true},
+		},
+		"/alice/a/b": {
+			{Recipient: user("bob"), ReadOnly: true},
+			{Recipient: user("eve"), ReadOnly: false},
+		},
+		"/alice/a/b/c": {
+			{Recipient: user("bob"), ReadOnly: true},
+			{Recipient: user("eve"), ReadOnly: true},
+		},
+	}}
+	r := New(nil, sm, vs)
+
+	drifts, err := r.Reconcile(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 1 || drifts[0].Kind != api.ACLDrift_ORPHANED || drifts[0].Path != "/alice/a" || !drifts[0].Repaired {
+		t.Fatalf("expected the orphaned grant of /alice/a to be repaired, got %v", drifts)
+	}
+	if entries := vs.acls["/alice/a"]; len(entries) != 1 || entries[0].Recipient.Identity != "bob" {
+		t.Errorf("expected only bob on /alice/a, got %v", entries)
+	}
+	for p, readOnly := range map[string]bool{"/alice/a/b": false, "/alice/a/b/c": true} {
+		found := false
+		for _, e := range vs.acls[p] {
+			if e.Recipient.Identity == "eve" {
+				found = true
+				if e.ReadOnly != readOnly {
+					t.Errorf("expected the grant of eve on %s to be read only %t", p, readOnly)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("grant of eve on the nested share %s removed", p)
+		}
+	}
+
+	drifts, err = r.Reconcile(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("expected no drifts after the repair, got %v", drifts)
+	}
+}
@@ -251,6 +251,23 @@ func (fs *allProjectsStorage) UpdateACL(ctx context.Context, name string, readOn
 	return fs.vs.UpdateACL(newCtx, targetPath, readOnly, recipient, shareList)
 }
 
+func (fs *allProjectsStorage) ListACL(ctx context.Context, name string) ([]*api.ACLEntry, error) {
+	project, relPath, err := fs.getProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := fs.getProjectMetadata(ctx, project)
+	if err != nil {
+		fs.logger.Error("error getting metadata for project", zap.Error(err))
+		return nil, err
+	}
+
+	newCtx := api.ContextSetUser(ctx, &api.User{AccountId: project.Owner})
+	targetPath := path.Join(md.Path, relPath)
+	return fs.vs.ListACL(newCtx, targetPath)
+}
+
 func (fs *allProjectsStorage) getProjectPath(ctx context.Context, project *api.Project, relPath string) string {
 	return path.Join("/all-projects", project.Name, relPath)
 }
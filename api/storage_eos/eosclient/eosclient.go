@@ -318,6 +318,38 @@ func (c *Client) UpdateACL(ctx context.Context, username, path string, readOnly
 	return c.AddACL(ctx, username, path, readOnly, recipient, shareList)
 }
 
+// ListACL returns the entries present in the sys.acl of the given path.
+// Citrine stores users by uid, they are converted back to usernames.
+func (c *Client) ListACL(ctx context.Context, username, path string) ([]*api.ACLEntry, error) {
+	aclManager, err := c.getACLForPath(ctx, username, path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*api.ACLEntry{}
+	for _, e := range aclManager.aclEntries {
+		recipient := &api.ShareRecipient{Identity: e.recipient}
+		switch e.aclType {
+		case aclTypeUser:
+			recipient.Type = api.ShareRecipient_USER
+			if _, err := strconv.ParseUint(e.recipient, 10, 64); err == nil {
+				if unixUser, err := osuser.LookupId(e.recipient); err == nil {
+					recipient.Identity = unixUser.Username
+				}
+			}
+		case aclTypeGroup:
+			recipient.Type = api.ShareRecipient_GROUP
+		case aclTypeUnixGroup:
+			recipient.Type = api.ShareRecipient_UNIX
+		default:
+			c.opt.Logger.Warn("unknown acl type", zap.String("path", path), zap.String("acl", e.serialize()))
+			continue
+		}
+		entries = append(entries, &api.ACLEntry{Recipient: recipient, ReadOnly: !e.hasWritePermissions()})
+	}
+	return entries, nil
+}
+
 func (c *Client) getACLForPath(ctx context.Context, username, path string) (*aclManager, error) {
 	finfo, err := c.GetFileInfoByPath(ctx, username, path)
 	if err != nil {
@@ -200,6 +200,16 @@ func (fs *eosStorage) UpdateACL(ctx context.Context, path string, readOnly bool,
 	return fs.c.AddACL(ctx, u.AccountId, path, readOnly, recipient, shareList)
 }
 
+func (fs *eosStorage) ListACL(ctx context.Context, path string) ([]*api.ACLEntry, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path = fs.getInternalPath(ctx, path)
+	return fs.c.ListACL(ctx, u.AccountId, path)
+}
+
 func (fs *eosStorage) GetMetadata(ctx context.Context, path string) (*api.Metadata, error) {
 	u, err := getUserFromContext(ctx)
 	if err != nil {
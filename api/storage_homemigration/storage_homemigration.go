@@ -154,6 +154,16 @@ func (fs *eosStorage) UpdateACL(ctx context.Context, path string, readOnly bool,
 	return ts.UpdateACL(ctx, path, readOnly, recipient, shareList)
 }
 
+func (fs *eosStorage) ListACL(ctx context.Context, path string) ([]*api.ACLEntry, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, _, _ := fs.getStorageForUser(ctx, u)
+	return ts.ListACL(ctx, path)
+}
+
 func (fs *eosStorage) GetQuota(ctx context.Context, p string) (int, int, error) {
 	u, err := getUserFromContext(ctx)
 	if err != nil {
@@ -84,6 +84,10 @@ func (fs *localStorage) UpdateACL(ctx context.Context, path string, readOnly boo
 	return api.NewError(api.StorageNotSupportedErrorCode)
 }
 
+func (fs *localStorage) ListACL(ctx context.Context, path string) ([]*api.ACLEntry, error) {
+	return nil, api.NewError(api.StorageNotSupportedErrorCode)
+}
+
 func (fs *localStorage) GetQuota(ctx context.Context, name string) (int, int, error) {
 	// TODO(labkode): add quota check
 	return 0, 0, nil
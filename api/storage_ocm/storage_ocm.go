@@ -0,0 +1,529 @@
+package storage_ocm
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"go.uber.org/zap"
+)
+
+type Options struct {
+	// Timeout in seconds for the metadata requests to remote servers,
+	// transfers are not limited.
+	Timeout            int  `json:"timeout"`
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+}
+
+func (opt *Options) init() {
+	if opt.Timeout == 0 {
+		opt.Timeout = 30
+	}
+}
+
+// New returns a storage that exposes the accepted remote shares of the user.
+// Paths are like /<remote_share_id>/a/b/c and are forwarded using WebDAV to the
+// remote server, authenticating with the share token.
+func New(opt *Options, rsm api.RemoteShareManager, ocm api.OCMProviderClient, logger *zap.Logger) api.Storage {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	tr := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		TLSClientConfig:     &tls.Config{InsecureSkipVerify: opt.InsecureSkipVerify},
+	}
+
+	return &ocmStorage{
+		rsm:            rsm,
+		ocm:            ocm,
+		logger:         logger,
+		client:         &http.Client{Transport: tr, Timeout: time.Duration(opt.Timeout) * time.Second},
+		transferClient: &http.Client{Transport: tr},
+	}
+}
+
+type ocmStorage struct {
+	rsm            api.RemoteShareManager
+	ocm            api.OCMProviderClient
+	logger         *zap.Logger
+	client         *http.Client
+	transferClient *http.Client
+}
+
+// remoteTarget is the location of a path inside a remote share.
+type remoteTarget struct {
+	share *api.RemoteShare
+	// endpoint is the WebDAV url of the root of the share
+	endpoint string
+	// rel is the path relative to the root of the share
+	rel string
+}
+
+func (t *remoteTarget) url(rel string) string {
+	escaped := (&url.URL{Path: path.Join("/", rel)}).EscapedPath()
+	if escaped == "/" {
+		return t.endpoint + "/"
+	}
+	return t.endpoint + escaped
+}
+
+func (fs *ocmStorage) getRemoteTarget(ctx context.Context, name string) (*remoteTarget, error) {
+	// path is /<remote_share_id>/Photos/Test
+	items := strings.Split(strings.TrimPrefix(path.Clean(name), "/"), "/")
+	if len(items) == 0 || items[0] == "" {
+		return nil, api.NewError(api.StorageNotFoundErrorCode)
+	}
+
+	share, err := fs.rsm.GetRemoteShare(ctx, items[0])
+	if err != nil {
+		if api.IsErrorCode(err, api.RemoteShareNotFoundErrorCode) {
+			return nil, api.NewError(api.StorageNotFoundErrorCode)
+		}
+		return nil, err
+	}
+
+	if share.State != api.RemoteShare_ACCEPTED {
+		return nil, api.NewError(api.StorageNotFoundErrorCode)
+	}
+
+	provider, err := fs.ocm.GetProvider(ctx, share.Remote)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := api.GetRemoteURL(share.Remote) + path.Join("/", provider.GetWebDAVPath())
+	return &remoteTarget{share: share, endpoint: endpoint, rel: path.Join("/", path.Join(items[1:]...))}, nil
+}
+
+func (fs *ocmStorage) newRequest(ctx context.Context, t *remoteTarget, method, rel string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, t.url(rel), body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	// the token is sent as the user name, like ownCloud does for federated shares
+	req.SetBasicAuth(t.share.Token, "")
+	return req, nil
+}
+
+func (fs *ocmStorage) do(client *http.Client, req *http.Request, expected ...int) (*http.Response, error) {
+	res, err := client.Do(req)
+	if err != nil {
+		fs.logger.Error("error contacting remote server", zap.String("method", req.Method), zap.String("url", req.URL.String()), zap.Error(err))
+		return nil, err
+	}
+
+	for _, code := range expected {
+		if res.StatusCode == code {
+			return res, nil
+		}
+	}
+
+	defer res.Body.Close()
+	fs.logger.Warn("unexpected answer from remote server", zap.String("method", req.Method), zap.String("url", req.URL.String()), zap.Int("http_code", res.StatusCode))
+	switch res.StatusCode {
+	case http.StatusNotFound:
+		return nil, api.NewError(api.StorageNotFoundErrorCode)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, api.NewError(api.StoragePermissionDeniedErrorCode)
+	case http.StatusMethodNotAllowed, http.StatusPreconditionFailed:
+		return nil, api.NewError(api.StorageAlreadyExistsErrorCode)
+	default:
+		return nil, fmt.Errorf("remote server answered %s %s with http status %d", req.Method, req.URL.String(), res.StatusCode)
+	}
+}
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:" xmlns:oc="http://owncloud.org/ns">
+  <d:prop>
+    <d:getlastmodified/>
+    <d:getcontentlength/>
+    <d:getcontenttype/>
+    <d:getetag/>
+    <d:resourcetype/>
+    <d:quota-used-bytes/>
+    <d:quota-available-bytes/>
+    <oc:permissions/>
+    <oc:size/>
+  </d:prop>
+</d:propfind>`
+
+type multistatusXML struct {
+	Responses []*responseXML `xml:"DAV: response"`
+}
+
+type responseXML struct {
+	Href      string         `xml:"DAV: href"`
+	Propstats []*propstatXML `xml:"DAV: propstat"`
+}
+
+type propstatXML struct {
+	Status string  `xml:"DAV: status"`
+	Prop   propXML `xml:"DAV: prop"`
+}
+
+type propXML struct {
+	LastModified   string `xml:"DAV: getlastmodified"`
+	ContentLength  string `xml:"DAV: getcontentlength"`
+	ContentType    string `xml:"DAV: getcontenttype"`
+	ETag           string `xml:"DAV: getetag"`
+	QuotaUsed      string `xml:"DAV: quota-used-bytes"`
+	QuotaAvailable string `xml:"DAV: quota-available-bytes"`
+	Permissions    string `xml:"http://owncloud.org/ns permissions"`
+	Size           string `xml:"http://owncloud.org/ns size"`
+	ResourceType   struct {
+		Collection *struct{} `xml:"DAV: collection"`
+	} `xml:"DAV: resourcetype"`
+}
+
+// propfind returns the properties of the resource and, with depth 1, of its children.
+// The keys of the returned map are the paths relative to the root of the share.
+func (fs *ocmStorage) propfind(ctx context.Context, t *remoteTarget, depth string) ([]string, map[string]*propXML, error) {
+	req, err := fs.newRequest(ctx, t, "PROPFIND", t.rel, strings.NewReader(propfindBody))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Depth", depth)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	res, err := fs.do(fs.client, req, http.StatusMultiStatus)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ms := &multistatusXML{}
+	if err := xml.Unmarshal(body, ms); err != nil {
+		return nil, nil, err
+	}
+
+	endpointPath := req.URL.Path
+	if t.rel != "/" {
+		endpointPath = strings.TrimSuffix(endpointPath, t.rel)
+	}
+	endpointPath = strings.TrimSuffix(endpointPath, "/")
+
+	paths := []string{}
+	props := map[string]*propXML{}
+	for _, r := range ms.Responses {
+		href := r.Href
+		if u, err := url.Parse(href); err == nil {
+			href = u.Path
+		}
+		rel := path.Join("/", strings.TrimPrefix(href, endpointPath))
+
+		prop := &propXML{}
+		for _, ps := range r.Propstats {
+			if strings.Contains(ps.Status, " 200 ") {
+				prop = &ps.Prop
+				break
+			}
+		}
+		paths = append(paths, rel)
+		props[rel] = prop
+	}
+	return paths, props, nil
+}
+
+func (fs *ocmStorage) convertToMetadata(t *remoteTarget, rel string, prop *propXML) *api.Metadata {
+	isDir := prop.ResourceType.Collection != nil
+
+	var size uint64
+	if isDir {
+		size, _ = strconv.ParseUint(prop.Size, 10, 64)
+	} else {
+		size, _ = strconv.ParseUint(prop.ContentLength, 10, 64)
+	}
+
+	var mtime uint64
+	if tm, err := http.ParseTime(prop.LastModified); err == nil {
+		mtime = uint64(tm.Unix())
+	}
+
+	mime := prop.ContentType
+	if mime == "" {
+		mime = api.DetectMimeType(isDir, rel)
+	}
+
+	p := path.Join(t.share.Id, rel)
+	return &api.Metadata{
+		Id:    p,
+		Path:  path.Join("/", p),
+		Size:  size,
+		Mtime: mtime,
+		IsDir: isDir,
+		Etag:  prop.ETag,
+		Mime:  mime,
+		// the permissions of ownCloud contain W (write), C (create) or K (mkdir) if the share is writable
+		IsReadOnly:  !strings.ContainsAny(prop.Permissions, "WCK"),
+		IsShareable: false,
+		ShareTarget: t.share.Name,
+	}
+}
+
+func (fs *ocmStorage) listRoot(ctx context.Context) ([]*api.Metadata, error) {
+	shares, err := fs.rsm.ListRemoteShares(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mds := []*api.Metadata{}
+	for _, share := range shares {
+		if share.State != api.RemoteShare_ACCEPTED {
+			continue
+		}
+		md, err := fs.GetMetadata(ctx, path.Join("/", share.Id))
+		if err != nil {
+			// an unreachable remote server must not break the listing
+			fs.logger.Warn("cannot get metadata of remote share", zap.String("id", share.Id), zap.String("remote", share.Remote), zap.Error(err))
+			continue
+		}
+		mds = append(mds, md)
+	}
+	return mds, nil
+}
+
+func (fs *ocmStorage) GetMetadata(ctx context.Context, name string) (*api.Metadata, error) {
+	if name == "/" {
+		return &api.Metadata{Path: "/", IsDir: true, Mime: api.DetectMimeType(true, name), IsReadOnly: true}, nil
+	}
+
+	t, err := fs.getRemoteTarget(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	_, props, err := fs.propfind(ctx, t, "0")
+	if err != nil {
+		return nil, err
+	}
+
+	prop, ok := props[t.rel]
+	if !ok {
+		return nil, api.NewError(api.StorageNotFoundErrorCode)
+	}
+	return fs.convertToMetadata(t, t.rel, prop), nil
+}
+
+// name is /<remote_share_id>/a/b/c
+func (fs *ocmStorage) ListFolder(ctx context.Context, name string) ([]*api.Metadata, error) {
+	if name == "/" {
+		return fs.listRoot(ctx)
+	}
+
+	t, err := fs.getRemoteTarget(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, props, err := fs.propfind(ctx, t, "1")
+	if err != nil {
+		return nil, err
+	}
+
+	mds := []*api.Metadata{}
+	for _, p := range paths {
+		// the folder itself is part of the response
+		if p == t.rel {
+			continue
+		}
+		mds = append(mds, fs.convertToMetadata(t, p, props[p]))
+	}
+	return mds, nil
+}
+
+func (fs *ocmStorage) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	t, err := fs.getRemoteTarget(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := fs.newRequest(ctx, t, "GET", t.rel, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := fs.do(fs.transferClient, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (fs *ocmStorage) Upload(ctx context.Context, name string, r io.ReadCloser) error {
+	defer r.Close()
+	t, err := fs.getRemoteTarget(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	req, err := fs.newRequest(ctx, t, "PUT", t.rel, r)
+	if err != nil {
+		return err
+	}
+
+	res, err := fs.do(fs.transferClient, req, http.StatusOK, http.StatusCreated, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (fs *ocmStorage) CreateDir(ctx context.Context, name string) error {
+	t, err := fs.getRemoteTarget(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	req, err := fs.newRequest(ctx, t, "MKCOL", t.rel, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := fs.do(fs.client, req, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (fs *ocmStorage) Delete(ctx context.Context, name string) error {
+	t, err := fs.getRemoteTarget(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if t.rel == "/" {
+		// removing the share itself is done by rejecting it
+		return api.NewError(api.StoragePermissionDeniedErrorCode)
+	}
+
+	req, err := fs.newRequest(ctx, t, "DELETE", t.rel, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := fs.do(fs.client, req, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (fs *ocmStorage) Move(ctx context.Context, oldName, newName string) error {
+	oldTarget, err := fs.getRemoteTarget(ctx, oldName)
+	if err != nil {
+		return err
+	}
+	newTarget, err := fs.getRemoteTarget(ctx, newName)
+	if err != nil {
+		return err
+	}
+
+	if oldTarget.share.Id != newTarget.share.Id {
+		return api.NewError(api.StorageNotSupportedErrorCode).WithMessage("cross-share rename forbidden")
+	}
+
+	req, err := fs.newRequest(ctx, oldTarget, "MOVE", oldTarget.rel, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Destination", newTarget.url(newTarget.rel))
+	req.Header.Set("Overwrite", "F")
+
+	res, err := fs.do(fs.client, req, http.StatusCreated, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (fs *ocmStorage) GetQuota(ctx context.Context, name string) (int, int, error) {
+	t, err := fs.getRemoteTarget(ctx, name)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	_, props, err := fs.propfind(ctx, t, "0")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	prop, ok := props[t.rel]
+	if !ok {
+		return 0, 0, api.NewError(api.StorageNotFoundErrorCode)
+	}
+
+	used, _ := strconv.Atoi(prop.QuotaUsed)
+	available, _ := strconv.Atoi(prop.QuotaAvailable)
+	if available < 0 { // unknown or unlimited quota
+		available = 0
+	}
+	return used + available, used, nil
+}
+
+func (fs *ocmStorage) GetPathByID(ctx context.Context, id string) (string, error) {
+	return "", api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) SetACL(ctx context.Context, path string, readOnly bool, recipient *api.ShareRecipient, shareList []*api.FolderShare) error {
+	return api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) UnsetACL(ctx context.Context, path string, recipient *api.ShareRecipient, shareList []*api.FolderShare) error {
+	return api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) UpdateACL(ctx context.Context, path string, readOnly bool, recipient *api.ShareRecipient, shareList []*api.FolderShare) error {
+	return api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) ListACL(ctx context.Context, path string) ([]*api.ACLEntry, error) {
+	return nil, api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) ListRevisions(ctx context.Context, path string) ([]*api.Revision, error) {
+	return nil, api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) DownloadRevision(ctx context.Context, path, revisionKey string) (io.ReadCloser, error) {
+	return nil, api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) RestoreRevision(ctx context.Context, path, revisionKey string) error {
+	return api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) EmptyRecycle(ctx context.Context, path string) error {
+	return api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) ListRecycle(ctx context.Context, path string) ([]*api.RecycleEntry, error) {
+	return nil, api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+func (fs *ocmStorage) RestoreRecycleEntry(ctx context.Context, restoreKey string) error {
+	return api.NewError(api.StorageNotSupportedErrorCode)
+}
@@ -3,26 +3,39 @@ package storage_public_link
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/cernbox/revaold/api"
 	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
 	"go.uber.org/zap"
 )
 
+// maxUploaderNameLength is the maximum length of the folders named after the uploaders.
+const maxUploaderNameLength = 64
+
+// reservationBlock is the number of bytes reserved at once while reading
+// the uploads of unknown size to links with a maximum total size.
+const reservationBlock = 4 << 20
+
 type linkStorage struct {
 	vfs         api.VirtualStorage
 	linkManager api.PublicLinkManager
+	notifier    api.Notifier
 	logger      *zap.Logger
 }
 
 type Options struct {
 }
 
-func New(opt *Options, vfs api.VirtualStorage, lm api.PublicLinkManager, logger *zap.Logger) api.Storage {
-	return &linkStorage{vfs, lm, logger}
+// New returns the storage of the public links. The notifier sends the digests
+// of the uploads to drop only links, it can be nil to disable them.
+func New(opt *Options, vfs api.VirtualStorage, lm api.PublicLinkManager, n api.Notifier, logger *zap.Logger) api.Storage {
+	return &linkStorage{vfs, lm, n, logger}
 }
 
 func getPublicLinkFromContext(ctx context.Context) (*api.PublicLink, error) {
@@ -103,6 +116,10 @@ func (fs *linkStorage) UpdateACL(ctx context.Context, path string, readOnly bool
 	return api.NewError(api.StorageNotSupportedErrorCode)
 }
 
+func (fs *linkStorage) ListACL(ctx context.Context, path string) ([]*api.ACLEntry, error) {
+	return nil, api.NewError(api.StorageNotSupportedErrorCode)
+}
+
 func (fs *linkStorage) GetMetadata(ctx context.Context, p string) (*api.Metadata, error) {
 	if p == "/" {
 		return &api.Metadata{
@@ -123,7 +140,10 @@ func (fs *linkStorage) GetMetadata(ctx context.Context, p string) (*api.Metadata
 		return nil, err
 	}
 
-	internalPath := path.Join(link.Path, linkRelativePath)
+	internalPath, err := fs.getInternalPath(ctx, link, linkRelativePath)
+	if err != nil {
+		return nil, err
+	}
 	md, err := fs.vfs.GetMetadata(ctx, internalPath)
 	if err != nil {
 		return nil, err
@@ -131,6 +151,10 @@ func (fs *linkStorage) GetMetadata(ctx context.Context, p string) (*api.Metadata
 
 	md.IsReadOnly = linkMetadata.IsReadOnly
 	md.Path = path.Join("/", link.Token, strings.TrimPrefix(md.Path, linkMetadata.Path))
+	if link.DropOnly {
+		// the folder of the uploader is hidden, the uploads are where they were sent
+		md.Path = path.Join("/", link.Token, linkRelativePath)
+	}
 	md.Id = link.Token
 	md.IsShareable = linkMetadata.IsShareable
 	return md, nil
@@ -203,6 +227,8 @@ func (fs *linkStorage) Download(ctx context.Context, name string) (io.ReadCloser
 		return nil, dropOnlyError(link.Id)
 	}
 
+	// the downloads are counted by the proxies, the reads of the previews
+	// and of the apps opening the files are not downloads.
 	p = path.Join(link.Path, p)
 	return fs.vfs.Download(ctx, p)
 }
@@ -217,6 +243,9 @@ func (fs *linkStorage) Upload(ctx context.Context, name string, r io.ReadCloser)
 		return readOnlyError(link.Id)
 	}
 
+	var limit int64 = -1
+	var reservation *uploadReservation
+	uploaded := false
 	if link.DropOnly {
 		// we cannot append a uuid to the filename as the ocproxy and other clients
 		// may rely on stat-upload-stat mechanism to check for a valid write, thus changing
@@ -229,10 +258,263 @@ func (fs *linkStorage) Upload(ctx context.Context, name string, r io.ReadCloser)
 			// filename alrady exists, we abort
 			return api.NewError(api.StorageAlreadyExistsErrorCode)
 		}
+
+		limit, err = fs.checkUploadPolicy(link, p, r)
+		if err != nil {
+			fs.logger.Warn("upload rejected by link policy", zap.Error(err), zap.String("id", link.Id), zap.String("path", p))
+			return err
+		}
+
+		// the uploaded bytes of the link may have changed since it was read, they are
+		// reserved before being written so concurrent uploads cannot exceed the maximum together.
+		if link.UploadPolicy != nil && link.UploadPolicy.MaxTotalSize > 0 {
+			reservation = &uploadReservation{ctx: ctx, lm: fs.linkManager, id: link.Id, logger: fs.logger}
+			defer func() {
+				if !uploaded {
+					reservation.release(0)
+				}
+			}()
+			if size, ok := getSize(r); ok {
+				if err := reservation.reserve(size); err != nil {
+					fs.logger.Warn("upload rejected by link policy", zap.Error(err), zap.String("id", link.Id), zap.String("path", p))
+					return err
+				}
+			}
+		}
 	}
 
-	p = path.Join(link.Path, p)
-	return fs.vfs.Upload(ctx, p, r)
+	internalPath, err := fs.getInternalPath(ctx, link, p)
+	if err != nil {
+		return err
+	}
+	if dir := path.Dir(internalPath); link.DropOnly && dir != path.Clean(link.Path) {
+		if err := fs.createUploaderFolder(ctx, dir); err != nil {
+			return err
+		}
+	}
+
+	lr := &limitedReader{ReadCloser: r, remaining: limit, reservation: reservation}
+	if err := fs.vfs.Upload(ctx, internalPath, lr); err != nil {
+		if lr.exceeded {
+			// the size was not known in advance, the partial upload is removed
+			if err := fs.vfs.Delete(ctx, internalPath); err != nil {
+				fs.logger.Error("error removing upload exceeding link policy", zap.Error(err), zap.String("path", internalPath))
+			}
+			if lr.reservationErr != nil {
+				return lr.reservationErr
+			}
+			return api.NewError(api.PublicLinkUploadTooLargeErrorCode).WithMessage(fmt.Sprintf("the upload exceeds the maximum of %d bytes allowed by the link", limit))
+		}
+		return err
+	}
+	uploaded = true
+
+	// the reserved bytes are already counted, only the ones not used are given back
+	unreserved := uint64(lr.read)
+	if reservation != nil {
+		reservation.release(lr.read)
+		unreserved = 0
+	}
+
+	// the file is already uploaded, failing to count it must not fail the upload.
+	if err := fs.linkManager.RecordPublicLinkUpload(ctx, link.Id, unreserved); err != nil {
+		fs.logger.Error("error recording link upload", zap.Error(err), zap.String("id", link.Id))
+	}
+
+	if link.DropOnly && link.UploadPolicy != nil && link.UploadPolicy.Digest && fs.notifier != nil {
+		uploader, _ := api.ContextGetUploaderName(ctx)
+		upload := &api.PublicLinkUpload{Name: path.Base(p), Uploader: uploader, Size: uint64(lr.read), Time: time.Now()}
+		if err := fs.notifier.NotifyPublicLinkUpload(ctx, link, upload); err != nil {
+			fs.logger.Error("error adding upload to link digest", zap.Error(err), zap.String("id", link.Id))
+		}
+	}
+	return nil
+}
+
+// getInternalPath returns the path in the storage of the owner of a path relative to the link.
+// The uploads to drop only links that put them in a folder per uploader go to that folder.
+func (fs *linkStorage) getInternalPath(ctx context.Context, link *api.PublicLink, p string) (string, error) {
+	if !link.DropOnly || link.UploadPolicy == nil || !link.UploadPolicy.UploaderFolders || p == "" {
+		return path.Join(link.Path, p), nil
+	}
+
+	name, _ := api.ContextGetUploaderName(ctx)
+	name = getUploaderFolder(name)
+	if name == "" {
+		return "", api.NewError(api.PublicLinkUploaderNameRequiredErrorCode).WithMessage("the link requires the name of the uploader")
+	}
+	return path.Join(link.Path, name, p), nil
+}
+
+func (fs *linkStorage) createUploaderFolder(ctx context.Context, dir string) error {
+	if _, err := fs.vfs.GetMetadata(ctx, dir); err == nil {
+		return nil
+	}
+	if err := fs.vfs.CreateDir(ctx, dir); err != nil && !api.IsErrorCode(err, api.StorageAlreadyExistsErrorCode) {
+		return err
+	}
+	return nil
+}
+
+// checkUploadPolicy checks the name and, if known in advance, the size of the upload
+// against the policy of the link. It returns the maximum number of bytes that
+// can still be uploaded, or -1 if unlimited.
+func (fs *linkStorage) checkUploadPolicy(link *api.PublicLink, p string, r io.Reader) (int64, error) {
+	policy := link.UploadPolicy
+	if policy == nil {
+		return -1, nil
+	}
+
+	if len(policy.AllowedTypes) > 0 && !isTypeAllowed(p, policy.AllowedTypes) {
+		return 0, api.NewError(api.PublicLinkUploadTypeNotAllowedErrorCode).WithMessage(fmt.Sprintf("the link only accepts files of type %s", strings.Join(policy.AllowedTypes, ", ")))
+	}
+
+	var limit int64 = -1
+	if policy.MaxFileSize > 0 {
+		limit = int64(policy.MaxFileSize)
+	}
+	if policy.MaxTotalSize > 0 {
+		remaining := int64(policy.MaxTotalSize) - int64(link.UploadedBytes)
+		if remaining < 0 {
+			remaining = 0
+		}
+		if limit < 0 || remaining < limit {
+			limit = remaining
+		}
+	}
+
+	if size, ok := getSize(r); ok && limit >= 0 && size > limit {
+		if policy.MaxFileSize > 0 && size > int64(policy.MaxFileSize) {
+			return 0, api.NewError(api.PublicLinkUploadTooLargeErrorCode).WithMessage(fmt.Sprintf("the link only accepts files up to %d bytes", policy.MaxFileSize))
+		}
+		return 0, api.NewError(api.PublicLinkUploadTooLargeErrorCode).WithMessage(fmt.Sprintf("the link has only %d bytes left of its maximum of %d", limit, policy.MaxTotalSize))
+	}
+	return limit, nil
+}
+
+// isTypeAllowed returns true if the extension or the mime type of the file,
+// detected from the extension, is in the allowed ones, like .pdf, application/pdf or image/*.
+func isTypeAllowed(p string, allowed []string) bool {
+	ext := strings.ToLower(path.Ext(p))
+	mimeType := strings.ToLower(api.DetectMimeType(false, p))
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:i])
+	}
+
+	for _, t := range allowed {
+		t = strings.ToLower(strings.TrimSpace(t))
+		switch {
+		case strings.HasPrefix(t, "."):
+			if ext == t {
+				return true
+			}
+		case strings.HasSuffix(t, "/*"):
+			if mimeType != "" && strings.HasPrefix(mimeType, strings.TrimSuffix(t, "*")) {
+				return true
+			}
+		case mimeType != "" && mimeType == t:
+			return true
+		}
+	}
+	return false
+}
+
+// getUploaderFolder returns the name of the uploader as a valid folder name,
+// or empty if it is not usable.
+func getUploaderFolder(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "." || name == ".." {
+		return ""
+	}
+	if len(name) > maxUploaderNameLength {
+		name = name[:maxUploaderNameLength]
+	}
+	return name
+}
+
+// getSize returns the size of the data if known in advance, like for
+// the files assembled by the storage service.
+func getSize(r io.Reader) (int64, bool) {
+	f, ok := r.(interface {
+		Stat() (os.FileInfo, error)
+	})
+	if !ok {
+		return 0, false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, false
+	}
+	return fi.Size(), true
+}
+
+// limitedReader counts the bytes read and fails once more than remaining bytes
+// are read, a negative remaining means unlimited. With a reservation, it also
+// fails once the bytes read cannot be reserved.
+type limitedReader struct {
+	io.ReadCloser
+	remaining      int64
+	read           int64
+	exceeded       bool
+	reservation    *uploadReservation
+	reservationErr error
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.read += int64(n)
+	if r.remaining >= 0 && r.read > r.remaining {
+		r.exceeded = true
+		return n, errors.New("upload exceeds the size allowed by the link")
+	}
+	if r.reservation != nil && r.read > r.reservation.reserved {
+		// a block more is reserved to not reserve on every read, unless
+		// the link has not that many bytes left
+		if rerr := r.reservation.reserve(r.read + reservationBlock); rerr != nil {
+			if rerr := r.reservation.reserve(r.read); rerr != nil {
+				if api.IsErrorCode(rerr, api.PublicLinkUploadTooLargeErrorCode) {
+					r.exceeded = true
+					r.reservationErr = rerr
+				}
+				return n, rerr
+			}
+		}
+	}
+	return n, err
+}
+
+// uploadReservation keeps the bytes reserved for an upload to a link.
+type uploadReservation struct {
+	ctx      context.Context
+	lm       api.PublicLinkManager
+	id       string
+	logger   *zap.Logger
+	reserved int64
+}
+
+// reserve makes sure size bytes are reserved.
+func (r *uploadReservation) reserve(size int64) error {
+	if size <= r.reserved {
+		return nil
+	}
+	if err := r.lm.ReservePublicLinkUpload(r.ctx, r.id, uint64(size-r.reserved)); err != nil {
+		return err
+	}
+	r.reserved = size
+	return nil
+}
+
+// release gives back the bytes reserved beyond the used ones.
+func (r *uploadReservation) release(used int64) {
+	if r.reserved <= used {
+		return
+	}
+	if err := r.lm.ReleasePublicLinkUpload(r.ctx, r.id, uint64(r.reserved-used)); err != nil {
+		r.logger.Error("error releasing bytes reserved for link upload", zap.Error(err), zap.String("id", r.id))
+		return
+	}
+	r.reserved = used
 }
 
 func (fs *linkStorage) Move(ctx context.Context, oldName, newName string) error {
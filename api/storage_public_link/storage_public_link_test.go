@@ -0,0 +1,116 @@
+package storage_public_link
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"go.uber.org/zap"
+)
+
+type fakeStorage struct {
+	api.VirtualStorage
+	files     map[string]string
+	failWrite bool
+}
+
+func (fs *fakeStorage) GetMetadata(ctx context.Context, p string) (*api.Metadata, error) {
+	if p == "/home/drop" {
+		return &api.Metadata{Id: p, Path: p, IsDir: true}, nil
+	}
+	if _, ok := fs.files[p]; ok {
+		return &api.Metadata{Id: p, Path: p}, nil
+	}
+	return nil, api.NewError(api.StorageNotFoundErrorCode)
+}
+
+func (fs *fakeStorage) Upload(ctx context.Context, p string, r io.ReadCloser) error {
+	data, err := ioutil.ReadAll(r)
+	fs.files[p] = string(data)
+	if err != nil {
+		return err
+	}
+	if fs.failWrite {
+		return errors.New("disk full")
+	}
+	return nil
+}
+
+func (fs *fakeStorage) Delete(ctx context.Context, p string) error {
+	delete(fs.files, p)
+	return nil
+}
+
+// fakeLinkManager returns the link as it was when created, like a link
+// read before other uploads to it were counted.
+type fakeLinkManager struct {
+	api.PublicLinkManager
+	link     *api.PublicLink
+	uploaded uint64
+	uploads  int
+}
+
+func (lm *fakeLinkManager) InspectPublicLinkByToken(ctx context.Context, token string) (*api.PublicLink, error) {
+	return lm.link, nil
+}
+
+func (lm *fakeLinkManager) ReservePublicLinkUpload(ctx context.Context, id string, size uint64) error {
+	if lm.uploaded+size > lm.link.UploadPolicy.MaxTotalSize {
+		return api.NewError(api.PublicLinkUploadTooLargeErrorCode)
+	}
+	lm.uploaded += size
+	return nil
+}
+
+func (lm *fakeLinkManager) ReleasePublicLinkUpload(ctx context.Context, id string, size uint64) error {
+	lm.uploaded -= size
+	return nil
+}
+
+func (lm *fakeLinkManager) RecordPublicLinkUpload(ctx context.Context, id string, size uint64) error {
+	lm.uploads++
+	lm.uploaded += size
+	return nil
+}
+
+func newLinkStorage() (*linkStorage, *fakeStorage, *fakeLinkManager, context.Context) {
+	link := &api.PublicLink{Id: "1", Token: "abc", Path: "/home/drop", DropOnly: true, UploadPolicy: &api.UploadPolicy{MaxTotalSize: 10}}
+	vfs := &fakeStorage{files: map[string]string{}}
+	lm := &fakeLinkManager{link: link}
+	fs := New(&Options{}, vfs, lm, nil, zap.NewNop()).(*linkStorage)
+	return fs, vfs, lm, api.ContextSetPublicLink(context.Background(), link)
+}
+
+func TestUploadsCannotExceedTheTotalSizeTogether(t *testing.T) {
+	fs, vfs, lm, ctx := newLinkStorage()
+
+	if err := fs.Upload(ctx, "/abc/a.txt", ioutil.NopCloser(strings.NewReader("123456"))); err != nil {
+		t.Fatal(err)
+	}
+	err := fs.Upload(ctx, "/abc/b.txt", ioutil.NopCloser(strings.NewReader("123456")))
+	if !api.IsErrorCode(err, api.PublicLinkUploadTooLargeErrorCode) {
+		t.Fatalf("expected the upload to be too large, got %v", err)
+	}
+	if _, ok := vfs.files["/home/drop/b.txt"]; ok {
+		t.Error("partial upload not deleted")
+	}
+	if lm.uploaded != 6 || lm.uploads != 1 {
+		t.Errorf("expected one upload of 6 bytes counted, got %d uploads of %d bytes", lm.uploads, lm.uploaded)
+	}
+}
+
+func TestFailedUploadReleasesTheReservedBytes(t *testing.T) {
+	fs, vfs, lm, ctx := newLinkStorage()
+	vfs.failWrite = true
+
+	if err := fs.Upload(ctx, "/abc/a.txt", ioutil.NopCloser(strings.NewReader("123456"))); err == nil {
+		t.Fatal("expected the upload to fail")
+	}
+	if lm.uploaded != 0 || lm.uploads != 0 {
+		t.Errorf("expected nothing counted, got %d uploads of %d bytes", lm.uploads, lm.uploaded)
+	}
+}
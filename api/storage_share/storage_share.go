@@ -36,7 +36,7 @@ func (fs *shareStorage) getReceivedShare(ctx context.Context, name string) (*api
 		return nil, "", api.NewError(api.StorageNotFoundErrorCode)
 	}
 
-	id := items[1]
+	id := getShareID(items[1])
 	share, err := fs.shareManager.GetReceivedFolderShare(ctx, id)
 	if err != nil {
 		return nil, "", err
@@ -51,6 +51,25 @@ func (fs *shareStorage) getReceivedShare(ctx context.Context, name string) (*api
 	return share, relativePath, nil
 }
 
+// getShareID returns the id of the share from the first element of a path,
+// that can be the id or the target of the share followed by (id:<id>).
+func getShareID(name string) string {
+	loc := shareIDRegexp.FindStringIndex(name)
+	if loc == nil {
+		return name
+	}
+	return name[loc[0]+4 : loc[1]-1]
+}
+
+// getShareTarget returns the target in a name like <target> (id:<id>).
+func getShareTarget(name string) string {
+	loc := shareIDRegexp.FindStringIndex(name)
+	if loc == nil {
+		return name
+	}
+	return strings.TrimSpace(name[0:loc[0]])
+}
+
 func (fs *shareStorage) GetPathByID(ctx context.Context, id string) (string, error) {
 	path := "/" + id
 	_, _, err := fs.getReceivedShare(ctx, path)
@@ -87,6 +106,10 @@ func (fs *shareStorage) UpdateACL(ctx context.Context, path string, readOnly boo
 	return api.NewError(api.StorageNotSupportedErrorCode)
 }
 
+func (fs *shareStorage) ListACL(ctx context.Context, path string) ([]*api.ACLEntry, error) {
+	return nil, api.NewError(api.StorageNotSupportedErrorCode)
+}
+
 func (fs *shareStorage) GetMetadata(ctx context.Context, p string) (*api.Metadata, error) {
 	if p == "/" {
 		return &api.Metadata{
@@ -209,6 +232,12 @@ func (fs *shareStorage) Move(ctx context.Context, oldName, newName string) error
 	if err != nil {
 		return err
 	}
+
+	// moving the share itself only changes the name under which the user sees it,
+	// the new name can be given as <target> or <target> (id:<id>).
+	if oldPath == "" {
+		return fs.renameReceivedShare(ctx, oldShare, newName)
+	}
 	newShare, newPath, err := fs.getReceivedShare(ctx, newName)
 	if err != nil {
 		return err
@@ -228,6 +257,27 @@ func (fs *shareStorage) Move(ctx context.Context, oldName, newName string) error
 	return fs.vs.Move(newCtx, oldPath, newPath)
 }
 
+func (fs *shareStorage) renameReceivedShare(ctx context.Context, share *api.FolderShare, newName string) error {
+	items := strings.Split(strings.Trim(newName, "/"), "/")
+	if len(items) != 1 {
+		return api.NewError(api.StorageNotSupportedErrorCode).WithMessage("received shares can only be renamed")
+	}
+
+	name := items[0]
+	if id := getShareID(name); id != name && id != share.Id {
+		return errors.New("cross-share rename forbidden")
+	}
+
+	// moving the share to its own id keeps the current name
+	target := getShareTarget(name)
+	if target == share.Id {
+		return nil
+	}
+
+	_, err := fs.shareManager.RenameReceivedShare(ctx, share.Id, target)
+	return err
+}
+
 func (fs *shareStorage) GetQuota(ctx context.Context, name string) (int, int, error) {
 	share, p, err := fs.getReceivedShare(ctx, name)
 	if err != nil {
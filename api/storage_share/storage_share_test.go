@@ -0,0 +1,82 @@
+package storage_share
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"go.uber.org/zap"
+)
+
+// fakeShareManager has the received shares 42, read-write, and 43, read-only.
+type fakeShareManager struct {
+	api.ShareManager
+	renamed map[string]string
+}
+
+func (sm *fakeShareManager) GetReceivedFolderShare(ctx context.Context, id string) (*api.FolderShare, error) {
+	switch id {
+	case "42":
+		return &api.FolderShare{Id: "42", OwnerId: "alice", Path: "/alice/project", Target: "project"}, nil
+	case "43":
+		return &api.FolderShare{Id: "43", OwnerId: "alice", Path: "/alice/photos", Target: "photos", ReadOnly: true}, nil
+	}
+	return nil, api.NewError(api.FolderShareNotFoundErrorCode)
+}
+
+func (sm *fakeShareManager) RenameReceivedShare(ctx context.Context, id, target string) (*api.FolderShare, error) {
+	sm.renamed[id] = target
+	return &api.FolderShare{Id: id, Target: target}, nil
+}
+
+type fakeStorage struct {
+	api.VirtualStorage
+	moves []string
+}
+
+func (vs *fakeStorage) Move(ctx context.Context, oldPath, newPath string) error {
+	u, _ := api.ContextGetUser(ctx)
+	vs.moves = append(vs.moves, u.AccountId+":"+oldPath+"->"+newPath)
+	return nil
+}
+
+func TestMoveReceivedShare(t *testing.T) {
+	tests := []struct {
+		oldName, newName string
+		ok               bool
+		renamed, moved   string
+	}{
+		{"/42", "/reviews", true, "reviews", ""},
+		{"/42", "/reviews (id:42)", true, "reviews", ""},
+		{"/project (id:42)", "/reviews (id:42)", true, "reviews", ""},
+		{"/42", "/42", true, "", ""},
+		{"/42", "/reviews (id:43)", false, "", ""},
+		{"/42", "/reviews/2018", false, "", ""},
+		{"/42/a.txt", "/42/b.txt", true, "", "alice:/alice/project/a.txt->/alice/project/b.txt"},
+		{"/42/a.txt", "/reviews (id:42)/b.txt", true, "", "alice:/alice/project/a.txt->/alice/project/b.txt"},
+		{"/42/a.txt", "/43/a.txt", false, "", ""},
+		{"/43/a.txt", "/43/b.txt", false, "", ""},
+	}
+	for _, test := range tests {
+		sm := &fakeShareManager{renamed: map[string]string{}}
+		vs := &fakeStorage{}
+		fs := New(&Options{}, vs, sm, zap.NewNop())
+		ctx := api.ContextSetUser(context.Background(), &api.User{AccountId: "bob"})
+
+		err := fs.Move(ctx, test.oldName, test.newName)
+		if (err == nil) != test.ok {
+			t.Errorf("%s -> %s: expected success %t, got %v", test.oldName, test.newName, test.ok, err)
+			continue
+		}
+		if sm.renamed["42"] != test.renamed || len(sm.renamed) > 1 {
+			t.Errorf("%s -> %s: expected the share renamed to %q, got %v", test.oldName, test.newName, test.renamed, sm.renamed)
+		}
+		var moved string
+		if len(vs.moves) > 0 {
+			moved = vs.moves[0]
+		}
+		if moved != test.moved || len(vs.moves) > 1 {
+			t.Errorf("%s -> %s: expected the move %q, got %v", test.oldName, test.newName, test.moved, vs.moves)
+		}
+	}
+}
@@ -153,6 +153,16 @@ func (fs *eosStorage) UpdateACL(ctx context.Context, path string, readOnly bool,
 	return ts.UpdateACL(ctx, path, readOnly, recipient, shareList)
 }
 
+func (fs *eosStorage) ListACL(ctx context.Context, path string) ([]*api.ACLEntry, error) {
+	_, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, _, _, path := fs.getStorageForPath(ctx, path)
+	return ts.ListACL(ctx, path)
+}
+
 func (fs *eosStorage) GetQuota(ctx context.Context, p string) (int, int, error) {
 	_, err := getUserFromContext(ctx)
 	if err != nil {
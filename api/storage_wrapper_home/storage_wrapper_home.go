@@ -95,6 +95,15 @@ func (fs *homeStorage) UnsetACL(ctx context.Context, path string, recipient *api
 	return nil
 }
 
+func (fs *homeStorage) ListACL(ctx context.Context, path string) ([]*api.ACLEntry, error) {
+	u, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path = fs.getInternalPath(ctx, u, path)
+	return fs.wrappedStorage.ListACL(ctx, path)
+}
+
 func (fs *homeStorage) GetPathByID(ctx context.Context, id string) (string, error) {
 	u, err := getUserFromContext(ctx)
 	if err != nil {
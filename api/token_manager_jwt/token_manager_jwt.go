@@ -2,33 +2,365 @@ package token_manager_jwt
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math/big"
 	"time"
 
+	"github.com/bluele/gcache"
 	"github.com/cernbox/revaold/api"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
 	"go.uber.org/zap"
 )
 
-func New(signSecret string) api.TokenManager {
-	return &tokenManager{signSecret: signSecret}
+// refreshTokenType is the type claim of the refresh tokens, the user tokens have none.
+const refreshTokenType = "refresh"
+
+type Options struct {
+	// Secret signs the tokens with HS256 when there is no private key.
+	Secret string
+
+	// AcceptSecretTokens accepts the HS256 tokens signed with Secret when there
+	// is a private key, so the ones signed before moving to the private key remain
+	// valid until they expire. It is meant to be unset once the migration is done.
+	AcceptSecretTokens bool
+
+	// PrivateKeyFile is a PEM file with the RSA or EC private key signing the tokens,
+	// with RS256 or ES256, identified by KeyID in the kid header. If KeyID is empty
+	// it is derived from the public key.
+	PrivateKeyFile string
+	KeyID          string
+
+	// PublicKeyFiles maps key ids to PEM files with the public keys verifying the tokens
+	// besides the signing one, like the previous key while the keys are rotated.
+	PublicKeyFiles map[string]string
+
+	// The lifetimes of the tokens in seconds.
+	UserTokenLifetime          int
+	PublicLinkTokenLifetime    int
+	RefreshTokenLifetime       int
+	ScopedTokenLifetime        int
+	ImpersonationTokenLifetime int
+
+	// MaxScopedTokenLifetime caps the lifetime asked for a scoped token,
+	// like the one of an app for the length of an editing session.
+	MaxScopedTokenLifetime int
+
+	// RevocationStore keeps the revoked tokens, if nil the tokens cannot be
+	// revoked and no refresh tokens are forged.
+	RevocationStore api.TokenRevocationStore
+
+	// The revocations are cached during RevocationCacheEviction seconds, so a token
+	// revoked by another instance is rejected after at most that time.
+	RevocationCacheSize     int
+	RevocationCacheEviction int
+}
+
+func (opt *Options) init() {
+	if opt.UserTokenLifetime <= 0 {
+		opt.UserTokenLifetime = 3600
+	}
+	if opt.PublicLinkTokenLifetime <= 0 {
+		opt.PublicLinkTokenLifetime = 3600
+	}
+	if opt.RefreshTokenLifetime <= 0 {
+		opt.RefreshTokenLifetime = 604800
+	}
+	if opt.ScopedTokenLifetime <= 0 {
+		opt.ScopedTokenLifetime = 600
+	}
+	if opt.MaxScopedTokenLifetime <= 0 {
+		opt.MaxScopedTokenLifetime = 43200
+	}
+	if opt.ImpersonationTokenLifetime <= 0 {
+		opt.ImpersonationTokenLifetime = 900
+	}
+	if opt.RevocationCacheSize <= 0 {
+		opt.RevocationCacheSize = 100000
+	}
+	if opt.RevocationCacheEviction <= 0 {
+		opt.RevocationCacheEviction = 10
+	}
+}
+
+func New(opt *Options) (api.TokenManager, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	tm := &tokenManager{
+		opt:             opt,
+		publicKeys:      map[string]interface{}{},
+		revokedTokens:   gcache.New(opt.RevocationCacheSize).LRU().Build(),
+		revokedUsers:    gcache.New(opt.RevocationCacheSize).LRU().Build(),
+		revocationCache: time.Second * time.Duration(opt.RevocationCacheEviction),
+	}
+	if opt.PrivateKeyFile == "" {
+		if opt.Secret == "" {
+			return nil, errors.New("jwt: a secret or a private key is required")
+		}
+		tm.signingMethod = jwt.SigningMethodHS256
+		tm.signingKey = []byte(opt.Secret)
+	} else {
+		data, err := ioutil.ReadFile(opt.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			tm.signingMethod, tm.signingKey = jwt.SigningMethodRS256, key
+			tm.keyID = opt.KeyID
+		} else if key, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+			method, err := getECSigningMethod(&key.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			tm.signingMethod, tm.signingKey = method, key
+			tm.keyID = opt.KeyID
+		} else {
+			return nil, fmt.Errorf("jwt: %s is not a PEM encoded RSA or EC private key", opt.PrivateKeyFile)
+		}
+
+		publicKey := getPublicKey(tm.signingKey)
+		if tm.keyID == "" {
+			kid, err := getKeyID(publicKey)
+			if err != nil {
+				return nil, err
+			}
+			tm.keyID = kid
+		}
+		tm.publicKeys = map[string]interface{}{tm.keyID: publicKey}
+	}
+
+	for kid, file := range opt.PublicKeyFiles {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+			tm.publicKeys[kid] = key
+		} else if key, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+			tm.publicKeys[kid] = key
+		} else {
+			return nil, fmt.Errorf("jwt: %s is not a PEM encoded RSA or EC public key", file)
+		}
+	}
+	return tm, nil
 }
 
 type tokenManager struct {
-	signSecret string
+	opt           *Options
+	signingMethod jwt.SigningMethod
+	signingKey    interface{}
+	keyID         string
+	publicKeys    map[string]interface{}
+
+	revokedTokens   gcache.Cache
+	revokedUsers    gcache.Cache
+	revocationCache time.Duration
+}
+
+func (tm *tokenManager) newToken() *jwt.Token {
+	token := jwt.New(tm.signingMethod)
+	if tm.keyID != "" {
+		token.Header["kid"] = tm.keyID
+	}
+	return token
+}
+
+// getVerificationKey selects the key by the signing method and the kid of the token.
+// The HS256 tokens are only accepted with a secret, so a public key can never be used as one,
+// and with a private key only if explicitly enabled.
+func (tm *tokenManager) getVerificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if tm.opt.Secret == "" || tm.opt.PrivateKeyFile != "" && !tm.opt.AcceptSecretTokens {
+			return nil, errors.New("HS256 tokens are not accepted")
+		}
+		return []byte(tm.opt.Secret), nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		kid, _ := token.Header["kid"].(string)
+		key, ok := tm.publicKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Header["alg"])
+	}
 }
 
 func (tm *tokenManager) ForgeUserToken(ctx context.Context, user *api.User) (string, error) {
+	return tm.forgeUserToken(ctx, user, "", tm.opt.UserTokenLifetime)
+}
+
+func (tm *tokenManager) DismantleUserToken(ctx context.Context, token string) (*api.User, error) {
+	return tm.dismantleUserToken(ctx, token, "")
+}
+
+func (tm *tokenManager) ForgeScopedToken(ctx context.Context, user *api.User, scope *api.TokenScope, lifetime int) (string, error) {
+	if scope == nil || scope.Path == "" && scope.ResourceId == "" {
+		return "", api.NewError(api.PathInvalidError).WithMessage("a scoped token must be restricted to a path or a resource")
+	}
+	// the impersonations do not outlive their short lived token
+	if user.Impersonator != "" {
+		return "", api.NewError(api.PermissionDeniedErrorCode).WithMessage("no scoped tokens for impersonations")
+	}
+	if lifetime <= 0 {
+		lifetime = tm.opt.ScopedTokenLifetime
+	}
+	if lifetime > tm.opt.MaxScopedTokenLifetime {
+		lifetime = tm.opt.MaxScopedTokenLifetime
+	}
+	scopedUser := *user
+	scopedUser.Scope = scope
+	return tm.forgeUserToken(ctx, &scopedUser, "", lifetime)
+}
+
+func (tm *tokenManager) ForgeImpersonationToken(ctx context.Context, user *api.User) (string, error) {
+	if user.Impersonator == "" {
+		return "", errors.New("jwt: the impersonation token has no impersonator")
+	}
+	return tm.forgeUserToken(ctx, user, "", tm.opt.ImpersonationTokenLifetime)
+}
+
+func (tm *tokenManager) ForgeRefreshToken(ctx context.Context, user *api.User) (string, error) {
+	// a refresh token that cannot be revoked would be valid for too long
+	if tm.opt.RevocationStore == nil {
+		return "", api.NewError(api.StorageNotSupportedErrorCode).WithMessage("refresh tokens require a revocation store")
+	}
+	// the impersonations do not outlive their short lived token
+	if user.Impersonator != "" {
+		return "", api.NewError(api.PermissionDeniedErrorCode).WithMessage("no refresh tokens for impersonations")
+	}
+	return tm.forgeUserToken(ctx, user, refreshTokenType, tm.opt.RefreshTokenLifetime)
+}
+
+// RedeemRefreshToken relies on the store to revoke the token and tell if it was already
+// revoked in the same step, as the cached revocations may be outdated.
+func (tm *tokenManager) RedeemRefreshToken(ctx context.Context, token string) (*api.User, error) {
+	l := ctx_zap.Extract(ctx)
+	if tm.opt.RevocationStore == nil {
+		return nil, api.NewError(api.StorageNotSupportedErrorCode).WithMessage("refresh tokens require a revocation store")
+	}
+
+	user, claims, err := tm.dismantle(ctx, token, refreshTokenType)
+	if err != nil {
+		return nil, api.NewError(api.TokenInvalidErrorCode).WithMessage(err.Error())
+	}
+	id, _ := claims["jti"].(string)
+	if id == "" {
+		return nil, api.NewError(api.TokenInvalidErrorCode).WithMessage("the refresh token has no id")
+	}
+
+	exp, _ := claims["exp"].(float64)
+	revoked, err := tm.opt.RevocationStore.RevokeToken(ctx, id, time.Unix(int64(exp), 0))
+	if err != nil {
+		l.Error("error revoking refresh token", zap.Error(err))
+		return nil, err
+	}
+	tm.revokedTokens.SetWithExpire(id, true, tm.revocationCache)
+	if !revoked {
+		l.Warn("audit: refresh token redeemed twice", zap.String("account_id", user.AccountId), zap.String("jti", id))
+		return nil, api.NewError(api.TokenInvalidErrorCode).WithMessage("the refresh token was already redeemed")
+	}
+	return user, nil
+}
+
+func (tm *tokenManager) RevokeUserToken(ctx context.Context, token string) error {
+	l := ctx_zap.Extract(ctx)
+	if tm.opt.RevocationStore == nil {
+		return api.NewError(api.StorageNotSupportedErrorCode).WithMessage("no revocation store")
+	}
+
+	claims, err := tm.parse(ctx, token)
+	if err != nil {
+		return api.NewError(api.TokenInvalidErrorCode).WithMessage(err.Error())
+	}
+	id, _ := claims["jti"].(string)
+	if id == "" {
+		return api.NewError(api.TokenInvalidErrorCode).WithMessage("the token has no id, it was forged before the tokens could be revoked")
+	}
+	exp, _ := claims["exp"].(float64)
+	if _, err := tm.opt.RevocationStore.RevokeToken(ctx, id, time.Unix(int64(exp), 0)); err != nil {
+		l.Error("error revoking token", zap.Error(err))
+		return err
+	}
+	tm.revokedTokens.SetWithExpire(id, true, tm.revocationCache)
+
+	accountID, _ := claims["account_id"].(string)
+	l.Info("audit: token revoked", zap.String("account_id", accountID), zap.String("jti", id))
+	return nil
+}
+
+func (tm *tokenManager) RevokeUserTokens(ctx context.Context, accountID string) error {
 	l := ctx_zap.Extract(ctx)
-	token := jwt.New(jwt.GetSigningMethod("HS256"))
+	if tm.opt.RevocationStore == nil {
+		return api.NewError(api.StorageNotSupportedErrorCode).WithMessage("no revocation store")
+	}
+
+	// the revocation is kept while the tokens issued until now can still be valid
+	lifetime := tm.opt.UserTokenLifetime
+	if tm.opt.RefreshTokenLifetime > lifetime {
+		lifetime = tm.opt.RefreshTokenLifetime
+	}
+	now := time.Now()
+	if err := tm.opt.RevocationStore.RevokeUserTokens(ctx, accountID, now, now.Add(time.Second*time.Duration(lifetime))); err != nil {
+		l.Error("error revoking user tokens", zap.Error(err))
+		return err
+	}
+	tm.revokedUsers.SetWithExpire(accountID, now, tm.revocationCache)
+	l.Info("audit: all tokens of user revoked", zap.String("account_id", accountID))
+	return nil
+}
+
+func (tm *tokenManager) forgeUserToken(ctx context.Context, user *api.User, tokenType string, lifetime int) (string, error) {
+	l := ctx_zap.Extract(ctx)
+	id, err := newTokenID()
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return "", err
+	}
+
+	now := time.Now()
+	token := tm.newToken()
 	claims := token.Claims.(jwt.MapClaims)
 	claims["account_id"] = user.AccountId
 	claims["display_name"] = user.DisplayName
 	claims["groups"] = user.Groups
-	claims["exp"] = time.Now().Add(time.Second * time.Duration(3600))
-	tokenString, err := token.SignedString([]byte(tm.signSecret))
+	claims["guest"] = user.Guest
+	if user.Impersonator != "" {
+		claims["impersonator"] = user.Impersonator
+	}
+	claims["jti"] = id
+	claims["iat"] = now.Unix()
+	exp := now.Add(time.Second * time.Duration(lifetime)).Unix()
+	if scope := user.Scope; scope != nil {
+		claims["scope"] = map[string]interface{}{
+			"read_only":   scope.ReadOnly,
+			"path":        scope.Path,
+			"expiration":  scope.Expiration,
+			"resource_id": scope.ResourceId,
+		}
+		if scope.Expiration != 0 && int64(scope.Expiration) < exp {
+			exp = int64(scope.Expiration)
+		}
+	}
+	claims["exp"] = exp
+	if tokenType != "" {
+		claims["type"] = tokenType
+	}
+	tokenString, err := token.SignedString(tm.signingKey)
 	if err != nil {
 		l.Error("", zap.Error(err))
 		return "", err
@@ -36,28 +368,110 @@ func (tm *tokenManager) ForgeUserToken(ctx context.Context, user *api.User) (str
 	return tokenString, nil
 }
 
-func (tm *tokenManager) DismantleUserToken(ctx context.Context, token string) (*api.User, error) {
+// dismantleUserToken returns the user of a token of the given type, the user tokens have no type.
+func (tm *tokenManager) dismantleUserToken(ctx context.Context, token, tokenType string) (*api.User, error) {
+	user, _, err := tm.dismantle(ctx, token, tokenType)
+	return user, err
+}
+
+func (tm *tokenManager) dismantle(ctx context.Context, token, tokenType string) (*api.User, jwt.MapClaims, error) {
 	l := ctx_zap.Extract(ctx)
-	rawToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		return []byte(tm.signSecret), nil
-	})
+	claims, err := tm.parse(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t, _ := claims["type"].(string); t != tokenType {
+		return nil, nil, fmt.Errorf("unexpected token type %q", t)
+	}
+
+	user, err := getUser(claims)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, nil, err
+	}
+
+	if err := tm.checkRevocation(ctx, claims, user.AccountId); err != nil {
+		return nil, nil, err
+	}
+	return user, claims, nil
+}
+
+func (tm *tokenManager) parse(ctx context.Context, token string) (jwt.MapClaims, error) {
+	l := ctx_zap.Extract(ctx)
+	rawToken, err := jwt.Parse(token, tm.getVerificationKey)
 	if err != nil {
 		l.Error("invalid token", zap.Error(err), zap.String("token", token))
 		return nil, err
 	}
 	if !rawToken.Valid {
 		l.Error("invalid token", zap.Error(err), zap.String("token", token))
-		return nil, err
+		return nil, errors.New("invalid token")
+	}
+	return rawToken.Claims.(jwt.MapClaims), nil
+}
 
+// checkRevocation fails if the token, or all the tokens of the user, were revoked.
+// The tokens forged without id nor issue time are only revoked with all the ones of the user.
+func (tm *tokenManager) checkRevocation(ctx context.Context, claims jwt.MapClaims, accountID string) error {
+	if tm.opt.RevocationStore == nil {
+		return nil
 	}
 
-	claims := rawToken.Claims.(jwt.MapClaims)
+	if id, _ := claims["jti"].(string); id != "" {
+		revoked, err := tm.isTokenRevoked(ctx, id)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return errors.New("token revoked")
+		}
+	}
+
+	revokedUntil, err := tm.getUserTokensRevocation(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	iat, _ := claims["iat"].(float64)
+	if !revokedUntil.IsZero() && int64(iat) <= revokedUntil.Unix() {
+		return errors.New("tokens of the user revoked")
+	}
+	return nil
+}
+
+// isTokenRevoked caches the answers of the store, as every request checks its token.
+func (tm *tokenManager) isTokenRevoked(ctx context.Context, id string) (bool, error) {
+	if v, err := tm.revokedTokens.Get(id); err == nil {
+		return v.(bool), nil
+	}
+	revoked, err := tm.opt.RevocationStore.IsTokenRevoked(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	tm.revokedTokens.SetWithExpire(id, revoked, tm.revocationCache)
+	return revoked, nil
+}
+
+func (tm *tokenManager) getUserTokensRevocation(ctx context.Context, accountID string) (time.Time, error) {
+	if v, err := tm.revokedUsers.Get(accountID); err == nil {
+		return v.(time.Time), nil
+	}
+	revokedUntil, err := tm.opt.RevocationStore.GetUserTokensRevocation(ctx, accountID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tm.revokedUsers.SetWithExpire(accountID, revokedUntil, tm.revocationCache)
+	return revokedUntil, nil
+}
+
+func getUser(claims jwt.MapClaims) (*api.User, error) {
 	accountID, ok := claims["account_id"].(string)
 	if !ok {
 		return nil, errors.New("account_id claim is not a string")
 	}
 
 	displayName, _ := claims["display_name"].(string) // no displayname is not an error
+	guest, _ := claims["guest"].(bool)                // tokens forged before the guests have no claim
+	impersonator, _ := claims["impersonator"].(string)
 
 	rawGroups, ok := claims["groups"].([]interface{})
 	if !ok {
@@ -67,24 +481,38 @@ func (tm *tokenManager) DismantleUserToken(ctx context.Context, token string) (*
 	for _, g := range rawGroups {
 		group, ok := g.(string)
 		if !ok {
-			err := errors.New(fmt.Sprintf("group %+v can not be casted to string", g))
-			l.Error("", zap.Error(err))
-			return nil, err
+			return nil, errors.New(fmt.Sprintf("group %+v can not be casted to string", g))
 		}
 		groups = append(groups, group)
 	}
 
 	user := &api.User{
-		AccountId:   accountID,
-		Groups:      groups,
-		DisplayName: displayName,
+		AccountId:    accountID,
+		Groups:       groups,
+		DisplayName:  displayName,
+		Guest:        guest,
+		Scope:        getScope(claims),
+		Impersonator: impersonator,
 	}
 	return user, nil
 }
 
+// getScope returns the scope of the token, or nil if the token is not restricted.
+func getScope(claims jwt.MapClaims) *api.TokenScope {
+	rawScope, ok := claims["scope"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	readOnly, _ := rawScope["read_only"].(bool)
+	path, _ := rawScope["path"].(string)
+	expiration, _ := rawScope["expiration"].(float64)
+	resourceID, _ := rawScope["resource_id"].(string)
+	return &api.TokenScope{ReadOnly: readOnly, Path: path, Expiration: uint64(expiration), ResourceId: resourceID}
+}
+
 func (tm *tokenManager) ForgePublicLinkToken(ctx context.Context, pl *api.PublicLink) (string, error) {
 	l := ctx_zap.Extract(ctx)
-	token := jwt.New(jwt.GetSigningMethod("HS256"))
+	token := tm.newToken()
 	claims := token.Claims.(jwt.MapClaims)
 	claims["token"] = pl.Token
 	claims["owner"] = pl.OwnerId
@@ -97,8 +525,17 @@ func (tm *tokenManager) ForgePublicLinkToken(ctx context.Context, pl *api.Public
 	claims["mtime"] = pl.Mtime
 	claims["item_type"] = pl.ItemType
 	claims["share_name"] = pl.Name
-	claims["exp"] = time.Now().Add(time.Second * time.Duration(3600))
-	tokenString, err := token.SignedString([]byte(tm.signSecret))
+	claims["version"] = pl.Version
+
+	// the token cannot outlive the link.
+	exp := time.Now().Add(time.Second * time.Duration(tm.opt.PublicLinkTokenLifetime))
+	if pl.Expires != 0 {
+		if linkExp := time.Unix(int64(pl.Expires), 0); linkExp.Before(exp) {
+			exp = linkExp
+		}
+	}
+	claims["exp"] = exp.Unix()
+	tokenString, err := token.SignedString(tm.signingKey)
 	if err != nil {
 		l.Error("", zap.Error(err))
 		return "", err
@@ -108,9 +545,7 @@ func (tm *tokenManager) ForgePublicLinkToken(ctx context.Context, pl *api.Public
 
 func (tm *tokenManager) DismantlePublicLinkToken(ctx context.Context, token string) (*api.PublicLink, error) {
 	l := ctx_zap.Extract(ctx)
-	rawToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		return []byte(tm.signSecret), nil
-	})
+	rawToken, err := jwt.Parse(token, tm.getVerificationKey)
 	if err != nil {
 		l.Error("invalid token", zap.Error(err), zap.String("token", token))
 		return nil, err
@@ -170,17 +605,105 @@ func (tm *tokenManager) DismantlePublicLinkToken(ctx context.Context, token stri
 	if !ok {
 		return nil, errors.New("share_name claim is not a string")
 	}
+	id, _ := claims["id"].(string)
+	expires, _ := claims["expires"].(float64)
+	version, _ := claims["version"].(string) // tokens without version are rejected by the link validator
 
 	pl := &api.PublicLink{
+		Id:        id,
 		Token:     token,
 		OwnerId:   owner,
 		ReadOnly:  readOnly,
 		Path:      path,
 		Protected: protected,
+		Expires:   uint64(expires),
 		Mtime:     uint64(mtime),
 		ItemType:  api.PublicLink_ItemType(itemType),
 		Name:      shareName,
 		DropOnly:  dropOnly,
+		Version:   version,
 	}
 	return pl, nil
 }
+
+// GetJWKS returns the public keys verifying the tokens as a JSON Web Key Set.
+func (tm *tokenManager) GetJWKS() ([]byte, error) {
+	keys := []map[string]string{}
+	for kid, key := range tm.publicKeys {
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{
+				"kid": kid,
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			method, err := getECSigningMethod(key)
+			if err != nil {
+				return nil, err
+			}
+			size := (key.Curve.Params().BitSize + 7) / 8
+			keys = append(keys, map[string]string{
+				"kid": kid,
+				"kty": "EC",
+				"use": "sig",
+				"alg": method.Alg(),
+				"crv": key.Curve.Params().Name,
+				"x":   base64.RawURLEncoding.EncodeToString(padBytes(key.X.Bytes(), size)),
+				"y":   base64.RawURLEncoding.EncodeToString(padBytes(key.Y.Bytes(), size)),
+			})
+		}
+	}
+	return json.Marshal(map[string]interface{}{"keys": keys})
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func getECSigningMethod(key *ecdsa.PublicKey) (jwt.SigningMethod, error) {
+	switch key.Curve.Params().Name {
+	case "P-256":
+		return jwt.SigningMethodES256, nil
+	case "P-384":
+		return jwt.SigningMethodES384, nil
+	case "P-521":
+		return jwt.SigningMethodES512, nil
+	default:
+		return nil, fmt.Errorf("jwt: unsupported curve %s", key.Curve.Params().Name)
+	}
+}
+
+func getPublicKey(privateKey interface{}) interface{} {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return &key.PublicKey
+	case *ecdsa.PrivateKey:
+		return &key.PublicKey
+	}
+	return nil
+}
+
+// getKeyID derives the id of a key from the hash of its public part.
+func getKeyID(publicKey interface{}) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
@@ -0,0 +1,324 @@
+package token_manager_jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/api/token_revocation_store_memory"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+var alice = &api.User{AccountId: "alice", Groups: []string{"physics"}}
+
+func newContext() context.Context {
+	return ctx_zap.ToContext(context.Background(), zap.NewNop())
+}
+
+// writeKeys writes the private and public keys of an RSA key to PEM files in dir.
+func writeKeys(t *testing.T, dir, name string) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateFile := filepath.Join(dir, name+".pem")
+	publicFile := filepath.Join(dir, name+".pub.pem")
+	writePEM(t, privateFile, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	writePEM(t, publicFile, "PUBLIC KEY", public)
+	return privateFile, publicFile
+}
+
+func writePEM(t *testing.T, file, kind string, data []byte) {
+	if err := ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: data}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "token_manager_jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func newTokenManager(t *testing.T, opt *Options) api.TokenManager {
+	tm, err := New(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestPrivateKeys(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	rsaFile, _ := writeKeys(t, dir, "rsa")
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecFile := filepath.Join(dir, "ec.pem")
+	writePEM(t, ecFile, "EC PRIVATE KEY", data)
+
+	for alg, file := range map[string]string{"RS256": rsaFile, "ES256": ecFile} {
+		tm := newTokenManager(t, &Options{PrivateKeyFile: file})
+		token, err := tm.ForgeUserToken(newContext(), alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, _ := jwt.Parse(token, nil)
+		if parsed.Method.Alg() != alg || parsed.Header["kid"] == "" {
+			t.Errorf("expected a %s token with a kid, got %s %v", alg, parsed.Method.Alg(), parsed.Header["kid"])
+		}
+		u, err := tm.DismantleUserToken(newContext(), token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.AccountId != "alice" {
+			t.Errorf("unexpected user %+v", u)
+		}
+	}
+}
+
+func TestRotatedKeysAreAccepted(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	oldPrivate, oldPublic := writeKeys(t, dir, "old")
+	newPrivate, _ := writeKeys(t, dir, "new")
+
+	old := newTokenManager(t, &Options{PrivateKeyFile: oldPrivate, KeyID: "old"})
+	token, err := old.ForgeUserToken(newContext(), alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := newTokenManager(t, &Options{PrivateKeyFile: newPrivate, KeyID: "new", PublicKeyFiles: map[string]string{"old": oldPublic}})
+	if _, err := rotated.DismantleUserToken(newContext(), token); err != nil {
+		t.Errorf("token of the previous key rejected: %v", err)
+	}
+
+	other := newTokenManager(t, &Options{PrivateKeyFile: newPrivate, KeyID: "new"})
+	if _, err := other.DismantleUserToken(newContext(), token); err == nil {
+		t.Error("token of an unknown key accepted")
+	}
+}
+
+func TestSecretTokensWithPrivateKey(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	private, _ := writeKeys(t, dir, "rsa")
+
+	secret := newTokenManager(t, &Options{Secret: "foo"})
+	token, err := secret.ForgeUserToken(newContext(), alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tm := newTokenManager(t, &Options{Secret: "foo", PrivateKeyFile: private})
+	if _, err := tm.DismantleUserToken(newContext(), token); err == nil {
+		t.Error("HS256 token accepted with a private key")
+	}
+
+	migrating := newTokenManager(t, &Options{Secret: "foo", PrivateKeyFile: private, AcceptSecretTokens: true})
+	if _, err := migrating.DismantleUserToken(newContext(), token); err != nil {
+		t.Errorf("HS256 token rejected while migrating: %v", err)
+	}
+}
+
+func TestPublicKeyIsNotASecret(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	private, public := writeKeys(t, dir, "rsa")
+	tm := newTokenManager(t, &Options{PrivateKeyFile: private})
+
+	// a token signed with HS256 using the public key, which is not secret
+	data, err := ioutil.ReadFile(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"account_id": "admin",
+		"groups":     []string{},
+		"exp":        time.Now().Add(time.Hour).Unix(),
+	})
+	signed, err := token.SignedString(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.DismantleUserToken(newContext(), signed); err == nil {
+		t.Error("token signed with the public key accepted")
+	}
+}
+
+func TestSecretOrPrivateKeyIsRequired(t *testing.T) {
+	if _, err := New(&Options{}); err == nil {
+		t.Error("expected an error without secret nor private key")
+	}
+}
+
+// countingStore counts the lookups of the revocations.
+type countingStore struct {
+	api.TokenRevocationStore
+	lookups int
+}
+
+func (s *countingStore) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	s.lookups++
+	return s.TokenRevocationStore.IsTokenRevoked(ctx, id)
+}
+
+func (s *countingStore) GetUserTokensRevocation(ctx context.Context, accountID string) (time.Time, error) {
+	s.lookups++
+	return s.TokenRevocationStore.GetUserTokensRevocation(ctx, accountID)
+}
+
+func TestRefreshTokenIsRedeemedOnce(t *testing.T) {
+	tm := newTokenManager(t, &Options{Secret: "foo", RevocationStore: token_revocation_store_memory.New(100)})
+	token, err := tm.ForgeRefreshToken(newContext(), alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	redeemed := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tm.RedeemRefreshToken(newContext(), token)
+			if err == nil {
+				mu.Lock()
+				redeemed++
+				mu.Unlock()
+			} else if !api.IsErrorCode(err, api.TokenInvalidErrorCode) {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if redeemed != 1 {
+		t.Errorf("expected the refresh token to be redeemed once, redeemed %d times", redeemed)
+	}
+}
+
+func TestUserTokenIsNotARefreshToken(t *testing.T) {
+	tm := newTokenManager(t, &Options{Secret: "foo", RevocationStore: token_revocation_store_memory.New(100)})
+	token, err := tm.ForgeUserToken(newContext(), alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.RedeemRefreshToken(newContext(), token); !api.IsErrorCode(err, api.TokenInvalidErrorCode) {
+		t.Errorf("expected an invalid token, got %v", err)
+	}
+}
+
+func TestRevocationsAreCached(t *testing.T) {
+	store := &countingStore{TokenRevocationStore: token_revocation_store_memory.New(100)}
+	tm := newTokenManager(t, &Options{Secret: "foo", RevocationStore: store})
+	token, err := tm.ForgeUserToken(newContext(), alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := tm.DismantleUserToken(newContext(), token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.lookups != 2 {
+		t.Errorf("expected the token and the user to be looked up once, got %d lookups", store.lookups)
+	}
+
+	// the revocations of the instance are enforced without waiting for the cache
+	if err := tm.RevokeUserToken(newContext(), token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.DismantleUserToken(newContext(), token); err == nil {
+		t.Error("revoked token accepted")
+	}
+}
+
+func TestScopedTokenLifetime(t *testing.T) {
+	tm := newTokenManager(t, &Options{Secret: "foo", ScopedTokenLifetime: 600, MaxScopedTokenLifetime: 3600})
+	scope := &api.TokenScope{Path: "/home/alice/notes.md"}
+
+	for requested, expected := range map[int]time.Duration{0: 600 * time.Second, 1800: 1800 * time.Second, 86400: time.Hour} {
+		token, err := tm.ForgeScopedToken(newContext(), alice, scope, requested)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, _ := jwt.Parse(token, nil)
+		exp := time.Unix(int64(parsed.Claims.(jwt.MapClaims)["exp"].(float64)), 0)
+		if lifetime := time.Until(exp); lifetime > expected || lifetime < expected-time.Minute {
+			t.Errorf("expected a lifetime of %s asking for %d seconds, got %s", expected, requested, lifetime)
+		}
+
+		u, err := tm.DismantleUserToken(newContext(), token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Scope == nil || u.Scope.Path != scope.Path {
+			t.Errorf("expected the token to be restricted to %s, got %+v", scope.Path, u.Scope)
+		}
+	}
+}
+
+func TestImpersonationCannotForgeScopedTokens(t *testing.T) {
+	tm := newTokenManager(t, &Options{Secret: "foo"})
+	impersonated := &api.User{AccountId: "alice", Impersonator: "admin"}
+	_, err := tm.ForgeScopedToken(newContext(), impersonated, &api.TokenScope{Path: "/home/alice/notes.md"}, 43200)
+	if !api.IsErrorCode(err, api.PermissionDeniedErrorCode) {
+		t.Errorf("expected the impersonation to be denied, got %v", err)
+	}
+}
+
+func TestAppPasswordScopeIsKept(t *testing.T) {
+	tm := newTokenManager(t, &Options{Secret: "foo"})
+	expiration := uint64(time.Now().Add(time.Hour).Unix())
+	user := &api.User{AccountId: "alice", Groups: []string{}, Scope: &api.TokenScope{ReadOnly: true, Path: "/home/alice/ci", Expiration: expiration}}
+
+	token, err := tm.ForgeUserToken(newContext(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := tm.DismantleUserToken(newContext(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scope == nil || !u.Scope.ReadOnly || u.Scope.Path != "/home/alice/ci" || u.Scope.Expiration != expiration {
+		t.Errorf("expected the scope of the app password, got %+v", u.Scope)
+	}
+
+	// the token does not outlive the app password
+	user.Scope.Expiration = uint64(time.Now().Add(-time.Minute).Unix())
+	token, err = tm.ForgeUserToken(newContext(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.DismantleUserToken(newContext(), token); err == nil {
+		t.Error("expected the token of the expired app password to be rejected")
+	}
+}
@@ -0,0 +1,84 @@
+package token_revocation_store_db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+
+	"database/sql"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// New returns a store that keeps the revoked tokens in MySQL tables,
+// so they are shared by all the instances of a deployment.
+// The expired entries are removed when new ones are added.
+//
+// The database is checked before returning, as every token is validated against
+// it and an unreachable store would reject all of them.
+//
+// The tables are created by the migration migrations/mysql/0006_cbox_revoked_tokens.up.sql.
+func New(dbUsername, dbPassword, dbHost string, dbPort int, dbName string) (api.TokenRevocationStore, error) {
+	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", dbUsername, dbPassword, dbHost, dbPort, dbName))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("token_revocation_store_db: database unreachable: %v", err)
+	}
+	return &store{db: db}, nil
+}
+
+type store struct {
+	db *sql.DB
+}
+
+// RevokeToken relies on the primary key, the insert of a token already revoked is ignored.
+func (s *store) RevokeToken(ctx context.Context, id string, expiration time.Time) (bool, error) {
+	now := time.Now().Unix()
+	if _, err := s.db.Exec("delete from cbox_revoked_tokens where expiration<?", now); err != nil {
+		return false, err
+	}
+	res, err := s.db.Exec("insert ignore into cbox_revoked_tokens (token_id, expiration) values (?, ?)", id, expiration.Unix())
+	if err != nil {
+		return false, err
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return inserted == 1, nil
+}
+
+func (s *store) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	var count int
+	query := "select count(*) from cbox_revoked_tokens where token_id=? and expiration>=?"
+	if err := s.db.QueryRow(query, id, time.Now().Unix()).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *store) RevokeUserTokens(ctx context.Context, accountID string, before, expiration time.Time) error {
+	now := time.Now().Unix()
+	if _, err := s.db.Exec("delete from cbox_revoked_user_tokens where expiration<?", now); err != nil {
+		return err
+	}
+	stmtString := "insert into cbox_revoked_user_tokens (account_id, revoked_until, expiration) values (?, ?, ?) on duplicate key update revoked_until=values(revoked_until), expiration=values(expiration)"
+	_, err := s.db.Exec(stmtString, accountID, before.Unix(), expiration.Unix())
+	return err
+}
+
+func (s *store) GetUserTokensRevocation(ctx context.Context, accountID string) (time.Time, error) {
+	var revokedUntil int64
+	query := "select revoked_until from cbox_revoked_user_tokens where account_id=? and expiration>=?"
+	if err := s.db.QueryRow(query, accountID, time.Now().Unix()).Scan(&revokedUntil); err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return time.Unix(revokedUntil, 0), nil
+}
@@ -0,0 +1,62 @@
+package token_revocation_store_memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/cernbox/revaold/api"
+)
+
+// New returns a store that keeps the revoked tokens in memory.
+// The revocations are not shared with other instances and are lost on restart,
+// so it is only suited for deployments with a single instance.
+// At most size tokens and size users are kept, the oldest ones are dropped first.
+func New(size int) api.TokenRevocationStore {
+	return &store{
+		tokens: gcache.New(size).LRU().Build(),
+		users:  gcache.New(size).LRU().Build(),
+	}
+}
+
+type store struct {
+	mu     sync.Mutex
+	tokens gcache.Cache
+	users  gcache.Cache
+}
+
+func (s *store) RevokeToken(ctx context.Context, id string, expiration time.Time) (bool, error) {
+	ttl := time.Until(expiration)
+	if ttl <= 0 {
+		return false, nil // already expired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.tokens.Get(id); err == nil {
+		return false, nil
+	}
+	return true, s.tokens.SetWithExpire(id, true, ttl)
+}
+
+func (s *store) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	_, err := s.tokens.Get(id)
+	return err == nil, nil
+}
+
+func (s *store) RevokeUserTokens(ctx context.Context, accountID string, before, expiration time.Time) error {
+	ttl := time.Until(expiration)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.users.SetWithExpire(accountID, before, ttl)
+}
+
+func (s *store) GetUserTokensRevocation(ctx context.Context, accountID string) (time.Time, error) {
+	v, err := s.users.Get(accountID)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return v.(time.Time), nil
+}
@@ -0,0 +1,154 @@
+package user_directory_cboxgroupd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/cernbox/revaold/api"
+	"go.uber.org/zap"
+)
+
+// The account types of the groups in cboxgroupd, the rest of the
+// types, like primary, secondary or service, are users.
+const (
+	accountTypeEGroup    = "egroup"
+	accountTypeUnixGroup = "unixgroup"
+)
+
+type Options struct {
+	Logger *zap.Logger
+
+	CBOXGroupDaemonURI    string
+	CBOXGroupDaemonSecret string
+}
+
+func (opt *Options) init() {
+	if opt.Logger == nil {
+		opt.Logger, _ = zap.NewProduction()
+	}
+	if opt.CBOXGroupDaemonURI == "" {
+		opt.CBOXGroupDaemonURI = "http://localhost:2002"
+	}
+}
+
+// New returns a user directory that looks up the users, e-groups and unix groups
+// in the CERNBox Group Daemon. The search matching is done by cboxgroupd and
+// the users have no avatar.
+func New(opt *Options) api.UserDirectory {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	tr := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 100,
+		TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+	}
+	return &userDirectory{opt: opt, client: &http.Client{Transport: tr}}
+}
+
+type userDirectory struct {
+	opt    *Options
+	client *http.Client
+}
+
+type searchEntry struct {
+	DN          string `json:"dn"`
+	CN          string `json:"cn"`
+	AccountType string `json:"account_type"`
+	DisplayName string `json:"display_name"`
+	Mail        string `json:"mail"`
+}
+
+func (ud *userDirectory) GetUser(ctx context.Context, accountID string) (*api.DirectoryUser, error) {
+	entries, err := ud.search(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.CN == accountID && getEntryType(e.AccountType) == api.DirectoryEntry_USER {
+			return &api.DirectoryUser{AccountId: e.CN, DisplayName: e.DisplayName, Mail: e.Mail}, nil
+		}
+	}
+	return nil, api.NewError(api.UserNotFoundErrorCode)
+}
+
+func (ud *userDirectory) GetUserAvatar(ctx context.Context, accountID string) ([]byte, string, error) {
+	return nil, "", api.NewError(api.StorageNotSupportedErrorCode).WithMessage("cboxgroupd has no avatars")
+}
+
+func (ud *userDirectory) Search(ctx context.Context, prefix string) ([]*api.DirectoryEntry, error) {
+	entries := []*api.DirectoryEntry{}
+	if prefix == "" {
+		return entries, nil
+	}
+
+	searchEntries, err := ud.search(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range searchEntries {
+		entries = append(entries, &api.DirectoryEntry{Type: getEntryType(e.AccountType), Id: e.CN, DisplayName: e.DisplayName, Mail: e.Mail})
+	}
+	return entries, nil
+}
+
+func (ud *userDirectory) search(ctx context.Context, term string) ([]*searchEntry, error) {
+	entries := []*searchEntry{}
+	if err := ud.get(ctx, "/api/v1/search/"+url.PathEscape(term), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// get decodes the JSON response of cboxgroupd for the path into v.
+func (ud *userDirectory) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequest("GET", ud.opt.CBOXGroupDaemonURI+path, nil)
+	if err != nil {
+		ud.opt.Logger.Error("", zap.Error(err))
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", ud.opt.CBOXGroupDaemonSecret))
+	res, err := ud.client.Do(req)
+	if err != nil {
+		ud.opt.Logger.Error("", zap.Error(err))
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		err := fmt.Errorf("error calling cboxgroupd %s", path)
+		ud.opt.Logger.Error("", zap.Int("http_code", res.StatusCode), zap.Error(err))
+		return err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		ud.opt.Logger.Error("", zap.Error(err))
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		ud.opt.Logger.Error("", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// getEntryType returns the type of the account.
+func getEntryType(accountType string) api.DirectoryEntry_EntryType {
+	switch accountType {
+	case accountTypeEGroup:
+		return api.DirectoryEntry_GROUP
+	case accountTypeUnixGroup:
+		return api.DirectoryEntry_UNIX_GROUP
+	default:
+		return api.DirectoryEntry_USER
+	}
+}
@@ -0,0 +1,210 @@
+package user_directory_ldap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/api/ldapclient"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+	"gopkg.in/ldap.v2"
+)
+
+type Options struct {
+	ldapclient.Options
+
+	// BaseDN is where the users are searched. UserFilter finds a user with the escaped
+	// account id replacing %s and UserSearchFilter the users matching a prefix, with the
+	// escaped prefix replacing every %s.
+	BaseDN           string
+	UserFilter       string
+	UserSearchFilter string
+
+	AccountIDAttribute    string
+	DisplayNameAttributes []string
+	MailAttribute         string
+	// AvatarAttribute holds the picture of the users, its type is detected from the content.
+	AvatarAttribute string
+
+	// GroupBaseDN is where the groups are searched. GroupSearchFilter finds the groups
+	// matching a prefix, with the escaped prefix replacing every %s.
+	GroupBaseDN        string
+	GroupSearchFilter  string
+	GroupNameAttribute string
+
+	// SizeLimit is the maximum number of users, and of groups, returned by a search.
+	SizeLimit int
+}
+
+func (opt *Options) init() {
+	if opt.UserFilter == "" {
+		opt.UserFilter = "(samaccountname=%s)"
+	}
+	if opt.UserSearchFilter == "" {
+		opt.UserSearchFilter = "(&(objectClass=user)(|(samaccountname=%s*)(displayName=%s*)(mail=%s*)))"
+	}
+	if opt.AccountIDAttribute == "" {
+		opt.AccountIDAttribute = "samaccountname"
+	}
+	if len(opt.DisplayNameAttributes) == 0 {
+		opt.DisplayNameAttributes = []string{"displayName", "cn"}
+	}
+	if opt.MailAttribute == "" {
+		opt.MailAttribute = "mail"
+	}
+	if opt.AvatarAttribute == "" {
+		opt.AvatarAttribute = "thumbnailPhoto"
+	}
+	if opt.GroupBaseDN == "" {
+		opt.GroupBaseDN = opt.BaseDN
+	}
+	if opt.GroupSearchFilter == "" {
+		opt.GroupSearchFilter = "(&(objectClass=group)(cn=%s*))"
+	}
+	if opt.GroupNameAttribute == "" {
+		opt.GroupNameAttribute = "cn"
+	}
+	if opt.SizeLimit <= 0 {
+		opt.SizeLimit = 100
+	}
+}
+
+// New returns a user directory that searches the users and groups in the LDAP server,
+// over connections taken from a bounded pool.
+func New(opt *Options) (api.UserDirectory, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	client, err := ldapclient.New(&opt.Options)
+	if err != nil {
+		return nil, err
+	}
+	opt.init()
+	return &userDirectory{opt: opt, client: client}, nil
+}
+
+type userDirectory struct {
+	opt    *Options
+	client *ldapclient.Client
+}
+
+func (ud *userDirectory) GetUser(ctx context.Context, accountID string) (*api.DirectoryUser, error) {
+	attributes := append([]string{ud.opt.AccountIDAttribute, ud.opt.MailAttribute}, ud.opt.DisplayNameAttributes...)
+	entry, err := ud.getUserEntry(ctx, accountID, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return ud.getDirectoryUser(entry), nil
+}
+
+func (ud *userDirectory) GetUserAvatar(ctx context.Context, accountID string) ([]byte, string, error) {
+	entry, err := ud.getUserEntry(ctx, accountID, []string{ud.opt.AvatarAttribute})
+	if err != nil {
+		return nil, "", err
+	}
+	avatar := entry.GetRawAttributeValue(ud.opt.AvatarAttribute)
+	if len(avatar) == 0 {
+		return nil, "", api.NewError(api.UserNotFoundErrorCode).WithMessage("the user has no avatar")
+	}
+	return avatar, http.DetectContentType(avatar), nil
+}
+
+func (ud *userDirectory) Search(ctx context.Context, prefix string) ([]*api.DirectoryEntry, error) {
+	entries := []*api.DirectoryEntry{}
+	if prefix == "" {
+		return entries, nil
+	}
+	escaped := ldap.EscapeFilter(prefix)
+
+	attributes := append([]string{ud.opt.AccountIDAttribute, ud.opt.MailAttribute}, ud.opt.DisplayNameAttributes...)
+	users, err := ud.search(ctx, ud.opt.BaseDN, strings.Replace(ud.opt.UserSearchFilter, "%s", escaped, -1), attributes)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range users {
+		u := ud.getDirectoryUser(e)
+		entries = append(entries, &api.DirectoryEntry{Type: api.DirectoryEntry_USER, Id: u.AccountId, DisplayName: u.DisplayName, Mail: u.Mail})
+	}
+
+	groups, err := ud.search(ctx, ud.opt.GroupBaseDN, strings.Replace(ud.opt.GroupSearchFilter, "%s", escaped, -1), []string{ud.opt.GroupNameAttribute})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range groups {
+		name := e.GetAttributeValue(ud.opt.GroupNameAttribute)
+		entries = append(entries, &api.DirectoryEntry{Type: api.DirectoryEntry_GROUP, Id: name, DisplayName: name})
+	}
+	return entries, nil
+}
+
+// getUserEntry returns the entry of the user with the attributes, or a
+// UserNotFoundErrorCode error if there is not exactly one.
+func (ud *userDirectory) getUserEntry(ctx context.Context, accountID string, attributes []string) (*ldap.Entry, error) {
+	l := ctx_zap.Extract(ctx)
+	c, err := ud.client.GetConn(ctx)
+	if err != nil {
+		l.Error("error getting ldap connection", zap.Error(err))
+		return nil, err
+	}
+	searchRequest := ldap.NewSearchRequest(
+		ud.opt.BaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, ud.opt.RequestTimeout, false,
+		fmt.Sprintf(ud.opt.UserFilter, ldap.EscapeFilter(accountID)),
+		attributes,
+		nil,
+	)
+	sr, err := c.Search(searchRequest)
+	ud.client.PutConn(c, err)
+	if err != nil {
+		l.Error("error searching user in ldap", zap.Error(err), zap.String("account_id", accountID))
+		return nil, err
+	}
+	if len(sr.Entries) != 1 {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+	return sr.Entries[0], nil
+}
+
+// search returns up to the size limit entries, the ones found
+// when the limit of the server is exceeded are kept.
+func (ud *userDirectory) search(ctx context.Context, baseDN, filter string, attributes []string) ([]*ldap.Entry, error) {
+	l := ctx_zap.Extract(ctx)
+	c, err := ud.client.GetConn(ctx)
+	if err != nil {
+		l.Error("error getting ldap connection", zap.Error(err))
+		return nil, err
+	}
+	searchRequest := ldap.NewSearchRequest(
+		baseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, ud.opt.SizeLimit, ud.opt.RequestTimeout, false,
+		filter,
+		attributes,
+		nil,
+	)
+	sr, err := c.Search(searchRequest)
+	if err != nil && ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) && sr != nil {
+		err = nil
+	}
+	ud.client.PutConn(c, err)
+	if err != nil {
+		l.Error("error searching ldap", zap.Error(err), zap.String("filter", filter))
+		return nil, err
+	}
+	return sr.Entries, nil
+}
+
+func (ud *userDirectory) getDirectoryUser(entry *ldap.Entry) *api.DirectoryUser {
+	u := &api.DirectoryUser{
+		AccountId: entry.GetAttributeValue(ud.opt.AccountIDAttribute),
+		Mail:      entry.GetAttributeValue(ud.opt.MailAttribute),
+	}
+	for _, attr := range ud.opt.DisplayNameAttributes {
+		if u.DisplayName = entry.GetAttributeValue(attr); u.DisplayName != "" {
+			break
+		}
+	}
+	return u
+}
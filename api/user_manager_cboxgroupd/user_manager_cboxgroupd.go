@@ -9,6 +9,8 @@ import (
 	"github.com/cernbox/revaold/api"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"os/user"
 
 	"go.uber.org/zap"
 )
@@ -105,6 +107,73 @@ func (um *userManager) GetUserGroups(ctx context.Context, username string) ([]st
 	return groups, nil
 }
 
+// GetUserUnixGroups returns the unix groups of the user as resolved by the host,
+// the users unknown to it are not members of any.
+func (um *userManager) GetUserUnixGroups(ctx context.Context, username string) ([]string, error) {
+	groups := []string{}
+	u, err := user.Lookup(username)
+	if err != nil {
+		if _, ok := err.(user.UnknownUserError); ok {
+			return groups, nil
+		}
+		um.logger.Error("", zap.Error(err))
+		return groups, err
+	}
+	gids, err := u.GroupIds()
+	if err != nil {
+		um.logger.Error("", zap.Error(err))
+		return groups, err
+	}
+	for _, gid := range gids {
+		g, err := user.LookupGroupId(gid)
+		if err != nil {
+			um.logger.Warn("unix group not found", zap.String("gid", gid), zap.Error(err))
+			continue
+		}
+		groups = append(groups, g.Name)
+	}
+	return groups, nil
+}
+
+// GetGroupMembers returns the account ids of the members of the group,
+// with the e-groups expanded by cboxgroupd.
+func (um *userManager) GetGroupMembers(ctx context.Context, group string) ([]string, error) {
+	users := []string{}
+	client := &http.Client{Transport: um.tr}
+	endpoint := fmt.Sprintf("%s/api/v1/membership/usersingroup/%s", um.cboxGroupDaemonURI, url.PathEscape(group))
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		um.logger.Error("", zap.Error(err))
+		return users, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", um.cboxGroupDaemonSecret))
+	res, err := client.Do(req)
+	if err != nil {
+		um.logger.Error("", zap.Error(err))
+		return users, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		err := errors.New("error calling cboxgroupd membership")
+		um.logger.Error("", zap.Int("http_code", res.StatusCode), zap.Error(err))
+		return users, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		um.logger.Error("", zap.Error(err))
+		return users, err
+	}
+
+	err = json.Unmarshal(body, &users)
+	if err != nil {
+		um.logger.Error("", zap.Error(err))
+		return users, err
+	}
+	return users, nil
+}
+
 type groupResponse []string
 
 /*
@@ -0,0 +1,313 @@
+package user_manager_file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v2"
+)
+
+// dummyHash is compared with the passwords of the unknown users, so they
+// take as long to be rejected as the wrong passwords of the known ones.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy"), bcrypt.DefaultCost)
+
+type Options struct {
+	Logger *zap.Logger
+
+	// File is the JSON file, or YAML if it ends in .yaml or .yml, with the users.
+	File string
+
+	// ReloadInterval is the time in seconds between the checks of the file for changes.
+	ReloadInterval int
+}
+
+func (opt *Options) init() {
+	if opt.Logger == nil {
+		opt.Logger, _ = zap.NewProduction()
+	}
+	if opt.ReloadInterval <= 0 {
+		opt.ReloadInterval = 10
+	}
+}
+
+// New returns a user manager that reads the users and their groups from a file,
+// for the installations without a user directory. It is also an api.AuthManager
+// checking the passwords of the users against their bcrypt hashes, which can be
+// generated with htpasswd -nbB <username> <password>, and an api.UserDirectory
+// with their display names and e-mails.
+//
+// The file is reloaded when it changes, if the new content is invalid the
+// previous one is kept. The password of einstein is relativity:
+//
+//	users:
+//	  - username: einstein
+//	    password: $2a$10$nAM75IKFDV/j4frEVVD2nept0K/9n5G4e79Mid95TECRb1Lds6cOC
+//	    display_name: Albert Einstein
+//	    mail: einstein@example.org
+//	    groups: [physicists, sailing-lovers]
+//	    unix_groups: [cern]
+func New(opt *Options) (api.UserManager, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	um := &userManager{opt: opt}
+	if err := um.load(); err != nil {
+		return nil, err
+	}
+	return um, nil
+}
+
+type userManager struct {
+	opt *Options
+
+	mu        sync.RWMutex
+	users     map[string]*user
+	modTime   time.Time
+	size      int64
+	lastCheck time.Time
+}
+
+type usersFile struct {
+	Users []*user `json:"users" yaml:"users"`
+}
+
+type user struct {
+	Username    string   `json:"username" yaml:"username"`
+	Password    string   `json:"password" yaml:"password"`
+	DisplayName string   `json:"display_name" yaml:"display_name"`
+	Mail        string   `json:"mail" yaml:"mail"`
+	Groups      []string `json:"groups" yaml:"groups"`
+	UnixGroups  []string `json:"unix_groups" yaml:"unix_groups"`
+}
+
+func (um *userManager) Authenticate(ctx context.Context, clientID, clientSecret string) (*api.User, error) {
+	u, ok := um.getUser(clientID)
+	if !ok {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(clientSecret))
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(clientSecret)); err != nil {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+
+	return &api.User{AccountId: u.Username, DisplayName: getDisplayName(u), Groups: getGroups(u)}, nil
+}
+
+func (um *userManager) GetUser(ctx context.Context, accountID string) (*api.DirectoryUser, error) {
+	u, ok := um.getUser(accountID)
+	if !ok {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+	return &api.DirectoryUser{AccountId: u.Username, DisplayName: getDisplayName(u), Mail: u.Mail}, nil
+}
+
+func (um *userManager) GetUserAvatar(ctx context.Context, accountID string) ([]byte, string, error) {
+	return nil, "", api.NewError(api.UserNotFoundErrorCode).WithMessage("the users file has no avatars")
+}
+
+// Search returns the users whose username, display name or mail, and the groups
+// and unix groups whose name, start with the prefix, ignoring the case.
+func (um *userManager) Search(ctx context.Context, prefix string) ([]*api.DirectoryEntry, error) {
+	entries := []*api.DirectoryEntry{}
+	if prefix == "" {
+		return entries, nil
+	}
+	prefix = strings.ToLower(prefix)
+	matches := func(values ...string) bool {
+		for _, v := range values {
+			if strings.HasPrefix(strings.ToLower(v), prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	um.reload()
+	um.mu.RLock()
+	defer um.mu.RUnlock()
+
+	// a group and a unix group can have the same name
+	type group struct {
+		t    api.DirectoryEntry_EntryType
+		name string
+	}
+	groups := map[group]bool{}
+	for _, u := range um.users {
+		if matches(u.Username, u.DisplayName, u.Mail) {
+			entries = append(entries, &api.DirectoryEntry{Type: api.DirectoryEntry_USER, Id: u.Username, DisplayName: getDisplayName(u), Mail: u.Mail})
+		}
+		for _, g := range u.Groups {
+			if matches(g) {
+				groups[group{api.DirectoryEntry_GROUP, g}] = true
+			}
+		}
+		for _, g := range u.UnixGroups {
+			if matches(g) {
+				groups[group{api.DirectoryEntry_UNIX_GROUP, g}] = true
+			}
+		}
+	}
+	for g := range groups {
+		entries = append(entries, &api.DirectoryEntry{Type: g.t, Id: g.name, DisplayName: g.name})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+		return entries[i].Id < entries[j].Id
+	})
+	return entries, nil
+}
+
+func (um *userManager) GetUserGroups(ctx context.Context, username string) ([]string, error) {
+	u, ok := um.getUser(username)
+	if !ok {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+	return getGroups(u), nil
+}
+
+func (um *userManager) GetUserUnixGroups(ctx context.Context, username string) ([]string, error) {
+	u, ok := um.getUser(username)
+	if !ok {
+		return nil, api.NewError(api.UserNotFoundErrorCode)
+	}
+	groups := []string{}
+	return append(groups, u.UnixGroups...), nil
+}
+
+func (um *userManager) IsInGroup(ctx context.Context, username, group string) (bool, error) {
+	groups, err := um.GetUserGroups(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	for _, g := range groups {
+		if g == group {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (um *userManager) GetGroupMembers(ctx context.Context, group string) ([]string, error) {
+	um.reload()
+	um.mu.RLock()
+	defer um.mu.RUnlock()
+
+	members := []string{}
+	for _, u := range um.users {
+		for _, g := range u.Groups {
+			if g == group {
+				members = append(members, u.Username)
+				break
+			}
+		}
+	}
+	return members, nil
+}
+
+func (um *userManager) getUser(username string) (*user, bool) {
+	um.reload()
+	um.mu.RLock()
+	defer um.mu.RUnlock()
+	u, ok := um.users[username]
+	return u, ok
+}
+
+// reload loads the file again if it changed since it was loaded,
+// checking it at most once every reload interval.
+func (um *userManager) reload() {
+	um.mu.Lock()
+	if time.Since(um.lastCheck) < time.Duration(um.opt.ReloadInterval)*time.Second {
+		um.mu.Unlock()
+		return
+	}
+	um.lastCheck = time.Now()
+	um.mu.Unlock()
+
+	fi, err := os.Stat(um.opt.File)
+	if err != nil {
+		um.opt.Logger.Error("error checking users file", zap.Error(err), zap.String("file", um.opt.File))
+		return
+	}
+	um.mu.RLock()
+	changed := !fi.ModTime().Equal(um.modTime) || fi.Size() != um.size
+	um.mu.RUnlock()
+	if !changed {
+		return
+	}
+
+	if err := um.load(); err != nil {
+		um.opt.Logger.Error("error reloading users file, keeping the previous users", zap.Error(err), zap.String("file", um.opt.File))
+		return
+	}
+	um.opt.Logger.Info("users file reloaded", zap.String("file", um.opt.File))
+}
+
+func (um *userManager) load() error {
+	fi, err := os.Stat(um.opt.File)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(um.opt.File)
+	if err != nil {
+		return err
+	}
+
+	f := &usersFile{}
+	switch filepath.Ext(um.opt.File) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, f)
+	default:
+		err = json.Unmarshal(data, f)
+	}
+	if err != nil {
+		return err
+	}
+
+	users := map[string]*user{}
+	for _, u := range f.Users {
+		if u.Username == "" {
+			return fmt.Errorf("user_manager_file: user without username in %s", um.opt.File)
+		}
+		if _, ok := users[u.Username]; ok {
+			return fmt.Errorf("user_manager_file: duplicated user %s in %s", u.Username, um.opt.File)
+		}
+		users[u.Username] = u
+	}
+
+	um.mu.Lock()
+	defer um.mu.Unlock()
+	um.users = users
+	um.modTime = fi.ModTime()
+	um.size = fi.Size()
+	um.lastCheck = time.Now()
+	return nil
+}
+
+func getDisplayName(u *user) string {
+	if u.DisplayName == "" {
+		return u.Username
+	}
+	return u.DisplayName
+}
+
+func getGroups(u *user) []string {
+	groups := make([]string, len(u.Groups))
+	copy(groups, u.Groups)
+	return groups
+}
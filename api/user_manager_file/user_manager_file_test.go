@@ -0,0 +1,149 @@
+package user_manager_file
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"go.uber.org/zap"
+)
+
+// the password of einstein is relativity
+const users = `
+users:
+  - username: einstein
+    password: $2a$10$nAM75IKFDV/j4frEVVD2nept0K/9n5G4e79Mid95TECRb1Lds6cOC
+    display_name: Albert Einstein
+    mail: einstein@example.org
+    groups: [physicists, sailing-lovers]
+    unix_groups: [cern]
+  - username: marie
+    mail: marie@example.org
+    groups: [physicists]
+    unix_groups: [physicists]
+`
+
+func newUserManager(t *testing.T, content string) (*userManager, string) {
+	dir, err := ioutil.TempDir("", "user_manager_file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "users.yaml")
+	if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	um, err := New(&Options{Logger: zap.NewNop(), File: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return um.(*userManager), dir
+}
+
+func TestAuthenticate(t *testing.T) {
+	um, dir := newUserManager(t, users)
+	defer os.RemoveAll(dir)
+
+	u, err := um.Authenticate(context.Background(), "einstein", "relativity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.AccountId != "einstein" || u.DisplayName != "Albert Einstein" || len(u.Groups) != 2 {
+		t.Errorf("unexpected user %+v", u)
+	}
+	if _, err := um.Authenticate(context.Background(), "einstein", "gravity"); !api.IsErrorCode(err, api.UserNotFoundErrorCode) {
+		t.Errorf("expected a wrong password to be rejected, got %v", err)
+	}
+	if _, err := um.Authenticate(context.Background(), "bohr", "relativity"); !api.IsErrorCode(err, api.UserNotFoundErrorCode) {
+		t.Errorf("expected an unknown user to be rejected, got %v", err)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	um, dir := newUserManager(t, users)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+
+	unixGroups, err := um.GetUserUnixGroups(ctx, "einstein")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unixGroups) != 1 || unixGroups[0] != "cern" {
+		t.Errorf("unexpected unix groups %v", unixGroups)
+	}
+	if ok, err := um.IsInGroup(ctx, "marie", "sailing-lovers"); err != nil || ok {
+		t.Errorf("marie is not a sailing lover: %t %v", ok, err)
+	}
+	members, err := um.GetGroupMembers(ctx, "physicists")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 {
+		t.Errorf("expected two physicists, got %v", members)
+	}
+	if _, err := um.GetUserGroups(ctx, "bohr"); !api.IsErrorCode(err, api.UserNotFoundErrorCode) {
+		t.Errorf("expected an unknown user, got %v", err)
+	}
+}
+
+func TestDirectory(t *testing.T) {
+	um, dir := newUserManager(t, users)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+
+	u, err := um.GetUser(ctx, "marie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.DisplayName != "marie" || u.Mail != "marie@example.org" {
+		t.Errorf("unexpected user %+v", u)
+	}
+	if _, err := um.GetUser(ctx, "bohr"); !api.IsErrorCode(err, api.UserNotFoundErrorCode) {
+		t.Errorf("expected an unknown user, got %v", err)
+	}
+
+	entries, err := um.Search(ctx, "Al")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Id != "einstein" || entries[0].Mail != "einstein@example.org" {
+		t.Errorf("expected einstein by display name, got %v", entries)
+	}
+
+	entries, err = um.Search(ctx, "phys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Type != api.DirectoryEntry_GROUP || entries[1].Type != api.DirectoryEntry_UNIX_GROUP {
+		t.Errorf("expected the group and the unix group physicists, got %v", entries)
+	}
+}
+
+func TestReload(t *testing.T) {
+	um, dir := newUserManager(t, users)
+	defer os.RemoveAll(dir)
+	um.opt.ReloadInterval = 1
+
+	// an invalid file keeps the previous users
+	if err := ioutil.WriteFile(um.opt.File, []byte("users: [[["), 0600); err != nil {
+		t.Fatal(err)
+	}
+	um.lastCheck = time.Time{}
+	if _, err := um.GetUser(context.Background(), "einstein"); err != nil {
+		t.Errorf("previous users not kept: %v", err)
+	}
+
+	if err := ioutil.WriteFile(um.opt.File, []byte("users:\n  - username: bohr\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	um.lastCheck = time.Time{}
+	if _, err := um.GetUser(context.Background(), "bohr"); err != nil {
+		t.Errorf("file not reloaded: %v", err)
+	}
+	if _, err := um.GetUser(context.Background(), "einstein"); err == nil {
+		t.Error("removed user still found")
+	}
+}
@@ -0,0 +1 @@
+drop index oc_share_token_unique on oc_share;
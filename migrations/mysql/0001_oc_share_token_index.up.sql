@@ -0,0 +1,6 @@
+-- The tokens of the links and remote shares identify them, the index makes the
+-- concurrent inserts of the same token fail. The shares to users and groups have
+-- no token and MySQL allows any number of NULL values in a unique index.
+-- Duplicated tokens, if any, have to be fixed before:
+--   select token, count(*) from oc_share where token is not null group by token having count(*) > 1;
+create unique index oc_share_token_unique on oc_share (token);
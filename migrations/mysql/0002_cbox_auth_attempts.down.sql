@@ -0,0 +1 @@
+drop table cbox_auth_attempts;
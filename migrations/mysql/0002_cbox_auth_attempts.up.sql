@@ -0,0 +1,6 @@
+-- The consecutive failed password attempts on the public links, by link and by client IP.
+create table cbox_auth_attempts (
+	attempt_key varchar(255) not null primary key,
+	failed int not null,
+	last_attempt bigint not null
+);
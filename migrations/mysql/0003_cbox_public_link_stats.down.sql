@@ -0,0 +1,2 @@
+drop table cbox_public_link_visitors;
+drop table cbox_public_link_stats;
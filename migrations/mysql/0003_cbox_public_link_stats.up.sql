@@ -0,0 +1,23 @@
+-- The usage limits and counters of the public links, oc_share belongs to the owncloud
+-- schema. The links without a row have no limits and nothing counted yet.
+create table cbox_public_link_stats (
+	share_id int not null primary key,
+	max_downloads bigint not null default 0,
+	max_visitors bigint not null default 0,
+	downloads bigint not null default 0,
+	uploads bigint not null default 0,
+	last_access bigint not null default 0,
+	max_file_size bigint not null default 0,
+	max_total_size bigint not null default 0,
+	allowed_types varchar(1024) not null default '',
+	uploader_folders tinyint(1) not null default 0,
+	upload_digest tinyint(1) not null default 0,
+	uploaded_bytes bigint not null default 0
+);
+
+-- The visitors of the links, told apart by their client IP.
+create table cbox_public_link_visitors (
+	share_id int not null,
+	visitor varchar(255) not null,
+	primary key (share_id, visitor)
+);
@@ -0,0 +1 @@
+alter table cbox_public_link_stats drop column labels;
@@ -0,0 +1,3 @@
+-- The labels chosen by the owners to tell apart the links of the same resource,
+-- separated by commas.
+alter table cbox_public_link_stats add column labels varchar(1024) not null default '';
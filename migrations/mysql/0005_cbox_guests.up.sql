@@ -0,0 +1,12 @@
+-- The guest accounts of the shares with external e-mail addresses. Only the hashes
+-- of the activation tokens are kept, the password is set when the guest activates it.
+create table cbox_guests (
+	email varchar(255) not null primary key,
+	password varchar(255) not null default '',
+	activation_token varchar(64),
+	token_expiration bigint not null default 0,
+	invited_by varchar(255) not null,
+	ctime bigint not null,
+	active tinyint(1) not null default 0,
+	unique key (activation_token)
+);
@@ -0,0 +1,2 @@
+drop table cbox_revoked_user_tokens;
+drop table cbox_revoked_tokens;
@@ -0,0 +1,13 @@
+-- The tokens revoked before they expire, by the id of the token, kept until then.
+create table cbox_revoked_tokens (
+	token_id varchar(64) not null primary key,
+	expiration bigint not null
+);
+
+-- The users whose tokens issued until revoked_until are revoked, kept until the
+-- last of them expires.
+create table cbox_revoked_user_tokens (
+	account_id varchar(255) not null primary key,
+	revoked_until bigint not null,
+	expiration bigint not null
+);
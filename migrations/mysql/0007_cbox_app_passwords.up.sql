@@ -0,0 +1,15 @@
+-- The app passwords of the users for their clients and scripts, optionally restricted
+-- to a path or to reading. Only the hashes of the passwords are kept.
+create table cbox_app_passwords (
+	id varchar(32) not null primary key,
+	account_id varchar(255) not null,
+	label varchar(255) not null,
+	password varchar(64) not null,
+	read_only tinyint(1) not null default 0,
+	path varchar(4096) not null default '',
+	expiration bigint not null default 0,
+	ctime bigint not null,
+	last_used bigint not null default 0,
+	unique key (password),
+	key (account_id)
+);
@@ -4,7 +4,10 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
 	"encoding/hex"
 	"encoding/json"
@@ -14,13 +17,14 @@ import (
 	"image/color"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/mail"
 	"net/smtp"
 	"net/url"
 	gourl "net/url"
 	"os"
 	"os/exec"
-	gouser "os/user"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -37,6 +41,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rwcarlsen/goexif/exif"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -45,6 +50,25 @@ import (
 
 var shareIDRegexp = regexp.MustCompile(`\(id:.+\)$`)
 
+var (
+	basicAuthCacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ocproxy_basic_auth_cache_hits_total",
+		Help: "Number of basic auth requests served with a cached token.",
+	})
+	basicAuthCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ocproxy_basic_auth_cache_misses_total",
+		Help: "Number of basic auth requests that had to authenticate the user against reva.",
+	})
+	basicAuthCacheInvalidations = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ocproxy_basic_auth_cache_invalidations_total",
+		Help: "Number of cached basic auth tokens dropped because reva did not accept them anymore.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(basicAuthCacheHits, basicAuthCacheMisses, basicAuthCacheInvalidations)
+}
+
 func (p *proxy) registerRoutes() {
 	p.router.HandleFunc("/status.php", p.status).Methods("GET")
 	p.router.HandleFunc("/ocs/v1.php/cloud/capabilities", p.capabilities).Methods("GET")
@@ -107,9 +131,11 @@ func (p *proxy) registerRoutes() {
 	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/shares/{share_id}", p.tokenAuth(p.deleteShare)).Methods("DELETE")
 	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/shares/{share_id}", p.tokenAuth(p.updateShare)).Methods("PUT")
 	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/remote_shares", p.tokenAuth(p.getRemoteShares)).Methods("GET")
-	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.getShare)).Methods("GET")
-	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.deleteShare)).Methods("DELETE")
-	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.updateShare)).Methods("PUT")
+	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/remote_shares/pending", p.tokenAuth(p.getPendingRemoteShares)).Methods("GET")
+	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/remote_shares/pending/{share_id}", p.tokenAuth(p.acceptRemoteShare)).Methods("POST")
+	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/remote_shares/pending/{share_id}", p.tokenAuth(p.rejectRemoteShare)).Methods("DELETE")
+	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.getRemoteShare)).Methods("GET")
+	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.rejectRemoteShare)).Methods("DELETE")
 	p.router.HandleFunc("/ocs/v2.php/apps/files_sharing/api/v1/sharees", p.tokenAuth(p.search)).Methods("GET")
 
 	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/shares", p.tokenAuth(p.getShares)).Methods("GET")
@@ -120,13 +146,26 @@ func (p *proxy) registerRoutes() {
 	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/shares/pending/{share_id}", p.tokenAuth(p.acceptShare)).Methods("POST")
 	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/shares/pending/{share_id}", p.tokenAuth(p.rejectShare)).Methods("DELETE")
 	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/remote_shares", p.tokenAuth(p.getRemoteShares)).Methods("GET")
-	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.getShare)).Methods("GET")
-	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.deleteShare)).Methods("DELETE")
-	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.updateShare)).Methods("PUT")
+	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/remote_shares/pending", p.tokenAuth(p.getPendingRemoteShares)).Methods("GET")
+	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/remote_shares/pending/{share_id}", p.tokenAuth(p.acceptRemoteShare)).Methods("POST")
+	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/remote_shares/pending/{share_id}", p.tokenAuth(p.rejectRemoteShare)).Methods("DELETE")
+	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.getRemoteShare)).Methods("GET")
+	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/remote_shares/{share_id}", p.tokenAuth(p.rejectRemoteShare)).Methods("DELETE")
 	p.router.HandleFunc("/ocs/v1.php/apps/files_sharing/api/v1/sharees", p.tokenAuth(p.search)).Methods("GET")
 
+	// app passwords
+	p.router.HandleFunc("/ocs/v2.php/core/apppasswords", p.tokenAuth(p.getAppPasswords)).Methods("GET")
+	p.router.HandleFunc("/ocs/v2.php/core/apppasswords", p.tokenAuth(p.createAppPassword)).Methods("POST")
+	p.router.HandleFunc("/ocs/v2.php/core/apppasswords/{id}", p.tokenAuth(p.revokeAppPassword)).Methods("DELETE")
+	p.router.HandleFunc("/ocs/v1.php/core/apppasswords", p.tokenAuth(p.getAppPasswords)).Methods("GET")
+	p.router.HandleFunc("/ocs/v1.php/core/apppasswords", p.tokenAuth(p.createAppPassword)).Methods("POST")
+	p.router.HandleFunc("/ocs/v1.php/core/apppasswords/{id}", p.tokenAuth(p.revokeAppPassword)).Methods("DELETE")
+
 	// public link routes
 	p.router.HandleFunc("/index.php/s/{token}", p.renderPublicLink).Methods("GET", "POST")
+
+	// activation of the guest accounts invited by e-mail
+	p.router.HandleFunc("/index.php/apps/guests/activate", p.renderGuestActivation).Methods("GET", "POST")
 	p.router.HandleFunc("/index.php/s/{token}/download", p.publicLinkAuth(p.tokenAuth(p.downloadArchivePL))).Methods("GET")
 	p.router.HandleFunc("/index.php/apps/files_sharing/ajax/publicpreview.php", p.tokenAuth(p.getPublicPreview)).Methods("GET")
 
@@ -145,6 +184,15 @@ func (p *proxy) registerRoutes() {
 	// avatars
 	p.router.HandleFunc("/index.php/avatar/{username}/{size}", p.tokenAuth(p.getAvatar)).Methods("GET")
 	p.router.HandleFunc("/index.php/apps/files_sharing/api/externalShares", p.tokenAuth(p.getExternalShares)).Methods("GET")
+	p.router.HandleFunc("/index.php/apps/files_sharing/api/externalShares", p.tokenAuth(p.acceptExternalShare)).Methods("POST")
+	p.router.HandleFunc("/index.php/apps/files_sharing/api/externalShares/{share_id}", p.tokenAuth(p.rejectExternalShare)).Methods("DELETE")
+
+	// open cloud mesh routes, called by remote servers
+	p.router.HandleFunc("/ocm-provider", p.getOCMProvider).Methods("GET")
+	p.router.HandleFunc("/ocm-provider/", p.getOCMProvider).Methods("GET")
+	p.router.HandleFunc("/.well-known/ocm", p.getOCMProvider).Methods("GET")
+	p.router.HandleFunc("/index.php/ocm/shares", p.addOCMShare).Methods("POST")
+	p.router.HandleFunc("/index.php/ocm/notifications", p.addOCMNotification).Methods("POST")
 
 	// project spaces
 	p.router.HandleFunc("/index.php/apps/files_projectspaces/ajax/personal_list.php", p.tokenAuth(p.getPersonalProjects)).Methods("GET")
@@ -175,6 +223,44 @@ func (p *proxy) registerRoutes() {
 
 }
 
+// forgeAppToken returns a token restricted to the path for an app, valid during lifetime
+// seconds or the default lifetime of revad if zero. The public link tokens are already
+// restricted to their link and the scoped tokens cannot forge other ones, so they are returned as they are.
+func (p *proxy) forgeAppToken(ctx context.Context, revaPath string, readOnly bool, lifetime int) (string, error) {
+	if token, ok := reva_api.ContextGetPublicLinkToken(ctx); ok && token != "" {
+		return token, nil
+	}
+
+	gCtx := GetContextWithAuth(ctx)
+	req := &reva_api.ScopedTokenReq{Scope: &reva_api.TokenScope{Path: revaPath, ReadOnly: readOnly}, Lifetime: uint64(lifetime)}
+	res, err := p.getAuthClient().ForgeScopedToken(gCtx, req)
+	if err != nil {
+		return "", err
+	}
+	if res.Status == reva_api.StatusCode_PERMISSION_DENIED {
+		if token, ok := reva_api.ContextGetAccessToken(ctx); ok && token != "" {
+			return token, nil
+		}
+	}
+	if res.Status != reva_api.StatusCode_OK {
+		return "", reva_api.NewError(reva_api.UnknownError).WithMessage(fmt.Sprintf("unexpected status forging app token: %s", res.Status))
+	}
+	return res.Token, nil
+}
+
+// getAppContext returns the context for the viewers to read the file with
+// a read-only token restricted to it instead of the token of the user.
+func (p *proxy) getAppContext(ctx context.Context, revaPath string) (context.Context, error) {
+	if _, ok := reva_api.ContextGetPublicLinkToken(ctx); ok {
+		return GetContextWithAuth(ctx), nil
+	}
+	token, err := p.forgeAppToken(ctx, revaPath, true, 0)
+	if err != nil {
+		return nil, err
+	}
+	return GetContextWithAuth(reva_api.ContextSetAccessToken(ctx, token)), nil
+}
+
 func (p *proxy) loadPublicRootFile(w http.ResponseWriter, r *http.Request) {
 	p.loadRootFile(w, r)
 }
@@ -201,7 +287,12 @@ func (p *proxy) loadRootFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gCtx := GetContextWithAuth(ctx)
+	gCtx, err := p.getAppContext(ctx, revaPath)
+	if err != nil {
+		p.logger.Error("error forging app token for root viewer", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 	pathReq := &reva_api.PathReq{Path: revaPath}
 	stream, err := p.getStorageClient().ReadFile(gCtx, pathReq)
 	if err != nil {
@@ -544,11 +635,18 @@ func (p *proxy) getCurrentUser(w http.ResponseWriter, r *http.Request) {
 		StatusCode int         `json:"statuscode"`
 	}
 
+	directoryUser := p.getDirectoryUser(ctx, user.AccountId)
 	userData := struct {
 		ID          string `json:"id"`
 		DisplayName string `json:"display-name"`
 		Email       string `json:"email"`
-	}{ID: user.AccountId, DisplayName: user.AccountId, Email: user.AccountId + "@cern.ch"}
+	}{ID: user.AccountId, DisplayName: directoryUser.DisplayName, Email: directoryUser.Mail}
+	if userData.DisplayName == "" {
+		userData.DisplayName = user.AccountId
+	}
+	if userData.Email == "" {
+		userData.Email = user.AccountId + "@cern.ch"
+	}
 
 	meta := &ResponseMeta{Status: "ok", StatusCode: 100, Message: "OK"}
 	payload := &OCSPayload{Meta: meta, Data: userData}
@@ -573,7 +671,12 @@ func (p *proxy) swanLoad(w http.ResponseWriter, r *http.Request) {
 	filename := r.URL.Query().Get("filename")
 	revaPath := p.getRevaPath(ctx, filename)
 
-	gCtx := GetContextWithAuth(ctx)
+	gCtx, err := p.getAppContext(ctx, revaPath)
+	if err != nil {
+		p.logger.Error("error forging app token for swan", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 	pathReq := &reva_api.PathReq{Path: revaPath}
 	stream, err := p.getStorageClient().ReadFile(gCtx, pathReq)
 	if err != nil {
@@ -723,15 +826,14 @@ func (p *proxy) wopiPublicOpen(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	unixUser, err := gouser.Lookup(pl.OwnerId)
+	// the wopi server accesses the file through revad with the token of the link
+	accessToken, err := p.forgeAppToken(ctx, revaPath, pl.ReadOnly, p.wopiTokenLifetime)
 	if err != nil {
-		p.logger.Error("ocproxy: api: error getting unix uid/gid", zap.Error(err))
+		p.logger.Error("ocproxy: api: error forging app token for wopi", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	uid, gid := unixUser.Uid, unixUser.Gid
-
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
@@ -752,8 +854,6 @@ func (p *proxy) wopiPublicOpen(w http.ResponseWriter, r *http.Request) {
 	}
 
 	q := req.URL.Query()
-	q.Add("ruid", uid)
-	q.Add("rgid", gid)
 	q.Add("filename", md.EosFile)
 	q.Add("canedit", canEdit)
 	q.Add("folderurl", folderURL)
@@ -762,6 +862,7 @@ func (p *proxy) wopiPublicOpen(w http.ResponseWriter, r *http.Request) {
 	req.URL.RawQuery = q.Encode()
 
 	req.Header.Set("authorization", fmt.Sprintf("Bearer %s", p.wopiSecret))
+	req.Header.Set("TokenHeader", accessToken)
 	res, err := client.Do(req)
 	if err != nil {
 		p.logger.Error("", zap.Error(err))
@@ -813,19 +914,19 @@ func (p *proxy) wopiOpen(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	unixUser, err := gouser.Lookup(user.AccountId)
+	revaPath := p.getRevaPath(ctx, fn)
+	md, err := p.getMetadata(ctx, revaPath)
 	if err != nil {
-		p.logger.Error("ocproxy: api: error getting unix uid/gid", zap.Error(err))
+		p.logger.Error("ocproxy: api: error getting md", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	uid, gid := unixUser.Uid, unixUser.Gid
-
-	revaPath := p.getRevaPath(ctx, fn)
-	md, err := p.getMetadata(ctx, revaPath)
+	// the wopi server accesses the file through revad with a token restricted
+	// to it and valid for the editing session, instead of as the unix user
+	accessToken, err := p.forgeAppToken(ctx, revaPath, md.IsReadOnly, p.wopiTokenLifetime)
 	if err != nil {
-		p.logger.Error("ocproxy: api: error getting md", zap.Error(err))
+		p.logger.Error("ocproxy: api: error forging app token for wopi", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -850,8 +951,6 @@ func (p *proxy) wopiOpen(w http.ResponseWriter, r *http.Request) {
 	}
 
 	q := req.URL.Query()
-	q.Add("ruid", uid)
-	q.Add("rgid", gid)
 	q.Add("filename", md.EosFile)
 	q.Add("canedit", canEdit)
 	q.Add("folderurl", folderURL)
@@ -860,6 +959,7 @@ func (p *proxy) wopiOpen(w http.ResponseWriter, r *http.Request) {
 	req.URL.RawQuery = q.Encode()
 
 	req.Header.Set("authorization", fmt.Sprintf("Bearer %s", p.wopiSecret))
+	req.Header.Set("TokenHeader", accessToken)
 	res, err := client.Do(req)
 	if err != nil {
 		p.logger.Error("", zap.Error(err))
@@ -1217,17 +1317,31 @@ func (p *proxy) getFavXMLProp() propertyXML {
 	return prop
 }
 
-func (p *proxy) getExternalShares(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Write([]byte("[]"))
-
-}
-
+// getAvatar writes the picture of the user, or its display name for the
+// web ui to draw a placeholder if the directory has no picture for the user.
 func (p *proxy) getAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	username := mux.Vars(r)["username"]
-	w.Header().Set("Content-Type", "application/json")
-	w.Write([]byte(fmt.Sprintf(`{"data":{"displayname":"%s"}`, username)))
 
+	gCtx := GetContextWithAuth(ctx)
+	res, err := p.getDirectoryClient().GetUserAvatar(gCtx, &reva_api.UserReq{AccountId: username})
+	if err != nil {
+		p.logger.Error("error getting avatar", zap.Error(err), zap.String("username", username))
+	} else if res.Status == reva_api.StatusCode_OK && len(res.Avatar) > 0 {
+		w.Header().Set("Content-Type", res.MimeType)
+		w.Write(res.Avatar)
+		return
+	}
+
+	data := map[string]interface{}{"data": map[string]string{"displayname": p.getDisplayName(ctx, username)}}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
 }
 
 func (p *proxy) getGalleryConfig(w http.ResponseWriter, r *http.Request) {
@@ -1357,6 +1471,17 @@ type OCSShare struct {
 	URL                  string     `json:"url"`
 	State                ShareState `json:"state"`
 	Expiration           string     `json:"expiration,omitempty"`
+	Label                string     `json:"label"`
+	Labels               []string   `json:"labels"`
+	MaxDownloads         uint64     `json:"max_downloads,omitempty"`
+	MaxVisitors          uint64     `json:"max_visitors,omitempty"`
+	Downloads            uint64     `json:"downloads,omitempty"`
+	Uploads              uint64     `json:"uploads,omitempty"`
+	Visitors             uint64     `json:"visitors,omitempty"`
+	LastAccess           string     `json:"last_access,omitempty"`
+
+	UploadPolicy  *reva_api.UploadPolicy `json:"upload_policy,omitempty"`
+	UploadedBytes uint64                 `json:"uploaded_bytes,omitempty"`
 }
 
 type NewShareOCSRequest struct {
@@ -1368,6 +1493,22 @@ type NewShareOCSRequest struct {
 	Password     JSONString `json:"password"`
 	Permissions  JSONInt    `json:"permissions"`
 	ExpireDate   JSONString `json:"expireDate"`
+	Token        string     `json:"token"`
+	MaxDownloads JSONInt    `json:"maxDownloads"`
+	MaxVisitors  JSONInt    `json:"maxVisitors"`
+
+	// UploadPolicy restricts the uploads to drop only links, if set on an update
+	// it replaces the current policy.
+	UploadPolicy *reva_api.UploadPolicy `json:"uploadPolicy"`
+
+	// Label is the name of the link used by nextcloud clients, it is an alias of Name.
+	Label string `json:"label"`
+
+	// Labels tag the link, on an update a non nil list replaces the current labels.
+	Labels []string `json:"labels"`
+
+	// MailTo is a comma separated list of e-mail addresses the new link is sent to.
+	MailTo string `json:"mailTo"`
 }
 
 type Options struct {
@@ -1390,8 +1531,8 @@ type Options struct {
 	OwnCloudPersonalProjectsPrefix string
 	RevaPersonalProjectsPrefix     string
 
-	CBOXGroupDaemonURI    string
-	CBOXGroupDaemonSecret string
+	OwnCloudRemoteSharePrefix string
+	RevaRemoteSharePrefix     string
 
 	MaxNumFilesForArchive int
 	MaxSizeForArchive     int
@@ -1401,14 +1542,36 @@ type Options struct {
 
 	WopiServer string
 	WopiSecret string
+	// WopiTokenLifetime is the time in seconds the token given to the
+	// wopi server is valid, it must cover an editing session.
+	WopiTokenLifetime int
 
 	DrawIOURL string
 
 	CacheSize     int
 	CacheEviction int
 
+	// BasicAuthCacheSize is the maximum number of basic auth credentials
+	// whose reva token is kept, BasicAuthCacheTTL is the time in seconds
+	// a token is reused, it should be lower than the lifetime of the tokens.
+	BasicAuthCacheSize int
+	BasicAuthCacheTTL  int
+
+	// DisplayNameCacheSize is the maximum number of users whose display name
+	// is kept, DisplayNameCacheTTL is the time in seconds it is reused.
+	DisplayNameCacheSize int
+	DisplayNameCacheTTL  int
+
 	MailServer            string
 	MailServerFromAddress string
+
+	// OCMSharedSecret authenticates the shares and notifications of remote servers
+	// relayed to revad, that only accepts the shares of its trusted providers.
+	OCMSharedSecret string
+
+	// TrustedProxies are the IPs or CIDR networks of the reverse proxies in front
+	// of ocproxy, only their X-Real-Ip and X-Forwarded-For headers are honoured.
+	TrustedProxies []string
 }
 
 func (opt *Options) init() {
@@ -1447,6 +1610,14 @@ func (opt *Options) init() {
 		opt.RevaPersonalProjectsPrefix = "/projects"
 	}
 
+	if opt.OwnCloudRemoteSharePrefix == "" {
+		opt.OwnCloudRemoteSharePrefix = "/__myremoteshares"
+	}
+
+	if opt.RevaRemoteSharePrefix == "" {
+		opt.RevaRemoteSharePrefix = "/shares"
+	}
+
 	if opt.MaxSizeForArchive == 0 {
 		opt.MaxSizeForArchive = 1024 * 1024 * 1024 * 8 // 8 GiB
 	}
@@ -1473,6 +1644,20 @@ func (opt *Options) init() {
 	if opt.CacheEviction == 0 {
 		opt.CacheEviction = 86400
 	}
+
+	if opt.BasicAuthCacheSize == 0 {
+		opt.BasicAuthCacheSize = 10000
+	}
+	if opt.BasicAuthCacheTTL == 0 {
+		opt.BasicAuthCacheTTL = 300
+	}
+
+	if opt.DisplayNameCacheSize == 0 {
+		opt.DisplayNameCacheSize = 10000
+	}
+	if opt.DisplayNameCacheTTL == 0 {
+		opt.DisplayNameCacheTTL = 3600
+	}
 }
 
 func New(opt *Options) (http.Handler, error) {
@@ -1504,13 +1689,22 @@ func New(opt *Options) (http.Handler, error) {
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
 
+	// the credentials are only kept as an HMAC with a key that does not leave the process
+	basicAuthCacheKey := make([]byte, 32)
+	if _, err := rand.Read(basicAuthCacheKey); err != nil {
+		return nil, err
+	}
+
+	trustedProxies, err := reva_api.ParseTrustedProxies(opt.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
 	proxy := &proxy{
-		maxUploadFileSize:     int64(opt.MaxUploadFileSize),
-		router:                opt.Router,
-		revaHost:              opt.REVAHost,
-		logger:                opt.Logger,
-		cboxGroupDaemonURI:    opt.CBOXGroupDaemonURI,
-		cboxGroupDaemonSecret: opt.CBOXGroupDaemonSecret,
+		maxUploadFileSize: int64(opt.MaxUploadFileSize),
+		router:            opt.Router,
+		revaHost:          opt.REVAHost,
+		logger:            opt.Logger,
 
 		ownCloudHomePrefix: opt.OwnCloudHomePrefix,
 		revaHomePrefix:     opt.RevaHomePrefix,
@@ -1524,6 +1718,9 @@ func New(opt *Options) (http.Handler, error) {
 		ownCloudPersonalProjectsPrefix: opt.OwnCloudPersonalProjectsPrefix,
 		revaPersonalProjectsPrefix:     opt.RevaPersonalProjectsPrefix,
 
+		ownCloudRemoteSharePrefix: opt.OwnCloudRemoteSharePrefix,
+		revaRemoteSharePrefix:     opt.RevaRemoteSharePrefix,
+
 		chunksFolder:    opt.ChunksFolder,
 		temporaryFolder: opt.TemporaryFolder,
 
@@ -1533,18 +1730,30 @@ func New(opt *Options) (http.Handler, error) {
 
 		overwriteHost: opt.OverwriteHost,
 
-		wopiServer: opt.WopiServer,
-		wopiSecret: opt.WopiSecret,
+		wopiServer:        opt.WopiServer,
+		wopiSecret:        opt.WopiSecret,
+		wopiTokenLifetime: opt.WopiTokenLifetime,
 
 		drawIOURL: opt.DrawIOURL,
 
 		shareCache:    gcache.New(opt.CacheSize).LFU().Build(),
 		cacheEviction: time.Duration(opt.CacheEviction) * time.Second,
 
+		basicAuthCache:    gcache.New(opt.BasicAuthCacheSize).LRU().Build(),
+		basicAuthCacheTTL: time.Duration(opt.BasicAuthCacheTTL) * time.Second,
+		basicAuthCacheKey: basicAuthCacheKey,
+
+		displayNameCache:    gcache.New(opt.DisplayNameCacheSize).LRU().Build(),
+		displayNameCacheTTL: time.Duration(opt.DisplayNameCacheTTL) * time.Second,
+
 		tr: tr,
 
 		mailServer:            opt.MailServer,
 		mailServerFromAddress: opt.MailServerFromAddress,
+
+		ocmSharedSecret: opt.OCMSharedSecret,
+
+		trustedProxies: trustedProxies,
 	}
 
 	proxy.registerRoutes()
@@ -1574,8 +1783,8 @@ type proxy struct {
 	ownCloudPersonalProjectsPrefix string
 	revaPersonalProjectsPrefix     string
 
-	cboxGroupDaemonURI    string
-	cboxGroupDaemonSecret string
+	ownCloudRemoteSharePrefix string
+	revaRemoteSharePrefix     string
 
 	maxNumFilesForArchive int
 	maxSizeForArchive     int
@@ -1583,8 +1792,9 @@ type proxy struct {
 
 	overwriteHost string
 
-	wopiServer string
-	wopiSecret string
+	wopiServer        string
+	wopiSecret        string
+	wopiTokenLifetime int
 
 	drawIOURL string
 
@@ -1592,8 +1802,19 @@ type proxy struct {
 	cacheEviction time.Duration
 	tr            *http.Transport
 
+	basicAuthCache    gcache.Cache
+	basicAuthCacheTTL time.Duration
+	basicAuthCacheKey []byte
+
+	displayNameCache    gcache.Cache
+	displayNameCacheTTL time.Duration
+
 	mailServer            string
 	mailServerFromAddress string
+
+	ocmSharedSecret string
+
+	trustedProxies []*net.IPNet
 }
 
 // TODO(labkode): store this global var inside the proxy
@@ -1636,6 +1857,14 @@ func (p *proxy) getShareClient() reva_api.ShareClient {
 	return reva_api.NewShareClient(conn)
 }
 
+func (p *proxy) getOCMClient() reva_api.OCMClient {
+	conn, err := p.getConn()
+	if err != nil {
+		panic(err)
+	}
+	return reva_api.NewOCMClient(conn)
+}
+
 func (p *proxy) getAuthClient() reva_api.AuthClient {
 	conn, err := p.getConn()
 	if err != nil {
@@ -1644,6 +1873,14 @@ func (p *proxy) getAuthClient() reva_api.AuthClient {
 	return reva_api.NewAuthClient(conn)
 }
 
+func (p *proxy) getDirectoryClient() reva_api.DirectoryClient {
+	conn, err := p.getConn()
+	if err != nil {
+		panic(err)
+	}
+	return reva_api.NewDirectoryClient(conn)
+}
+
 func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	p.router.ServeHTTP(w, r)
 }
@@ -2065,11 +2302,13 @@ type OCSShareeData struct {
 	Users   []*OCSShareeEntry `json:"users"`
 	Groups  []*OCSShareeEntry `json:"groups"`
 	Remotes []*OCSShareeEntry `json:"remotes"`
+	Emails  []*OCSShareeEntry `json:"emails"`
 }
 type OCSShareeExact struct {
 	Users   []*OCSShareeEntry `json:"users"`
 	Groups  []*OCSShareeEntry `json:"groups"`
 	Remotes []*OCSShareeEntry `json:"remotes"`
+	Emails  []*OCSShareeEntry `json:"emails"`
 }
 
 type OCSShareeEntry struct {
@@ -2287,7 +2526,7 @@ func (p *proxy) downloadArchivePL(w http.ResponseWriter, r *http.Request) {
 	if fileCount > p.maxNumFilesForArchive {
 		p.logger.Warn("exceeded max number of files for archiving", zap.Int("max", p.maxNumFilesForArchive), zap.Int("found", fileCount))
 		w.WriteHeader(http.StatusBadRequest)
-		msg := fmt.Sprintf("You are trying to download an archive (tar/zip) that contains %s files, which exceed our limit of %d.\nTry using the sync client to get a copy of your files", fileCount, p.maxNumFilesForArchive)
+		msg := fmt.Sprintf("You are trying to download an archive (tar/zip) that contains %d files, which exceed our limit of %d.\nTry using the sync client to get a copy of your files", fileCount, p.maxNumFilesForArchive)
 		w.Write([]byte(msg))
 		return
 	}
@@ -2310,6 +2549,10 @@ func (p *proxy) downloadArchivePL(w http.ResponseWriter, r *http.Request) {
 
 	// TODO(labkode): check for size because once the data is being written to the client we cannot override the headers.
 
+	if !p.recordPublicLinkDownload(w, r) {
+		return
+	}
+
 	// if downloadStartSecret is set in the query param we need to set the cookie ocDownloadStarted with same value.
 	if r.URL.Query().Get("downloadStartSecret") != "" {
 		http.SetCookie(w, &http.Cookie{
@@ -2478,7 +2721,7 @@ func (p *proxy) downloadArchive(w http.ResponseWriter, r *http.Request) {
 	if fileCount > p.maxNumFilesForArchive {
 		p.logger.Warn("exceeded max number of files for archiving", zap.Int("max", p.maxNumFilesForArchive), zap.Int("found", fileCount))
 		w.WriteHeader(http.StatusBadRequest)
-		msg := fmt.Sprintf("You are trying to download an archive (tar/zip) that contains %s files, which exceed our limit of %d.\nTry using the sync client to get a copy of your files", fileCount, p.maxNumFilesForArchive)
+		msg := fmt.Sprintf("You are trying to download an archive (tar/zip) that contains %d files, which exceed our limit of %d.\nTry using the sync client to get a copy of your files", fileCount, p.maxNumFilesForArchive)
 		w.Write([]byte(msg))
 		return
 	}
@@ -2491,6 +2734,10 @@ func (p *proxy) downloadArchive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !p.recordPublicLinkDownload(w, r) {
+		return
+	}
+
 	// if downloadStartSecret is set in the query param we need to set the cookie ocDownloadStarted with same value.
 	if r.URL.Query().Get("downloadStartSecret") != "" {
 		http.SetCookie(w, &http.Cookie{
@@ -3154,24 +3401,6 @@ func (p *proxy) loadFile(w http.ResponseWriter, r *http.Request) {
 	w.Write(encoded)
 }
 
-type searchEntry struct {
-	DN          string          `json:"dn"`
-	CN          string          `json:"cn"`
-	AccountType LDAPAccountType `json:"account_type"`
-	DisplayName string          `json:"display_name"`
-	Mail        string          `json:"mail"`
-}
-
-func (p *proxy) getShareType(ldapType LDAPAccountType) ShareType {
-	if ldapType == LDAPAccountTypePrimary || ldapType == LDAPAccountTypeSecondary || ldapType == LDAPAccountTypeService {
-		return ShareTypeUser
-	} else if ldapType == LDAPAccountTypeEGroup || ldapType == LDAPAccountTypeEGroup {
-		return ShareTypeGroup
-	} else {
-		// fallback to user
-		return ShareTypeUser
-	}
-}
 func (p *proxy) getSearchTarget(search string) string {
 	tokens := strings.Split(search, ":")
 	if len(tokens) == 0 {
@@ -3181,12 +3410,16 @@ func (p *proxy) getSearchTarget(search string) string {
 	}
 }
 
-// search calls the cboxgroupd daemon for finding entries.
+// defaultShareesPerPage is the number of sharees returned when the client does not
+// ask for a page size, like owncloud does.
+const defaultShareesPerPage = 200
+
+// search looks up the users and groups to share with in the directory of reva.
 func (p *proxy) search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	search := r.URL.Query().Get("search")
 
 	//itemType := r.URL.Query().Get("itemType")
-	//perPage := r.URL.Query().Get("perPage")
 
 	if search == "" {
 		w.WriteHeader(http.StatusBadRequest)
@@ -3194,43 +3427,17 @@ func (p *proxy) search(w http.ResponseWriter, r *http.Request) {
 	}
 	searchTarget := p.getSearchTarget(search)
 
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{Transport: tr}
-	url := fmt.Sprintf("%s/api/v1/search/%s", p.cboxGroupDaemonURI, search)
-	p.logger.Info("jeje", zap.String("uri", p.cboxGroupDaemonURI), zap.String("url", url))
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		p.logger.Error("", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.cboxGroupDaemonSecret))
-	res, err := client.Do(req)
-	if err != nil {
-		p.logger.Error("", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	if res.StatusCode != http.StatusOK {
-		p.logger.Error("error calling cboxgroupd search", zap.Int("status", res.StatusCode))
-		w.WriteHeader(res.StatusCode)
-		return
-
+	page, perPage := 1, defaultShareesPerPage
+	if v, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && v > 0 {
+		page = v
 	}
-
-	searchEntries := []*searchEntry{}
-	body, err := ioutil.ReadAll(res.Body)
-	defer res.Body.Close()
-	if err != nil {
-		p.logger.Error("", zap.Error(err))
-		w.WriteHeader(res.StatusCode)
-		return
+	if v, err := strconv.Atoi(r.URL.Query().Get("perPage")); err == nil && v > 0 {
+		perPage = v
 	}
 
-	err = json.Unmarshal(body, &searchEntries)
+	gCtx := GetContextWithAuth(ctx)
+	searchReq := &reva_api.SearchReq{Prefix: searchTarget, Offset: uint64((page - 1) * perPage), Limit: uint64(perPage)}
+	stream, err := p.getDirectoryClient().Search(gCtx, searchReq)
 	if err != nil {
 		p.logger.Error("", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
@@ -3241,23 +3448,43 @@ func (p *proxy) search(w http.ResponseWriter, r *http.Request) {
 	inexactUserEntries := []*OCSShareeEntry{}
 	exactGroupEntries := []*OCSShareeEntry{}
 	inexactGroupEntries := []*OCSShareeEntry{}
-	for _, se := range searchEntries {
-		shareType := p.getShareType(se.AccountType)
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			p.logger.Error("", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if res.Status != reva_api.StatusCode_OK {
+			p.writeError(res.Status, w, r)
+			return
+		}
+
+		e := res.Entry
 		ocsEntry := &OCSShareeEntry{
-			Value: &OCSShareeEntryValue{ShareType: p.getShareType(se.AccountType), ShareWith: se.CN},
+			Value: &OCSShareeEntryValue{ShareType: ShareTypeUser, ShareWith: e.Id},
 		}
 
-		if shareType == ShareTypeUser {
-			ocsEntry.Label = fmt.Sprintf("%s (%s)", se.DisplayName, se.CN)
-			if se.CN == searchTarget {
+		if e.Type == reva_api.DirectoryEntry_USER {
+			ocsEntry.Label = fmt.Sprintf("%s (%s)", e.DisplayName, e.Id)
+			if e.Id == searchTarget {
 				exactUserEntries = append(exactUserEntries, ocsEntry)
 			} else {
 				inexactUserEntries = append(inexactUserEntries, ocsEntry)
 			}
 
-		} else { // asumme group
-			ocsEntry.Label = se.CN // owncloud will append (group) at the end
-			if se.CN == searchTarget {
+		} else {
+			ocsEntry.Value.ShareType = ShareTypeGroup
+			ocsEntry.Label = e.Id // owncloud will append (group) at the end
+			if e.Type == reva_api.DirectoryEntry_UNIX_GROUP {
+				// owncloud only knows one kind of group, unix groups are told apart by the prefix
+				ocsEntry.Label = fmt.Sprintf("%s (unix)", e.Id)
+				ocsEntry.Value.ShareWith = unixGroupPrefix + e.Id
+			}
+			if e.Id == searchTarget {
 				exactGroupEntries = append(exactGroupEntries, ocsEntry)
 			} else {
 				inexactGroupEntries = append(inexactGroupEntries, ocsEntry)
@@ -3267,8 +3494,28 @@ func (p *proxy) search(w http.ResponseWriter, r *http.Request) {
 
 	}
 
-	exact := &OCSShareeExact{Users: exactUserEntries, Groups: exactGroupEntries, Remotes: []*OCSShareeEntry{}}
-	data := &OCSShareeData{Exact: exact, Users: inexactUserEntries, Groups: inexactGroupEntries, Remotes: []*OCSShareeEntry{}}
+	// a federated cloud id like gonzalhu@cernbox.cern.ch is offered as a remote recipient
+	exactRemoteEntries := []*OCSShareeEntry{}
+	if user, host := reva_api.SplitFederatedID(search); user != "" && host != "" {
+		ocsEntry := &OCSShareeEntry{
+			Label: search,
+			Value: &OCSShareeEntryValue{ShareType: ShareTypeRemote, ShareWith: search},
+		}
+		exactRemoteEntries = append(exactRemoteEntries, ocsEntry)
+	}
+
+	// an e-mail address is offered too, to invite the recipient as a guest
+	exactEmailEntries := []*OCSShareeEntry{}
+	if address, err := mail.ParseAddress(search); err == nil && address.Address == search {
+		ocsEntry := &OCSShareeEntry{
+			Label: search,
+			Value: &OCSShareeEntryValue{ShareType: ShareTypeGuest, ShareWith: search},
+		}
+		exactEmailEntries = append(exactEmailEntries, ocsEntry)
+	}
+
+	exact := &OCSShareeExact{Users: exactUserEntries, Groups: exactGroupEntries, Remotes: exactRemoteEntries, Emails: exactEmailEntries}
+	data := &OCSShareeData{Exact: exact, Users: inexactUserEntries, Groups: inexactGroupEntries, Remotes: []*OCSShareeEntry{}, Emails: []*OCSShareeEntry{}}
 
 	meta := &ResponseMeta{Status: "ok", StatusCode: 100, Message: "OK"}
 	payload := &OCSPayload{Meta: meta, Data: data}
@@ -3291,11 +3538,22 @@ func (p *proxy) search(w http.ResponseWriter, r *http.Request) {
 func (p *proxy) createPublicLinkShare(ctx context.Context, newShare *NewShareOCSRequest, readOnly, dropOnly bool, expiration int64, w http.ResponseWriter, r *http.Request) {
 	gCtx := GetContextWithAuth(ctx)
 	newLinkReq := &reva_api.NewLinkReq{
-		Path:     newShare.Path,
-		ReadOnly: readOnly,
-		DropOnly: dropOnly,
-		Password: newShare.Password.Value,
-		Expires:  uint64(expiration),
+		Token:        newShare.Token,
+		Name:         newShare.Name,
+		Path:         newShare.Path,
+		ReadOnly:     readOnly,
+		DropOnly:     dropOnly,
+		Password:     newShare.Password.Value,
+		Expires:      uint64(expiration),
+		MaxDownloads: uint64(newShare.MaxDownloads.Value),
+		MaxVisitors:  uint64(newShare.MaxVisitors.Value),
+		UploadPolicy: newShare.UploadPolicy,
+		Labels:       newShare.Labels,
+	}
+	for _, mailTo := range strings.Split(newShare.MailTo, ",") {
+		if mailTo = strings.TrimSpace(mailTo); mailTo != "" {
+			newLinkReq.Notify = append(newLinkReq.Notify, mailTo)
+		}
 	}
 	publicLinkRes, err := p.getShareClient().CreatePublicLink(gCtx, newLinkReq)
 	if err != nil {
@@ -3332,15 +3590,7 @@ func (p *proxy) createPublicLinkShare(ctx context.Context, newShare *NewShareOCS
 }
 
 func (p *proxy) createFolderShare(ctx context.Context, newShare *NewShareOCSRequest, readOnly bool, w http.ResponseWriter, r *http.Request) {
-	recipientType := reva_api.ShareRecipient_USER
-	if newShare.ShareType == ShareTypeGroup {
-		recipientType = reva_api.ShareRecipient_GROUP
-	}
-
-	recipient := &reva_api.ShareRecipient{
-		Identity: newShare.ShareWith,
-		Type:     recipientType,
-	}
+	recipient := getShareRecipient(newShare.ShareType, newShare.ShareWith)
 
 	newFolderShareReq := &reva_api.NewFolderShareReq{
 		Path:      newShare.Path,
@@ -3382,6 +3632,99 @@ func (p *proxy) createFolderShare(ctx context.Context, newShare *NewShareOCSRequ
 	w.Write(encoded)
 
 }
+// unixGroupPrefix is prepended to the name of unix groups in the ocs api,
+// as ownCloud does not differentiate them from e-groups.
+const unixGroupPrefix = "unixgroup:"
+
+func getShareRecipient(shareType ShareType, shareWith string) *reva_api.ShareRecipient {
+	recipient := &reva_api.ShareRecipient{Identity: shareWith, Type: reva_api.ShareRecipient_USER}
+	switch shareType {
+	case ShareTypeGroup:
+		recipient.Type = reva_api.ShareRecipient_GROUP
+		if strings.HasPrefix(shareWith, unixGroupPrefix) {
+			recipient.Type = reva_api.ShareRecipient_UNIX
+			recipient.Identity = strings.TrimPrefix(shareWith, unixGroupPrefix)
+		}
+	case ShareTypeRemote:
+		recipient.Type = reva_api.ShareRecipient_REMOTE
+	case ShareTypeGuest:
+		recipient.Type = reva_api.ShareRecipient_GUEST
+	}
+	return recipient
+}
+
+func getOCSShareWith(recipient *reva_api.ShareRecipient) (ShareType, string) {
+	switch recipient.Type {
+	case reva_api.ShareRecipient_GROUP:
+		return ShareTypeGroup, recipient.Identity
+	case reva_api.ShareRecipient_UNIX:
+		return ShareTypeGroup, unixGroupPrefix + recipient.Identity
+	case reva_api.ShareRecipient_REMOTE:
+		return ShareTypeRemote, recipient.Identity
+	case reva_api.ShareRecipient_GUEST:
+		return ShareTypeGuest, recipient.Identity
+	default:
+		return ShareTypeUser, recipient.Identity
+	}
+}
+
+// getShareWithDisplayName returns the display name of the recipient of a share,
+// only the users have one different from their id.
+func (p *proxy) getShareWithDisplayName(ctx context.Context, shareType ShareType, shareWith string) string {
+	if shareType != ShareTypeUser {
+		return shareWith
+	}
+	return p.getDisplayName(ctx, shareWith)
+}
+
+// getDisplayName returns the display name of the user, or the account id
+// if the user is not in the directory.
+func (p *proxy) getDisplayName(ctx context.Context, accountID string) string {
+	u := p.getDirectoryUser(ctx, accountID)
+	if u.DisplayName == "" {
+		return accountID
+	}
+	return u.DisplayName
+}
+
+// directoryErrorTTL is the time the users whose lookup failed are cached, so a listing
+// does not call the directory for every share while it is down and the names are back soon.
+const directoryErrorTTL = 30 * time.Second
+
+// getDirectoryUser returns the user from the directory, cached as the shares show
+// the names of the same few users over and over. If the user cannot be found
+// only the account id is set.
+func (p *proxy) getDirectoryUser(ctx context.Context, accountID string) *reva_api.DirectoryUser {
+	if v, err := p.displayNameCache.Get(accountID); err == nil {
+		return v.(*reva_api.DirectoryUser)
+	}
+
+	u := &reva_api.DirectoryUser{AccountId: accountID}
+	ttl := p.displayNameCacheTTL
+	gCtx := GetContextWithAuth(ctx)
+	res, err := p.getDirectoryClient().GetUser(gCtx, &reva_api.UserReq{AccountId: accountID})
+	switch {
+	case err != nil:
+		p.logger.Error("error getting user from directory", zap.Error(err), zap.String("account_id", accountID))
+		ttl = directoryErrorTTL
+	case res.Status == reva_api.StatusCode_PERMISSION_DENIED:
+		// not cached, the cache is shared by the users that can look up the directory
+		return u
+	case res.Status == reva_api.StatusCode_OK && res.User != nil:
+		u = res.User
+	case res.Status != reva_api.StatusCode_USER_NOT_FOUND:
+		p.logger.Error("error getting user from directory", zap.String("status", res.Status.String()), zap.String("account_id", accountID))
+		ttl = directoryErrorTTL
+	}
+	if ttl > p.displayNameCacheTTL {
+		ttl = p.displayNameCacheTTL
+	}
+	if err := p.displayNameCache.SetWithExpire(accountID, u, ttl); err != nil {
+		p.logger.Warn("error caching directory user", zap.Error(err), zap.String("account_id", accountID))
+	}
+	return u
+}
+
 func (p *proxy) createShare(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	newShare := &NewShareOCSRequest{}
@@ -3410,6 +3753,11 @@ func (p *proxy) createShare(w http.ResponseWriter, r *http.Request) {
 
 		newShare.Path = r.Form.Get("path")
 		newShare.ShareWith = r.Form.Get("shareWith")
+		newShare.Token = r.Form.Get("token")
+		newShare.Name = r.Form.Get("name")
+		newShare.Label = r.Form.Get("label")
+		newShare.Labels = splitLabels(r.Form.Get("labels"))
+		newShare.MailTo = r.Form.Get("mailTo")
 
 		var shareType ShareType
 		shareTypeString := r.Form.Get("shareType")
@@ -3419,6 +3767,8 @@ func (p *proxy) createShare(w http.ResponseWriter, r *http.Request) {
 			shareType = ShareTypeGroup
 		} else if shareTypeString == "3" {
 			shareType = ShareTypePublicLink
+		} else if shareTypeString == "6" {
+			shareType = ShareTypeRemote
 		}
 		newShare.ShareType = shareType
 
@@ -3457,6 +3807,21 @@ func (p *proxy) createShare(w http.ResponseWriter, r *http.Request) {
 		}
 		newShare.ExpireDate = expireDateJSON
 
+		if newShare.MaxDownloads, err = getFormJSONInt(r, "maxDownloads"); err != nil {
+			p.logger.Error("", zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if newShare.MaxVisitors, err = getFormJSONInt(r, "maxVisitors"); err != nil {
+			p.logger.Error("", zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if newShare.UploadPolicy, err = getFormUploadPolicy(r); err != nil {
+			p.logger.Error("", zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
 	}
 
 	newShare.Path, ctx = p.stripCBOXMappedPath(r, newShare.Path)
@@ -3507,12 +3872,17 @@ func (p *proxy) createShare(w http.ResponseWriter, r *http.Request) {
 	}
 
 	md := res.Metadata
-	newShare.Name = path.Base(md.Path)
+	if newShare.Name == "" {
+		newShare.Name = newShare.Label
+	}
+	if newShare.Name == "" {
+		newShare.Name = path.Base(md.Path)
+	}
 
 	if newShare.ShareType == ShareTypePublicLink {
 		p.createPublicLinkShare(ctx, newShare, readOnly, dropOnly, expiration, w, r)
 		return
-	} else if newShare.ShareType == ShareTypeUser || newShare.ShareType == ShareTypeGroup {
+	} else if newShare.ShareType == ShareTypeUser || newShare.ShareType == ShareTypeGroup || newShare.ShareType == ShareTypeRemote || newShare.ShareType == ShareTypeGuest {
 		p.createFolderShare(ctx, newShare, readOnly, w, r)
 		return
 	} else {
@@ -3522,29 +3892,513 @@ func (p *proxy) createShare(w http.ResponseWriter, r *http.Request) {
 
 }
 
-func (p *proxy) getRemoteShares(w http.ResponseWriter, r *http.Request) {
-	shares := []*OCSShare{}
-	meta := &ResponseMeta{Status: "ok", StatusCode: 100}
-	payload := &OCSPayload{Meta: meta, Data: shares}
-	ocsRes := &OCSResponse{OCS: payload}
-	encoded, err := json.Marshal(ocsRes)
-	if err != nil {
-		p.logger.Error("", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+// OCSRemoteShare is the representation of a share received from
+// a remote server used by the ownCloud web interface.
+type OCSRemoteShare struct {
+	ID          string `json:"id"`
+	Remote      string `json:"remote"`
+	RemoteID    string `json:"remote_id"`
+	ShareToken  string `json:"share_token"`
+	Name        string `json:"name"`
+	Owner       string `json:"owner"`
+	User        string `json:"user"`
+	Mountpoint  string `json:"mountpoint"`
+	Accepted    int    `json:"accepted"`
+	MimeType    string `json:"mimetype"`
+	Mtime       int    `json:"mtime"`
+	Permissions int    `json:"permissions"`
+	Type        string `json:"type"`
+	FileID      string `json:"file_id"`
+}
+
+func (p *proxy) remoteShareToOCSRemoteShare(ctx context.Context, share *reva_api.RemoteShare) *OCSRemoteShare {
+	ocsShare := &OCSRemoteShare{
+		ID:         share.Id,
+		Remote:     share.Remote,
+		RemoteID:   share.RemoteId,
+		ShareToken: share.Token,
+		Name:       path.Join("/", share.Name),
+		Owner:      share.Owner,
+		User:       share.Recipient,
+		Mountpoint: path.Join(p.ownCloudRemoteSharePrefix, fmt.Sprintf("%s (id:%s)", path.Base(path.Join("/", share.Name)), share.Id)),
+		MimeType:   "httpd/unix-directory",
+		Type:       "dir",
+	}
+
+	if share.State == reva_api.RemoteShare_ACCEPTED {
+		ocsShare.Accepted = 1
+		md, err := p.getCachedMetadata(ctx, p.getRevaPath(ctx, ocsShare.Mountpoint))
+		if err != nil {
+			// the remote server may be unreachable
+			p.logger.Warn("cannot get metadata of remote share", zap.Error(err), zap.String("id", share.Id))
+		} else {
+			ocsShare.Mtime = int(md.Mtime)
+			ocsShare.MimeType = md.Mime
+			ocsShare.FileID = md.Id
+			ocsShare.Permissions = int(PermissionReadWrite)
+			if md.IsReadOnly {
+				ocsShare.Permissions = int(PermissionRead)
+			}
+			if !md.IsDir {
+				ocsShare.Type = "file"
+			}
+		}
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(encoded)
-
+	return ocsShare
 }
 
-func (p *proxy) getShares(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	onlySharedWithOthers := r.URL.Query().Get("only_shared_with_others") == "true"
-	onlySharedByLink := r.URL.Query().Get("only_shared_by_link") == "true"
-	originalPath := r.URL.Query().Get("path")
-	path, ctx := p.stripCBOXMappedPath(r, originalPath)
+func (p *proxy) listRemoteShares(ctx context.Context, state reva_api.RemoteShare_State) ([]*OCSRemoteShare, error) {
+	gCtx := GetContextWithAuth(ctx)
+	stream, err := p.getOCMClient().ListRemoteShares(gCtx, &reva_api.EmptyReq{})
+	if err != nil {
+		return nil, err
+	}
+
+	shares := []*OCSRemoteShare{}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if res.Status != reva_api.StatusCode_OK {
+			return nil, reva_api.NewError(reva_api.UnknownError).WithMessage(fmt.Sprintf("unexpected status listing remote shares: %s", res.Status))
+		}
+
+		if res.Share.State == state {
+			shares = append(shares, p.remoteShareToOCSRemoteShare(ctx, res.Share))
+		}
+	}
+	return shares, nil
+}
+
+func (p *proxy) writeOCSData(data interface{}, w http.ResponseWriter) {
+	meta := &ResponseMeta{Status: "ok", StatusCode: 100}
+	payload := &OCSPayload{Meta: meta, Data: data}
+	ocsRes := &OCSResponse{OCS: payload}
+	encoded, err := json.Marshal(ocsRes)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(encoded)
+}
+
+func (p *proxy) getRemoteShares(w http.ResponseWriter, r *http.Request) {
+	shares, err := p.listRemoteShares(r.Context(), reva_api.RemoteShare_ACCEPTED)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	p.writeOCSData(shares, w)
+}
+
+func (p *proxy) getPendingRemoteShares(w http.ResponseWriter, r *http.Request) {
+	shares, err := p.listRemoteShares(r.Context(), reva_api.RemoteShare_PENDING)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	p.writeOCSData(shares, w)
+}
+
+func (p *proxy) getRemoteShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	shareID := mux.Vars(r)["share_id"]
+	gCtx := GetContextWithAuth(ctx)
+
+	res, err := p.getOCMClient().GetRemoteShare(gCtx, &reva_api.ShareIDReq{Id: shareID})
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if res.Status != reva_api.StatusCode_OK {
+		p.writeError(res.Status, w, r)
+		return
+	}
+
+	p.writeOCSData(p.remoteShareToOCSRemoteShare(ctx, res.Share), w)
+}
+
+func (p *proxy) acceptRemoteShare(w http.ResponseWriter, r *http.Request) {
+	shareID := mux.Vars(r)["share_id"]
+	status, err := p.answerRemoteShare(r.Context(), shareID, true)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if status != reva_api.StatusCode_OK {
+		p.writeError(status, w, r)
+		return
+	}
+	p.writeOCSData(nil, w)
+}
+
+func (p *proxy) rejectRemoteShare(w http.ResponseWriter, r *http.Request) {
+	shareID := mux.Vars(r)["share_id"]
+	status, err := p.answerRemoteShare(r.Context(), shareID, false)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if status != reva_api.StatusCode_OK {
+		p.writeError(status, w, r)
+		return
+	}
+	p.writeOCSData(nil, w)
+}
+
+func (p *proxy) answerRemoteShare(ctx context.Context, shareID string, accept bool) (reva_api.StatusCode, error) {
+	gCtx := GetContextWithAuth(ctx)
+	req := &reva_api.ShareIDReq{Id: shareID}
+
+	var res *reva_api.EmptyResponse
+	var err error
+	if accept {
+		res, err = p.getOCMClient().AcceptRemoteShare(gCtx, req)
+	} else {
+		res, err = p.getOCMClient().RejectRemoteShare(gCtx, req)
+	}
+	if err != nil {
+		return reva_api.StatusCode_UNKNOWN, errors.Wrapf(err, "error answering remote share: id=%s accept=%t", shareID, accept)
+	}
+	return res.Status, nil
+}
+
+// OCSAppPassword is an app password of the user, the password is only set when it is created.
+type OCSAppPassword struct {
+	ID         string `json:"id"`
+	Label      string `json:"label"`
+	ReadOnly   bool   `json:"read_only"`
+	Path       string `json:"path,omitempty"`
+	Expiration uint64 `json:"expiration,omitempty"`
+	Ctime      uint64 `json:"ctime"`
+	LastUsed   uint64 `json:"last_used,omitempty"`
+	Password   string `json:"password,omitempty"`
+}
+
+func (p *proxy) appPasswordToOCSAppPassword(ctx context.Context, ap *reva_api.AppPassword) *OCSAppPassword {
+	ocsAppPassword := &OCSAppPassword{ID: ap.Id, Label: ap.Label, Ctime: ap.Ctime, LastUsed: ap.LastUsed}
+	if ap.Scope != nil {
+		ocsAppPassword.ReadOnly = ap.Scope.ReadOnly
+		ocsAppPassword.Expiration = ap.Scope.Expiration
+		if ap.Scope.Path != "" {
+			ocsAppPassword.Path = p.getPlainOCPath(ctx, ap.Scope.Path)
+		}
+	}
+	return ocsAppPassword
+}
+
+func (p *proxy) getAppPasswords(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	gCtx := GetContextWithAuth(ctx)
+	stream, err := p.getAuthClient().ListAppPasswords(gCtx, &reva_api.EmptyReq{})
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	appPasswords := []*OCSAppPassword{}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			p.logger.Error("", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if res.Status != reva_api.StatusCode_OK {
+			p.writeError(res.Status, w, r)
+			return
+		}
+		appPasswords = append(appPasswords, p.appPasswordToOCSAppPassword(ctx, res.AppPassword))
+	}
+	p.writeOCSData(appPasswords, w)
+}
+
+// createAppPassword creates an app password with the label of the form, restricted
+// to the optional scope given by the form keys readOnly, path and expiration, as unix time.
+func (p *proxy) createAppPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	scope := &reva_api.TokenScope{ReadOnly: r.Form.Get("readOnly") == "true" || r.Form.Get("readOnly") == "1"}
+	if ocPath := r.Form.Get("path"); ocPath != "" {
+		scope.Path = p.getRevaPath(ctx, path.Join("/", ocPath))
+	}
+	if expiration := r.Form.Get("expiration"); expiration != "" {
+		exp, err := strconv.ParseUint(expiration, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		scope.Expiration = exp
+	}
+
+	gCtx := GetContextWithAuth(ctx)
+	req := &reva_api.NewAppPasswordReq{Label: r.Form.Get("label"), Scope: scope}
+	res, err := p.getAuthClient().CreateAppPassword(gCtx, req)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if res.Status != reva_api.StatusCode_OK {
+		p.writeError(res.Status, w, r)
+		return
+	}
+
+	ocsAppPassword := p.appPasswordToOCSAppPassword(ctx, res.AppPassword)
+	ocsAppPassword.Password = res.Password
+	p.writeOCSData(ocsAppPassword, w)
+}
+
+func (p *proxy) revokeAppPassword(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	gCtx := GetContextWithAuth(r.Context())
+	res, err := p.getAuthClient().RevokeAppPassword(gCtx, &reva_api.AppPasswordReq{Id: id})
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if res.Status != reva_api.StatusCode_OK {
+		p.writeError(res.Status, w, r)
+		return
+	}
+	p.writeOCSData(nil, w)
+}
+
+// getExternalShares returns the pending remote shares, the ownCloud web interface
+// asks the user to accept or reject them.
+func (p *proxy) getExternalShares(w http.ResponseWriter, r *http.Request) {
+	shares, err := p.listRemoteShares(r.Context(), reva_api.RemoteShare_PENDING)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	encoded, err := json.Marshal(shares)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+}
+
+func (p *proxy) acceptExternalShare(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	shareID := r.Form.Get("id")
+	if shareID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	status, err := p.answerRemoteShare(r.Context(), shareID, true)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if status != reva_api.StatusCode_OK {
+		p.writeError(status, w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+}
+
+func (p *proxy) rejectExternalShare(w http.ResponseWriter, r *http.Request) {
+	shareID := mux.Vars(r)["share_id"]
+	status, err := p.answerRemoteShare(r.Context(), shareID, false)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if status != reva_api.StatusCode_OK {
+		p.writeError(status, w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+}
+
+// getOCMProvider returns the discovery document of the Open Cloud Mesh protocol.
+func (p *proxy) getOCMProvider(w http.ResponseWriter, r *http.Request) {
+	provider := &reva_api.OCMProvider{
+		Enabled:    true,
+		APIVersion: "1.0-proposal1",
+		EndPoint:   fmt.Sprintf("https://%s/index.php/ocm", p.overwriteHost),
+		Provider:   "CERNBox",
+		ResourceTypes: []*reva_api.OCMResourceType{
+			{
+				Name:       "file",
+				ShareTypes: []string{"user"},
+				Protocols:  map[string]string{"webdav": "/public.php/webdav/"},
+			},
+		},
+	}
+
+	encoded, err := json.Marshal(provider)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(encoded)
+}
+
+// getOCMSecretContext returns the context to relay the calls of remote servers to revad.
+func (p *proxy) getOCMSecretContext(ctx context.Context) context.Context {
+	return metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", "ocm-secret "+p.ocmSharedSecret))
+}
+
+// addOCMShare is called by remote servers to notify that one of their users
+// has shared a resource with a local user.
+func (p *proxy) addOCMShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ocmShare := &reva_api.OCMShare{}
+	if err := json.Unmarshal(body, ocmShare); err != nil {
+		p.logger.Warn("invalid ocm share", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if ocmShare.Protocol == nil || ocmShare.Protocol.Options == nil || ocmShare.Protocol.Options.SharedSecret == "" {
+		p.logger.Warn("ocm share without shared secret", zap.String("provider_id", ocmShare.ProviderID))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// shareWith is the federated cloud id of the local user, like gonzalhu@cernbox.cern.ch
+	recipient, _ := reva_api.SplitFederatedID(ocmShare.ShareWith)
+	_, remote := reva_api.SplitFederatedID(ocmShare.Owner)
+	if recipient == "" || remote == "" {
+		p.logger.Warn("ocm share with invalid recipient or owner", zap.String("share_with", ocmShare.ShareWith), zap.String("owner", ocmShare.Owner))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	share := &reva_api.RemoteShare{
+		Remote:    remote,
+		RemoteId:  ocmShare.ProviderID,
+		Token:     ocmShare.Protocol.Options.SharedSecret,
+		Name:      ocmShare.Name,
+		Owner:     ocmShare.Owner,
+		Recipient: recipient,
+	}
+
+	res, err := p.getOCMClient().AddRemoteShare(p.getOCMSecretContext(ctx), &reva_api.NewRemoteShareReq{Share: share})
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if res.Status != reva_api.StatusCode_OK {
+		p.writeError(res.Status, w, r)
+		return
+	}
+
+	p.logger.Info("ocm share received", zap.String("remote", remote), zap.String("recipient", recipient), zap.String("id", res.Share.Id))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("{}"))
+}
+
+// addOCMNotification is called by remote servers to notify changes on shares.
+func (p *proxy) addOCMNotification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	notification := &reva_api.OCMNotification{}
+	if err := json.Unmarshal(body, notification); err != nil || notification.Notification == nil {
+		p.logger.Warn("invalid ocm notification", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch notification.NotificationType {
+	case reva_api.OCMNotificationShareUnshared:
+		// the owner removed a share received by a local user
+		req := &reva_api.RemoveRemoteShareReq{RemoteId: notification.ProviderID, Token: notification.Notification.SharedSecret}
+		res, err := p.getOCMClient().RemoveRemoteShare(p.getOCMSecretContext(ctx), req)
+		if err != nil {
+			p.logger.Error("", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if res.Status != reva_api.StatusCode_OK {
+			p.writeError(res.Status, w, r)
+			return
+		}
+	case reva_api.OCMNotificationShareAccepted, reva_api.OCMNotificationShareDeclined:
+		// the recipient answered a share created by a local user, the share
+		// stays valid until the owner removes it.
+		p.logger.Info("ocm share answered by remote recipient", zap.String("type", notification.NotificationType), zap.String("provider_id", notification.ProviderID))
+	default:
+		p.logger.Warn("ocm notification not supported", zap.String("type", notification.NotificationType))
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("{}"))
+}
+
+func (p *proxy) getShares(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	onlySharedWithOthers := r.URL.Query().Get("only_shared_with_others") == "true"
+	onlySharedByLink := r.URL.Query().Get("only_shared_by_link") == "true"
+	// subfiles returns the shares of the resources under the path instead of the ones of the path.
+	// reshares is accepted for compatibility with the ownCloud clients but resources can not be
+	// reshared in CERNBox, so the shares of a path are always the ones of its owner.
+	subfiles := r.URL.Query().Get("subfiles") == "true"
+	originalPath := r.URL.Query().Get("path")
+	path, ctx := p.stripCBOXMappedPath(r, originalPath)
 
 	sharedWithMe := r.URL.Query().Get("shared_with_me")
 
@@ -3556,7 +4410,7 @@ func (p *proxy) getShares(w http.ResponseWriter, r *http.Request) {
 	ocsShares := []*OCSShare{}
 
 	if onlySharedByLink {
-		publicLinks, err := p.getPublicLinkShares(ctx, path)
+		publicLinks, err := p.getPublicLinkShares(ctx, path, subfiles)
 		if err != nil {
 			p.logger.Error("", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
@@ -3565,7 +4419,7 @@ func (p *proxy) getShares(w http.ResponseWriter, r *http.Request) {
 		ocsShares = publicLinks
 
 	} else if onlySharedWithOthers {
-		folderShares, err := p.getFolderShares(ctx, path)
+		folderShares, err := p.getFolderShares(ctx, path, subfiles)
 		if err != nil {
 			p.logger.Error("", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
@@ -3574,7 +4428,7 @@ func (p *proxy) getShares(w http.ResponseWriter, r *http.Request) {
 		}
 		ocsShares = folderShares
 	} else {
-		publicLinks, err := p.getPublicLinkShares(ctx, path)
+		publicLinks, err := p.getPublicLinkShares(ctx, path, subfiles)
 		if err != nil {
 			p.logger.Error("", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
@@ -3582,7 +4436,7 @@ func (p *proxy) getShares(w http.ResponseWriter, r *http.Request) {
 		}
 		ocsShares = publicLinks
 
-		folderShares, err := p.getFolderShares(ctx, path)
+		folderShares, err := p.getFolderShares(ctx, path, subfiles)
 		if err != nil {
 			p.logger.Error("", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
@@ -3593,6 +4447,16 @@ func (p *proxy) getShares(w http.ResponseWriter, r *http.Request) {
 		ocsShares = append(ocsShares, folderShares...)
 	}
 
+	if subfiles {
+		children := []*OCSShare{}
+		for _, ocsShare := range ocsShares {
+			if strings.TrimSuffix(ocsShare.Path, "/") != strings.TrimSuffix(originalPath, "/") {
+				children = append(children, ocsShare)
+			}
+		}
+		ocsShares = children
+	}
+
 	meta := &ResponseMeta{Status: "ok", StatusCode: 100}
 	payload := &OCSPayload{Meta: meta, Data: ocsShares}
 	ocsRes := &OCSResponse{OCS: payload}
@@ -3611,15 +4475,37 @@ func (p *proxy) getShares(w http.ResponseWriter, r *http.Request) {
 	w.Write(encoded)
 }
 
-func (p *proxy) getPublicLinkShares(ctx context.Context, onlyForPath string) ([]*OCSShare, error) {
-	gCtx := GetContextWithAuth(ctx)
-
+func (p *proxy) getPublicLinkShares(ctx context.Context, onlyForPath string, recursive bool) ([]*OCSShare, error) {
 	var revaPath string
 	if onlyForPath != "" {
 		revaPath = p.getRevaPath(ctx, onlyForPath)
 	}
 
-	stream, err := p.getShareClient().ListPublicLinks(gCtx, &reva_api.ListPublicLinksReq{Path: revaPath})
+	publicLinks, err := p.listPublicLinks(ctx, revaPath, recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	ocsShares := []*OCSShare{}
+	for _, pl := range publicLinks {
+		ocsShare, err := p.publicLinkToOCSShare(ctx, pl)
+		if err != nil {
+			p.logger.Warn("cannot convert public link to ocs share", zap.Error(err), zap.String("pl", fmt.Sprintf("%+v", pl)))
+			continue
+		}
+		ocsShares = append(ocsShares, ocsShare)
+	}
+	return ocsShares, nil
+
+}
+
+func (p *proxy) listPublicLinks(ctx context.Context, revaPath string, recursive bool) ([]*reva_api.PublicLink, error) {
+	return p.listPublicLinksReq(ctx, &reva_api.ListPublicLinksReq{Path: revaPath, Recursive: recursive})
+}
+
+func (p *proxy) listPublicLinksReq(ctx context.Context, req *reva_api.ListPublicLinksReq) ([]*reva_api.PublicLink, error) {
+	gCtx := GetContextWithAuth(ctx)
+	stream, err := p.getShareClient().ListPublicLinks(gCtx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -3641,18 +4527,7 @@ func (p *proxy) getPublicLinkShares(ctx context.Context, onlyForPath string) ([]
 		publicLinks = append(publicLinks, plr.PublicLink)
 
 	}
-
-	ocsShares := []*OCSShare{}
-	for _, pl := range publicLinks {
-		ocsShare, err := p.publicLinkToOCSShare(ctx, pl)
-		if err != nil {
-			p.logger.Warn("cannot convert public link to ocs share", zap.Error(err), zap.String("pl", fmt.Sprintf("%+v", pl)))
-			continue
-		}
-		ocsShares = append(ocsShares, ocsShare)
-	}
-	return ocsShares, nil
-
+	return publicLinks, nil
 }
 
 func (p *proxy) getSharedMountPath(ctx context.Context, share *reva_api.FolderShare) string {
@@ -3697,15 +4572,37 @@ func (p *proxy) getReceivedFolderShares(ctx context.Context) ([]*OCSShare, error
 
 }
 
-func (p *proxy) getFolderShares(ctx context.Context, onlyForPath string) ([]*OCSShare, error) {
-	gCtx := GetContextWithAuth(ctx)
-
+func (p *proxy) getFolderShares(ctx context.Context, onlyForPath string, recursive bool) ([]*OCSShare, error) {
 	var revaPath string
 	if onlyForPath != "" {
 		revaPath = p.getRevaPath(ctx, onlyForPath)
 	}
 
-	stream, err := p.getShareClient().ListFolderShares(gCtx, &reva_api.ListFolderSharesReq{Path: revaPath})
+	folderShares, err := p.listFolderShares(ctx, revaPath, recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	ocsShares := []*OCSShare{}
+	for _, share := range folderShares {
+		ocsShare, err := p.folderShareToOCSShare(ctx, share)
+		if err != nil {
+			p.logger.Warn("cannot convert folder share to ocs share", zap.Error(err), zap.String("folder share", fmt.Sprintf("%+v", share)))
+			continue
+		}
+		ocsShares = append(ocsShares, ocsShare)
+	}
+	return ocsShares, nil
+
+}
+
+func (p *proxy) listFolderShares(ctx context.Context, revaPath string, recursive bool) ([]*reva_api.FolderShare, error) {
+	return p.listFolderSharesReq(ctx, &reva_api.ListFolderSharesReq{Path: revaPath, Recursive: recursive})
+}
+
+func (p *proxy) listFolderSharesReq(ctx context.Context, req *reva_api.ListFolderSharesReq) ([]*reva_api.FolderShare, error) {
+	gCtx := GetContextWithAuth(ctx)
+	stream, err := p.getShareClient().ListFolderShares(gCtx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -3727,18 +4624,7 @@ func (p *proxy) getFolderShares(ctx context.Context, onlyForPath string) ([]*OCS
 		folderShares = append(folderShares, res.FolderShare)
 
 	}
-
-	ocsShares := []*OCSShare{}
-	for _, share := range folderShares {
-		ocsShare, err := p.folderShareToOCSShare(ctx, share)
-		if err != nil {
-			p.logger.Warn("cannot convert folder share to ocs share", zap.Error(err), zap.String("folder share", fmt.Sprintf("%+v", share)))
-			continue
-		}
-		ocsShares = append(ocsShares, ocsShare)
-	}
-	return ocsShares, nil
-
+	return folderShares, nil
 }
 
 func (p *proxy) receivedFolderShareToOCSShare(ctx context.Context, share *reva_api.FolderShare) (*OCSShare, error) {
@@ -3750,10 +4636,7 @@ func (p *proxy) receivedFolderShareToOCSShare(ctx context.Context, share *reva_a
 	}
 
 	var itemType ItemType = ItemTypeFolder
-	shareType := ShareTypeUser
-	if share.Recipient.Type == reva_api.ShareRecipient_GROUP {
-		shareType = ShareTypeGroup
-	}
+	shareType, shareWith := getOCSShareWith(share.Recipient)
 
 	var mimeType = "httpd/unix-directory"
 	var permissions Permission
@@ -3763,14 +4646,13 @@ func (p *proxy) receivedFolderShareToOCSShare(ctx context.Context, share *reva_a
 		permissions = PermissionReadWrite
 	}
 
-	var shareWith string = share.Recipient.Identity
-
+	ownerDisplayName := p.getDisplayName(ctx, share.OwnerId)
 	targetPath := path.Join(p.ownCloudSharePrefix, share.Target+fmt.Sprintf(" (id:%s)", share.Id))
 	ocsShare := &OCSShare{
 		ShareType:            shareType,
 		ID:                   share.Id,
-		DisplayNameFileOwner: share.OwnerId,
-		DisplayNameOwner:     share.OwnerId,
+		DisplayNameFileOwner: ownerDisplayName,
+		DisplayNameOwner:     ownerDisplayName,
 		FileSource:           md.Id,
 		FileTarget:           targetPath,
 		ItemSource:           md.Id,
@@ -3783,7 +4665,7 @@ func (p *proxy) receivedFolderShareToOCSShare(ctx context.Context, share *reva_a
 		UIDFileOwner:         share.OwnerId,
 		UIDOwner:             share.OwnerId,
 		ShareWith:            &shareWith,
-		ShareWithDisplayName: shareWith,
+		ShareWithDisplayName: p.getShareWithDisplayName(ctx, shareType, shareWith),
 	}
 	return ocsShare, nil
 }
@@ -3798,10 +4680,7 @@ func (p *proxy) folderShareToOCSShare(ctx context.Context, share *reva_api.Folde
 	}
 
 	var itemType ItemType = ItemTypeFolder
-	shareType := ShareTypeUser
-	if share.Recipient.Type == reva_api.ShareRecipient_GROUP {
-		shareType = ShareTypeGroup
-	}
+	shareType, shareWith := getOCSShareWith(share.Recipient)
 
 	var mimeType = "httpd/unix-directory"
 	var permissions Permission
@@ -3811,13 +4690,12 @@ func (p *proxy) folderShareToOCSShare(ctx context.Context, share *reva_api.Folde
 		permissions = PermissionReadWrite
 	}
 
-	var shareWith string = share.Recipient.Identity
-
+	ownerDisplayName := p.getDisplayName(ctx, owner)
 	ocsShare := &OCSShare{
 		ShareType:            shareType,
 		ID:                   share.Id,
-		DisplayNameFileOwner: owner,
-		DisplayNameOwner:     owner,
+		DisplayNameFileOwner: ownerDisplayName,
+		DisplayNameOwner:     ownerDisplayName,
 		FileSource:           md.Id,
 		FileTarget:           md.Path,
 		ItemSource:           md.Id,
@@ -3831,7 +4709,7 @@ func (p *proxy) folderShareToOCSShare(ctx context.Context, share *reva_api.Folde
 		UIDFileOwner:         owner,
 		UIDOwner:             owner,
 		ShareWith:            &shareWith,
-		ShareWithDisplayName: shareWith,
+		ShareWithDisplayName: p.getShareWithDisplayName(ctx, shareType, shareWith),
 	}
 	return ocsShare, nil
 }
@@ -3891,18 +4769,26 @@ func (p *proxy) publicLinkToOCSShare(ctx context.Context, pl *reva_api.PublicLin
 		expiration = t.Format("2006-01-02 03:04:05")
 	}
 
+	var lastAccess string
+	if pl.LastAccess > 0 {
+		lastAccess = time.Unix(int64(pl.LastAccess), 0).Format("2006-01-02 03:04:05")
+	}
+
+	ownerDisplayName := p.getDisplayName(ctx, owner)
 	ocsShare := &OCSShare{
 		ShareType:            ShareTypePublicLink,
 		ID:                   pl.Id,
 		Token:                pl.Token,
-		DisplayNameFileOwner: owner,
-		DisplayNameOwner:     owner,
+		DisplayNameFileOwner: ownerDisplayName,
+		DisplayNameOwner:     ownerDisplayName,
 		FileSource:           md.Id,
 		FileTarget:           md.Path,
 		ItemSource:           md.Id,
 		ItemType:             itemType,
 		MimeType:             mimeType,
 		Name:                 pl.Name,
+		Label:                pl.Name,
+		Labels:               pl.Labels,
 		Path:                 p.joinCBOXMappedPath(ctx, md.Path),
 		Permissions:          permissions,
 		ShareTime:            int(pl.Mtime),
@@ -3913,6 +4799,14 @@ func (p *proxy) publicLinkToOCSShare(ctx context.Context, pl *reva_api.PublicLin
 		ShareWithDisplayName: shareWith,
 		Expiration:           expiration,
 		URL:                  fmt.Sprintf("https://%s/index.php/s/%s", p.overwriteHost, pl.Token),
+		MaxDownloads:         pl.MaxDownloads,
+		MaxVisitors:          pl.MaxVisitors,
+		Downloads:            pl.Downloads,
+		Uploads:              pl.Uploads,
+		Visitors:             pl.Visitors,
+		LastAccess:           lastAccess,
+		UploadPolicy:         pl.UploadPolicy,
+		UploadedBytes:        pl.UploadedBytes,
 	}
 	return ocsShare, nil
 }
@@ -4290,8 +5184,20 @@ func (p *proxy) updateFolderShare(shareID string, readOnly bool, w http.Response
 	w.Write(encoded)
 }
 
-// TODO(labkode): check for updateReadOnly
-func (p *proxy) updatePublicLinkShare(shareID string, newShare *NewShareOCSRequest, updateExpiration, updatePassword, updatePermissions bool, expiration int64, readOnly, dropOnly bool, w http.ResponseWriter, r *http.Request) {
+// TODO(labkode): check for updateReadOnly
+// splitLabels parses the comma separated labels of a form, an empty value
+// gives an empty list so that an update can remove all the labels.
+func splitLabels(v string) []string {
+	labels := []string{}
+	for _, label := range strings.Split(v, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func (p *proxy) updatePublicLinkShare(shareID string, newShare *NewShareOCSRequest, updateExpiration, updatePassword, updatePermissions, updateName bool, expiration int64, readOnly, dropOnly bool, w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	updateLinkReq := &reva_api.UpdateLinkReq{
 		UpdateExpiration: updateExpiration,
@@ -4302,6 +5208,17 @@ func (p *proxy) updatePublicLinkShare(shareID string, newShare *NewShareOCSReque
 		Password:         newShare.Password.Value,
 		Expiration:       uint64(expiration),
 		Id:               shareID,
+
+		UpdateMaxDownloads: newShare.MaxDownloads.Set,
+		MaxDownloads:       uint64(newShare.MaxDownloads.Value),
+		UpdateMaxVisitors:  newShare.MaxVisitors.Set,
+		MaxVisitors:        uint64(newShare.MaxVisitors.Value),
+		UpdateUploadPolicy: newShare.UploadPolicy != nil,
+		UploadPolicy:       newShare.UploadPolicy,
+		UpdateName:         updateName,
+		Name:               newShare.Name,
+		UpdateLabels:       newShare.Labels != nil,
+		Labels:             newShare.Labels,
 	}
 
 	gCtx := GetContextWithAuth(ctx)
@@ -4344,6 +5261,7 @@ func (p *proxy) updateShare(w http.ResponseWriter, r *http.Request) {
 	shareID := mux.Vars(r)["share_id"]
 
 	newShare := &NewShareOCSRequest{}
+	var updateName bool
 	if r.Header.Get("Content-Type") == "application/json" {
 		body, err := ioutil.ReadAll(r.Body)
 		if err != nil {
@@ -4358,6 +5276,7 @@ func (p *proxy) updateShare(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+		updateName = newShare.Name != "" || newShare.Label != ""
 	} else { // assume x-www-form-urlencoded
 		err := r.ParseForm()
 		if err != nil {
@@ -4367,6 +5286,12 @@ func (p *proxy) updateShare(w http.ResponseWriter, r *http.Request) {
 		}
 
 		newShare.ShareWith = r.Form.Get("shareWith")
+		newShare.Name = r.Form.Get("name")
+		newShare.Label = r.Form.Get("label")
+		updateName = len(r.Form["name"]) > 0 || len(r.Form["label"]) > 0
+		if len(r.Form["labels"]) > 0 {
+			newShare.Labels = splitLabels(r.Form.Get("labels"))
+		}
 
 		var shareType ShareType
 		shareTypeString := r.Form.Get("shareType")
@@ -4376,6 +5301,8 @@ func (p *proxy) updateShare(w http.ResponseWriter, r *http.Request) {
 			shareType = ShareTypeGroup
 		} else if shareTypeString == "3" {
 			shareType = ShareTypePublicLink
+		} else if shareTypeString == "6" {
+			shareType = ShareTypeRemote
 		}
 		newShare.ShareType = shareType
 
@@ -4412,6 +5339,21 @@ func (p *proxy) updateShare(w http.ResponseWriter, r *http.Request) {
 		}
 		newShare.ExpireDate = expireDateJSON
 
+		if newShare.MaxDownloads, err = getFormJSONInt(r, "maxDownloads"); err != nil {
+			p.logger.Error("", zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if newShare.MaxVisitors, err = getFormJSONInt(r, "maxVisitors"); err != nil {
+			p.logger.Error("", zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if newShare.UploadPolicy, err = getFormUploadPolicy(r); err != nil {
+			p.logger.Error("", zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
 	}
 
 	var readOnly bool = true
@@ -4448,6 +5390,9 @@ func (p *proxy) updateShare(w http.ResponseWriter, r *http.Request) {
 
 	updatePassword := newShare.Password.Set
 	updatePermissions := newShare.Permissions.Set
+	if newShare.Name == "" {
+		newShare.Name = newShare.Label
+	}
 
 	found, err := p.isPublicLinkShare(ctx, shareID)
 	if err != nil {
@@ -4456,7 +5401,7 @@ func (p *proxy) updateShare(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if found {
-		p.updatePublicLinkShare(shareID, newShare, updateExpiration, updatePassword, updatePermissions, expiration, readOnly, dropOnly, w, r)
+		p.updatePublicLinkShare(shareID, newShare, updateExpiration, updatePassword, updatePermissions, updateName, expiration, readOnly, dropOnly, w, r)
 		return
 	}
 
@@ -4517,9 +5462,122 @@ func (p *proxy) writeError(status reva_api.StatusCode, w http.ResponseWriter, r
 		w.WriteHeader(http.StatusForbidden)
 		return
 	}
+	if status == reva_api.StatusCode_REMOTE_SHARE_NOT_FOUND {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if status == reva_api.StatusCode_PUBLIC_LINK_TOKEN_ALREADY_EXISTS {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if status == reva_api.StatusCode_PUBLIC_LINK_INVALID_TOKEN {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if status == reva_api.StatusCode_PUBLIC_LINK_INVALID_NAME {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if status == reva_api.StatusCode_PUBLIC_LINK_LIMIT_REACHED {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if status == reva_api.StatusCode_PUBLIC_LINK_UPLOAD_TOO_LARGE {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if status == reva_api.StatusCode_PUBLIC_LINK_UPLOAD_TYPE_NOT_ALLOWED {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	if status == reva_api.StatusCode_PUBLIC_LINK_UPLOADER_NAME_REQUIRED {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if status == reva_api.StatusCode_FOLDER_SHARE_INVALID_TARGET {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if status == reva_api.StatusCode_NOTIFICATION_INVALID_RECIPIENT {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if status == reva_api.StatusCode_APP_PASSWORD_NOT_FOUND {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if status == reva_api.StatusCode_PERMISSION_DENIED {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if status == reva_api.StatusCode_PATH_INVALID {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if status == reva_api.StatusCode_STORAGE_NOT_SUPPORTED {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
 	w.WriteHeader(http.StatusInternalServerError)
 }
 
+// recordPublicLinkDownload counts the download of the public link of the request, if any,
+// returning false if the link does not allow more downloads and the error was written.
+func (p *proxy) recordPublicLinkDownload(w http.ResponseWriter, r *http.Request) bool {
+	ctx := r.Context()
+	pl, ok := reva_api.ContextGetPublicLink(ctx)
+	if !ok {
+		return true
+	}
+
+	res, err := p.getShareClient().RecordPublicLinkDownload(GetContextWithAuth(ctx), &reva_api.ShareIDReq{Id: pl.Id})
+	if err != nil {
+		p.logger.Error("error recording public link download", zap.Error(err), zap.String("id", pl.Id))
+		w.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
+	if res.Status != reva_api.StatusCode_OK {
+		p.writeError(res.Status, w, r)
+		return false
+	}
+	return true
+}
+
+// getClientIP returns the IP of the client that sent the request.
+// The headers set by the reverse proxies are only honoured when the connection comes
+// from a trusted proxy, as any client can send them. The X-Forwarded-For addresses
+// are read from the right, the first one that is not a trusted proxy is the client,
+// the ones on its left could have been sent by the client.
+func (p *proxy) getClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !p.isTrustedProxy(ip) {
+		return ip
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-Ip")); realIP != "" {
+		return realIP
+	}
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := strings.TrimSpace(forwarded[i])
+		if forwardedIP == "" {
+			continue
+		}
+		ip = forwardedIP
+		if !p.isTrustedProxy(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+func (p *proxy) isTrustedProxy(ip string) bool {
+	return reva_api.IsTrustedProxy(p.trustedProxies, ip)
+}
+
 func getUserFromContext(ctx context.Context) (*reva_api.User, error) {
 	u, ok := reva_api.ContextGetUser(ctx)
 	if !ok {
@@ -4537,6 +5595,8 @@ const (
 	ShareTypeUser       ShareType = 0
 	ShareTypeGroup                = 1
 	ShareTypePublicLink           = 3
+	ShareTypeGuest                = 4 // share by e-mail in ownCloud
+	ShareTypeRemote               = 6
 
 	PermissionRead      Permission = 1
 	PermissionReadWrite Permission = 15
@@ -4573,6 +5633,61 @@ type JSONInt struct {
 	Set   bool
 }
 
+// getFormJSONInt returns the integer form value of the key, which is
+// only set if the key is present in the form.
+func getFormJSONInt(r *http.Request, key string) (JSONInt, error) {
+	v := JSONInt{}
+	if len(r.Form[key]) == 0 || r.Form.Get(key) == "" {
+		return v, nil
+	}
+	i, err := strconv.ParseInt(r.Form.Get(key), 10, 64)
+	if err != nil {
+		return v, err
+	}
+	if i < 0 {
+		return v, fmt.Errorf("%s cannot be negative", key)
+	}
+	v.Value = int(i)
+	v.Valid = true
+	v.Set = true
+	return v, nil
+}
+
+// getFormUploadPolicy returns the upload policy given in the form, or nil if none of its fields is set.
+func getFormUploadPolicy(r *http.Request) (*reva_api.UploadPolicy, error) {
+	keys := []string{"maxFileSize", "maxTotalSize", "allowedTypes", "uploaderFolders", "uploadDigest"}
+	set := false
+	for _, key := range keys {
+		if len(r.Form[key]) > 0 {
+			set = true
+		}
+	}
+	if !set {
+		return nil, nil
+	}
+
+	policy := &reva_api.UploadPolicy{}
+	maxFileSize, err := getFormJSONInt(r, "maxFileSize")
+	if err != nil {
+		return nil, err
+	}
+	policy.MaxFileSize = uint64(maxFileSize.Value)
+	maxTotalSize, err := getFormJSONInt(r, "maxTotalSize")
+	if err != nil {
+		return nil, err
+	}
+	policy.MaxTotalSize = uint64(maxTotalSize.Value)
+
+	for _, t := range strings.Split(r.Form.Get("allowedTypes"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			policy.AllowedTypes = append(policy.AllowedTypes, t)
+		}
+	}
+	policy.UploaderFolders = r.Form.Get("uploaderFolders") == "true" || r.Form.Get("uploaderFolders") == "1"
+	policy.Digest = r.Form.Get("uploadDigest") == "true" || r.Form.Get("uploadDigest") == "1"
+	return policy, nil
+}
+
 type JSONString struct {
 	Value string
 	Valid bool
@@ -4619,16 +5734,66 @@ func (i *JSONInt) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-type LDAPAccountType string
+// minGuestPasswordLength is the minimum length of the passwords chosen by the guests.
+const minGuestPasswordLength = 8
 
-var (
-	LDAPAccountTypePrimary   LDAPAccountType = "primary"
-	LDAPAccountTypeSecondary LDAPAccountType = "secondary"
-	LDAPAccountTypeService   LDAPAccountType = "service"
-	LDAPAccountTypeEGroup    LDAPAccountType = "egroup"
-	LDAPAccountTypeUnixGroup LDAPAccountType = "unixgroup"
-	LDAPAccountTypeUndefined LDAPAccountType = "undefined"
-)
+// renderGuestActivation shows the form where an invited guest chooses its password
+// with the token received in the invitation, the token can only be used once.
+func (p *proxy) renderGuestActivation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tpl, err := template.New("guest_activation").Parse(guestActivationTemplate)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]string{"Token": r.URL.Query().Get("token"), "Warning": "", "Email": ""}
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	if r.Method != "POST" {
+		tpl.Execute(w, data)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	data["Token"] = r.Form.Get("token")
+	password := r.Form.Get("password")
+
+	if len(password) < minGuestPasswordLength {
+		data["Warning"] = fmt.Sprintf("The password must have at least %d characters", minGuestPasswordLength)
+		tpl.Execute(w, data)
+		return
+	}
+	if password != r.Form.Get("password-confirmation") {
+		data["Warning"] = "The passwords do not match"
+		tpl.Execute(w, data)
+		return
+	}
+
+	res, err := p.getAuthClient().ActivateGuest(ctx, &reva_api.ActivateGuestReq{Token: data["Token"], Password: password})
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if res.Status == reva_api.StatusCode_GUEST_INVALID_TOKEN {
+		data["Warning"] = "The activation link is not valid or has expired, ask the owner of the share for a new invitation"
+		w.WriteHeader(http.StatusBadRequest)
+		tpl.Execute(w, data)
+		return
+	}
+	if res.Status != reva_api.StatusCode_OK {
+		p.writeError(res.Status, w, r)
+		return
+	}
+
+	data["Email"] = res.User.AccountId
+	tpl.Execute(w, data)
+}
 
 func (p *proxy) renderPublicLink(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -4645,7 +5810,7 @@ func (p *proxy) renderPublicLink(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := p.getAuthClient()
-	res, err := client.ForgePublicLinkToken(ctx, &reva_api.ForgePublicLinkTokenReq{Token: token, Password: password})
+	res, err := client.ForgePublicLinkToken(ctx, &reva_api.ForgePublicLinkTokenReq{Token: token, Password: password, ClientIp: p.getClientIP(r)})
 	if err != nil {
 		// render link not found template
 		p.logger.Error("", zap.Error(err))
@@ -4661,7 +5826,12 @@ func (p *proxy) renderPublicLink(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		tpl.Execute(w, nil)
+		data := map[string]string{"Warning": ""}
+		if res.Status == reva_api.StatusCode_PUBLIC_LINK_LOCKED {
+			data["Warning"] = "Too many wrong passwords, please try again later"
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+		tpl.Execute(w, data)
 		return
 	}
 
@@ -4682,6 +5852,7 @@ func (p *proxy) renderPublicLink(w http.ResponseWriter, r *http.Request) {
 	pl := res2.PublicLink
 	ctx = reva_api.ContextSetPublicLink(ctx, pl)
 	ctx = reva_api.ContextSetPublicLinkToken(ctx, res.Token)
+	ctx = reva_api.ContextSetClientIP(ctx, p.getClientIP(r))
 
 	revaPath := p.getRevaPath(ctx, "/")
 
@@ -5227,6 +6398,10 @@ func (p *proxy) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !p.recordPublicLinkDownload(w, r) {
+		return
+	}
+
 	stream, err := p.getStorageClient().ReadFile(gCtx, gReq)
 	if err != nil {
 		p.logger.Error("", zap.Error(err))
@@ -5469,6 +6644,9 @@ func (p *proxy) move(w http.ResponseWriter, r *http.Request) {
 	gCtx := GetContextWithAuth(ctx)
 	oldRevaPath := p.getRevaPath(ctx, oldPath)
 	destinationRevaPath := p.getRevaPath(ctx, destinationPath)
+	if revaPath, ok := p.getRenamedShareRevaPath(ctx, oldPath, destinationPath); ok {
+		destinationRevaPath = revaPath
+	}
 	gReq := &reva_api.MoveReq{OldPath: oldRevaPath, NewPath: destinationRevaPath}
 	emptyRes, err := p.getStorageClient().Move(gCtx, gReq)
 	if err != nil {
@@ -6068,6 +7246,18 @@ func (p *proxy) propfind(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// the share indicators are expensive to compute, so they are only
+	// added when the client asks for them.
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		p.logger.Error("", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if bytes.Contains(body, []byte("share-types")) {
+		p.setShareIndicators(ctx, mds)
+	}
+
 	mdsInXML, err := p.mdsToXML(ctx, mds)
 	if err != nil {
 		p.logger.Error("", zap.Error(err))
@@ -6081,6 +7271,72 @@ func (p *proxy) propfind(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(mdsInXML))
 }
 
+// setShareIndicators sets the kinds of shares of every resource in mds, looking
+// up the shares and public links of the user on these resources only.
+func (p *proxy) setShareIndicators(ctx context.Context, mds []*reva_api.Metadata) {
+	fileIDs := []string{}
+	folderIDs := []string{}
+	for _, md := range mds {
+		fileIDs = append(fileIDs, md.Id)
+		if md.IsDir {
+			folderIDs = append(folderIDs, md.Id)
+			if md.MigId != "" {
+				folderIDs = append(folderIDs, md.MigId)
+			}
+		}
+	}
+
+	shareTypes := map[string][]string{}
+	var folderShares []*reva_api.FolderShare
+	var err error
+	if len(folderIDs) > 0 {
+		folderShares, err = p.listFolderSharesReq(ctx, &reva_api.ListFolderSharesReq{FileIds: folderIDs})
+	}
+	if err != nil {
+		p.logger.Warn("error listing folder shares for share indicators", zap.Error(err))
+	}
+	for _, share := range folderShares {
+		shareTypes[share.Path] = append(shareTypes[share.Path], strings.ToLower(share.Recipient.Type.String()))
+	}
+
+	publicLinks, err := p.listPublicLinksReq(ctx, &reva_api.ListPublicLinksReq{FileIds: fileIDs})
+	if err != nil {
+		p.logger.Warn("error listing public links for share indicators", zap.Error(err))
+	}
+	for _, pl := range publicLinks {
+		shareTypes[pl.Path] = append(shareTypes[pl.Path], "link")
+	}
+
+	for _, md := range mds {
+		md.ShareTypes = append(shareTypes[md.Id], shareTypes[md.MigId]...)
+	}
+}
+
+// getOCShareTypes converts the share indicators of a resource to
+// the share types understood by the ownCloud clients.
+func getOCShareTypes(shareTypes []string) []ShareType {
+	seen := map[ShareType]bool{}
+	ocShareTypes := []ShareType{}
+	for _, st := range shareTypes {
+		var ocShareType ShareType
+		switch st {
+		case "group", "unix":
+			ocShareType = ShareTypeGroup
+		case "remote":
+			ocShareType = ShareTypeRemote
+		case "link":
+			ocShareType = ShareTypePublicLink
+		default:
+			ocShareType = ShareTypeUser
+		}
+		if !seen[ocShareType] {
+			seen[ocShareType] = true
+			ocShareTypes = append(ocShareTypes, ocShareType)
+		}
+	}
+	return ocShareTypes
+}
+
 func (p *proxy) isChunkedUpload(path string) (bool, error) {
 	return regexp.MatchString(`-chunking-\w+-[0-9]+-[0-9]+$`, path)
 }
@@ -6313,6 +7569,15 @@ func (p *proxy) mdToPropResponse(ctx context.Context, md *reva_api.Metadata, pro
 
 	propList = append(propList, getResourceType, getContentLegnth, getContentType, getLastModified, // general WebDAV properties
 		getETag /*quotaAvailableBytes, quotaUsedBytes,*/, ocID, ocDownloadURL, ocDC, ocPermissions) // properties needed by ownCloud
+	if len(md.ShareTypes) > 0 {
+		var shareTypesXML bytes.Buffer
+		for _, st := range getOCShareTypes(md.ShareTypes) {
+			shareTypesXML.WriteString(fmt.Sprintf("<oc:share-type>%d</oc:share-type>", st))
+		}
+		ocShareTypes := propertyXML{xml.Name{Space: "", Local: "oc:share-types"},
+			"", shareTypesXML.Bytes()}
+		propList = append(propList, ocShareTypes)
+	}
 	propList = append(propList, props...)
 
 	// PropStat, only HTTP/1.1 200 is sent.
@@ -6480,6 +7745,13 @@ func getMD5Hash(text string) string {
 func GetContextWithAuth(ctx context.Context) context.Context {
 	if token, ok := reva_api.ContextGetPublicLinkToken(ctx); ok && token != "" {
 		header := metadata.New(map[string]string{"authorization": "pl-bearer " + token})
+		if ip, ok := reva_api.ContextGetClientIP(ctx); ok && ip != "" {
+			header.Set("x-client-ip", ip)
+		}
+		if name, ok := reva_api.ContextGetUploaderName(ctx); ok && name != "" {
+			// metadata values must be ascii, the name is unescaped by revad
+			header.Set("x-uploader-name", url.PathEscape(name))
+		}
 		return metadata.NewOutgoingContext(ctx, header)
 	}
 
@@ -6497,7 +7769,7 @@ func (p *proxy) publicLinkAuth(h http.HandlerFunc) http.HandlerFunc {
 
 		// try to authenticate the link with empty password
 		client := p.getAuthClient()
-		res, err := client.ForgePublicLinkToken(ctx, &reva_api.ForgePublicLinkTokenReq{Token: token, Password: ""})
+		res, err := client.ForgePublicLinkToken(ctx, &reva_api.ForgePublicLinkTokenReq{Token: token, Password: "", ClientIp: p.getClientIP(r)})
 		if err == nil && res.Status == reva_api.StatusCode_OK {
 			// inject token in request
 			r.Header.Set("x-access-token", res.Token)
@@ -6518,32 +7790,68 @@ func (p *proxy) tokenAuth(h http.HandlerFunc) http.HandlerFunc {
 		// 1st: check if token comes from header
 		token := r.Header.Get("X-Access-Token")
 
-		// 2nd: check if token comes from query parameter
-		if token == "" {
+		// 2nd: check if token comes from query parameter, except for the apps,
+		// as their urls end up in the history of the browsers and in the logs
+		if token == "" && !strings.HasPrefix(r.URL.Path, "/index.php/apps/") {
 			token = r.URL.Query().Get("x-access-token")
 		}
 
 		// 3rd: check basic auth
 		// the request public.php/webdav sends the public link token as basic auth, so
 		// we cannot use basic auth first as it will try to authorize a non existing user, reducing the performance
-		if token == "" {
+		// remote servers access the federated shares through public.php/webdav
+		// sending the share token as basic auth user name
+		if token == "" && strings.HasPrefix(r.URL.Path, "/public.php/webdav") {
 			if username, password, ok := r.BasicAuth(); ok {
-				req := &reva_api.ForgeUserTokenReq{ClientId: username, ClientSecret: password}
-				res, err := authClient.ForgeUserToken(ctx, req)
-				if err != nil {
-					p.logger.Warn("error authentication user with basic auth", zap.String("username", username), zap.Error(err))
+				res, err := authClient.ForgePublicLinkToken(ctx, &reva_api.ForgePublicLinkTokenReq{Token: username, Password: password, ClientIp: p.getClientIP(r)})
+				if err == nil && res.Status == reva_api.StatusCode_PUBLIC_LINK_LOCKED {
+					p.logger.Warn("public link locked", zap.String("token", username))
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				if err != nil || res.Status != reva_api.StatusCode_OK {
+					p.logger.Warn("error authenticating public link with basic auth", zap.String("token", username), zap.Error(err))
 					w.WriteHeader(http.StatusUnauthorized)
 					return
 				}
+				token = res.Token
+			}
+		}
 
-				if res.Status != reva_api.StatusCode_OK {
-					p.logger.Warn("grpc auth req failed", zap.String("username", username), zap.Int("code", int(res.Status)))
+		// 4th: check for an access token of the OpenID Connect issuer, exchanged for a reva token
+		if token == "" {
+			if authHeader := r.Header.Get("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+				req := &reva_api.ForgeUserTokenReq{ClientId: reva_api.OIDCClientID, ClientSecret: strings.TrimPrefix(authHeader, "Bearer ")}
+				res, err := authClient.ForgeUserToken(ctx, req)
+				if err != nil || res.Status != reva_api.StatusCode_OK {
+					p.logger.Warn("error authenticating user with bearer token", zap.Error(err))
 					w.WriteHeader(http.StatusUnauthorized)
 					return
 				}
-
 				token = res.Token
-				p.logger.Info("x-access-token generated from basic auth", zap.String("username", username))
+				p.logger.Info("x-access-token generated from bearer token")
+			}
+		}
+
+		// the clients send the credentials on every request, the token minted for them is cached
+		// to not authenticate the user against the auth manager again and again
+		var basicAuthKey string
+		var basicAuthCached bool
+		if token == "" {
+			if username, password, ok := r.BasicAuth(); ok {
+				basicAuthKey = p.getBasicAuthCacheKey(username, password)
+				if v, err := p.basicAuthCache.Get(basicAuthKey); err == nil {
+					basicAuthCacheHits.Inc()
+					token = v.(string)
+					basicAuthCached = true
+				} else {
+					basicAuthCacheMisses.Inc()
+					token, err = p.forgeBasicAuthToken(ctx, basicAuthKey, username, password)
+					if err != nil {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+				}
 			}
 
 		}
@@ -6556,6 +7864,20 @@ func (p *proxy) tokenAuth(h http.HandlerFunc) http.HandlerFunc {
 
 		// try with user token
 		userRes, err := authClient.DismantleUserToken(ctx, &reva_api.TokenReq{Token: token})
+		if basicAuthKey != "" && (err != nil || userRes.Status != reva_api.StatusCode_OK) {
+			// the token expired or has been revoked
+			p.basicAuthCache.Remove(basicAuthKey)
+			if basicAuthCached {
+				basicAuthCacheInvalidations.Inc()
+				username, password, _ := r.BasicAuth()
+				token, err = p.forgeBasicAuthToken(ctx, basicAuthKey, username, password)
+				if err != nil {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				userRes, err = authClient.DismantleUserToken(ctx, &reva_api.TokenReq{Token: token})
+			}
+		}
 		if err == nil && userRes.Status == reva_api.StatusCode_OK {
 			user := userRes.User
 			ctx = reva_api.ContextSetUser(ctx, user)
@@ -6581,6 +7903,11 @@ func (p *proxy) tokenAuth(h http.HandlerFunc) http.HandlerFunc {
 		pl := res.PublicLink
 		ctx = reva_api.ContextSetPublicLink(ctx, pl)
 		ctx = reva_api.ContextSetPublicLinkToken(ctx, token)
+		ctx = reva_api.ContextSetClientIP(ctx, p.getClientIP(r))
+		// the web UI asks the name of the uploader for the links that put the uploads in a folder per uploader
+		if name, err := url.PathUnescape(r.Header.Get("X-Uploader-Name")); err == nil && name != "" {
+			ctx = reva_api.ContextSetUploaderName(ctx, name)
+		}
 		r = r.WithContext(ctx)
 		p.logger.Info("authenticated with public link token", zap.String("token", pl.Token))
 		h(w, r)
@@ -6588,6 +7915,38 @@ func (p *proxy) tokenAuth(h http.HandlerFunc) http.HandlerFunc {
 	})
 }
 
+// forgeBasicAuthToken authenticates the user with the basic auth credentials
+// and caches the token under key.
+func (p *proxy) forgeBasicAuthToken(ctx context.Context, key, username, password string) (string, error) {
+	req := &reva_api.ForgeUserTokenReq{ClientId: username, ClientSecret: password}
+	res, err := p.getAuthClient().ForgeUserToken(ctx, req)
+	if err != nil {
+		p.logger.Warn("error authentication user with basic auth", zap.String("username", username), zap.Error(err))
+		return "", err
+	}
+
+	if res.Status != reva_api.StatusCode_OK {
+		p.logger.Warn("grpc auth req failed", zap.String("username", username), zap.Int("code", int(res.Status)))
+		return "", reva_api.NewError(reva_api.UnknownError).WithMessage(fmt.Sprintf("auth req failed with code %d", res.Status))
+	}
+
+	if err := p.basicAuthCache.SetWithExpire(key, res.Token, p.basicAuthCacheTTL); err != nil {
+		p.logger.Warn("error caching basic auth token", zap.String("username", username), zap.Error(err))
+	}
+	p.logger.Info("x-access-token generated from basic auth", zap.String("username", username))
+	return res.Token, nil
+}
+
+// getBasicAuthCacheKey returns the key of the basic auth cache for the credentials,
+// the password cannot be recovered from it.
+func (p *proxy) getBasicAuthCacheKey(username, password string) string {
+	mac := hmac.New(sha256.New, p.basicAuthCacheKey)
+	mac.Write([]byte(username))
+	mac.Write([]byte{0})
+	mac.Write([]byte(password))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func (p *proxy) getRevaPath(ctx context.Context, ocPath string) string {
 	var revaPath string
 
@@ -6626,6 +7985,21 @@ func (p *proxy) getRevaPath(ctx context.Context, ocPath string) string {
 			}
 
 			revaPath = path.Join(p.revaSharePrefix, revaPath)
+		} else if strings.HasPrefix(ocPath, p.ownCloudRemoteSharePrefix) {
+			revaPath = strings.TrimPrefix(ocPath, p.ownCloudRemoteSharePrefix)
+			// remove the name of the remote share before contacting reva
+			revaPath = strings.TrimPrefix(revaPath, "/")
+			tokens := strings.Split(revaPath, "/")
+			_, id, err := p.splitRootPath(ctx, tokens[0])
+			if err != nil {
+				p.logger.Error("error removing remote share name from ocPath", zap.Error(err), zap.String("ocPath", ocPath))
+			}
+			revaPath = path.Join("/", id)
+			if len(tokens) > 1 {
+				revaPath = path.Join(revaPath, path.Join(tokens[1:]...))
+			}
+
+			revaPath = path.Join(p.revaRemoteSharePrefix, revaPath)
 		} else if strings.HasPrefix(ocPath, p.ownCloudPersonalProjectsPrefix) {
 			revaPath = strings.TrimPrefix(ocPath, p.ownCloudPersonalProjectsPrefix)
 			revaPath = path.Join(p.revaPersonalProjectsPrefix, revaPath)
@@ -6676,6 +8050,13 @@ func (p *proxy) getOCPath(ctx context.Context, md *reva_api.Metadata) string {
 			tokens[0] = p.addShareTarget(ctx, tokens[0], md)
 			ocPath = path.Join("/", path.Join(tokens...))
 			ocPath = path.Join(p.ownCloudSharePrefix, ocPath)
+		} else if strings.HasPrefix(revaPath, p.revaRemoteSharePrefix) {
+			ocPath = strings.TrimPrefix(revaPath, p.revaRemoteSharePrefix)
+			ocPath = strings.TrimPrefix(ocPath, "/")
+			tokens := strings.Split(ocPath, "/")
+			tokens[0] = p.addShareTarget(ctx, tokens[0], md)
+			ocPath = path.Join("/", path.Join(tokens...))
+			ocPath = path.Join(p.ownCloudRemoteSharePrefix, ocPath)
 		} else {
 			if strings.HasPrefix(revaPath, p.revaHomePrefix) {
 				ocPath = strings.TrimPrefix(revaPath, p.revaHomePrefix)
@@ -6706,6 +8087,44 @@ func (p *proxy) splitRootPath(ctx context.Context, path string) (string, string,
 	return targetName, shareID, nil
 }
 
+// getRenamedShareRevaPath returns the reva path for a received share that is renamed by the recipient.
+// The clients send the new name without the id of the share, so it is taken from the old path.
+// If the new name carries an id it is kept, even if it is the one of another share,
+// so the storage rejects the move of a share onto another one.
+func (p *proxy) getRenamedShareRevaPath(ctx context.Context, oldPath, newPath string) (string, bool) {
+	oldName, ok := p.getShareRootName(oldPath)
+	if !ok {
+		return "", false
+	}
+	newName, ok := p.getShareRootName(newPath)
+	if !ok {
+		return "", false
+	}
+
+	_, id, err := p.splitRootPath(ctx, oldName)
+	if err != nil {
+		return "", false
+	}
+
+	target := newName
+	if newTarget, newID, err := p.splitRootPath(ctx, newName); err == nil {
+		target, id = newTarget, newID
+	}
+	return path.Join(p.revaSharePrefix, fmt.Sprintf("%s (id:%s)", strings.TrimSpace(target), id)), true
+}
+
+// getShareRootName returns the name of the mount point of a received share if ocPath points to it.
+func (p *proxy) getShareRootName(ocPath string) (string, bool) {
+	if !strings.HasPrefix(ocPath, p.ownCloudSharePrefix+"/") {
+		return "", false
+	}
+	name := strings.Trim(strings.TrimPrefix(ocPath, p.ownCloudSharePrefix), "/")
+	if name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
 func (p *proxy) addShareTarget(ctx context.Context, id string, md *reva_api.Metadata) string {
 	return fmt.Sprintf("%s (id:%s)", md.ShareTarget, id)
 
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	reva_api "github.com/cernbox/revaold/api"
+
+	"github.com/bluele/gcache"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// fakeAuthServer forges a new token on every login and accepts the tokens not revoked.
+type fakeAuthServer struct {
+	reva_api.AuthServer
+	forged int
+	valid  map[string]bool
+}
+
+func (s *fakeAuthServer) ForgeUserToken(ctx context.Context, req *reva_api.ForgeUserTokenReq) (*reva_api.TokenResponse, error) {
+	if req.ClientSecret != "relativity" {
+		return &reva_api.TokenResponse{Status: reva_api.StatusCode_USER_NOT_FOUND}, nil
+	}
+	s.forged++
+	token := fmt.Sprintf("%s-%d", req.ClientId, s.forged)
+	s.valid[token] = true
+	return &reva_api.TokenResponse{Status: reva_api.StatusCode_OK, Token: token}, nil
+}
+
+func (s *fakeAuthServer) DismantleUserToken(ctx context.Context, req *reva_api.TokenReq) (*reva_api.UserResponse, error) {
+	if !s.valid[req.Token] {
+		return &reva_api.UserResponse{Status: reva_api.StatusCode_TOKEN_INVALID}, nil
+	}
+	return &reva_api.UserResponse{Status: reva_api.StatusCode_OK, User: &reva_api.User{AccountId: "einstein"}}, nil
+}
+
+func (s *fakeAuthServer) DismantlePublicLinkToken(ctx context.Context, req *reva_api.TokenReq) (*reva_api.PublicLinkResponse, error) {
+	return nil, reva_api.NewError(reva_api.TokenInvalidErrorCode)
+}
+
+func newTestProxy(t *testing.T) (*proxy, *fakeAuthServer, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	as := &fakeAuthServer{valid: map[string]bool{}}
+	server := grpc.NewServer()
+	reva_api.RegisterAuthServer(server, as)
+	go server.Serve(lis)
+
+	globalConn = nil
+	p := &proxy{
+		logger:            zap.NewNop(),
+		revaHost:          lis.Addr().String(),
+		basicAuthCache:    gcache.New(10).LRU().Build(),
+		basicAuthCacheTTL: time.Minute,
+		basicAuthCacheKey: []byte("key"),
+	}
+	return p, as, func() {
+		if globalConn != nil {
+			globalConn.Close()
+			globalConn = nil
+		}
+		server.Stop()
+	}
+}
+
+// get requests the webdav endpoint with the credentials and returns the status and the token used.
+func get(p *proxy, password string) (int, string) {
+	var token string
+	router := mux.NewRouter()
+	router.HandleFunc("/remote.php/webdav{path:.*}", p.tokenAuth(func(w http.ResponseWriter, r *http.Request) {
+		token, _ = reva_api.ContextGetAccessToken(r.Context())
+	}))
+	r := httptest.NewRequest("GET", "/remote.php/webdav/", nil)
+	r.SetBasicAuth("einstein", password)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w.Code, token
+}
+
+func TestBasicAuthTokensAreCached(t *testing.T) {
+	p, as, stop := newTestProxy(t)
+	defer stop()
+
+	// the first request is a miss and forges a token, the second one reuses it
+	if code, token := get(p, "relativity"); code != http.StatusOK || token != "einstein-1" {
+		t.Fatalf("expected the forged token, got %d %q", code, token)
+	}
+	if code, token := get(p, "relativity"); code != http.StatusOK || token != "einstein-1" || as.forged != 1 {
+		t.Errorf("expected the cached token, got %d %q after %d logins", code, token, as.forged)
+	}
+
+	// the credentials are part of the key, a wrong password is not served from the cache
+	if code, _ := get(p, "gravity"); code != http.StatusUnauthorized {
+		t.Errorf("expected a wrong password to be rejected, got %d", code)
+	}
+	if p.getBasicAuthCacheKey("einstein", "relativity") == p.getBasicAuthCacheKey("einstein", "gravity") {
+		t.Error("expected the keys of different passwords to differ")
+	}
+}
+
+func TestRevokedBasicAuthTokensAreForgedAgain(t *testing.T) {
+	p, as, stop := newTestProxy(t)
+	defer stop()
+
+	if code, _ := get(p, "relativity"); code != http.StatusOK {
+		t.Fatalf("expected the user to be authenticated, got %d", code)
+	}
+	delete(as.valid, "einstein-1")
+	if code, token := get(p, "relativity"); code != http.StatusOK || token != "einstein-2" {
+		t.Errorf("expected a token forged again, got %d %q", code, token)
+	}
+	v, err := p.basicAuthCache.Get(p.getBasicAuthCacheKey("einstein", "relativity"))
+	if err != nil || v.(string) != "einstein-2" {
+		t.Errorf("expected the new token in the cache, got %v %v", v, err)
+	}
+}
+
+func TestExpiredBasicAuthTokensAreForgedAgain(t *testing.T) {
+	p, as, stop := newTestProxy(t)
+	defer stop()
+	p.basicAuthCacheTTL = time.Millisecond
+
+	if code, _ := get(p, "relativity"); code != http.StatusOK {
+		t.Fatalf("expected the user to be authenticated, got %d", code)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if code, token := get(p, "relativity"); code != http.StatusOK || token != "einstein-2" || as.forged != 2 {
+		t.Errorf("expected a token forged after the expiration, got %d %q after %d logins", code, token, as.forged)
+	}
+}
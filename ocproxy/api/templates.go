@@ -386,6 +386,7 @@ var publicLinkTemplatePassword = `
         <form method="post">
           <fieldset>
             <div class="warning-info">This share is password-protected</div>
+            {{ if .Warning }}<div class="warning">{{ .Warning }}</div>{{ end }}
             <p>
               <label for="password" class="infield">Password</label>
               <input type="hidden" name="requesttoken" value="GxEhFD47HA8bZlM1MyQfGChWczklBDM4KyQVO18ATHE=:hvNGFqhLL7cYAVIiqo+qr1KMyTWijp62F95Hkn7Nxs0=" />
@@ -962,3 +963,82 @@ var publicLinkDropOnly = `
 	</footer>
 </html>
 `
+
+var guestActivationTemplate = `
+<!DOCTYPE html>
+<html class="ng-csp" data-placeholder-focus="false" lang="en" >
+  <head>
+    <meta charset="utf-8">
+    <title>
+      CERNBox
+    </title>
+    <meta http-equiv="X-UA-Compatible" content="IE=edge">
+    <meta name="referrer" content="never">
+    <meta name="viewport" content="width=device-width, minimum-scale=1.0, maximum-scale=1.0">
+    <meta name="theme-color" content="#1d2d44">
+    <link rel="icon" href="/core/img/favicon.ico">
+    <link rel="apple-touch-icon-precomposed" href="/core/img/favicon-touch.png">
+    <link rel="mask-icon" sizes="any" href="/core/img/favicon-mask.svg" color="#1d2d44">
+    <link rel="stylesheet" href="/core/css/styles.css">
+    <link rel="stylesheet" href="/core/css/inputs.css">
+    <link rel="stylesheet" href="/core/css/header.css">
+    <link rel="stylesheet" href="/core/css/icons.css">
+    <link rel="stylesheet" href="/core/css/fonts.css">
+    <link rel="stylesheet" href="/core/css/global.css">
+    <link rel="stylesheet" href="/core/css/fixes.css">
+    <link rel="stylesheet" href="/core/css/mobile.css">
+    <link rel="stylesheet" href="/apps/cernbox-theme/core/css/styles.css">
+  </head>
+  <body id="body-login">
+    <div class="wrapper">
+      <div class="v-align">
+        <header role="banner">
+          <div id="header">
+            <div class="logo">
+              <h1 class="hidden-visually">
+              </h1>
+            </div>
+            <div id="logo-claim" style="display:none;"></div>
+          </div>
+        </header>
+        {{ if .Email }}
+        <fieldset>
+          <div class="warning-info">Your guest account {{ .Email }} is active</div>
+          <p>
+            You can now <a href="/">log in</a> with your e-mail address and the password you have chosen.
+          </p>
+        </fieldset>
+        {{ else }}
+        <form method="post">
+          <fieldset>
+            <div class="warning-info">Choose a password to activate your guest account</div>
+            {{ if .Warning }}<div class="warning">{{ .Warning }}</div>{{ end }}
+            <input type="hidden" name="token" value="{{ .Token }}" />
+            <p>
+              <label for="password" class="infield">Password</label>
+              <input type="password" name="password" id="password"
+                placeholder="Password" value=""
+                autocomplete="new-password" autocapitalize="off" autocorrect="off"
+                autofocus />
+            </p>
+            <p>
+              <label for="password-confirmation" class="infield">Confirm password</label>
+              <input type="password" name="password-confirmation" id="password-confirmation"
+                placeholder="Confirm password" value=""
+                autocomplete="new-password" autocapitalize="off" autocorrect="off" />
+            </p>
+            <input type="submit" id="submit" class="login primary" value="Activate" />
+          </fieldset>
+        </form>
+        {{ end }}
+        <div class="push"></div>
+        <!-- for sticky footer -->
+      </div>
+    </div>
+    <footer role="contentinfo">
+      <p class="info">
+        <a href="https://cernbox.web.cern.ch" target="_blank" rel="noreferrer">CERNBox</a> &ndash; The CERN Cloud Storage</p>
+    </footer>
+  </body>
+</html>
+`
@@ -8,6 +8,7 @@ import (
 	"github.com/cernbox/gohub/gologger"
 	"github.com/cernbox/revaold/ocproxy/api"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
 
@@ -24,14 +25,13 @@ func init() {
 	gc.Add("tls-cert", "/etc/grid-security/hostcert.pem", "TLS certificate to encrypt connections.")
 	gc.Add("tls-key", "/etc/grid-security/hostkey.pem", "TLS private key to encrypt connections.")
 	gc.Add("tls-enable", false, "Enable TLS for encrypting connections.")
+	gc.Add("metrics-address", "localhost:1093", "tcp address to expose the prometheus metrics, empty disables them")
 
 	gc.Add("data-chunks-folder", "", "folder where to store data chunks before they are commited to REVA.")
 	gc.Add("temporary-folder", "", "folder where to store temporary data. Empty means use the OS temporary folder.")
 	gc.Add("max-upload-file-size", 8589934592, "maximum file size for upload files.")
 	gc.Add("jwt-sign-key", "bar", "secret to sign JWT tokens.")
 	gc.Add("reva-tcp-address", "localhost:9999", "tcp address of the REVA server.")
-	gc.Add("cboxgroupd-http-address", "http://localhost:2002", "http(s) address of the CERNBox Group Daemon (cboxgroupd).")
-	gc.Add("cboxgroupd-shared-secret", "bar", "shared secret to connect to the CERNBox Group Daemon (cboxgroupd).")
 
 	gc.Add("archive-max-num-files", 1000, "maximun number of files to allow for download in archive (tar/zip)")
 	gc.Add("archive-max-size", 8589934592, "maximun aggreagated size to allow for download in archive (tar/zip)")
@@ -40,6 +40,7 @@ func init() {
 	gc.Add("overwrite-host", "", "if set, overwrites the hostname of the machine, usually used when server is after a proxy")
 	gc.Add("wopi-server", "http://wopihost.example.org", "hostname of the wopi server")
 	gc.Add("wopi-secret", "bar", "secret to use to connect to the wopi server")
+	gc.Add("wopi-token-lifetime", 28800, "time in seconds the token given to the wopi server for an editing session is valid, capped by revad")
 
 	gc.Add("apps-drawio-url", "https://drawio.web.cern.ch", "The DrawIO URL")
 
@@ -48,6 +49,13 @@ func init() {
 
 	gc.Add("cache-size", 1000000, "cache size for md records")
 	gc.Add("cache-eviction", 86400, "cache eviction time in seconds for md records")
+	gc.Add("basic-auth-cache-size", 10000, "number of basic auth credentials whose token is cached")
+	gc.Add("basic-auth-cache-ttl", 300, "time in seconds a token minted for basic auth credentials is reused, should be lower than the token lifetime")
+	gc.Add("display-name-cache-size", 10000, "number of users whose display name from the directory is cached")
+	gc.Add("display-name-cache-ttl", 3600, "time in seconds the display name of a user from the directory is reused")
+
+	gc.Add("ocm-shared-secret", "", "secret to relay the shares and notifications of remote servers to revad, must match the one of revad")
+	gc.Add("trusted-proxies", "", "comma separated IPs or CIDR networks of the reverse proxies whose X-Real-Ip and X-Forwarded-For headers are trusted")
 
 	gc.BindFlags()
 	gc.ReadConfig()
@@ -66,19 +74,24 @@ func main() {
 		REVAHost:              gc.GetString("reva-tcp-address"),
 		MaxUploadFileSize:     uint64(gc.GetInt("max-upload-file-size")),
 		Logger:                logger,
-		CBOXGroupDaemonURI:    gc.GetString("cboxgroupd-http-address"),
-		CBOXGroupDaemonSecret: gc.GetString("cboxgroupd-shared-secret"),
 		MaxNumFilesForArchive: gc.GetInt("archive-max-num-files"),
 		MaxSizeForArchive:     gc.GetInt("archive-max-size"),
 		MaxViewerFileFize:     gc.GetInt("viewer-max-file-size"),
 		OverwriteHost:         gc.GetString("overwrite-host"),
 		WopiServer:            gc.GetString("wopi-server"),
 		WopiSecret:            gc.GetString("wopi-secret"),
+		WopiTokenLifetime:     gc.GetInt("wopi-token-lifetime"),
 		DrawIOURL:             gc.GetString("apps-drawio-url"),
 		CacheSize:             gc.GetInt("cache-size"),
 		CacheEviction:         gc.GetInt("cache-eviction"),
+		BasicAuthCacheSize:    gc.GetInt("basic-auth-cache-size"),
+		BasicAuthCacheTTL:     gc.GetInt("basic-auth-cache-ttl"),
+		DisplayNameCacheSize:  gc.GetInt("display-name-cache-size"),
+		DisplayNameCacheTTL:   gc.GetInt("display-name-cache-ttl"),
 		MailServer:            gc.GetString("apps-mail-server"),
 		MailServerFromAddress: gc.GetString("apps-mail-server-from-address"),
+		OCMSharedSecret:       gc.GetString("ocm-shared-secret"),
+		TrustedProxies:        getTrustedProxies(),
 	}
 
 	_, err := api.New(opts)
@@ -87,6 +100,14 @@ func main() {
 		panic(err)
 	}
 
+	if addr := gc.GetString("metrics-address"); addr != "" {
+		go func() {
+			if err := http.ListenAndServe(addr, promhttp.Handler()); err != nil {
+				logger.Error("metrics server exited with error", zap.Error(err))
+			}
+		}()
+	}
+
 	loggedRouter := gologger.GetLoggedHTTPHandler(gc.GetString("http-log"), router)
 
 	err = router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
@@ -117,3 +138,13 @@ func main() {
 		logger.Info("server exited without error")
 	}
 }
+
+func getTrustedProxies() []string {
+	proxies := []string{}
+	for _, v := range strings.Split(gc.GetString("trusted-proxies"), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			proxies = append(proxies, v)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,182 @@
+package admincmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/reva-cli/util"
+
+	"github.com/codegangsta/cli"
+	"github.com/ryanuber/columnize"
+)
+
+var ReconcileSharesCommand = cli.Command{
+	Name:      "reconcile",
+	Usage:     "Compares the folder shares with the ACLs on the storage and repairs the differences",
+	ArgsUsage: "Usage: reconcile [--dry-run]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Only reports the differences without repairing them",
+		},
+	},
+	Action: reconcileShares,
+}
+
+var TransferOwnershipCommand = cli.Command{
+	Name:      "transfer-ownership",
+	Usage:     "Moves a file or folder to the home of another user together with its shares and public links",
+	ArgsUsage: "Usage: transfer-ownership <from> <to> <path>",
+	Action:    transferOwnership,
+}
+
+var RevokeUserTokensCommand = cli.Command{
+	Name:      "revoke-tokens",
+	Usage:     "Revokes all the access and refresh tokens issued to a user until now",
+	ArgsUsage: "Usage: revoke-tokens <account_id>",
+	Action:    revokeUserTokens,
+}
+
+var ImpersonateUserCommand = cli.Command{
+	Name:      "impersonate",
+	Usage:     "Prints a short lived token to see what a user sees, it cannot be used to change shares",
+	ArgsUsage: "Usage: impersonate <account_id>",
+	Action:    impersonateUser,
+}
+
+func reconcileShares(c *cli.Context) error {
+	ctx := util.GetContextWithAuth()
+	client, err := util.GetAdminClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	stream, err := client.ReconcileShares(ctx, &api.ReconcileSharesReq{DryRun: c.Bool("dry-run")})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	lines := []string{"#Kind|Owner|Path|ShareID|Type|Recipient|ReadOnly|Repaired|Error"}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if res.Status != api.StatusCode_OK {
+			return cli.NewExitError(res.Status, 1)
+		}
+		d := res.Drift
+		line := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%t|%t|%s", d.Kind, d.OwnerId, d.Path, d.ShareId, getRecipientTypeHuman(d.Recipient.Type), d.Recipient.Identity, d.ReadOnly, d.Repaired, d.Error)
+		lines = append(lines, line)
+	}
+	fmt.Fprintln(c.App.Writer, columnize.SimpleFormat(lines))
+	return nil
+}
+
+func transferOwnership(c *cli.Context) error {
+	if len(c.Args()) != 3 {
+		return cli.NewExitError(c.Command.ArgsUsage, 1)
+	}
+	from, to, path := c.Args().Get(0), c.Args().Get(1), c.Args().Get(2)
+
+	ctx := util.GetContextWithAuth()
+	client, err := util.GetAdminClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	stream, err := client.TransferOwnership(ctx, &api.TransferOwnershipReq{From: from, To: to, Path: path})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	lines := []string{"#Kind|ID|OldPath|NewPath|Type|Recipient|Files|Size|Transferred|Error"}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if res.Status != api.StatusCode_OK {
+			return cli.NewExitError(res.Status, 1)
+		}
+		i := res.Item
+		var recipientType, recipient string
+		if i.Recipient != nil {
+			recipientType, recipient = getRecipientTypeHuman(i.Recipient.Type), i.Recipient.Identity
+		}
+		line := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%d|%d|%t|%s", i.Kind, i.Id, i.OldPath, i.NewPath, recipientType, recipient, i.Files, i.Size, i.Transferred, i.Error)
+		lines = append(lines, line)
+	}
+	fmt.Fprintln(c.App.Writer, columnize.SimpleFormat(lines))
+	return nil
+}
+
+func revokeUserTokens(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		return cli.NewExitError(c.Command.ArgsUsage, 1)
+	}
+	accountID := c.Args().First()
+
+	ctx := util.GetContextWithAuth()
+	client, err := util.GetAdminClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	res, err := client.RevokeUserTokens(ctx, &api.RevokeUserTokensReq{AccountId: accountID})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if res.Status != api.StatusCode_OK {
+		return cli.NewExitError(res.Status, 1)
+	}
+	fmt.Fprintf(c.App.Writer, "All tokens of %s have been revoked\n", accountID)
+	return nil
+}
+
+func impersonateUser(c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		return cli.NewExitError(c.Command.ArgsUsage, 1)
+	}
+	accountID := c.Args().First()
+
+	ctx := util.GetContextWithAuth()
+	client, err := util.GetAdminClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	res, err := client.ImpersonateUser(ctx, &api.ImpersonateUserReq{AccountId: accountID})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if res.Status != api.StatusCode_OK {
+		return cli.NewExitError(res.Status, 1)
+	}
+	fmt.Fprintln(c.App.Writer, res.Token)
+	return nil
+}
+
+func getRecipientTypeHuman(t api.ShareRecipient_RecipientType) string {
+	switch t {
+	case api.ShareRecipient_USER:
+		return "user"
+	case api.ShareRecipient_GROUP:
+		return "group"
+	case api.ShareRecipient_UNIX:
+		return "unix-group"
+	case api.ShareRecipient_REMOTE:
+		return "remote"
+	case api.ShareRecipient_GUEST:
+		return "guest"
+	default:
+		return "unknown"
+	}
+}
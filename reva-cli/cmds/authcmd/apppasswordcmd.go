@@ -0,0 +1,149 @@
+package authcmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/reva-cli/util"
+	"github.com/codegangsta/cli"
+	"github.com/ryanuber/columnize"
+)
+
+var AppPasswordCommands = cli.Command{
+	Name:  "app-password",
+	Usage: "App password commands",
+	Subcommands: []cli.Command{
+		CreateAppPasswordCommand,
+		ListAppPasswordsCommand,
+		RevokeAppPasswordCommand,
+	},
+}
+
+var CreateAppPasswordCommand = cli.Command{
+	Name:      "create",
+	Usage:     "Creates an app password to log in clients and scripts, the password is only shown once",
+	ArgsUsage: "Usage: create <label> [--read-only] [--path <path>] [--expiration <expiration>]",
+	Action:    createAppPassword,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "read-only",
+			Usage: "the app password can only be used to read",
+		},
+		cli.StringFlag{
+			Name:  "path",
+			Usage: "the app password can only be used to access this path and the paths under it",
+		},
+		cli.StringFlag{
+			Name:  "expiration",
+			Usage: "expiration time for the app password, like 2018-02-28 12:45:00",
+		},
+	},
+}
+
+var ListAppPasswordsCommand = cli.Command{
+	Name:      "list",
+	Usage:     "Lists the app passwords",
+	ArgsUsage: "Usage: list",
+	Action:    listAppPasswords,
+}
+
+var RevokeAppPasswordCommand = cli.Command{
+	Name:      "revoke",
+	Usage:     "Revokes an app password",
+	ArgsUsage: "Usage: revoke <id>",
+	Action:    revokeAppPassword,
+}
+
+func createAppPassword(c *cli.Context) error {
+	label := c.Args().First()
+	if label == "" {
+		return cli.NewExitError(c.Command.ArgsUsage, 1)
+	}
+
+	scope := &api.TokenScope{ReadOnly: c.Bool("read-only"), Path: c.String("path")}
+	if c.String("expiration") != "" {
+		t, err := time.Parse("2006-01-02 15:04:05", c.String("expiration"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		scope.Expiration = uint64(t.Unix())
+	}
+
+	client, err := util.GetAuthClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx := util.GetContextWithAuth()
+	res, err := client.CreateAppPassword(ctx, &api.NewAppPasswordReq{Label: label, Scope: scope})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if res.Status != api.StatusCode_OK {
+		return cli.NewExitError(res.Status, 1)
+	}
+	fmt.Fprintf(c.App.Writer, "ID: %s\nLabel: %s\nPassword: %s\n", res.AppPassword.Id, res.AppPassword.Label, res.Password)
+	fmt.Fprintln(c.App.Writer, "The password cannot be shown again, store it now")
+	return nil
+}
+
+func listAppPasswords(c *cli.Context) error {
+	client, err := util.GetAuthClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx := util.GetContextWithAuth()
+	stream, err := client.ListAppPasswords(ctx, &api.EmptyReq{})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	lines := []string{"#ID|Label|ReadOnly|Path|Expiration|Created|LastUsed"}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if res.Status != api.StatusCode_OK {
+			return cli.NewExitError(res.Status, 1)
+		}
+		ap := res.AppPassword
+		scope := ap.Scope
+		if scope == nil {
+			scope = &api.TokenScope{}
+		}
+		line := fmt.Sprintf("%s|%s|%t|%s|%d|%d|%d", ap.Id, ap.Label, scope.ReadOnly, scope.Path, scope.Expiration, ap.Ctime, ap.LastUsed)
+		lines = append(lines, line)
+	}
+	fmt.Fprintln(c.App.Writer, columnize.SimpleFormat(lines))
+	return nil
+}
+
+func revokeAppPassword(c *cli.Context) error {
+	id := c.Args().First()
+	if id == "" {
+		return cli.NewExitError(c.Command.ArgsUsage, 1)
+	}
+
+	client, err := util.GetAuthClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx := util.GetContextWithAuth()
+	res, err := client.RevokeAppPassword(ctx, &api.AppPasswordReq{Id: id})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if res.Status != api.StatusCode_OK {
+		return cli.NewExitError(res.Status, 1)
+	}
+	fmt.Fprintf(c.App.Writer, "App password %s revoked\n", id)
+	return nil
+}
@@ -29,6 +29,13 @@ var VerifyTokenCommand = cli.Command{
 	Action:    verifyToken,
 }
 
+var RefreshTokenCommand = cli.Command{
+	Name:      "refresh-token",
+	Usage:     "Replaces the saved access and refresh tokens with new ones",
+	ArgsUsage: "Usage: refresh-token",
+	Action:    refreshToken,
+}
+
 func forgePublicLinkToken(c *cli.Context) error {
 	if len(c.Args()) < 1 {
 		return cli.NewExitError(c.Command.ArgsUsage, 1)
@@ -57,6 +64,30 @@ func forgePublicLinkToken(c *cli.Context) error {
 	return nil
 }
 
+func refreshToken(c *cli.Context) error {
+	token := util.GetRefreshToken()
+	if token == "" {
+		return cli.NewExitError("no refresh token saved, please login again", 1)
+	}
+
+	client, err := util.GetAuthClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	res, err := client.RefreshUserToken(context.Background(), &api.RefreshTokenReq{RefreshToken: token})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if res.Status != api.StatusCode_OK {
+		return cli.NewExitError(res.Status, 1)
+	}
+	util.SetAccessToken(res.Token)
+	util.SetRefreshToken(res.RefreshToken)
+	fmt.Println("Access token saved in: ", util.AccessTokenFile)
+	return nil
+}
+
 func verifyToken(c *cli.Context) {
 	fmt.Println("not implemented")
 }
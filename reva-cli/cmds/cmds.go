@@ -8,6 +8,7 @@ import (
 	"github.com/codegangsta/cli"
 
 	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/reva-cli/cmds/admincmd"
 	"github.com/cernbox/revaold/reva-cli/cmds/authcmd"
 	"github.com/cernbox/revaold/reva-cli/cmds/sharecmd"
 	"github.com/cernbox/revaold/reva-cli/cmds/storagecmd"
@@ -56,6 +57,8 @@ var ShareCommands = cli.Command{
 		sharecmd.CreatePublicLinkCommand,
 		sharecmd.UpdatePublicLinkCommand,
 		sharecmd.RevokePublicLinkCommand,
+
+		sharecmd.NotificationPreferencesCommand,
 	},
 }
 
@@ -72,6 +75,25 @@ var AuthCommands = cli.Command{
 	Subcommands: []cli.Command{
 		authcmd.ForgePublicLinkTokenCommand,
 		authcmd.VerifyTokenCommand,
+		authcmd.RefreshTokenCommand,
+		authcmd.AppPasswordCommands,
+	},
+}
+
+var AdminCommands = cli.Command{
+	Name:  "admin",
+	Usage: "Admin commands",
+	Subcommands: []cli.Command{
+		cli.Command{
+			Name:  "shares",
+			Usage: "Share administration commands",
+			Subcommands: []cli.Command{
+				admincmd.ReconcileSharesCommand,
+			},
+		},
+		admincmd.TransferOwnershipCommand,
+		admincmd.RevokeUserTokensCommand,
+		admincmd.ImpersonateUserCommand,
 	},
 }
 
@@ -110,7 +132,7 @@ func login(c *cli.Context) {
 		os.Exit(1)
 	}
 
-	req := &api.ForgeUserTokenReq{ClientId: username, ClientSecret: password}
+	req := &api.ForgeUserTokenReq{ClientId: username, ClientSecret: password, Refresh: true}
 	tokenRes, err := authClient.ForgeUserToken(context.Background(), req)
 	if err != nil {
 		fmt.Println(err)
@@ -123,4 +145,42 @@ func login(c *cli.Context) {
 	token := tokenRes.Token
 	util.SetAccessToken(token)
 	fmt.Println("Access token saved in: ", util.AccessTokenFile)
+	if tokenRes.RefreshToken != "" {
+		util.SetRefreshToken(tokenRes.RefreshToken)
+		fmt.Println("Refresh token saved in: ", util.RefreshTokenFile)
+	}
+}
+
+var LogoutCommand = cli.Command{
+	Name:      "logout",
+	Usage:     "Logout from reva, revoking the saved tokens",
+	ArgsUsage: "Usage: logout",
+	Action:    logout,
+}
+
+func logout(c *cli.Context) error {
+	authClient, err := util.GetAuthClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	for _, token := range []string{util.GetAccessToken(), util.GetRefreshToken()} {
+		if token == "" {
+			continue
+		}
+		res, err := authClient.RevokeToken(context.Background(), &api.TokenReq{Token: token})
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		switch res.Status {
+		case api.StatusCode_OK, api.StatusCode_TOKEN_INVALID: // an invalid or expired token can not be used anyway
+		case api.StatusCode_STORAGE_NOT_SUPPORTED:
+			fmt.Println("The server cannot revoke tokens, they stay valid until they expire")
+		default:
+			return cli.NewExitError(res.Status, 1)
+		}
+	}
+	util.RemoveTokens()
+	fmt.Println("Logged out")
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/cernbox/revaold/api"
@@ -30,7 +31,13 @@ var ListFolderSharesCommand = cli.Command{
 	Name:      "folder-share-list",
 	Usage:     "List folder shares",
 	ArgsUsage: "Usage: folder-share-list [path]",
-	Action:    listFolderShares,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "recursive",
+			Usage: "lists also the shares of the folders under the path",
+		},
+	},
+	Action: listFolderShares,
 }
 
 var RemoveFolderShareCommand = cli.Command{
@@ -91,10 +98,79 @@ var CreatePublicLinkCommand = cli.Command{
 			Name:  "expiration",
 			Usage: "expiration time for the link, like 2018-02-28:12:45:00",
 		},
+		cli.StringFlag{
+			Name:  "token",
+			Usage: "custom token for the link, between 10 and 64 letters, digits, dashes and underscores. If empty a random token is generated",
+		},
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "name to tell apart the links of the same resource, by default the name of the resource",
+		},
+		cli.StringFlag{
+			Name:  "labels",
+			Usage: "comma separated list of labels to tag the link with, like project,review",
+		},
+		cli.Uint64Flag{
+			Name:  "max-downloads",
+			Usage: "maximum number of downloads allowed, 0 means unlimited",
+		},
+		cli.Uint64Flag{
+			Name:  "max-visitors",
+			Usage: "maximum number of distinct visitors allowed, 0 means unlimited",
+		},
+		cli.StringSliceFlag{
+			Name:  "notify",
+			Usage: "e-mail address the link is sent to, can be repeated",
+		},
+		cli.BoolFlag{
+			Name:  "drop-only",
+			Usage: "people can only upload files to the link, without seeing its contents",
+		},
+		cli.Uint64Flag{
+			Name:  "max-file-size",
+			Usage: "maximum size in bytes of the files uploaded to a drop only link, 0 means unlimited",
+		},
+		cli.Uint64Flag{
+			Name:  "max-total-size",
+			Usage: "maximum size in bytes of all the files uploaded to a drop only link, 0 means unlimited",
+		},
+		cli.StringSliceFlag{
+			Name:  "allowed-type",
+			Usage: "extension like .pdf or mime type like image/* accepted by a drop only link, can be repeated",
+		},
+		cli.BoolFlag{
+			Name:  "uploader-folders",
+			Usage: "put the uploads to a drop only link in a folder named after the uploader",
+		},
+		cli.BoolFlag{
+			Name:  "upload-digest",
+			Usage: "e-mail a periodic digest of the uploads to a drop only link",
+		},
 	},
 	Action: createPublicLink,
 }
 
+var NotificationPreferencesCommand = cli.Command{
+	Name:      "notification-preferences",
+	Usage:     "Shows or changes the preferences for the e-mails about received shares",
+	ArgsUsage: "Usage: notification-preferences [--opt-out | --opt-in] [--language <language>]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "opt-out",
+			Usage: "stop receiving e-mails about received shares",
+		},
+		cli.BoolFlag{
+			Name:  "opt-in",
+			Usage: "receive e-mails about received shares again",
+		},
+		cli.StringFlag{
+			Name:  "language",
+			Usage: "language of the e-mails, like en or fr",
+		},
+	},
+	Action: notificationPreferences,
+}
+
 var RevokePublicLinkCommand = cli.Command{
 	Name:      "public-link-revoke",
 	Usage:     "Revokes a public link",
@@ -112,8 +188,14 @@ var InspectPublicLinkCommand = cli.Command{
 var ListPublicLinksCommand = cli.Command{
 	Name:      "public-link-list",
 	Usage:     "List all public links",
-	ArgsUsage: "Usage: public-link-list",
-	Action:    listPublicLinks,
+	ArgsUsage: "Usage: public-link-list [path]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "recursive",
+			Usage: "lists also the links of the resources under the path",
+		},
+	},
+	Action: listPublicLinks,
 }
 
 var UpdatePublicLinkCommand = cli.Command{
@@ -145,6 +227,22 @@ var UpdatePublicLinkCommand = cli.Command{
 			Name:  "set-read-only",
 			Usage: "set read-only field to the value from --read-only flag",
 		},
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "set the name of the link, empty goes back to the name of the resource",
+		},
+		cli.StringFlag{
+			Name:  "labels",
+			Usage: "set the comma separated list of labels of the link, empty removes all the labels",
+		},
+		cli.Uint64Flag{
+			Name:  "max-downloads",
+			Usage: "set the maximum number of downloads allowed, 0 means unlimited",
+		},
+		cli.Uint64Flag{
+			Name:  "max-visitors",
+			Usage: "set the maximum number of distinct visitors allowed, 0 means unlimited",
+		},
 	},
 	Action: updatePublicLink,
 }
@@ -172,7 +270,16 @@ func inspectPublicLink(c *cli.Context) error {
 	link := linkRes.PublicLink
 	modified := time.Unix(int64(link.Mtime), 0).Format(time.RFC3339)
 	expires := time.Unix(int64(link.Expires), 0).Format(time.RFC3339)
-	fmt.Fprintf(c.App.Writer, "ID: %s\nToken: %s\nProtected: %t\nReadOnly: %t\nModify: %s Timestamp: %d\nExpires: %s Timestamp: %d\nPath: %s\n", link.Id, link.Token, link.Protected, link.ReadOnly, modified, link.Mtime, expires, link.Expires, link.Path)
+	fmt.Fprintf(c.App.Writer, "ID: %s\nToken: %s\nName: %s\nProtected: %t\nReadOnly: %t\nModify: %s Timestamp: %d\nExpires: %s Timestamp: %d\nPath: %s\n", link.Id, link.Token, link.Name, link.Protected, link.ReadOnly, modified, link.Mtime, expires, link.Expires, link.Path)
+	fmt.Fprintf(c.App.Writer, "Labels: %s\n", strings.Join(link.Labels, ","))
+	fmt.Fprintf(c.App.Writer, "MaxDownloads: %d\nMaxVisitors: %d\nDownloads: %d\nUploads: %d\nVisitors: %d\n", link.MaxDownloads, link.MaxVisitors, link.Downloads, link.Uploads, link.Visitors)
+	if link.LastAccess > 0 {
+		lastAccess := time.Unix(int64(link.LastAccess), 0).Format(time.RFC3339)
+		fmt.Fprintf(c.App.Writer, "LastAccess: %s Timestamp: %d\n", lastAccess, link.LastAccess)
+	}
+	if policy := link.UploadPolicy; policy != nil {
+		fmt.Fprintf(c.App.Writer, "MaxFileSize: %d\nMaxTotalSize: %d\nAllowedTypes: %s\nUploaderFolders: %t\nUploadDigest: %t\nUploadedBytes: %d\n", policy.MaxFileSize, policy.MaxTotalSize, strings.Join(policy.AllowedTypes, ","), policy.UploaderFolders, policy.Digest, link.UploadedBytes)
+	}
 	return nil
 }
 
@@ -188,9 +295,29 @@ func createPublicLink(c *cli.Context) error {
 	}
 
 	req := &api.NewLinkReq{
-		Password: c.String("password"),
-		ReadOnly: !c.Bool("read-write"),
-		Path:     path,
+		Token:        c.String("token"),
+		Name:         c.String("name"),
+		Password:     c.String("password"),
+		ReadOnly:     !c.Bool("read-write"),
+		Path:         path,
+		MaxDownloads: c.Uint64("max-downloads"),
+		MaxVisitors:  c.Uint64("max-visitors"),
+		Notify:       c.StringSlice("notify"),
+		Labels:       splitLabels(c.String("labels")),
+	}
+
+	if c.Bool("drop-only") {
+		req.ReadOnly = false
+		req.DropOnly = true
+	}
+	if c.IsSet("max-file-size") || c.IsSet("max-total-size") || c.IsSet("allowed-type") || c.Bool("uploader-folders") || c.Bool("upload-digest") {
+		req.UploadPolicy = &api.UploadPolicy{
+			MaxFileSize:     c.Uint64("max-file-size"),
+			MaxTotalSize:    c.Uint64("max-total-size"),
+			AllowedTypes:    c.StringSlice("allowed-type"),
+			UploaderFolders: c.Bool("uploader-folders"),
+			Digest:          c.Bool("upload-digest"),
+		}
 	}
 
 	if c.String("expiration") != "" {
@@ -213,7 +340,8 @@ func createPublicLink(c *cli.Context) error {
 
 	modified := time.Unix(int64(link.Mtime), 0).Format(time.RFC3339)
 	expires := time.Unix(int64(link.Expires), 0).Format(time.RFC3339)
-	fmt.Fprintf(c.App.Writer, "Token: %s\nProtected: %t\nReadOnly: %t\nModify: %s Timestamp: %d\nExpires: %s Timestamp: %d\nPath: %s\n", link.Token, link.Protected, link.ReadOnly, modified, link.Mtime, expires, link.Expires, link.Path)
+	fmt.Fprintf(c.App.Writer, "Token: %s\nName: %s\nProtected: %t\nReadOnly: %t\nModify: %s Timestamp: %d\nExpires: %s Timestamp: %d\nPath: %s\n", link.Token, link.Name, link.Protected, link.ReadOnly, modified, link.Mtime, expires, link.Expires, link.Path)
+	fmt.Fprintf(c.App.Writer, "Labels: %s\n", strings.Join(link.Labels, ","))
 	return nil
 }
 
@@ -243,12 +371,13 @@ func listPublicLinks(c *cli.Context) error {
 		return cli.NewExitError(err, 1)
 	}
 
-	stream, err := client.ListPublicLinks(ctx, &api.ListPublicLinksReq{})
+	req := &api.ListPublicLinksReq{Path: c.Args().First(), Recursive: c.Bool("recursive")}
+	stream, err := client.ListPublicLinks(ctx, req)
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
-	lines := []string{"#ID|Token|Protected|Expires|ReadOnly|Modified|Path"}
+	lines := []string{"#ID|Token|Name|Protected|Expires|ReadOnly|Modified|Path"}
 	for {
 		linkRes, err := stream.Recv()
 		if err == io.EOF {
@@ -261,7 +390,7 @@ func listPublicLinks(c *cli.Context) error {
 			return cli.NewExitError(linkRes.Status, 1)
 		}
 		link := linkRes.PublicLink
-		line := fmt.Sprintf("%s|%s|%t|%d|%t|%d|%s", link.Id, link.Token, link.Protected, link.Expires, link.ReadOnly, link.Mtime, link.Path)
+		line := fmt.Sprintf("%s|%s|%s|%t|%d|%t|%d|%s", link.Id, link.Token, link.Name, link.Protected, link.Expires, link.ReadOnly, link.Mtime, link.Path)
 		lines = append(lines, line)
 	}
 	fmt.Fprintln(c.App.Writer, columnize.SimpleFormat(lines))
@@ -303,6 +432,26 @@ func updatePublicLink(c *cli.Context) error {
 		req.ReadOnly = c.Bool("read-only")
 	}
 
+	if c.IsSet("name") {
+		req.UpdateName = true
+		req.Name = c.String("name")
+	}
+
+	if c.IsSet("labels") {
+		req.UpdateLabels = true
+		req.Labels = splitLabels(c.String("labels"))
+	}
+
+	if c.IsSet("max-downloads") {
+		req.UpdateMaxDownloads = true
+		req.MaxDownloads = c.Uint64("max-downloads")
+	}
+
+	if c.IsSet("max-visitors") {
+		req.UpdateMaxVisitors = true
+		req.MaxVisitors = c.Uint64("max-visitors")
+	}
+
 	ctx := util.GetContextWithAuth()
 	linkRes, err := client.UpdatePublicLink(ctx, req)
 	if err != nil {
@@ -315,10 +464,21 @@ func updatePublicLink(c *cli.Context) error {
 
 	modified := time.Unix(int64(link.Mtime), 0).Format(time.RFC3339)
 	expires := time.Unix(int64(link.Expires), 0).Format(time.RFC3339)
-	fmt.Fprintf(c.App.Writer, "Token: %s\nProtected: %t\nReadOnly: %t\nModify: %s Timestamp: %d\nExpires: %s Timestamp: %d\nPath: %s\n", link.Token, link.Protected, link.ReadOnly, modified, link.Mtime, expires, link.Expires, link.Path)
+	fmt.Fprintf(c.App.Writer, "Token: %s\nName: %s\nProtected: %t\nReadOnly: %t\nModify: %s Timestamp: %d\nExpires: %s Timestamp: %d\nPath: %s\n", link.Token, link.Name, link.Protected, link.ReadOnly, modified, link.Mtime, expires, link.Expires, link.Path)
+	fmt.Fprintf(c.App.Writer, "Labels: %s\n", strings.Join(link.Labels, ","))
 	return nil
 }
 
+func splitLabels(v string) []string {
+	labels := []string{}
+	for _, label := range strings.Split(v, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
 func getRecipientType(t string) (api.ShareRecipient_RecipientType, error) {
 	switch t {
 	case "user":
@@ -327,6 +487,8 @@ func getRecipientType(t string) (api.ShareRecipient_RecipientType, error) {
 		return api.ShareRecipient_GROUP, nil
 	case "unix-group":
 		return api.ShareRecipient_UNIX, nil
+	case "guest":
+		return api.ShareRecipient_GUEST, nil
 	default:
 		return 0, errors.New("unknow recipient type")
 	}
@@ -340,6 +502,8 @@ func getRecipientTypeHuman(t api.ShareRecipient_RecipientType) string {
 		return "group"
 	case api.ShareRecipient_UNIX:
 		return "unix-group"
+	case api.ShareRecipient_GUEST:
+		return "guest"
 	default:
 		return "unknown"
 	}
@@ -395,7 +559,8 @@ func listFolderShares(c *cli.Context) error {
 		return cli.NewExitError(err, 1)
 	}
 
-	stream, err := client.ListFolderShares(ctx, &api.ListFolderSharesReq{})
+	req := &api.ListFolderSharesReq{Path: c.Args().First(), Recursive: c.Bool("recursive")}
+	stream, err := client.ListFolderShares(ctx, req)
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
@@ -513,3 +678,47 @@ func mountReceivedShare(c *cli.Context) {
 func unmountReceivedShare(c *cli.Context) {
 	fmt.Println("not implemented")
 }
+
+func notificationPreferences(c *cli.Context) error {
+	if c.Bool("opt-out") && c.Bool("opt-in") {
+		return cli.NewExitError(c.Command.ArgsUsage, 1)
+	}
+
+	client, err := util.GetNotificationClient()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx := util.GetContextWithAuth()
+	res, err := client.GetNotificationPreferences(ctx, &api.EmptyReq{})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if res.Status != api.StatusCode_OK {
+		return cli.NewExitError(res.Status, 1)
+	}
+
+	prefs := res.Preferences
+	if prefs == nil {
+		prefs = &api.NotificationPreferences{}
+	}
+
+	if c.Bool("opt-out") || c.Bool("opt-in") || c.IsSet("language") {
+		if c.Bool("opt-out") || c.Bool("opt-in") {
+			prefs.OptOut = c.Bool("opt-out")
+		}
+		if c.IsSet("language") {
+			prefs.Language = c.String("language")
+		}
+		if _, err := client.SetNotificationPreferences(ctx, &api.NotificationPreferencesReq{Preferences: prefs}); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+
+	language := prefs.Language
+	if language == "" {
+		language = "default"
+	}
+	fmt.Fprintf(c.App.Writer, "OptOut: %t\nLanguage: %s\n", prefs.OptOut, language)
+	return nil
+}
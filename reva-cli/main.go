@@ -24,7 +24,9 @@ func main() {
 		cmds.AuthCommands,
 		cmds.ShareCommands,
 		cmds.PreviewCommands,
+		cmds.AdminCommands,
 		cmds.LoginCommand,
+		cmds.LogoutCommand,
 	}
 
 	app.Before = func(c *cli.Context) error {
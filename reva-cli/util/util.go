@@ -25,6 +25,7 @@ var ConfigDir string
 var ConfigFile string
 var LogFile string
 var AccessTokenFile string
+var RefreshTokenFile string
 
 type Config struct {
 	Username  string
@@ -42,6 +43,7 @@ func init() {
 	ConfigFile = path.Join(ConfigDir, "config")
 	LogFile = path.Join(ConfigDir, "log")
 	AccessTokenFile = path.Join(ConfigDir, "access-token")
+	RefreshTokenFile = path.Join(ConfigDir, "refresh-token")
 
 	// check if ConfigDir exists or not
 	if _, err := os.Stat(ConfigDir); os.IsNotExist(err) {
@@ -80,6 +82,29 @@ func SetAccessToken(token string) {
 	}
 }
 
+func GetRefreshToken() string {
+	data, err := ioutil.ReadFile(RefreshTokenFile)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func SetRefreshToken(token string) {
+	if err := ioutil.WriteFile(RefreshTokenFile, []byte(token), 0600); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// RemoveTokens deletes the saved access and refresh tokens.
+func RemoveTokens() {
+	for _, p := range []string{AccessTokenFile, RefreshTokenFile} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			log.Fatalln(err)
+		}
+	}
+}
+
 func SavePublicLinkAccessToken(token, accessToken string) {
 	p := path.Join(ConfigDir, fmt.Sprintf("public-link-access-token-for-%s", token))
 	if err := ioutil.WriteFile(p, []byte(accessToken), 0600); err != nil {
@@ -134,6 +159,22 @@ func GetPreviewClient() (api.PreviewClient, error) {
 	return api.NewPreviewClient(conn), nil
 }
 
+func GetAdminClient() (api.AdminClient, error) {
+	conn, err := getConn()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewAdminClient(conn), nil
+}
+
+func GetNotificationClient() (api.NotificationClient, error) {
+	conn, err := getConn()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewNotificationClient(conn), nil
+}
+
 func GetContextWithAuth() context.Context {
 	token := GetAccessToken()
 	header := metadata.New(map[string]string{"authorization": "user-bearer " + token})
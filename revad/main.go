@@ -7,32 +7,62 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/url"
+	"path"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/cernbox/cboxredirectd/api/redismigrator"
 	"github.com/cernbox/gohub/goconfig"
 	"github.com/cernbox/gohub/gologger"
 
 	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/api/app_password_manager_db"
+	"github.com/cernbox/revaold/api/auth_attempt_store_db"
+	"github.com/cernbox/revaold/api/auth_attempt_store_memory"
+	"github.com/cernbox/revaold/api/auth_manager_app_password"
+	"github.com/cernbox/revaold/api/auth_manager_guest"
 	"github.com/cernbox/revaold/api/auth_manager_impersonate"
 	"github.com/cernbox/revaold/api/auth_manager_ldap"
+	"github.com/cernbox/revaold/api/auth_manager_oidc"
+	"github.com/cernbox/revaold/api/guest_manager_db"
+	"github.com/cernbox/revaold/api/ldapclient"
 	"github.com/cernbox/revaold/api/mount"
+	"github.com/cernbox/revaold/api/notifier_smtp"
+	"github.com/cernbox/revaold/api/ocm_client_http"
+	"github.com/cernbox/revaold/api/ownership_transferer"
 	"github.com/cernbox/revaold/api/project_manager_db"
+	"github.com/cernbox/revaold/api/public_link_manager_json"
 	"github.com/cernbox/revaold/api/public_link_manager_owncloud"
+	"github.com/cernbox/revaold/api/public_link_validator"
+	"github.com/cernbox/revaold/api/remote_share_manager_owncloud"
+	"github.com/cernbox/revaold/api/share_manager_json"
 	"github.com/cernbox/revaold/api/share_manager_owncloud"
+	"github.com/cernbox/revaold/api/share_reconciler"
 	"github.com/cernbox/revaold/api/storage_all_projects"
 	"github.com/cernbox/revaold/api/storage_eos"
 	"github.com/cernbox/revaold/api/storage_homemigration"
 	"github.com/cernbox/revaold/api/storage_local"
+	"github.com/cernbox/revaold/api/storage_ocm"
 	"github.com/cernbox/revaold/api/storage_public_link"
 	"github.com/cernbox/revaold/api/storage_share"
 	"github.com/cernbox/revaold/api/storage_usermigration"
 	"github.com/cernbox/revaold/api/storage_wrapper_home"
 	"github.com/cernbox/revaold/api/tag_manager_db"
 	"github.com/cernbox/revaold/api/token_manager_jwt"
+	"github.com/cernbox/revaold/api/token_revocation_store_db"
+	"github.com/cernbox/revaold/api/token_revocation_store_memory"
+	"github.com/cernbox/revaold/api/user_directory_cboxgroupd"
+	"github.com/cernbox/revaold/api/user_directory_ldap"
 	"github.com/cernbox/revaold/api/user_manager_cboxgroupd"
+	"github.com/cernbox/revaold/api/user_manager_file"
 	"github.com/cernbox/revaold/api/virtual_storage"
+	"github.com/cernbox/revaold/revad/svcs/adminsvc"
 	"github.com/cernbox/revaold/revad/svcs/authsvc"
+	"github.com/cernbox/revaold/revad/svcs/directorysvc"
+	"github.com/cernbox/revaold/revad/svcs/notificationsvc"
+	"github.com/cernbox/revaold/revad/svcs/ocmsvc"
 	"github.com/cernbox/revaold/revad/svcs/previewsvc"
 	"github.com/cernbox/revaold/revad/svcs/sharesvc"
 	"github.com/cernbox/revaold/revad/svcs/storagesvc"
@@ -43,6 +73,7 @@ import (
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
 	"github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
 	"github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
 	"github.com/grpc-ecosystem/go-grpc-prometheus"
 
@@ -53,6 +84,7 @@ import (
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 var gc *goconfig.GoConfig
@@ -60,13 +92,25 @@ var logger *zap.Logger
 var vs api.VirtualStorage
 var tokenManager api.TokenManager
 var authManager api.AuthManager
+var guestManager api.GuestManager
+var appPasswordManager api.AppPasswordManager
 var publicLinkManager api.PublicLinkManager
 var shareManager api.ShareManager
 var userManager api.UserManager
+var userDirectory api.UserDirectory
 var projectManager api.ProjectManager
 var tagManager api.TagManager
+var shareReconciler api.ShareReconciler
+var ownershipTransferer api.OwnershipTransferer
+var notifier api.Notifier
+var remoteShareManager api.RemoteShareManager
+var ocmClient api.OCMProviderClient
+var authAttemptStore api.AuthAttemptStore
+var publicLinkValidator api.PublicLinkValidator
+var trustedProxies []*net.IPNet
 
 func main() {
+	setup()
 
 	mountTable := getMountTable(gc)
 
@@ -82,6 +126,7 @@ func main() {
 			grpc_prometheus.StreamServerInterceptor,
 			grpc_zap.StreamServerInterceptor(logger),
 			grpc_auth.StreamServerInterceptor(getAuthFunc(tokenManager)),
+			getAccessStreamInterceptor(),
 			grpc_recovery.StreamServerInterceptor(),
 		)),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
@@ -90,6 +135,7 @@ func main() {
 			grpc_prometheus.UnaryServerInterceptor,
 			grpc_zap.UnaryServerInterceptor(logger),
 			grpc_auth.UnaryServerInterceptor(getAuthFunc(tokenManager)),
+			getAccessUnaryInterceptor(),
 			grpc_recovery.UnaryServerInterceptor(),
 		)),
 	)
@@ -98,11 +144,37 @@ func main() {
 	grpc_prometheus.Register(server)
 	http.Handle("/metrics", promhttp.Handler())
 
-	api.RegisterAuthServer(server, authsvc.New(authManager, tokenManager, publicLinkManager))
+	// the keys verifying the tokens, for the services that validate them without the private key
+	if publisher, ok := tokenManager.(api.KeySetPublisher); ok {
+		http.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+			jwks, err := publisher.GetJWKS()
+			if err != nil {
+				logger.Error("error getting jwks", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(jwks)
+		})
+	}
+
+	api.RegisterAuthServer(server, authsvc.New(authManager, guestManager, appPasswordManager, tokenManager, publicLinkManager, publicLinkValidator, authAttemptStore, getThrottleOptions(), getAuthFunc(tokenManager)))
 	api.RegisterStorageServer(server, storagesvc.New(vs, gc.GetString("svc-storage-tx-temporary-folder")))
-	api.RegisterShareServer(server, sharesvc.New(publicLinkManager, shareManager))
+	api.RegisterShareServer(server, sharesvc.New(publicLinkManager, shareManager, notifier, guestManager))
 	api.RegisterPreviewServer(server, previewsvc.New())
 	api.RegisterTaggerServer(server, taggersvc.New(tagManager))
+	api.RegisterDirectoryServer(server, directorysvc.New(userDirectory, userManager))
+	api.RegisterAdminServer(server, adminsvc.New(shareReconciler, ownershipTransferer, userManager, userDirectory, guestManager, tokenManager, gc.GetString("admin-group"), gc.GetString("impersonation-group")))
+	if notifier != nil {
+		api.RegisterNotificationServer(server, notificationsvc.New(notifier))
+	}
+	if gc.GetBool("ocm-enabled") {
+		api.RegisterOCMServer(server, ocmsvc.New(remoteShareManager, ocmClient, getAuthFunc(tokenManager), gc.GetString("ocm-shared-secret"), getList("ocm-trusted-providers")))
+	}
+
+	if gc.GetBool("share-reconciler-enabled") {
+		go runShareReconciler()
+	}
 
 	logger.Info("listening for grpc connecitons on: " + gc.GetString("tcp-address"))
 	lis, err := net.Listen("tcp", gc.GetString("tcp-address"))
@@ -116,6 +188,19 @@ func main() {
 	log.Fatalf("failed to listen: %v", server.Serve(lis))
 }
 
+// runShareReconciler periodically compares the shares with the storage ACLs.
+func runShareReconciler() {
+	interval := time.Duration(gc.GetInt("share-reconciler-interval")) * time.Second
+	repair := gc.GetBool("share-reconciler-repair")
+	for {
+		time.Sleep(interval)
+		logger.Info("running share reconciler", zap.Bool("repair", repair))
+		if _, err := shareReconciler.Reconcile(context.Background(), repair); err != nil {
+			logger.Error("error running share reconciler", zap.Error(err))
+		}
+	}
+}
+
 func getMountTable(gc *goconfig.GoConfig) *api.MountTable {
 	mountFile := gc.GetString("mount-table")
 	contents, err := ioutil.ReadFile(mountFile)
@@ -224,7 +309,7 @@ func loadMountTable(mt *api.MountTable) error {
 			if err != nil {
 				panic(err)
 			}
-			storage := storage_public_link.New(opts, vs, publicLinkManager, logger)
+			storage := storage_public_link.New(opts, vs, publicLinkManager, notifier, logger)
 
 			storage, err = applyStorageWrappers(storage, mte.StorageWrappers)
 			if err != nil {
@@ -250,6 +335,29 @@ func loadMountTable(mt *api.MountTable) error {
 				panic(err)
 			}
 
+			mount := mount.New(mte.MountID, mte.MountPoint, mte.MountOptions, storage)
+			mounts = append(mounts, mount)
+		case "ocm":
+			if !gc.GetBool("ocm-enabled") {
+				logger.Warn("ocm is disabled, skipping mount", zap.String("mount_id", mte.MountID))
+				continue
+			}
+			bytes, err := json.Marshal(mte.StorageOptions)
+			if err != nil {
+				panic(err)
+			}
+			opts := &storage_ocm.Options{}
+			err = json.Unmarshal(bytes, opts)
+			if err != nil {
+				panic(err)
+			}
+			storage := storage_ocm.New(opts, remoteShareManager, ocmClient, logger)
+
+			storage, err = applyStorageWrappers(storage, mte.StorageWrappers)
+			if err != nil {
+				panic(err)
+			}
+
 			mount := mount.New(mte.MountID, mte.MountPoint, mte.MountOptions, storage)
 			mounts = append(mounts, mount)
 
@@ -323,6 +431,9 @@ func getAuthFunc(tm api.TokenManager) func(context.Context) (context.Context, er
 			user, err := tm.DismantleUserToken(ctx, token)
 			if err == nil {
 				grpc_ctxtags.Extract(ctx).Set("auth.accountid", user.AccountId)
+				if user.Impersonator != "" {
+					grpc_ctxtags.Extract(ctx).Set("auth.impersonator", user.Impersonator)
+				}
 				uuid := uuid.Must(uuid.NewV4())
 				tid := uuid.String()
 				grpc_ctxtags.Extract(ctx).Set("tid", tid)
@@ -339,11 +450,16 @@ func getAuthFunc(tm api.TokenManager) func(context.Context) (context.Context, er
 				return nil, grpc.Errorf(codes.Unauthenticated, "invalid pl auth token: %v", err)
 			}
 
+			// the link may have been revoked or modified after the token was forged
+			if err := publicLinkValidator.ValidatePublicLink(ctx, pl); err != nil {
+				return nil, grpc.Errorf(codes.Unauthenticated, "invalid pl auth token: %v", err)
+			}
+
 			grpc_ctxtags.Extract(ctx).Set("auth.accountid", pl.Token)
 			uuid, _ := uuid.NewV4()
 			tid := uuid.String()
 			grpc_ctxtags.Extract(ctx).Set("tid", tid)
-			newCtx := api.ContextSetPublicLink(ctx, pl)
+			newCtx := setForwardedMetadata(ctx, api.ContextSetPublicLink(ctx, pl))
 
 			// we set the user context as well from the owner of the link
 			newCtx = api.ContextSetUser(newCtx, &api.User{AccountId: pl.OwnerId, Groups: []string{}})
@@ -355,6 +471,273 @@ func getAuthFunc(tm api.TokenManager) func(context.Context) (context.Context, er
 	}
 }
 
+// setForwardedMetadata sets in newCtx the client IP and the uploader name forwarded
+// in the metadata of ctx, only if the call comes from a trusted proxy, otherwise
+// the client IP is the address of the peer and the uploader name is ignored.
+func setForwardedMetadata(ctx, newCtx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	// the client IP is forwarded by the proxies to tell apart the visitors of the link
+	var claimed string
+	if len(md["x-client-ip"]) > 0 {
+		claimed = md["x-client-ip"][0]
+	}
+	if ip := api.GetClientIP(ctx, trustedProxies, claimed); ip != "" {
+		newCtx = api.ContextSetClientIP(newCtx, ip)
+	}
+
+	// the name the uploader entered, for the links putting the uploads in a folder per uploader
+	if len(md["x-uploader-name"]) > 0 && api.IsTrustedProxy(trustedProxies, api.GetPeerIP(ctx)) {
+		if name, err := url.PathUnescape(md["x-uploader-name"][0]); err == nil {
+			newCtx = api.ContextSetUploaderName(newCtx, name)
+		}
+	}
+	return newCtx
+}
+
+// guestMethods are the methods available to the guests besides the storage
+// and preview ones, that are restricted to the received shares.
+var guestMethods = map[string]bool{
+	"/api.Share/ListReceivedShares":                true,
+	"/api.Share/MountReceivedShare":                true,
+	"/api.Share/UnmountReceivedShare":              true,
+	"/api.Notification/GetNotificationPreferences": true,
+	"/api.Notification/SetNotificationPreferences": true,
+}
+
+// guestDeniedStorageMethods have no path to check or change the acls.
+var guestDeniedStorageMethods = map[string]bool{
+	"/api.Storage/RestoreRecycleEntry": true,
+	"/api.Storage/SetACL":              true,
+	"/api.Storage/UpdateACL":           true,
+	"/api.Storage/UnsetACL":            true,
+}
+
+// readOnlyMethods are the methods available to the users logged in with a read-only scope.
+var readOnlyMethods = map[string]bool{
+	"/api.Storage/Inspect":                         true,
+	"/api.Storage/ListFolder":                      true,
+	"/api.Storage/ReadFile":                        true,
+	"/api.Storage/ListRevisions":                   true,
+	"/api.Storage/ReadRevision":                    true,
+	"/api.Storage/ListRecycle":                     true,
+	"/api.Storage/GetQuota":                        true,
+	"/api.Preview/ReadPreview":                     true,
+	"/api.Tagger/GetTags":                          true,
+	"/api.Share/InspectPublicLink":                 true,
+	"/api.Share/ListPublicLinks":                   true,
+	"/api.Share/ListFolderShares":                  true,
+	"/api.Share/GetFolderShare":                    true,
+	"/api.Share/ListReceivedShares":                true,
+	"/api.OCM/ListRemoteShares":                    true,
+	"/api.OCM/GetRemoteShare":                      true,
+	"/api.Notification/GetNotificationPreferences": true,
+	"/api.Directory/GetUser":                       true,
+	"/api.Directory/GetUserAvatar":                 true,
+	"/api.Directory/Search":                        true,
+	"/api.Directory/ListGroupMembers":              true,
+}
+
+// pathlessScopedMethods are the storage methods without a path available to the users
+// logged in with a scope restricted to a path, the uploads are checked when they are
+// committed with FinishWriteTx.
+var pathlessScopedMethods = map[string]bool{
+	"/api.Storage/StartWriteTx": true,
+	"/api.Storage/WriteChunk":   true,
+}
+
+// impersonationDeniedMethods are the methods changing shares, not available to the
+// admins impersonating a user. The moves in the received shares, that rename them,
+// are checked apart.
+var impersonationDeniedMethods = map[string]bool{
+	"/api.Storage/SetACL":             true,
+	"/api.Storage/UpdateACL":          true,
+	"/api.Storage/UnsetACL":           true,
+	"/api.Share/CreatePublicLink":     true,
+	"/api.Share/UpdatePublicLink":     true,
+	"/api.Share/RevokePublicLink":     true,
+	"/api.Share/AddFolderShare":       true,
+	"/api.Share/UpdateFolderShare":    true,
+	"/api.Share/UnshareFolder":        true,
+	"/api.Share/MountReceivedShare":   true,
+	"/api.Share/UnmountReceivedShare": true,
+	"/api.OCM/AddRemoteShare":         true,
+	"/api.OCM/RemoveRemoteShare":      true,
+	"/api.OCM/AcceptRemoteShare":      true,
+	"/api.OCM/RejectRemoteShare":      true,
+}
+
+// checkAccess returns an error if the user of the context cannot call the method with the request.
+func checkAccess(ctx context.Context, method string, req interface{}) error {
+	if err := checkImpersonation(ctx, method, req); err != nil {
+		return err
+	}
+	if err := checkGuest(ctx, method, req); err != nil {
+		return err
+	}
+	return checkScope(ctx, method, req)
+}
+
+// checkImpersonation returns an error if the user of the context is impersonated
+// by an admin and the method changes shares.
+func checkImpersonation(ctx context.Context, method string, req interface{}) error {
+	u, ok := api.ContextGetUser(ctx)
+	if !ok || u.Impersonator == "" {
+		return nil
+	}
+	if impersonationDeniedMethods[method] {
+		return grpc.Errorf(codes.PermissionDenied, "method %s not allowed when impersonating a user", method)
+	}
+	if method == "/api.Storage/Move" {
+		for _, p := range getRequestPaths(req) {
+			if isReceivedSharesPath(p) {
+				return grpc.Errorf(codes.PermissionDenied, "received shares cannot be renamed when impersonating a user")
+			}
+		}
+	}
+	return nil
+}
+
+// auditImpersonation logs the calls of the admins impersonating a user with both identities.
+func auditImpersonation(ctx context.Context, method string) {
+	if u, ok := api.ContextGetUser(ctx); ok && u.Impersonator != "" {
+		ctx_zap.Extract(ctx).Info("audit: call as impersonated user", zap.String("method", method), zap.String("account_id", u.AccountId), zap.String("impersonator", u.Impersonator))
+	}
+}
+
+// checkGuest returns an error if the user of the context is a guest that cannot
+// call the method with the request, guests only see what was shared with them.
+func checkGuest(ctx context.Context, method string, req interface{}) error {
+	u, ok := api.ContextGetUser(ctx)
+	if !ok || !u.Guest || strings.HasPrefix(method, "/api.Auth/") || guestMethods[method] {
+		return nil
+	}
+
+	if !strings.HasPrefix(method, "/api.Storage/") && !strings.HasPrefix(method, "/api.Preview/") || guestDeniedStorageMethods[method] {
+		return grpc.Errorf(codes.PermissionDenied, "method %s not allowed for guests", method)
+	}
+
+	for _, p := range getRequestPaths(req) {
+		if !isReceivedSharesPath(p) {
+			return grpc.Errorf(codes.PermissionDenied, "path %s not allowed for guests", p)
+		}
+	}
+	return nil
+}
+
+// checkScope returns an error if the user of the context logged in with a scoped
+// credential, like an app password or the token of an app, that does not allow to call
+// the method with the request. The scopes restricted to a path or a resource only allow
+// the storage, preview and tag methods, with the paths under them. The id-based paths
+// are only allowed under a resource, as their tree path is not known here.
+func checkScope(ctx context.Context, method string, req interface{}) error {
+	u, ok := api.ContextGetUser(ctx)
+	if !ok || u.Scope == nil || strings.HasPrefix(method, "/api.Auth/") {
+		return nil
+	}
+	scope := u.Scope
+
+	if scope.ReadOnly && !readOnlyMethods[method] {
+		return grpc.Errorf(codes.PermissionDenied, "method %s not allowed for read-only tokens", method)
+	}
+	if scope.Path == "" && scope.ResourceId == "" {
+		return nil
+	}
+
+	if !strings.HasPrefix(method, "/api.Storage/") && !strings.HasPrefix(method, "/api.Preview/") && !strings.HasPrefix(method, "/api.Tagger/") || guestDeniedStorageMethods[method] {
+		return grpc.Errorf(codes.PermissionDenied, "method %s not allowed for tokens restricted to a path", method)
+	}
+	paths := getRequestPaths(req)
+	if len(paths) == 0 && !pathlessScopedMethods[method] {
+		return grpc.Errorf(codes.PermissionDenied, "method %s not allowed for tokens restricted to a path", method)
+	}
+	for _, p := range paths {
+		if !(scope.Path != "" && isScopePath(p, scope.Path)) && !(scope.ResourceId != "" && isScopeResource(p, scope.ResourceId)) {
+			return grpc.Errorf(codes.PermissionDenied, "path %s not allowed for this token", p)
+		}
+	}
+	return nil
+}
+
+// isScopeResource returns true if the id-based path is the resource of the scope or is under it.
+func isScopeResource(p, resourceID string) bool {
+	if strings.HasPrefix(p, "/") {
+		return false
+	}
+	p = path.Clean(p)
+	return p == resourceID || strings.HasPrefix(p, resourceID+"/")
+}
+
+// getRequestPaths returns the paths the request acts on.
+func getRequestPaths(req interface{}) []string {
+	paths := []string{}
+	if r, ok := req.(interface{ GetPath() string }); ok {
+		paths = append(paths, r.GetPath())
+	}
+	if r, ok := req.(interface {
+		GetOldPath() string
+		GetNewPath() string
+	}); ok {
+		paths = append(paths, r.GetOldPath(), r.GetNewPath())
+	}
+	return paths
+}
+
+// isScopePath returns true if the path is the path of the scope or is under it.
+func isScopePath(p, scopePath string) bool {
+	if !strings.HasPrefix(p, "/") {
+		return false
+	}
+	p = path.Clean(p)
+	scopePath = path.Clean(scopePath)
+	return p == scopePath || strings.HasPrefix(p, strings.TrimSuffix(scopePath, "/")+"/")
+}
+
+// isReceivedSharesPath returns true if the path, or the id-based path, is in the mount of the received shares.
+func isReceivedSharesPath(p string) bool {
+	prefix := gc.GetString("guests-shares-prefix")
+	p = path.Clean(p)
+	if p == prefix || strings.HasPrefix(p, prefix+"/") {
+		return true
+	}
+	m, err := vs.GetMount(prefix)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(p, m.GetMountPointId()+":")
+}
+
+func getAccessUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		auditImpersonation(ctx, info.FullMethod)
+		if err := checkAccess(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func getAccessStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		auditImpersonation(stream.Context(), info.FullMethod)
+		return handler(srv, &accessServerStream{ServerStream: stream, method: info.FullMethod})
+	}
+}
+
+// accessServerStream checks the messages received on a stream, as the
+// requests of the streaming methods are not known by the interceptor.
+type accessServerStream struct {
+	grpc.ServerStream
+	method string
+}
+
+func (s *accessServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkAccess(s.Context(), s.method, m)
+}
+
 func init() {
 	gc = goconfig.New()
 	gc.SetConfigName("revad")
@@ -376,10 +759,44 @@ func init() {
 	gc.Add("auth-manager-ldap-filter", "(samaccountname=%s)", "Filter for LDAP queries.")
 	gc.Add("auth-manager-ldap-bind-username", "DN=foo,OU=Users,OU=Organic Units,DC=cern,DC=ch", "Username to bind to LDAP.")
 	gc.Add("auth-manager-ldap-bind-password", "bar", "Password to bind to LDAP.")
+	gc.Add("auth-manager-ldap-ca-cert-file", "", "PEM file with the CA certificates trusted to sign the certificate of the LDAP server, empty uses the system ones")
+	gc.Add("auth-manager-ldap-server-name", "", "name expected in the certificate of the LDAP server, empty uses the hostname")
+	gc.Add("auth-manager-ldap-insecure", false, "if set the certificate of the LDAP server is not verified, only for testing")
+	gc.Add("auth-manager-ldap-display-name-attributes", "displayName,cn", "comma separated attributes tried in order to get the display name of the users")
+	gc.Add("auth-manager-ldap-groups-attribute", "memberOf", "attribute of the users with the DNs of their groups")
+	gc.Add("auth-manager-ldap-group-basedn", "", "Base DN for the group search, empty uses the base DN of the users")
+	gc.Add("auth-manager-ldap-group-filter", "", "if set the groups are searched with this filter, like (member=%s), with the DN of the user, instead of read from the groups attribute")
+	gc.Add("auth-manager-ldap-group-name-attribute", "cn", "attribute with the name of the groups found by the group search")
+	gc.Add("auth-manager-ldap-pool-size", 10, "maximum number of open connections to the LDAP server")
+	gc.Add("auth-manager-ldap-idle-timeout", 300, "time in seconds an unused LDAP connection is kept open")
+	gc.Add("auth-manager-ldap-connect-timeout", 5, "time in seconds to wait for a connection to the LDAP server")
+	gc.Add("auth-manager-ldap-request-timeout", 10, "time in seconds to wait for the responses of the LDAP server")
+	gc.Add("auth-manager-oidc-enabled", false, "if set the users can also authenticate with access tokens of the OpenID Connect issuer")
+	gc.Add("auth-manager-oidc-issuer", "https://auth.cern.ch/auth/realms/cern", "URL of the OpenID Connect issuer, it must match the iss claim of the tokens")
+	gc.Add("auth-manager-oidc-jwks-url", "", "URL of the signing keys of the issuer, if empty it is discovered from the issuer")
+	gc.Add("auth-manager-oidc-audience", "", "audience the tokens must have been issued for, usually the client id registered in the issuer, required")
+	gc.Add("auth-manager-oidc-account-id-claim", "preferred_username", "claim mapped to the account id of the users")
+	gc.Add("auth-manager-oidc-display-name-claim", "name", "claim mapped to the display name of the users")
+	gc.Add("auth-manager-oidc-groups-claim", "groups", "claim mapped to the groups of the users")
+	gc.Add("auth-manager-oidc-keys-refresh-interval", 3600, "time in seconds the signing keys of the issuer are cached")
 
 	gc.Add("user-manager", "cboxgroupd", "Implementation to use for the user manager")
 	gc.Add("user-manager-cboxgroupd-uri", "http://localhost:2002", "URI of the CERNBox Group Daemon")
 	gc.Add("user-manager-cboxgroupd-secret", "bar", "Secret to talk to the CERNBox Group Daemon")
+	gc.Add("user-manager-file-path", "/etc/revad/users.yaml", "JSON or YAML file with the users, their bcrypt password hashes and groups, for the file user and auth managers and user directory")
+	gc.Add("user-manager-file-reload-interval", 10, "time in seconds between the checks of the users file for changes")
+
+	gc.Add("user-directory", "cboxgroupd", "Implementation to use for the user directory, cboxgroupd uses the URI and secret of the cboxgroupd user manager, ldap the connection settings of the ldap auth manager and file the users file of the file user manager")
+	gc.Add("user-directory-ldap-user-filter", "(samaccountname=%s)", "filter to find a user, with the account id")
+	gc.Add("user-directory-ldap-user-search-filter", "(&(objectClass=user)(|(samaccountname=%s*)(displayName=%s*)(mail=%s*)))", "filter to search the users, every %s is replaced with the prefix")
+	gc.Add("user-directory-ldap-account-id-attribute", "samaccountname", "attribute with the account id of the users")
+	gc.Add("user-directory-ldap-display-name-attributes", "displayName,cn", "comma separated attributes tried in order to get the display name of the users")
+	gc.Add("user-directory-ldap-mail-attribute", "mail", "attribute with the e-mail address of the users")
+	gc.Add("user-directory-ldap-avatar-attribute", "thumbnailPhoto", "attribute with the picture of the users")
+	gc.Add("user-directory-ldap-group-basedn", "", "Base DN for the group search, empty uses the base DN of the ldap auth manager")
+	gc.Add("user-directory-ldap-group-search-filter", "(&(objectClass=group)(cn=%s*))", "filter to search the groups, every %s is replaced with the prefix")
+	gc.Add("user-directory-ldap-group-name-attribute", "cn", "attribute with the name of the groups")
+	gc.Add("user-directory-ldap-size-limit", 100, "maximum number of users, and of groups, returned by a search")
 
 	gc.Add("project-manager", "db", "Implementation to use for the project manager")
 	gc.Add("project-manager-db-username", "foo", "Username to access the database.")
@@ -389,7 +806,25 @@ func init() {
 	gc.Add("project-manager-db-name", "", "Name of the database.")
 
 	gc.Add("token-manager", "jwt", "Implementation to use for the token manager")
-	gc.Add("token-manager-jwt-secret", "bar", "Secret to sign JWT tokens.")
+	gc.Add("token-manager-jwt-secret", "", "Secret to sign JWT tokens with HS256 if there is no private key")
+	gc.Add("token-manager-jwt-accept-secret-tokens", false, "if set and there is a private key, the HS256 tokens signed with the secret are still accepted, like while moving from the secret to the private key")
+	gc.Add("token-manager-jwt-private-key-file", "", "PEM file with the RSA or EC private key to sign JWT tokens with RS256 or ES256")
+	gc.Add("token-manager-jwt-key-id", "", "kid of the private key, empty derives it from the key")
+	gc.Add("token-manager-jwt-public-keys", "", "comma separated <kid>=<PEM file> public keys also verifying the tokens, like the previous key during a rotation")
+	gc.Add("token-manager-jwt-user-token-lifetime", 3600, "time in seconds the user tokens are valid")
+	gc.Add("token-manager-jwt-public-link-token-lifetime", 3600, "time in seconds the public link tokens are valid, they never outlive the link")
+	gc.Add("token-manager-jwt-refresh-token-lifetime", 604800, "time in seconds the refresh tokens are valid")
+	gc.Add("token-manager-jwt-scoped-token-lifetime", 600, "time in seconds the tokens handed to the apps, restricted to a file or folder, are valid if the app does not ask for another lifetime")
+	gc.Add("token-manager-jwt-max-scoped-token-lifetime", 43200, "maximum time in seconds the apps can ask their tokens to be valid, like for the length of an editing session")
+	gc.Add("token-manager-jwt-impersonation-token-lifetime", 900, "time in seconds the tokens of the admins impersonating users are valid")
+	gc.Add("token-manager-jwt-revocation-cache-eviction", 10, "time in seconds the revocations are cached, tokens revoked by other instances are rejected after at most this time")
+	gc.Add("token-revocation-store", "memory", "Implementation to use for keeping the revoked tokens (db, memory, none), memory is only suited for a single instance, db must be reachable at startup, none disables the revocation and the refresh tokens")
+	gc.Add("token-revocation-store-memory-size", 100000, "maximum number of revoked tokens and users kept in memory")
+	gc.Add("token-revocation-store-db-username", "foo", "Username to access the database.")
+	gc.Add("token-revocation-store-db-password", "bar", "Password to access the database.")
+	gc.Add("token-revocation-store-db-hostname", "localhost", "Host where to access the database.")
+	gc.Add("token-revocation-store-db-port", 3306, "Port where to access the database.")
+	gc.Add("token-revocation-store-db-name", "", "Name of the database.")
 
 	gc.Add("public-link-manager", "owncloud", "Implementation to use for the public link manager")
 	gc.Add("public-link-manager-owncloud-db-username", "foo", "Username to access the owncloud database.")
@@ -399,6 +834,30 @@ func init() {
 	gc.Add("public-link-manager-owncloud-db-name", "owncloud", "Name of the owncloud database.")
 	gc.Add("public-link-manager-owncloud-cache-size", 1000000, "cache size for metadata operations of public link to files.")
 	gc.Add("public-link-manager-owncloud-cache-eviction", 86400, "cache eviction in seconds to purge elements.")
+	gc.Add("public-link-manager-owncloud-token-length", 15, "Length of the generated public link tokens.")
+	gc.Add("public-link-manager-json-file", "/var/lib/revad/public-links.json", "File where the json public link manager stores the links.")
+
+	gc.Add("share-manager", "owncloud", "Implementation to use for the share manager, owncloud or json. The owncloud one uses the public-link-manager-owncloud-db-* settings.")
+	gc.Add("share-manager-json-file", "/var/lib/revad/shares.json", "File where the json share manager stores the shares.")
+
+	gc.Add("public-link-validator-cache-size", 100000, "number of links cached to validate the public link tokens.")
+	gc.Add("public-link-validator-cache-eviction", 10, "time in seconds a link is cached to validate the public link tokens, revocations and updates are enforced after at most this time.")
+
+	gc.Add("auth-attempt-store", "memory", "Implementation to use for tracking failed password attempts on public links (memory, db, none)")
+	gc.Add("auth-attempt-store-expiration", 86400, "time in seconds the failed attempts of a link or client IP are remembered")
+	gc.Add("auth-attempt-store-memory-size", 100000, "maximum number of links and client IPs tracked in memory")
+	gc.Add("auth-attempt-store-db-username", "foo", "Username to access the database.")
+	gc.Add("auth-attempt-store-db-password", "bar", "Password to access the database.")
+	gc.Add("auth-attempt-store-db-hostname", "localhost", "Host where to access the database.")
+	gc.Add("auth-attempt-store-db-port", 3306, "Port where to access the database.")
+	gc.Add("auth-attempt-store-db-name", "", "Name of the database.")
+	gc.Add("public-link-auth-free-attempts", 5, "failed password attempts allowed on a link or from a client IP before throttling")
+	gc.Add("public-link-auth-backoff", 1, "initial back-off in seconds after the free attempts, doubled with every failure")
+	gc.Add("public-link-auth-max-attempts", 20, "failed password attempts after which the client IP is locked")
+	gc.Add("public-link-auth-lockout", 900, "time in seconds a client IP stays locked")
+	gc.Add("public-link-auth-max-link-delay", 10, "maximum time in seconds the password attempts on a link are delayed after failures on it")
+	gc.Add("public-link-auth-max-concurrent-attempts", 1, "password attempts on a link checked at the same time, the others are refused")
+	gc.Add("trusted-proxies", "", "comma separated IPs or CIDR networks of the proxies, like ocproxy, whose forwarded client IPs and uploader names are trusted")
 
 	gc.Add("tag-manager", "db", "Implementation to use for the tag manager")
 	gc.Add("tag-manager-db-username", "foo", "Username to access the  database.")
@@ -424,41 +883,267 @@ func init() {
 	gc.Add("mig-eoshome-homedir-script", "/root/eoshome-homedir-creation.sh", "script to create home directory on EOSHOME")
 	gc.Add("mig-eoshome-homedir-script-enabled", false, "if set enables creation of home dirs in EOSHOME")
 
+	gc.Add("admin-group", "", "Group whose members are allowed to use the admin service. Empty means nobody.")
+	gc.Add("impersonation-group", "", "Group whose members are allowed to impersonate other users, without changing their shares. Empty means nobody.")
+
+	gc.Add("share-reconciler-enabled", false, "if set runs the share reconciler periodically in background")
+	gc.Add("share-reconciler-interval", 86400, "interval in seconds between runs of the share reconciler")
+	gc.Add("share-reconciler-repair", false, "if set the background share reconciler repairs the drifts instead of only reporting them")
+	gc.Add("share-reconciler-ignored-recipients", "", "comma separated list of grants not managed by the share manager, like group:cernbox-admins")
+
+	gc.Add("ownership-transfer-home-prefix", "/home", "mount point of the homes, transferred data goes to <prefix>/transferred-from-<user> in the home of the new owner")
+
+	gc.Add("ocm-enabled", false, "if set enables federated sharing with other servers using the Open Cloud Mesh protocol")
+	gc.Add("ocm-domain", "localhost", "domain of this server used in the federated cloud ids of the local users, like cernbox.cern.ch")
+	gc.Add("ocm-timeout", 10, "timeout in seconds for the requests to remote servers")
+	gc.Add("ocm-provider-cache-eviction", 3600, "time in seconds the discovery information of remote servers is cached")
+	gc.Add("ocm-insecure", false, "if set skips the verification of the TLS certificates of remote servers")
+	gc.Add("ocm-shared-secret", "", "secret ocproxy authenticates with to relay the shares and notifications of remote servers, empty rejects them")
+	gc.Add("ocm-trusted-providers", "", "comma separated list of remote servers allowed to share with local users, empty rejects every server")
+
+	gc.Add("notifications-enabled", false, "if set e-mails the recipients of new shares, and the recipients given by link creators")
+	gc.Add("notifications-smtp-server", "cernmx.cern.ch:25", "SMTP server where to send the notifications, like localhost:1025 for a local sink")
+	gc.Add("notifications-from-address", "cernbox-noreply@cern.ch", "The sender of the notifications (FROM header)")
+	gc.Add("notifications-mail-domain", "cern.ch", "domain appended to the account ids to get the e-mail addresses of the users")
+	gc.Add("notifications-share-url", "https://cernbox.cern.ch/index.php/apps/files/?dir=/__myshares/", "prefix of the links to received shares sent in the notifications")
+	gc.Add("notifications-public-link-url", "https://cernbox.cern.ch/index.php/s/", "prefix of the public links sent in the notifications")
+	gc.Add("notifications-templates-folder", "", "folder with a sub folder per language containing <kind>.tmpl templates, english ones are built in")
+	gc.Add("notifications-default-language", "en", "language of the notifications for users without preference")
+	gc.Add("notifications-preferences-file", "/var/lib/revad/notification-preferences.json", "file where the notification preferences of the users are stored")
+	gc.Add("notifications-queue-size", 1000, "maximum number of notifications waiting to be sent")
+	gc.Add("notifications-workers", 2, "number of notifications sent in parallel")
+	gc.Add("notifications-max-retries", 5, "number of times a notification that could not be sent is retried, negative disables the retries")
+	gc.Add("notifications-retry-interval", 30, "time in seconds before the first retry, doubled on each retry")
+	gc.Add("notifications-digest-interval", 3600, "time in seconds the uploads to drop only links are collected before e-mailing their digest to the owners")
+	gc.Add("notifications-max-link-recipients", 20, "maximum number of addresses a public link can be sent to at once")
+	gc.Add("notifications-max-link-recipients-per-hour", 100, "maximum number of addresses a user can send public links to in an hour")
+
+	gc.Add("guests-enabled", false, "if set the folders can be shared with e-mail addresses, inviting the recipients as guests")
+	gc.Add("guests-db-username", "foo", "Username to access the database.")
+	gc.Add("guests-db-password", "bar", "Password to access the database.")
+	gc.Add("guests-db-hostname", "localhost", "Host where to access the database.")
+	gc.Add("guests-db-port", 3306, "Port where to access the database.")
+	gc.Add("guests-db-name", "", "Name of the database.")
+	gc.Add("guests-activation-expiration", 604800, "time in seconds the guests have to activate their accounts with the token of the invitation")
+	gc.Add("guests-activation-url", "https://cernbox.cern.ch/index.php/apps/guests/activate?token=", "prefix of the activation links sent in the invitations")
+	gc.Add("guests-shares-prefix", "/shared-with-me", "mount point of the received shares, the only path the guests can access")
+
+	gc.Add("app-passwords-enabled", false, "if set the users can create app passwords to log in their clients and scripts")
+	gc.Add("app-passwords-db-username", "foo", "Username to access the database.")
+	gc.Add("app-passwords-db-password", "bar", "Password to access the database.")
+	gc.Add("app-passwords-db-hostname", "localhost", "Host where to access the database.")
+	gc.Add("app-passwords-db-port", 3306, "Port where to access the database.")
+	gc.Add("app-passwords-db-name", "", "Name of the database.")
+
 	gc.Add("svc-storage-tx-temporary-folder", "", "temporary folder to create and assemble write tx, if default, assumes os.Tempdir")
+}
 
+// setup reads the configuration and creates the managers, it is not part of init
+// so the tests of the package do not need a configuration.
+func setup() {
 	gc.BindFlags()
 	gc.ReadConfig()
 
 	logger = gologger.New(gc.GetString("log-level"), gc.GetString("app-log"))
 
+	trustedProxies = getTrustedProxies()
 	vs = virtual_storage.NewVFS(logger)
 	userManager = getUserManager()
+	userDirectory = getUserDirectory()
+	ocmClient = getOCMClient()
+	remoteShareManager = getRemoteShareManager()
 	shareManager = getShareManager()
 	publicLinkManager = getPublicLinkManager()
+	publicLinkValidator = public_link_validator.New(publicLinkManager, gc.GetInt("public-link-validator-cache-size"), gc.GetInt("public-link-validator-cache-eviction"))
 	projectManager = getProjectManager()
 	tokenManager = getTokenManager()
+	guestManager = getGuestManager()
+	appPasswordManager = getAppPasswordManager()
 	authManager = getAuthManager()
+	authAttemptStore = getAuthAttemptStore()
 	tagManager = getTagManager()
+	shareReconciler = getShareReconciler()
+	ownershipTransferer = getOwnershipTransferer()
+	notifier = getNotifier()
 }
 
 func getUserManager() api.UserManager {
-	userManagerOpt := &user_manager_cboxgroupd.Options{Logger: logger, CBOXGroupDaemonURI: gc.GetString("user-manager-cboxgroupd-uri"), CBOXGroupDaemonSecret: gc.GetString("user-manager-cboxgroupd-secret")}
-	userManager := user_manager_cboxgroupd.New(userManagerOpt)
-	return userManager
+	driver := gc.GetString("user-manager")
+	switch driver {
+	case "cboxgroupd":
+		userManagerOpt := &user_manager_cboxgroupd.Options{Logger: logger, CBOXGroupDaemonURI: gc.GetString("user-manager-cboxgroupd-uri"), CBOXGroupDaemonSecret: gc.GetString("user-manager-cboxgroupd-secret")}
+		userManager := user_manager_cboxgroupd.New(userManagerOpt)
+		return userManager
+	case "file":
+		return getFileUserManager()
+	default:
+		panic("user manager driver not found: " + driver)
+	}
+}
+
+func getUserDirectory() api.UserDirectory {
+	driver := gc.GetString("user-directory")
+	switch driver {
+	case "cboxgroupd":
+		opt := &user_directory_cboxgroupd.Options{Logger: logger, CBOXGroupDaemonURI: gc.GetString("user-manager-cboxgroupd-uri"), CBOXGroupDaemonSecret: gc.GetString("user-manager-cboxgroupd-secret")}
+		return user_directory_cboxgroupd.New(opt)
+	case "ldap":
+		opt := &user_directory_ldap.Options{
+			BaseDN:                gc.GetString("auth-manager-ldap-basedn"),
+			UserFilter:            gc.GetString("user-directory-ldap-user-filter"),
+			UserSearchFilter:      gc.GetString("user-directory-ldap-user-search-filter"),
+			AccountIDAttribute:    gc.GetString("user-directory-ldap-account-id-attribute"),
+			DisplayNameAttributes: getList("user-directory-ldap-display-name-attributes"),
+			MailAttribute:         gc.GetString("user-directory-ldap-mail-attribute"),
+			AvatarAttribute:       gc.GetString("user-directory-ldap-avatar-attribute"),
+			GroupBaseDN:           gc.GetString("user-directory-ldap-group-basedn"),
+			GroupSearchFilter:     gc.GetString("user-directory-ldap-group-search-filter"),
+			GroupNameAttribute:    gc.GetString("user-directory-ldap-group-name-attribute"),
+			SizeLimit:             gc.GetInt("user-directory-ldap-size-limit"),
+		}
+		opt.Options = ldapclient.Options{
+			Logger:         logger,
+			Hostname:       gc.GetString("auth-manager-ldap-hostname"),
+			Port:           gc.GetInt("auth-manager-ldap-port"),
+			BindUsername:   gc.GetString("auth-manager-ldap-bind-username"),
+			BindPassword:   gc.GetString("auth-manager-ldap-bind-password"),
+			CACertFile:     gc.GetString("auth-manager-ldap-ca-cert-file"),
+			ServerName:     gc.GetString("auth-manager-ldap-server-name"),
+			Insecure:       gc.GetBool("auth-manager-ldap-insecure"),
+			PoolSize:       gc.GetInt("auth-manager-ldap-pool-size"),
+			IdleTimeout:    gc.GetInt("auth-manager-ldap-idle-timeout"),
+			ConnectTimeout: gc.GetInt("auth-manager-ldap-connect-timeout"),
+			RequestTimeout: gc.GetInt("auth-manager-ldap-request-timeout"),
+		}
+		ud, err := user_directory_ldap.New(opt)
+		if err != nil {
+			panic(err)
+		}
+		return ud
+	case "file":
+		return getFileUserManager().(api.UserDirectory)
+	default:
+		panic("user directory driver not found: " + driver)
+	}
+}
+
+var fileUserManager api.UserManager
+
+// getFileUserManager returns the file user manager, shared by the user and auth
+// managers and the user directory so the users file is only loaded once.
+func getFileUserManager() api.UserManager {
+	if fileUserManager == nil {
+		opt := &user_manager_file.Options{Logger: logger, File: gc.GetString("user-manager-file-path"), ReloadInterval: gc.GetInt("user-manager-file-reload-interval")}
+		um, err := user_manager_file.New(opt)
+		if err != nil {
+			panic(err)
+		}
+		fileUserManager = um
+	}
+	return fileUserManager
 }
 func getShareManager() api.ShareManager {
-	shareManager, err := share_manager_owncloud.New(gc.GetString("public-link-manager-owncloud-db-username"), gc.GetString("public-link-manager-owncloud-db-password"), gc.GetString("public-link-manager-owncloud-db-hostname"), gc.GetInt("public-link-manager-owncloud-db-port"), gc.GetString("public-link-manager-owncloud-db-name"), vs, userManager)
+	driver := gc.GetString("share-manager")
+	switch driver {
+	case "owncloud":
+		shareManager, err := share_manager_owncloud.New(gc.GetString("public-link-manager-owncloud-db-username"), gc.GetString("public-link-manager-owncloud-db-password"), gc.GetString("public-link-manager-owncloud-db-hostname"), gc.GetInt("public-link-manager-owncloud-db-port"), gc.GetString("public-link-manager-owncloud-db-name"), vs, userManager, ocmClient, gc.GetString("ocm-domain"))
+		if err != nil {
+			panic(err)
+		}
+		return shareManager
+	case "json":
+		shareManager, err := share_manager_json.New(gc.GetString("share-manager-json-file"), vs, userManager)
+		if err != nil {
+			panic(err)
+		}
+		return shareManager
+	default:
+		panic("share manager driver not found: " + driver)
+	}
+}
+func getOCMClient() api.OCMProviderClient {
+	// a nil client disables the shares to remote recipients
+	if !gc.GetBool("ocm-enabled") {
+		return nil
+	}
+	opt := &ocm_client_http.Options{Logger: logger, Timeout: gc.GetInt("ocm-timeout"), ProviderCacheEviction: gc.GetInt("ocm-provider-cache-eviction"), InsecureSkipVerify: gc.GetBool("ocm-insecure")}
+	return ocm_client_http.New(opt)
+}
+func getRemoteShareManager() api.RemoteShareManager {
+	remoteShareManager, err := remote_share_manager_owncloud.New(gc.GetString("public-link-manager-owncloud-db-username"), gc.GetString("public-link-manager-owncloud-db-password"), gc.GetString("public-link-manager-owncloud-db-hostname"), gc.GetInt("public-link-manager-owncloud-db-port"), gc.GetString("public-link-manager-owncloud-db-name"))
 	if err != nil {
 		panic(err)
 	}
-	return shareManager
+	return remoteShareManager
 }
-func getPublicLinkManager() api.PublicLinkManager {
-	publicLinkManager, err := public_link_manager_owncloud.New(gc.GetString("public-link-manager-owncloud-db-username"), gc.GetString("public-link-manager-owncloud-db-password"), gc.GetString("public-link-manager-owncloud-db-hostname"), gc.GetInt("public-link-manager-owncloud-db-port"), gc.GetString("public-link-manager-owncloud-db-name"), gc.GetInt("public-link-manager-owncloud-cache-size"), gc.GetInt("public-link-manager-owncloud-cache-eviction"), vs)
+func getShareReconciler() api.ShareReconciler {
+	opt := &share_reconciler.Options{Logger: logger, IgnoredRecipients: getList("share-reconciler-ignored-recipients")}
+	return share_reconciler.New(opt, shareManager, vs)
+}
+
+// getList returns the non empty values of a comma separated config key.
+func getList(key string) []string {
+	values := []string{}
+	for _, v := range strings.Split(gc.GetString(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+func getOwnershipTransferer() api.OwnershipTransferer {
+	opt := &ownership_transferer.Options{Logger: logger, HomePrefix: gc.GetString("ownership-transfer-home-prefix")}
+	return ownership_transferer.New(opt, vs, shareManager, publicLinkManager)
+}
+func getNotifier() api.Notifier {
+	// a nil notifier disables the notifications
+	if !gc.GetBool("notifications-enabled") {
+		return nil
+	}
+	opt := &notifier_smtp.Options{
+		Logger:             logger,
+		Server:             gc.GetString("notifications-smtp-server"),
+		From:               gc.GetString("notifications-from-address"),
+		MailDomain:         gc.GetString("notifications-mail-domain"),
+		ShareURL:           gc.GetString("notifications-share-url"),
+		PublicLinkURL:      gc.GetString("notifications-public-link-url"),
+		GuestActivationURL: gc.GetString("guests-activation-url"),
+		TemplatesFolder:    gc.GetString("notifications-templates-folder"),
+		DefaultLanguage:    gc.GetString("notifications-default-language"),
+		PreferencesFile:    gc.GetString("notifications-preferences-file"),
+		QueueSize:          gc.GetInt("notifications-queue-size"),
+		Workers:            gc.GetInt("notifications-workers"),
+		MaxRetries:         gc.GetInt("notifications-max-retries"),
+		RetryInterval:      gc.GetInt("notifications-retry-interval"),
+		DigestInterval:     gc.GetInt("notifications-digest-interval"),
+
+		MaxLinkRecipients:        gc.GetInt("notifications-max-link-recipients"),
+		MaxLinkRecipientsPerHour: gc.GetInt("notifications-max-link-recipients-per-hour"),
+	}
+	n, err := notifier_smtp.New(opt, userManager)
 	if err != nil {
 		panic(err)
 	}
-	return publicLinkManager
+	return n
+}
+func getPublicLinkManager() api.PublicLinkManager {
+	driver := gc.GetString("public-link-manager")
+	switch driver {
+	case "owncloud":
+		publicLinkManager, err := public_link_manager_owncloud.New(gc.GetString("public-link-manager-owncloud-db-username"), gc.GetString("public-link-manager-owncloud-db-password"), gc.GetString("public-link-manager-owncloud-db-hostname"), gc.GetInt("public-link-manager-owncloud-db-port"), gc.GetString("public-link-manager-owncloud-db-name"), gc.GetInt("public-link-manager-owncloud-cache-size"), gc.GetInt("public-link-manager-owncloud-cache-eviction"), gc.GetInt("public-link-manager-owncloud-token-length"), vs)
+		if err != nil {
+			panic(err)
+		}
+		return publicLinkManager
+	case "json":
+		publicLinkManager, err := public_link_manager_json.New(gc.GetString("public-link-manager-json-file"), gc.GetInt("public-link-manager-owncloud-token-length"), vs)
+		if err != nil {
+			panic(err)
+		}
+		return publicLinkManager
+	default:
+		panic("public link manager driver not found: " + driver)
+	}
 }
 func getProjectManager() api.ProjectManager {
 	projectManager := project_manager_db.New(gc.GetString("project-manager-db-username"), gc.GetString("project-manager-db-password"), gc.GetString("project-manager-db-hostname"), gc.GetInt("project-manager-db-port"), gc.GetString("project-manager-db-name"), vs)
@@ -466,25 +1151,176 @@ func getProjectManager() api.ProjectManager {
 }
 
 func getTokenManager() api.TokenManager {
-	tokenManager := token_manager_jwt.New(gc.GetString("token-manager-jwt-secret"))
+	publicKeys := map[string]string{}
+	for _, v := range getList("token-manager-jwt-public-keys") {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 {
+			panic("invalid public key, expected <kid>=<file>: " + v)
+		}
+		publicKeys[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	opt := &token_manager_jwt.Options{
+		Secret:                     gc.GetString("token-manager-jwt-secret"),
+		AcceptSecretTokens:         gc.GetBool("token-manager-jwt-accept-secret-tokens"),
+		PrivateKeyFile:             gc.GetString("token-manager-jwt-private-key-file"),
+		KeyID:                      gc.GetString("token-manager-jwt-key-id"),
+		PublicKeyFiles:             publicKeys,
+		UserTokenLifetime:          gc.GetInt("token-manager-jwt-user-token-lifetime"),
+		PublicLinkTokenLifetime:    gc.GetInt("token-manager-jwt-public-link-token-lifetime"),
+		RefreshTokenLifetime:       gc.GetInt("token-manager-jwt-refresh-token-lifetime"),
+		ScopedTokenLifetime:        gc.GetInt("token-manager-jwt-scoped-token-lifetime"),
+		MaxScopedTokenLifetime:     gc.GetInt("token-manager-jwt-max-scoped-token-lifetime"),
+		ImpersonationTokenLifetime: gc.GetInt("token-manager-jwt-impersonation-token-lifetime"),
+		RevocationStore:            getTokenRevocationStore(),
+		RevocationCacheEviction:    gc.GetInt("token-manager-jwt-revocation-cache-eviction"),
+	}
+	tokenManager, err := token_manager_jwt.New(opt)
+	if err != nil {
+		panic(err)
+	}
 	return tokenManager
 }
 func getAuthManager() api.AuthManager {
+	var am api.AuthManager
 	driver := gc.GetString("auth-manager")
 	switch driver {
 	case "impersonate":
-		return auth_manager_impersonate.New()
+		am = auth_manager_impersonate.New()
+	case "file":
+		am = getFileUserManager().(api.AuthManager)
 	case "ldap":
-		hostname := gc.GetString("auth-manager-ldap-hostname")
-		port := gc.GetInt("auth-manager-ldap-port")
-		baseDN := gc.GetString("auth-manager-ldap-basedn")
-		filter := gc.GetString("auth-manager-ldap-filter")
-		bindUsername := gc.GetString("auth-manager-ldap-bind-username")
-		bindPassword := gc.GetString("auth-manager-ldap-bind-password")
-		return auth_manager_ldap.New(hostname, port, baseDN, filter, bindUsername, bindPassword)
+		opt := &auth_manager_ldap.Options{
+			Logger:                logger,
+			Hostname:              gc.GetString("auth-manager-ldap-hostname"),
+			Port:                  gc.GetInt("auth-manager-ldap-port"),
+			BaseDN:                gc.GetString("auth-manager-ldap-basedn"),
+			Filter:                gc.GetString("auth-manager-ldap-filter"),
+			BindUsername:          gc.GetString("auth-manager-ldap-bind-username"),
+			BindPassword:          gc.GetString("auth-manager-ldap-bind-password"),
+			CACertFile:            gc.GetString("auth-manager-ldap-ca-cert-file"),
+			ServerName:            gc.GetString("auth-manager-ldap-server-name"),
+			Insecure:              gc.GetBool("auth-manager-ldap-insecure"),
+			DisplayNameAttributes: getList("auth-manager-ldap-display-name-attributes"),
+			GroupsAttribute:       gc.GetString("auth-manager-ldap-groups-attribute"),
+			GroupBaseDN:           gc.GetString("auth-manager-ldap-group-basedn"),
+			GroupFilter:           gc.GetString("auth-manager-ldap-group-filter"),
+			GroupNameAttribute:    gc.GetString("auth-manager-ldap-group-name-attribute"),
+			PoolSize:              gc.GetInt("auth-manager-ldap-pool-size"),
+			IdleTimeout:           gc.GetInt("auth-manager-ldap-idle-timeout"),
+			ConnectTimeout:        gc.GetInt("auth-manager-ldap-connect-timeout"),
+			RequestTimeout:        gc.GetInt("auth-manager-ldap-request-timeout"),
+		}
+		ldapManager, err := auth_manager_ldap.New(opt)
+		if err != nil {
+			panic(err)
+		}
+		am = ldapManager
 	default:
 		panic("auth manager driver not found: " + driver)
 	}
+
+	// the guests log in with their e-mail address, so they cannot use app passwords
+	if appPasswordManager != nil {
+		am = auth_manager_app_password.New(am, appPasswordManager, userManager)
+	}
+
+	if guestManager != nil {
+		am = auth_manager_guest.New(am, guestManager)
+	}
+
+	if gc.GetBool("auth-manager-oidc-enabled") {
+		opt := &auth_manager_oidc.Options{
+			Logger:           logger,
+			Issuer:           gc.GetString("auth-manager-oidc-issuer"),
+			JWKSURL:          gc.GetString("auth-manager-oidc-jwks-url"),
+			Audience:         gc.GetString("auth-manager-oidc-audience"),
+			AccountIDClaim:   gc.GetString("auth-manager-oidc-account-id-claim"),
+			DisplayNameClaim: gc.GetString("auth-manager-oidc-display-name-claim"),
+			GroupsClaim:      gc.GetString("auth-manager-oidc-groups-claim"),
+			RefreshInterval:  gc.GetInt("auth-manager-oidc-keys-refresh-interval"),
+		}
+		oidcManager, err := auth_manager_oidc.New(am, opt)
+		if err != nil {
+			panic(err)
+		}
+		am = oidcManager
+	}
+	return am
+}
+func getGuestManager() api.GuestManager {
+	// a nil manager disables the shares with guests
+	if !gc.GetBool("guests-enabled") {
+		return nil
+	}
+	gm, err := guest_manager_db.New(gc.GetString("guests-db-username"), gc.GetString("guests-db-password"), gc.GetString("guests-db-hostname"), gc.GetInt("guests-db-port"), gc.GetString("guests-db-name"), gc.GetInt("guests-activation-expiration"))
+	if err != nil {
+		panic(err)
+	}
+	return gm
+}
+func getAppPasswordManager() api.AppPasswordManager {
+	// a nil manager disables the app passwords
+	if !gc.GetBool("app-passwords-enabled") {
+		return nil
+	}
+	apm, err := app_password_manager_db.New(gc.GetString("app-passwords-db-username"), gc.GetString("app-passwords-db-password"), gc.GetString("app-passwords-db-hostname"), gc.GetInt("app-passwords-db-port"), gc.GetString("app-passwords-db-name"))
+	if err != nil {
+		panic(err)
+	}
+	return apm
+}
+func getTokenRevocationStore() api.TokenRevocationStore {
+	driver := gc.GetString("token-revocation-store")
+	switch driver {
+	case "none":
+		return nil
+	case "memory":
+		return token_revocation_store_memory.New(gc.GetInt("token-revocation-store-memory-size"))
+	case "db":
+		store, err := token_revocation_store_db.New(gc.GetString("token-revocation-store-db-username"), gc.GetString("token-revocation-store-db-password"), gc.GetString("token-revocation-store-db-hostname"), gc.GetInt("token-revocation-store-db-port"), gc.GetString("token-revocation-store-db-name"))
+		if err != nil {
+			panic(err)
+		}
+		return store
+	default:
+		panic("token revocation store driver not found: " + driver)
+	}
+}
+func getAuthAttemptStore() api.AuthAttemptStore {
+	driver := gc.GetString("auth-attempt-store")
+	switch driver {
+	case "none":
+		return nil
+	case "memory":
+		return auth_attempt_store_memory.New(gc.GetInt("auth-attempt-store-memory-size"), gc.GetInt("auth-attempt-store-expiration"))
+	case "db":
+		store, err := auth_attempt_store_db.New(gc.GetString("auth-attempt-store-db-username"), gc.GetString("auth-attempt-store-db-password"), gc.GetString("auth-attempt-store-db-hostname"), gc.GetInt("auth-attempt-store-db-port"), gc.GetString("auth-attempt-store-db-name"), gc.GetInt("auth-attempt-store-expiration"))
+		if err != nil {
+			panic(err)
+		}
+		return store
+	default:
+		panic("auth attempt store driver not found: " + driver)
+	}
+}
+func getThrottleOptions() *authsvc.ThrottleOptions {
+	return &authsvc.ThrottleOptions{
+		FreeAttempts:          gc.GetInt("public-link-auth-free-attempts"),
+		BackOff:               gc.GetInt("public-link-auth-backoff"),
+		MaxAttempts:           gc.GetInt("public-link-auth-max-attempts"),
+		Lockout:               gc.GetInt("public-link-auth-lockout"),
+		MaxLinkDelay:          gc.GetInt("public-link-auth-max-link-delay"),
+		MaxConcurrentAttempts: gc.GetInt("public-link-auth-max-concurrent-attempts"),
+		TrustedProxies:        trustedProxies,
+	}
+}
+func getTrustedProxies() []*net.IPNet {
+	proxies, err := api.ParseTrustedProxies(getList("trusted-proxies"))
+	if err != nil {
+		panic(err)
+	}
+	return proxies
 }
 func getTagManager() api.TagManager {
 	tagManager := tag_manager_db.New(gc.GetString("tag-manager-db-username"), gc.GetString("tag-manager-db-password"), gc.GetString("tag-manager-db-hostname"), gc.GetInt("tag-manager-db-port"), gc.GetString("tag-manager-db-name"), vs)
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func scopedContext(scope *api.TokenScope) context.Context {
+	return api.ContextSetUser(context.Background(), &api.User{AccountId: "alice", Scope: scope})
+}
+
+// fakeVirtualStorage mounts the received shares with the id received.
+type fakeVirtualStorage struct {
+	api.VirtualStorage
+}
+
+func (vs *fakeVirtualStorage) GetMount(p string) (api.Mount, error) {
+	return &fakeMount{}, nil
+}
+
+type fakeMount struct {
+	api.Mount
+}
+
+func (m *fakeMount) GetMountPointId() string {
+	return "received"
+}
+
+func init() {
+	vs = &fakeVirtualStorage{}
+}
+
+func TestIsScopePath(t *testing.T) {
+	tests := []struct {
+		path, scope string
+		expected    bool
+	}{
+		{"/home/alice/notes.md", "/home/alice/notes.md", true},
+		{"/home/alice/project/a.txt", "/home/alice/project", true},
+		{"/home/alice/project/a.txt", "/home/alice/project/", true},
+		{"/home/alice/project2/a.txt", "/home/alice/project", false},
+		{"/home/alice/project/../secret.txt", "/home/alice/project", false},
+		{"123:/home/alice/project", "/home/alice/project", false},
+	}
+	for _, test := range tests {
+		if got := isScopePath(test.path, test.scope); got != test.expected {
+			t.Errorf("isScopePath(%q, %q) = %t, expected %t", test.path, test.scope, got, test.expected)
+		}
+	}
+}
+
+func TestIsScopeResource(t *testing.T) {
+	tests := []struct {
+		path, resource string
+		expected       bool
+	}{
+		{"eoshome:123", "eoshome:123", true},
+		{"eoshome:123/a.txt", "eoshome:123", true},
+		{"eoshome:1234", "eoshome:123", false},
+		{"eoshome:123/../456", "eoshome:123", false},
+		{"/home/alice", "eoshome:123", false},
+	}
+	for _, test := range tests {
+		if got := isScopeResource(test.path, test.resource); got != test.expected {
+			t.Errorf("isScopeResource(%q, %q) = %t, expected %t", test.path, test.resource, got, test.expected)
+		}
+	}
+}
+
+func TestCheckScope(t *testing.T) {
+	notes := &api.TokenScope{Path: "/home/alice/notes.md", ReadOnly: true}
+	project := &api.TokenScope{Path: "/home/alice/project"}
+	tests := []struct {
+		scope    *api.TokenScope
+		method   string
+		req      interface{}
+		expected bool
+	}{
+		{nil, "/api.Share/CreatePublicLink", &api.NewLinkReq{Path: "/home/alice"}, true},
+		{notes, "/api.Storage/ReadFile", &api.PathReq{Path: "/home/alice/notes.md"}, true},
+		{notes, "/api.Storage/ReadFile", &api.PathReq{Path: "/home/alice/other.md"}, false},
+		{notes, "/api.Storage/Delete", &api.PathReq{Path: "/home/alice/notes.md"}, false},
+		{notes, "/api.Auth/ForgeScopedToken", &api.ScopedTokenReq{}, true},
+		{project, "/api.Storage/Move", &api.MoveReq{OldPath: "/home/alice/project/a.txt", NewPath: "/home/alice/project/b.txt"}, true},
+		{project, "/api.Storage/Move", &api.MoveReq{OldPath: "/home/alice/project/a.txt", NewPath: "/home/alice/a.txt"}, false},
+		{project, "/api.Storage/StartWriteTx", &api.EmptyReq{}, true},
+		{project, "/api.Storage/EmptyRecycle", &api.PathReq{Path: "/home/alice"}, false},
+		{project, "/api.Share/CreatePublicLink", &api.NewLinkReq{Path: "/home/alice/project"}, false},
+		{project, "/api.Storage/SetACL", &api.ACLReq{Path: "/home/alice/project"}, false},
+	}
+	for _, test := range tests {
+		err := checkScope(scopedContext(test.scope), test.method, test.req)
+		if (err == nil) != test.expected {
+			t.Errorf("checkScope(%+v, %s, %+v) = %v, expected allowed %t", test.scope, test.method, test.req, err, test.expected)
+		}
+	}
+}
+
+func TestCheckGuest(t *testing.T) {
+	ctx := api.ContextSetUser(context.Background(), &api.User{AccountId: "bob@example.org", Guest: true})
+	tests := []struct {
+		method   string
+		req      interface{}
+		expected bool
+	}{
+		{"/api.Storage/ReadFile", &api.PathReq{Path: "/shared-with-me/project/a.txt"}, true},
+		{"/api.Storage/ReadFile", &api.PathReq{Path: "received:123"}, true},
+		{"/api.Storage/ReadFile", &api.PathReq{Path: "/home/alice/a.txt"}, false},
+		{"/api.Storage/ReadFile", &api.PathReq{Path: "/shared-with-me/../home/alice/a.txt"}, false},
+		{"/api.Storage/Move", &api.MoveReq{OldPath: "/shared-with-me/project/a.txt", NewPath: "/home/bob/a.txt"}, false},
+		{"/api.Storage/SetACL", &api.ACLReq{Path: "/shared-with-me/project"}, false},
+		{"/api.Share/ListReceivedShares", &api.EmptyReq{}, true},
+		{"/api.Share/CreatePublicLink", &api.NewLinkReq{Path: "/shared-with-me/project"}, false},
+		{"/api.Auth/RefreshUserToken", &api.RefreshTokenReq{}, true},
+	}
+	for _, test := range tests {
+		err := checkGuest(ctx, test.method, test.req)
+		if (err == nil) != test.expected {
+			t.Errorf("checkGuest(%s, %+v) = %v, expected allowed %t", test.method, test.req, err, test.expected)
+		}
+	}
+
+	if err := checkGuest(scopedContext(nil), "/api.Share/CreatePublicLink", &api.NewLinkReq{Path: "/home/alice"}); err != nil {
+		t.Errorf("user denied as a guest: %v", err)
+	}
+}
+
+func TestCheckImpersonation(t *testing.T) {
+	ctx := api.ContextSetUser(context.Background(), &api.User{AccountId: "alice", Impersonator: "admin"})
+	tests := []struct {
+		method   string
+		req      interface{}
+		expected bool
+	}{
+		{"/api.Storage/ReadFile", &api.PathReq{Path: "/home/alice/a.txt"}, true},
+		{"/api.Storage/Move", &api.MoveReq{OldPath: "/home/alice/a.txt", NewPath: "/home/alice/b.txt"}, true},
+		{"/api.Storage/Move", &api.MoveReq{OldPath: "/shared-with-me/project", NewPath: "/shared-with-me/renamed"}, false},
+		{"/api.Storage/Move", &api.MoveReq{OldPath: "received:123", NewPath: "/shared-with-me/renamed"}, false},
+		{"/api.Storage/SetACL", &api.ACLReq{Path: "/home/alice/project"}, false},
+		{"/api.Share/CreatePublicLink", &api.NewLinkReq{Path: "/home/alice/project"}, false},
+		{"/api.Share/UnmountReceivedShare", &api.ReceivedShareReq{}, false},
+	}
+	for _, test := range tests {
+		err := checkImpersonation(ctx, test.method, test.req)
+		if (err == nil) != test.expected {
+			t.Errorf("checkImpersonation(%s, %+v) = %v, expected allowed %t", test.method, test.req, err, test.expected)
+		}
+	}
+
+	move := &api.MoveReq{OldPath: "/shared-with-me/project", NewPath: "/shared-with-me/renamed"}
+	if err := checkImpersonation(scopedContext(nil), "/api.Storage/Move", move); err != nil {
+		t.Errorf("user denied as impersonated: %v", err)
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	tests := []struct {
+		user     *api.User
+		method   string
+		req      interface{}
+		expected bool
+	}{
+		{&api.User{AccountId: "alice"}, "/api.Share/CreatePublicLink", &api.NewLinkReq{Path: "/home/alice/project"}, true},
+		{&api.User{AccountId: "alice", Impersonator: "admin"}, "/api.Share/CreatePublicLink", &api.NewLinkReq{Path: "/home/alice/project"}, false},
+		{&api.User{AccountId: "bob@example.org", Guest: true}, "/api.Storage/ReadFile", &api.PathReq{Path: "/shared-with-me/a.txt"}, true},
+		{&api.User{AccountId: "bob@example.org", Guest: true, Scope: &api.TokenScope{Path: "/shared-with-me/b.txt"}}, "/api.Storage/ReadFile", &api.PathReq{Path: "/shared-with-me/a.txt"}, false},
+		{&api.User{AccountId: "alice", Scope: &api.TokenScope{ReadOnly: true}}, "/api.Storage/Delete", &api.PathReq{Path: "/home/alice/a.txt"}, false},
+		{&api.User{AccountId: "alice", Scope: &api.TokenScope{ReadOnly: true}}, "/api.Storage/ReadFile", &api.PathReq{Path: "/home/alice/a.txt"}, true},
+	}
+	for _, test := range tests {
+		err := checkAccess(api.ContextSetUser(context.Background(), test.user), test.method, test.req)
+		if (err == nil) != test.expected {
+			t.Errorf("checkAccess(%+v, %s, %+v) = %v, expected allowed %t", test.user, test.method, test.req, err, test.expected)
+		}
+	}
+}
+
+func TestSetForwardedMetadata(t *testing.T) {
+	proxies, err := api.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustedProxies = proxies
+	defer func() { trustedProxies = nil }()
+
+	md := metadata.Pairs("x-client-ip", "192.0.2.1", "x-uploader-name", "Marie%20Curie")
+	tests := []struct {
+		peer         string
+		ip, uploader string
+	}{
+		{"10.1.2.3", "192.0.2.1", "Marie Curie"},
+		{"198.51.100.7", "198.51.100.7", ""},
+	}
+	for _, test := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(test.peer), Port: 4321}})
+		newCtx := setForwardedMetadata(ctx, ctx)
+		if ip, _ := api.ContextGetClientIP(newCtx); ip != test.ip {
+			t.Errorf("peer %s: expected client IP %q, got %q", test.peer, test.ip, ip)
+		}
+		if name, _ := api.ContextGetUploaderName(newCtx); name != test.uploader {
+			t.Errorf("peer %s: expected uploader name %q, got %q", test.peer, test.uploader, name)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package adminsvc
+
+import (
+	"github.com/cernbox/revaold/api"
+	"golang.org/x/net/context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// New returns the admin service. The members of the adminGroup can use the
+// admin calls and the members of the impersonationGroup can impersonate other
+// users, an empty group means nobody.
+// New returns the admin service, the guest manager is nil if the guests are disabled.
+func New(rec api.ShareReconciler, ot api.OwnershipTransferer, um api.UserManager, ud api.UserDirectory, gm api.GuestManager, tm api.TokenManager, adminGroup, impersonationGroup string) api.AdminServer {
+	return &svc{reconciler: rec, transferer: ot, userManager: um, userDirectory: ud, guestManager: gm, tokenManager: tm, adminGroup: adminGroup, impersonationGroup: impersonationGroup}
+}
+
+type svc struct {
+	reconciler         api.ShareReconciler
+	transferer         api.OwnershipTransferer
+	userManager        api.UserManager
+	userDirectory      api.UserDirectory
+	guestManager       api.GuestManager
+	tokenManager       api.TokenManager
+	adminGroup         string
+	impersonationGroup string
+}
+
+func (s *svc) ReconcileShares(req *api.ReconcileSharesReq, stream api.Admin_ReconcileSharesServer) error {
+	ctx := stream.Context()
+	l := ctx_zap.Extract(ctx)
+
+	if err := s.checkAdmin(ctx); err != nil {
+		if api.IsErrorCode(err, api.PermissionDeniedErrorCode) {
+			return stream.Send(&api.ACLDriftResponse{Status: api.StatusCode_PERMISSION_DENIED})
+		}
+		l.Error("error checking admin membership", zap.Error(err))
+		return err
+	}
+
+	drifts, err := s.reconciler.Reconcile(ctx, !req.DryRun)
+	if err != nil {
+		l.Error("error reconciling shares", zap.Error(err))
+		return err
+	}
+
+	for _, drift := range drifts {
+		if err := stream.Send(&api.ACLDriftResponse{Drift: drift}); err != nil {
+			l.Error("error streaming acl drift", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *svc) TransferOwnership(req *api.TransferOwnershipReq, stream api.Admin_TransferOwnershipServer) error {
+	ctx := stream.Context()
+	l := ctx_zap.Extract(ctx)
+
+	if err := s.checkAdmin(ctx); err != nil {
+		if api.IsErrorCode(err, api.PermissionDeniedErrorCode) {
+			return stream.Send(&api.TransferredItemResponse{Status: api.StatusCode_PERMISSION_DENIED})
+		}
+		l.Error("error checking admin membership", zap.Error(err))
+		return err
+	}
+
+	l.Info("audit: ownership transfer requested", zap.String("from", req.From), zap.String("to", req.To), zap.String("path", req.Path))
+	items, err := s.transferer.TransferOwnership(ctx, req.From, req.To, req.Path)
+	if err != nil {
+		if status := api.GetStatus(err); status != api.StatusCode_UNKNOWN {
+			return stream.Send(&api.TransferredItemResponse{Status: status})
+		}
+		l.Error("error transferring ownership", zap.Error(err))
+		return err
+	}
+
+	for _, item := range items {
+		if err := stream.Send(&api.TransferredItemResponse{Item: item}); err != nil {
+			l.Error("error streaming transferred item", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *svc) RevokeUserTokens(ctx context.Context, req *api.RevokeUserTokensReq) (*api.EmptyResponse, error) {
+	l := ctx_zap.Extract(ctx)
+
+	if err := s.checkAdmin(ctx); err != nil {
+		if api.IsErrorCode(err, api.PermissionDeniedErrorCode) {
+			return &api.EmptyResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+		}
+		l.Error("error checking admin membership", zap.Error(err))
+		return nil, err
+	}
+
+	u, _ := api.ContextGetUser(ctx)
+	l.Info("audit: revocation of all tokens of user requested", zap.String("account_id", req.AccountId), zap.String("admin", u.AccountId))
+	if err := s.tokenManager.RevokeUserTokens(ctx, req.AccountId); err != nil {
+		if status := api.GetStatus(err); status != api.StatusCode_UNKNOWN {
+			return &api.EmptyResponse{Status: status}, nil
+		}
+		l.Error("error revoking user tokens", zap.Error(err))
+		return nil, err
+	}
+	return &api.EmptyResponse{}, nil
+}
+
+// ImpersonateUser lets the support staff see what the user sees. The token carries
+// the account id of the admin, so the calls made with it are audited with both identities.
+func (s *svc) ImpersonateUser(ctx context.Context, req *api.ImpersonateUserReq) (*api.TokenResponse, error) {
+	l := ctx_zap.Extract(ctx)
+
+	if err := s.checkGroup(ctx, s.impersonationGroup); err != nil {
+		if api.IsErrorCode(err, api.PermissionDeniedErrorCode) {
+			return &api.TokenResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+		}
+		l.Error("error checking impersonation group membership", zap.Error(err))
+		return nil, err
+	}
+
+	admin, _ := api.ContextGetUser(ctx)
+	if req.AccountId == "" || req.AccountId == admin.AccountId {
+		return &api.TokenResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+	}
+
+	user, err := s.getUser(ctx, req.AccountId)
+	if err != nil {
+		if api.IsErrorCode(err, api.UserNotFoundErrorCode) {
+			return &api.TokenResponse{Status: api.StatusCode_USER_NOT_FOUND}, nil
+		}
+		l.Error("error getting user to impersonate", zap.Error(err), zap.String("account_id", req.AccountId))
+		return nil, err
+	}
+
+	user.Impersonator = admin.AccountId
+	token, err := s.tokenManager.ForgeImpersonationToken(ctx, user)
+	if err != nil {
+		l.Error("error forging impersonation token", zap.Error(err))
+		return nil, err
+	}
+	l.Info("audit: user impersonated", zap.String("account_id", req.AccountId), zap.String("impersonator", admin.AccountId))
+	return &api.TokenResponse{Token: token}, nil
+}
+
+func (s *svc) checkAdmin(ctx context.Context) error {
+	return s.checkGroup(ctx, s.adminGroup)
+}
+
+// getUser returns the user as it logs in, a guest restricted to what was shared with
+// it or a user of the directory with its groups, or a UserNotFoundErrorCode error.
+func (s *svc) getUser(ctx context.Context, accountID string) (*api.User, error) {
+	if s.guestManager != nil {
+		guest, err := s.guestManager.GetGuest(ctx, accountID)
+		if err == nil {
+			return guest, nil
+		}
+		if !api.IsErrorCode(err, api.UserNotFoundErrorCode) {
+			return nil, err
+		}
+	}
+
+	du, err := s.userDirectory.GetUser(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.userManager.GetUserGroups(ctx, du.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	return &api.User{AccountId: du.AccountId, DisplayName: du.DisplayName, Groups: groups}, nil
+}
+
+// checkGroup returns a PermissionDeniedErrorCode error if the user of the context
+// did not log in with its own full token or is not member of the group.
+func (s *svc) checkGroup(ctx context.Context, group string) error {
+	if _, ok := api.ContextGetPublicLink(ctx); ok {
+		return api.NewError(api.PermissionDeniedErrorCode).WithMessage("public link tokens cannot be used")
+	}
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return api.NewError(api.ContextUserRequiredError)
+	}
+
+	// an impersonation would otherwise get the permissions of the impersonated admin
+	if u.Impersonator != "" || u.Scope != nil {
+		return api.NewError(api.PermissionDeniedErrorCode).WithMessage("impersonation and scoped tokens cannot be used")
+	}
+
+	if group == "" {
+		return api.NewError(api.PermissionDeniedErrorCode).WithMessage("group not configured")
+	}
+
+	ok, err := s.userManager.IsInGroup(ctx, u.AccountId, group)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return api.NewError(api.PermissionDeniedErrorCode).WithMessage(u.AccountId + " is not member of " + group)
+	}
+	return nil
+}
@@ -1,20 +1,91 @@
 package authsvc
 
 import (
+	"net"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/cernbox/revaold/api"
 	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
 )
 
-func New(am api.AuthManager, tm api.TokenManager, lm api.PublicLinkManager) api.AuthServer {
-	return &svc{am: am, tm: tm, lm: lm}
+var (
+	linkAuthFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "revad_public_link_auth_failures_total",
+		Help: "Number of failed password attempts on public links.",
+	})
+	linkAuthLocked = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "revad_public_link_auth_locked_total",
+		Help: "Number of attempts on public links rejected because of too many previous failures from the client IP.",
+	})
+	linkAuthDelayed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "revad_public_link_auth_delayed_total",
+		Help: "Number of attempts on public links delayed because of too many previous failures on the link.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(linkAuthFailures, linkAuthLocked, linkAuthDelayed)
+}
+
+// ThrottleOptions configure the throttling of the password attempts on public links.
+// After FreeAttempts consecutive failures from the same client IP, new attempts are
+// rejected during a back-off that starts at BackOff seconds and doubles with every
+// failure. After MaxAttempts failures the IP is locked during Lockout seconds.
+//
+// The failures on a link only delay the answers to the attempts on it, by the same
+// back-off up to MaxLinkDelay seconds, so the attempts of a client cannot lock
+// the link for everyone else. At most MaxConcurrentAttempts attempts on a link are
+// checked at the same time, the others are rejected, so the delay cannot be
+// worked around by sending the guesses in parallel.
+//
+// The client IP of the requests is only honoured from the TrustedProxies, like
+// ocproxy, the IP of the other callers is the one of their connection.
+type ThrottleOptions struct {
+	FreeAttempts          int
+	MaxAttempts           int
+	BackOff               int
+	Lockout               int
+	MaxLinkDelay          int
+	MaxConcurrentAttempts int
+	TrustedProxies        []*net.IPNet
+}
+
+// New returns the auth service. If the attempt store is nil the password
+// attempts on public links are not throttled, if the guest manager is nil
+// the guests cannot be activated and if the app password manager is nil the
+// app passwords cannot be managed. The authFunc is used to authenticate the
+// calls managing the app passwords and forging scoped tokens, the rest of the
+// calls are not authenticated.
+func New(am api.AuthManager, gm api.GuestManager, apm api.AppPasswordManager, tm api.TokenManager, lm api.PublicLinkManager, plv api.PublicLinkValidator, as api.AuthAttemptStore, opt *ThrottleOptions, authFunc func(context.Context) (context.Context, error)) api.AuthServer {
+	if opt == nil {
+		opt = &ThrottleOptions{}
+	}
+	if opt.MaxConcurrentAttempts <= 0 {
+		opt.MaxConcurrentAttempts = 1
+	}
+	return &svc{am: am, gm: gm, apm: apm, tm: tm, lm: lm, plv: plv, as: as, opt: opt, authFunc: authFunc, inFlight: map[string]int{}}
 }
 
 type svc struct {
-	am api.AuthManager
-	tm api.TokenManager
-	lm api.PublicLinkManager
+	am       api.AuthManager
+	gm       api.GuestManager
+	apm      api.AppPasswordManager
+	tm       api.TokenManager
+	lm       api.PublicLinkManager
+	plv      api.PublicLinkValidator
+	as       api.AuthAttemptStore
+	opt      *ThrottleOptions
+	authFunc func(context.Context) (context.Context, error)
+
+	// inFlight counts the attempts being checked on each link
+	inFlightMu sync.Mutex
+	inFlight   map[string]int
 }
 
 func (s *svc) ForgeUserToken(ctx context.Context, req *api.ForgeUserTokenReq) (*api.TokenResponse, error) {
@@ -31,9 +102,63 @@ func (s *svc) ForgeUserToken(ctx context.Context, req *api.ForgeUserTokenReq) (*
 		return nil, err
 	}
 	tokenResponse := &api.TokenResponse{Token: token}
+
+	if req.Refresh {
+		refreshToken, err := s.tm.ForgeRefreshToken(ctx, user)
+		if err != nil {
+			// without a revocation store there are no refresh tokens,
+			// the clients log in again when the token expires
+			if api.IsErrorCode(err, api.StorageNotSupportedErrorCode) {
+				return tokenResponse, nil
+			}
+			l.Error("", zap.Error(err))
+			return nil, err
+		}
+		tokenResponse.RefreshToken = refreshToken
+	}
 	return tokenResponse, nil
 }
 
+// RefreshUserToken does not authenticate the user again, the new tokens
+// carry the same groups as the refresh token.
+func (s *svc) RefreshUserToken(ctx context.Context, req *api.RefreshTokenReq) (*api.TokenResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	// the refresh tokens are used once, so a stolen one stops working when the client refreshes
+	user, err := s.tm.RedeemRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		if api.IsErrorCode(err, api.TokenInvalidErrorCode) {
+			l.Warn("refresh token invalid", zap.Error(err))
+			return &api.TokenResponse{Status: api.StatusCode_TOKEN_INVALID}, nil
+		}
+		l.Error("error redeeming refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	token, err := s.tm.ForgeUserToken(ctx, user)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	refreshToken, err := s.tm.ForgeRefreshToken(ctx, user)
+	if err != nil {
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+	return &api.TokenResponse{Token: token, RefreshToken: refreshToken}, nil
+}
+
+func (s *svc) RevokeToken(ctx context.Context, req *api.TokenReq) (*api.EmptyResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	if err := s.tm.RevokeUserToken(ctx, req.Token); err != nil {
+		if status := api.GetStatus(err); status != api.StatusCode_UNKNOWN {
+			return &api.EmptyResponse{Status: status}, nil
+		}
+		l.Error("error revoking token", zap.Error(err))
+		return nil, err
+	}
+	return &api.EmptyResponse{}, nil
+}
+
 func (s *svc) DismantleUserToken(ctx context.Context, req *api.TokenReq) (*api.UserResponse, error) {
 	l := ctx_zap.Extract(ctx)
 	token := req.Token
@@ -50,15 +175,53 @@ func (s *svc) DismantleUserToken(ctx context.Context, req *api.TokenReq) (*api.U
 
 func (s *svc) ForgePublicLinkToken(ctx context.Context, req *api.ForgePublicLinkTokenReq) (*api.TokenResponse, error) {
 	l := ctx_zap.Extract(ctx)
+
+	// the empty password is used to check if a link is protected, so it is not
+	// considered a guess and it is not throttled.
+	throttle := s.as != nil && req.Password != ""
+	// the client IP is only honoured from the trusted proxies
+	req = &api.ForgePublicLinkTokenReq{Token: req.Token, Password: req.Password, ClientIp: api.GetClientIP(ctx, s.opt.TrustedProxies, req.ClientIp)}
+	keys := getAttemptKeys(req)
+
+	if throttle {
+		if !s.startAttempt(req.Token) {
+			linkAuthLocked.Inc()
+			l.Warn("audit: public link authentication rejected, too many concurrent attempts", zap.String("token", req.Token), zap.String("client_ip", req.ClientIp))
+			return &api.TokenResponse{Status: api.StatusCode_PUBLIC_LINK_LOCKED}, nil
+		}
+		defer s.endAttempt(req.Token)
+
+		if err := s.checkAttempts(ctx, req); err != nil {
+			if api.IsErrorCode(err, api.PublicLinkLockedErrorCode) {
+				linkAuthLocked.Inc()
+				l.Warn("audit: public link authentication rejected, too many failed attempts", zap.String("token", req.Token), zap.String("client_ip", req.ClientIp))
+				return &api.TokenResponse{Status: api.StatusCode_PUBLIC_LINK_LOCKED}, nil
+			}
+			l.Error("error checking failed attempts", zap.Error(err))
+			return nil, err
+		}
+	}
+
 	pl, err := s.lm.AuthenticatePublicLink(ctx, req.Token, req.Password)
 	if err != nil {
 		if api.IsErrorCode(err, api.PublicLinkInvalidPasswordErrorCode) {
+			if throttle {
+				s.addFailedAttempt(ctx, keys, req)
+			}
 			return &api.TokenResponse{Status: api.StatusCode_PUBLIC_LINK_INVALID_PASSWORD}, nil
 		}
 		l.Error("", zap.Error(err))
 		return nil, err
 	}
 
+	if throttle {
+		for _, key := range keys {
+			if err := s.as.ResetAuthAttempts(ctx, key); err != nil {
+				l.Error("error resetting failed attempts", zap.Error(err), zap.String("key", key))
+			}
+		}
+	}
+
 	token, err := s.tm.ForgePublicLinkToken(ctx, pl)
 	if err != nil {
 		l.Warn("", zap.Error(err))
@@ -76,12 +239,286 @@ func (s *svc) DismantlePublicLinkToken(ctx context.Context, req *api.TokenReq) (
 		l.Error("token invalid", zap.Error(err))
 		return nil, api.NewError(api.TokenInvalidErrorCode).WithMessage(err.Error())
 	}
+	if err := s.plv.ValidatePublicLink(ctx, u); err != nil {
+		l.Warn("token invalid", zap.Error(err))
+		return nil, err
+	}
 	userRes := &api.PublicLinkResponse{PublicLink: u}
 	return userRes, nil
 }
 
-// Override the Auth function to avoid checking the bearer token for this service
+func (s *svc) ActivateGuest(ctx context.Context, req *api.ActivateGuestReq) (*api.UserResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	if s.gm == nil {
+		return &api.UserResponse{Status: api.StatusCode_STORAGE_NOT_SUPPORTED}, nil
+	}
+
+	email, err := s.gm.ActivateGuest(ctx, req.Token, req.Password)
+	if err != nil {
+		if api.IsErrorCode(err, api.GuestInvalidTokenErrorCode) {
+			l.Warn("audit: guest activation rejected, invalid token", zap.Error(err))
+			return &api.UserResponse{Status: api.StatusCode_GUEST_INVALID_TOKEN}, nil
+		}
+		l.Error("", zap.Error(err))
+		return nil, err
+	}
+
+	l.Info("audit: guest activated", zap.String("email", email))
+	return &api.UserResponse{User: &api.User{AccountId: email, DisplayName: email, Groups: []string{}, Guest: true}}, nil
+}
+
+func (s *svc) CreateAppPassword(ctx context.Context, req *api.NewAppPasswordReq) (*api.AppPasswordResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	if s.apm == nil {
+		return &api.AppPasswordResponse{Status: api.StatusCode_STORAGE_NOT_SUPPORTED}, nil
+	}
+	if err := checkAppPasswordUser(ctx); err != nil {
+		return &api.AppPasswordResponse{Status: api.GetStatus(err)}, nil
+	}
+
+	ap, password, err := s.apm.CreateAppPassword(ctx, req.Label, req.Scope)
+	if err != nil {
+		if status := api.GetStatus(err); status != api.StatusCode_UNKNOWN {
+			return &api.AppPasswordResponse{Status: status}, nil
+		}
+		l.Error("error creating app password", zap.Error(err))
+		return nil, err
+	}
+	return &api.AppPasswordResponse{AppPassword: ap, Password: password}, nil
+}
+
+func (s *svc) ListAppPasswords(req *api.EmptyReq, stream api.Auth_ListAppPasswordsServer) error {
+	ctx := stream.Context()
+	l := ctx_zap.Extract(ctx)
+	if s.apm == nil {
+		return stream.Send(&api.AppPasswordResponse{Status: api.StatusCode_STORAGE_NOT_SUPPORTED})
+	}
+	if err := checkAppPasswordUser(ctx); err != nil {
+		return stream.Send(&api.AppPasswordResponse{Status: api.GetStatus(err)})
+	}
+
+	aps, err := s.apm.ListAppPasswords(ctx)
+	if err != nil {
+		l.Error("error listing app passwords", zap.Error(err))
+		return err
+	}
+	for _, ap := range aps {
+		if err := stream.Send(&api.AppPasswordResponse{AppPassword: ap}); err != nil {
+			l.Error("", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *svc) RevokeAppPassword(ctx context.Context, req *api.AppPasswordReq) (*api.EmptyResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	if s.apm == nil {
+		return &api.EmptyResponse{Status: api.StatusCode_STORAGE_NOT_SUPPORTED}, nil
+	}
+	if err := checkAppPasswordUser(ctx); err != nil {
+		return &api.EmptyResponse{Status: api.GetStatus(err)}, nil
+	}
+
+	if err := s.apm.RevokeAppPassword(ctx, req.Id); err != nil {
+		if status := api.GetStatus(err); status != api.StatusCode_UNKNOWN {
+			return &api.EmptyResponse{Status: status}, nil
+		}
+		l.Error("error revoking app password", zap.Error(err))
+		return nil, err
+	}
+	return &api.EmptyResponse{}, nil
+}
+
+// ForgeScopedToken forges a token of the user of the context for the apps, which
+// get access to a single file or folder instead of the full token of the user.
+func (s *svc) ForgeScopedToken(ctx context.Context, req *api.ScopedTokenReq) (*api.TokenResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	u, err := getLoggedInUser(ctx)
+	if err != nil {
+		return &api.TokenResponse{Status: api.GetStatus(err)}, nil
+	}
+	// a scoped token cannot be widened into another one, and the impersonations
+	// cannot outlive their token with a longer lived one
+	if u.Scope != nil || u.Impersonator != "" {
+		return &api.TokenResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+	}
+
+	scope := req.Scope
+	if scope != nil && scope.Path != "" {
+		if !strings.HasPrefix(scope.Path, "/") {
+			return &api.TokenResponse{Status: api.StatusCode_PATH_INVALID}, nil
+		}
+		scope = &api.TokenScope{ReadOnly: scope.ReadOnly, Path: path.Clean(scope.Path), Expiration: scope.Expiration, ResourceId: scope.ResourceId}
+	}
+
+	token, err := s.tm.ForgeScopedToken(ctx, u, scope, int(req.Lifetime))
+	if err != nil {
+		if status := api.GetStatus(err); status != api.StatusCode_UNKNOWN {
+			return &api.TokenResponse{Status: status}, nil
+		}
+		l.Error("error forging scoped token", zap.Error(err))
+		return nil, err
+	}
+	l.Info("scoped token forged", zap.String("account_id", u.AccountId), zap.String("path", scope.Path), zap.String("resource_id", scope.ResourceId), zap.Bool("read_only", scope.ReadOnly))
+	return &api.TokenResponse{Token: token}, nil
+}
+
+// checkAppPasswordUser returns an error if the user of the context cannot manage app passwords:
+// the guests, the users logged in with a scoped token, as a leaked app password
+// could otherwise create an unrestricted one, and the impersonations, that cannot outlive their token.
+func checkAppPasswordUser(ctx context.Context) error {
+	u, err := getLoggedInUser(ctx)
+	if err != nil {
+		return err
+	}
+	if u.Guest || u.Scope != nil || u.Impersonator != "" {
+		return api.NewError(api.PermissionDeniedErrorCode)
+	}
+	return nil
+}
+
+// getLoggedInUser returns the user of the context if it logged in with a user token,
+// the user of the public link tokens is the owner of the link.
+func getLoggedInUser(ctx context.Context) (*api.User, error) {
+	if _, ok := api.ContextGetPublicLink(ctx); ok {
+		return nil, api.NewError(api.PermissionDeniedErrorCode)
+	}
+	u, ok := api.ContextGetUser(ctx)
+	if !ok {
+		return nil, api.NewError(api.ContextUserRequiredError)
+	}
+	return u, nil
+}
+
+// getAttemptKeys returns the keys used to track the failed attempts of the request,
+// one for the link and one for the client IP if known.
+func getAttemptKeys(req *api.ForgePublicLinkTokenReq) []string {
+	keys := []string{getLinkAttemptKey(req)}
+	if req.ClientIp != "" {
+		keys = append(keys, getIPAttemptKey(req))
+	}
+	return keys
+}
+
+func getLinkAttemptKey(req *api.ForgePublicLinkTokenReq) string {
+	return "link:" + req.Token
+}
+
+func getIPAttemptKey(req *api.ForgePublicLinkTokenReq) string {
+	return "ip:" + req.ClientIp
+}
+
+// checkAttempts returns a PublicLinkLockedErrorCode error if the client IP
+// is in back-off or locked, and waits for the delay of the link.
+func (s *svc) checkAttempts(ctx context.Context, req *api.ForgePublicLinkTokenReq) error {
+	if req.ClientIp != "" {
+		attempts, err := s.as.GetAuthAttempts(ctx, getIPAttemptKey(req))
+		if err != nil {
+			return err
+		}
+		if wait := s.getWait(attempts.Failed); wait > 0 && time.Since(attempts.Last) < wait {
+			return api.NewError(api.PublicLinkLockedErrorCode).WithMessage(req.ClientIp)
+		}
+	}
+
+	attempts, err := s.as.GetAuthAttempts(ctx, getLinkAttemptKey(req))
+	if err != nil {
+		return err
+	}
+	delay := s.getLinkDelay(attempts.Failed)
+	if delay <= 0 {
+		return nil
+	}
+	linkAuthDelayed.Inc()
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startAttempt returns false if the link already has the maximum number of
+// attempts being checked, otherwise the attempt is counted until endAttempt.
+func (s *svc) startAttempt(token string) bool {
+	s.inFlightMu.Lock()
+	defer s.inFlightMu.Unlock()
+	if s.inFlight[token] >= s.opt.MaxConcurrentAttempts {
+		return false
+	}
+	s.inFlight[token]++
+	return true
+}
+
+func (s *svc) endAttempt(token string) {
+	s.inFlightMu.Lock()
+	defer s.inFlightMu.Unlock()
+	if s.inFlight[token]--; s.inFlight[token] <= 0 {
+		delete(s.inFlight, token)
+	}
+}
+
+// getLinkDelay returns the time the attempts on a link are delayed
+// after the given number of failures on it.
+func (s *svc) getLinkDelay(failed int) time.Duration {
+	delay := s.getWait(failed)
+	if max := time.Duration(s.opt.MaxLinkDelay) * time.Second; delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// getWait returns the time new attempts are rejected after the given number of failures.
+func (s *svc) getWait(failed int) time.Duration {
+	lockout := time.Duration(s.opt.Lockout) * time.Second
+	if failed < s.opt.FreeAttempts {
+		return 0
+	}
+	if failed >= s.opt.MaxAttempts {
+		return lockout
+	}
+
+	wait := time.Duration(s.opt.BackOff) * time.Second
+	for i := s.opt.FreeAttempts; i < failed && wait < lockout; i++ {
+		wait *= 2
+	}
+	if wait > lockout {
+		wait = lockout
+	}
+	return wait
+}
+
+func (s *svc) addFailedAttempt(ctx context.Context, keys []string, req *api.ForgePublicLinkTokenReq) {
+	l := ctx_zap.Extract(ctx)
+	linkAuthFailures.Inc()
+
+	failed := 0
+	for _, key := range keys {
+		attempts, err := s.as.AddFailedAuthAttempt(ctx, key)
+		if err != nil {
+			l.Error("error storing failed attempt", zap.Error(err), zap.String("key", key))
+			continue
+		}
+		if attempts.Failed > failed {
+			failed = attempts.Failed
+		}
+	}
+
+	l.Warn("audit: public link authentication failed", zap.String("token", req.Token), zap.String("client_ip", req.ClientIp), zap.Int("failed_attempts", failed))
+	if failed == s.opt.MaxAttempts {
+		l.Warn("audit: public link client locked after too many failed attempts", zap.String("token", req.Token), zap.String("client_ip", req.ClientIp), zap.Int("lockout", s.opt.Lockout))
+	}
+}
+
+// Override the Auth function to avoid checking the bearer token for this service,
+// except for the calls managing the app passwords and the scoped tokens of the user
 // https://github.com/grpc-ecosystem/go-grpc-middleware/tree/master/auth#type-serviceauthfuncoverride
 func (s *svc) AuthFuncOverride(ctx context.Context, fullMethodName string) (context.Context, error) {
-	return ctx, nil
+	switch fullMethodName {
+	case "/api.Auth/CreateAppPassword", "/api.Auth/ListAppPasswords", "/api.Auth/RevokeAppPassword", "/api.Auth/ForgeScopedToken":
+		return s.authFunc(ctx)
+	default:
+		return ctx, nil
+	}
 }
@@ -0,0 +1,261 @@
+package authsvc
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/cernbox/revaold/api/auth_attempt_store_memory"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/peer"
+)
+
+type fakeLinkManager struct {
+	api.PublicLinkManager
+}
+
+func (lm *fakeLinkManager) AuthenticatePublicLink(ctx context.Context, token, password string) (*api.PublicLink, error) {
+	if password != "secret" {
+		return nil, api.NewError(api.PublicLinkInvalidPasswordErrorCode)
+	}
+	return &api.PublicLink{Token: token}, nil
+}
+
+type fakeTokenManager struct {
+	api.TokenManager
+}
+
+func (tm *fakeTokenManager) ForgePublicLinkToken(ctx context.Context, pl *api.PublicLink) (string, error) {
+	return "token-" + pl.Token, nil
+}
+
+func (tm *fakeTokenManager) ForgeUserToken(ctx context.Context, user *api.User) (string, error) {
+	return "token-" + user.AccountId, nil
+}
+
+// ForgeRefreshToken fails like the token manager without a revocation store.
+func (tm *fakeTokenManager) ForgeRefreshToken(ctx context.Context, user *api.User) (string, error) {
+	return "", api.NewError(api.StorageNotSupportedErrorCode)
+}
+
+type fakeAuthManager struct {
+	api.AuthManager
+}
+
+func (am *fakeAuthManager) Authenticate(ctx context.Context, clientID, clientSecret string) (*api.User, error) {
+	return &api.User{AccountId: clientID}, nil
+}
+
+// proxy is the trusted proxy the attempts come from.
+var proxy = &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}}
+
+func trustedProxies(t *testing.T) []*net.IPNet {
+	proxies, err := api.ParseTrustedProxies([]string{"127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proxies
+}
+
+// fakeGuestManager activates the guest invited with the token once.
+type fakeGuestManager struct {
+	api.GuestManager
+	tokens map[string]string
+}
+
+func (gm *fakeGuestManager) ActivateGuest(ctx context.Context, token, password string) (string, error) {
+	email, ok := gm.tokens[token]
+	if !ok {
+		return "", api.NewError(api.GuestInvalidTokenErrorCode)
+	}
+	delete(gm.tokens, token)
+	return email, nil
+}
+
+type fakeAppPasswordManager struct {
+	api.AppPasswordManager
+}
+
+func (apm *fakeAppPasswordManager) CreateAppPassword(ctx context.Context, label string, scope *api.TokenScope) (*api.AppPassword, string, error) {
+	return &api.AppPassword{Id: "1", Label: label, Scope: scope}, "password", nil
+}
+
+func forge(t *testing.T, s api.AuthServer, ip, password string) api.StatusCode {
+	ctx := peer.NewContext(ctx_zap.ToContext(context.Background(), zap.NewNop()), proxy)
+	res, err := s.ForgePublicLinkToken(ctx, &api.ForgePublicLinkTokenReq{Token: "abcdefghij", Password: password, ClientIp: ip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Status
+}
+
+func TestFailedAttemptsLockTheClientNotTheLink(t *testing.T) {
+	as := auth_attempt_store_memory.New(100, 3600)
+	opt := &ThrottleOptions{FreeAttempts: 3, MaxAttempts: 3, BackOff: 60, Lockout: 3600, TrustedProxies: trustedProxies(t)}
+	s := New(nil, nil, nil, &fakeTokenManager{}, &fakeLinkManager{}, nil, as, opt, nil)
+
+	for i := 0; i < 3; i++ {
+		if status := forge(t, s, "192.0.2.1", "guess"); status != api.StatusCode_PUBLIC_LINK_INVALID_PASSWORD {
+			t.Fatalf("attempt %d: expected invalid password, got %s", i, status)
+		}
+	}
+	if status := forge(t, s, "192.0.2.1", "secret"); status != api.StatusCode_PUBLIC_LINK_LOCKED {
+		t.Errorf("expected the client to be locked, got %s", status)
+	}
+	// the other clients still get in, without delay as MaxLinkDelay is 0
+	if status := forge(t, s, "192.0.2.2", "secret"); status != api.StatusCode_OK {
+		t.Errorf("expected the link to be accessible from other clients, got %s", status)
+	}
+}
+
+func TestClientIPOfUntrustedPeersIsIgnored(t *testing.T) {
+	as := auth_attempt_store_memory.New(100, 3600)
+	opt := &ThrottleOptions{FreeAttempts: 3, MaxAttempts: 3, BackOff: 60, Lockout: 3600}
+	s := New(nil, nil, nil, &fakeTokenManager{}, &fakeLinkManager{}, nil, as, opt, nil)
+
+	// changing the claimed client IP does not escape the lock of the peer
+	for i := 0; i < 3; i++ {
+		if status := forge(t, s, fmt.Sprintf("192.0.2.%d", i), "guess"); status != api.StatusCode_PUBLIC_LINK_INVALID_PASSWORD {
+			t.Fatalf("attempt %d: expected invalid password, got %s", i, status)
+		}
+	}
+	if status := forge(t, s, "192.0.2.10", "secret"); status != api.StatusCode_PUBLIC_LINK_LOCKED {
+		t.Errorf("expected the peer to be locked, got %s", status)
+	}
+}
+
+func TestConcurrentAttemptsOnALinkAreRefused(t *testing.T) {
+	as := auth_attempt_store_memory.New(100, 3600)
+	opt := &ThrottleOptions{FreeAttempts: 3, MaxAttempts: 10, BackOff: 60, Lockout: 3600, MaxConcurrentAttempts: 1, TrustedProxies: trustedProxies(t)}
+	s := New(nil, nil, nil, &fakeTokenManager{}, &fakeLinkManager{}, nil, as, opt, nil).(*svc)
+
+	// an attempt on the link is still being checked
+	if !s.startAttempt("abcdefghij") {
+		t.Fatal("expected the first attempt to start")
+	}
+	if status := forge(t, s, "192.0.2.1", "secret"); status != api.StatusCode_PUBLIC_LINK_LOCKED {
+		t.Errorf("expected the concurrent attempt to be refused, got %s", status)
+	}
+	s.endAttempt("abcdefghij")
+	if status := forge(t, s, "192.0.2.1", "secret"); status != api.StatusCode_OK {
+		t.Errorf("expected the attempt to be accepted once the other ended, got %s", status)
+	}
+}
+
+func TestGetLinkDelay(t *testing.T) {
+	s := &svc{opt: &ThrottleOptions{FreeAttempts: 2, MaxAttempts: 10, BackOff: 1, Lockout: 900, MaxLinkDelay: 5}}
+	tests := []struct {
+		failed int
+		delay  time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{2, time.Second},
+		{3, 2 * time.Second},
+		{4, 4 * time.Second},
+		{5, 5 * time.Second},
+		{20, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if delay := s.getLinkDelay(tt.failed); delay != tt.delay {
+			t.Errorf("%d failures: expected %s, got %s", tt.failed, tt.delay, delay)
+		}
+	}
+}
+
+func TestCheckAttemptsStopsWaitingWhenCanceled(t *testing.T) {
+	as := auth_attempt_store_memory.New(100, 3600)
+	s := &svc{as: as, opt: &ThrottleOptions{FreeAttempts: 1, MaxAttempts: 10, BackOff: 60, Lockout: 900, MaxLinkDelay: 60}}
+	req := &api.ForgePublicLinkTokenReq{Token: "abcdefghij"}
+	if _, err := as.AddFailedAuthAttempt(context.Background(), getLinkAttemptKey(req)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.checkAttempts(ctx, req); err == nil {
+		t.Error("expected the delayed attempt to be canceled")
+	}
+}
+
+func TestForgeScopedTokenIsDeniedToImpersonations(t *testing.T) {
+	s := New(nil, nil, nil, &fakeTokenManager{}, nil, nil, nil, nil, nil)
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	ctx = api.ContextSetUser(ctx, &api.User{AccountId: "alice", Impersonator: "admin"})
+	res, err := s.ForgeScopedToken(ctx, &api.ScopedTokenReq{Scope: &api.TokenScope{Path: "/home/alice/notes.md"}, Lifetime: 43200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != api.StatusCode_PERMISSION_DENIED {
+		t.Errorf("expected the impersonation to be denied, got %s", res.Status)
+	}
+}
+
+func TestLoginWithoutRefreshTokens(t *testing.T) {
+	s := New(&fakeAuthManager{}, nil, nil, &fakeTokenManager{}, nil, nil, nil, nil, nil)
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	res, err := s.ForgeUserToken(ctx, &api.ForgeUserTokenReq{ClientId: "alice", ClientSecret: "secret", Refresh: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != api.StatusCode_OK || res.Token != "token-alice" || res.RefreshToken != "" {
+		t.Errorf("expected the access token without refresh token, got %+v", res)
+	}
+}
+
+func TestActivateGuest(t *testing.T) {
+	gm := &fakeGuestManager{tokens: map[string]string{"invitation": "marie@example.org"}}
+	s := New(nil, gm, nil, nil, nil, nil, nil, nil, nil)
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+
+	res, err := s.ActivateGuest(ctx, &api.ActivateGuestReq{Token: "invitation", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != api.StatusCode_OK || res.User.AccountId != "marie@example.org" || !res.User.Guest {
+		t.Errorf("expected the guest to be activated, got %+v", res)
+	}
+
+	// the activation token cannot be used again
+	res, err = s.ActivateGuest(ctx, &api.ActivateGuestReq{Token: "invitation", Password: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != api.StatusCode_GUEST_INVALID_TOKEN {
+		t.Errorf("expected the token to be invalid, got %s", res.Status)
+	}
+
+	s = New(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	if res, err := s.ActivateGuest(ctx, &api.ActivateGuestReq{Token: "invitation"}); err != nil || res.Status != api.StatusCode_STORAGE_NOT_SUPPORTED {
+		t.Errorf("expected the guests to be disabled, got %v %v", res, err)
+	}
+}
+
+func TestScopedUsersCannotCreateAppPasswords(t *testing.T) {
+	s := New(nil, nil, &fakeAppPasswordManager{}, nil, nil, nil, nil, nil, nil)
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	tests := []struct {
+		name   string
+		user   *api.User
+		status api.StatusCode
+	}{
+		{"user", &api.User{AccountId: "alice"}, api.StatusCode_OK},
+		{"app password", &api.User{AccountId: "alice", Scope: &api.TokenScope{Path: "/home/alice/ci"}}, api.StatusCode_PERMISSION_DENIED},
+		{"read only", &api.User{AccountId: "alice", Scope: &api.TokenScope{ReadOnly: true}}, api.StatusCode_PERMISSION_DENIED},
+		{"guest", &api.User{AccountId: "marie@example.org", Guest: true}, api.StatusCode_PERMISSION_DENIED},
+		{"impersonation", &api.User{AccountId: "alice", Impersonator: "admin"}, api.StatusCode_PERMISSION_DENIED},
+	}
+	for _, test := range tests {
+		res, err := s.CreateAppPassword(api.ContextSetUser(ctx, test.user), &api.NewAppPasswordReq{Label: "ci"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != test.status {
+			t.Errorf("%s: expected %s, got %s", test.name, test.status, res.Status)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package directorysvc
+
+import (
+	"sort"
+
+	"github.com/cernbox/revaold/api"
+	"golang.org/x/net/context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+// New returns the directory service to look up the users and groups, the
+// members of the groups come from the user manager like for the shares.
+func New(ud api.UserDirectory, um api.UserManager) api.DirectoryServer {
+	return &svc{userDirectory: ud, userManager: um}
+}
+
+type svc struct {
+	userDirectory api.UserDirectory
+	userManager   api.UserManager
+}
+
+// canBrowse returns true if the user of the context can look up the directory, the same
+// for all the methods. The links, the guests and the tokens restricted to a path would
+// otherwise let anyone, or an app, list the users and their e-mail addresses.
+func canBrowse(ctx context.Context) bool {
+	if _, ok := api.ContextGetPublicLink(ctx); ok {
+		return false
+	}
+	u, ok := api.ContextGetUser(ctx)
+	if !ok || u.Guest {
+		return false
+	}
+	return u.Scope == nil || u.Scope.Path == "" && u.Scope.ResourceId == ""
+}
+
+func (s *svc) GetUser(ctx context.Context, req *api.UserReq) (*api.DirectoryUserResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	if !canBrowse(ctx) {
+		return &api.DirectoryUserResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+	}
+
+	u, err := s.userDirectory.GetUser(ctx, req.AccountId)
+	if err != nil {
+		if status := api.GetStatus(err); status != api.StatusCode_UNKNOWN {
+			return &api.DirectoryUserResponse{Status: status}, nil
+		}
+		l.Error("error getting user from directory", zap.Error(err), zap.String("account_id", req.AccountId))
+		return nil, err
+	}
+	return &api.DirectoryUserResponse{User: u}, nil
+}
+
+func (s *svc) GetUserAvatar(ctx context.Context, req *api.UserReq) (*api.AvatarResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	if !canBrowse(ctx) {
+		return &api.AvatarResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+	}
+
+	avatar, mimeType, err := s.userDirectory.GetUserAvatar(ctx, req.AccountId)
+	if err != nil {
+		if status := api.GetStatus(err); status != api.StatusCode_UNKNOWN {
+			return &api.AvatarResponse{Status: status}, nil
+		}
+		l.Error("error getting user avatar from directory", zap.Error(err), zap.String("account_id", req.AccountId))
+		return nil, err
+	}
+	return &api.AvatarResponse{Avatar: avatar, MimeType: mimeType}, nil
+}
+
+func (s *svc) Search(req *api.SearchReq, stream api.Directory_SearchServer) error {
+	ctx := stream.Context()
+	l := ctx_zap.Extract(ctx)
+
+	if !canBrowse(ctx) {
+		return stream.Send(&api.DirectoryEntryResponse{Status: api.StatusCode_PERMISSION_DENIED})
+	}
+
+	entries, err := s.userDirectory.Search(ctx, req.Prefix)
+	if err != nil {
+		l.Error("error searching directory", zap.Error(err), zap.String("prefix", req.Prefix))
+		return err
+	}
+
+	// the entries are sorted so the pages are stable between calls
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Id != entries[j].Id {
+			return entries[i].Id < entries[j].Id
+		}
+		return entries[i].Type < entries[j].Type
+	})
+	if req.Offset >= uint64(len(entries)) {
+		return nil
+	}
+	entries = entries[req.Offset:]
+	if req.Limit > 0 && req.Limit < uint64(len(entries)) {
+		entries = entries[:req.Limit]
+	}
+
+	for _, e := range entries {
+		if err := stream.Send(&api.DirectoryEntryResponse{Entry: e}); err != nil {
+			l.Error("error streaming directory entry", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *svc) ListGroupMembers(ctx context.Context, req *api.GroupReq) (*api.GroupMembersResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	if !canBrowse(ctx) {
+		return &api.GroupMembersResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+	}
+
+	members, err := s.userManager.GetGroupMembers(ctx, req.Group)
+	if err != nil {
+		if status := api.GetStatus(err); status != api.StatusCode_UNKNOWN {
+			return &api.GroupMembersResponse{Status: status}, nil
+		}
+		l.Error("error listing group members from directory", zap.Error(err), zap.String("group", req.Group))
+		return nil, err
+	}
+	return &api.GroupMembersResponse{Members: members}, nil
+}
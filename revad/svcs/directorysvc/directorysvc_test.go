@@ -0,0 +1,94 @@
+package directorysvc
+
+import (
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+type fakeUserDirectory struct {
+	api.UserDirectory
+}
+
+func (ud *fakeUserDirectory) GetUser(ctx context.Context, accountID string) (*api.DirectoryUser, error) {
+	return &api.DirectoryUser{AccountId: accountID, Mail: accountID + "@example.org"}, nil
+}
+
+func (ud *fakeUserDirectory) GetUserAvatar(ctx context.Context, accountID string) ([]byte, string, error) {
+	return []byte("avatar"), "image/png", nil
+}
+
+func (ud *fakeUserDirectory) Search(ctx context.Context, prefix string) ([]*api.DirectoryEntry, error) {
+	return []*api.DirectoryEntry{{Id: "alice"}, {Id: "bob"}}, nil
+}
+
+type fakeUserManager struct {
+	api.UserManager
+}
+
+func (um *fakeUserManager) GetGroupMembers(ctx context.Context, group string) ([]string, error) {
+	return []string{"alice", "bob"}, nil
+}
+
+type searchStream struct {
+	api.Directory_SearchServer
+	ctx       context.Context
+	responses []*api.DirectoryEntryResponse
+}
+
+func (s *searchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *searchStream) Send(res *api.DirectoryEntryResponse) error {
+	s.responses = append(s.responses, res)
+	return nil
+}
+
+// statuses returns the status of each method of the service called with the context.
+func statuses(t *testing.T, ctx context.Context) []api.StatusCode {
+	s := New(&fakeUserDirectory{}, &fakeUserManager{})
+
+	user, err := s.GetUser(ctx, &api.UserReq{AccountId: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	avatar, err := s.GetUserAvatar(ctx, &api.UserReq{AccountId: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := &searchStream{ctx: ctx}
+	if err := s.Search(&api.SearchReq{Prefix: "a"}, stream); err != nil {
+		t.Fatal(err)
+	}
+	members, err := s.ListGroupMembers(ctx, &api.GroupReq{Group: "physics"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []api.StatusCode{user.Status, avatar.Status, stream.responses[0].Status, members.Status}
+}
+
+func TestAccessIsTheSameForAllMethods(t *testing.T) {
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	link := api.ContextSetPublicLink(ctx, &api.PublicLink{Token: "abc"})
+	tests := map[string]struct {
+		ctx      context.Context
+		expected api.StatusCode
+	}{
+		"user":        {api.ContextSetUser(ctx, &api.User{AccountId: "alice"}), api.StatusCode_OK},
+		"read-only":   {api.ContextSetUser(ctx, &api.User{AccountId: "alice", Scope: &api.TokenScope{ReadOnly: true}}), api.StatusCode_OK},
+		"public link": {api.ContextSetUser(link, &api.User{AccountId: "alice"}), api.StatusCode_PERMISSION_DENIED},
+		"guest":       {api.ContextSetUser(ctx, &api.User{AccountId: "bob@example.org", Guest: true}), api.StatusCode_PERMISSION_DENIED},
+		"app":         {api.ContextSetUser(ctx, &api.User{AccountId: "alice", Scope: &api.TokenScope{Path: "/home/alice/a.txt"}}), api.StatusCode_PERMISSION_DENIED},
+	}
+	for name, test := range tests {
+		for i, status := range statuses(t, test.ctx) {
+			if status != test.expected {
+				t.Errorf("%s: expected %s for method %d, got %s", name, test.expected, i, status)
+			}
+		}
+	}
+}
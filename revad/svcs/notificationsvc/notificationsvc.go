@@ -0,0 +1,40 @@
+package notificationsvc
+
+import (
+	"github.com/cernbox/revaold/api"
+	"golang.org/x/net/context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+)
+
+func New(n api.Notifier) api.NotificationServer {
+	return &svc{notifier: n}
+}
+
+type svc struct {
+	notifier api.Notifier
+}
+
+func (s *svc) GetNotificationPreferences(ctx context.Context, req *api.EmptyReq) (*api.NotificationPreferencesResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	prefs, err := s.notifier.GetNotificationPreferences(ctx)
+	if err != nil {
+		l.Error("error getting notification preferences", zap.Error(err))
+		return nil, err
+	}
+	return &api.NotificationPreferencesResponse{Preferences: prefs}, nil
+}
+
+func (s *svc) SetNotificationPreferences(ctx context.Context, req *api.NotificationPreferencesReq) (*api.EmptyResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	prefs := req.Preferences
+	if prefs == nil {
+		prefs = &api.NotificationPreferences{}
+	}
+	if err := s.notifier.SetNotificationPreferences(ctx, prefs); err != nil {
+		l.Error("error setting notification preferences", zap.Error(err))
+		return nil, err
+	}
+	return &api.EmptyResponse{}, nil
+}
@@ -0,0 +1,178 @@
+package ocmsvc
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/cernbox/revaold/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// New returns the OCM service. The authFunc is used to authenticate the calls
+// that act on behalf of the local users, the calls coming from remote servers
+// are relayed by ocproxy, that authenticates with the shared secret. The shares
+// are only accepted from the trusted providers, an empty secret or list rejects
+// every call from remote servers.
+func New(rsm api.RemoteShareManager, oc api.OCMProviderClient, authFunc func(context.Context) (context.Context, error), sharedSecret string, trustedProviders []string) api.OCMServer {
+	return &svc{remoteShareManager: rsm, ocmClient: oc, authFunc: authFunc, sharedSecret: sharedSecret, trustedProviders: trustedProviders}
+}
+
+type svc struct {
+	remoteShareManager api.RemoteShareManager
+	ocmClient          api.OCMProviderClient
+	authFunc           func(context.Context) (context.Context, error)
+	sharedSecret       string
+	trustedProviders   []string
+}
+
+func (s *svc) AuthFuncOverride(ctx context.Context, fullMethodName string) (context.Context, error) {
+	switch fullMethodName {
+	case "/api.OCM/AddRemoteShare", "/api.OCM/RemoveRemoteShare":
+		secret, err := grpc_auth.AuthFromMD(ctx, "ocm-secret")
+		if err != nil {
+			return nil, err
+		}
+		if s.sharedSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(s.sharedSecret)) != 1 {
+			return nil, grpc.Errorf(codes.Unauthenticated, "invalid ocm secret")
+		}
+		return ctx, nil
+	default:
+		return s.authFunc(ctx)
+	}
+}
+
+func (s *svc) isTrustedProvider(host string) bool {
+	for _, trusted := range s.trustedProviders {
+		if strings.EqualFold(trusted, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *svc) AddRemoteShare(ctx context.Context, req *api.NewRemoteShareReq) (*api.RemoteShareResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	if req.Share == nil {
+		return nil, errors.New("remote share is missing")
+	}
+
+	// the owner, and so the remote, is chosen by the sender, the share is only accepted
+	// if the trusted server it claims to come from grants access with its secret.
+	if !s.isTrustedProvider(req.Share.Remote) {
+		l.Warn("remote share from untrusted provider", zap.String("remote", req.Share.Remote))
+		return &api.RemoteShareResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+	}
+	if err := s.ocmClient.VerifyShare(ctx, req.Share.Remote, req.Share.Token); err != nil {
+		l.Warn("remote share not verified by its provider", zap.String("remote", req.Share.Remote), zap.String("remote_id", req.Share.RemoteId), zap.Error(err))
+		return &api.RemoteShareResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+	}
+
+	share, err := s.remoteShareManager.AddRemoteShare(ctx, req.Share)
+	if err != nil {
+		l.Error("error adding remote share", zap.Error(err))
+		return nil, err
+	}
+	res := &api.RemoteShareResponse{Share: share}
+	return res, nil
+}
+
+func (s *svc) RemoveRemoteShare(ctx context.Context, req *api.RemoveRemoteShareReq) (*api.EmptyResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	if err := s.remoteShareManager.RemoveRemoteShare(ctx, req.Remote, req.RemoteId, req.Token); err != nil {
+		if api.IsErrorCode(err, api.RemoteShareNotFoundErrorCode) {
+			return &api.EmptyResponse{Status: api.StatusCode_REMOTE_SHARE_NOT_FOUND}, nil
+		}
+		l.Error("error removing remote share", zap.Error(err))
+		return nil, err
+	}
+	return &api.EmptyResponse{}, nil
+}
+
+func (s *svc) ListRemoteShares(req *api.EmptyReq, stream api.OCM_ListRemoteSharesServer) error {
+	ctx := stream.Context()
+	l := ctx_zap.Extract(ctx)
+	shares, err := s.remoteShareManager.ListRemoteShares(ctx)
+	if err != nil {
+		l.Error("error listing remote shares", zap.Error(err))
+		return err
+	}
+	for _, share := range shares {
+		remoteShareRes := &api.RemoteShareResponse{Share: share}
+		if err := stream.Send(remoteShareRes); err != nil {
+			l.Error("error streaming remote share", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *svc) GetRemoteShare(ctx context.Context, req *api.ShareIDReq) (*api.RemoteShareResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	share, err := s.remoteShareManager.GetRemoteShare(ctx, req.Id)
+	if err != nil {
+		if api.IsErrorCode(err, api.RemoteShareNotFoundErrorCode) {
+			return &api.RemoteShareResponse{Status: api.StatusCode_REMOTE_SHARE_NOT_FOUND}, nil
+		}
+		l.Error("error getting remote share", zap.Error(err))
+		return nil, err
+	}
+	res := &api.RemoteShareResponse{Share: share}
+	return res, nil
+}
+
+func (s *svc) AcceptRemoteShare(ctx context.Context, req *api.ShareIDReq) (*api.EmptyResponse, error) {
+	return s.answerRemoteShare(ctx, req.Id, true)
+}
+
+func (s *svc) RejectRemoteShare(ctx context.Context, req *api.ShareIDReq) (*api.EmptyResponse, error) {
+	return s.answerRemoteShare(ctx, req.Id, false)
+}
+
+// answerRemoteShare accepts or rejects a pending remote share and notifies the owner's server.
+func (s *svc) answerRemoteShare(ctx context.Context, id string, accept bool) (*api.EmptyResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	share, err := s.remoteShareManager.GetRemoteShare(ctx, id)
+	if err != nil {
+		if api.IsErrorCode(err, api.RemoteShareNotFoundErrorCode) {
+			return &api.EmptyResponse{Status: api.StatusCode_REMOTE_SHARE_NOT_FOUND}, nil
+		}
+		l.Error("error getting remote share", zap.Error(err))
+		return nil, err
+	}
+
+	notificationType := api.OCMNotificationShareAccepted
+	if accept {
+		err = s.remoteShareManager.AcceptRemoteShare(ctx, id)
+	} else {
+		notificationType = api.OCMNotificationShareDeclined
+		err = s.remoteShareManager.RejectRemoteShare(ctx, id)
+	}
+	if err != nil {
+		if api.IsErrorCode(err, api.RemoteShareNotFoundErrorCode) {
+			return &api.EmptyResponse{Status: api.StatusCode_REMOTE_SHARE_NOT_FOUND}, nil
+		}
+		l.Error("error answering remote share", zap.Error(err), zap.Bool("accept", accept))
+		return nil, err
+	}
+
+	// the answer is already stored, a remote server that cannot be
+	// reached must not prevent the user from accepting or rejecting the share.
+	notification := &api.OCMNotification{
+		NotificationType: notificationType,
+		ResourceType:     "file",
+		ProviderID:       share.RemoteId,
+		Notification:     &api.OCMNotificationData{SharedSecret: share.Token},
+	}
+	if err := s.ocmClient.SendNotification(ctx, share.Remote, notification); err != nil {
+		l.Warn("error notifying remote server", zap.Error(err), zap.String("remote", share.Remote), zap.String("id", id))
+	}
+
+	return &api.EmptyResponse{}, nil
+}
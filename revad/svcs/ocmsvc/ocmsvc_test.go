@@ -0,0 +1,103 @@
+package ocmsvc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+type fakeRemoteShareManager struct {
+	api.RemoteShareManager
+	added []*api.RemoteShare
+}
+
+func (m *fakeRemoteShareManager) AddRemoteShare(ctx context.Context, share *api.RemoteShare) (*api.RemoteShare, error) {
+	m.added = append(m.added, share)
+	return share, nil
+}
+
+// fakeOCMClient verifies the shares whose token is in tokens.
+type fakeOCMClient struct {
+	api.OCMProviderClient
+	tokens map[string]bool
+	hosts  []string
+}
+
+func (c *fakeOCMClient) VerifyShare(ctx context.Context, host, token string) error {
+	c.hosts = append(c.hosts, host)
+	if !c.tokens[token] {
+		return errors.New("unauthorized")
+	}
+	return nil
+}
+
+func noAuth(ctx context.Context) (context.Context, error) {
+	return nil, errors.New("no user")
+}
+
+func withSecret(secret string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "ocm-secret "+secret))
+}
+
+func TestAuthFuncOverride(t *testing.T) {
+	s := New(nil, nil, noAuth, "s3cr3t", nil).(*svc)
+	for _, method := range []string{"/api.OCM/AddRemoteShare", "/api.OCM/RemoveRemoteShare"} {
+		if _, err := s.AuthFuncOverride(withSecret("s3cr3t"), method); err != nil {
+			t.Errorf("%s: valid secret rejected: %v", method, err)
+		}
+		if _, err := s.AuthFuncOverride(withSecret("wrong"), method); err == nil {
+			t.Errorf("%s: wrong secret accepted", method)
+		}
+		if _, err := s.AuthFuncOverride(context.Background(), method); err == nil {
+			t.Errorf("%s: call without secret accepted", method)
+		}
+	}
+	if _, err := s.AuthFuncOverride(withSecret("s3cr3t"), "/api.OCM/ListRemoteShares"); err == nil {
+		t.Error("the secret must not authenticate the calls of the users")
+	}
+
+	// without a configured secret nobody can relay the calls of remote servers
+	s = New(nil, nil, noAuth, "", nil).(*svc)
+	if _, err := s.AuthFuncOverride(withSecret(""), "/api.OCM/AddRemoteShare"); err == nil {
+		t.Error("empty secret accepted")
+	}
+}
+
+func TestAddRemoteShare(t *testing.T) {
+	rsm := &fakeRemoteShareManager{}
+	oc := &fakeOCMClient{tokens: map[string]bool{"good": true}}
+	s := New(rsm, oc, noAuth, "s3cr3t", []string{"cernbox.cern.ch"})
+	ctx := context.Background()
+
+	tests := []struct {
+		remote, token string
+		status        api.StatusCode
+	}{
+		{"evil.example.org", "good", api.StatusCode_PERMISSION_DENIED},
+		{"cernbox.cern.ch", "forged", api.StatusCode_PERMISSION_DENIED},
+		{"CERNBOX.cern.ch", "good", api.StatusCode_OK},
+	}
+	for _, tt := range tests {
+		share := &api.RemoteShare{Remote: tt.remote, RemoteId: "1", Token: tt.token, Recipient: "gonzalhu"}
+		res, err := s.AddRemoteShare(ctx, &api.NewRemoteShareReq{Share: share})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != tt.status {
+			t.Errorf("remote %s token %s: expected %s, got %s", tt.remote, tt.token, tt.status, res.Status)
+		}
+	}
+
+	if len(rsm.added) != 1 {
+		t.Errorf("expected only the verified share to be added, got %d", len(rsm.added))
+	}
+	// untrusted servers are never contacted
+	for _, h := range oc.hosts {
+		if h == "evil.example.org" {
+			t.Error("the share was verified against an untrusted server")
+		}
+	}
+}
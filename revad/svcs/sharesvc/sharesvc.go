@@ -1,6 +1,9 @@
 package sharesvc
 
 import (
+	"net/mail"
+	"strings"
+
 	"github.com/cernbox/revaold/api"
 	"golang.org/x/net/context"
 
@@ -9,13 +12,17 @@ import (
 	"go.uber.org/zap"
 )
 
-func New(lm api.PublicLinkManager, sm api.ShareManager) api.ShareServer {
-	return &svc{linkManager: lm, shareManager: sm}
+// New returns the share service. The notifier can be nil to disable the notifications,
+// and the guest manager to disable the shares with guests, that also need the notifier.
+func New(lm api.PublicLinkManager, sm api.ShareManager, n api.Notifier, gm api.GuestManager) api.ShareServer {
+	return &svc{linkManager: lm, shareManager: sm, notifier: n, guestManager: gm}
 }
 
 type svc struct {
 	linkManager  api.PublicLinkManager
 	shareManager api.ShareManager
+	notifier     api.Notifier
+	guestManager api.GuestManager
 }
 
 func (s *svc) ListReceivedShares(req *api.EmptyReq, stream api.Share_ListReceivedSharesServer) error {
@@ -66,7 +73,15 @@ func (s *svc) UnmountReceivedShare(ctx context.Context, req *api.ReceivedShareRe
 func (s *svc) ListFolderShares(req *api.ListFolderSharesReq, stream api.Share_ListFolderSharesServer) error {
 	ctx := stream.Context()
 	l := ctx_zap.Extract(ctx)
-	shares, err := s.shareManager.ListFolderShares(ctx, req.Path)
+	var shares []*api.FolderShare
+	var err error
+	if len(req.FileIds) > 0 {
+		shares, err = s.shareManager.ListFolderSharesOfFileIDs(ctx, req.FileIds)
+	} else if req.Recursive {
+		shares, err = s.shareManager.ListFolderSharesInPath(ctx, req.Path)
+	} else {
+		shares, err = s.shareManager.ListFolderShares(ctx, req.Path)
+	}
 	if err != nil {
 		l.Error("error listing folder shares", zap.Error(err))
 		return err
@@ -98,11 +113,44 @@ func (s *svc) GetFolderShare(ctx context.Context, req *api.ShareIDReq) (*api.Fol
 
 func (s *svc) AddFolderShare(ctx context.Context, req *api.NewFolderShareReq) (*api.FolderShareResponse, error) {
 	l := ctx_zap.Extract(ctx)
+
+	// sharing with an e-mail address invites a guest, who activates its
+	// account with the token sent in the invitation.
+	var activationToken string
+	if req.Recipient != nil && req.Recipient.Type == api.ShareRecipient_GUEST {
+		if s.guestManager == nil || s.notifier == nil {
+			return &api.FolderShareResponse{Status: api.StatusCode_STORAGE_NOT_SUPPORTED}, nil
+		}
+		address, err := mail.ParseAddress(req.Recipient.Identity)
+		if err != nil {
+			l.Warn("invalid guest address", zap.Error(err), zap.String("guest", req.Recipient.Identity))
+			return &api.FolderShareResponse{Status: api.StatusCode_NOTIFICATION_INVALID_RECIPIENT}, nil
+		}
+		req.Recipient = &api.ShareRecipient{Type: api.ShareRecipient_GUEST, Identity: strings.ToLower(address.Address)}
+
+		activationToken, err = s.guestManager.InviteGuest(ctx, req.Recipient.Identity)
+		if err != nil {
+			l.Error("error inviting guest", zap.Error(err), zap.String("guest", req.Recipient.Identity))
+			return nil, err
+		}
+	}
+
 	share, err := s.shareManager.AddFolderShare(ctx, req.Path, req.Recipient, req.ReadOnly)
 	if err != nil {
 		l.Error("error creating folder share", zap.Error(err))
 		return nil, err
 	}
+
+	// the share is already created, failing to notify is not an error for the owner
+	if activationToken != "" {
+		if err := s.notifier.NotifyGuestInvitation(ctx, share, req.Path, activationToken); err != nil {
+			l.Warn("error sending guest invitation", zap.Error(err), zap.String("id", share.Id))
+		}
+	} else if s.notifier != nil {
+		if err := s.notifier.NotifyFolderShare(ctx, share, req.Path); err != nil {
+			l.Warn("error notifying recipients of folder share", zap.Error(err), zap.String("id", share.Id))
+		}
+	}
 	folderShareRes := &api.FolderShareResponse{FolderShare: share}
 	return folderShareRes, nil
 }
@@ -131,7 +179,15 @@ func (s *svc) UnshareFolder(ctx context.Context, req *api.UnshareFolderReq) (*ap
 func (s *svc) ListPublicLinks(req *api.ListPublicLinksReq, stream api.Share_ListPublicLinksServer) error {
 	ctx := stream.Context()
 	l := ctx_zap.Extract(ctx)
-	links, err := s.linkManager.ListPublicLinks(ctx, req.Path)
+	var links []*api.PublicLink
+	var err error
+	if len(req.FileIds) > 0 {
+		links, err = s.linkManager.ListPublicLinksOfFileIDs(ctx, req.FileIds)
+	} else if req.Recursive {
+		links, err = s.linkManager.ListPublicLinksInPath(ctx, req.Path)
+	} else {
+		links, err = s.linkManager.ListPublicLinks(ctx, req.Path)
+	}
 	if err != nil {
 		l.Error("error listing public links", zap.Error(err))
 		return err
@@ -148,17 +204,56 @@ func (s *svc) ListPublicLinks(req *api.ListPublicLinksReq, stream api.Share_List
 func (s *svc) CreatePublicLink(ctx context.Context, req *api.NewLinkReq) (*api.PublicLinkResponse, error) {
 	l := ctx_zap.Extract(ctx)
 	opts := &api.PublicLinkOptions{
-		Password:   req.Password,
-		Expiration: req.Expires,
-		ReadOnly:   req.ReadOnly,
-		DropOnly:   req.DropOnly,
+		Token:        req.Token,
+		Name:         req.Name,
+		Labels:       req.Labels,
+		Password:     req.Password,
+		Expiration:   req.Expires,
+		ReadOnly:     req.ReadOnly,
+		DropOnly:     req.DropOnly,
+		MaxDownloads: req.MaxDownloads,
+		MaxVisitors:  req.MaxVisitors,
+		UploadPolicy: req.UploadPolicy,
+	}
+
+	if req.UploadPolicy != nil && req.UploadPolicy.Digest && s.notifier == nil {
+		return &api.PublicLinkResponse{Status: api.StatusCode_STORAGE_NOT_SUPPORTED}, nil
+	}
+
+	// the recipients are validated before creating the link, so the link
+	// is not created if it cannot be sent
+	if len(req.Notify) > 0 {
+		if s.notifier == nil {
+			return &api.PublicLinkResponse{Status: api.StatusCode_STORAGE_NOT_SUPPORTED}, nil
+		}
+		for _, recipient := range req.Notify {
+			if _, err := mail.ParseAddress(recipient); err != nil {
+				l.Warn("invalid recipient for public link notification", zap.String("recipient", recipient))
+				return &api.PublicLinkResponse{Status: api.StatusCode_NOTIFICATION_INVALID_RECIPIENT}, nil
+			}
+		}
 	}
 
 	publicLink, err := s.linkManager.CreatePublicLink(ctx, req.Path, opts)
 	if err != nil {
+		if api.IsErrorCode(err, api.PublicLinkTokenAlreadyExistsErrorCode) {
+			return &api.PublicLinkResponse{Status: api.StatusCode_PUBLIC_LINK_TOKEN_ALREADY_EXISTS}, nil
+		}
+		if api.IsErrorCode(err, api.PublicLinkInvalidTokenErrorCode) {
+			return &api.PublicLinkResponse{Status: api.StatusCode_PUBLIC_LINK_INVALID_TOKEN}, nil
+		}
+		if api.IsErrorCode(err, api.PublicLinkInvalidNameErrorCode) {
+			return &api.PublicLinkResponse{Status: api.StatusCode_PUBLIC_LINK_INVALID_NAME}, nil
+		}
 		l.Error("error creating public link", zap.Error(err))
 		return nil, err
 	}
+
+	if len(req.Notify) > 0 {
+		if err := s.notifier.NotifyPublicLink(ctx, publicLink, req.Path, req.Notify); err != nil {
+			l.Warn("error sending public link", zap.Error(err), zap.String("id", publicLink.Id))
+		}
+	}
 	publicLinkRes := &api.PublicLinkResponse{PublicLink: publicLink}
 	return publicLinkRes, nil
 }
@@ -187,6 +282,26 @@ func (s *svc) RevokePublicLink(ctx context.Context, req *api.ShareIDReq) (*api.E
 	return &api.EmptyResponse{}, nil
 }
 
+// RecordPublicLinkDownload counts a download of the link of the context, done by
+// the visitor with the client IP forwarded by the proxy.
+func (s *svc) RecordPublicLinkDownload(ctx context.Context, req *api.ShareIDReq) (*api.EmptyResponse, error) {
+	l := ctx_zap.Extract(ctx)
+	pl, ok := api.ContextGetPublicLink(ctx)
+	if !ok || pl.Id != req.Id {
+		return &api.EmptyResponse{Status: api.StatusCode_PERMISSION_DENIED}, nil
+	}
+
+	visitor, _ := api.ContextGetClientIP(ctx)
+	if err := s.linkManager.RecordPublicLinkDownload(ctx, pl.Id, visitor); err != nil {
+		if api.IsErrorCode(err, api.PublicLinkLimitReachedErrorCode) {
+			return &api.EmptyResponse{Status: api.StatusCode_PUBLIC_LINK_LIMIT_REACHED}, nil
+		}
+		l.Error("error recording public link download", zap.Error(err))
+		return nil, err
+	}
+	return &api.EmptyResponse{}, nil
+}
+
 func (s *svc) UpdatePublicLink(ctx context.Context, req *api.UpdateLinkReq) (*api.PublicLinkResponse, error) {
 	l := ctx_zap.Extract(ctx)
 	opts := &api.PublicLinkOptions{
@@ -198,10 +313,31 @@ func (s *svc) UpdatePublicLink(ctx context.Context, req *api.UpdateLinkReq) (*ap
 		UpdateExpiration: req.UpdateExpiration,
 		UpdateReadOnly:   req.UpdateReadOnly,
 		UpdateDropOnly:   req.DropOnly,
+
+		MaxDownloads:       req.MaxDownloads,
+		MaxVisitors:        req.MaxVisitors,
+		UpdateMaxDownloads: req.UpdateMaxDownloads,
+		UpdateMaxVisitors:  req.UpdateMaxVisitors,
+
+		UploadPolicy:       req.UploadPolicy,
+		UpdateUploadPolicy: req.UpdateUploadPolicy,
+
+		Name:       req.Name,
+		UpdateName: req.UpdateName,
+
+		Labels:       req.Labels,
+		UpdateLabels: req.UpdateLabels,
+	}
+
+	if req.UpdateUploadPolicy && req.UploadPolicy != nil && req.UploadPolicy.Digest && s.notifier == nil {
+		return &api.PublicLinkResponse{Status: api.StatusCode_STORAGE_NOT_SUPPORTED}, nil
 	}
 
 	publicLink, err := s.linkManager.UpdatePublicLink(ctx, req.Id, opts)
 	if err != nil {
+		if api.IsErrorCode(err, api.PublicLinkInvalidNameErrorCode) {
+			return &api.PublicLinkResponse{Status: api.StatusCode_PUBLIC_LINK_INVALID_NAME}, nil
+		}
 		l.Error("error updating public link", zap.Error(err))
 		return nil, err
 	}
@@ -0,0 +1,144 @@
+package sharesvc
+
+import (
+	"testing"
+
+	"github.com/cernbox/revaold/api"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags/zap"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+)
+
+// fakeLinkManager allows maxDownloads downloads per link.
+type fakeLinkManager struct {
+	api.PublicLinkManager
+	maxDownloads int
+	downloads    map[string][]string
+}
+
+func (lm *fakeLinkManager) RecordPublicLinkDownload(ctx context.Context, id, visitor string) error {
+	if len(lm.downloads[id]) >= lm.maxDownloads {
+		return api.NewError(api.PublicLinkLimitReachedErrorCode)
+	}
+	lm.downloads[id] = append(lm.downloads[id], visitor)
+	return nil
+}
+
+func TestRecordPublicLinkDownload(t *testing.T) {
+	lm := &fakeLinkManager{maxDownloads: 1, downloads: map[string][]string{}}
+	s := New(lm, nil, nil, nil)
+
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+	plCtx := api.ContextSetClientIP(api.ContextSetPublicLink(ctx, &api.PublicLink{Id: "7", Token: "abcdefghij"}), "192.0.2.1")
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		id     string
+		status api.StatusCode
+	}{
+		{"without link", ctx, "7", api.StatusCode_PERMISSION_DENIED},
+		{"other link", plCtx, "8", api.StatusCode_PERMISSION_DENIED},
+		{"download", plCtx, "7", api.StatusCode_OK},
+		{"limit reached", plCtx, "7", api.StatusCode_PUBLIC_LINK_LIMIT_REACHED},
+	}
+	for _, tt := range tests {
+		res, err := s.RecordPublicLinkDownload(tt.ctx, &api.ShareIDReq{Id: tt.id})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if res.Status != tt.status {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.status, res.Status)
+		}
+	}
+
+	if visitors := lm.downloads["7"]; len(visitors) != 1 || visitors[0] != "192.0.2.1" {
+		t.Errorf("expected one download by the client IP, got %v", visitors)
+	}
+	if len(lm.downloads["8"]) != 0 {
+		t.Error("download recorded on a link other than the one of the token")
+	}
+}
+
+type fakeShareManager struct {
+	api.ShareManager
+	recipients []*api.ShareRecipient
+}
+
+func (sm *fakeShareManager) AddFolderShare(ctx context.Context, p string, recipient *api.ShareRecipient, readOnly bool) (*api.FolderShare, error) {
+	sm.recipients = append(sm.recipients, recipient)
+	return &api.FolderShare{Id: "1", Path: p, Recipient: recipient, ReadOnly: readOnly}, nil
+}
+
+// fakeGuestManager returns an activation token only the first time a guest is invited.
+type fakeGuestManager struct {
+	api.GuestManager
+	invited map[string]bool
+}
+
+func (gm *fakeGuestManager) InviteGuest(ctx context.Context, email string) (string, error) {
+	if gm.invited[email] {
+		return "", nil
+	}
+	gm.invited[email] = true
+	return "activation-" + email, nil
+}
+
+type fakeNotifier struct {
+	api.Notifier
+	invitations []string
+	shares      int
+}
+
+func (n *fakeNotifier) NotifyGuestInvitation(ctx context.Context, share *api.FolderShare, path, activationToken string) error {
+	n.invitations = append(n.invitations, activationToken)
+	return nil
+}
+
+func (n *fakeNotifier) NotifyFolderShare(ctx context.Context, share *api.FolderShare, path string) error {
+	n.shares++
+	return nil
+}
+
+func TestShareWithGuest(t *testing.T) {
+	sm := &fakeShareManager{}
+	n := &fakeNotifier{}
+	s := New(nil, sm, n, &fakeGuestManager{invited: map[string]bool{}})
+	ctx := ctx_zap.ToContext(context.Background(), zap.NewNop())
+
+	share := func(identity string) api.StatusCode {
+		req := &api.NewFolderShareReq{Path: "/home/project", Recipient: &api.ShareRecipient{Type: api.ShareRecipient_GUEST, Identity: identity}, ReadOnly: true}
+		res, err := s.AddFolderShare(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Status
+	}
+
+	if status := share("Marie Curie <Marie@Example.org>"); status != api.StatusCode_OK {
+		t.Fatalf("expected the share with the guest, got %s", status)
+	}
+	if sm.recipients[0].Identity != "marie@example.org" {
+		t.Errorf("expected the normalized address of the guest, got %q", sm.recipients[0].Identity)
+	}
+	if len(n.invitations) != 1 || n.invitations[0] != "activation-marie@example.org" {
+		t.Errorf("expected the invitation with the activation token, got %v", n.invitations)
+	}
+
+	// the guest is already invited, it only gets the notification of the share
+	if status := share("marie@example.org"); status != api.StatusCode_OK {
+		t.Fatalf("expected the second share with the guest, got %s", status)
+	}
+	if len(n.invitations) != 1 || n.shares != 1 {
+		t.Errorf("expected a share notification without invitation, got %v and %d", n.invitations, n.shares)
+	}
+
+	if status := share("not an address"); status != api.StatusCode_NOTIFICATION_INVALID_RECIPIENT {
+		t.Errorf("expected the invalid address to be rejected, got %s", status)
+	}
+
+	s = New(nil, sm, n, nil)
+	if status := share("marie@example.org"); status != api.StatusCode_STORAGE_NOT_SUPPORTED {
+		t.Errorf("expected the guests to be disabled, got %s", status)
+	}
+}
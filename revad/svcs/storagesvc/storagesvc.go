@@ -435,6 +435,11 @@ func (s *svc) FinishWriteTx(ctx context.Context, req *api.TxEnd) (*api.EmptyResp
 	}
 
 	if err := s.vs.Upload(ctx, req.Path, fd); err != nil {
+		// the uploads rejected by the policy of a drop only link are reported to the uploader
+		if api.IsErrorCode(err, api.PublicLinkUploadTooLargeErrorCode) || api.IsErrorCode(err, api.PublicLinkUploadTypeNotAllowedErrorCode) || api.IsErrorCode(err, api.PublicLinkUploaderNameRequiredErrorCode) {
+			l.Warn("upload rejected", zap.Error(err), zap.String("path", req.Path))
+			return &api.EmptyResponse{Status: api.GetStatus(err)}, nil
+		}
 		return nil, err
 	}
 
@@ -444,6 +449,9 @@ func (s *svc) FinishWriteTx(ctx context.Context, req *api.TxEnd) (*api.EmptyResp
 func (s *svc) Move(ctx context.Context, req *api.MoveReq) (*api.EmptyResponse, error) {
 	l := ctx_zap.Extract(ctx)
 	if err := s.vs.Move(ctx, req.OldPath, req.NewPath); err != nil {
+		if api.IsErrorCode(err, api.FolderShareInvalidTargetErrorCode) {
+			return &api.EmptyResponse{Status: api.StatusCode_FOLDER_SHARE_INVALID_TARGET}, nil
+		}
 		l.Error("", zap.Error(err))
 		return nil, err
 	}